@@ -0,0 +1,41 @@
+// migrate-blobs moves blobs that are stored in the database to the configured blob store.
+//
+// It accepts the same flags as the api.
+package main
+
+import (
+	"context"
+	"log"
+
+	"go.uber.org/zap"
+
+	service_blobs "getsturdy.com/api/pkg/blobs/service"
+	"getsturdy.com/api/pkg/db/migrate"
+	"getsturdy.com/api/pkg/di"
+)
+
+func main() {
+	var migrateService *migrate.Service
+	if err := di.Init(migrate.Module).To(&migrateService); err != nil {
+		log.Fatalf("failed to init: %+v", err)
+	}
+
+	if err := migrateService.Migrate(context.Background()); err != nil {
+		log.Fatalf("failed to migrate up: %+v", err)
+	}
+
+	var (
+		blobsService *service_blobs.Service
+		logger       *zap.Logger
+	)
+	if err := di.Init(service_blobs.Module).To(&blobsService, &logger); err != nil {
+		log.Fatalf("failed to init: %+v", err)
+	}
+
+	migrated, err := blobsService.MigrateFromDatabase(context.Background())
+	if err != nil {
+		logger.Fatal("failed to migrate blobs", zap.Int("migrated", migrated), zap.Error(err))
+	}
+
+	logger.Info("migrated blobs", zap.Int("migrated", migrated))
+}
@@ -5,12 +5,17 @@ import (
 	"fmt"
 
 	workers_ci "getsturdy.com/api/pkg/ci/workers"
+	worker_codeowners "getsturdy.com/api/pkg/codeowners/worker"
 	worker_gc "getsturdy.com/api/pkg/gc/worker"
 	"getsturdy.com/api/pkg/gitserver"
 	httpx "getsturdy.com/api/pkg/http"
+	worker_mergequeue "getsturdy.com/api/pkg/mergequeue/worker"
 	"getsturdy.com/api/pkg/metrics"
 	"getsturdy.com/api/pkg/pprof"
+	server_shards "getsturdy.com/api/pkg/shards/server"
 	worker_snapshots "getsturdy.com/api/pkg/snapshots/worker"
+	worker_sync "getsturdy.com/api/pkg/sync/worker"
+	worker_webhooks "getsturdy.com/api/pkg/webhooks/worker"
 
 	"golang.org/x/sync/errgroup"
 )
@@ -24,9 +29,14 @@ type API struct {
 	snapshotterQueue worker_snapshots.Queue
 	ciBuildQueue     *workers_ci.BuildQueue
 	gcQueue          *worker_gc.Queue
+	mergeQueue       *worker_mergequeue.Queue
+	codeOwnersQueue  *worker_codeowners.Queue
+	webhooksQueue    *worker_webhooks.Queue
+	syncQueue        *worker_sync.Queue
 	gitsrv           *gitserver.Server
 	pprof            *pprof.Server
 	metrics          *metrics.Server
+	shardsServer     *server_shards.Server
 }
 
 func ProvideAPI(
@@ -34,18 +44,28 @@ func ProvideAPI(
 	snapshotterQueue worker_snapshots.Queue,
 	ciBuildQueue *workers_ci.BuildQueue,
 	gcQueue *worker_gc.Queue,
+	mergeQueue *worker_mergequeue.Queue,
+	codeOwnersQueue *worker_codeowners.Queue,
+	webhooksQueue *worker_webhooks.Queue,
+	syncQueue *worker_sync.Queue,
 	gitsrv *gitserver.Server,
 	pprof *pprof.Server,
 	metrics *metrics.Server,
+	shardsServer *server_shards.Server,
 ) *API {
 	return &API{
 		httpServer:       httpServer,
 		snapshotterQueue: snapshotterQueue,
 		ciBuildQueue:     ciBuildQueue,
 		gcQueue:          gcQueue,
+		mergeQueue:       mergeQueue,
+		codeOwnersQueue:  codeOwnersQueue,
+		webhooksQueue:    webhooksQueue,
+		syncQueue:        syncQueue,
 		gitsrv:           gitsrv,
 		pprof:            pprof,
 		metrics:          metrics,
+		shardsServer:     shardsServer,
 	}
 }
 
@@ -72,6 +92,34 @@ func (a *API) Start(ctx context.Context) error {
 		}
 		return nil
 	})
+	// merge queue
+	wg.Go(func() error {
+		if err := a.mergeQueue.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start merge queue: %w", err)
+		}
+		return nil
+	})
+	// code owners queue
+	wg.Go(func() error {
+		if err := a.codeOwnersQueue.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start code owners queue: %w", err)
+		}
+		return nil
+	})
+	// webhooks queue
+	wg.Go(func() error {
+		if err := a.webhooksQueue.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start webhooks queue: %w", err)
+		}
+		return nil
+	})
+	// sync stacked workspaces queue
+	wg.Go(func() error {
+		if err := a.syncQueue.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start sync queue: %w", err)
+		}
+		return nil
+	})
 	// Start the git HTTP server
 	wg.Go(func() error {
 		if err := a.gitsrv.Start(); err != nil {
@@ -93,6 +141,13 @@ func (a *API) Start(ctx context.Context) error {
 		}
 		return nil
 	})
+	// Shards server, for requests from other nodes
+	wg.Go(func() error {
+		if err := a.shardsServer.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start shards server: %w", err)
+		}
+		return nil
+	})
 	wg.Go(func() error {
 		if err := a.httpServer.Start(); err != nil {
 			return fmt.Errorf("failed to start server: %w", err)
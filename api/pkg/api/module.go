@@ -2,13 +2,18 @@ package api
 
 import (
 	workers_ci "getsturdy.com/api/pkg/ci/workers"
+	worker_codeowners "getsturdy.com/api/pkg/codeowners/worker"
 	"getsturdy.com/api/pkg/di"
 	worker_gc "getsturdy.com/api/pkg/gc/worker"
 	"getsturdy.com/api/pkg/gitserver"
 	"getsturdy.com/api/pkg/http"
+	worker_mergequeue "getsturdy.com/api/pkg/mergequeue/worker"
 	"getsturdy.com/api/pkg/metrics"
 	"getsturdy.com/api/pkg/pprof"
+	server_shards "getsturdy.com/api/pkg/shards/server"
 	worker_snapshots "getsturdy.com/api/pkg/snapshots/worker"
+	worker_sync "getsturdy.com/api/pkg/sync/worker"
+	worker_webhooks "getsturdy.com/api/pkg/webhooks/worker"
 )
 
 func Module(c *di.Container) {
@@ -16,8 +21,13 @@ func Module(c *di.Container) {
 	c.Import(worker_snapshots.Module)
 	c.Import(workers_ci.Module)
 	c.Import(worker_gc.Module)
+	c.Import(worker_mergequeue.Module)
+	c.Import(worker_codeowners.Module)
+	c.Import(worker_webhooks.Module)
+	c.Import(worker_sync.Module)
 	c.Import(gitserver.Module)
 	c.Import(pprof.Module)
 	c.Import(metrics.Module)
+	c.Import(server_shards.Module)
 	c.Register(ProvideAPI)
 }
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"getsturdy.com/api/pkg/auth"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/codebases/acl"
+	"getsturdy.com/api/pkg/users"
+)
+
+// CanPerform checks if the user has write access to the codebase, and that the codebase's ACL policy allows the user
+// to perform _action_ on _resource_.
+func (s *Service) CanPerform(ctx context.Context, codebaseID codebases.ID, action acl.Action, resource acl.Identity) error {
+	subject, found := auth.FromContext(ctx)
+	if !found {
+		return fmt.Errorf("subject is not found in the context: %w", auth.ErrUnauthenticated)
+	}
+
+	if subject.Type != auth.SubjectUser {
+		return fmt.Errorf("unsupported subject type '%s': %w", subject.Type, auth.ErrForbidden)
+	}
+
+	if err := s.CanWrite(ctx, &codebases.Codebase{ID: codebaseID}); err != nil {
+		return err
+	}
+
+	return s.canUserPerform(ctx, users.ID(subject.ID), codebaseID, action, resource)
+}
+
+func (s *Service) canUserPerform(ctx context.Context, userID users.ID, codebaseID codebases.ID, action acl.Action, resource acl.Identity) error {
+	aclPolicy, err := s.aclProvider.GetByCodebaseID(ctx, codebaseID)
+	if err != nil {
+		return fmt.Errorf("failed to get acl policy: %w", err)
+	}
+
+	user, err := s.userService.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if aclPolicy.Policy.AllowsAny([]acl.Identity{
+		{Type: acl.Users, ID: userID.String()},
+		{Type: acl.Users, ID: user.Email},
+	}, action, resource) {
+		return nil
+	}
+
+	return fmt.Errorf("user is not allowed to %s: %w", action, auth.ErrForbidden)
+}
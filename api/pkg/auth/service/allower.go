@@ -21,7 +21,47 @@ var (
 	noneAllowed, _ = unidiff.NewAllower()
 )
 
+// GetAllower returns an allower for the files in obj that the subject in ctx can read. It is used to hide the files
+// that the subject is not allowed to see.
 func (s *Service) GetAllower(ctx context.Context, obj any) (*unidiff.Allower, error) {
+	return s.getAllower(ctx, obj, acl.ActionRead)
+}
+
+// GetWriteAllower returns an allower for the files in obj that the subject in ctx can write to. It is used for
+// everything that changes files, like syncing files from the subject's computer, and landing changes.
+func (s *Service) GetWriteAllower(ctx context.Context, obj any) (*unidiff.Allower, error) {
+	return s.getAllower(ctx, obj, acl.ActionWrite)
+}
+
+// CanWriteFiles returns an error if the subject in ctx is not allowed to write to all of paths in obj.
+func (s *Service) CanWriteFiles(ctx context.Context, obj any, paths ...string) error {
+	allower, err := s.GetWriteAllower(ctx, obj)
+	if err != nil {
+		return fmt.Errorf("failed to get allower: %w", err)
+	}
+	for _, path := range paths {
+		if !allower.IsAllowed(path, false) {
+			return fmt.Errorf("not allowed to write to %s: %w", path, auth.ErrForbidden)
+		}
+	}
+	return nil
+}
+
+// CanWriteChanges returns an error if the workspace has changes to files that the subject in ctx is not allowed to write
+// to.
+func (s *Service) CanWriteChanges(ctx context.Context, ws *workspaces.Workspace) error {
+	diffs, _, err := s.workspaceService.Diffs(ctx, ws.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get diffs: %w", err)
+	}
+	var paths []string
+	for _, diff := range diffs {
+		paths = append(paths, diff.Paths()...)
+	}
+	return s.CanWriteFiles(ctx, ws, paths...)
+}
+
+func (s *Service) getAllower(ctx context.Context, obj any, action acl.Action) (*unidiff.Allower, error) {
 	if obj == nil {
 		return noneAllowed, nil
 	}
@@ -37,30 +77,30 @@ func (s *Service) GetAllower(ctx context.Context, obj any) (*unidiff.Allower, er
 		// TODO: mutagen request should be authenticated
 		switch object := obj.(type) {
 		case *codebases.Codebase:
-			return s.getUserCodebaseAllower(ctx, subjectID, object)
+			return s.getUserCodebaseAllower(ctx, subjectID, object, action)
 		case codebases.Codebase:
-			return s.getUserCodebaseAllower(ctx, subjectID, &object)
+			return s.getUserCodebaseAllower(ctx, subjectID, &object, action)
 		}
 
 	case auth.SubjectUser:
 		subjectID := users.ID(subject.ID)
 		switch object := obj.(type) {
 		case *codebases.Codebase:
-			return s.getUserCodebaseAllower(ctx, subjectID, object)
+			return s.getUserCodebaseAllower(ctx, subjectID, object, action)
 		case codebases.Codebase:
-			return s.getUserCodebaseAllower(ctx, subjectID, &object)
+			return s.getUserCodebaseAllower(ctx, subjectID, &object, action)
 		case changes.Change:
-			return s.getUserChangeAllower(ctx, subjectID, &object)
+			return s.getUserChangeAllower(ctx, subjectID, &object, action)
 		case *changes.Change:
-			return s.getUserChangeAllower(ctx, subjectID, object)
+			return s.getUserChangeAllower(ctx, subjectID, object, action)
 		case workspaces.Workspace:
-			return s.getUserWorkspaceAllower(ctx, subjectID, &object)
+			return s.getUserWorkspaceAllower(ctx, subjectID, &object, action)
 		case *workspaces.Workspace:
-			return s.getUserWorkspaceAllower(ctx, subjectID, object)
+			return s.getUserWorkspaceAllower(ctx, subjectID, object, action)
 		case suggestions.Suggestion:
-			return s.getUserSuggestionAllower(ctx, subjectID, &object)
+			return s.getUserSuggestionAllower(ctx, subjectID, &object, action)
 		case *suggestions.Suggestion:
-			return s.getUserSuggestionAllower(ctx, subjectID, object)
+			return s.getUserSuggestionAllower(ctx, subjectID, object, action)
 		}
 
 	case auth.SubjectCI:
@@ -78,48 +118,48 @@ func (s *Service) GetAllower(ctx context.Context, obj any) (*unidiff.Allower, er
 	case auth.SubjectAnonymous:
 		switch object := obj.(type) {
 		case *changes.Change:
-			return s.getAnonymousChangeAllower(ctx, object)
+			return s.getAnonymousChangeAllower(ctx, object, action)
 		case changes.Change:
-			return s.getAnonymousChangeAllower(ctx, &object)
+			return s.getAnonymousChangeAllower(ctx, &object, action)
 		case workspaces.Workspace:
-			return s.getAnonymousWorkspaceAllower(ctx, &object)
+			return s.getAnonymousWorkspaceAllower(ctx, &object, action)
 		case *workspaces.Workspace:
-			return s.getAnonymousWorkspaceAllower(ctx, object)
+			return s.getAnonymousWorkspaceAllower(ctx, object, action)
 		case *codebases.Codebase:
-			return s.getAnonymousCodebaseAllower(ctx, object)
+			return s.getAnonymousCodebaseAllower(ctx, object, action)
 		case codebases.Codebase:
-			return s.getAnonymousCodebaseAllower(ctx, &object)
+			return s.getAnonymousCodebaseAllower(ctx, &object, action)
 		}
 	}
 
 	return noneAllowed, nil
 }
 
-func (s *Service) getUserChangeAllower(ctx context.Context, userID users.ID, change *changes.Change) (*unidiff.Allower, error) {
+func (s *Service) getUserChangeAllower(ctx context.Context, userID users.ID, change *changes.Change, action acl.Action) (*unidiff.Allower, error) {
 	cb, err := s.codebaseService.GetByID(ctx, change.CodebaseID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get codebase: %w", err)
 	}
-	return s.getUserCodebaseAllower(ctx, userID, cb)
+	return s.getUserCodebaseAllower(ctx, userID, cb, action)
 }
 
-func (s *Service) getUserWorkspaceAllower(ctx context.Context, userID users.ID, workspace *workspaces.Workspace) (*unidiff.Allower, error) {
+func (s *Service) getUserWorkspaceAllower(ctx context.Context, userID users.ID, workspace *workspaces.Workspace, action acl.Action) (*unidiff.Allower, error) {
 	cb, err := s.codebaseService.GetByID(ctx, workspace.CodebaseID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get codebase: %w", err)
 	}
-	return s.getUserCodebaseAllower(ctx, userID, cb)
+	return s.getUserCodebaseAllower(ctx, userID, cb, action)
 }
 
-func (s *Service) getUserSuggestionAllower(ctx context.Context, userID users.ID, suggestion *suggestions.Suggestion) (*unidiff.Allower, error) {
+func (s *Service) getUserSuggestionAllower(ctx context.Context, userID users.ID, suggestion *suggestions.Suggestion, action acl.Action) (*unidiff.Allower, error) {
 	cb, err := s.codebaseService.GetByID(ctx, suggestion.CodebaseID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get codebase: %w", err)
 	}
-	return s.getUserCodebaseAllower(ctx, userID, cb)
+	return s.getUserCodebaseAllower(ctx, userID, cb, action)
 }
 
-func (s *Service) getUserCodebaseAllower(ctx context.Context, userID users.ID, codebase *codebases.Codebase) (*unidiff.Allower, error) {
+func (s *Service) getUserCodebaseAllower(ctx context.Context, userID users.ID, codebase *codebases.Codebase, action acl.Action) (*unidiff.Allower, error) {
 	aclPolicy, err := s.aclProvider.GetByCodebaseID(ctx, codebase.ID)
 	if errors.Is(err, sql.ErrNoRows) {
 		return noneAllowed, nil
@@ -134,19 +174,16 @@ func (s *Service) getUserCodebaseAllower(ctx context.Context, userID users.ID, c
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	allowedByEmail := aclPolicy.Policy.List(
-		acl.Identity{Type: acl.Users, ID: user.Email},
-		acl.ActionWrite,
+	allowed := aclPolicy.Policy.ListAny(
+		[]acl.Identity{
+			{Type: acl.Users, ID: user.Email},
+			{Type: acl.Users, ID: user.ID.String()},
+		},
+		action,
 		acl.Files,
 	)
 
-	allowedByID := aclPolicy.Policy.List(
-		acl.Identity{Type: acl.Users, ID: user.ID.String()},
-		acl.ActionWrite,
-		acl.Files,
-	)
-
-	return unidiff.NewAllower(append(allowedByEmail, allowedByID...)...)
+	return unidiff.NewAllower(allowed...)
 }
 
 func (s *Service) getCIWorkspaceAllower(ctx context.Context, workspaceID string, workspace *workspaces.Workspace) (*unidiff.Allower, error) {
@@ -163,27 +200,32 @@ func (s *Service) getCIChangeAllower(ctx context.Context, changeID string, chang
 	return allAllowed, nil
 }
 
-func (s *Service) getAnonymousWorkspaceAllower(ctx context.Context, workspace *workspaces.Workspace) (*unidiff.Allower, error) {
+func (s *Service) getAnonymousWorkspaceAllower(ctx context.Context, workspace *workspaces.Workspace, action acl.Action) (*unidiff.Allower, error) {
 	cb, err := s.codebaseService.GetByID(ctx, workspace.CodebaseID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get codebase: %w", err)
 	}
-	return s.getAnonymousCodebaseAllower(ctx, cb)
+	return s.getAnonymousCodebaseAllower(ctx, cb, action)
 }
-func (s *Service) getAnonymousChangeAllower(ctx context.Context, change *changes.Change) (*unidiff.Allower, error) {
+func (s *Service) getAnonymousChangeAllower(ctx context.Context, change *changes.Change, action acl.Action) (*unidiff.Allower, error) {
 	cb, err := s.codebaseService.GetByID(ctx, change.CodebaseID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get codebase: %w", err)
 	}
-	return s.getAnonymousCodebaseAllower(ctx, cb)
+	return s.getAnonymousCodebaseAllower(ctx, cb, action)
 }
 
-func (s *Service) getAnonymousCodebaseAllower(ctx context.Context, cb *codebases.Codebase) (*unidiff.Allower, error) {
+func (s *Service) getAnonymousCodebaseAllower(ctx context.Context, cb *codebases.Codebase, action acl.Action) (*unidiff.Allower, error) {
 	if !cb.IsPublic {
 		// if codebase is not public, then anonymous users can't see any files.
 		return noneAllowed, nil
 	}
 
+	if action != acl.ActionRead {
+		// anonymous users can only read
+		return noneAllowed, nil
+	}
+
 	// for public codebases, use acls to determine what files are visible.
 
 	aclPolicy, err := s.aclProvider.GetByCodebaseID(ctx, cb.ID)
@@ -195,7 +237,7 @@ func (s *Service) getAnonymousCodebaseAllower(ctx context.Context, cb *codebases
 
 	allowedByID := aclPolicy.Policy.List(
 		acl.Identity{Type: acl.Users, ID: "anonymous"},
-		acl.ActionWrite,
+		action,
 		acl.Files,
 	)
 
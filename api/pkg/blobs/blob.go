@@ -1,8 +1,28 @@
 package blobs
 
+import (
+	"context"
+	"errors"
+	"io"
+)
+
 type ID string
 
+// Blob is a blob that is stored in the blobs table. Blobs are no longer written to the database, the table only holds
+// blobs that have not yet been migrated to a BlobStore.
 type Blob struct {
 	ID   ID     `db:"id"`
 	Data []byte `db:"data"`
 }
+
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore stores blobs without keeping them in memory.
+type BlobStore interface {
+	// Put stores the content of reader as the blob with the given id, overwriting any existing blob.
+	Put(ctx context.Context, id ID, reader io.Reader) error
+	// Get returns a reader for the blob with the given id, or ErrNotFound. The caller must close the reader.
+	Get(ctx context.Context, id ID) (io.ReadCloser, error)
+	// Delete deletes the blob with the given id. Deleting a blob that does not exist is not an error.
+	Delete(ctx context.Context, id ID) error
+}
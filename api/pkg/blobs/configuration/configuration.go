@@ -0,0 +1,16 @@
+package configuration
+
+type Configuration struct {
+	Type string           `long:"type" description:"Where blobs are stored" default:"fs" choice:"fs" choice:"s3"`
+	Path string           `long:"path" description:"Path to the directory containing the blobs, if type is fs" default:"tmp/blobs"`
+	S3   *S3Configuration `flags-group:"s3" namespace:"s3"`
+}
+
+type S3Configuration struct {
+	Endpoint        string `long:"endpoint" description:"S3 compatible endpoint, uses AWS if empty"`
+	Region          string `long:"region" description:"S3 region" default:"us-east-1"`
+	Bucket          string `long:"bucket" description:"S3 bucket"`
+	AccessKeyID     string `long:"access-key-id" description:"S3 access key id, uses the default credentials chain if empty"`
+	SecretAccessKey string `long:"secret-access-key" description:"S3 secret access key"`
+	PathStyle       bool   `long:"path-style" description:"Use path style addressing, required by most S3 compatible services"`
+}
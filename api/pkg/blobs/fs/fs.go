@@ -0,0 +1,89 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"getsturdy.com/api/pkg/blobs"
+)
+
+var _ blobs.BlobStore = &Store{}
+
+// Store is a blobs.BlobStore that stores blobs as files on the local filesystem.
+type Store struct {
+	root string
+}
+
+func New(root string) *Store {
+	return &Store{root: root}
+}
+
+var errInvalidID = errors.New("invalid blob id")
+
+func (s *Store) path(id blobs.ID) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(string(id)))
+	if id == "" || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %q", errInvalidID, id)
+	}
+	return filepath.Join(s.root, clean), nil
+}
+
+func (s *Store) Put(_ context.Context, id blobs.ID, reader io.Reader) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// write to a temporary file first, so that readers never see a partially written blob
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".blob-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, reader); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to rename file: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) Get(_ context.Context, id blobs.ID) (io.ReadCloser, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return nil, err
+	}
+	fp, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, blobs.ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return fp, nil
+}
+
+func (s *Store) Delete(_ context.Context, id blobs.ID) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package fs_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"getsturdy.com/api/pkg/blobs"
+	"getsturdy.com/api/pkg/blobs/fs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	store := fs.New(t.TempDir())
+
+	_, err := store.Get(ctx, "avatar.png")
+	assert.ErrorIs(t, err, blobs.ErrNotFound)
+
+	assert.NoError(t, store.Put(ctx, "avatar.png", strings.NewReader("first")))
+	assert.NoError(t, store.Put(ctx, "avatar.png", strings.NewReader("second")))
+
+	reader, err := store.Get(ctx, "avatar.png")
+	assert.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.NoError(t, reader.Close())
+	assert.Equal(t, "second", string(data))
+
+	assert.NoError(t, store.Delete(ctx, "avatar.png"))
+	assert.NoError(t, store.Delete(ctx, "avatar.png"))
+
+	_, err = store.Get(ctx, "avatar.png")
+	assert.ErrorIs(t, err, blobs.ErrNotFound)
+}
+
+func TestStore_invalidID(t *testing.T) {
+	ctx := context.Background()
+	store := fs.New(t.TempDir())
+
+	for _, id := range []blobs.ID{"", "../avatar.png", "/etc/passwd"} {
+		assert.Error(t, store.Put(ctx, id, strings.NewReader("data")), id)
+		_, err := store.Get(ctx, id)
+		assert.Error(t, err, id)
+	}
+}
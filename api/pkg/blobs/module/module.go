@@ -0,0 +1,31 @@
+package module
+
+import (
+	"fmt"
+
+	"getsturdy.com/api/pkg/blobs"
+	"getsturdy.com/api/pkg/blobs/configuration"
+	"getsturdy.com/api/pkg/blobs/fs"
+	"getsturdy.com/api/pkg/blobs/s3"
+	module_configuration "getsturdy.com/api/pkg/configuration/module"
+	"getsturdy.com/api/pkg/di"
+)
+
+func Module(c *di.Container) {
+	c.Import(module_configuration.Module)
+	c.Register(New)
+}
+
+func New(cfg *configuration.Configuration) (blobs.BlobStore, error) {
+	switch cfg.Type {
+	case "fs":
+		return fs.New(cfg.Path), nil
+	case "s3":
+		if cfg.S3 == nil {
+			return nil, fmt.Errorf("s3 is not configured")
+		}
+		return s3.New(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown blob store type: %q", cfg.Type)
+	}
+}
@@ -1,7 +1,9 @@
 package routes
 
 import (
+	"bufio"
 	"errors"
+	"io"
 	"net/http"
 	"path"
 
@@ -27,11 +29,21 @@ func Get(logger *zap.Logger, blobService *service_blob.Service) http.HandlerFunc
 		} else if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			return
-		} else {
-			w.Header().Set("Content-Type", http.DetectContentType(blob.Data))
-			if _, err := w.Write(blob.Data); err != nil {
-				logger.Error("failed to write blob", zap.Error(err))
-			}
+		}
+		defer blob.Close()
+
+		// 512 bytes is all that is needed to detect the content type
+		reader := bufio.NewReaderSize(blob, 512)
+		head, err := reader.Peek(512)
+		if err != nil && !errors.Is(err, io.EOF) {
+			logger.Error("failed to read blob", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", http.DetectContentType(head))
+		if _, err := io.Copy(w, reader); err != nil {
+			logger.Error("failed to write blob", zap.Error(err))
 		}
 	}
 }
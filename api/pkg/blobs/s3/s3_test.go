@@ -0,0 +1,88 @@
+package s3_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"getsturdy.com/api/pkg/blobs"
+	"getsturdy.com/api/pkg/blobs/configuration"
+	"getsturdy.com/api/pkg/blobs/s3"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newObjectStorage starts a minimal S3 compatible server that supports path style PUT, GET and DELETE of objects.
+func newObjectStorage(t *testing.T) *httptest.Server {
+	var (
+		mu      sync.Mutex
+		objects = map[string][]byte{}
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case http.MethodPut:
+			data, err := io.ReadAll(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			objects[r.URL.Path] = data
+		case http.MethodGet:
+			data, ok := objects[r.URL.Path]
+			if !ok {
+				w.Header().Set("Content-Type", "application/xml")
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`))
+				return
+			}
+			_, _ = w.Write(data)
+		case http.MethodDelete:
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	srv := newObjectStorage(t)
+
+	store, err := s3.New(&configuration.S3Configuration{
+		Endpoint:        srv.URL,
+		Region:          "us-east-1",
+		Bucket:          "blobs",
+		AccessKeyID:     "access",
+		SecretAccessKey: "secret",
+		PathStyle:       true,
+	})
+	assert.NoError(t, err)
+
+	_, err = store.Get(ctx, "avatar.png")
+	assert.ErrorIs(t, err, blobs.ErrNotFound)
+
+	assert.NoError(t, store.Put(ctx, "avatar.png", strings.NewReader("avatar")))
+
+	reader, err := store.Get(ctx, "avatar.png")
+	assert.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.NoError(t, reader.Close())
+	assert.Equal(t, "avatar", string(data))
+
+	assert.NoError(t, store.Delete(ctx, "avatar.png"))
+
+	_, err = store.Get(ctx, "avatar.png")
+	assert.ErrorIs(t, err, blobs.ErrNotFound)
+}
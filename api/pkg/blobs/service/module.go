@@ -1,11 +1,15 @@
 package service
 
 import (
+	module_blobs "getsturdy.com/api/pkg/blobs/module"
 	"getsturdy.com/api/pkg/db"
 	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/logger"
 )
 
 func Module(c *di.Container) {
 	c.Import(db.Module)
+	c.Import(logger.Module)
+	c.Import(module_blobs.Module)
 	c.Register(New)
 }
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"errors"
@@ -10,43 +11,95 @@ import (
 	"getsturdy.com/api/pkg/blobs"
 
 	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
 )
 
 type Service struct {
-	db *sqlx.DB
+	logger *zap.Logger
+	db     *sqlx.DB
+	store  blobs.BlobStore
 }
 
-func New(db *sqlx.DB) *Service {
-	return &Service{db: db}
+func New(logger *zap.Logger, db *sqlx.DB, store blobs.BlobStore) *Service {
+	return &Service{
+		logger: logger.Named("blobsService"),
+		db:     db,
+		store:  store,
+	}
 }
 
-var ErrNotFound = fmt.Errorf("not found: %w", sql.ErrNoRows)
+var ErrNotFound = blobs.ErrNotFound
+
+// Fetch returns a reader for the blob. Blobs that are not yet migrated from the database are read from there.
+// The caller must close the reader.
+func (s *Service) Fetch(ctx context.Context, id blobs.ID) (io.ReadCloser, error) {
+	reader, err := s.store.Get(ctx, id)
+	switch {
+	case err == nil:
+		return reader, nil
+	case errors.Is(err, blobs.ErrNotFound):
+		return s.fetchFromDatabase(ctx, id)
+	default:
+		return nil, fmt.Errorf("failed to fetch blob: %w", err)
+	}
+}
 
-func (s *Service) Fetch(ctx context.Context, id blobs.ID) (*blobs.Blob, error) {
+func (s *Service) fetchFromDatabase(ctx context.Context, id blobs.ID) (io.ReadCloser, error) {
 	var blob blobs.Blob
 	if err := s.db.GetContext(ctx, &blob, "SELECT * FROM blobs WHERE id = $1", id); errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrNotFound
 	} else if err != nil {
 		return nil, fmt.Errorf("failed to fetch blob: %w", err)
 	} else {
-		return &blob, nil
+		return io.NopCloser(bytes.NewReader(blob.Data)), nil
 	}
 }
 
 func (s *Service) Store(ctx context.Context, id blobs.ID, reader io.Reader) error {
-	data, err := io.ReadAll(reader)
-	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
-	}
-	blob := &blobs.Blob{
-		ID:   id,
-		Data: data,
-	}
-	if _, err := s.db.NamedExecContext(ctx, `
-		INSERT INTO blobs (id, data)
-		VALUES (:id, :data)
-	`, blob); err != nil {
+	if err := s.store.Put(ctx, id, reader); err != nil {
 		return fmt.Errorf("failed to store blob: %w", err)
 	}
 	return nil
 }
+
+// MigrateFromDatabase moves all blobs from the database to the blob store, one at a time. It's safe to run while the
+// API is serving requests, and to run again if it's interrupted. Returns the number of migrated blobs.
+func (s *Service) MigrateFromDatabase(ctx context.Context) (int, error) {
+	migrated := 0
+	for {
+		var ids []blobs.ID
+		if err := s.db.SelectContext(ctx, &ids, "SELECT id FROM blobs ORDER BY id LIMIT 100"); err != nil {
+			return migrated, fmt.Errorf("failed to list blobs: %w", err)
+		}
+		if len(ids) == 0 {
+			return migrated, nil
+		}
+
+		for _, id := range ids {
+			if err := s.migrate(ctx, id); err != nil {
+				return migrated, fmt.Errorf("failed to migrate blob %s: %w", id, err)
+			}
+			migrated++
+			s.logger.Info("migrated blob", zap.String("id", string(id)))
+		}
+	}
+}
+
+func (s *Service) migrate(ctx context.Context, id blobs.ID) error {
+	var blob blobs.Blob
+	if err := s.db.GetContext(ctx, &blob, "SELECT * FROM blobs WHERE id = $1", id); errors.Is(err, sql.ErrNoRows) {
+		// deleted since it was listed
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to fetch blob: %w", err)
+	}
+
+	if err := s.store.Put(ctx, id, bytes.NewReader(blob.Data)); err != nil {
+		return fmt.Errorf("failed to store blob: %w", err)
+	}
+
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM blobs WHERE id = $1", id); err != nil {
+		return fmt.Errorf("failed to delete blob from database: %w", err)
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	service_buildkite "getsturdy.com/api/pkg/buildkite/enterprise/service"
 	service_ci "getsturdy.com/api/pkg/ci/service"
 	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/codebases/acl"
 	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
 	"getsturdy.com/api/pkg/graphql/resolvers"
 	"getsturdy.com/api/pkg/integrations"
@@ -131,6 +132,10 @@ func (root *rootResolver) CreateOrUpdateBuildkiteIntegration(ctx context.Context
 		return nil, gqlerrors.Error(err)
 	}
 
+	if err := root.authService.CanPerform(ctx, codebases.ID(args.Input.CodebaseID), acl.ActionManageIntegrations, acl.Identity{Type: acl.Integrations, ID: string(providers.ProviderNameBuildkite)}); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
 	// Create new
 	if args.Input.IntegrationID == nil {
 		integration, err := root.createNewConfiguration(ctx, args)
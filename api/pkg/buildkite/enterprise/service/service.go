@@ -7,14 +7,16 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 
 	"getsturdy.com/api/pkg/buildkite"
 	db_buildkite "getsturdy.com/api/pkg/buildkite/enterprise/db"
-	"getsturdy.com/api/pkg/buildkite/service"
+	"getsturdy.com/api/pkg/ci"
 	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/integrations/providers"
 )
 
-var _ service.Service = &Service{}
+var _ ci.BuildProvider = &Service{}
 
 type Service struct {
 	configRepo db_buildkite.Repository
@@ -42,7 +44,11 @@ func (b *Service) GetConfigurationByIntegrationID(ctx context.Context, integrati
 	return b.configRepo.GetConfigByIntegrationID(ctx, integrationID)
 }
 
-func (b *Service) CreateBuild(ctx context.Context, integrationID, ciCommitId, title string) (*service.Build, error) {
+func (b *Service) ProviderName() providers.ProviderName {
+	return providers.ProviderNameBuildkite
+}
+
+func (b *Service) CreateBuild(ctx context.Context, integrationID, ciCommitId, title string) (*ci.Build, error) {
 	cfg, err := b.configRepo.GetConfigByIntegrationID(ctx, integrationID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get config by codebase id: %w", err)
@@ -63,7 +69,7 @@ func (b *Service) CreateBuild(ctx context.Context, integrationID, ciCommitId, ti
 		return nil, fmt.Errorf("failed to build json: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", url(cfg), bytes.NewReader(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", buildsURL(cfg), bytes.NewReader(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create build request: %w", err)
 	}
@@ -90,13 +96,41 @@ func (b *Service) CreateBuild(ctx context.Context, integrationID, ciCommitId, ti
 		return nil, fmt.Errorf("unexpected response, id not set")
 	}
 
-	return &service.Build{
+	return &ci.Build{
+		ID:   strconv.FormatInt(parsedRes.Number, 10),
 		Name: parsedRes.Pipeline.Name,
 		URL:  parsedRes.WebURL,
 	}, nil
 }
 
-func url(cfg *buildkite.Config) string {
+// CancelBuild cancels the build, buildID is the build number.
+func (b *Service) CancelBuild(ctx context.Context, integrationID, buildID string) error {
+	cfg, err := b.configRepo.GetConfigByIntegrationID(ctx, integrationID)
+	if err != nil {
+		return fmt.Errorf("failed to get config by codebase id: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/%s/cancel", buildsURL(cfg), buildID), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create cancel build request: %w", err)
+	}
+
+	req.Header.Add("Authorization", "Bearer "+cfg.APIToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make cancel build request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func buildsURL(cfg *buildkite.Config) string {
 	return fmt.Sprintf("https://api.buildkite.com/v2/organizations/%s/pipelines/%s/builds", slugify(cfg.OrganizationName), slugify(cfg.PipelineName))
 }
 
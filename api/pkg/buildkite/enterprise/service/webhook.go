@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"getsturdy.com/api/pkg/buildkite"
+	"getsturdy.com/api/pkg/ci"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/statuses"
+
+	bk "github.com/buildkite/go-buildkite/v3/buildkite"
+)
+
+var (
+	acceptEvents = map[string]bool{
+		"build.scheduled": true,
+		"build.running":   true,
+		"build.finished":  true,
+		"job.scheduled":   true,
+		"job.running":     true,
+		"job.finished":    true,
+		"job.activated":   true,
+	}
+
+	// Valid states: running, scheduled, passed, failed, blocked, canceled, canceling, skipped, not_run, finished
+	buildkiteStateToType = map[string]statuses.Type{
+		"running":   statuses.TypePending,
+		"blocked":   statuses.TypePending,
+		"canceling": statuses.TypePending,
+		"scheduled": statuses.TypePending,
+		"assigned":  statuses.TypePending,
+
+		"passed":   statuses.TypeHealthy,
+		"skipped":  statuses.TypeHealthy,
+		"not_run":  statuses.TypeHealthy,
+		"finished": statuses.TypeHealthy,
+
+		"failed":   statuses.TypeFailing,
+		"canceled": statuses.TypeFailing,
+	}
+)
+
+var ErrInvalidSignature = errors.New("invalid signature")
+
+var allowedWindow = 5 * time.Minute
+
+// The X-Buildkite-Signature header contains a timestamp and an HMAC signature.
+// The timestamp is prefixed by timestamp= and the signature is prefixed by signature=.
+// e.g. timestamp=1637075221,signature=dbdabe3596995f7bd1f39f50f135df4c48e4291f5368c0eb5c5a02664ae536e9
+//
+// Buildkite generates the signature using HMAC-SHA256; a hash-based message authentication code HMAC used with
+// the SHA-256 hash function and a secret key. The webhook token value is used as the secret key. The timestamp
+// is an integer representation of a UTC timestamp.
+func parseSignatureHeader(xBuildkiteSignature string) (timestamp time.Time, signature string, err error) {
+	params := strings.Split(xBuildkiteSignature, ",")
+	for _, param := range params {
+		kv := strings.Split(param, "=")
+		switch kv[0] {
+		case "timestamp":
+			ts, err := strconv.Atoi(kv[1])
+			if err != nil {
+				return time.Time{}, "", fmt.Errorf("invalid timestamp: %w", err)
+			}
+			timestamp = time.Unix(int64(ts), 0)
+		case "signature":
+			signature = kv[1]
+		}
+	}
+	return
+}
+
+// StatusFromWebhook validates and parses a webhook sent from the Buildkite pipeline of the integration.
+func (b *Service) StatusFromWebhook(ctx context.Context, integrationID string, header http.Header, body []byte) (*statuses.Status, error) {
+	cfg, err := b.configRepo.GetConfigByIntegrationID(ctx, integrationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config by integration id: %w", err)
+	}
+
+	if err := validateSingleSignature(cfg, header.Get("X-Buildkite-Signature"), body); err != nil {
+		return nil, err
+	}
+
+	payload, err := ParseWebhookPayload(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if !payload.Accepted() || payload.Build.Commit == nil {
+		return nil, ci.ErrIgnoredWebhook
+	}
+
+	statusType, err := payload.StatusType()
+	if err != nil {
+		return nil, err
+	}
+
+	webURL := payload.WebURL()
+	return &statuses.Status{
+		CommitSHA:  *payload.Build.Commit,
+		Type:       statusType,
+		Title:      payload.Title(),
+		DetailsURL: &webURL,
+	}, nil
+}
+
+// ValidateSignature validates that the webhook has been signed by any of the Buildkite integrations in the codebase.
+func (b *Service) ValidateSignature(ctx context.Context, xBuildkiteSignature string, codebaseID codebases.ID, requestBody []byte) error {
+	buildkiteConfigs, err := b.GetConfigurationsByCodebaseID(ctx, codebaseID)
+	if err != nil {
+		return fmt.Errorf("failed to get buildkite configuration: %w", err)
+	}
+
+	var lastError error
+
+	for _, cfg := range buildkiteConfigs {
+		if err := validateSingleSignature(cfg, xBuildkiteSignature, requestBody); err != nil {
+			lastError = err
+		} else if err == nil {
+			// Successfully validated
+			return nil
+		}
+	}
+
+	// Unexpected
+	if lastError == nil {
+		return fmt.Errorf("failed to validate buildkite signature (unexpected no success)")
+	}
+
+	return lastError
+}
+
+func validateSingleSignature(buildkiteCfg *buildkite.Config, xBuildkiteSignature string, requestBody []byte) error {
+	timestamp, signature, err := parseSignatureHeader(xBuildkiteSignature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	now := time.Now()
+	if timestamp.After(now) {
+		return ErrInvalidSignature
+	}
+	if timestamp.Before(now.Add(-1 * allowedWindow)) {
+		return ErrInvalidSignature
+	}
+
+	hmacSum := hmac.New(sha256.New, []byte(buildkiteCfg.WebhookSecret))
+	if _, err := hmacSum.Write([]byte(fmt.Sprint(timestamp.Unix()))); err != nil {
+		return fmt.Errorf("failed to write timestamp to sha: %w", err)
+	}
+	if _, err := hmacSum.Write([]byte(".")); err != nil {
+		return fmt.Errorf("failed to write dot to sha: %w", err)
+	}
+	if _, err := hmacSum.Write(requestBody); err != nil {
+		return fmt.Errorf("failed to write request body to sha: %w", err)
+	}
+
+	signatureValid := fmt.Sprintf("%x", hmacSum.Sum(nil)) == signature
+	if !signatureValid {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+type WebhookPayload struct {
+	Event    string      `json:"event"`
+	Build    bk.Build    `json:"build"`
+	Job      *bk.Job     `json:"job"`
+	Pipeline bk.Pipeline `json:"pipeline"`
+}
+
+func ParseWebhookPayload(body []byte) (*WebhookPayload, error) {
+	payload := &WebhookPayload{}
+	if err := json.Unmarshal(body, payload); err != nil {
+		return nil, fmt.Errorf("failed to parse payload: %w", err)
+	}
+	return payload, nil
+}
+
+// Accepted returns true if the payload is of an event that is mapped to statuses.
+func (p *WebhookPayload) Accepted() bool {
+	return acceptEvents[p.Event]
+}
+
+func (p *WebhookPayload) StatusType() (statuses.Type, error) {
+	state := p.State()
+	statusType, ok := buildkiteStateToType[state]
+	if !ok {
+		return statuses.TypeUndefined, fmt.Errorf("invalid status: %s", state)
+	}
+	return statusType, nil
+}
+
+func (p *WebhookPayload) WebURL() string {
+	if p.Job != nil {
+		return p.Job.WebURL
+	}
+	return *p.Build.WebURL
+}
+
+var buildkiteEmoji = regexp.MustCompile(`:[a-z0-9_]+:`)
+
+func sanitize(s string) string {
+	s = buildkiteEmoji.ReplaceAllString(s, "")
+	s = strings.TrimSpace(s)
+	return s
+}
+
+func (p *WebhookPayload) Title() string {
+	if p.Job != nil {
+		return fmt.Sprintf("%s: %s", sanitize(*p.Pipeline.Name), sanitize(*p.Job.Name))
+	}
+	return sanitize(*p.Pipeline.Name)
+}
+
+func (p *WebhookPayload) State() string {
+	if p.Job != nil {
+		return *p.Job.State
+	}
+	return *p.Build.State
+}
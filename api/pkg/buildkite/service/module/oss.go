@@ -4,10 +4,8 @@
 package module
 
 import (
-	"getsturdy.com/api/pkg/buildkite/service"
 	"getsturdy.com/api/pkg/di"
 )
 
-func Module(c *di.Container) {
-	c.Register(service.New, new(service.Service))
-}
+// Module is empty, Buildkite is not available in the OSS version of Sturdy.
+func Module(c *di.Container) {}
@@ -104,3 +104,26 @@ func (r *repo) GetByParentChangeID(ctx context.Context, parentChangeID changes.I
 	}
 	return res, nil
 }
+
+func (r *repo) ListByCodebaseID(ctx context.Context, codebaseID codebases.ID) ([]*changes.Change, error) {
+	var res []*changes.Change
+	err := r.db.SelectContext(ctx, &res, `
+		SELECT
+			id, codebase_id, title, updated_description, user_id, git_creator_name, git_creator_email, created_at, git_created_at, commit_id, parent_change_id, workspace_id
+		FROM
+			changes
+		WHERE
+			codebase_id = $1
+	`, codebaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+	return res, nil
+}
+
+func (r *repo) DeleteByCodebaseID(ctx context.Context, codebaseID codebases.ID) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM changes WHERE codebase_id = $1`, codebaseID); err != nil {
+		return fmt.Errorf("failed to delete changes: %w", err)
+	}
+	return nil
+}
@@ -62,3 +62,22 @@ func (r *inMemoryChangeRepo) GetByParentChangeID(_ context.Context, id changes.I
 	}
 	return nil, sql.ErrNoRows
 }
+
+func (r *inMemoryChangeRepo) ListByCodebaseID(_ context.Context, codebaseID codebases.ID) ([]*changes.Change, error) {
+	var res []*changes.Change
+	for _, change := range r.changes {
+		if change.CodebaseID == codebaseID {
+			res = append(res, change)
+		}
+	}
+	return res, nil
+}
+
+func (r *inMemoryChangeRepo) DeleteByCodebaseID(_ context.Context, codebaseID codebases.ID) error {
+	for id, ch := range r.changes {
+		if ch.CodebaseID == codebaseID {
+			delete(r.changes, id)
+		}
+	}
+	return nil
+}
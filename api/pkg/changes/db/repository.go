@@ -14,4 +14,6 @@ type Repository interface {
 	Insert(ctx context.Context, ch changes.Change) error
 	Update(ctx context.Context, ch changes.Change) error
 	GetByParentChangeID(context.Context, changes.ID) (*changes.Change, error)
+	ListByCodebaseID(context.Context, codebases.ID) ([]*changes.Change, error)
+	DeleteByCodebaseID(context.Context, codebases.ID) error
 }
@@ -11,6 +11,7 @@ import (
 	db_comments "getsturdy.com/api/pkg/comments/db"
 	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
 	"getsturdy.com/api/pkg/graphql/resolvers"
+	"getsturdy.com/api/pkg/trunks"
 	"getsturdy.com/api/vcs/executor"
 
 	"github.com/graph-gophers/graphql-go"
@@ -77,14 +78,19 @@ func NewResolver(
 	}
 }
 
-func (r *ChangeRootResolver) InternalListChanges(ctx context.Context, codebaseID codebases.ID, limit int, before *graphql.ID) ([]resolvers.ChangeResolver, error) {
+func (r *ChangeRootResolver) InternalListChanges(ctx context.Context, codebaseID codebases.ID, limit int, before *graphql.ID, trunkID *graphql.ID) ([]resolvers.ChangeResolver, error) {
 	var beforeChange *changes.ID
 	if before != nil {
 		changeID := changes.ID(*before)
 		beforeChange = &changeID
 	}
 
-	changes, err := r.svc.Changelog(ctx, codebaseID, limit, beforeChange)
+	var changelogOptions []service.ChangelogOption
+	if trunkID != nil {
+		changelogOptions = append(changelogOptions, service.OnTrunk(trunks.ID(*trunkID)))
+	}
+
+	changes, err := r.svc.Changelog(ctx, codebaseID, limit, beforeChange, changelogOptions...)
 	if err != nil {
 		return nil, gqlerrors.Error(err)
 	}
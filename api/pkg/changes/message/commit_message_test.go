@@ -13,3 +13,10 @@ func TestCommitMessage(t *testing.T) {
 	out := CommitMessage(input)
 	assert.Equal(t, expected, out)
 }
+
+func TestText(t *testing.T) {
+	input := "<p>Fix &amp; improve</p><ul><li><p>one</p></li><li><p>two</p></li></ul>"
+	expected := "Fix & improve\n\n* one\n* two"
+
+	assert.Equal(t, expected, Text(input))
+}
@@ -8,6 +8,20 @@ import (
 )
 
 func CommitMessage(draftDescription string) string {
+	return strings.TrimSpace(
+		// bluemonday normalizes all newlines to \n
+		// We want to have Windows-compatible newlines, so replace all \n with \r\n
+		strings.ReplaceAll(sanitize(draftDescription), "\n", "\r\n"),
+	) + "\r\n\r\nCreated with Sturdy"
+}
+
+// Text returns the description as plain text, with \n newlines.
+func Text(description string) string {
+	return html.UnescapeString(strings.TrimSpace(sanitize(description)))
+}
+
+// sanitize strips all html from the description, and adds newlines between the blocks.
+func sanitize(description string) string {
 	newLiner := strings.NewReplacer(
 		"<ul>", "<ul>\n",
 		"<ol>", "<ol>\n",
@@ -23,13 +37,7 @@ func CommitMessage(draftDescription string) string {
 		"<p>", "<p>\n",
 	)
 
-	sanitized := bluemonday.StrictPolicy().Sanitize(newLiner.Replace(draftDescription))
-
-	return strings.TrimSpace(
-		// bluemonday normalizes all newlines to \n
-		// We want to have Windows-compatible newlines, so replace all \n with \r\n
-		strings.ReplaceAll(sanitized, "\n", "\r\n"),
-	) + "\r\n\r\nCreated with Sturdy"
+	return bluemonday.StrictPolicy().Sanitize(newLiner.Replace(description))
 }
 
 func Title(in string) string {
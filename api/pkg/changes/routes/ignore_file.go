@@ -57,7 +57,7 @@ func IgnoreFile(logger *zap.Logger, viewRepo db.Repository, codebaseUserRepo db_
 		}
 
 		// Update .gitignore file
-		err = vcs.AddToGitignore(executorProvider, view.CodebaseID, view.ID, req.Path)
+		err = vcs.AddToGitignore(c.Request.Context(), executorProvider, view.CodebaseID, view.ID, req.Path)
 		if err != nil {
 			logger.Error("failed to add ignore", zap.Error(err))
 			c.AbortWithStatusJSON(http.StatusInternalServerError, err)
@@ -15,11 +15,14 @@ import (
 	"getsturdy.com/api/vcs"
 )
 
+// CreateAndLandFromView creates a new commit from the changes in the view, and lands it on top of the trunk with the
+// given branch name.
 func (s *Service) CreateAndLandFromView(
 	ctx context.Context,
 	viewRepo vcs.RepoWriter,
 	codebaseID codebases.ID,
 	workspaceID string,
+	trunkBranchName string,
 	message string,
 	signature git.Signature,
 	diffOpts ...vcs.DiffOption,
@@ -59,12 +62,12 @@ func (s *Service) CreateAndLandFromView(
 		return "", nil, fmt.Errorf("failed to create the new change: %w", err)
 	}
 
-	if err = fastLand(viewRepo, createdCommitID); err != nil {
+	if err = fastLand(viewRepo, createdCommitID, trunkBranchName); err != nil {
 		return "", nil, fmt.Errorf("landing failed: %w", err)
 	}
 
-	// move the workspace branch to be the same as the new sturdytrunk
-	if err := viewRepo.MoveBranch(workspaceID, "sturdytrunk"); err != nil {
+	// move the workspace branch to be the same as the new trunk
+	if err := viewRepo.MoveBranch(workspaceID, trunkBranchName); err != nil {
 		return "", nil, fmt.Errorf("failed to move workspace to new trunk: %w", err)
 	}
 
@@ -85,7 +88,7 @@ func (s *Service) CreateAndLandFromView(
 
 	// will be executed once the new state has been recorded in the databases
 	resPushFunc := func(viewRepo vcs.RepoGitWriter) error {
-		if err := viewRepo.Push(s.logger, "sturdytrunk"); err != nil {
+		if err := viewRepo.Push(s.logger, trunkBranchName); err != nil {
 			return fmt.Errorf("push failed: %w", err)
 		}
 
@@ -99,12 +102,12 @@ func (s *Service) CreateAndLandFromView(
 	return newBranchCommit, resPushFunc, nil
 }
 
-func fastLand(viewRepo vcs.RepoWriter, commitID string) (err error) {
-	if err = viewRepo.FetchBranch("sturdytrunk"); err != nil {
+func fastLand(viewRepo vcs.RepoWriter, commitID, trunkBranchName string) (err error) {
+	if err = viewRepo.FetchBranch(trunkBranchName); err != nil {
 		return fmt.Errorf("failed to fetch before fastland: %w", err)
 	}
 
-	if err := syncSingleCommitOnBranch(viewRepo, commitID, "origin", "sturdytrunk"); err != nil {
+	if err := syncSingleCommitOnBranch(viewRepo, commitID, "origin", trunkBranchName); err != nil {
 		return fmt.Errorf("failed to land: %w", err)
 	}
 
@@ -17,6 +17,7 @@ import (
 	"getsturdy.com/api/pkg/codebases"
 	db_codebases "getsturdy.com/api/pkg/codebases/db"
 	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
+	"getsturdy.com/api/pkg/trunks"
 	"getsturdy.com/api/pkg/unidiff"
 	"getsturdy.com/api/pkg/workspaces"
 	"getsturdy.com/api/vcs"
@@ -75,7 +76,7 @@ func (svc *Service) GetByCommitAndCodebase(ctx context.Context, commitID string,
 }
 
 func (svc *Service) CreateOnTop(ctx context.Context, ws *workspaces.Workspace, commitID string) (*changes.Change, error) {
-	headChange, err := svc.head(ctx, ws.CodebaseID)
+	headChange, err := svc.head(ctx, ws.CodebaseID, ws.TrunkID)
 	switch {
 	case errors.Is(err, ErrNotFound):
 		return svc.CreateWithChangeAsParent(ctx, ws, commitID, nil)
@@ -140,11 +141,20 @@ func (svc *Service) CreateWithChangeAsParent(ctx context.Context, ws *workspaces
 	return &changeChange, nil
 }
 
-func (svc *Service) head(ctx context.Context, codebaseID codebases.ID) (*changes.Change, error) {
+func (svc *Service) head(ctx context.Context, codebaseID codebases.ID, trunkID *trunks.ID) (*changes.Change, error) {
 	// To find the root commit, peek into git
 	var headCommitID string
 
 	getHeadCommit := func(repo vcs.RepoGitReader) error {
+		if trunkID != nil {
+			commitID, err := repo.BranchCommitID(trunks.BranchName(trunkID))
+			if err != nil {
+				return fmt.Errorf("could not find trunk head commit: %w", err)
+			}
+			headCommitID = commitID
+			return nil
+		}
+
 		headCommit, err := repo.HeadCommit()
 		if err != nil {
 			return fmt.Errorf("could not find head commit: %w", err)
@@ -153,7 +163,7 @@ func (svc *Service) head(ctx context.Context, codebaseID codebases.ID) (*changes
 		return nil
 	}
 
-	err := svc.executorProvider.New().GitRead(getHeadCommit).ExecTrunk(codebaseID, "changeServiceChangelog")
+	err := svc.executorProvider.New().GitRead(getHeadCommit).ExecTrunkContext(ctx, codebaseID, "changeServiceChangelog")
 	switch {
 	case errors.Is(err, vcs.ErrNotFound):
 		return nil, ErrNotFound
@@ -164,20 +174,43 @@ func (svc *Service) head(ctx context.Context, codebaseID codebases.ID) (*changes
 	}
 }
 
+type ChangelogOptions struct {
+	TrunkID *trunks.ID
+}
+
+type ChangelogOption func(*ChangelogOptions)
+
+// OnTrunk lists the changes of the given trunk, instead of the default trunk.
+func OnTrunk(trunkID trunks.ID) ChangelogOption {
+	return func(options *ChangelogOptions) {
+		options.TrunkID = &trunkID
+	}
+}
+
+func getChangelogOptions(oo ...ChangelogOption) *ChangelogOptions {
+	options := &ChangelogOptions{}
+	for _, o := range oo {
+		o(options)
+	}
+	return options
+}
+
 // Changelog returns a list of changes for the given codebaesID in the descending order.
 //
 // limit - the maximum number of changes to return
 // before - if set, used as a change id to start the list from
 //          if not set, list will start from the head
-func (svc *Service) Changelog(ctx context.Context, codebaseID codebases.ID, limit int, before *changes.ID) ([]*changes.Change, error) {
+func (svc *Service) Changelog(ctx context.Context, codebaseID codebases.ID, limit int, before *changes.ID, oo ...ChangelogOption) ([]*changes.Change, error) {
 	var (
 		startFrom *changes.Change
 		err       error
 		res       []*changes.Change
 	)
 
+	options := getChangelogOptions(oo...)
+
 	if before == nil {
-		startFrom, err = svc.head(ctx, codebaseID)
+		startFrom, err = svc.head(ctx, codebaseID, options.TrunkID)
 		res = append(res, startFrom)
 	} else {
 		startFrom, err = svc.changeRepo.Get(ctx, *before)
@@ -245,7 +278,7 @@ func (svc *Service) ParentChange(ctx context.Context, ch *changes.Change) (*chan
 		parents = details.Parents
 		return nil
 	}
-	if err := svc.executorProvider.New().GitRead(getCurrentFromGit).ExecTrunk(ch.CodebaseID, "changeService.parentChange"); err != nil {
+	if err := svc.executorProvider.New().GitRead(getCurrentFromGit).ExecTrunkContext(ctx, ch.CodebaseID, "changeService.parentChange"); err != nil {
 		return nil, fmt.Errorf("could not get from git: %w", err)
 	}
 
@@ -305,7 +338,7 @@ func (svc *Service) importCommitToChange(ctx context.Context, codebaseID codebas
 		}
 		return nil
 	}
-	if err := svc.executorProvider.New().GitRead(getCommit).ExecTrunk(codebaseID, "changeServiceChangelog"); err != nil {
+	if err := svc.executorProvider.New().GitRead(getCommit).ExecTrunkContext(ctx, codebaseID, "changeServiceChangelog"); err != nil {
 		return nil, err
 	}
 
@@ -374,7 +407,7 @@ func (svc *Service) Diffs(ctx context.Context, ch *changes.Change, allower *unid
 		fn = diffToRoot
 	}
 
-	err = svc.executorProvider.New().GitRead(fn).ExecTrunk(ch.CodebaseID, "changeService.Diffs")
+	err = svc.executorProvider.New().GitRead(fn).ExecTrunkContext(ctx, ch.CodebaseID, "changeService.Diffs")
 	if err != nil {
 		return nil, err
 	}
@@ -399,7 +432,7 @@ func (svc *Service) HeadChange(ctx context.Context, cb *codebases.Codebase) (*ch
 		return svc.GetChangeByID(ctx, changes.ID(*cb.CachedHeadChangeID))
 	}
 
-	headChange, err := svc.head(ctx, cb.ID)
+	headChange, err := svc.head(ctx, cb.ID, nil)
 	switch {
 	case errors.Is(err, ErrNotFound):
 		cb.CalculatedHeadChangeID = true
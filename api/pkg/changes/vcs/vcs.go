@@ -135,7 +135,7 @@ func CreateChangesTreeFromPatches(ctx context.Context, logger *zap.Logger, r vcs
 	return treeID, nil
 }
 
-func AddToGitignore(executorProvider executor.Provider, codebaseID codebases.ID, viewID, ignorePath string) error {
+func AddToGitignore(ctx context.Context, executorProvider executor.Provider, codebaseID codebases.ID, viewID, ignorePath string) error {
 	executor := executorProvider.New().Read(func(repo vcs.RepoReader) error {
 		ignoreFilePath := path.Join(repo.Path(), ".gitignore")
 
@@ -159,7 +159,7 @@ func AddToGitignore(executorProvider executor.Provider, codebaseID codebases.ID,
 		return nil
 	})
 
-	if err := executor.ExecView(codebaseID, viewID, "AddToGitignore"); err != nil {
+	if err := executor.ExecViewContext(ctx, codebaseID, viewID, "AddToGitignore"); err != nil {
 		return err
 	}
 
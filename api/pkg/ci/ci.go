@@ -15,3 +15,15 @@ type Commit struct {
 	TrunkCommitSHA  string       `db:"trunk_commit_id"`
 	CreatedAt       time.Time    `db:"created_at"`
 }
+
+// StartedBuild is a build of a commit in the trunk repo that has been started by a BuildProvider. It's kept so that
+// the build can be cancelled if the commit no longer needs to be tested.
+type StartedBuild struct {
+	ID             string       `db:"id"`
+	CodebaseID     codebases.ID `db:"codebase_id"`
+	IntegrationID  string       `db:"integration_id"`
+	BuildID        string       `db:"build_id"`
+	Title          string       `db:"title"`
+	TrunkCommitSHA string       `db:"trunk_commit_id"`
+	CreatedAt      time.Time    `db:"created_at"`
+}
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"getsturdy.com/api/pkg/ci"
+	"getsturdy.com/api/pkg/codebases"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func NewBuildRepository(db *sqlx.DB) BuildRepository {
+	return &buildDatabase{db: db}
+}
+
+type buildDatabase struct {
+	db *sqlx.DB
+}
+
+func (r *buildDatabase) Create(ctx context.Context, b *ci.StartedBuild) error {
+	if _, err := r.db.NamedExecContext(ctx, `INSERT INTO ci_builds
+		(id, codebase_id, integration_id, build_id, title, trunk_commit_id, created_at)
+		VALUES(:id, :codebase_id, :integration_id, :build_id, :title, :trunk_commit_id, :created_at)`, b); err != nil {
+		return fmt.Errorf("failed to insert: %w", err)
+	}
+	return nil
+}
+
+func (r *buildDatabase) ListByCodebaseAndTrunkCommitID(ctx context.Context, codebaseID codebases.ID, trunkCommitID string) ([]*ci.StartedBuild, error) {
+	var res []*ci.StartedBuild
+	if err := r.db.SelectContext(ctx, &res, `SELECT id, codebase_id, integration_id, build_id, title, trunk_commit_id, created_at
+		FROM ci_builds
+		WHERE codebase_id = $1 AND trunk_commit_id = $2`, codebaseID, trunkCommitID); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+	return res, nil
+}
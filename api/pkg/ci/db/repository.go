@@ -11,3 +11,8 @@ type CommitRepository interface {
 	Create(context.Context, *ci.Commit) error
 	GetByCodebaseAndCiRepoCommitID(ctx context.Context, codebaseID codebases.ID, ciRepoCommitID string) (*ci.Commit, error)
 }
+
+type BuildRepository interface {
+	Create(context.Context, *ci.StartedBuild) error
+	ListByCodebaseAndTrunkCommitID(ctx context.Context, codebaseID codebases.ID, trunkCommitID string) ([]*ci.StartedBuild, error)
+}
@@ -0,0 +1,53 @@
+package ci
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/integrations/providers"
+	"getsturdy.com/api/pkg/statuses"
+)
+
+// ErrIgnoredWebhook is returned by a BuildProvider when a webhook was valid, but did not contain a status update.
+var ErrIgnoredWebhook = errors.New("webhook ignored")
+
+// Build is a build that has been started by a BuildProvider.
+type Build struct {
+	// ID is the id of the build in the provider, it's used to cancel the build.
+	ID          string
+	Name        string
+	Description *string
+	URL         string
+}
+
+// BuildProvider is a continuous integration provider that builds can be started on.
+type BuildProvider interface {
+	// ProviderName is the name of the integrations that are handled by this provider.
+	ProviderName() providers.ProviderName
+
+	// CreateBuild starts a build of ciCommitSHA, a commit in the ci repository of the codebase.
+	CreateBuild(ctx context.Context, integrationID, ciCommitSHA, title string) (*Build, error)
+	// CancelBuild cancels a build that has been started with CreateBuild.
+	CancelBuild(ctx context.Context, integrationID, buildID string) error
+	// StatusFromWebhook validates a webhook sent by the provider, and maps it to a status. CommitSHA of the returned
+	// status is the sha of the commit in the ci repository, and not the trunk commit.
+	StatusFromWebhook(ctx context.Context, integrationID string, header http.Header, body []byte) (*statuses.Status, error)
+}
+
+// BuildProviderOut is used to register a BuildProvider in the di container:
+//
+//	c.Register(func(p *MyProvider) ci.BuildProviderOut { return ci.BuildProviderOut{BuildProvider: p} })
+type BuildProviderOut struct {
+	di.Out
+
+	BuildProvider BuildProvider `group:"ci_build_providers"`
+}
+
+// BuildProvidersIn contains all BuildProviders that have been registered in the di container.
+type BuildProvidersIn struct {
+	di.In
+
+	BuildProviders []BuildProvider `group:"ci_build_providers"`
+}
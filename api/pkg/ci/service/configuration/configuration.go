@@ -1,5 +1,6 @@
 package configuration
 
 type Configuration struct {
-	PublicAPIHostname string `long:"public-api-hostname" description:"Public API hostname. Used to fetch codebases from CI"`
+	PublicAPIHostname            string `long:"public-api-hostname" description:"Public API hostname. Used to fetch codebases from CI"`
+	AllowPrivateWebhookAddresses bool   `long:"allow-private-webhook-addresses" description:"Allow webhook CI integrations to send builds to loopback, link-local and private network addresses"`
 }
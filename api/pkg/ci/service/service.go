@@ -6,12 +6,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"path"
 	"strings"
 	"time"
 
-	service_buildkite "getsturdy.com/api/pkg/buildkite/service"
 	"getsturdy.com/api/pkg/changes"
 	"getsturdy.com/api/pkg/ci"
 	db_ci "getsturdy.com/api/pkg/ci/db"
@@ -33,6 +33,7 @@ import (
 	"getsturdy.com/api/vcs/provider"
 
 	"github.com/google/uuid"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 )
 
@@ -47,9 +48,10 @@ type Service struct {
 
 	configRepo   db_integrations.IntegrationsRepository
 	ciCommitRepo db_ci.CommitRepository
+	ciBuildRepo  db_ci.BuildRepository
 
-	buildkiteService service_buildkite.Service
-	githubService    service_github.Service
+	buildProviders map[providers.ProviderName]ci.BuildProvider
+	githubService  service_github.Service
 
 	publicApiHostname string
 	statusService     *svc_statuses.Service
@@ -63,8 +65,9 @@ func New(
 
 	configRepo db_integrations.IntegrationsRepository,
 	ciCommitRepo db_ci.CommitRepository,
+	ciBuildRepo db_ci.BuildRepository,
 
-	buildkiteService service_buildkite.Service,
+	buildProviders ci.BuildProvidersIn,
 	githubService service_github.Service,
 
 	cfg *configuration.Configuration,
@@ -72,15 +75,21 @@ func New(
 	jwtService *service_jwt.Service,
 	snapshotter *service_snaphsotter.Service,
 ) *Service {
+	providersByName := make(map[providers.ProviderName]ci.BuildProvider, len(buildProviders.BuildProviders))
+	for _, provider := range buildProviders.BuildProviders {
+		providersByName[provider.ProviderName()] = provider
+	}
+
 	return &Service{
 		logger:           logger.Named("ciService"),
 		executorProvider: executorProvider,
 
 		configRepo:   configRepo,
 		ciCommitRepo: ciCommitRepo,
+		ciBuildRepo:  ciBuildRepo,
 
-		buildkiteService: buildkiteService,
-		githubService:    githubService,
+		buildProviders: providersByName,
+		githubService:  githubService,
 
 		publicApiHostname: cfg.PublicAPIHostname,
 		statusService:     statusService,
@@ -99,7 +108,7 @@ type sturdyJsonData struct {
 //go:embed download.bash
 var downloadBash string
 
-func (svc *Service) loadSeedFiles(commitSHA string, codebaseID codebases.ID, seedFiles []string) (map[string][]byte, error) {
+func (svc *Service) loadSeedFiles(ctx context.Context, commitSHA string, codebaseID codebases.ID, seedFiles []string) (map[string][]byte, error) {
 	seedFilesContents := make(map[string][]byte)
 	if err := svc.executorProvider.New().GitRead(func(repo vcs.RepoGitReader) error {
 		for _, sf := range seedFiles {
@@ -114,7 +123,7 @@ func (svc *Service) loadSeedFiles(commitSHA string, codebaseID codebases.ID, see
 			}
 		}
 		return nil
-	}).ExecTrunk(codebaseID, "readSeedFiles"); err != nil {
+	}).ExecTrunkContext(ctx, codebaseID, "readSeedFiles"); err != nil {
 		return nil, fmt.Errorf("failed to get seed files: %w", err)
 	}
 	return seedFilesContents, nil
@@ -127,7 +136,7 @@ func (svc *Service) createSnapshotGit(ctx context.Context, snapshot *snapshots.S
 	}
 
 	// Load seed files contents from trunk
-	seedFilesContents, err := svc.loadSeedFiles(snapshot.CommitSHA, snapshot.CodebaseID, seedFiles)
+	seedFilesContents, err := svc.loadSeedFiles(ctx, snapshot.CommitSHA, snapshot.CodebaseID, seedFiles)
 	if err != nil {
 		return "", err
 	}
@@ -201,7 +210,7 @@ func (svc *Service) createSnapshotGit(ctx context.Context, snapshot *snapshots.S
 			}
 
 			return nil
-		}).ExecView(snapshot.CodebaseID, "ci", "prepareContinuousIntegrationRepo"); err != nil {
+		}).ExecViewContext(ctx, snapshot.CodebaseID, "ci", "prepareContinuousIntegrationRepo"); err != nil {
 		return "", err
 	}
 
@@ -226,7 +235,7 @@ func (svc *Service) createChangeGit(ctx context.Context, ch *changes.Change, see
 	}
 
 	// Load seed files contents from trunk
-	seedFilesContents, err := svc.loadSeedFiles(*ch.CommitID, ch.CodebaseID, seedFiles)
+	seedFilesContents, err := svc.loadSeedFiles(ctx, *ch.CommitID, ch.CodebaseID, seedFiles)
 	if err != nil {
 		return "", err
 	}
@@ -299,7 +308,7 @@ func (svc *Service) createChangeGit(ctx context.Context, ch *changes.Change, see
 			}
 
 			return nil
-		}).ExecView(ch.CodebaseID, "ci", "prepareContinuousIntegrationRepo"); err != nil {
+		}).ExecViewContext(ctx, ch.CodebaseID, "ci", "prepareContinuousIntegrationRepo"); err != nil {
 		return "", err
 	}
 
@@ -369,14 +378,20 @@ func (svc *Service) TriggerWorkspace(ctx context.Context, workspace *workspaces.
 		return nil, fmt.Errorf("workspace has no latest snapshot")
 	}
 
-	ciConfigurations, err := svc.configRepo.ListByCodebaseID(ctx, workspace.CodebaseID)
+	snapshot, err := svc.snapshotter.GetByID(ctx, *workspace.LatestSnapshotID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list ci configs: %w", err)
+		return nil, fmt.Errorf("failed to get snapshot: %w", err)
 	}
 
-	snapshot, err := svc.snapshotter.GetByID(ctx, *workspace.LatestSnapshotID)
+	return svc.TriggerSnapshot(ctx, workspace, snapshot, opts...)
+}
+
+// TriggerSnapshot starts a continuous integration build for the given snapshot of the workspace. The snapshot does
+// not have to be the latest snapshot of the workspace.
+func (svc *Service) TriggerSnapshot(ctx context.Context, workspace *workspaces.Workspace, snapshot *snapshots.Snapshot, opts ...TriggerOption) ([]*statuses.Status, error) {
+	ciConfigurations, err := svc.configRepo.ListByCodebaseID(ctx, workspace.CodebaseID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get snapshot: %w", err)
+		return nil, fmt.Errorf("failed to list ci configs: %w", err)
 	}
 	// todo: do not mix seed files?
 	seedFiles := []string{}
@@ -399,34 +414,12 @@ func (svc *Service) TriggerWorkspace(ctx context.Context, workspace *workspaces.
 			}
 		}
 
-		switch config.Provider {
-		case providers.ProviderNameBuildkite:
-			build, err := svc.buildkiteService.CreateBuild(ctx, config.ID, commitID, workspace.NameOrFallback())
-			if err != nil {
-				return nil, fmt.Errorf("failed to trigger buildkite build: %w", err)
-			}
-
-			status := &statuses.Status{
-				ID:          uuid.NewString(),
-				CommitSHA:   snapshot.CommitSHA,
-				CodebaseID:  snapshot.CodebaseID,
-				Type:        statuses.TypePending,
-				Title:       build.Name,
-				Description: build.Description,
-				DetailsURL:  &build.URL,
-				Timestamp:   time.Now(),
-			}
-
-			// Set status
-			if err := svc.statusService.Set(ctx, status); err != nil {
-				return nil, fmt.Errorf("failed to set status: %w", err)
-			}
-
-			ss = append(ss, status)
-
-		default:
-			return nil, fmt.Errorf("unsupported provider: %s", config.Provider)
+		status, err := svc.createBuild(ctx, config, commitID, snapshot.CommitSHA, workspace.NameOrFallback())
+		if err != nil {
+			return nil, err
 		}
+
+		ss = append(ss, status)
 	}
 
 	// if the codebase has a github integration, trigger ci via github as well
@@ -475,36 +468,151 @@ func (svc *Service) TriggerChange(ctx context.Context, ch *changes.Change, opts
 			}
 		}
 
-		switch config.Provider {
-		case providers.ProviderNameBuildkite:
-			build, err := svc.buildkiteService.CreateBuild(ctx, config.ID, commitID, title)
-			if err != nil {
-				return nil, fmt.Errorf("failed to trigger build: %w", err)
-			}
+		status, err := svc.createBuild(ctx, config, commitID, *ch.CommitID, title)
+		if err != nil {
+			return nil, err
+		}
 
-			status := &statuses.Status{
-				ID:          uuid.NewString(),
-				CommitSHA:   *ch.CommitID,
-				CodebaseID:  ch.CodebaseID,
-				Type:        statuses.TypePending,
-				Title:       build.Name,
-				Description: build.Description,
-				DetailsURL:  &build.URL,
-				Timestamp:   time.Now(),
-			}
+		ss = append(ss, status)
+	}
 
-			// Set status
-			if err := svc.statusService.Set(ctx, status); err != nil {
-				return nil, fmt.Errorf("failed to set status: %w", err)
-			}
+	return ss, nil
+}
+
+// createBuild starts a build of ciCommitSHA with the provider of the integration, and sets a pending status on
+// trunkCommitSHA.
+func (svc *Service) createBuild(ctx context.Context, integration *integrations.Integration, ciCommitSHA, trunkCommitSHA, title string) (*statuses.Status, error) {
+	provider, ok := svc.buildProviders[integration.Provider]
+	if !ok {
+		return nil, fmt.Errorf("unsupported provider: %s", integration.Provider)
+	}
 
-			ss = append(ss, status)
-		default:
-			return nil, fmt.Errorf("unsupported provider: %s", config.Provider)
+	build, err := provider.CreateBuild(ctx, integration.ID, ciCommitSHA, title)
+	if err != nil {
+		return nil, fmt.Errorf("failed to trigger %s build: %w", integration.Provider, err)
+	}
+
+	if err := svc.ciBuildRepo.Create(ctx, &ci.StartedBuild{
+		ID:             uuid.NewString(),
+		CodebaseID:     integration.CodebaseID,
+		IntegrationID:  integration.ID,
+		BuildID:        build.ID,
+		Title:          build.Name,
+		TrunkCommitSHA: trunkCommitSHA,
+		CreatedAt:      time.Now(),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create build: %w", err)
+	}
+
+	status := &statuses.Status{
+		ID:          uuid.NewString(),
+		CommitSHA:   trunkCommitSHA,
+		CodebaseID:  integration.CodebaseID,
+		Type:        statuses.TypePending,
+		Title:       build.Name,
+		Description: build.Description,
+		Timestamp:   time.Now(),
+	}
+	if build.URL != "" {
+		status.DetailsURL = &build.URL
+	}
+
+	// Set status
+	if err := svc.statusService.Set(ctx, status); err != nil {
+		return nil, fmt.Errorf("failed to set status: %w", err)
+	}
+
+	return status, nil
+}
+
+// CancelBuild cancels a build that has been started by the integration.
+func (svc *Service) CancelBuild(ctx context.Context, integrationID, buildID string) error {
+	integration, err := svc.configRepo.Get(ctx, integrationID)
+	if err != nil {
+		return fmt.Errorf("failed to get integration: %w", err)
+	}
+
+	provider, ok := svc.buildProviders[integration.Provider]
+	if !ok {
+		return fmt.Errorf("unsupported provider: %s", integration.Provider)
+	}
+
+	if err := provider.CancelBuild(ctx, integration.ID, buildID); err != nil {
+		return fmt.Errorf("failed to cancel %s build: %w", integration.Provider, err)
+	}
+
+	return nil
+}
+
+// CancelBuilds cancels the builds of trunkCommitSHA that have been started by the integrations of the codebase, and
+// are still pending.
+func (svc *Service) CancelBuilds(ctx context.Context, codebaseID codebases.ID, trunkCommitSHA string) error {
+	builds, err := svc.ciBuildRepo.ListByCodebaseAndTrunkCommitID(ctx, codebaseID, trunkCommitSHA)
+	if err != nil {
+		return fmt.Errorf("failed to list builds: %w", err)
+	}
+	if len(builds) == 0 {
+		return nil
+	}
+
+	statusList, err := svc.statusService.List(ctx, codebaseID, trunkCommitSHA)
+	if err != nil {
+		return fmt.Errorf("failed to list statuses: %w", err)
+	}
+	statusByTitle := make(map[string]statuses.Type, len(statusList))
+	for _, status := range statusList {
+		statusByTitle[status.Title] = status.Type
+	}
+
+	var errs error
+	for _, build := range builds {
+		if statusType, ok := statusByTitle[build.Title]; ok && statusType != statuses.TypePending {
+			continue // the build has already finished
+		}
+		if err := svc.CancelBuild(ctx, build.IntegrationID, build.BuildID); err != nil {
+			errs = multierr.Append(errs, err)
 		}
 	}
+	return errs
+}
 
-	return ss, nil
+// HandleWebhook validates a webhook sent by the provider of the integration, and sets the status that the webhook
+// contains. If the webhook did not contain a status, ci.ErrIgnoredWebhook is returned.
+func (svc *Service) HandleWebhook(ctx context.Context, integrationID string, header http.Header, body []byte) (*statuses.Status, error) {
+	integration, err := svc.configRepo.Get(ctx, integrationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get integration: %w", err)
+	}
+
+	if integration.DeletedAt != nil {
+		return nil, fmt.Errorf("integration is deleted")
+	}
+
+	provider, ok := svc.buildProviders[integration.Provider]
+	if !ok {
+		return nil, fmt.Errorf("unsupported provider: %s", integration.Provider)
+	}
+
+	status, err := provider.StatusFromWebhook(ctx, integration.ID, header, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s webhook: %w", integration.Provider, err)
+	}
+
+	trunkCommitSHA, err := svc.GetTrunkCommitSHA(ctx, integration.CodebaseID, status.CommitSHA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trunk commit: %w", err)
+	}
+
+	status.ID = uuid.NewString()
+	status.CommitSHA = trunkCommitSHA
+	status.CodebaseID = integration.CodebaseID
+	status.Timestamp = time.Now()
+
+	if err := svc.statusService.Set(ctx, status); err != nil {
+		return nil, fmt.Errorf("failed to set status: %w", err)
+	}
+
+	return status, nil
 }
 
 func (svc *Service) GetTrunkCommitSHA(ctx context.Context, codebaseID codebases.ID, ciRepoCommitID string) (string, error) {
@@ -29,7 +29,7 @@ func UserCan(
 		return false, err
 	}
 
-	allowedByID := aclPolicy.Assert(
+	allowedByID := aclPolicy.Allows(
 		acl.Identity{Type: acl.Users, ID: userID.String()},
 		action,
 		resource,
@@ -43,7 +43,7 @@ func UserCan(
 		return false, err
 	}
 
-	allowedByEmail := aclPolicy.Assert(
+	allowedByEmail := aclPolicy.Allows(
 		acl.Identity{Type: acl.Users, ID: user.Email},
 		action,
 		resource,
@@ -14,6 +14,7 @@ type ACLRepository interface {
 	Create(context.Context, acl.ACL) error
 	Update(context.Context, acl.ACL) error
 	GetByCodebaseID(context.Context, codebases.ID) (acl.ACL, error)
+	DeleteByCodebaseID(context.Context, codebases.ID) error
 }
 
 type aclRepository struct {
@@ -71,3 +72,10 @@ func (r *aclRepository) GetByCodebaseID(ctx context.Context, codebaseID codebase
 	}
 	return *entity, nil
 }
+
+func (r *aclRepository) DeleteByCodebaseID(ctx context.Context, codebaseID codebases.ID) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM acls WHERE codebase_id = $1`, codebaseID); err != nil {
+		return fmt.Errorf("failed to delete acls: %w", err)
+	}
+	return nil
+}
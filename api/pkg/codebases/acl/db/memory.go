@@ -41,3 +41,14 @@ func (r *inMemoryAclRepo) GetByCodebaseID(_ context.Context, codebaseID codebase
 	}
 	return acl.ACL{}, sql.ErrNoRows
 }
+
+func (r *inMemoryAclRepo) DeleteByCodebaseID(_ context.Context, codebaseID codebases.ID) error {
+	kept := r.acls[:0]
+	for _, v := range r.acls {
+		if v.CodebaseID != codebaseID {
+			kept = append(kept, v)
+		}
+	}
+	r.acls = kept
+	return nil
+}
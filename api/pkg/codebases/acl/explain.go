@@ -0,0 +1,37 @@
+package acl
+
+// Explanation describes why a principal can or can't perform an action on a resource.
+type Explanation struct {
+	Allowed bool
+	// Rules are the rules that match the principal, action and resource, regardless of their effect.
+	Rules []*Rule
+	// Groups are the groups that have either the principal or the resource as a member.
+	Groups []*Group
+}
+
+// Explain evaluates if _principal_ can _action_ on _resource_, and returns the rules and the groups that were
+// taken into account.
+func (p Policy) Explain(principal Identity, action Action, resource Identity) *Explanation {
+	explanation := &Explanation{
+		Allowed: p.Allows(principal, action, resource),
+		Rules:   []*Rule{},
+		Groups:  []*Group{},
+	}
+
+	for _, rule := range p.Rules {
+		if rule.Assert(principal, action, resource, p.Groups) {
+			explanation.Rules = append(explanation.Rules, rule)
+		}
+	}
+
+	for _, group := range p.Groups {
+		for _, member := range resolveGroups(group.Members, p.Groups) {
+			if member.Matches(principal) || member.Matches(resource) {
+				explanation.Groups = append(explanation.Groups, group)
+				break
+			}
+		}
+	}
+
+	return explanation
+}
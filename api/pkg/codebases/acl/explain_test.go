@@ -0,0 +1,64 @@
+package acl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Policy_Explain(t *testing.T) {
+	p := Policy{
+		Rules: []*Rule{
+			{
+				ID:     "everyone can write everything",
+				Action: ActionWrite,
+				Principals: []*Identifier{
+					{Type: Users, Pattern: "*"},
+				},
+				Resources: []*Identifier{
+					{Type: Files, Pattern: "*"},
+				},
+			},
+			{
+				ID:     "contractors can not write infra",
+				Effect: EffectDeny,
+				Action: ActionWrite,
+				Principals: []*Identifier{
+					{Type: Groups, Pattern: "contractors"},
+				},
+				Resources: []*Identifier{
+					{Type: Files, Pattern: "infra/**"},
+				},
+			},
+		},
+		Groups: []*Group{
+			{
+				ID: "contractors",
+				Members: []*Identifier{
+					{Type: Users, Pattern: "user-1"},
+				},
+			},
+			{
+				ID: "admins",
+				Members: []*Identifier{
+					{Type: Users, Pattern: "user-2"},
+				},
+			},
+		},
+	}
+
+	explanation := p.Explain(Identity{Type: Users, ID: "user-1"}, ActionWrite, Identity{Type: Files, ID: "infra/main.tf"})
+	assert.False(t, explanation.Allowed)
+	assert.Equal(t, []*Rule{p.Rules[0], p.Rules[1]}, explanation.Rules)
+	assert.Equal(t, []*Group{p.Groups[0]}, explanation.Groups)
+
+	explanation = p.Explain(Identity{Type: Users, ID: "user-2"}, ActionWrite, Identity{Type: Files, ID: "infra/main.tf"})
+	assert.True(t, explanation.Allowed)
+	assert.Equal(t, []*Rule{p.Rules[0]}, explanation.Rules)
+	assert.Equal(t, []*Group{p.Groups[1]}, explanation.Groups)
+
+	explanation = p.Explain(Identity{Type: Users, ID: "user-3"}, ActionLand, Identity{Type: Workspaces, ID: "workspace-1"})
+	assert.True(t, explanation.Allowed, "land is opt-in")
+	assert.Empty(t, explanation.Rules)
+	assert.Empty(t, explanation.Groups)
+}
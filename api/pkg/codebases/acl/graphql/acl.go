@@ -66,26 +66,18 @@ func (r *ACLRootResolver) InternalACLByCodebaseID(ctx context.Context, codebaseI
 }
 
 func (r *ACLRootResolver) UpdateACL(ctx context.Context, args resolvers.UpdateACLArgs) (resolvers.ACLResolver, error) {
-	a, err := r.aclProvider.GetByCodebaseID(ctx, codebases.ID(args.Input.CodebaseID))
+	a, err := r.getWritableACL(ctx, codebases.ID(args.Input.CodebaseID))
 	if err != nil {
-		return nil, gqlerrors.Error(err)
-	}
-
-	allowed, err := access.UserCanWriteACL(ctx, r.userRepo, a.Policy, string(a.ID))
-	if err != nil {
-		return nil, gqlerrors.Error(err)
-	}
-	if !allowed {
-		return nil, gqlerrors.Error(gqlerrors.ErrForbidden)
+		return nil, err
 	}
 
 	if args.Input.Policy == nil {
 		return &aclResolver{a: a, root: r}, nil
 	}
 
-	policy := acl.Policy{}
-	if err := hujson.Unmarshal([]byte(*args.Input.Policy), &policy); err != nil {
-		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "policy", "failed to decode as json")
+	policy, err := parsePolicy(*args.Input.Policy)
+	if err != nil {
+		return nil, err
 	}
 
 	if errs := policy.Errors(string(a.ID)); len(errs) > 0 {
@@ -118,3 +110,11 @@ func (r *aclResolver) ID() graphql.ID {
 func (r *aclResolver) Policy() (string, error) {
 	return r.a.RawPolicy, nil
 }
+
+func parsePolicy(raw string) (acl.Policy, error) {
+	policy := acl.Policy{}
+	if err := hujson.Unmarshal([]byte(raw), &policy); err != nil {
+		return acl.Policy{}, gqlerrors.Error(gqlerrors.ErrBadRequest, "policy", "failed to decode as json")
+	}
+	return policy, nil
+}
@@ -0,0 +1,177 @@
+package graphql
+
+import (
+	"context"
+	"sort"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/codebases/acl"
+	"getsturdy.com/api/pkg/codebases/acl/access"
+	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
+	"getsturdy.com/api/pkg/graphql/resolvers"
+)
+
+// getWritableACL returns the ACL of the codebase if the user is allowed to change it.
+func (r *ACLRootResolver) getWritableACL(ctx context.Context, codebaseID codebases.ID) (acl.ACL, error) {
+	a, err := r.aclProvider.GetByCodebaseID(ctx, codebaseID)
+	if err != nil {
+		return acl.ACL{}, gqlerrors.Error(err)
+	}
+
+	allowed, err := access.UserCanWriteACL(ctx, r.userRepo, a.Policy, string(a.ID))
+	if err != nil {
+		return acl.ACL{}, gqlerrors.Error(err)
+	}
+	if !allowed {
+		return acl.ACL{}, gqlerrors.Error(gqlerrors.ErrForbidden)
+	}
+
+	return a, nil
+}
+
+func (r *ACLRootResolver) ExplainACL(ctx context.Context, args resolvers.ExplainACLArgs) (resolvers.ACLExplanationResolver, error) {
+	principal := new(acl.Identity)
+	principal.ParseString(args.Input.Principal)
+	if !principal.Type.IsValid() {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "principal", "unsupported principal type")
+	}
+
+	resource := new(acl.Identity)
+	resource.ParseString(args.Input.Resource)
+	if !resource.Type.IsValid() {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "resource", "unsupported resource type")
+	}
+
+	action := acl.Action(args.Input.Action)
+	if !action.IsValid() {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "action", "unsupported type")
+	}
+
+	a, err := r.getWritableACL(ctx, codebases.ID(args.Input.CodebaseID))
+	if err != nil {
+		return nil, err
+	}
+
+	policy := a.Policy
+	if args.Input.Policy != nil {
+		if policy, err = parsePolicy(*args.Input.Policy); err != nil {
+			return nil, err
+		}
+	}
+
+	return &explanationResolver{explanation: policy.Explain(*principal, action, *resource)}, nil
+}
+
+func (r *ACLRootResolver) ValidateACL(ctx context.Context, args resolvers.ValidateACLArgs) (resolvers.ACLValidationResolver, error) {
+	a, err := r.getWritableACL(ctx, codebases.ID(args.Input.CodebaseID))
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := parsePolicy(args.Input.Policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &validationResolver{policy: policy, errs: policy.Errors(string(a.ID))}, nil
+}
+
+type explanationResolver struct {
+	explanation *acl.Explanation
+}
+
+func (r *explanationResolver) Allowed() bool {
+	return r.explanation.Allowed
+}
+
+func (r *explanationResolver) MatchedRules() []resolvers.ACLRuleResolver {
+	res := make([]resolvers.ACLRuleResolver, 0, len(r.explanation.Rules))
+	for _, rule := range r.explanation.Rules {
+		res = append(res, &ruleResolver{rule: rule})
+	}
+	return res
+}
+
+func (r *explanationResolver) MatchedGroups() []string {
+	res := make([]string, 0, len(r.explanation.Groups))
+	for _, group := range r.explanation.Groups {
+		res = append(res, group.ID)
+	}
+	return res
+}
+
+type ruleResolver struct {
+	rule *acl.Rule
+}
+
+func (r *ruleResolver) ID() string {
+	return r.rule.ID
+}
+
+func (r *ruleResolver) Effect() string {
+	if r.rule.Effect == "" {
+		return string(acl.EffectAllow)
+	}
+	return string(r.rule.Effect)
+}
+
+func (r *ruleResolver) Action() string {
+	return string(r.rule.Action)
+}
+
+type validationResolver struct {
+	policy acl.Policy
+	errs   map[string]error
+}
+
+func (r *validationResolver) Valid() bool {
+	return len(r.errs) == 0
+}
+
+func (r *validationResolver) Errors() []resolvers.ACLPolicyErrorResolver {
+	paths := make([]string, 0, len(r.errs))
+	for path := range r.errs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	res := make([]resolvers.ACLPolicyErrorResolver, 0, len(paths))
+	for _, path := range paths {
+		res = append(res, &policyErrorResolver{path: path, err: r.errs[path]})
+	}
+	return res
+}
+
+func (r *validationResolver) Tests() []resolvers.ACLTestResultResolver {
+	res := make([]resolvers.ACLTestResultResolver, 0, len(r.policy.Tests))
+	for _, test := range r.policy.Tests {
+		res = append(res, &testResultResolver{test: test, passed: test.Passes(r.policy)})
+	}
+	return res
+}
+
+type policyErrorResolver struct {
+	path string
+	err  error
+}
+
+func (r *policyErrorResolver) Path() string {
+	return r.path
+}
+
+func (r *policyErrorResolver) Message() string {
+	return r.err.Error()
+}
+
+type testResultResolver struct {
+	test   *acl.Test
+	passed bool
+}
+
+func (r *testResultResolver) ID() string {
+	return r.test.ID
+}
+
+func (r *testResultResolver) Passed() bool {
+	return r.passed
+}
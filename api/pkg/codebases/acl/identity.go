@@ -9,11 +9,13 @@ import (
 type identityType string
 
 var supportedIdentityTypes = map[identityType]bool{
-	Users:     true,
-	Groups:    true,
-	Codebases: true,
-	ACLs:      true,
-	Files:     true,
+	Users:        true,
+	Groups:       true,
+	Codebases:    true,
+	ACLs:         true,
+	Files:        true,
+	Workspaces:   true,
+	Integrations: true,
 }
 
 func (it identityType) IsValid() bool {
@@ -26,6 +28,10 @@ const (
 	Groups    identityType = "groups"
 	ACLs      identityType = "acls"
 	Files     identityType = "files"
+	// Workspaces are identified by workspace id.
+	Workspaces identityType = "workspaces"
+	// Integrations are identified by provider name, for example "buildkite", "github", "remote" or "webhooks".
+	Integrations identityType = "integrations"
 )
 
 type Identity struct {
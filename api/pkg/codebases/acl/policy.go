@@ -2,6 +2,8 @@ package acl
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 )
 
 type Policy struct {
@@ -17,22 +19,32 @@ type Policy struct {
 //   files := List(Identity{Type: Users, ID: "user1"}, ActionWrite, Files)
 //
 // will return a list of file patterns the user1 can write to.
+//
+// Rules for actions that imply _action_ are included, so listing ActionRead also returns the patterns that
+// _principal_ can write to.
+//
+// Patterns from deny rules are prefixed with "!". The patterns are ordered from the least to the most specific, so
+// that the last matching pattern decides if a resource is allowed, same as in unidiff.Allower.
 func (p Policy) List(principal Identity, action Action, typ identityType) []string {
-	allowedPatterns := []string{}
+	return p.ListAny([]Identity{principal}, action, typ)
+}
+
+// ListAny is like List, for a principal that is known by more than one identity, for example both by the id and the
+// email of a user. Rules that match any of the identities are included, and ordered together.
+func (p Policy) ListAny(principals []Identity, action Action, typ identityType) []string {
+	type entry struct {
+		pattern     string
+		specificity int
+		deny        bool
+	}
+
+	entries := []entry{}
 	for _, rule := range p.Rules {
-		if rule.Action != action {
+		if !rule.appliesTo(action) {
 			continue
 		}
 
-		principalFound := false
-		for _, p := range resolveGroups(rule.Principals, p.Groups) {
-			if p.Matches(principal) {
-				principalFound = true
-				break
-			}
-		}
-
-		if !principalFound {
+		if !rule.assertAnyPrincipal(principals, p.Groups) {
 			continue
 		}
 
@@ -40,27 +52,125 @@ func (p Policy) List(principal Identity, action Action, typ identityType) []stri
 			if resource.Type != typ {
 				continue
 			}
-			allowedPatterns = append(allowedPatterns, resource.Pattern)
+			deny := rule.Effect == EffectDeny
+			pattern := resource.Pattern
+			if deny {
+				pattern = "!" + pattern
+			}
+			entries = append(entries, entry{
+				pattern:     pattern,
+				specificity: specificity(resource.Pattern),
+				deny:        deny,
+			})
+		}
+	}
+
+	// deny patterns go after allow patterns with the same specificity, so that they take precedence
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].specificity != entries[j].specificity {
+			return entries[i].specificity < entries[j].specificity
 		}
+		return !entries[i].deny && entries[j].deny
+	})
+
+	patterns := make([]string, 0, len(entries))
+	for _, e := range entries {
+		patterns = append(patterns, e.pattern)
 	}
-	return allowedPatterns
+	return patterns
 }
 
+// Assert returns true if the most specific rule that matches _principal_, _action_ and _resource_ is an allow rule.
+// Deny rules take precedence over allow rules with the same specificity.
 func (p Policy) Assert(principal Identity, action Action, resource Identity) bool {
-	for _, acl := range p.Rules {
-		if acl.Assert(principal, action, resource, p.Groups) {
+	_, allowed := p.decide([]Identity{principal}, action, resource)
+	return allowed
+}
+
+// Allows returns true if _principal_ can _action_ on _resource_.
+//
+// Unlike Assert, it takes into account that opt-in actions are allowed for everyone until the policy contains a rule
+// that allows that action.
+func (p Policy) Allows(principal Identity, action Action, resource Identity) bool {
+	return p.AllowsAny([]Identity{principal}, action, resource)
+}
+
+// AllowsAny is like Allows, for a principal that is known by more than one identity. The most specific rule that
+// matches any of the identities decides, so a rule that denies the email of a user can not be overridden by a less
+// specific rule that allows the id of the same user.
+func (p Policy) AllowsAny(principals []Identity, action Action, resource Identity) bool {
+	if matched, allowed := p.decide(principals, action, resource); matched {
+		return allowed
+	}
+	return action.isOptIn() && !p.restricts(action)
+}
+
+// decide finds the most specific rule that matches. matched is false if no rule matches.
+func (p Policy) decide(principals []Identity, action Action, resource Identity) (matched, allowed bool) {
+	bestSpecificity := -1
+	for _, rule := range p.Rules {
+		if !rule.appliesTo(action) {
+			continue
+		}
+		if !rule.assertAnyPrincipal(principals, p.Groups) {
+			continue
+		}
+		for _, r := range resolveGroups(rule.Resources, p.Groups) {
+			if !r.Matches(resource) {
+				continue
+			}
+			deny := rule.Effect == EffectDeny
+			spec := specificity(r.Pattern)
+			switch {
+			case spec > bestSpecificity:
+				bestSpecificity = spec
+				allowed = !deny
+			case spec == bestSpecificity && deny:
+				allowed = false
+			}
+			matched = true
+		}
+	}
+	return matched, allowed
+}
+
+// restricts returns true if the policy contains at least one rule that allows _action_.
+func (p Policy) restricts(action Action) bool {
+	for _, rule := range p.Rules {
+		if rule.Action == action && rule.Effect != EffectDeny {
 			return true
 		}
 	}
 	return false
 }
 
+// specificity returns how specific a pattern is. Patterns with more literal characters are more specific, and a
+// pattern without wildcards is more specific than a pattern with the same literal characters and a wildcard.
+func specificity(pattern string) int {
+	pattern = strings.TrimPrefix(pattern, "!")
+	literals := 0
+	for _, r := range pattern {
+		if r != '*' && r != '?' {
+			literals++
+		}
+	}
+	if strings.ContainsAny(pattern, "*?") {
+		return literals * 2
+	}
+	return literals*2 + 1
+}
+
 var (
 	ErrTestFails               = fmt.Errorf("test fails")
 	ErrSubgroupsForbidden      = fmt.Errorf("groups can't have other groups as memebers")
 	ErrUnsupportedIdentityType = fmt.Errorf("unsupported identity type")
 	ErrTestMustHaveCondition   = fmt.Errorf("test must have either 'allow' or 'deny' condition")
 	ErrUnsupportedActionType   = fmt.Errorf("unsupported action type")
+	ErrUnsupportedEffect       = fmt.Errorf("unsupported effect, must be either 'allow' or 'deny'")
+	ErrNegatedDenyPattern      = fmt.Errorf("deny rules can't have negated patterns")
+	ErrConflictingRules        = func(id string) error {
+		return fmt.Errorf("allows and denies the same principals and resources as rule '%s'", id)
+	}
 	ErrACLTestMissing          = func(id string) error {
 		return fmt.Errorf("at least one 'allow write' test must exist for 'acls::%s' resource", id)
 	}
@@ -77,21 +187,13 @@ func (p Policy) Errors(aclID string) map[string]error {
 			errs[fmt.Sprintf("tests[\"%s\"]", test.ID)] = ErrTestMustHaveCondition
 		}
 
-		if test.Resource.Type == ACLs && test.Allow != nil && test.Resource.ID == aclID {
+		if test.Resource.Type == ACLs && test.Allow != nil && *test.Allow == ActionWrite && test.Resource.ID == aclID {
 			aclTest = test
 		}
 
 		// tests must pass
-		if test.Allow != nil && *test.Allow == ActionWrite {
-			if !p.Assert(test.Principal, *test.Allow, test.Resource) {
-				errs[fmt.Sprintf("tests[\"%s\"]", test.ID)] = ErrTestFails
-			}
-		}
-
-		if test.Deny != nil {
-			if p.Assert(test.Principal, *test.Deny, test.Resource) {
-				errs[fmt.Sprintf("tests[\"%s\"]", test.ID)] = ErrTestFails
-			}
+		if !test.Passes(p) {
+			errs[fmt.Sprintf("tests[\"%s\"]", test.ID)] = ErrTestFails
 		}
 	}
 
@@ -131,6 +233,28 @@ func (p Policy) Errors(aclID string) map[string]error {
 		if !rule.Action.IsValid() {
 			errs[fmt.Sprintf("rules[\"%s\"].action", rule.ID)] = ErrUnsupportedActionType
 		}
+
+		if !rule.Effect.IsValid() {
+			errs[fmt.Sprintf("rules[\"%s\"].effect", rule.ID)] = ErrUnsupportedEffect
+		}
+
+		if rule.Effect == EffectDeny {
+			for _, r := range rule.Resources {
+				if strings.HasPrefix(r.Pattern, "!") {
+					bytes, _ := r.MarshalJSON()
+					errs[fmt.Sprintf("rules[\"%s\"].resources[%s]", rule.ID, string(bytes))] = ErrNegatedDenyPattern
+				}
+			}
+		}
+	}
+
+	// rules can't both allow and deny the same thing, that is most likely a mistake
+	for i, rule := range p.Rules {
+		for _, other := range p.Rules[i+1:] {
+			if rule.conflictsWith(other) {
+				errs[fmt.Sprintf("rules[\"%s\"]", other.ID)] = ErrConflictingRules(rule.ID)
+			}
+		}
 	}
 
 	return errs
@@ -149,6 +273,23 @@ type Test struct {
 	Resource  Identity `json:"resource"`
 }
 
+// Passes returns true if the test conditions hold for the policy _p_.
+func (t *Test) Passes(p Policy) bool {
+	if t.Allow != nil && t.Allow.IsValid() {
+		if !p.Allows(t.Principal, *t.Allow, t.Resource) {
+			return false
+		}
+	}
+
+	if t.Deny != nil {
+		if p.Allows(t.Principal, *t.Deny, t.Resource) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func resolveGroups(identifiers []*Identifier, groups []*Group) []*Identifier {
 	resolved := make([]*Identifier, 0, len(identifiers))
 	for _, i := range identifiers {
@@ -167,18 +308,50 @@ func resolveGroups(identifiers []*Identifier, groups []*Group) []*Identifier {
 
 type Rule struct {
 	ID         string        `json:"id,omitempty"`
+	Effect     Effect        `json:"effect,omitempty"`
 	Action     Action        `json:"action,omitempty"`
 	Principals []*Identifier `json:"principals,omitempty"`
 	Resources  []*Identifier `json:"resources,omitempty"`
 }
 
+// Assert returns true if the rule matches _principal_, _action_ and _resource_, regardless of its effect.
 func (a *Rule) Assert(principal Identity, action Action, resource Identity, groups []*Group) bool {
-	if a.Action != action {
+	if !a.appliesTo(action) {
 		return false
 	}
 	return a.assertPrincipal(principal, groups) && a.assertResource(resource, groups)
 }
 
+// appliesTo returns true if the rule has an effect on _action_. Allow rules apply to the actions that their action
+// implies, and deny rules apply to the actions that imply their action. For example, a rule that denies read also
+// denies write.
+func (a *Rule) appliesTo(action Action) bool {
+	if a.Effect == EffectDeny {
+		return action.Implies(a.Action)
+	}
+	return a.Action.Implies(action)
+}
+
+// conflictsWith returns true if one of the rules allows, and the other denies, the same action for the same principal
+// and resource patterns.
+func (a *Rule) conflictsWith(other *Rule) bool {
+	if (a.Effect == EffectDeny) == (other.Effect == EffectDeny) || a.Action != other.Action {
+		return false
+	}
+	return overlaps(a.Principals, other.Principals) && overlaps(a.Resources, other.Resources)
+}
+
+func overlaps(a, b []*Identifier) bool {
+	for _, i := range a {
+		for _, j := range b {
+			if i.Type == j.Type && i.Pattern == j.Pattern {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (a *Rule) assertPrincipal(principal Identity, groups []*Group) bool {
 	for _, p := range resolveGroups(a.Principals, groups) {
 		if p.Matches(principal) {
@@ -188,6 +361,15 @@ func (a *Rule) assertPrincipal(principal Identity, groups []*Group) bool {
 	return false
 }
 
+func (a *Rule) assertAnyPrincipal(principals []Identity, groups []*Group) bool {
+	for _, principal := range principals {
+		if a.assertPrincipal(principal, groups) {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *Rule) assertResource(resource Identity, groups []*Group) bool {
 	for _, r := range resolveGroups(a.Resources, groups) {
 		if r.Matches(resource) {
@@ -197,14 +379,75 @@ func (a *Rule) assertResource(resource Identity, groups []*Group) bool {
 	return false
 }
 
+type Effect string
+
+const (
+	// EffectAllow is the default effect of a rule.
+	EffectAllow Effect = "allow"
+	EffectDeny  Effect = "deny"
+)
+
+func (e Effect) IsValid() bool {
+	return e == "" || e == EffectAllow || e == EffectDeny
+}
+
 type Action string
 
-var supportedActions = map[Action]bool{ActionWrite: true}
+var supportedActions = map[Action]bool{
+	ActionRead:               true,
+	ActionWrite:              true,
+	ActionLand:               true,
+	ActionReview:             true,
+	ActionAdmin:              true,
+	ActionManageIntegrations: true,
+}
 
 func (a Action) IsValid() bool {
 	return supportedActions[a]
 }
 
+// optInActions are allowed for everyone with access to the codebase, until the policy contains a rule for the action.
+// This way, existing policies keep working as they did before the actions were introduced.
+var optInActions = map[Action]bool{
+	ActionLand:               true,
+	ActionReview:             true,
+	ActionAdmin:              true,
+	ActionManageIntegrations: true,
+}
+
+func (a Action) isOptIn() bool {
+	return optInActions[a]
+}
+
+// impliedActions contains the actions that are allowed by a rule for another action.
+var impliedActions = map[Action][]Action{
+	ActionWrite: {ActionRead},
+	ActionAdmin: {ActionLand, ActionReview, ActionManageIntegrations},
+}
+
+// Implies returns true if a rule for _a_ allows _action_.
+func (a Action) Implies(action Action) bool {
+	if a == action {
+		return true
+	}
+	for _, implied := range impliedActions[a] {
+		if implied == action {
+			return true
+		}
+	}
+	return false
+}
+
 const (
+	ActionRead  Action = "read"
 	ActionWrite Action = "write"
+	// ActionLand allows landing changes from workspaces.
+	ActionLand Action = "land"
+	// ActionReview allows reviewing workspaces.
+	ActionReview Action = "review"
+	// ActionAdmin allows administrating the codebase, for example archiving other users' workspaces or creating
+	// service tokens. It implies all of land, review and manage_integrations.
+	ActionAdmin Action = "admin"
+	// ActionManageIntegrations allows configuring integrations, remotes and webhooks.
+	ActionManageIntegrations Action = "manage_integrations"
 )
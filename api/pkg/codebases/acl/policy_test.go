@@ -203,6 +203,318 @@ func Test_Policy_group_match(t *testing.T) {
 	assert.True(t, isAllowed)
 }
 
+func Test_Policy_write_implies_read(t *testing.T) {
+	p := Policy{
+		Rules: []*Rule{
+			{
+				ID:     "user-1 can write all files",
+				Action: ActionWrite,
+				Principals: []*Identifier{
+					{Type: Users, Pattern: "user-1"},
+				},
+				Resources: []*Identifier{
+					{Type: Files, Pattern: "*"},
+				},
+			},
+			{
+				ID:     "user-2 can read all files",
+				Action: ActionRead,
+				Principals: []*Identifier{
+					{Type: Users, Pattern: "user-2"},
+				},
+				Resources: []*Identifier{
+					{Type: Files, Pattern: "*"},
+				},
+			},
+		},
+	}
+
+	assert.True(t, p.Assert(Identity{Type: Users, ID: "user-1"}, ActionRead, Identity{Type: Files, ID: "README.md"}))
+	assert.True(t, p.Assert(Identity{Type: Users, ID: "user-1"}, ActionWrite, Identity{Type: Files, ID: "README.md"}))
+	assert.True(t, p.Assert(Identity{Type: Users, ID: "user-2"}, ActionRead, Identity{Type: Files, ID: "README.md"}))
+	assert.False(t, p.Assert(Identity{Type: Users, ID: "user-2"}, ActionWrite, Identity{Type: Files, ID: "README.md"}))
+}
+
+func Test_Policy_List_read(t *testing.T) {
+	p := Policy{
+		Rules: []*Rule{
+			{
+				ID:     "everyone can write src",
+				Action: ActionWrite,
+				Principals: []*Identifier{
+					{Type: Users, Pattern: "*"},
+				},
+				Resources: []*Identifier{
+					{Type: Files, Pattern: "src/**"},
+				},
+			},
+			{
+				ID:     "user-1 can read secrets",
+				Action: ActionRead,
+				Principals: []*Identifier{
+					{Type: Users, Pattern: "user-1"},
+				},
+				Resources: []*Identifier{
+					{Type: Files, Pattern: "secrets/**"},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []string{"src/**", "secrets/**"}, p.List(Identity{Type: Users, ID: "user-1"}, ActionRead, Files))
+	assert.Equal(t, []string{"src/**"}, p.List(Identity{Type: Users, ID: "user-1"}, ActionWrite, Files))
+	assert.Equal(t, []string{"src/**"}, p.List(Identity{Type: Users, ID: "user-2"}, ActionRead, Files))
+}
+
+func Test_Policy_deny_precedence(t *testing.T) {
+	p := Policy{
+		Rules: []*Rule{
+			{
+				ID:     "everyone can write everything",
+				Action: ActionWrite,
+				Principals: []*Identifier{
+					{Type: Users, Pattern: "*"},
+				},
+				Resources: []*Identifier{
+					{Type: Files, Pattern: "*"},
+				},
+			},
+			{
+				ID:     "nobody can write infra",
+				Effect: EffectDeny,
+				Action: ActionWrite,
+				Principals: []*Identifier{
+					{Type: Users, Pattern: "*"},
+				},
+				Resources: []*Identifier{
+					{Type: Files, Pattern: "infra/**"},
+				},
+			},
+			{
+				ID:     "user-1 can write infra/user-1.tf",
+				Action: ActionWrite,
+				Principals: []*Identifier{
+					{Type: Users, Pattern: "user-1"},
+				},
+				Resources: []*Identifier{
+					{Type: Files, Pattern: "infra/user-1.tf"},
+				},
+			},
+		},
+	}
+
+	user1 := Identity{Type: Users, ID: "user-1"}
+	user2 := Identity{Type: Users, ID: "user-2"}
+
+	assert.True(t, p.Assert(user2, ActionWrite, Identity{Type: Files, ID: "README.md"}))
+	assert.False(t, p.Assert(user2, ActionWrite, Identity{Type: Files, ID: "infra/main.tf"}))
+	assert.True(t, p.Assert(user2, ActionRead, Identity{Type: Files, ID: "infra/main.tf"}), "deny write does not deny read")
+	assert.False(t, p.Allows(user1, ActionWrite, Identity{Type: Files, ID: "infra/main.tf"}))
+	assert.True(t, p.Allows(user1, ActionWrite, Identity{Type: Files, ID: "infra/user-1.tf"}), "more specific allow wins")
+
+	assert.Equal(t, []string{"*", "!infra/**", "infra/user-1.tf"}, p.List(user1, ActionWrite, Files))
+	assert.Equal(t, []string{"*", "!infra/**"}, p.List(user2, ActionWrite, Files))
+	assert.Equal(t, []string{"*"}, p.List(user2, ActionRead, Files))
+}
+
+func Test_Policy_deny_wins_at_equal_specificity(t *testing.T) {
+	p := Policy{
+		Rules: []*Rule{
+			{
+				ID:     "nobody can read secrets",
+				Effect: EffectDeny,
+				Action: ActionRead,
+				Principals: []*Identifier{
+					{Type: Users, Pattern: "*"},
+				},
+				Resources: []*Identifier{
+					{Type: Files, Pattern: "secrets/**"},
+				},
+			},
+			{
+				ID:     "user-1 can write secrets",
+				Action: ActionWrite,
+				Principals: []*Identifier{
+					{Type: Users, Pattern: "user-1"},
+				},
+				Resources: []*Identifier{
+					{Type: Files, Pattern: "secrets/**"},
+				},
+			},
+		},
+	}
+
+	user1 := Identity{Type: Users, ID: "user-1"}
+	secret := Identity{Type: Files, ID: "secrets/key.pem"}
+
+	assert.False(t, p.Assert(user1, ActionRead, secret))
+	assert.False(t, p.Assert(user1, ActionWrite, secret), "deny read also denies write")
+	assert.Equal(t, []string{"secrets/**", "!secrets/**"}, p.List(user1, ActionWrite, Files))
+}
+
+func Test_Policy_deny_opt_in_action(t *testing.T) {
+	p := Policy{
+		Rules: []*Rule{
+			{
+				ID:     "user-1 can not land",
+				Effect: EffectDeny,
+				Action: ActionLand,
+				Principals: []*Identifier{
+					{Type: Users, Pattern: "user-1"},
+				},
+				Resources: []*Identifier{
+					{Type: Workspaces, Pattern: "*"},
+				},
+			},
+		},
+	}
+
+	workspace := Identity{Type: Workspaces, ID: "workspace-1"}
+	assert.False(t, p.Allows(Identity{Type: Users, ID: "user-1"}, ActionLand, workspace))
+	assert.False(t, p.Allows(Identity{Type: Users, ID: "user-1"}, ActionAdmin, workspace), "deny land also denies admin")
+	assert.True(t, p.Allows(Identity{Type: Users, ID: "user-2"}, ActionLand, workspace), "deny rules do not opt in")
+}
+
+func Test_Policy_Errors_conflicting_rules(t *testing.T) {
+	p := Policy{
+		Rules: []*Rule{
+			adminsCanWriteACLsRule,
+			{
+				ID:     "admins can write docs",
+				Action: ActionWrite,
+				Principals: []*Identifier{
+					{Type: Groups, Pattern: "admins"},
+				},
+				Resources: []*Identifier{
+					{Type: Files, Pattern: "docs/**"},
+				},
+			},
+			{
+				ID:     "admins can not write docs",
+				Effect: EffectDeny,
+				Action: ActionWrite,
+				Principals: []*Identifier{
+					{Type: Groups, Pattern: "admins"},
+				},
+				Resources: []*Identifier{
+					{Type: Files, Pattern: "docs/**"},
+				},
+			},
+			{
+				ID:     "unknown effect",
+				Effect: "maybe",
+				Action: ActionRead,
+				Principals: []*Identifier{
+					{Type: Users, Pattern: "*"},
+				},
+				Resources: []*Identifier{
+					{Type: Files, Pattern: "*"},
+				},
+			},
+		},
+		Groups: []*Group{adminsGroup},
+		Tests:  []*Test{adminsCanWriteACLsTest},
+	}
+
+	errs := p.Errors(aclID)
+	if assert.Len(t, errs, 2) {
+		assert.Equal(t, ErrConflictingRules("admins can write docs"), errs[`rules["admins can not write docs"]`])
+		assert.ErrorIs(t, errs[`rules["unknown effect"].effect`], ErrUnsupportedEffect)
+	}
+}
+
+func Test_Policy_Allows_opt_in_actions(t *testing.T) {
+	user := Identity{Type: Users, ID: "user-1"}
+	workspace := Identity{Type: Workspaces, ID: "workspace-1"}
+
+	unrestricted := Policy{}
+	assert.True(t, unrestricted.Allows(user, ActionLand, workspace))
+	assert.True(t, unrestricted.Allows(user, ActionAdmin, workspace))
+	assert.False(t, unrestricted.Allows(user, ActionWrite, Identity{Type: Files, ID: "README.md"}))
+
+	restricted := Policy{
+		Rules: []*Rule{
+			{
+				ID:     "maintainers can land",
+				Action: ActionLand,
+				Principals: []*Identifier{
+					{Type: Groups, Pattern: "maintainers"},
+				},
+				Resources: []*Identifier{
+					{Type: Workspaces, Pattern: "*"},
+				},
+			},
+			{
+				ID:     "user-2 can administrate",
+				Action: ActionAdmin,
+				Principals: []*Identifier{
+					{Type: Users, Pattern: "user-2"},
+				},
+				Resources: []*Identifier{
+					{Type: Workspaces, Pattern: "*"},
+				},
+			},
+		},
+		Groups: []*Group{
+			{
+				ID: "maintainers",
+				Members: []*Identifier{
+					{Type: Users, Pattern: "user-3"},
+				},
+			},
+		},
+	}
+	assert.False(t, restricted.Allows(user, ActionLand, workspace))
+	assert.False(t, restricted.Allows(user, ActionAdmin, workspace))
+	assert.True(t, restricted.Allows(user, ActionReview, workspace))
+	assert.True(t, restricted.Allows(Identity{Type: Users, ID: "user-2"}, ActionLand, workspace), "admin implies land")
+	assert.True(t, restricted.Allows(Identity{Type: Users, ID: "user-3"}, ActionLand, workspace))
+	assert.False(t, restricted.Allows(Identity{Type: Users, ID: "user-3"}, ActionAdmin, workspace))
+}
+
+func Test_Policy_Errors_opt_in_action_tests(t *testing.T) {
+	actionLand := ActionLand
+	p := Policy{
+		Rules: []*Rule{
+			adminsCanWriteACLsRule,
+			{
+				ID:     "admins can land",
+				Action: ActionLand,
+				Principals: []*Identifier{
+					{Type: Groups, Pattern: "admins"},
+				},
+				Resources: []*Identifier{
+					{Type: Workspaces, Pattern: "*"},
+				},
+			},
+		},
+		Groups: []*Group{adminsGroup},
+		Tests: []*Test{
+			adminsCanWriteACLsTest,
+			{
+				ID:        "user-1 can land",
+				Principal: Identity{Type: Users, ID: "user-1"},
+				Allow:     &actionLand,
+				Resource:  Identity{Type: Workspaces, ID: "workspace-1"},
+			},
+			{
+				ID:        "user-2 can not land",
+				Principal: Identity{Type: Users, ID: "user-2"},
+				Deny:      &actionLand,
+				Resource:  Identity{Type: Workspaces, ID: "workspace-1"},
+			},
+		},
+	}
+
+	assert.Len(t, p.Errors(aclID), 0)
+
+	p.Rules = p.Rules[:1]
+	errs := p.Errors(aclID)
+	if assert.Len(t, errs, 1) {
+		assert.ErrorIs(t, errs[`tests["user-2 can not land"]`], ErrTestFails)
+	}
+}
+
 func Test_Policy_Errors_all_good(t *testing.T) {
 	actionWrite := ActionWrite
 	p := Policy{
@@ -398,3 +710,49 @@ func Test_Policy_Errors_test_group_contains_invalid_resource(t *testing.T) {
 		assert.ErrorIs(t, errs["groups[\"test\"].members[\"invalid\"]"], ErrUnsupportedIdentityType)
 	}
 }
+
+func Test_Policy_multiple_identities(t *testing.T) {
+	p := Policy{
+		Rules: []*Rule{
+			{
+				ID:     "everyone can write everything",
+				Action: ActionWrite,
+				Principals: []*Identifier{
+					{Type: Users, Pattern: "*"},
+				},
+				Resources: []*Identifier{
+					{Type: Files, Pattern: "*"},
+				},
+			},
+			{
+				ID:     "alice can not read infra",
+				Effect: EffectDeny,
+				Action: ActionRead,
+				Principals: []*Identifier{
+					{Type: Users, Pattern: "alice@getsturdy.com"},
+				},
+				Resources: []*Identifier{
+					{Type: Files, Pattern: "infra/**"},
+				},
+			},
+		},
+	}
+
+	alice := []Identity{
+		{Type: Users, ID: "alice@getsturdy.com"},
+		{Type: Users, ID: "c0ffee"},
+	}
+	infra := Identity{Type: Files, ID: "infra/main.tf"}
+
+	// rules for either identity are ordered together, so the deny rule for the email is last
+	assert.Equal(t, []string{"*", "!infra/**"}, p.ListAny(alice, ActionRead, Files))
+	assert.Equal(t, []string{"*", "!infra/**"}, p.ListAny(alice, ActionWrite, Files))
+
+	assert.False(t, p.AllowsAny(alice, ActionRead, infra))
+	assert.False(t, p.AllowsAny(alice, ActionWrite, infra))
+	assert.True(t, p.AllowsAny(alice, ActionRead, Identity{Type: Files, ID: "README.md"}))
+
+	// each identity on its own
+	assert.False(t, p.Allows(alice[0], ActionRead, infra))
+	assert.True(t, p.Allows(alice[1], ActionRead, infra))
+}
@@ -32,9 +32,11 @@ type Codebase struct {
 	ArchivedAt      *time.Time      `db:"archived_at" json:"archived_at"`
 	OrganizationID  *string         `db:"organization_id"`
 
-	IsReady              bool `json:"is_ready" db:"is_ready"`
-	IsPublic             bool `json:"is_public" db:"is_public"`
-	RequireHealthyStatus bool `json:"-" db:"require_healthy_status"`
+	IsReady                   bool `json:"is_ready" db:"is_ready"`
+	IsPublic                  bool `json:"is_public" db:"is_public"`
+	RequireHealthyStatus      bool `json:"-" db:"require_healthy_status"`
+	MergeQueueEnabled         bool `json:"-" db:"merge_queue_enabled"`
+	RequireCodeOwnersApproval bool `json:"-" db:"require_code_owners_approval"`
 
 	// Use through ChangeService.HeadChange()
 	CalculatedHeadChangeID bool    `json:"-" db:"calculated_head_change_id"`
@@ -20,8 +20,8 @@ func NewRepo(db *sqlx.DB) CodebaseRepository {
 }
 
 func (r *Repo) Create(entity codebases.Codebase) error {
-	_, err := r.db.NamedExec(`INSERT INTO codebases (id, short_id, name, description, emoji, created_at, invite_code, is_ready, is_public, organization_id, calculated_head_change_id, cached_head_change_id, require_healthy_status)
-		VALUES (:id, :short_id, :name, :description, :emoji, :created_at, :invite_code, :is_ready, :is_public, :organization_id, :calculated_head_change_id, :cached_head_change_id, :require_healthy_status)`, &entity)
+	_, err := r.db.NamedExec(`INSERT INTO codebases (id, short_id, name, description, emoji, created_at, invite_code, is_ready, is_public, organization_id, calculated_head_change_id, cached_head_change_id, require_healthy_status, merge_queue_enabled, require_code_owners_approval)
+		VALUES (:id, :short_id, :name, :description, :emoji, :created_at, :invite_code, :is_ready, :is_public, :organization_id, :calculated_head_change_id, :cached_head_change_id, :require_healthy_status, :merge_queue_enabled, :require_code_owners_approval)`, &entity)
 	if err != nil {
 		return fmt.Errorf("failed to create codebase: %w", err)
 	}
@@ -30,7 +30,7 @@ func (r *Repo) Create(entity codebases.Codebase) error {
 
 func (r *Repo) Get(id codebases.ID) (*codebases.Codebase, error) {
 	entity := &codebases.Codebase{}
-	err := r.db.Get(entity, `SELECT id, short_id, name, description, emoji, created_at, invite_code, is_ready, archived_at, is_public, organization_id, calculated_head_change_id, cached_head_change_id, require_healthy_status
+	err := r.db.Get(entity, `SELECT id, short_id, name, description, emoji, created_at, invite_code, is_ready, archived_at, is_public, organization_id, calculated_head_change_id, cached_head_change_id, require_healthy_status, merge_queue_enabled, require_code_owners_approval
 		FROM codebases
 		WHERE id = $1
 		AND archived_at IS NULL`, id)
@@ -42,7 +42,7 @@ func (r *Repo) Get(id codebases.ID) (*codebases.Codebase, error) {
 
 func (r *Repo) GetAllowArchived(id codebases.ID) (*codebases.Codebase, error) {
 	entity := &codebases.Codebase{}
-	err := r.db.Get(entity, `SELECT id, short_id, name, description, emoji, created_at, invite_code, is_ready, archived_at, is_public, organization_id, calculated_head_change_id, cached_head_change_id, require_healthy_status
+	err := r.db.Get(entity, `SELECT id, short_id, name, description, emoji, created_at, invite_code, is_ready, archived_at, is_public, organization_id, calculated_head_change_id, cached_head_change_id, require_healthy_status, merge_queue_enabled, require_code_owners_approval
 		FROM codebases
 		WHERE id = $1`, id)
 	if err != nil {
@@ -53,7 +53,7 @@ func (r *Repo) GetAllowArchived(id codebases.ID) (*codebases.Codebase, error) {
 
 func (r *Repo) GetByInviteCode(inviteCode string) (*codebases.Codebase, error) {
 	entity := &codebases.Codebase{}
-	err := r.db.Get(entity, `SELECT id, short_id, name, description, emoji, created_at, invite_code, is_ready, archived_at, is_public, organization_id, calculated_head_change_id, cached_head_change_id, require_healthy_status
+	err := r.db.Get(entity, `SELECT id, short_id, name, description, emoji, created_at, invite_code, is_ready, archived_at, is_public, organization_id, calculated_head_change_id, cached_head_change_id, require_healthy_status, merge_queue_enabled, require_code_owners_approval
 		FROM codebases
 		WHERE invite_code = $1
 	    AND archived_at IS NULL`, inviteCode)
@@ -65,7 +65,7 @@ func (r *Repo) GetByInviteCode(inviteCode string) (*codebases.Codebase, error) {
 
 func (r *Repo) GetByShortID(shortID codebases.ShortCodebaseID) (*codebases.Codebase, error) {
 	entity := &codebases.Codebase{}
-	err := r.db.Get(entity, `SELECT id, short_id, name, description, emoji, created_at, invite_code, is_ready, archived_at, is_public, organization_id, calculated_head_change_id, cached_head_change_id, require_healthy_status
+	err := r.db.Get(entity, `SELECT id, short_id, name, description, emoji, created_at, invite_code, is_ready, archived_at, is_public, organization_id, calculated_head_change_id, cached_head_change_id, require_healthy_status, merge_queue_enabled, require_code_owners_approval
 		FROM codebases
 		WHERE short_id = $1
 	    AND archived_at IS NULL`, shortID)
@@ -88,7 +88,9 @@ func (r *Repo) Update(entity *codebases.Codebase) error {
 		    organization_id = :organization_id,
 			calculated_head_change_id = :calculated_head_change_id,
 			cached_head_change_id = :cached_head_change_id,
-			require_healthy_status = :require_healthy_status
+			require_healthy_status = :require_healthy_status,
+			merge_queue_enabled = :merge_queue_enabled,
+			require_code_owners_approval = :require_code_owners_approval
 		WHERE id = :id`, &entity)
 	if err != nil {
 		return fmt.Errorf("failed to perform update: %w", err)
@@ -99,7 +101,7 @@ func (r *Repo) Update(entity *codebases.Codebase) error {
 func (r *Repo) ListByOrganization(ctx context.Context, organizationID string) ([]*codebases.Codebase, error) {
 	var res []*codebases.Codebase
 	err := r.db.SelectContext(ctx, &res, `
-		SELECT id, short_id, name, description, emoji, created_at, invite_code, is_ready, archived_at, is_public, organization_id, calculated_head_change_id, cached_head_change_id, require_healthy_status
+		SELECT id, short_id, name, description, emoji, created_at, invite_code, is_ready, archived_at, is_public, organization_id, calculated_head_change_id, cached_head_change_id, require_healthy_status, merge_queue_enabled, require_code_owners_approval
 		FROM codebases
 		WHERE organization_id = $1
 	    AND archived_at IS NULL`, organizationID)
@@ -118,3 +120,10 @@ func (r *Repo) Count(ctx context.Context) (uint64, error) {
 	}
 	return res.Count, nil
 }
+
+func (r *Repo) Delete(ctx context.Context, id codebases.ID) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM codebases WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete codebase: %w", err)
+	}
+	return nil
+}
@@ -79,3 +79,16 @@ func (r *memory) ListByOrganization(_ context.Context, id string) ([]*codebases.
 func (r *memory) Count(context.Context) (uint64, error) {
 	return uint64(len(r.byID)), nil
 }
+
+func (m *memory) Delete(_ context.Context, id codebases.ID) error {
+	cb, ok := m.byID[id]
+	if !ok {
+		return nil
+	}
+	delete(m.byID, id)
+	delete(m.byShortID, cb.ShortCodebaseID)
+	if cb.InviteCode != nil {
+		delete(m.byInviteCode, *cb.InviteCode)
+	}
+	return nil
+}
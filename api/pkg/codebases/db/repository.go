@@ -15,4 +15,5 @@ type CodebaseRepository interface {
 	Update(entity *codebases.Codebase) error
 	ListByOrganization(ctx context.Context, organizationID string) ([]*codebases.Codebase, error)
 	Count(context.Context) (uint64, error)
+	Delete(context.Context, codebases.ID) error
 }
@@ -24,6 +24,7 @@ import (
 	"getsturdy.com/api/pkg/graphql/resolvers"
 	service_organization "getsturdy.com/api/pkg/organization/service"
 	service_remote "getsturdy.com/api/pkg/remote/service"
+	"getsturdy.com/api/pkg/trunks"
 	"getsturdy.com/api/pkg/users"
 	db_user "getsturdy.com/api/pkg/users/db"
 	"getsturdy.com/api/pkg/views"
@@ -53,6 +54,10 @@ type CodebaseRootResolver struct {
 	codebaseGitHubIntegrationResolver resolvers.CodebaseGitHubIntegrationRootResolver
 	organizationRootResolver          *resolvers.OrganizationRootResolver
 	remoteRootResolver                resolvers.RemoteRootResolver
+	landingRulesRootResolver          resolvers.LandingRulesRootResolver
+	trunksRootResolver                resolvers.TrunksRootResolver
+	webhooksRootResolver              resolvers.WebhooksRootResolver
+	releasesRootResolver              resolvers.ReleasesRootResolver
 
 	logger           *zap.Logger
 	viewEvents       events.EventReader
@@ -84,6 +89,10 @@ func NewCodebaseRootResolver(
 	codebaseGitHubIntegrationResolver resolvers.CodebaseGitHubIntegrationRootResolver,
 	organizationRootResolver *resolvers.OrganizationRootResolver,
 	remoteRootResolver resolvers.RemoteRootResolver,
+	landingRulesRootResolver resolvers.LandingRulesRootResolver,
+	trunksRootResolver resolvers.TrunksRootResolver,
+	webhooksRootResolver resolvers.WebhooksRootResolver,
+	releasesRootResolver resolvers.ReleasesRootResolver,
 
 	logger *zap.Logger,
 	viewEvents events.EventReader,
@@ -114,6 +123,10 @@ func NewCodebaseRootResolver(
 		codebaseGitHubIntegrationResolver: codebaseGitHubIntegrationResolver,
 		organizationRootResolver:          organizationRootResolver,
 		remoteRootResolver:                remoteRootResolver,
+		landingRulesRootResolver:          landingRulesRootResolver,
+		trunksRootResolver:                trunksRootResolver,
+		webhooksRootResolver:              webhooksRootResolver,
+		releasesRootResolver:              releasesRootResolver,
 
 		logger:           logger.Named("CodebaseRootResolver"),
 		viewEvents:       viewEvents,
@@ -304,6 +317,12 @@ func (r *CodebaseRootResolver) UpdateCodebase(ctx context.Context, args resolver
 	if args.Input.RequireHealthyStatus != nil {
 		cb.RequireHealthyStatus = *args.Input.RequireHealthyStatus
 	}
+	if args.Input.MergeQueueEnabled != nil {
+		cb.MergeQueueEnabled = *args.Input.MergeQueueEnabled
+	}
+	if args.Input.RequireCodeOwnersApproval != nil {
+		cb.RequireCodeOwnersApproval = *args.Input.RequireCodeOwnersApproval
+	}
 
 	if err := r.codebaseService.Update(ctx, cb); err != nil {
 		return nil, gqlerrors.Error(fmt.Errorf("failed to update codebase: %w", err))
@@ -584,8 +603,9 @@ func (r *CodebaseResolver) ACL(ctx context.Context) (resolvers.ACLResolver, erro
 func (r *CodebaseResolver) Changes(ctx context.Context, args *resolvers.CodebaseChangesArgs) ([]resolvers.ChangeResolver, error) {
 	const defaultLimit int = 100
 	var (
-		limit  = defaultLimit
-		before *graphql.ID
+		limit   = defaultLimit
+		before  *graphql.ID
+		trunkID *graphql.ID
 	)
 	if args != nil && args.Input != nil {
 		if args.Input.Limit != nil && *args.Input.Limit <= 100 {
@@ -593,8 +613,9 @@ func (r *CodebaseResolver) Changes(ctx context.Context, args *resolvers.Codebase
 		}
 
 		before = args.Input.Before
+		trunkID = args.Input.TrunkID
 	}
-	return r.root.changeRootResolver.InternalListChanges(ctx, r.c.ID, limit, before)
+	return r.root.changeRootResolver.InternalListChanges(ctx, r.c.ID, limit, before, trunkID)
 }
 
 func (r *CodebaseResolver) Readme(ctx context.Context) (resolvers.FileResolver, error) {
@@ -667,10 +688,34 @@ func (r *CodebaseResolver) Remote(ctx context.Context) (resolvers.RemoteResolver
 	}
 }
 
+func (r *CodebaseResolver) LandingRules(ctx context.Context) (resolvers.LandingRulesResolver, error) {
+	return r.root.landingRulesRootResolver.InternalLandingRulesByCodebaseID(ctx, r.c.ID)
+}
+
+func (r *CodebaseResolver) Trunks(ctx context.Context) ([]resolvers.TrunkResolver, error) {
+	return r.root.trunksRootResolver.InternalListByCodebaseID(ctx, r.c.ID)
+}
+
+func (r *CodebaseResolver) Releases(ctx context.Context) ([]resolvers.ReleaseResolver, error) {
+	return r.root.releasesRootResolver.InternalListByCodebaseID(ctx, r.c.ID)
+}
+
+func (r *CodebaseResolver) Webhooks(ctx context.Context) ([]resolvers.WebhookResolver, error) {
+	return r.root.webhooksRootResolver.InternalWebhooksByCodebaseID(ctx, r.c.ID)
+}
+
 func (r *CodebaseResolver) RequireHealthyStatus() bool {
 	return r.c.RequireHealthyStatus
 }
 
+func (r *CodebaseResolver) MergeQueueEnabled() bool {
+	return r.c.MergeQueueEnabled
+}
+
+func (r *CodebaseResolver) RequireCodeOwnersApproval() bool {
+	return r.c.RequireCodeOwnersApproval
+}
+
 func (r *CodebaseResolver) Writeable(ctx context.Context) bool {
 	if err := r.root.authService.CanWrite(ctx, r.c); err == nil {
 		return true
@@ -736,7 +781,13 @@ func (r *CodebaseRootResolver) PushCodebase(ctx context.Context, args resolvers.
 		return nil, gqlerrors.Error(err)
 	}
 
-	if err := r.remoteService.PushTrunk(ctx, c.ID); err != nil {
+	var trunkID *trunks.ID
+	if args.Input.TrunkID != nil {
+		id := trunks.ID(*args.Input.TrunkID)
+		trunkID = &id
+	}
+
+	if err := r.remoteService.PushTrunk(ctx, c.ID, trunkID); err != nil {
 		return nil, gqlerrors.Error(err)
 	}
 
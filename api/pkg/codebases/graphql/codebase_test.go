@@ -39,6 +39,10 @@ func TestCodebaseAccess(t *testing.T) {
 		nil,
 		nil,
 		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 		zap.NewNop(),
 		nil,
 		nil,
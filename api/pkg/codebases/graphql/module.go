@@ -14,12 +14,16 @@ import (
 	graphql_github "getsturdy.com/api/pkg/github/graphql"
 	"getsturdy.com/api/pkg/graphql/resolvers"
 	graphql_integrations "getsturdy.com/api/pkg/integrations/graphql"
+	graphql_landingrules "getsturdy.com/api/pkg/landingrules/graphql"
 	"getsturdy.com/api/pkg/logger"
 	service_organization "getsturdy.com/api/pkg/organization/service"
+	graphql_releases "getsturdy.com/api/pkg/releases/graphql"
 	graphql_remote "getsturdy.com/api/pkg/remote/graphql/module"
 	service_remote "getsturdy.com/api/pkg/remote/service/module"
+	graphql_trunks "getsturdy.com/api/pkg/trunks/graphql"
 	db_user "getsturdy.com/api/pkg/users/db"
 	db_view "getsturdy.com/api/pkg/views/db"
+	graphql_webhooks "getsturdy.com/api/pkg/webhooks/graphql"
 	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
 	"getsturdy.com/api/vcs/executor"
 )
@@ -45,6 +49,10 @@ func Module(c *di.Container) {
 	c.Import(graphql_integrations.Module)
 	c.Import(graphql_github.Module)
 	c.Import(graphql_remote.Module)
+	c.Import(graphql_landingrules.Module)
+	c.Import(graphql_trunks.Module)
+	c.Import(graphql_releases.Module)
+	c.Import(graphql_webhooks.Module)
 	c.Register(NewCodebaseRootResolver)
 
 	// populate cyclic resolver
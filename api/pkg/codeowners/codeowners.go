@@ -0,0 +1,151 @@
+package codeowners
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"getsturdy.com/api/pkg/unidiff"
+)
+
+// Paths are the locations in the codebase where the ownership file is looked up, in order.
+var Paths = []string{
+	".sturdy/CODEOWNERS",
+	"CODEOWNERS",
+}
+
+// Rule assigns owners to the paths matching Pattern.
+type Rule struct {
+	Pattern string
+	// Owners are the emails of the owners of the paths matching the pattern.
+	Owners []string
+
+	allower *unidiff.Allower
+}
+
+// CodeOwners is a parsed ownership file.
+//
+// Each line in the file is a pattern followed by the emails of the owners of the paths that match it. Patterns use
+// the same semantics as unidiff.Allower. Empty lines, and lines starting with '#' are ignored.
+// If more than one rule matches a path, the last one wins. A rule without owners can be used to unset the owners
+// of a path.
+type CodeOwners struct {
+	Rules []*Rule
+}
+
+// Parse parses the contents of an ownership file.
+func Parse(contents []byte) (*CodeOwners, error) {
+	co := &CodeOwners{}
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if strings.HasPrefix(fields[0], "!") {
+			return nil, fmt.Errorf("line %d: negated patterns are not supported", lineNumber)
+		}
+
+		allower, err := unidiff.NewAllower(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		owners := fields[1:]
+		for _, owner := range owners {
+			if !strings.Contains(owner, "@") {
+				return nil, fmt.Errorf("line %d: owner %q is not an email", lineNumber, owner)
+			}
+		}
+
+		co.Rules = append(co.Rules, &Rule{
+			Pattern: fields[0],
+			Owners:  owners,
+			allower: allower,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return co, nil
+}
+
+// Owners returns the emails of the owners of the file at path. A file is matched by a rule if either the file, or
+// any of the directories it is in, matches the pattern.
+func (co *CodeOwners) Owners(path string) []string {
+	for i := len(co.Rules) - 1; i >= 0; i-- {
+		if co.Rules[i].matches(path) {
+			return co.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+func (r *Rule) matches(path string) bool {
+	if r.allower.IsAllowed(path, false) {
+		return true
+	}
+	for dir := parentDir(path); dir != ""; dir = parentDir(dir) {
+		if r.allower.IsAllowed(dir, true) {
+			return true
+		}
+	}
+	return false
+}
+
+func parentDir(path string) string {
+	idx := strings.LastIndexByte(path, '/')
+	if idx < 0 {
+		return ""
+	}
+	return path[:idx]
+}
+
+// OwnedPaths maps the paths changed by diffs to their owners. Paths without owners are omitted.
+func (co *CodeOwners) OwnedPaths(diffs []unidiff.FileDiff) map[string][]string {
+	res := make(map[string][]string)
+	for _, diff := range diffs {
+		for _, path := range []string{diff.OrigName, diff.NewName} {
+			if path == "" || path == "/dev/null" {
+				continue
+			}
+			if owners := co.Owners(path); len(owners) > 0 {
+				res[path] = owners
+			}
+		}
+	}
+	return res
+}
+
+// Unapproved returns the sorted owned paths that none of their owners have approved. Approvals from the author of the
+// changes are ignored, authors can not approve their own changes.
+func Unapproved(ownedPaths map[string][]string, author string, approvers []string) []string {
+	approved := make(map[string]bool, len(approvers))
+	for _, approver := range approvers {
+		if !strings.EqualFold(approver, author) {
+			approved[strings.ToLower(approver)] = true
+		}
+	}
+
+	var missing []string
+	for path, owners := range ownedPaths {
+		if !anyApproved(approved, owners) {
+			missing = append(missing, path)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+func anyApproved(approved map[string]bool, owners []string) bool {
+	for _, owner := range owners {
+		if approved[strings.ToLower(owner)] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package codeowners_test
+
+import (
+	"testing"
+
+	"getsturdy.com/api/pkg/codeowners"
+	"getsturdy.com/api/pkg/unidiff"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testFile = `
+# Default owners
+*                 default@getsturdy.com
+
+*.go              gopher@getsturdy.com backend@getsturdy.com
+/web/             frontend@getsturdy.com
+docs/**/*.md      writer@getsturdy.com
+/web/vendor/
+`
+
+func TestOwners(t *testing.T) {
+	co, err := codeowners.Parse([]byte(testFile))
+	assert.NoError(t, err)
+	assert.Len(t, co.Rules, 5)
+
+	cases := []struct {
+		path     string
+		expected []string
+	}{
+		{"README.md", []string{"default@getsturdy.com"}},
+		{"main.go", []string{"gopher@getsturdy.com", "backend@getsturdy.com"}},
+		{"api/pkg/main.go", []string{"gopher@getsturdy.com", "backend@getsturdy.com"}},
+		{"web/index.js", []string{"frontend@getsturdy.com"}},
+		{"web/src/main.go", []string{"frontend@getsturdy.com"}},
+		{"api/web/index.js", []string{"default@getsturdy.com"}},
+		{"docs/guides/setup.md", []string{"writer@getsturdy.com"}},
+		{"web/vendor/lib.js", []string{}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.path, func(t *testing.T) {
+			assert.Equal(t, tc.expected, co.Owners(tc.path))
+		})
+	}
+}
+
+func TestOwnedPaths(t *testing.T) {
+	co, err := codeowners.Parse([]byte("/api/ backend@getsturdy.com"))
+	assert.NoError(t, err)
+
+	owned := co.OwnedPaths([]unidiff.FileDiff{
+		{OrigName: "/dev/null", NewName: "api/main.go"},
+		{OrigName: "web/index.js", NewName: "web/index.js"},
+		{OrigName: "web/app.go", NewName: "api/app.go"},
+	})
+	assert.Equal(t, map[string][]string{
+		"api/main.go": {"backend@getsturdy.com"},
+		"api/app.go":  {"backend@getsturdy.com"},
+	}, owned)
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := codeowners.Parse([]byte("!*.go foo@getsturdy.com"))
+	assert.Error(t, err)
+
+	_, err = codeowners.Parse([]byte("*.go foo"))
+	assert.Error(t, err)
+
+	_, err = codeowners.Parse([]byte("[ foo@getsturdy.com"))
+	assert.Error(t, err)
+}
+
+func TestUnapproved(t *testing.T) {
+	ownedPaths := map[string][]string{
+		"main.go":   {"author@getsturdy.com"},
+		"README.md": {"reviewer@getsturdy.com"},
+		"web/a.js":  {"author@getsturdy.com", "reviewer@getsturdy.com"},
+	}
+
+	cases := []struct {
+		name      string
+		approvers []string
+		expected  []string
+	}{
+		{"no approvals", nil, []string{"README.md", "main.go", "web/a.js"}},
+		{"author owns the path", []string{"author@getsturdy.com"}, []string{"README.md", "main.go", "web/a.js"}},
+		{"author owns the path, different case", []string{"Author@getsturdy.com"}, []string{"README.md", "main.go", "web/a.js"}},
+		{"reviewer approved", []string{"reviewer@getsturdy.com"}, []string{"main.go"}},
+		{"both approved", []string{"author@getsturdy.com", "other@getsturdy.com", "REVIEWER@getsturdy.com"}, []string{"main.go"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, codeowners.Unapproved(ownedPaths, "author@getsturdy.com", tc.approvers))
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	service_codebase "getsturdy.com/api/pkg/codebases/service"
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/logger"
+	queue "getsturdy.com/api/pkg/queue/module"
+	service_review "getsturdy.com/api/pkg/review/service"
+	service_users "getsturdy.com/api/pkg/users/service/module"
+	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
+	"getsturdy.com/api/vcs/executor"
+)
+
+func Module(c *di.Container) {
+	c.Import(logger.Module)
+	c.Import(service_workspaces.Module)
+	c.Import(service_review.Module)
+	c.Import(service_codebase.Module)
+	c.Import(service_users.Module)
+	c.Import(executor.Module)
+	c.Import(queue.Module)
+	c.Register(New)
+}
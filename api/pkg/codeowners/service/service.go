@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"getsturdy.com/api/pkg/codebases"
+	service_codebase "getsturdy.com/api/pkg/codebases/service"
+	"getsturdy.com/api/pkg/codeowners"
+	"getsturdy.com/api/pkg/queue"
+	"getsturdy.com/api/pkg/queue/names"
+	"getsturdy.com/api/pkg/review"
+	service_review "getsturdy.com/api/pkg/review/service"
+	"getsturdy.com/api/pkg/users"
+	service_users "getsturdy.com/api/pkg/users/service"
+	"getsturdy.com/api/pkg/workspaces"
+	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
+	"getsturdy.com/api/vcs"
+	"getsturdy.com/api/vcs/executor"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrNotFound = errors.New("codebase has no code owners")
+	// ErrMissingApproval is returned by Check if one or more of the owned paths in a workspace has not been approved
+	// by any of its owners.
+	ErrMissingApproval = errors.New("workspace is missing approval from code owners")
+)
+
+// Message is the message that is published to the code owners worker, when the reviewers of a workspace needs to be
+// updated.
+type Message struct {
+	WorkspaceID string `json:"workspace_id"`
+}
+
+type Service struct {
+	logger *zap.Logger
+
+	workspaceService *service_workspaces.Service
+	reviewService    *service_review.Service
+	codebaseService  *service_codebase.Service
+	usersService     service_users.Service
+
+	executorProvider executor.Provider
+	queue            queue.Queue
+}
+
+func New(
+	logger *zap.Logger,
+
+	workspaceService *service_workspaces.Service,
+	reviewService *service_review.Service,
+	codebaseService *service_codebase.Service,
+	usersService service_users.Service,
+
+	executorProvider executor.Provider,
+	queue queue.Queue,
+) *Service {
+	return &Service{
+		logger: logger.Named("codeOwnersService"),
+
+		workspaceService: workspaceService,
+		reviewService:    reviewService,
+		codebaseService:  codebaseService,
+		usersService:     usersService,
+
+		executorProvider: executorProvider,
+		queue:            queue,
+	}
+}
+
+// Notify schedules the code owners of the workspace to be requested as reviewers.
+func (s *Service) Notify(ctx context.Context, workspaceID string) error {
+	if err := s.queue.Publish(ctx, names.CodeOwners, &Message{WorkspaceID: workspaceID}); err != nil {
+		return fmt.Errorf("failed to publish to queue: %w", err)
+	}
+	return nil
+}
+
+// Get returns the code owners of the codebase, as they are defined on the trunk.
+func (s *Service) Get(ctx context.Context, codebaseID codebases.ID) (*codeowners.CodeOwners, error) {
+	var contents []byte
+	if err := s.executorProvider.New().GitRead(func(repo vcs.RepoGitReader) error {
+		headCommit, err := repo.HeadCommit()
+		if err != nil {
+			// the trunk is empty
+			return ErrNotFound
+		}
+		for _, path := range codeowners.Paths {
+			contents, err = repo.FileContentsAtCommit(headCommit.Id().String(), path)
+			switch {
+			case err == nil:
+				return nil
+			case errors.Is(err, vcs.ErrFileNotFound):
+				continue
+			default:
+				return fmt.Errorf("failed to read %s: %w", path, err)
+			}
+		}
+		return ErrNotFound
+	}).ExecTrunkContext(ctx, codebaseID, "readCodeOwners"); err != nil {
+		return nil, err
+	}
+
+	co, err := codeowners.Parse(contents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse code owners: %w", err)
+	}
+	return co, nil
+}
+
+// OwnedPaths returns the paths changed in the workspace, together with the emails of their owners. Paths without
+// owners are omitted.
+func (s *Service) OwnedPaths(ctx context.Context, ws *workspaces.Workspace) (map[string][]string, error) {
+	co, err := s.Get(ctx, ws.CodebaseID)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	diffs, _, err := s.workspaceService.Diffs(ctx, ws.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diffs: %w", err)
+	}
+
+	return co.OwnedPaths(diffs), nil
+}
+
+// RequestReviews requests a review from every owner of the paths changed in the workspace. Owners that are not
+// users with access to the codebase are ignored, and so is the author of the workspace.
+func (s *Service) RequestReviews(ctx context.Context, ws *workspaces.Workspace) error {
+	ownedPaths, err := s.OwnedPaths(ctx, ws)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	for _, owners := range ownedPaths {
+		for _, email := range owners {
+			if seen[strings.ToLower(email)] {
+				continue
+			}
+			seen[strings.ToLower(email)] = true
+
+			owner, err := s.usersService.GetByEmail(ctx, email)
+			if err != nil {
+				s.logger.Warn("code owner is not a user", zap.String("email", email), zap.Error(err))
+				continue
+			}
+			if owner.ID == ws.UserID {
+				continue
+			}
+
+			canAccess, err := s.codebaseService.CanAccess(ctx, owner.ID, ws.CodebaseID)
+			if err != nil {
+				return fmt.Errorf("failed to check access: %w", err)
+			}
+			if !canAccess {
+				s.logger.Warn("code owner can not access the codebase", zap.Stringer("user_id", owner.ID), zap.Stringer("codebase_id", ws.CodebaseID))
+				continue
+			}
+
+			if _, err := s.reviewService.RequestReview(ctx, ws, ws.UserID, owner.ID); err != nil {
+				return fmt.Errorf("failed to request review: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Check returns ErrMissingApproval if any of the paths changed in the workspace does not have an approving review
+// from at least one of its owners. The author of the workspace can not approve their own changes.
+func (s *Service) Check(ctx context.Context, ws *workspaces.Workspace) error {
+	ownedPaths, err := s.OwnedPaths(ctx, ws)
+	if err != nil {
+		return err
+	}
+	if len(ownedPaths) == 0 {
+		return nil
+	}
+
+	reviews, err := s.reviewService.ListLatestByWorkspace(ctx, ws.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list reviews: %w", err)
+	}
+
+	author, err := s.usersService.GetByID(ctx, ws.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get author: %w", err)
+	}
+
+	var approverIDs []users.ID
+	for _, rev := range reviews {
+		if rev.Grade == review.ReviewGradeApprove && rev.DismissedAt == nil && !rev.IsReplaced && rev.UserID != ws.UserID {
+			approverIDs = append(approverIDs, rev.UserID)
+		}
+	}
+
+	var approvers []string
+	if len(approverIDs) > 0 {
+		approverUsers, err := s.usersService.GetByIDs(ctx, approverIDs...)
+		if err != nil {
+			return fmt.Errorf("failed to get approvers: %w", err)
+		}
+		for _, approver := range approverUsers {
+			approvers = append(approvers, approver.Email)
+		}
+	}
+
+	if missing := codeowners.Unapproved(ownedPaths, author.Email, approvers); len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrMissingApproval, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
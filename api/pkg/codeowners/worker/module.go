@@ -0,0 +1,17 @@
+package worker
+
+import (
+	service_codeowners "getsturdy.com/api/pkg/codeowners/service"
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/logger"
+	queue "getsturdy.com/api/pkg/queue/module"
+	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
+)
+
+func Module(c *di.Container) {
+	c.Import(logger.Module)
+	c.Import(queue.Module)
+	c.Import(service_codeowners.Module)
+	c.Import(service_workspaces.Module)
+	c.Register(New)
+}
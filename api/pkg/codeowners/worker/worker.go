@@ -0,0 +1,86 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	service_codeowners "getsturdy.com/api/pkg/codeowners/service"
+	"getsturdy.com/api/pkg/queue"
+	"getsturdy.com/api/pkg/queue/names"
+	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
+
+	"go.uber.org/zap"
+)
+
+// Queue is a background queue that requests reviews from the code owners of updated workspaces.
+type Queue struct {
+	logger *zap.Logger
+
+	queue queue.Queue
+	name  names.IncompleteQueueName
+
+	codeOwnersService *service_codeowners.Service
+	workspaceService  *service_workspaces.Service
+}
+
+func New(
+	logger *zap.Logger,
+	queue queue.Queue,
+	codeOwnersService *service_codeowners.Service,
+	workspaceService *service_workspaces.Service,
+) *Queue {
+	return &Queue{
+		logger:            logger.Named("codeOwnersQueue"),
+		queue:             queue,
+		name:              names.CodeOwners,
+		codeOwnersService: codeOwnersService,
+		workspaceService:  workspaceService,
+	}
+}
+
+// Start starts the worker.
+func (q *Queue) Start(ctx context.Context) error {
+	messages := make(chan queue.Message)
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				q.logger.Error("panic in code owners queue", zap.String("panic", fmt.Sprintf("%v", rec)))
+			}
+		}()
+		for msg := range messages {
+			m := &service_codeowners.Message{}
+			if err := msg.As(m); err != nil {
+				q.logger.Error("failed to decode message", zap.Error(err), zap.Any("message", msg))
+				continue
+			}
+
+			logger := q.logger.With(zap.String("workspace_id", m.WorkspaceID))
+
+			ws, err := q.workspaceService.GetByID(ctx, m.WorkspaceID)
+			if err != nil {
+				logger.Error("failed to get workspace", zap.Error(err))
+				continue
+			}
+
+			if ws.ArchivedAt == nil {
+				if err := q.codeOwnersService.RequestReviews(ctx, ws); err != nil {
+					logger.Error("failed to request reviews from code owners", zap.Error(err))
+					continue
+				}
+			}
+
+			if err := msg.Ack(); err != nil {
+				logger.Error("failed to ack message", zap.Error(err))
+				continue
+			}
+		}
+	}()
+
+	q.logger.Info("starting queue", zap.Stringer("queue_name", q.name))
+	if err := q.queue.Subscribe(ctx, q.name, messages); err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+	q.logger.Info("queue stoped", zap.Stringer("queue_name", q.name))
+
+	return nil
+}
@@ -19,6 +19,7 @@ type Repository interface {
 	GetByWorkspace(workspaceID string) ([]comments.Comment, error)
 	GetByParent(id comments.ID) ([]comments.Comment, error)
 	CountByWorkspaceID(context.Context, string) (int32, error)
+	DeleteByCodebaseID(context.Context, codebases.ID) error
 }
 
 type repo struct {
@@ -111,3 +112,10 @@ func (r *repo) CountByWorkspaceID(ctx context.Context, workspaceID string) (int3
 	}
 	return res, nil
 }
+
+func (r *repo) DeleteByCodebaseID(ctx context.Context, codebaseID codebases.ID) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM comments WHERE codebase_id = $1`, codebaseID); err != nil {
+		return fmt.Errorf("failed to delete comments: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+
+	"getsturdy.com/api/pkg/changes"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/comments"
+)
+
+var _ Repository = &memory{}
+
+type memory struct {
+	mu   sync.RWMutex
+	byID map[comments.ID]comments.Comment
+}
+
+func NewMemory() Repository {
+	return &memory{
+		byID: map[comments.ID]comments.Comment{},
+	}
+}
+
+func (m *memory) Create(comment comments.Comment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.byID[comment.ID] = comment
+	return nil
+}
+
+func (m *memory) Get(id comments.ID) (comments.Comment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	comment, found := m.byID[id]
+	if !found {
+		return comments.Comment{}, sql.ErrNoRows
+	}
+	return comment, nil
+}
+
+func (m *memory) Update(comment comments.Comment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, found := m.byID[comment.ID]; !found {
+		return sql.ErrNoRows
+	}
+	m.byID[comment.ID] = comment
+	return nil
+}
+
+func (m *memory) GetByCodebaseAndChange(codebaseID codebases.ID, changeID changes.ID) ([]comments.Comment, error) {
+	res := m.list(func(c comments.Comment) bool {
+		return c.CodebaseID == codebaseID && c.ChangeID != nil && *c.ChangeID == changeID && c.ParentComment == nil
+	})
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].CreatedAt.After(res[j].CreatedAt)
+	})
+	return res, nil
+}
+
+func (m *memory) GetByWorkspace(workspaceID string) ([]comments.Comment, error) {
+	res := m.list(func(c comments.Comment) bool {
+		return c.WorkspaceID != nil && *c.WorkspaceID == workspaceID && c.ParentComment == nil
+	})
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].CreatedAt.After(res[j].CreatedAt)
+	})
+	return res, nil
+}
+
+func (m *memory) GetByParent(id comments.ID) ([]comments.Comment, error) {
+	res := m.list(func(c comments.Comment) bool {
+		return c.ParentComment != nil && *c.ParentComment == id
+	})
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].CreatedAt.Before(res[j].CreatedAt)
+	})
+	return res, nil
+}
+
+func (m *memory) CountByWorkspaceID(_ context.Context, workspaceID string) (int32, error) {
+	res := m.list(func(c comments.Comment) bool {
+		return c.WorkspaceID != nil && *c.WorkspaceID == workspaceID
+	})
+	return int32(len(res)), nil
+}
+
+func (m *memory) DeleteByCodebaseID(_ context.Context, codebaseID codebases.ID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, comment := range m.byID {
+		if comment.CodebaseID == codebaseID {
+			delete(m.byID, id)
+		}
+	}
+	return nil
+}
+
+// list returns the comments that are not deleted, and that match the filter.
+func (m *memory) list(filter func(comments.Comment) bool) []comments.Comment {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var res []comments.Comment
+	for _, comment := range m.byID {
+		if comment.DeletedAt == nil && filter(comment) {
+			res = append(res, comment)
+		}
+	}
+	return res
+}
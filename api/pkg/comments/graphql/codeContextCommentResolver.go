@@ -1,6 +1,10 @@
 package graphql
 
 import (
+	"context"
+
+	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
+
 	"github.com/graph-gophers/graphql-go"
 )
 
@@ -28,8 +32,16 @@ func (r *CodeCommentContextResolver) LineIsNew() bool {
 	return r.comment.LineIsNew
 }
 
-func (r *CodeCommentContextResolver) Context() string {
-	return *r.comment.Context
+// Context is empty if the user is not allowed to read the file that is commented on.
+func (r *CodeCommentContextResolver) Context(ctx context.Context) (string, error) {
+	allower, err := r.root.getAllower(ctx, &r.comment)
+	if err != nil {
+		return "", gqlerrors.Error(err)
+	}
+	if !isPathAllowed(allower, r.comment.Path, r.comment.OldPath) {
+		return "", nil
+	}
+	return *r.comment.Context, nil
 }
 
 func (r *CodeCommentContextResolver) ContextStartsAtLine() int32 {
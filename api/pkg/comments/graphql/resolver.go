@@ -29,6 +29,7 @@ import (
 	"getsturdy.com/api/pkg/notification"
 	notification_sender "getsturdy.com/api/pkg/notification/sender"
 	db_snapshots "getsturdy.com/api/pkg/snapshots/db"
+	"getsturdy.com/api/pkg/unidiff"
 	"getsturdy.com/api/pkg/users"
 	service_users "getsturdy.com/api/pkg/users/service"
 	"getsturdy.com/api/pkg/views"
@@ -67,6 +68,7 @@ type CommentRootResolver struct {
 
 	eventsReader       events.EventReader
 	eventsSubscriber   *eventsv2.Subscriber
+	eventsPublisher    *eventsv2.Publisher
 	eventsSender       events.EventSender
 	notificationSender notification_sender.NotificationSender
 	activitySender     sender_workspace_activity.ActivitySender
@@ -92,6 +94,7 @@ func NewResolver(
 
 	eventsSender events.EventSender,
 	eventsSubscriber *eventsv2.Subscriber,
+	eventsPublisher *eventsv2.Publisher,
 	eventsReader events.EventReader,
 	notificationSender notification_sender.NotificationSender,
 	activitySender sender_workspace_activity.ActivitySender,
@@ -121,6 +124,7 @@ func NewResolver(
 
 		eventsSender:       eventsSender,
 		eventsSubscriber:   eventsSubscriber,
+		eventsPublisher:    eventsPublisher,
 		eventsReader:       eventsReader,
 		notificationSender: notificationSender,
 		activitySender:     activitySender,
@@ -332,6 +336,8 @@ func (r *CommentRootResolver) UpdateComment(ctx context.Context, args resolvers.
 		return nil, gqlerrors.Error(err)
 	}
 
+	r.sendCommentUpdated(ctx, comment)
+
 	if comment.WorkspaceID != nil {
 		if err := r.eventsSender.Codebase(comment.CodebaseID, events.WorkspaceUpdatedComments, *comment.WorkspaceID); err != nil {
 			r.logger.Error("failed to send workspace updated comments event", zap.Error(err))
@@ -368,6 +374,8 @@ func (r *CommentRootResolver) DeleteComment(ctx context.Context, args resolvers.
 		return nil, gqlerrors.Error(err)
 	}
 
+	r.sendCommentUpdated(ctx, &comm)
+
 	// send events
 	if comm.WorkspaceID != nil {
 		if err := r.eventsSender.Codebase(comm.CodebaseID, events.WorkspaceUpdatedComments, *comm.WorkspaceID); err != nil {
@@ -406,6 +414,8 @@ func (r *CommentRootResolver) ResolveComment(ctx context.Context, args resolvers
 		return nil, gqlerrors.Error(err)
 	}
 
+	r.sendCommentUpdated(ctx, &comm)
+
 	// send events
 	if comm.WorkspaceID != nil {
 		if err := r.eventsSender.Codebase(comm.CodebaseID, events.WorkspaceUpdatedComments, *comm.WorkspaceID); err != nil {
@@ -416,6 +426,13 @@ func (r *CommentRootResolver) ResolveComment(ctx context.Context, args resolvers
 	return &CommentResolver{root: r, comment: comm}, nil
 }
 
+func (r *CommentRootResolver) sendCommentUpdated(ctx context.Context, comment *comments.Comment) {
+	if err := r.eventsPublisher.CommentUpdated(ctx, eventsv2.Codebase(comment.CodebaseID), comment); err != nil {
+		r.logger.Error("failed to send comment updated event", zap.Error(err))
+		// do not fail
+	}
+}
+
 func (r *CommentRootResolver) getUsersByCodebaseID(ctx context.Context, codebaseID codebases.ID) ([]*users.User, error) {
 	codebaseUsers, err := r.codebaseUserRepo.GetByCodebase(codebaseID)
 	if err != nil {
@@ -465,6 +482,8 @@ func (r *CommentRootResolver) CreateComment(ctx context.Context, args resolvers.
 		return nil, gqlerrors.Error(err)
 	}
 
+	r.sendCommentUpdated(ctx, comment)
+
 	if err := r.activitySender.Comment(ctx, comment); err != nil {
 		return nil, gqlerrors.Error(err)
 	}
@@ -584,6 +603,10 @@ func (r *CommentRootResolver) prepareTopComment(ctx context.Context, args resolv
 
 		// Comment on code
 		if args.Input.Path != nil {
+			if err := r.canReadPath(ctx, ws, *args.Input.Path, args.Input.OldPath); err != nil {
+				return nil, err
+			}
+
 			// Build context
 			context, contextStartsAt, err := vcs.GetWorkspaceContext(int(*args.Input.LineStart), *args.Input.LineIsNew, *args.Input.Path, args.Input.OldPath, ws, r.executorProvider, r.snapshotRepo)
 			if err != nil {
@@ -609,6 +632,10 @@ func (r *CommentRootResolver) prepareTopComment(ctx context.Context, args resolv
 
 		// Comment on code
 		if args.Input.Path != nil {
+			if err := r.canReadPath(ctx, ch, *args.Input.Path, args.Input.OldPath); err != nil {
+				return nil, err
+			}
+
 			// Build context
 			context, contextStartsAt, err := vcs.GetChangeContext(int(*args.Input.LineStart), *args.Input.LineIsNew, *args.Input.Path, args.Input.OldPath, ch, r.executorProvider)
 			if err != nil {
@@ -653,6 +680,48 @@ func (r *CommentRootResolver) prepareTopComment(ctx context.Context, args resolv
 	return newComm, nil
 }
 
+// canReadPath returns an error if the user can't read the files at path or oldPath in obj.
+func (r *CommentRootResolver) canReadPath(ctx context.Context, obj any, path string, oldPath *string) error {
+	allower, err := r.authService.GetAllower(ctx, obj)
+	if err != nil {
+		return fmt.Errorf("failed to get allower: %w", err)
+	}
+	if !isPathAllowed(allower, path, oldPath) {
+		return fmt.Errorf("path is not allowed: %w", auth.ErrForbidden)
+	}
+	return nil
+}
+
+// getAllower returns the allower for the workspace or change that the comment is made on.
+func (r *CommentRootResolver) getAllower(ctx context.Context, comment *comments.Comment) (*unidiff.Allower, error) {
+	switch {
+	case comment.WorkspaceID != nil:
+		ws, err := r.workspaceReader.Get(*comment.WorkspaceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get workspace: %w", err)
+		}
+		return r.authService.GetAllower(ctx, ws)
+	case comment.ChangeID != nil:
+		ch, err := r.changeService.GetChangeByID(ctx, *comment.ChangeID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get change: %w", err)
+		}
+		return r.authService.GetAllower(ctx, ch)
+	default:
+		return r.authService.GetAllower(ctx, nil)
+	}
+}
+
+func isPathAllowed(allower *unidiff.Allower, path string, oldPath *string) bool {
+	if !allower.IsAllowed(path, false) {
+		return false
+	}
+	if oldPath != nil && *oldPath != "" && !allower.IsAllowed(*oldPath, false) {
+		return false
+	}
+	return true
+}
+
 func (r *CommentRootResolver) prepareReplyComment(ctx context.Context, args resolvers.CreateCommentArgs) (*comments.Comment, error) {
 	userID, err := auth.UserID(ctx)
 	if err != nil {
@@ -6,15 +6,20 @@ import (
 	"os"
 
 	proxy "getsturdy.com/api/pkg/analytics/proxy/configuration"
+	blobs "getsturdy.com/api/pkg/blobs/configuration"
 	service_ci "getsturdy.com/api/pkg/ci/service/configuration"
 	db "getsturdy.com/api/pkg/db/configuration"
 	"getsturdy.com/api/pkg/di"
+	events "getsturdy.com/api/pkg/events/broadcast/configuration"
 	gitserver "getsturdy.com/api/pkg/gitserver/configuration"
 	http "getsturdy.com/api/pkg/http/configuration"
 	logger "getsturdy.com/api/pkg/logger/configuration"
 	metrics "getsturdy.com/api/pkg/metrics/configuration"
 	pprof "getsturdy.com/api/pkg/pprof/configuration"
+	queue "getsturdy.com/api/pkg/queue/configuration"
+	shards "getsturdy.com/api/pkg/shards/configuration"
 	uploader "getsturdy.com/api/pkg/users/avatars/uploader/configuration"
+	webhooks "getsturdy.com/api/pkg/webhooks/configuration"
 	provider "getsturdy.com/api/vcs/provider/configuration"
 
 	"github.com/jessevdk/go-flags"
@@ -31,6 +36,10 @@ type Base struct {
 	Pprof    *pprof.Configuration      `flags-group:"pprof" namespace:"pprof"`
 	Metrics  *metrics.Configuration    `flags-group:"metrics" namespace:"metrics"`
 	Logger   *logger.Configuration     `flags-group:"logger" namespace:"logger"`
+	Events   *events.Configuration     `flags-group:"events" namespace:"events"`
+	Blobs    *blobs.Configuration      `flags-group:"blobs" namespace:"blobs"`
+	Shards   *shards.Configuration     `flags-group:"shards" namespace:"shards"`
+	Webhooks *webhooks.Configuration   `flags-group:"webhooks" namespace:"webhooks"`
 }
 
 type Configuration struct {
@@ -38,6 +47,7 @@ type Configuration struct {
 
 	Analytics *proxy.Configuration    `flags-group:"analytics" namespace:"analytics"`
 	Avatars   *uploader.Configuration `flags-group:"avatars" namespace:"users.avatars"`
+	Queue     *queue.Configuration    `flags-group:"queue" namespace:"queue"`
 }
 
 func New() (Configuration, error) {
@@ -8,6 +8,7 @@ import (
 	proxy "getsturdy.com/api/pkg/analytics/proxy/configuration"
 	"getsturdy.com/api/pkg/configuration"
 	"getsturdy.com/api/pkg/github/enterprise/config"
+	queue "getsturdy.com/api/pkg/queue/configuration"
 	uploader "getsturdy.com/api/pkg/users/avatars/uploader/configuration"
 
 	"github.com/jessevdk/go-flags"
@@ -19,6 +20,7 @@ type Configuration struct {
 	GitHub    *config.GitHubAppConfig `flags-group:"github-app" namespace:"github-app" env-namespace:"STURDY_GITHUB_APP"`
 	Analytics *proxy.Configuration    `flags-group:"analytics" namespace:"analytics"`
 	Avatars   *uploader.Configuration `flags-group:"avatars" namespace:"users.avatars"`
+	Queue     *queue.Configuration    `flags-group:"queue" namespace:"queue"`
 }
 
 func New() (Configuration, error) {
@@ -2,20 +2,26 @@ package configuration
 
 import (
 	"os"
+	"path/filepath"
 	"time"
 
 	proxy "getsturdy.com/api/pkg/analytics/proxy/configuration"
+	blobs "getsturdy.com/api/pkg/blobs/configuration"
 	service_ci "getsturdy.com/api/pkg/ci/service/configuration"
 	"getsturdy.com/api/pkg/configuration/flags"
 	db "getsturdy.com/api/pkg/db/configuration"
 	"getsturdy.com/api/pkg/di"
+	events "getsturdy.com/api/pkg/events/broadcast/configuration"
 	gitserver "getsturdy.com/api/pkg/gitserver/configuration"
 	http "getsturdy.com/api/pkg/http/configuration"
 	"getsturdy.com/api/pkg/internal/sturdytest"
 	logger "getsturdy.com/api/pkg/logger/configuration"
 	metrics "getsturdy.com/api/pkg/metrics/configuration"
 	pprof "getsturdy.com/api/pkg/pprof/configuration"
+	queue "getsturdy.com/api/pkg/queue/configuration"
+	shards "getsturdy.com/api/pkg/shards/configuration"
 	uploader "getsturdy.com/api/pkg/users/avatars/uploader/configuration"
+	webhooks "getsturdy.com/api/pkg/webhooks/configuration"
 	provider "getsturdy.com/api/vcs/provider/configuration"
 )
 
@@ -73,10 +79,15 @@ func TestModule(c *di.Container) {
 				Logger: &logger.Configuration{
 					Level: "INFO",
 				},
+				Events:   &events.Configuration{Type: "inmemory"},
+				Blobs:    &blobs.Configuration{Type: "fs", Path: filepath.Join(tmpPath, "blobs")},
+				Shards:   &shards.Configuration{},
+				Webhooks: &webhooks.Configuration{},
 			},
 
 			Analytics: &proxy.Configuration{Disable: true},
 			Avatars:   &uploader.Configuration{},
+			Queue:     &queue.Configuration{Type: "inmemory"},
 		}, nil
 	})
 }
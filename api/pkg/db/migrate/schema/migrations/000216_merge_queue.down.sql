@@ -0,0 +1,4 @@
+DROP TABLE merge_queue_entries;
+
+ALTER TABLE codebases
+    DROP COLUMN merge_queue_enabled;
@@ -0,0 +1,22 @@
+ALTER TABLE codebases
+    ADD COLUMN merge_queue_enabled BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE merge_queue_entries
+(
+    id                TEXT PRIMARY KEY,
+    codebase_id       TEXT                     NOT NULL,
+    workspace_id      TEXT                     NOT NULL,
+    user_id           TEXT                     NOT NULL,
+    snapshot_id       TEXT                     NOT NULL,
+    status            TEXT                     NOT NULL,
+    base_commit_sha   TEXT,
+    commit_sha        TEXT,
+    tested_commit_sha TEXT,
+    change_id         TEXT,
+    failure_reason    TEXT,
+    created_at        TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at        TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX merge_queue_entries_codebase_id_status_idx ON merge_queue_entries (codebase_id, status);
+CREATE INDEX merge_queue_entries_workspace_id_idx ON merge_queue_entries (workspace_id);
@@ -0,0 +1,9 @@
+CREATE TABLE ci_configurations_webhook (
+    id             TEXT                     NOT NULL PRIMARY KEY,
+    codebase_id    TEXT                     NOT NULL,
+    integration_id TEXT                     NOT NULL UNIQUE,
+    url            TEXT                     NOT NULL,
+    secret         TEXT                     NOT NULL,
+    created_at     TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at     TIMESTAMP WITH TIME ZONE
+);
@@ -0,0 +1,13 @@
+CREATE TABLE pushed_branches
+(
+    id           TEXT PRIMARY KEY,
+    codebase_id  TEXT                     NOT NULL,
+    user_id      TEXT                     NOT NULL,
+    name         TEXT                     NOT NULL,
+    workspace_id TEXT                     NOT NULL,
+    commit_sha   TEXT                     NOT NULL,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at   TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE UNIQUE INDEX pushed_branches_codebase_id_user_id_name_idx ON pushed_branches (codebase_id, user_id, name);
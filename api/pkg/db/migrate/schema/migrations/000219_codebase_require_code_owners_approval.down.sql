@@ -0,0 +1,2 @@
+ALTER TABLE codebases
+    DROP COLUMN require_code_owners_approval;
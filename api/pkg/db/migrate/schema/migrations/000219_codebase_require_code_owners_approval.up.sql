@@ -0,0 +1,2 @@
+ALTER TABLE codebases
+    ADD COLUMN require_code_owners_approval BOOLEAN NOT NULL DEFAULT false;
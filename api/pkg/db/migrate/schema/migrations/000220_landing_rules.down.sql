@@ -0,0 +1 @@
+DROP TABLE landing_rules;
@@ -0,0 +1,10 @@
+CREATE TABLE landing_rules
+(
+    codebase_id                   TEXT PRIMARY KEY,
+    min_approvals                 INTEGER                  NOT NULL DEFAULT 0,
+    require_resolved_comments     BOOLEAN                  NOT NULL DEFAULT false,
+    required_statuses             TEXT[]                   NOT NULL DEFAULT '{}',
+    require_up_to_date_with_trunk BOOLEAN                  NOT NULL DEFAULT false,
+    created_at                    TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at                    TIMESTAMP WITH TIME ZONE NOT NULL
+);
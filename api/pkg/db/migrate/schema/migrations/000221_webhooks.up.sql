@@ -0,0 +1,30 @@
+CREATE TABLE webhooks
+(
+    id          TEXT PRIMARY KEY,
+    codebase_id TEXT                     NOT NULL,
+    url         TEXT                     NOT NULL,
+    secret      TEXT                     NOT NULL,
+    events      TEXT[]                   NOT NULL DEFAULT '{}',
+    created_by  TEXT                     NOT NULL,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at  TIMESTAMP WITH TIME ZONE NOT NULL,
+    deleted_at  TIMESTAMP WITH TIME ZONE NULL
+);
+
+CREATE INDEX webhooks_codebase_id_idx ON webhooks (codebase_id);
+
+CREATE TABLE webhook_deliveries
+(
+    id          TEXT PRIMARY KEY,
+    webhook_id  TEXT                     NOT NULL,
+    event_id    TEXT                     NOT NULL,
+    event       TEXT                     NOT NULL,
+    payload     TEXT                     NOT NULL,
+    attempt     INTEGER                  NOT NULL,
+    status_code INTEGER                  NULL,
+    error       TEXT                     NULL,
+    duration_ms BIGINT                   NOT NULL,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX webhook_deliveries_webhook_id_created_at_idx ON webhook_deliveries (webhook_id, created_at DESC);
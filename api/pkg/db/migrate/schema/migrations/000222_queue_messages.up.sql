@@ -0,0 +1,11 @@
+CREATE TABLE queue_messages
+(
+    id            BIGSERIAL PRIMARY KEY,
+    queue         TEXT                     NOT NULL,
+    body          BYTEA                    NOT NULL,
+    receive_count INTEGER                  NOT NULL DEFAULT 0,
+    visible_at    TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at    TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX queue_messages_queue_visible_at_idx ON queue_messages (queue, visible_at);
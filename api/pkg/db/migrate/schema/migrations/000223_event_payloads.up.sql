@@ -0,0 +1,8 @@
+CREATE TABLE event_payloads
+(
+    id         TEXT PRIMARY KEY,
+    data       BYTEA                    NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX event_payloads_created_at_idx ON event_payloads (created_at);
@@ -0,0 +1 @@
+ALTER TABLE workspaces DROP COLUMN parent_workspace_id;
@@ -0,0 +1,3 @@
+ALTER TABLE workspaces ADD COLUMN parent_workspace_id TEXT;
+
+CREATE INDEX workspaces_parent_workspace_id_idx ON workspaces (parent_workspace_id);
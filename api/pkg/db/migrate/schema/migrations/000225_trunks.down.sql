@@ -0,0 +1,3 @@
+ALTER TABLE workspaces DROP COLUMN trunk_id;
+
+DROP TABLE trunks;
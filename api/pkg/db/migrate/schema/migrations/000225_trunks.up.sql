@@ -0,0 +1,13 @@
+CREATE TABLE trunks
+(
+    id             TEXT PRIMARY KEY,
+    codebase_id    TEXT                     NOT NULL,
+    name           TEXT                     NOT NULL,
+    tracked_branch TEXT,
+    created_by     TEXT                     NOT NULL,
+    created_at     TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE UNIQUE INDEX trunks_codebase_id_name_idx ON trunks (codebase_id, name);
+
+ALTER TABLE workspaces ADD COLUMN trunk_id TEXT;
@@ -0,0 +1,13 @@
+CREATE TABLE releases
+(
+    id          TEXT PRIMARY KEY,
+    codebase_id TEXT                     NOT NULL,
+    change_id   TEXT                     NOT NULL,
+    tag         TEXT                     NOT NULL,
+    notes       TEXT                     NOT NULL,
+    created_by  TEXT                     NOT NULL,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE UNIQUE INDEX releases_codebase_id_tag_idx ON releases (codebase_id, tag);
+CREATE INDEX releases_change_id_idx ON releases (change_id);
@@ -0,0 +1,2 @@
+DROP TABLE codebase_shards;
+DROP TABLE storage_nodes;
@@ -0,0 +1,17 @@
+CREATE TABLE storage_nodes
+(
+    id      TEXT PRIMARY KEY,
+    addr    TEXT                     NOT NULL,
+    seen_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE TABLE codebase_shards
+(
+    codebase_id TEXT PRIMARY KEY,
+    node_id     TEXT                     NOT NULL,
+    moving_to   TEXT,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at  TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX codebase_shards_node_id_idx ON codebase_shards (node_id);
@@ -0,0 +1,3 @@
+DROP INDEX webhook_deliveries_retry_at_idx;
+
+ALTER TABLE webhook_deliveries DROP COLUMN retry_at;
@@ -0,0 +1,3 @@
+ALTER TABLE webhook_deliveries ADD COLUMN retry_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX webhook_deliveries_retry_at_idx ON webhook_deliveries (retry_at) WHERE retry_at IS NOT NULL;
@@ -0,0 +1 @@
+DROP TABLE ci_builds;
@@ -0,0 +1,13 @@
+CREATE TABLE ci_builds
+(
+    id              TEXT PRIMARY KEY,
+    codebase_id     TEXT                     NOT NULL,
+    integration_id  TEXT                     NOT NULL,
+    build_id        TEXT                     NOT NULL,
+    title           TEXT                     NOT NULL,
+    trunk_commit_id TEXT                     NOT NULL,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX ci_builds_codebase_id_trunk_commit_id_idx
+    ON ci_builds (codebase_id, trunk_commit_id);
@@ -100,10 +100,19 @@ func addRequires(in reflect.Type, to map[reflect.Type]bool) {
 
 	for i := 0; i < in.NumField(); i++ {
 		field := in.Field(i)
+		// value groups can be empty, so they are never missing
+		if isGroup(field) {
+			continue
+		}
 		addRequires(field.Type, to)
 	}
 }
 
+func isGroup(field reflect.StructField) bool {
+	_, ok := field.Tag.Lookup("group")
+	return ok
+}
+
 func (c *Container) requires() map[reflect.Type]bool {
 	c.requiresOnce.Do(func() {
 		result := map[reflect.Type]bool{}
@@ -138,6 +147,9 @@ func addProvides(in reflect.Type, to map[reflect.Type]bool) {
 
 	for i := 0; i < in.NumField(); i++ {
 		field := in.Field(i)
+		if isGroup(field) {
+			continue
+		}
 		addProvides(field.Type, to)
 	}
 }
@@ -194,3 +194,46 @@ func TestContainer_ImportDuplicateAlternativeImplementations(t *testing.T) {
 	err := Init(c3).To(&something)
 	assert.Error(t, err)
 }
+
+func TestContainer_Group(t *testing.T) {
+	type out struct {
+		Out
+		S string `group:"strings"`
+	}
+
+	type in struct {
+		In
+		Strings []string `group:"strings"`
+	}
+
+	c1 := func(c *Container) {
+		c.Register(func() out { return out{S: "a"} })
+	}
+	c2 := func(c *Container) {
+		c.Register(func() out { return out{S: "b"} })
+	}
+	c3 := func(c *Container) {
+		c.Import(c1)
+		c.Import(c2)
+		c.Register(func(i in) int { return len(i.Strings) })
+	}
+	var i int
+	if assert.NoError(t, Init(c3).To(&i)) {
+		assert.Equal(t, 2, i)
+	}
+}
+
+func TestContainer_Group_empty(t *testing.T) {
+	type in struct {
+		In
+		Strings []string `group:"strings"`
+	}
+
+	c := func(c *Container) {
+		c.Register(func(i in) int { return len(i.Strings) })
+	}
+	var i int
+	if assert.NoError(t, Init(c).To(&i)) {
+		assert.Equal(t, 0, i)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"getsturdy.com/api/pkg/graphql/resolvers"
 	"getsturdy.com/api/pkg/snapshots"
 	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
+	"getsturdy.com/api/pkg/trunks"
 	"getsturdy.com/api/pkg/workspaces"
 
 	"github.com/graph-gophers/graphql-go"
@@ -104,7 +105,7 @@ func (r *ContentsDownloadURLRootResolver) downloadChange(ctx context.Context, ch
 		return nil, gqlerrors.Error(err)
 	}
 
-	url, err := r.service.CreateArchive(ctx, allower, change.CodebaseID, "sturdytrunk", *change.CommitID, format)
+	url, err := r.service.CreateArchive(ctx, allower, change.CodebaseID, trunks.BranchName(nil), *change.CommitID, format)
 	if err != nil {
 		return nil, gqlerrors.Error(err)
 	}
@@ -12,6 +12,7 @@ import (
 	service_change "getsturdy.com/api/pkg/changes/service"
 	"getsturdy.com/api/pkg/codebases"
 	"getsturdy.com/api/pkg/downloads/enterprise/cloud/service/configuration"
+	"getsturdy.com/api/pkg/trunks"
 	"getsturdy.com/api/pkg/unidiff"
 	"getsturdy.com/api/vcs"
 	"getsturdy.com/api/vcs/executor"
@@ -118,8 +119,8 @@ func (svc *Service) CreateArchive(ctx context.Context, allower *unidiff.Allower,
 		})
 		return eg.Wait()
 	}).Write(func(writer vcs.RepoWriter) error {
-		if err := writer.CheckoutBranchWithForce("sturdytrunk"); err != nil {
-			return fmt.Errorf("failed to checkout sturdytrunk: %w", err)
+		if err := writer.CheckoutBranchWithForce(trunks.BranchName(nil)); err != nil {
+			return fmt.Errorf("failed to checkout trunk: %w", err)
 		}
 
 		if err := writer.DeleteBranch(archiveBranchName); err != nil {
@@ -127,7 +128,7 @@ func (svc *Service) CreateArchive(ctx context.Context, allower *unidiff.Allower,
 		}
 
 		return nil
-	}).ExecTemporaryView(codebaseID, "createArchive"); err != nil {
+	}).ExecTemporaryViewContext(ctx, codebaseID, "createArchive"); err != nil {
 		return "", fmt.Errorf("executor failed: %w", err)
 	}
 	// Get a pre signed URL
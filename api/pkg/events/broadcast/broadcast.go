@@ -0,0 +1,29 @@
+package broadcast
+
+import "context"
+
+// Broadcaster shares events between all instances of the API that are running against the same database. It's used by
+// the in-process event buses, so that subscribers on one instance receive the events that are published on another.
+type Broadcaster interface {
+	// Broadcast sends data to the subscribers of channel on all other instances.
+	Broadcast(ctx context.Context, channel string, data []byte) error
+	// Subscribe calls handler with the data that other instances broadcast to channel, until ctx is cancelled.
+	Subscribe(ctx context.Context, channel string, handler func(data []byte)) error
+}
+
+var _ Broadcaster = &noop{}
+
+type noop struct{}
+
+// NewNoop returns a Broadcaster for installations with a single instance of the API.
+func NewNoop() Broadcaster {
+	return &noop{}
+}
+
+func (*noop) Broadcast(context.Context, string, []byte) error {
+	return nil
+}
+
+func (*noop) Subscribe(context.Context, string, func([]byte)) error {
+	return nil
+}
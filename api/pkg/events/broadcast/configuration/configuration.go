@@ -0,0 +1,5 @@
+package configuration
+
+type Configuration struct {
+	Type string `long:"type" description:"Backend used to share events between multiple instances of the API" default:"inmemory" choice:"inmemory" choice:"postgres"`
+}
@@ -0,0 +1,34 @@
+package module
+
+import (
+	"getsturdy.com/api/pkg/db"
+	db_configuration "getsturdy.com/api/pkg/db/configuration"
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/events/broadcast"
+	"getsturdy.com/api/pkg/events/broadcast/configuration"
+	"getsturdy.com/api/pkg/events/broadcast/postgres"
+	"getsturdy.com/api/pkg/logger"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+func Module(c *di.Container) {
+	c.Import(logger.Module)
+	c.Import(db.Module)
+	c.Register(New)
+}
+
+func New(
+	logger *zap.Logger,
+	cfg *configuration.Configuration,
+	db *sqlx.DB,
+	dbConfiguration *db_configuration.Configuration,
+) broadcast.Broadcaster {
+	switch cfg.Type {
+	case "postgres":
+		return postgres.New(logger, db, dbConfiguration)
+	default:
+		return broadcast.NewNoop()
+	}
+}
@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	db_configuration "getsturdy.com/api/pkg/db/configuration"
+	"getsturdy.com/api/pkg/events/broadcast"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxNotifyPayloadSize is the largest payload that is sent with NOTIFY, Postgres has a limit of 8000 bytes.
+	// Larger payloads are stored in the event_payloads table, and only referenced in the notification.
+	maxNotifyPayloadSize = 7900
+	// payloadTTL is for how long payloads are kept in the event_payloads table.
+	payloadTTL = 5 * time.Minute
+)
+
+var _ broadcast.Broadcaster = &Broadcaster{}
+
+type envelope struct {
+	// Origin identifies the instance that sent the event.
+	Origin string `json:"origin"`
+	Data   []byte `json:"data,omitempty"`
+	// PayloadID is set instead of Data if the payload is stored in the event_payloads table.
+	PayloadID string `json:"payload_id,omitempty"`
+}
+
+// Broadcaster is a broadcast.Broadcaster that uses Postgres LISTEN/NOTIFY.
+//
+// Notifications are not persisted, events that are broadcasted while an instance is reconnecting to the database are
+// lost for that instance.
+type Broadcaster struct {
+	logger *zap.Logger
+	db     *sqlx.DB
+	dsn    string
+	origin string
+
+	mu       sync.Mutex
+	listener *pq.Listener
+	handlers map[string]map[string]func([]byte)
+}
+
+func New(logger *zap.Logger, db *sqlx.DB, dbConfiguration *db_configuration.Configuration) *Broadcaster {
+	return &Broadcaster{
+		logger:   logger.Named("postgresBroadcaster"),
+		db:       db,
+		dsn:      dbConfiguration.URL.String(),
+		origin:   uuid.NewString(),
+		handlers: map[string]map[string]func([]byte){},
+	}
+}
+
+func (b *Broadcaster) Broadcast(ctx context.Context, channel string, data []byte) error {
+	payload, err := json.Marshal(&envelope{Origin: b.origin, Data: data})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	if len(payload) > maxNotifyPayloadSize {
+		id := uuid.NewString()
+		if _, err := b.db.ExecContext(ctx, `
+			INSERT INTO event_payloads (id, data, created_at) VALUES ($1, $2, NOW())
+		`, id, data); err != nil {
+			return fmt.Errorf("failed to insert payload: %w", err)
+		}
+		if _, err := b.db.ExecContext(ctx, `
+			DELETE FROM event_payloads WHERE created_at < NOW() - MAKE_INTERVAL(secs => $1)
+		`, payloadTTL.Seconds()); err != nil {
+			b.logger.Error("failed to delete expired payloads", zap.Error(err))
+		}
+
+		payload, err = json.Marshal(&envelope{Origin: b.origin, PayloadID: id})
+		if err != nil {
+			return fmt.Errorf("failed to marshal payload: %w", err)
+		}
+	}
+
+	if _, err := b.db.ExecContext(ctx, `SELECT pg_notify($1, $2)`, channel, string(payload)); err != nil {
+		return fmt.Errorf("failed to notify: %w", err)
+	}
+	return nil
+}
+
+func (b *Broadcaster) Subscribe(ctx context.Context, channel string, handler func([]byte)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.listener == nil {
+		b.listener = pq.NewListener(b.dsn, 10*time.Millisecond, time.Minute, b.onListenerEvent)
+		go b.listen(b.listener)
+	}
+
+	if len(b.handlers[channel]) == 0 {
+		if err := b.listener.Listen(channel); err != nil && !errors.Is(err, pq.ErrChannelAlreadyOpen) {
+			return fmt.Errorf("failed to listen to %s: %w", channel, err)
+		}
+		b.handlers[channel] = map[string]func([]byte){}
+	}
+
+	id := uuid.NewString()
+	b.handlers[channel][id] = handler
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.handlers[channel], id)
+		b.mu.Unlock()
+	}()
+
+	return nil
+}
+
+func (b *Broadcaster) onListenerEvent(event pq.ListenerEventType, err error) {
+	switch event {
+	case pq.ListenerEventDisconnected:
+		b.logger.Warn("disconnected from database, events from other instances are lost until reconnected", zap.Error(err))
+	case pq.ListenerEventReconnected:
+		b.logger.Info("reconnected to database")
+	case pq.ListenerEventConnectionAttemptFailed:
+		b.logger.Error("failed to connect to database", zap.Error(err))
+	}
+}
+
+func (b *Broadcaster) listen(listener *pq.Listener) {
+	for notification := range listener.Notify {
+		// nil is sent after the connection has been re-established
+		if notification == nil {
+			continue
+		}
+
+		env := &envelope{}
+		if err := json.Unmarshal([]byte(notification.Extra), env); err != nil {
+			b.logger.Error("failed to unmarshal notification", zap.String("channel", notification.Channel), zap.Error(err))
+			continue
+		}
+
+		if env.Origin == b.origin {
+			continue
+		}
+
+		data := env.Data
+		if env.PayloadID != "" {
+			if err := b.db.Get(&data, `SELECT data FROM event_payloads WHERE id = $1`, env.PayloadID); err != nil {
+				b.logger.Error("failed to get payload", zap.String("payload_id", env.PayloadID), zap.Error(err))
+				continue
+			}
+		}
+
+		b.mu.Lock()
+		handlers := make([]func([]byte), 0, len(b.handlers[notification.Channel]))
+		for _, handler := range b.handlers[notification.Channel] {
+			handlers = append(handlers, handler)
+		}
+		b.mu.Unlock()
+
+		for _, handler := range handlers {
+			handler(data)
+		}
+	}
+}
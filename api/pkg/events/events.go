@@ -1,7 +1,10 @@
 package events
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"sync"
 
 	"github.com/google/uuid"
@@ -9,6 +12,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"go.uber.org/zap"
 
+	"getsturdy.com/api/pkg/events/broadcast"
 	"getsturdy.com/api/pkg/users"
 )
 
@@ -76,19 +80,34 @@ type inMemory struct {
 	subscribers map[Topic]map[string]CallbackFunc
 	q           chan payload
 
-	logger *zap.Logger
+	logger      *zap.Logger
+	broadcaster broadcast.Broadcaster
 }
 
-func NewInMemory(logger *zap.Logger) EventReadWriter {
+// broadcastChannel is the channel that events are shared on with other instances of the API.
+const broadcastChannel = "sturdy_events"
+
+type broadcastMessage struct {
+	Topic     Topic     `json:"topic"`
+	EventType EventType `json:"event_type"`
+	Reference string    `json:"reference"`
+}
+
+func NewInMemory(logger *zap.Logger, broadcaster broadcast.Broadcaster) (EventReadWriter, error) {
 	m := &inMemory{
 		subscribers: make(map[Topic]map[string]CallbackFunc),
 		q:           make(chan payload, 1024),
 		logger:      logger.Named("EventReadWriter"),
+		broadcaster: broadcaster,
+	}
+
+	if err := broadcaster.Subscribe(context.Background(), broadcastChannel, m.receive); err != nil {
+		return nil, fmt.Errorf("failed to subscribe to broadcasts: %w", err)
 	}
 
 	go m.work()
 
-	return m
+	return m, nil
 }
 
 func (i *inMemory) UserEvent(userID users.ID, eventType EventType, reference string) {
@@ -114,6 +133,25 @@ type payload struct {
 
 func (i *inMemory) event(topic Topic, eventType EventType, reference string) {
 	i.q <- payload{topic, eventType, reference}
+
+	data, err := json.Marshal(&broadcastMessage{Topic: topic, EventType: eventType, Reference: reference})
+	if err != nil {
+		i.logger.Error("failed to marshal event", zap.Error(err))
+		return
+	}
+	if err := i.broadcaster.Broadcast(context.Background(), broadcastChannel, data); err != nil {
+		i.logger.Error("failed to broadcast event", zap.Stringer("type", eventType), zap.Error(err))
+	}
+}
+
+// receive queues an event that was broadcasted by another instance of the API.
+func (i *inMemory) receive(data []byte) {
+	msg := &broadcastMessage{}
+	if err := json.Unmarshal(data, msg); err != nil {
+		i.logger.Error("failed to unmarshal broadcasted event", zap.Error(err))
+		return
+	}
+	i.q <- payload{msg.Topic, msg.EventType, msg.Reference}
 }
 
 var ErrClientDisconnected = errors.New("client disconnected")
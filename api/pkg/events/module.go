@@ -3,6 +3,7 @@ package events
 import (
 	db_codebases "getsturdy.com/api/pkg/codebases/db"
 	"getsturdy.com/api/pkg/di"
+	module_broadcast "getsturdy.com/api/pkg/events/broadcast/module"
 	"getsturdy.com/api/pkg/logger"
 	db_organizations "getsturdy.com/api/pkg/organization/db"
 	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
@@ -13,6 +14,7 @@ func Module(c *di.Container) {
 	c.Import(db_codebases.Module)
 	c.Import(db_organizations.Module)
 	c.Import(db_workspaces.Module)
+	c.Import(module_broadcast.Module)
 	c.Register(NewInMemory)
 	c.Register(NewSender)
 	c.Register(func(e EventReadWriter) EventReader {
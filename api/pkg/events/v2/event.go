@@ -1,8 +1,11 @@
 package events
 
 import (
+	"getsturdy.com/api/pkg/changes"
 	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/comments"
 	"getsturdy.com/api/pkg/github"
+	"getsturdy.com/api/pkg/mergequeue"
 	"getsturdy.com/api/pkg/notification"
 	"getsturdy.com/api/pkg/onboarding"
 	"getsturdy.com/api/pkg/organization"
@@ -39,6 +42,11 @@ const (
 	StatusUpdated
 	CompletedOnboardingStep
 	OrganizationUpdated
+	MergeQueueEntryUpdated
+	ChangeLanded
+	CommentUpdated
+
+	numTypes
 )
 
 func (t Type) String() string {
@@ -81,6 +89,12 @@ func (t Type) String() string {
 		return "WorkspaceWatchingStatusUpdated"
 	case OrganizationUpdated:
 		return "OrganizationUpdated"
+	case MergeQueueEntryUpdated:
+		return "MergeQueueEntryUpdated"
+	case ChangeLanded:
+		return "ChangeLanded"
+	case CommentUpdated:
+		return "CommentUpdated"
 	default:
 		return "Unknown"
 	}
@@ -89,6 +103,9 @@ func (t Type) String() string {
 type event struct {
 	Type Type
 
+	// CodebaseID is only set for events sent to the codebases topic.
+	CodebaseID codebases.ID
+
 	Codebase          *codebases.Codebase
 	View              *views.View
 	Workspace         *workspaces.Workspace
@@ -99,4 +116,42 @@ type event struct {
 	OnboardingStep    *onboarding.Step
 	WorkspaceWatcher  *watchers.Watcher
 	Organization      *organization.Organization
+	MergeQueueEntry   *mergequeue.Entry
+	Change            *changes.Change
+	Comment           *comments.Comment
+}
+
+// payload returns the object that the event is about.
+func (e *event) payload() any {
+	switch e.Type {
+	case CodebaseEvent, CodebaseUpdated:
+		return e.Codebase
+	case ViewUpdated, ViewStatusUpdated:
+		return e.View
+	case WorkspaceUpdated, WorkspaceUpdatedComments, WorkspaceUpdatedReviews, WorkspaceUpdatedActivity,
+		WorkspaceUpdatedSnapshot, WorkspaceUpdatedPresence, WorkspaceUpdatedSuggestion:
+		return e.Workspace
+	case WorkspaceWatchingStatusUpdated:
+		return e.WorkspaceWatcher
+	case ReviewUpdated:
+		return e.Review
+	case GitHubPRUpdated:
+		return e.GitHubPullRequest
+	case NotificationEvent:
+		return e.Notification
+	case StatusUpdated:
+		return e.Status
+	case CompletedOnboardingStep:
+		return e.OnboardingStep
+	case OrganizationUpdated:
+		return e.Organization
+	case MergeQueueEntryUpdated:
+		return e.MergeQueueEntry
+	case ChangeLanded:
+		return e.Change
+	case CommentUpdated:
+		return e.Comment
+	default:
+		return nil
+	}
 }
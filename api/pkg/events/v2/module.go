@@ -3,6 +3,7 @@ package events
 import (
 	db_codebases "getsturdy.com/api/pkg/codebases/db"
 	"getsturdy.com/api/pkg/di"
+	module_broadcast "getsturdy.com/api/pkg/events/broadcast/module"
 	"getsturdy.com/api/pkg/logger"
 	db_organization "getsturdy.com/api/pkg/organization/db"
 	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
@@ -13,6 +14,7 @@ func Module(c *di.Container) {
 	c.Import(db_codebases.Module)
 	c.Import(db_organization.Module)
 	c.Import(db_workspaces.Module)
+	c.Import(module_broadcast.Module)
 
 	c.Register(New)
 	c.Register(NewPublisher)
@@ -3,8 +3,11 @@ package events
 import (
 	"context"
 
+	"getsturdy.com/api/pkg/changes"
 	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/comments"
 	"getsturdy.com/api/pkg/github"
+	"getsturdy.com/api/pkg/mergequeue"
 	"getsturdy.com/api/pkg/notification"
 	"getsturdy.com/api/pkg/onboarding"
 	"getsturdy.com/api/pkg/organization"
@@ -41,254 +44,175 @@ func NewPublisher(
 	}
 }
 
-func (p *Publisher) CodebaseEvent(ctx context.Context, receiver *receiver, codebase *codebases.Codebase) error {
+// publish sends the event to all topics of the receiver. Events that are scoped to one or more codebases are also
+// sent to the codebases topic, see Subscriber.OnAnyInCodebase.
+func (p *Publisher) publish(ctx context.Context, receiver *receiver, evt *event) error {
 	topics, err := receiver.Topics(ctx, p.codebaseUserRepo, p.workspaceRepo, p.organizationMemberRepo)
 	if err != nil {
 		return err
 	}
 	for topic := range topics {
-		p.pubSub.pub(topic, &event{
-			Type:     CodebaseEvent,
-			Codebase: codebase,
-		})
+		p.pubSub.pub(topic, evt)
+	}
+
+	published := map[codebases.ID]bool{}
+	for _, codebaseID := range receiver.CodebaseIDs {
+		if published[codebaseID] {
+			continue
+		}
+		published[codebaseID] = true
+		codebaseEvt := *evt
+		codebaseEvt.CodebaseID = codebaseID
+		// codebase events are handled by background workers, deliver them only on this instance to not handle them
+		// once per instance.
+		p.pubSub.pubLocal(codebasesTopic, &codebaseEvt)
 	}
 	return nil
 }
 
+func (p *Publisher) CodebaseEvent(ctx context.Context, receiver *receiver, codebase *codebases.Codebase) error {
+	return p.publish(ctx, receiver, &event{
+		Type:     CodebaseEvent,
+		Codebase: codebase,
+	})
+}
+
 func (p *Publisher) CodebaseUpdated(ctx context.Context, receiver *receiver, codebase *codebases.Codebase) error {
-	topics, err := receiver.Topics(ctx, p.codebaseUserRepo, p.workspaceRepo, p.organizationMemberRepo)
-	if err != nil {
-		return err
-	}
-	for topic := range topics {
-		p.pubSub.pub(topic, &event{
-			Type:     CodebaseUpdated,
-			Codebase: codebase,
-		})
-	}
-	return nil
+	return p.publish(ctx, receiver, &event{
+		Type:     CodebaseUpdated,
+		Codebase: codebase,
+	})
 }
 
 func (p *Publisher) ViewUpdated(ctx context.Context, receiver *receiver, view *views.View) error {
-	topics, err := receiver.Topics(ctx, p.codebaseUserRepo, p.workspaceRepo, p.organizationMemberRepo)
-	if err != nil {
-		return err
-	}
-	for topic := range topics {
-		p.pubSub.pub(topic, &event{
-			Type: ViewUpdated,
-			View: view,
-		})
-	}
-	return nil
+	return p.publish(ctx, receiver, &event{
+		Type: ViewUpdated,
+		View: view,
+	})
 }
 
 func (p *Publisher) ViewStatusUpdated(ctx context.Context, receiver *receiver, view *views.View) error {
-	topics, err := receiver.Topics(ctx, p.codebaseUserRepo, p.workspaceRepo, p.organizationMemberRepo)
-	if err != nil {
-		return err
-	}
-	for topic := range topics {
-		p.pubSub.pub(topic, &event{
-			Type: ViewStatusUpdated,
-			View: view,
-		})
-	}
-	return nil
+	return p.publish(ctx, receiver, &event{
+		Type: ViewStatusUpdated,
+		View: view,
+	})
 }
 
 func (p *Publisher) WorkspaceUpdated(ctx context.Context, receiver *receiver, workspace *workspaces.Workspace) error {
-	topics, err := receiver.Topics(ctx, p.codebaseUserRepo, p.workspaceRepo, p.organizationMemberRepo)
-	if err != nil {
-		return err
-	}
-	for topic := range topics {
-		p.pubSub.pub(topic, &event{
-			Type:      WorkspaceUpdated,
-			Workspace: workspace,
-		})
-	}
-	return nil
+	return p.publish(ctx, receiver, &event{
+		Type:      WorkspaceUpdated,
+		Workspace: workspace,
+	})
 }
 
 func (p *Publisher) WorkspaceUpdatedComments(ctx context.Context, receiver *receiver, workspace *workspaces.Workspace) error {
-	topics, err := receiver.Topics(ctx, p.codebaseUserRepo, p.workspaceRepo, p.organizationMemberRepo)
-	if err != nil {
-		return err
-	}
-	for topic := range topics {
-		p.pubSub.pub(topic, &event{
-			Type:      WorkspaceUpdatedComments,
-			Workspace: workspace,
-		})
-	}
-	return nil
+	return p.publish(ctx, receiver, &event{
+		Type:      WorkspaceUpdatedComments,
+		Workspace: workspace,
+	})
 }
 
 func (p *Publisher) WorkspaceUpdatedReviews(ctx context.Context, receiver *receiver, workspace *workspaces.Workspace) error {
-	topics, err := receiver.Topics(ctx, p.codebaseUserRepo, p.workspaceRepo, p.organizationMemberRepo)
-	if err != nil {
-		return err
-	}
-	for topic := range topics {
-		p.pubSub.pub(topic, &event{
-			Type:      WorkspaceUpdatedReviews,
-			Workspace: workspace,
-		})
-	}
-	return nil
+	return p.publish(ctx, receiver, &event{
+		Type:      WorkspaceUpdatedReviews,
+		Workspace: workspace,
+	})
 }
 
 func (p *Publisher) WorkspaceUpdatedActivity(ctx context.Context, receiver *receiver, workspace *workspaces.Workspace) error {
-	topics, err := receiver.Topics(ctx, p.codebaseUserRepo, p.workspaceRepo, p.organizationMemberRepo)
-	if err != nil {
-		return err
-	}
-	for topic := range topics {
-		p.pubSub.pub(topic, &event{
-			Type:      WorkspaceUpdatedActivity,
-			Workspace: workspace,
-		})
-	}
-	return nil
+	return p.publish(ctx, receiver, &event{
+		Type:      WorkspaceUpdatedActivity,
+		Workspace: workspace,
+	})
 }
 
 func (p *Publisher) WorkspaceUpdatedSnapshot(ctx context.Context, receiver *receiver, workspace *workspaces.Workspace) error {
-	topics, err := receiver.Topics(ctx, p.codebaseUserRepo, p.workspaceRepo, p.organizationMemberRepo)
-	if err != nil {
-		return err
-	}
-	for topic := range topics {
-		p.pubSub.pub(topic, &event{
-			Type:      WorkspaceUpdatedSnapshot,
-			Workspace: workspace,
-		})
-	}
-	return nil
+	return p.publish(ctx, receiver, &event{
+		Type:      WorkspaceUpdatedSnapshot,
+		Workspace: workspace,
+	})
 }
 
 func (p *Publisher) WorkspaceUpdatedPresence(ctx context.Context, receiver *receiver, workspace *workspaces.Workspace) error {
-	topics, err := receiver.Topics(ctx, p.codebaseUserRepo, p.workspaceRepo, p.organizationMemberRepo)
-	if err != nil {
-		return err
-	}
-	for topic := range topics {
-		p.pubSub.pub(topic, &event{
-			Type:      WorkspaceUpdatedPresence,
-			Workspace: workspace,
-		})
-	}
-	return nil
+	return p.publish(ctx, receiver, &event{
+		Type:      WorkspaceUpdatedPresence,
+		Workspace: workspace,
+	})
 }
 
 func (p *Publisher) WorkspaceUpdatedSuggestion(ctx context.Context, receiver *receiver, workspace *workspaces.Workspace) error {
-	topics, err := receiver.Topics(ctx, p.codebaseUserRepo, p.workspaceRepo, p.organizationMemberRepo)
-	if err != nil {
-		return err
-	}
-	for topic := range topics {
-		p.pubSub.pub(topic, &event{
-			Type:      WorkspaceUpdatedSuggestion,
-			Workspace: workspace,
-		})
-	}
-	return nil
+	return p.publish(ctx, receiver, &event{
+		Type:      WorkspaceUpdatedSuggestion,
+		Workspace: workspace,
+	})
 }
 
 func (p *Publisher) WorkspaceWatchingStatusUpdated(ctx context.Context, receiver *receiver, watcher *watchers.Watcher) error {
-	topics, err := receiver.Topics(ctx, p.codebaseUserRepo, p.workspaceRepo, p.organizationMemberRepo)
-	if err != nil {
-		return err
-	}
-	for topic := range topics {
-		p.pubSub.pub(topic, &event{
-			Type:             WorkspaceWatchingStatusUpdated,
-			WorkspaceWatcher: watcher,
-		})
-	}
-	return nil
+	return p.publish(ctx, receiver, &event{
+		Type:             WorkspaceWatchingStatusUpdated,
+		WorkspaceWatcher: watcher,
+	})
 }
 
 func (p *Publisher) ReviewUpdated(ctx context.Context, receiver *receiver, review *review.Review) error {
-	topics, err := receiver.Topics(ctx, p.codebaseUserRepo, p.workspaceRepo, p.organizationMemberRepo)
-	if err != nil {
-		return err
-	}
-	for topic := range topics {
-		p.pubSub.pub(topic, &event{
-			Type:   ReviewUpdated,
-			Review: review,
-		})
-	}
-	return nil
+	return p.publish(ctx, receiver, &event{
+		Type:   ReviewUpdated,
+		Review: review,
+	})
 }
 
 func (p *Publisher) GitHubPRUpdated(ctx context.Context, receiver *receiver, pr *github.PullRequest) error {
-	topics, err := receiver.Topics(ctx, p.codebaseUserRepo, p.workspaceRepo, p.organizationMemberRepo)
-	if err != nil {
-		return err
-	}
-	for topic := range topics {
-		p.pubSub.pub(topic, &event{
-			Type:              GitHubPRUpdated,
-			GitHubPullRequest: pr,
-		})
-	}
-	return nil
+	return p.publish(ctx, receiver, &event{
+		Type:              GitHubPRUpdated,
+		GitHubPullRequest: pr,
+	})
 }
 
 func (p *Publisher) NotificationEvent(ctx context.Context, receiver *receiver, notification *notification.Notification) error {
-	topics, err := receiver.Topics(ctx, p.codebaseUserRepo, p.workspaceRepo, p.organizationMemberRepo)
-	if err != nil {
-		return err
-	}
-	for topic := range topics {
-		p.pubSub.pub(topic, &event{
-			Type:         NotificationEvent,
-			Notification: notification,
-		})
-	}
-	return nil
+	return p.publish(ctx, receiver, &event{
+		Type:         NotificationEvent,
+		Notification: notification,
+	})
 }
 
 func (p *Publisher) StatusUpdated(ctx context.Context, receiver *receiver, status *statuses.Status) error {
-	topics, err := receiver.Topics(ctx, p.codebaseUserRepo, p.workspaceRepo, p.organizationMemberRepo)
-	if err != nil {
-		return err
-	}
-	for topic := range topics {
-		p.pubSub.pub(topic, &event{
-			Type:   StatusUpdated,
-			Status: status,
-		})
-	}
-	return nil
+	return p.publish(ctx, receiver, &event{
+		Type:   StatusUpdated,
+		Status: status,
+	})
 }
 
 func (p *Publisher) CompletedOnboardingStep(ctx context.Context, receiver *receiver, step *onboarding.Step) error {
-	topics, err := receiver.Topics(ctx, p.codebaseUserRepo, p.workspaceRepo, p.organizationMemberRepo)
-	if err != nil {
-		return err
-	}
-	for topic := range topics {
-		p.pubSub.pub(topic, &event{
-			Type:           CompletedOnboardingStep,
-			OnboardingStep: step,
-		})
-	}
-	return nil
+	return p.publish(ctx, receiver, &event{
+		Type:           CompletedOnboardingStep,
+		OnboardingStep: step,
+	})
 }
 
 func (p *Publisher) OrganizationUpdated(ctx context.Context, receiver *receiver, organization *organization.Organization) error {
-	topics, err := receiver.Topics(ctx, p.codebaseUserRepo, p.workspaceRepo, p.organizationMemberRepo)
-	if err != nil {
-		return err
-	}
-	for topic := range topics {
-		p.pubSub.pub(topic, &event{
-			Type:         OrganizationUpdated,
-			Organization: organization,
-		})
-	}
-	return nil
+	return p.publish(ctx, receiver, &event{
+		Type:         OrganizationUpdated,
+		Organization: organization,
+	})
+}
+
+func (p *Publisher) ChangeLanded(ctx context.Context, receiver *receiver, change *changes.Change) error {
+	return p.publish(ctx, receiver, &event{
+		Type:   ChangeLanded,
+		Change: change,
+	})
+}
+
+func (p *Publisher) CommentUpdated(ctx context.Context, receiver *receiver, comment *comments.Comment) error {
+	return p.publish(ctx, receiver, &event{
+		Type:    CommentUpdated,
+		Comment: comment,
+	})
+}
+
+func (p *Publisher) MergeQueueEntryUpdated(ctx context.Context, receiver *receiver, entry *mergequeue.Entry) error {
+	return p.publish(ctx, receiver, &event{
+		Type:            MergeQueueEntryUpdated,
+		MergeQueueEntry: entry,
+	})
 }
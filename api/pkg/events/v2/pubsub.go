@@ -1,15 +1,28 @@
 package events
 
 import (
+	"bytes"
 	"context"
+	"encoding/gob"
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
+	"getsturdy.com/api/pkg/events/broadcast"
+
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+// broadcastChannel is the channel that events are shared on with other instances of the API.
+const broadcastChannel = "sturdy_events_v2"
+
+type broadcastMessage struct {
+	Topic Topic
+	Event *event
+}
+
 type subscriber struct {
 	ctx      context.Context
 	callback callback
@@ -20,21 +33,52 @@ type callback func(context.Context, *event) error
 type subscriptionID string
 
 type pubSub struct {
-	logger *zap.Logger
+	logger      *zap.Logger
+	broadcaster broadcast.Broadcaster
 
 	subscribersGuard *sync.RWMutex
 	subscribers      map[Topic]map[Type]map[subscriptionID]subscriber
 }
 
-func New(logger *zap.Logger) *pubSub {
-	return &pubSub{
+func New(logger *zap.Logger, broadcaster broadcast.Broadcaster) (*pubSub, error) {
+	ps := &pubSub{
 		logger:           logger.Named("events_pubsub"),
+		broadcaster:      broadcaster,
 		subscribersGuard: &sync.RWMutex{},
 		subscribers:      map[Topic]map[Type]map[subscriptionID]subscriber{},
 	}
+	if err := broadcaster.Subscribe(context.Background(), broadcastChannel, ps.receive); err != nil {
+		return nil, fmt.Errorf("failed to subscribe to broadcasts: %w", err)
+	}
+	return ps, nil
 }
 
+// pub publishes the event to the subscribers of topic on all instances of the API.
 func (r *pubSub) pub(topic Topic, evt *event) {
+	r.pubLocal(topic, evt)
+
+	buf := &bytes.Buffer{}
+	if err := gob.NewEncoder(buf).Encode(&broadcastMessage{Topic: topic, Event: evt}); err != nil {
+		r.logger.Error("failed to encode event", zap.Stringer("topic", topic), zap.Stringer("type", evt.Type), zap.Error(err))
+		return
+	}
+	if err := r.broadcaster.Broadcast(context.Background(), broadcastChannel, buf.Bytes()); err != nil {
+		r.logger.Error("failed to broadcast event", zap.Stringer("topic", topic), zap.Stringer("type", evt.Type), zap.Error(err))
+	}
+}
+
+// receive publishes an event that was broadcasted by another instance of the API.
+func (r *pubSub) receive(data []byte) {
+	msg := &broadcastMessage{}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(msg); err != nil {
+		r.logger.Error("failed to decode broadcasted event", zap.Error(err))
+		return
+	}
+	r.pubLocal(msg.Topic, msg.Event)
+}
+
+// pubLocal publishes the event to the subscribers of topic on this instance only.
+func (r *pubSub) pubLocal(topic Topic, evt *event) {
 	r.subscribersGuard.RLock()
 	handlers := r.subscribers[topic][evt.Type]
 	r.subscribersGuard.RUnlock()
@@ -0,0 +1,82 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/events/broadcast"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+var _ broadcast.Broadcaster = &network{}
+
+// network is a broadcast.Broadcaster that connects all pubSubs that are created with it.
+type network struct {
+	mu       sync.Mutex
+	handlers []func([]byte)
+}
+
+func (n *network) Broadcast(_ context.Context, _ string, data []byte) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	// skip the sender, it's always subscribed first in these tests
+	for _, handler := range n.handlers[1:] {
+		handler(data)
+	}
+	return nil
+}
+
+func (n *network) Subscribe(_ context.Context, _ string, handler func([]byte)) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.handlers = append(n.handlers, handler)
+	return nil
+}
+
+func TestPubSub_broadcast(t *testing.T) {
+	net := &network{}
+
+	publisher, err := New(zap.NewNop(), net)
+	assert.NoError(t, err)
+	subscriber, err := New(zap.NewNop(), net)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan *event, 2)
+	subscriber.sub(ctx, func(_ context.Context, evt *event) error {
+		received <- evt
+		return nil
+	}, userTopic("user"), CodebaseUpdated)
+	subscriber.sub(ctx, func(_ context.Context, evt *event) error {
+		received <- evt
+		return nil
+	}, codebasesTopic, CodebaseUpdated)
+
+	publisher.pub(userTopic("user"), &event{
+		Type:     CodebaseUpdated,
+		Codebase: &codebases.Codebase{ID: "codebase", Name: "name"},
+	})
+	publisher.pubLocal(codebasesTopic, &event{Type: CodebaseUpdated, CodebaseID: "codebase"})
+
+	select {
+	case evt := <-received:
+		assert.Equal(t, CodebaseUpdated, evt.Type)
+		assert.Equal(t, codebases.ID("codebase"), evt.Codebase.ID)
+		assert.Equal(t, "name", evt.Codebase.Name)
+	case <-time.After(time.Second):
+		t.Fatal("event was not received")
+	}
+
+	select {
+	case evt := <-received:
+		t.Fatalf("unexpected event: %+v", evt)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -6,8 +6,11 @@ import (
 	"reflect"
 	"runtime"
 
+	"getsturdy.com/api/pkg/changes"
 	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/comments"
 	"getsturdy.com/api/pkg/github"
+	"getsturdy.com/api/pkg/mergequeue"
 	"getsturdy.com/api/pkg/notification"
 	"getsturdy.com/api/pkg/onboarding"
 	"getsturdy.com/api/pkg/organization"
@@ -143,6 +146,41 @@ func (s *Subscriber) OnOrganizationUpdated(ctx context.Context, topic Topic, cal
 	}, topic, OrganizationUpdated)
 }
 
+func (s *Subscriber) OnMergeQueueEntryUpdated(ctx context.Context, topic Topic, callback func(context.Context, *mergequeue.Entry) error) {
+	s.pubsub.sub(ctx, func(ctx context.Context, event *event) error {
+		return callbackWithError(ctx, event.MergeQueueEntry, callback)
+	}, topic, MergeQueueEntryUpdated)
+}
+
+func (s *Subscriber) OnChangeLanded(ctx context.Context, topic Topic, callback func(context.Context, *changes.Change) error) {
+	s.pubsub.sub(ctx, func(ctx context.Context, event *event) error {
+		return callbackWithError(ctx, event.Change, callback)
+	}, topic, ChangeLanded)
+}
+
+func (s *Subscriber) OnCommentUpdated(ctx context.Context, topic Topic, callback func(context.Context, *comments.Comment) error) {
+	s.pubsub.sub(ctx, func(ctx context.Context, event *event) error {
+		return callbackWithError(ctx, event.Comment, callback)
+	}, topic, CommentUpdated)
+}
+
+// OnAnyInCodebase subscribes to events of all types, in all codebases. The callback is invoked with the codebase the
+// event happened in, and the object that the event is about, for example a *review.Review for ReviewUpdated events.
+//
+// Only events published in this process are received.
+func (s *Subscriber) OnAnyInCodebase(ctx context.Context, callback func(ctx context.Context, codebaseID codebases.ID, t Type, payload any) error) {
+	tt := make([]Type, 0, numTypes)
+	for t := TypeUndefined + 1; t < numTypes; t++ {
+		tt = append(tt, t)
+	}
+	s.pubsub.sub(ctx, func(ctx context.Context, event *event) error {
+		if err := callback(ctx, event.CodebaseID, event.Type, event.payload()); err != nil {
+			return fmt.Errorf("%s: %w", functionName(callback), err)
+		}
+		return nil
+	}, codebasesTopic, tt...)
+}
+
 func callbackWithError[T any](ctx context.Context, value T, callback func(context.Context, T) error) error {
 	if err := callback(ctx, value); err != nil {
 		return fmt.Errorf("%s: %w", functionName(callback), err)
@@ -8,6 +8,9 @@ import (
 
 type Topic string
 
+// codebasesTopic receives all events that are scoped to a codebase, regardless of who the receivers are.
+const codebasesTopic Topic = "codebases"
+
 func (t Topic) String() string {
 	return string(t)
 }
@@ -0,0 +1,385 @@
+package exports
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Version is the version of the archive format. Archives with a newer version can not be imported.
+const Version = 1
+
+// BundleName is the name of the git bundle with the trunk repository in the archive.
+const BundleName = "trunk.bundle"
+
+var (
+	ErrInvalidArchive     = errors.New("invalid archive")
+	ErrUnsupportedVersion = errors.New("unsupported archive version")
+)
+
+// Archive is everything that is exported from a codebase, except for the git bundle. User ids in an archive are the ids
+// on the instance that the codebase was exported from, and are mapped to users on the importing instance by email.
+type Archive struct {
+	Manifest   Manifest
+	Codebase   Codebase
+	Users      []User
+	Members    []Member
+	Trunks     []Trunk
+	Workspaces []Workspace
+	Changes    []Change
+	Comments   []Comment
+	Reviews    []Review
+	Statuses   []Status
+	ACL        *ACL
+}
+
+type Manifest struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+}
+
+type Codebase struct {
+	ID                        string     `json:"id"`
+	ShortID                   string     `json:"short_id"`
+	Name                      string     `json:"name"`
+	Description               string     `json:"description"`
+	Emoji                     string     `json:"emoji"`
+	IsPublic                  bool       `json:"is_public"`
+	RequireHealthyStatus      bool       `json:"require_healthy_status"`
+	MergeQueueEnabled         bool       `json:"merge_queue_enabled"`
+	RequireCodeOwnersApproval bool       `json:"require_code_owners_approval"`
+	CreatedAt                 *time.Time `json:"created_at"`
+}
+
+type User struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type Member struct {
+	UserID    string     `json:"user_id"`
+	InvitedBy *string    `json:"invited_by"`
+	CreatedAt *time.Time `json:"created_at"`
+}
+
+type Trunk struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	TrackedBranch *string   `json:"tracked_branch"`
+	CreatedBy     string    `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type Workspace struct {
+	ID                string     `json:"id"`
+	UserID            string     `json:"user_id"`
+	Name              *string    `json:"name"`
+	DraftDescription  string     `json:"draft_description"`
+	TrunkID           *string    `json:"trunk_id"`
+	ParentWorkspaceID *string    `json:"parent_workspace_id"`
+	ChangeID          *string    `json:"change_id"`
+	CreatedAt         *time.Time `json:"created_at"`
+	UpdatedAt         *time.Time `json:"updated_at"`
+	LastLandedAt      *time.Time `json:"last_landed_at"`
+	ArchivedAt        *time.Time `json:"archived_at"`
+	// LatestSnapshot is nil if the workspace has never been snapshotted. The commit of the snapshot is in the bundle.
+	LatestSnapshot *Snapshot `json:"latest_snapshot"`
+}
+
+type Snapshot struct {
+	ID         string    `json:"id"`
+	CommitID   string    `json:"commit_id"`
+	Action     string    `json:"action"`
+	DiffsCount *int32    `json:"diffs_count"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type Change struct {
+	ID              string     `json:"id"`
+	Title           *string    `json:"title"`
+	Description     string     `json:"description"`
+	UserID          *string    `json:"user_id"`
+	WorkspaceID     *string    `json:"workspace_id"`
+	CommitID        *string    `json:"commit_id"`
+	ParentChangeID  *string    `json:"parent_change_id"`
+	CreatedAt       *time.Time `json:"created_at"`
+	GitCreatedAt    *time.Time `json:"git_created_at"`
+	GitCreatorName  *string    `json:"git_creator_name"`
+	GitCreatorEmail *string    `json:"git_creator_email"`
+}
+
+type Comment struct {
+	ID              string     `json:"id"`
+	ChangeID        *string    `json:"change_id"`
+	WorkspaceID     *string    `json:"workspace_id"`
+	ParentCommentID *string    `json:"parent_comment_id"`
+	UserID          string     `json:"user_id"`
+	Message         string     `json:"message"`
+	CreatedAt       time.Time  `json:"created_at"`
+	ResolvedAt      *time.Time `json:"resolved_at"`
+	ResolvedBy      *string    `json:"resolved_by"`
+
+	// The lines that the comment is anchored to, empty for comments that are not on a file.
+	Path                string  `json:"path"`
+	OldPath             *string `json:"old_path"`
+	LineStart           int     `json:"line_start"`
+	LineEnd             int     `json:"line_end"`
+	LineIsNew           bool    `json:"line_is_new"`
+	Context             *string `json:"context"`
+	ContextStartsAtLine *int    `json:"context_starts_at_line"`
+}
+
+type Review struct {
+	ID          string    `json:"id"`
+	WorkspaceID string    `json:"workspace_id"`
+	UserID      string    `json:"user_id"`
+	Grade       string    `json:"grade"`
+	RequestedBy *string   `json:"requested_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type Status struct {
+	ID          string    `json:"id"`
+	CommitID    string    `json:"commit_id"`
+	Type        string    `json:"type"`
+	Title       string    `json:"title"`
+	Description *string   `json:"description"`
+	DetailsURL  *string   `json:"details_url"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+type ACL struct {
+	ID        string    `json:"id"`
+	Policy    string    `json:"policy"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// files maps the files in the archive to the parts of the Archive that are stored in them.
+func (a *Archive) files() []struct {
+	name  string
+	value any
+} {
+	return []struct {
+		name  string
+		value any
+	}{
+		{"manifest.json", &a.Manifest},
+		{"codebase.json", &a.Codebase},
+		{"users.json", &a.Users},
+		{"members.json", &a.Members},
+		{"trunks.json", &a.Trunks},
+		{"workspaces.json", &a.Workspaces},
+		{"changes.json", &a.Changes},
+		{"comments.json", &a.Comments},
+		{"reviews.json", &a.Reviews},
+		{"statuses.json", &a.Statuses},
+		{"acl.json", &a.ACL},
+	}
+}
+
+// Write writes the archive, and the git bundle at bundlePath, to w as a gzipped tar.
+func Write(w io.Writer, archive *Archive, bundlePath string) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	for _, file := range archive.files() {
+		data, err := json.MarshalIndent(file.value, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", file.name, err)
+		}
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    file.name,
+			Mode:    0o644,
+			Size:    int64(len(data)),
+			ModTime: archive.Manifest.ExportedAt,
+		}); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+
+	if err := writeFile(tw, BundleName, bundlePath, archive.Manifest.ExportedAt); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to close tar: %w", err)
+	}
+	if err := gw.Close(); err != nil {
+		return fmt.Errorf("failed to close gzip: %w", err)
+	}
+	return nil
+}
+
+func writeFile(tw *tar.Writer, name, path string, modTime time.Time) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", name, err)
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    info.Size(),
+		ModTime: modTime,
+	}); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := io.Copy(tw, f); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// Read reads an archive that was written with Write. The git bundle is extracted into dir, and its path is returned.
+func Read(r io.Reader, dir string) (*Archive, string, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open gzip: %w", err)
+	}
+	defer gr.Close()
+
+	archive := &Archive{}
+	values := make(map[string]any)
+	for _, file := range archive.files() {
+		values[file.name] = file.value
+	}
+
+	var bundlePath string
+	seen := make(map[string]bool)
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read tar: %w", err)
+		}
+
+		if header.Name == BundleName {
+			bundlePath = filepath.Join(dir, BundleName)
+			if err := extractFile(tr, bundlePath); err != nil {
+				return nil, "", err
+			}
+			continue
+		}
+
+		value, ok := values[header.Name]
+		if !ok {
+			// unknown files are ignored
+			continue
+		}
+		if err := json.NewDecoder(tr).Decode(value); err != nil {
+			return nil, "", fmt.Errorf("failed to decode %s: %w", header.Name, err)
+		}
+		seen[header.Name] = true
+
+		if header.Name == "manifest.json" && archive.Manifest.Version > Version {
+			return nil, "", fmt.Errorf("%w: version %d", ErrUnsupportedVersion, archive.Manifest.Version)
+		}
+	}
+
+	if !seen["manifest.json"] || !seen["codebase.json"] {
+		return nil, "", fmt.Errorf("%w: missing manifest or codebase", ErrInvalidArchive)
+	}
+	if bundlePath == "" {
+		return nil, "", fmt.Errorf("%w: missing %s", ErrInvalidArchive, BundleName)
+	}
+	if err := archive.validateReferences(); err != nil {
+		return nil, "", err
+	}
+	return archive, bundlePath, nil
+}
+
+// validateReferences makes sure that everything in the archive only references trunks, workspaces, changes and
+// comments that are in the archive. Without it, an archive could attach comments or reviews to workspaces in other
+// codebases on the importing instance.
+func (a *Archive) validateReferences() error {
+	trunkIDs := make(map[string]bool, len(a.Trunks))
+	for _, t := range a.Trunks {
+		trunkIDs[t.ID] = true
+	}
+	workspaceIDs := make(map[string]bool, len(a.Workspaces))
+	for _, ws := range a.Workspaces {
+		workspaceIDs[ws.ID] = true
+	}
+	changeIDs := make(map[string]bool, len(a.Changes))
+	for _, ch := range a.Changes {
+		changeIDs[ch.ID] = true
+	}
+	commentIDs := make(map[string]bool, len(a.Comments))
+	for _, c := range a.Comments {
+		commentIDs[c.ID] = true
+	}
+
+	check := func(ids map[string]bool, id *string, what string) error {
+		if id != nil && !ids[*id] {
+			return fmt.Errorf("%w: %s %s is not in the archive", ErrInvalidArchive, what, *id)
+		}
+		return nil
+	}
+
+	for _, ws := range a.Workspaces {
+		if err := check(trunkIDs, ws.TrunkID, "trunk"); err != nil {
+			return err
+		}
+		if err := check(workspaceIDs, ws.ParentWorkspaceID, "workspace"); err != nil {
+			return err
+		}
+		if err := check(changeIDs, ws.ChangeID, "change"); err != nil {
+			return err
+		}
+	}
+	for _, ch := range a.Changes {
+		if err := check(workspaceIDs, ch.WorkspaceID, "workspace"); err != nil {
+			return err
+		}
+		if err := check(changeIDs, ch.ParentChangeID, "change"); err != nil {
+			return err
+		}
+	}
+	for _, c := range a.Comments {
+		if err := check(workspaceIDs, c.WorkspaceID, "workspace"); err != nil {
+			return err
+		}
+		if err := check(changeIDs, c.ChangeID, "change"); err != nil {
+			return err
+		}
+		if err := check(commentIDs, c.ParentCommentID, "comment"); err != nil {
+			return err
+		}
+	}
+	for _, r := range a.Reviews {
+		if err := check(workspaceIDs, &r.WorkspaceID, "workspace"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractFile(r io.Reader, path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return f.Close()
+}
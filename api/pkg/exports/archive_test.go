@@ -0,0 +1,106 @@
+package exports
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteRead(t *testing.T) {
+	bundlePath := filepath.Join(t.TempDir(), BundleName)
+	assert.NoError(t, os.WriteFile(bundlePath, []byte("# v2 git bundle\n"), 0o644))
+
+	title := "Add a README"
+	changeID := "change"
+	exportedAt := time.Now().UTC().Truncate(time.Second)
+	archive := &Archive{
+		Manifest: Manifest{Version: Version, ExportedAt: exportedAt},
+		Codebase: Codebase{ID: "codebase", ShortID: "short", Name: "Codebase"},
+		Users:    []User{{ID: "user", Name: "User", Email: "user@getsturdy.com"}},
+		Members:  []Member{{UserID: "user"}},
+		Workspaces: []Workspace{{
+			ID:             "workspace",
+			UserID:         "user",
+			ChangeID:       &changeID,
+			LatestSnapshot: &Snapshot{ID: "snapshot", CommitID: "abc", Action: "view_sync", CreatedAt: exportedAt},
+		}},
+		Changes:  []Change{{ID: changeID, Title: &title, Description: "<p>Add a README</p>"}},
+		Comments: []Comment{{ID: "comment", ChangeID: &changeID, UserID: "user", Path: "README.md", LineStart: 1, LineEnd: 2, LineIsNew: true, CreatedAt: exportedAt}},
+		Reviews:  []Review{{ID: "review", WorkspaceID: "workspace", UserID: "user", Grade: "Approve", CreatedAt: exportedAt}},
+		Statuses: []Status{{ID: "status", CommitID: "abc", Type: "healthy", Title: "ci", Timestamp: exportedAt}},
+		ACL:      &ACL{ID: "acl", Policy: "{}", CreatedAt: exportedAt},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, archive, bundlePath))
+
+	dir := t.TempDir()
+	read, readBundlePath, err := Read(&buf, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, archive, read)
+	assert.Equal(t, filepath.Join(dir, BundleName), readBundlePath)
+
+	bundle, err := os.ReadFile(readBundlePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# v2 git bundle\n", string(bundle))
+}
+
+func TestRead_invalid(t *testing.T) {
+	write := func(files map[string]string) *bytes.Buffer {
+		var buf bytes.Buffer
+		gw := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gw)
+		for name, content := range files {
+			assert.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))}))
+			_, err := tw.Write([]byte(content))
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, tw.Close())
+		assert.NoError(t, gw.Close())
+		return &buf
+	}
+
+	_, _, err := Read(write(map[string]string{
+		"manifest.json": `{"version": 1}`,
+		"codebase.json": `{"id": "codebase"}`,
+	}), t.TempDir())
+	assert.ErrorIs(t, err, ErrInvalidArchive)
+
+	_, _, err = Read(write(map[string]string{
+		"manifest.json": `{"version": 2}`,
+	}), t.TempDir())
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+}
+
+func TestRead_foreignReferences(t *testing.T) {
+	bundlePath := filepath.Join(t.TempDir(), BundleName)
+	assert.NoError(t, os.WriteFile(bundlePath, []byte("# v2 git bundle\n"), 0o644))
+
+	foreign := "workspace-in-another-codebase"
+	foreignChange := "change-in-another-codebase"
+	archives := map[string]*Archive{
+		"review":               {Reviews: []Review{{ID: "review", WorkspaceID: foreign}}},
+		"comment on workspace": {Comments: []Comment{{ID: "comment", WorkspaceID: &foreign}}},
+		"comment on change":    {Comments: []Comment{{ID: "comment", ChangeID: &foreignChange}}},
+		"change":               {Changes: []Change{{ID: "change", WorkspaceID: &foreign}}},
+		"parent workspace":     {Workspaces: []Workspace{{ID: "workspace", ParentWorkspaceID: &foreign}}},
+	}
+	for name, archive := range archives {
+		t.Run(name, func(t *testing.T) {
+			archive.Manifest = Manifest{Version: Version}
+			archive.Codebase = Codebase{ID: "codebase"}
+
+			var buf bytes.Buffer
+			assert.NoError(t, Write(&buf, archive, bundlePath))
+
+			_, _, err := Read(&buf, t.TempDir())
+			assert.ErrorIs(t, err, ErrInvalidArchive)
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	service_codebase "getsturdy.com/api/pkg/codebases/service"
+	"getsturdy.com/api/pkg/di"
+	service_exports "getsturdy.com/api/pkg/exports/service"
+	"getsturdy.com/api/pkg/logger"
+	service_organization "getsturdy.com/api/pkg/organization/service"
+)
+
+func Module(c *di.Container) {
+	c.Import(logger.Module)
+	c.Import(service_codebase.Module)
+	c.Import(service_auth.Module)
+	c.Import(service_organization.Module)
+	c.Import(service_exports.Module)
+	c.Register(NewExportRoute)
+	c.Register(NewImportRoute)
+}
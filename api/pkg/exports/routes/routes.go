@@ -0,0 +1,108 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"getsturdy.com/api/pkg/auth"
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/codebases/acl"
+	service_codebase "getsturdy.com/api/pkg/codebases/service"
+	"getsturdy.com/api/pkg/exports"
+	service_exports "getsturdy.com/api/pkg/exports/service"
+	service_organization "getsturdy.com/api/pkg/organization/service"
+)
+
+type ExportRoute func(*gin.Context)
+
+// NewExportRoute streams an archive of the codebase. The archive contains all workspaces, comments and files of the
+// codebase, regardless of its ACL policy, so only codebase admins can export it.
+func NewExportRoute(
+	logger *zap.Logger,
+	codebaseService *service_codebase.Service,
+	authService *service_auth.Service,
+	exportsService *service_exports.Service,
+) ExportRoute {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		cb, err := codebaseService.GetByID(ctx, codebases.ID(c.Param("id")))
+		if err != nil {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+
+		if err := authService.CanRead(ctx, cb); err != nil {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+
+		if err := authService.CanPerform(ctx, cb.ID, acl.ActionAdmin, acl.Identity{Type: acl.Codebases, ID: cb.ID.String()}); err != nil {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+
+		c.Header("Content-Type", "application/gzip")
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("%s.tar.gz", cb.ShortCodebaseID)))
+		c.Status(http.StatusOK)
+
+		if err := exportsService.Export(ctx, cb.ID, c.Writer); err != nil {
+			// the response has already started, so the client sees a truncated archive
+			logger.Error("failed to export codebase", zap.Stringer("codebase_id", cb.ID), zap.Error(err))
+			return
+		}
+	}
+}
+
+type ImportRoute func(*gin.Context)
+
+// NewImportRoute creates a codebase from an archive in the request body. If the organization_id query parameter is
+// set, the codebase is created in that organization.
+func NewImportRoute(
+	logger *zap.Logger,
+	authService *service_auth.Service,
+	organizationService *service_organization.Service,
+	exportsService *service_exports.Service,
+) ImportRoute {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		userID, err := auth.UserID(ctx)
+		if err != nil {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		var organizationID *string
+		if id := c.Query("organization_id"); id != "" {
+			org, err := organizationService.GetByID(ctx, id)
+			if err != nil {
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			}
+			if err := authService.CanWrite(ctx, org); err != nil {
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			}
+			organizationID = &org.ID
+		}
+
+		cb, err := exportsService.Import(ctx, userID, c.Request.Body, organizationID)
+		switch {
+		case err == nil:
+			c.JSON(http.StatusOK, cb)
+		case errors.Is(err, service_exports.ErrAlreadyExists):
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, exports.ErrInvalidArchive), errors.Is(err, exports.ErrUnsupportedVersion):
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			logger.Error("failed to import codebase", zap.Error(err))
+			c.AbortWithStatus(http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	db_changes "getsturdy.com/api/pkg/changes/db"
+	db_acl "getsturdy.com/api/pkg/codebases/acl/db"
+	db_codebases "getsturdy.com/api/pkg/codebases/db"
+	db_comments "getsturdy.com/api/pkg/comments/db"
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/logger"
+	service_organization "getsturdy.com/api/pkg/organization/service"
+	db_review "getsturdy.com/api/pkg/review/db"
+	db_snapshots "getsturdy.com/api/pkg/snapshots/db"
+	db_statuses "getsturdy.com/api/pkg/statuses/db"
+	db_trunks "getsturdy.com/api/pkg/trunks/db"
+	db_users "getsturdy.com/api/pkg/users/db"
+	service_users "getsturdy.com/api/pkg/users/service/module"
+	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
+	"getsturdy.com/api/vcs/executor"
+)
+
+func Module(c *di.Container) {
+	c.Import(logger.Module)
+	c.Import(db_codebases.Module)
+	c.Import(db_users.Module)
+	c.Import(service_users.Module)
+	c.Import(db_trunks.Module)
+	c.Import(db_workspaces.Module)
+	c.Import(db_snapshots.Module)
+	c.Import(db_changes.Module)
+	c.Import(db_comments.Module)
+	c.Import(db_review.Module)
+	c.Import(db_statuses.Module)
+	c.Import(db_acl.Module)
+	c.Import(service_organization.Module)
+	c.Import(executor.Module)
+	c.Register(New)
+}
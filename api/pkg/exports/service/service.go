@@ -0,0 +1,818 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"getsturdy.com/api/pkg/changes"
+	db_changes "getsturdy.com/api/pkg/changes/db"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/codebases/acl"
+	db_acl "getsturdy.com/api/pkg/codebases/acl/db"
+	db_codebases "getsturdy.com/api/pkg/codebases/db"
+	"getsturdy.com/api/pkg/comments"
+	db_comments "getsturdy.com/api/pkg/comments/db"
+	"getsturdy.com/api/pkg/exports"
+	service_organization "getsturdy.com/api/pkg/organization/service"
+	"getsturdy.com/api/pkg/review"
+	db_review "getsturdy.com/api/pkg/review/db"
+	"getsturdy.com/api/pkg/snapshots"
+	db_snapshots "getsturdy.com/api/pkg/snapshots/db"
+	"getsturdy.com/api/pkg/statuses"
+	db_statuses "getsturdy.com/api/pkg/statuses/db"
+	"getsturdy.com/api/pkg/trunks"
+	db_trunks "getsturdy.com/api/pkg/trunks/db"
+	"getsturdy.com/api/pkg/users"
+	db_users "getsturdy.com/api/pkg/users/db"
+	service_users "getsturdy.com/api/pkg/users/service"
+	"getsturdy.com/api/pkg/workspaces"
+	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
+	"getsturdy.com/api/vcs"
+	"getsturdy.com/api/vcs/executor"
+	"getsturdy.com/api/vcs/provider"
+)
+
+var ErrAlreadyExists = errors.New("codebase already exists")
+
+type Service struct {
+	logger              *zap.Logger
+	codebaseRepo        db_codebases.CodebaseRepository
+	codebaseUserRepo    db_codebases.CodebaseUserRepository
+	userRepo            db_users.Repository
+	userService         service_users.Service
+	trunkRepo           db_trunks.Repository
+	workspaceRepo       db_workspaces.Repository
+	snapshotRepo        db_snapshots.Repository
+	changeRepo          db_changes.Repository
+	commentRepo         db_comments.Repository
+	reviewRepo          db_review.ReviewRepository
+	statusRepo          db_statuses.Repository
+	aclRepo             db_acl.ACLRepository
+	organizationService *service_organization.Service
+	executorProvider    executor.Provider
+}
+
+func New(
+	logger *zap.Logger,
+	codebaseRepo db_codebases.CodebaseRepository,
+	codebaseUserRepo db_codebases.CodebaseUserRepository,
+	userRepo db_users.Repository,
+	userService service_users.Service,
+	trunkRepo db_trunks.Repository,
+	workspaceRepo db_workspaces.Repository,
+	snapshotRepo db_snapshots.Repository,
+	changeRepo db_changes.Repository,
+	commentRepo db_comments.Repository,
+	reviewRepo db_review.ReviewRepository,
+	statusRepo db_statuses.Repository,
+	aclRepo db_acl.ACLRepository,
+	organizationService *service_organization.Service,
+	executorProvider executor.Provider,
+) *Service {
+	return &Service{
+		logger:              logger.Named("exportsService"),
+		codebaseRepo:        codebaseRepo,
+		codebaseUserRepo:    codebaseUserRepo,
+		userRepo:            userRepo,
+		userService:         userService,
+		trunkRepo:           trunkRepo,
+		workspaceRepo:       workspaceRepo,
+		snapshotRepo:        snapshotRepo,
+		changeRepo:          changeRepo,
+		commentRepo:         commentRepo,
+		reviewRepo:          reviewRepo,
+		statusRepo:          statusRepo,
+		aclRepo:             aclRepo,
+		organizationService: organizationService,
+		executorProvider:    executorProvider,
+	}
+}
+
+// Export writes an archive of the codebase to w. The archive contains the trunk repository as a git bundle, with the
+// latest snapshot of every workspace, and the changes, comments, reviews, statuses and ACL policy of the codebase.
+func (svc *Service) Export(ctx context.Context, codebaseID codebases.ID, w io.Writer) error {
+	cb, err := svc.codebaseRepo.Get(codebaseID)
+	if err != nil {
+		return fmt.Errorf("failed to get codebase: %w", err)
+	}
+
+	archive := &exports.Archive{
+		Manifest: exports.Manifest{
+			Version:    exports.Version,
+			ExportedAt: time.Now(),
+		},
+		Codebase: exports.Codebase{
+			ID:                        cb.ID.String(),
+			ShortID:                   string(cb.ShortCodebaseID),
+			Name:                      cb.Name,
+			Description:               cb.Description,
+			Emoji:                     cb.Emoji,
+			IsPublic:                  cb.IsPublic,
+			RequireHealthyStatus:      cb.RequireHealthyStatus,
+			MergeQueueEnabled:         cb.MergeQueueEnabled,
+			RequireCodeOwnersApproval: cb.RequireCodeOwnersApproval,
+			CreatedAt:                 cb.CreatedAt,
+		},
+	}
+
+	userIDs := make(map[users.ID]bool)
+	addUser := func(id *users.ID) *string {
+		if id == nil {
+			return nil
+		}
+		userIDs[*id] = true
+		s := id.String()
+		return &s
+	}
+
+	members, err := svc.codebaseUserRepo.GetByCodebase(codebaseID)
+	if err != nil {
+		return fmt.Errorf("failed to get members: %w", err)
+	}
+	for _, member := range members {
+		archive.Members = append(archive.Members, exports.Member{
+			UserID:    *addUser(&member.UserID),
+			InvitedBy: addUser(member.InvitedBy),
+			CreatedAt: member.CreatedAt,
+		})
+	}
+
+	tt, err := svc.trunkRepo.ListByCodebaseID(ctx, codebaseID)
+	if err != nil {
+		return fmt.Errorf("failed to list trunks: %w", err)
+	}
+	for _, t := range tt {
+		archive.Trunks = append(archive.Trunks, exports.Trunk{
+			ID:            t.ID.String(),
+			Name:          t.Name,
+			TrackedBranch: t.TrackedBranch,
+			CreatedBy:     *addUser(&t.CreatedBy),
+			CreatedAt:     t.CreatedAt,
+		})
+	}
+
+	// commitIDs are the commits that statuses are exported for
+	var commitIDs []string
+	// snapshotBranches are the snapshot branches that are included in the bundle
+	snapshotBranches := make(map[string]bool)
+	// comments can belong to both a workspace and a change, and are only exported once
+	exportedComments := make(map[comments.ID]bool)
+
+	wss, err := svc.workspaceRepo.ListByCodebaseIDs([]codebases.ID{codebaseID}, true)
+	if err != nil {
+		return fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	for _, ws := range wss {
+		workspace := exports.Workspace{
+			ID:                ws.ID,
+			UserID:            *addUser(&ws.UserID),
+			Name:              ws.Name,
+			DraftDescription:  ws.DraftDescription,
+			TrunkID:           (*string)(ws.TrunkID),
+			ParentWorkspaceID: ws.ParentWorkspaceID,
+			ChangeID:          (*string)(ws.ChangeID),
+			CreatedAt:         ws.CreatedAt,
+			UpdatedAt:         ws.UpdatedAt,
+			LastLandedAt:      ws.LastLandedAt,
+			ArchivedAt:        ws.ArchivedAt,
+		}
+
+		if ws.LatestSnapshotID != nil {
+			// snapshots that have been garbage collected are not found, and are not exported
+			snapshot, err := svc.snapshotRepo.Get(*ws.LatestSnapshotID)
+			switch {
+			case err == nil:
+				workspace.LatestSnapshot = &exports.Snapshot{
+					ID:         snapshot.ID.String(),
+					CommitID:   snapshot.CommitSHA,
+					Action:     snapshot.Action.String(),
+					DiffsCount: snapshot.DiffsCount,
+					CreatedAt:  snapshot.CreatedAt,
+				}
+				snapshotBranches[snapshot.BranchName()] = true
+				commitIDs = append(commitIDs, snapshot.CommitSHA)
+			case errors.Is(err, sql.ErrNoRows):
+			default:
+				return fmt.Errorf("failed to get snapshot: %w", err)
+			}
+		}
+		archive.Workspaces = append(archive.Workspaces, workspace)
+
+		cc, err := svc.commentRepo.GetByWorkspace(ws.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get comments: %w", err)
+		}
+		if err := svc.exportComments(archive, cc, exportedComments, addUser); err != nil {
+			return err
+		}
+
+		rr, err := svc.reviewRepo.ListLatestByWorkspace(ctx, ws.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list reviews: %w", err)
+		}
+		for _, r := range rr {
+			archive.Reviews = append(archive.Reviews, exports.Review{
+				ID:          r.ID,
+				WorkspaceID: r.WorkspaceID,
+				UserID:      *addUser(&r.UserID),
+				Grade:       string(r.Grade),
+				RequestedBy: addUser(r.RequestedBy),
+				CreatedAt:   r.CreatedAt,
+			})
+		}
+	}
+
+	cc, err := svc.changeRepo.ListByCodebaseID(ctx, codebaseID)
+	if err != nil {
+		return fmt.Errorf("failed to list changes: %w", err)
+	}
+	for _, ch := range cc {
+		archive.Changes = append(archive.Changes, exports.Change{
+			ID:              ch.ID.String(),
+			Title:           ch.Title,
+			Description:     ch.UpdatedDescription,
+			UserID:          addUser(ch.UserID),
+			WorkspaceID:     ch.WorkspaceID,
+			CommitID:        ch.CommitID,
+			ParentChangeID:  (*string)(ch.ParentChangeID),
+			CreatedAt:       ch.CreatedAt,
+			GitCreatedAt:    ch.GitCreatedAt,
+			GitCreatorName:  ch.GitCreatorName,
+			GitCreatorEmail: ch.GitCreatorEmail,
+		})
+		if ch.CommitID != nil {
+			commitIDs = append(commitIDs, *ch.CommitID)
+		}
+
+		changeComments, err := svc.commentRepo.GetByCodebaseAndChange(codebaseID, ch.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get comments: %w", err)
+		}
+		if err := svc.exportComments(archive, changeComments, exportedComments, addUser); err != nil {
+			return err
+		}
+	}
+
+	for _, commitID := range commitIDs {
+		ss, err := svc.statusRepo.ListByCodebaseIDAndCommitID(ctx, codebaseID, commitID)
+		if err != nil {
+			return fmt.Errorf("failed to list statuses: %w", err)
+		}
+		for _, s := range ss {
+			archive.Statuses = append(archive.Statuses, exports.Status{
+				ID:          s.ID,
+				CommitID:    s.CommitSHA,
+				Type:        string(s.Type),
+				Title:       s.Title,
+				Description: s.Description,
+				DetailsURL:  s.DetailsURL,
+				Timestamp:   s.Timestamp,
+			})
+		}
+	}
+
+	policy, err := svc.aclRepo.GetByCodebaseID(ctx, codebaseID)
+	switch {
+	case err == nil:
+		archive.ACL = &exports.ACL{
+			ID:        string(policy.ID),
+			Policy:    policy.RawPolicy,
+			CreatedAt: policy.CreatedAt,
+		}
+	case errors.Is(err, sql.ErrNoRows):
+	default:
+		return fmt.Errorf("failed to get acl: %w", err)
+	}
+
+	ids := make([]users.ID, 0, len(userIDs))
+	for id := range userIDs {
+		ids = append(ids, id)
+	}
+	uu, err := svc.userRepo.GetByIDs(ctx, ids...)
+	if err != nil {
+		return fmt.Errorf("failed to get users: %w", err)
+	}
+	for _, u := range uu {
+		archive.Users = append(archive.Users, exports.User{
+			ID:    u.ID.String(),
+			Name:  u.Name,
+			Email: u.Email,
+		})
+	}
+
+	dir, err := os.MkdirTemp("", "sturdy-export-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	bundlePath := filepath.Join(dir, exports.BundleName)
+	if err := svc.executorProvider.New().GitRead(func(repo vcs.RepoGitReader) error {
+		branches, err := repo.Branches()
+		if err != nil {
+			return fmt.Errorf("failed to list branches: %w", err)
+		}
+
+		// only the latest snapshot of each workspace is exported
+		var included []string
+		for _, branch := range branches {
+			if strings.HasPrefix(branch, "snapshot-") && !snapshotBranches[branch] {
+				continue
+			}
+			included = append(included, branch)
+		}
+
+		f, err := os.Create(bundlePath)
+		if err != nil {
+			return fmt.Errorf("failed to create bundle: %w", err)
+		}
+		defer f.Close()
+		if err := repo.CreateBundle(f, included...); err != nil {
+			return fmt.Errorf("failed to create bundle: %w", err)
+		}
+		return f.Close()
+	}).ExecTrunkContext(ctx, codebaseID, "exportCodebase"); err != nil {
+		return fmt.Errorf("failed to export trunk: %w", err)
+	}
+
+	if err := exports.Write(w, archive, bundlePath); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
+
+// exportComments adds the comments, and the replies to them, to the archive. Replies are added after the comment that
+// they reply to.
+func (svc *Service) exportComments(archive *exports.Archive, cc []comments.Comment, exported map[comments.ID]bool, addUser func(*users.ID) *string) error {
+	for _, c := range cc {
+		if exported[c.ID] {
+			continue
+		}
+		exported[c.ID] = true
+
+		archive.Comments = append(archive.Comments, exports.Comment{
+			ID:                  c.ID.String(),
+			ChangeID:            (*string)(c.ChangeID),
+			WorkspaceID:         c.WorkspaceID,
+			ParentCommentID:     (*string)(c.ParentComment),
+			UserID:              *addUser(&c.UserID),
+			Message:             c.Message,
+			CreatedAt:           c.CreatedAt,
+			ResolvedAt:          c.ResolvedAt,
+			ResolvedBy:          addUser(c.ResolvedBy),
+			Path:                c.Path,
+			OldPath:             c.OldPath,
+			LineStart:           c.LineStart,
+			LineEnd:             c.LineEnd,
+			LineIsNew:           c.LineIsNew,
+			Context:             c.Context,
+			ContextStartsAtLine: c.ContextStartsAtLine,
+		})
+
+		if c.ParentComment != nil {
+			continue
+		}
+		replies, err := svc.commentRepo.GetByParent(c.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get replies: %w", err)
+		}
+		if err := svc.exportComments(archive, replies, exported, addUser); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Import creates a codebase from an archive that was written by Export. The codebase keeps the ids that it had on the
+// instance that it was exported from, and ErrAlreadyExists is returned if it already exists on this instance.
+//
+// The importing user is made the only member of the codebase, other users have to be invited. See importUsers for how
+// the users in the archive are matched to users on this instance.
+func (svc *Service) Import(ctx context.Context, userID users.ID, r io.Reader, organizationID *string) (*codebases.Codebase, error) {
+	dir, err := os.MkdirTemp("", "sturdy-import-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	archive, bundlePath, err := exports.Read(r, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	codebaseID := codebases.ID(archive.Codebase.ID)
+	if _, err := svc.codebaseRepo.GetAllowArchived(codebaseID); err == nil {
+		return nil, ErrAlreadyExists
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get codebase: %w", err)
+	}
+
+	userIDs, err := svc.importUsers(ctx, userID, organizationID, archive.Users)
+	if err != nil {
+		return nil, err
+	}
+	mapper := userMapper{ids: userIDs, fallback: userID}
+
+	cb := codebases.Codebase{
+		ID:                        codebaseID,
+		ShortCodebaseID:           codebases.ShortCodebaseID(archive.Codebase.ShortID),
+		Name:                      archive.Codebase.Name,
+		Description:               archive.Codebase.Description,
+		Emoji:                     archive.Codebase.Emoji,
+		CreatedAt:                 archive.Codebase.CreatedAt,
+		OrganizationID:            organizationID,
+		IsReady:                   false, // set once everything is imported
+		IsPublic:                  archive.Codebase.IsPublic,
+		RequireHealthyStatus:      archive.Codebase.RequireHealthyStatus,
+		MergeQueueEnabled:         archive.Codebase.MergeQueueEnabled,
+		RequireCodeOwnersApproval: archive.Codebase.RequireCodeOwnersApproval,
+	}
+	if err := svc.codebaseRepo.Create(cb); err != nil {
+		return nil, fmt.Errorf("failed to create codebase: %w", err)
+	}
+
+	if err := svc.importContents(ctx, userID, codebaseID, archive, bundlePath, mapper); err != nil {
+		// the request might have been cancelled, but the partially imported codebase should still be removed
+		if cleanupErr := svc.cleanup(context.Background(), codebaseID); cleanupErr != nil {
+			svc.logger.Error("failed to clean up partially imported codebase", zap.Stringer("codebase_id", codebaseID), zap.Error(cleanupErr))
+		}
+		return nil, err
+	}
+
+	cb.IsReady = true
+	if err := svc.codebaseRepo.Update(&cb); err != nil {
+		return nil, fmt.Errorf("failed to mark codebase as ready: %w", err)
+	}
+
+	svc.logger.Info("imported codebase",
+		zap.Stringer("codebase_id", codebaseID),
+		zap.Int("workspaces", len(archive.Workspaces)),
+		zap.Int("changes", len(archive.Changes)),
+	)
+
+	return &cb, nil
+}
+
+// importContents creates the trunk repository, and everything that belongs to the codebase, from the archive.
+func (svc *Service) importContents(ctx context.Context, userID users.ID, codebaseID codebases.ID, archive *exports.Archive, bundlePath string, mapper userMapper) error {
+	if err := svc.executorProvider.New().
+		AllowRebasingState(). // allowed because the repo does not exist yet
+		Schedule(func(repoProvider provider.RepoProvider) error {
+			if _, err := vcs.CreateBareRepoFromBundle(bundlePath, repoProvider.TrunkPath(codebaseID), trunks.DefaultBranchName); err != nil {
+				return fmt.Errorf("failed to create trunk: %w", err)
+			}
+			return nil
+		}).
+		ExecTrunkContext(ctx, codebaseID, "importCodebase"); err != nil {
+		return fmt.Errorf("failed to import trunk: %w", err)
+	}
+
+	now := time.Now()
+	if err := svc.codebaseUserRepo.Create(codebases.CodebaseUser{
+		ID:         uuid.NewString(),
+		UserID:     userID,
+		CodebaseID: codebaseID,
+		CreatedAt:  &now,
+	}); err != nil {
+		return fmt.Errorf("failed to add importer as member: %w", err)
+	}
+
+	if archive.ACL != nil {
+		if err := svc.aclRepo.Create(ctx, acl.ACL{
+			ID:         acl.ID(archive.ACL.ID),
+			CodebaseID: codebaseID,
+			CreatedAt:  archive.ACL.CreatedAt,
+			RawPolicy:  archive.ACL.Policy,
+		}); err != nil {
+			return fmt.Errorf("failed to create acl: %w", err)
+		}
+	}
+
+	for _, t := range archive.Trunks {
+		if err := svc.trunkRepo.Create(ctx, &trunks.Trunk{
+			ID:            trunks.ID(t.ID),
+			CodebaseID:    codebaseID,
+			Name:          t.Name,
+			TrackedBranch: t.TrackedBranch,
+			CreatedBy:     mapper.id(t.CreatedBy),
+			CreatedAt:     t.CreatedAt,
+		}); err != nil {
+			return fmt.Errorf("failed to create trunk: %w", err)
+		}
+	}
+
+	// workspaces are created before the snapshots and changes that reference them, and are updated to reference them
+	// once they exist
+	for _, ws := range archive.Workspaces {
+		if err := svc.workspaceRepo.Create(workspaces.Workspace{
+			ID:               ws.ID,
+			UserID:           mapper.id(ws.UserID),
+			CodebaseID:       codebaseID,
+			Name:             ws.Name,
+			CreatedAt:        ws.CreatedAt,
+			DraftDescription: ws.DraftDescription,
+			TrunkID:          (*trunks.ID)(ws.TrunkID),
+		}); err != nil {
+			return fmt.Errorf("failed to create workspace: %w", err)
+		}
+
+		if ws.LatestSnapshot == nil {
+			continue
+		}
+		snapshotID := snapshots.ID(ws.LatestSnapshot.ID)
+		if err := svc.snapshotRepo.Create(&snapshots.Snapshot{
+			ID:          snapshotID,
+			CommitSHA:   ws.LatestSnapshot.CommitID,
+			CodebaseID:  codebaseID,
+			WorkspaceID: ws.ID,
+			CreatedAt:   ws.LatestSnapshot.CreatedAt,
+			Action:      snapshots.Action(ws.LatestSnapshot.Action),
+			DiffsCount:  ws.LatestSnapshot.DiffsCount,
+		}); err != nil {
+			return fmt.Errorf("failed to create snapshot: %w", err)
+		}
+		if err := svc.workspaceRepo.UpdateFields(ctx, ws.ID,
+			db_workspaces.SetLatestSnapshotID(&snapshotID),
+			db_workspaces.SetDiffsCount(ws.LatestSnapshot.DiffsCount),
+		); err != nil {
+			return fmt.Errorf("failed to set latest snapshot: %w", err)
+		}
+	}
+
+	for _, ch := range parentsFirst(archive.Changes) {
+		if err := svc.changeRepo.Insert(ctx, changes.Change{
+			ID:                 changes.ID(ch.ID),
+			CodebaseID:         codebaseID,
+			Title:              ch.Title,
+			UpdatedDescription: ch.Description,
+			UserID:             mapper.ptr(ch.UserID),
+			WorkspaceID:        ch.WorkspaceID,
+			CreatedAt:          ch.CreatedAt,
+			GitCreatedAt:       ch.GitCreatedAt,
+			GitCreatorName:     ch.GitCreatorName,
+			GitCreatorEmail:    ch.GitCreatorEmail,
+			CommitID:           ch.CommitID,
+			ParentChangeID:     (*changes.ID)(ch.ParentChangeID),
+		}); err != nil {
+			return fmt.Errorf("failed to create change: %w", err)
+		}
+	}
+
+	for _, ws := range archive.Workspaces {
+		if err := svc.workspaceRepo.UpdateFields(ctx, ws.ID,
+			db_workspaces.SetParentWorkspaceID(ws.ParentWorkspaceID),
+			db_workspaces.SetChangeID((*changes.ID)(ws.ChangeID)),
+			db_workspaces.SetUpdatedAt(ws.UpdatedAt),
+			db_workspaces.SetLastLandedAt(ws.LastLandedAt),
+			db_workspaces.SetArchivedAt(ws.ArchivedAt),
+		); err != nil {
+			return fmt.Errorf("failed to update workspace: %w", err)
+		}
+	}
+
+	for _, c := range archive.Comments {
+		comment := comments.Comment{
+			ID:                  comments.ID(c.ID),
+			CodebaseID:          codebaseID,
+			ChangeID:            (*changes.ID)(c.ChangeID),
+			WorkspaceID:         c.WorkspaceID,
+			UserID:              mapper.id(c.UserID),
+			CreatedAt:           c.CreatedAt,
+			Message:             c.Message,
+			Path:                c.Path,
+			OldPath:             c.OldPath,
+			LineStart:           c.LineStart,
+			LineEnd:             c.LineEnd,
+			LineIsNew:           c.LineIsNew,
+			ContextStartsAtLine: c.ContextStartsAtLine,
+			Context:             c.Context,
+			ParentComment:       (*comments.ID)(c.ParentCommentID),
+		}
+		if err := svc.commentRepo.Create(comment); err != nil {
+			return fmt.Errorf("failed to create comment: %w", err)
+		}
+		if c.ResolvedAt == nil {
+			continue
+		}
+		comment.ResolvedAt = c.ResolvedAt
+		comment.ResolvedBy = mapper.ptr(c.ResolvedBy)
+		if err := svc.commentRepo.Update(comment); err != nil {
+			return fmt.Errorf("failed to resolve comment: %w", err)
+		}
+	}
+
+	for _, r := range archive.Reviews {
+		if err := svc.reviewRepo.Create(ctx, review.Review{
+			ID:          r.ID,
+			UserID:      mapper.id(r.UserID),
+			CodebaseID:  codebaseID,
+			WorkspaceID: r.WorkspaceID,
+			Grade:       review.ReviewGrade(r.Grade),
+			CreatedAt:   r.CreatedAt,
+			RequestedBy: mapper.ptr(r.RequestedBy),
+		}); err != nil {
+			return fmt.Errorf("failed to create review: %w", err)
+		}
+	}
+
+	for _, s := range archive.Statuses {
+		if err := svc.statusRepo.Create(ctx, &statuses.Status{
+			ID:          s.ID,
+			CommitSHA:   s.CommitID,
+			CodebaseID:  codebaseID,
+			Type:        statuses.Type(s.Type),
+			Title:       s.Title,
+			DetailsURL:  s.DetailsURL,
+			Description: s.Description,
+			Timestamp:   s.Timestamp,
+		}); err != nil {
+			return fmt.Errorf("failed to create status: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// cleanup removes a codebase that failed to import, and everything that was created for it. Users that were created
+// for the import are kept, they are not linked to any email.
+func (svc *Service) cleanup(ctx context.Context, codebaseID codebases.ID) error {
+	if err := svc.statusRepo.DeleteByCodebaseID(ctx, codebaseID); err != nil {
+		return err
+	}
+	if err := svc.reviewRepo.DeleteByCodebaseID(ctx, codebaseID); err != nil {
+		return err
+	}
+	if err := svc.commentRepo.DeleteByCodebaseID(ctx, codebaseID); err != nil {
+		return err
+	}
+	if err := svc.changeRepo.DeleteByCodebaseID(ctx, codebaseID); err != nil {
+		return err
+	}
+	if err := svc.snapshotRepo.DeleteByCodebaseID(ctx, codebaseID); err != nil {
+		return err
+	}
+	if err := svc.workspaceRepo.DeleteByCodebaseID(ctx, codebaseID); err != nil {
+		return err
+	}
+	if err := svc.trunkRepo.DeleteByCodebaseID(ctx, codebaseID); err != nil {
+		return err
+	}
+	if err := svc.aclRepo.DeleteByCodebaseID(ctx, codebaseID); err != nil {
+		return err
+	}
+
+	members, err := svc.codebaseUserRepo.GetByCodebase(codebaseID)
+	if err != nil {
+		return fmt.Errorf("failed to get members: %w", err)
+	}
+	for _, member := range members {
+		if err := svc.codebaseUserRepo.DeleteByID(ctx, member.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := svc.executorProvider.New().
+		AllowRebasingState(). // allowed because the repo might not exist
+		Schedule(func(repoProvider provider.RepoProvider) error {
+			return os.RemoveAll(repoProvider.TrunkPath(codebaseID))
+		}).
+		ExecTrunkContext(ctx, codebaseID, "cleanupImportedCodebase"); err != nil {
+		return fmt.Errorf("failed to remove trunk: %w", err)
+	}
+
+	return svc.codebaseRepo.Delete(ctx, codebaseID)
+}
+
+// userMapper maps the ids of the users in an archive to the ids of the users on this instance, see importUsers. Users
+// that are not in the archive are mapped to the fallback user.
+type userMapper struct {
+	ids      map[string]users.ID
+	fallback users.ID
+}
+
+func (m userMapper) id(archiveID string) users.ID {
+	if mapped, ok := m.ids[archiveID]; ok {
+		return mapped
+	}
+	return m.fallback
+}
+
+func (m userMapper) ptr(archiveID *string) *users.ID {
+	if archiveID == nil {
+		return nil
+	}
+	mapped := m.id(*archiveID)
+	return &mapped
+}
+
+// importUsers returns a map from the ids of the users in the archive to the ids of the users on this instance that
+// their work is attributed to.
+//
+// Anyone can create an archive with any emails in it, so users are only matched by email to existing users on this
+// instance if the codebase is imported to an organization by an admin of the organization, and only to the members of
+// the organization. All other users, apart from the importing user, are created as shadow users that are not linked
+// to any email, so that no one can sign in as them.
+func (svc *Service) importUsers(ctx context.Context, importerID users.ID, organizationID *string, uu []exports.User) (map[string]users.ID, error) {
+	importer, err := svc.userService.GetByID(ctx, importerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get importer: %w", err)
+	}
+
+	canLink, err := svc.canLinkUsers(ctx, importerID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]users.ID, len(uu))
+	for _, u := range uu {
+		if strings.EqualFold(u.Email, importer.Email) {
+			ids[u.ID] = importer.ID
+			continue
+		}
+
+		if canLink {
+			existing, err := svc.userService.GetByEmail(ctx, u.Email)
+			switch {
+			case err == nil:
+				isMember, err := svc.organizationService.CanAccess(ctx, existing.ID, *organizationID)
+				if err != nil {
+					return nil, fmt.Errorf("failed to check organization membership: %w", err)
+				}
+				if isMember {
+					ids[u.ID] = existing.ID
+					continue
+				}
+			case errors.Is(err, sql.ErrNoRows):
+			default:
+				return nil, fmt.Errorf("failed to get user: %w", err)
+			}
+		}
+
+		name := u.Name
+		if name == "" {
+			name = users.EmailToName(u.Email)
+		}
+		created, err := svc.userService.CreateShadow(ctx, unlinkedEmail(), service_users.UserReferer(importerID), &name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create user: %w", err)
+		}
+		ids[u.ID] = created.ID
+	}
+	return ids, nil
+}
+
+// canLinkUsers returns true if the users in an archive that is imported by _importerID_ can be matched to the existing
+// users of the organization. Only the user that created the organization administrates it.
+func (svc *Service) canLinkUsers(ctx context.Context, importerID users.ID, organizationID *string) (bool, error) {
+	if organizationID == nil {
+		return false, nil
+	}
+	org, err := svc.organizationService.GetByID(ctx, *organizationID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get organization: %w", err)
+	}
+	return org.CreatedBy == importerID, nil
+}
+
+// unlinkedEmail returns an email that can not be used to sign in, for users that are created by an import.
+func unlinkedEmail() string {
+	return fmt.Sprintf("imported-%s@users.invalid", uuid.NewString())
+}
+
+// parentsFirst orders the changes so that every change comes after its parent.
+func parentsFirst(cc []exports.Change) []exports.Change {
+	byID := make(map[string]exports.Change, len(cc))
+	for _, ch := range cc {
+		byID[ch.ID] = ch
+	}
+
+	ordered := make([]exports.Change, 0, len(cc))
+	visited := make(map[string]bool, len(cc))
+	var visit func(ch exports.Change)
+	visit = func(ch exports.Change) {
+		if visited[ch.ID] {
+			return
+		}
+		visited[ch.ID] = true
+		if ch.ParentChangeID != nil {
+			if parent, ok := byID[*ch.ParentChangeID]; ok {
+				visit(parent)
+			}
+		}
+		ordered = append(ordered, ch)
+	}
+	for _, ch := range cc {
+		visit(ch)
+	}
+	return ordered
+}
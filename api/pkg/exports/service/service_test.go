@@ -0,0 +1,395 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"getsturdy.com/api/pkg/analytics/disabled"
+	service_analytics "getsturdy.com/api/pkg/analytics/service"
+	"getsturdy.com/api/pkg/changes"
+	db_changes "getsturdy.com/api/pkg/changes/db"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/codebases/acl"
+	db_acl "getsturdy.com/api/pkg/codebases/acl/db"
+	db_codebases "getsturdy.com/api/pkg/codebases/db"
+	vcs_codebases "getsturdy.com/api/pkg/codebases/vcs"
+	"getsturdy.com/api/pkg/comments"
+	db_comments "getsturdy.com/api/pkg/comments/db"
+	"getsturdy.com/api/pkg/organization"
+	db_organization "getsturdy.com/api/pkg/organization/db"
+	service_organization "getsturdy.com/api/pkg/organization/service"
+	"getsturdy.com/api/pkg/review"
+	db_review "getsturdy.com/api/pkg/review/db"
+	db_snapshots "getsturdy.com/api/pkg/snapshots/db"
+	"getsturdy.com/api/pkg/statuses"
+	db_statuses "getsturdy.com/api/pkg/statuses/db"
+	"getsturdy.com/api/pkg/trunks"
+	db_trunks "getsturdy.com/api/pkg/trunks/db"
+	"getsturdy.com/api/pkg/users"
+	db_users "getsturdy.com/api/pkg/users/db"
+	service_users "getsturdy.com/api/pkg/users/service"
+	"getsturdy.com/api/pkg/workspaces"
+	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
+	"getsturdy.com/api/vcs"
+	"getsturdy.com/api/vcs/executor"
+	"getsturdy.com/api/vcs/provider"
+)
+
+// instance is a Sturdy installation, with its own database and repositories.
+type instance struct {
+	service *Service
+
+	codebaseRepo     db_codebases.CodebaseRepository
+	codebaseUserRepo db_codebases.CodebaseUserRepository
+	userRepo         db_users.Repository
+	trunkRepo        db_trunks.Repository
+	workspaceRepo    db_workspaces.Repository
+	changeRepo       db_changes.Repository
+	commentRepo      db_comments.Repository
+	reviewRepo       db_review.ReviewRepository
+	statusRepo       db_statuses.Repository
+	aclRepo          db_acl.ACLRepository
+	organizationRepo db_organization.Repository
+	orgMemberRepo    db_organization.MemberRepository
+	repoProvider     provider.RepoProvider
+	executorProvider executor.Provider
+}
+
+func newInstance(t *testing.T) *instance {
+	repoProvider := provider.New(t.TempDir(), "localhost:8888")
+	i := &instance{
+		codebaseRepo:     db_codebases.NewMemory(),
+		codebaseUserRepo: db_codebases.NewInMemoryCodebaseUserRepo(),
+		userRepo:         db_users.NewMemory(),
+		trunkRepo:        db_trunks.NewMemory(),
+		workspaceRepo:    db_workspaces.NewMemory(),
+		changeRepo:       db_changes.NewInMemoryRepo(),
+		commentRepo:      db_comments.NewMemory(),
+		reviewRepo:       db_review.NewMemory(),
+		statusRepo:       db_statuses.NewMemory(),
+		aclRepo:          db_acl.NewInMemoryAclRepo(),
+		organizationRepo: db_organization.NewInMemoryOrganizationRepo(),
+		orgMemberRepo:    db_organization.NewInMemoryOrganizationMemberRepository(),
+		repoProvider:     repoProvider,
+		executorProvider: executor.NewProvider(zap.NewNop(), repoProvider),
+	}
+	i.service = i.newService()
+	return i
+}
+
+func (i *instance) newService() *Service {
+	analyticsService := service_analytics.New(zap.NewNop(), disabled.NewClient(zap.NewNop()))
+	return New(
+		zap.NewNop(),
+		i.codebaseRepo,
+		i.codebaseUserRepo,
+		i.userRepo,
+		service_users.New(zap.NewNop(), i.userRepo, analyticsService),
+		i.trunkRepo,
+		i.workspaceRepo,
+		db_snapshots.NewInMemorySnapshotRepo(),
+		i.changeRepo,
+		i.commentRepo,
+		i.reviewRepo,
+		i.statusRepo,
+		i.aclRepo,
+		service_organization.New(zap.NewNop(), nil, i.organizationRepo, i.orgMemberRepo, analyticsService, nil),
+		i.executorProvider,
+	)
+}
+
+func (i *instance) createUser(t *testing.T, name, email string) *users.User {
+	u := &users.User{ID: users.ID(name), Name: name, Email: email, Status: users.StatusActive}
+	require.NoError(t, i.userRepo.Create(u))
+	return u
+}
+
+func (i *instance) headCommitID(t *testing.T, codebaseID codebases.ID) string {
+	var commitID string
+	require.NoError(t, i.executorProvider.New().GitRead(func(repo vcs.RepoGitReader) error {
+		var err error
+		commitID, err = repo.BranchCommitID(trunks.DefaultBranchName)
+		return err
+	}).ExecTrunk(codebaseID, "testHeadCommitID"))
+	return commitID
+}
+
+type source struct {
+	codebaseID codebases.ID
+	author     *users.User
+	reviewer   *users.User
+	commitID   string
+}
+
+// newSource creates a codebase with a workspace, a landed change, comments, a review, a status and an ACL policy.
+func newSource(t *testing.T) (*instance, source) {
+	ctx := context.Background()
+	i := newInstance(t)
+
+	author := i.createUser(t, "author", "author@example.com")
+	reviewer := i.createUser(t, "reviewer", "reviewer@example.com")
+
+	codebaseID := codebases.ID("codebase")
+	require.NoError(t, i.codebaseRepo.Create(codebases.Codebase{
+		ID:              codebaseID,
+		ShortCodebaseID: "short",
+		Name:            "Codebase",
+		IsReady:         true,
+	}))
+	now := time.Now()
+	require.NoError(t, i.codebaseUserRepo.Create(codebases.CodebaseUser{ID: "member-author", UserID: author.ID, CodebaseID: codebaseID, CreatedAt: &now}))
+	require.NoError(t, i.codebaseUserRepo.Create(codebases.CodebaseUser{ID: "member-reviewer", UserID: reviewer.ID, CodebaseID: codebaseID, CreatedAt: &now}))
+
+	require.NoError(t, i.executorProvider.New().
+		Schedule(vcs_codebases.Create(codebaseID)).
+		ExecTrunk(codebaseID, "createTrunk"))
+	commitID := i.headCommitID(t, codebaseID)
+
+	require.NoError(t, i.trunkRepo.Create(ctx, &trunks.Trunk{
+		ID:         "trunk",
+		CodebaseID: codebaseID,
+		Name:       trunks.DefaultBranchName,
+		CreatedBy:  author.ID,
+		CreatedAt:  now,
+	}))
+
+	workspaceID := "workspace"
+	require.NoError(t, i.workspaceRepo.Create(workspaces.Workspace{
+		ID:         workspaceID,
+		UserID:     author.ID,
+		CodebaseID: codebaseID,
+		CreatedAt:  &now,
+	}))
+
+	title := "Landed"
+	require.NoError(t, i.changeRepo.Insert(ctx, changes.Change{
+		ID:          "change",
+		CodebaseID:  codebaseID,
+		Title:       &title,
+		UserID:      &author.ID,
+		WorkspaceID: &workspaceID,
+		CreatedAt:   &now,
+		CommitID:    &commitID,
+	}))
+
+	require.NoError(t, i.commentRepo.Create(comments.Comment{
+		ID:          "comment",
+		CodebaseID:  codebaseID,
+		WorkspaceID: &workspaceID,
+		UserID:      reviewer.ID,
+		CreatedAt:   now,
+		Message:     "looks good",
+	}))
+	parentID := comments.ID("comment")
+	require.NoError(t, i.commentRepo.Create(comments.Comment{
+		ID:            "reply",
+		CodebaseID:    codebaseID,
+		WorkspaceID:   &workspaceID,
+		UserID:        author.ID,
+		CreatedAt:     now.Add(time.Second),
+		Message:       "thanks",
+		ParentComment: &parentID,
+	}))
+
+	require.NoError(t, i.reviewRepo.Create(ctx, review.Review{
+		ID:          "review",
+		UserID:      reviewer.ID,
+		CodebaseID:  codebaseID,
+		WorkspaceID: workspaceID,
+		Grade:       review.ReviewGradeApprove,
+		CreatedAt:   now,
+	}))
+
+	require.NoError(t, i.statusRepo.Create(ctx, &statuses.Status{
+		ID:         "status",
+		CommitSHA:  commitID,
+		CodebaseID: codebaseID,
+		Type:       statuses.TypeHealthy,
+		Title:      "ci",
+		Timestamp:  now,
+	}))
+
+	require.NoError(t, i.aclRepo.Create(ctx, acl.ACL{
+		ID:         "acl",
+		CodebaseID: codebaseID,
+		CreatedAt:  now,
+		RawPolicy:  `{"rules":[]}`,
+	}))
+
+	return i, source{codebaseID: codebaseID, author: author, reviewer: reviewer, commitID: commitID}
+}
+
+func export(t *testing.T, i *instance, codebaseID codebases.ID) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, i.service.Export(context.Background(), codebaseID, &buf))
+	return buf.Bytes()
+}
+
+func TestExportImport(t *testing.T) {
+	ctx := context.Background()
+	src, s := newSource(t)
+	archive := export(t, src, s.codebaseID)
+
+	dst := newInstance(t)
+	importer := dst.createUser(t, "importer", "importer@example.com")
+	// has the same email as the author on the source instance, but the importer is not an admin
+	existing := dst.createUser(t, "existing", s.author.Email)
+
+	cb, err := dst.service.Import(ctx, importer.ID, bytes.NewReader(archive), nil)
+	require.NoError(t, err)
+	assert.Equal(t, s.codebaseID, cb.ID)
+	assert.Equal(t, "Codebase", cb.Name)
+	assert.True(t, cb.IsReady)
+
+	// the trunk is imported
+	assert.Equal(t, s.commitID, dst.headCommitID(t, s.codebaseID))
+
+	tt, err := dst.trunkRepo.ListByCodebaseID(ctx, s.codebaseID)
+	require.NoError(t, err)
+	require.Len(t, tt, 1)
+
+	// the importer is the only member
+	members, err := dst.codebaseUserRepo.GetByCodebase(s.codebaseID)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	assert.Equal(t, importer.ID, members[0].UserID)
+
+	// authors are not linked to existing users
+	ws, err := dst.workspaceRepo.Get("workspace")
+	require.NoError(t, err)
+	assert.NotEqual(t, existing.ID, ws.UserID)
+	assert.NotEqual(t, importer.ID, ws.UserID)
+
+	author, err := dst.userRepo.Get(ws.UserID)
+	require.NoError(t, err)
+	assert.Equal(t, users.StatusShadow, author.Status)
+	assert.Equal(t, s.author.Name, author.Name)
+	assert.NotEqual(t, s.author.Email, author.Email)
+
+	ch, err := dst.changeRepo.Get(ctx, "change")
+	require.NoError(t, err)
+	if assert.NotNil(t, ch.UserID) {
+		assert.Equal(t, ws.UserID, *ch.UserID)
+	}
+	assert.Equal(t, &s.commitID, ch.CommitID)
+
+	cc, err := dst.commentRepo.GetByWorkspace("workspace")
+	require.NoError(t, err)
+	require.Len(t, cc, 1)
+	assert.Equal(t, "looks good", cc[0].Message)
+	replies, err := dst.commentRepo.GetByParent(cc[0].ID)
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, ws.UserID, replies[0].UserID)
+
+	rr, err := dst.reviewRepo.ListLatestByWorkspace(ctx, "workspace")
+	require.NoError(t, err)
+	require.Len(t, rr, 1)
+	assert.Equal(t, review.ReviewGradeApprove, rr[0].Grade)
+
+	ss, err := dst.statusRepo.ListByCodebaseIDAndCommitID(ctx, s.codebaseID, s.commitID)
+	require.NoError(t, err)
+	require.Len(t, ss, 1)
+	assert.Equal(t, statuses.TypeHealthy, ss[0].Type)
+
+	policy, err := dst.aclRepo.GetByCodebaseID(ctx, s.codebaseID)
+	require.NoError(t, err)
+	assert.Equal(t, `{"rules":[]}`, policy.RawPolicy)
+
+	// importing the same codebase again fails
+	_, err = dst.service.Import(ctx, importer.ID, bytes.NewReader(archive), nil)
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+}
+
+func TestImport_organizationAdmin(t *testing.T) {
+	ctx := context.Background()
+	src, s := newSource(t)
+	archive := export(t, src, s.codebaseID)
+
+	dst := newInstance(t)
+	admin := dst.createUser(t, "admin", "admin@example.com")
+	author := dst.createUser(t, "author", s.author.Email)
+	reviewer := dst.createUser(t, "other", s.reviewer.Email)
+
+	require.NoError(t, dst.organizationRepo.Create(ctx, organization.Organization{ID: "org", Name: "Org", CreatedBy: admin.ID}))
+	require.NoError(t, dst.orgMemberRepo.Create(ctx, &organization.Member{ID: "m1", UserID: admin.ID, OrganizationID: "org"}))
+	require.NoError(t, dst.orgMemberRepo.Create(ctx, &organization.Member{ID: "m2", UserID: author.ID, OrganizationID: "org"}))
+
+	orgID := "org"
+	_, err := dst.service.Import(ctx, admin.ID, bytes.NewReader(archive), &orgID)
+	require.NoError(t, err)
+
+	// the author is a member of the organization, and is linked
+	ws, err := dst.workspaceRepo.Get("workspace")
+	require.NoError(t, err)
+	assert.Equal(t, author.ID, ws.UserID)
+
+	// the reviewer is not, and is not linked
+	rr, err := dst.reviewRepo.ListLatestByWorkspace(ctx, "workspace")
+	require.NoError(t, err)
+	require.Len(t, rr, 1)
+	assert.NotEqual(t, reviewer.ID, rr[0].UserID)
+
+	// no one but the importer is made a member
+	members, err := dst.codebaseUserRepo.GetByCodebase(s.codebaseID)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	assert.Equal(t, admin.ID, members[0].UserID)
+}
+
+type failingReviewRepo struct {
+	db_review.ReviewRepository
+}
+
+func (failingReviewRepo) Create(context.Context, review.Review) error {
+	return errors.New("failed")
+}
+
+func TestImport_cleanup(t *testing.T) {
+	ctx := context.Background()
+	src, s := newSource(t)
+	archive := export(t, src, s.codebaseID)
+
+	dst := newInstance(t)
+	importer := dst.createUser(t, "importer", "importer@example.com")
+
+	workingReviewRepo := dst.reviewRepo
+	dst.reviewRepo = failingReviewRepo{ReviewRepository: workingReviewRepo}
+	dst.service = dst.newService()
+
+	_, err := dst.service.Import(ctx, importer.ID, bytes.NewReader(archive), nil)
+	assert.Error(t, err)
+
+	// everything that was imported is removed
+	_, err = dst.codebaseRepo.GetAllowArchived(s.codebaseID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = os.Stat(dst.repoProvider.TrunkPath(s.codebaseID))
+	assert.True(t, os.IsNotExist(err))
+	members, err := dst.codebaseUserRepo.GetByCodebase(s.codebaseID)
+	require.NoError(t, err)
+	assert.Empty(t, members)
+	wss, err := dst.workspaceRepo.ListByCodebaseIDs([]codebases.ID{s.codebaseID}, true)
+	require.NoError(t, err)
+	assert.Empty(t, wss)
+	cc, err := dst.commentRepo.GetByWorkspace("workspace")
+	require.NoError(t, err)
+	assert.Empty(t, cc)
+	_, err = dst.aclRepo.GetByCodebaseID(ctx, s.codebaseID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	// so the import can be retried
+	dst.reviewRepo = workingReviewRepo
+	dst.service = dst.newService()
+	_, err = dst.service.Import(ctx, importer.ID, bytes.NewReader(archive), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, s.commitID, dst.headCommitID(t, s.codebaseID))
+}
@@ -101,7 +101,7 @@ func (r *fileRootResolver) InternalFile(ctx context.Context, codebase *codebases
 		}
 
 		return gqlerrors.ErrNotFound
-	}).ExecTrunk(codebase.ID, "fileRootResolver")
+	}).ExecTrunkContext(ctx, codebase.ID, "fileRootResolver")
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -44,8 +45,18 @@ func NewGetFileRoute(
 				return
 			}
 
-			data, err := fileService.ReadWorkspaceFile(ctx, ws, path, isNew == "1")
+			allower, err := authService.GetAllower(ctx, ws)
 			if err != nil {
+				logger.Error("could not get allower", zap.Error(err))
+				c.AbortWithStatus(http.StatusInternalServerError)
+				return
+			}
+
+			data, err := fileService.ReadWorkspaceFile(ctx, allower, ws, path, isNew == "1")
+			if errors.Is(err, service_file.ErrNotAllowed) {
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			} else if err != nil {
 				logger.Error("could not get file", zap.Error(err))
 				c.AbortWithStatus(http.StatusNotFound)
 				return
@@ -69,8 +80,18 @@ func NewGetFileRoute(
 				return
 			}
 
-			data, err := fileService.ReadChangeFile(ctx, ch, path, isNew == "1")
+			allower, err := authService.GetAllower(ctx, ch)
 			if err != nil {
+				logger.Error("could not get allower", zap.Error(err))
+				c.AbortWithStatus(http.StatusInternalServerError)
+				return
+			}
+
+			data, err := fileService.ReadChangeFile(ctx, allower, ch, path, isNew == "1")
+			if errors.Is(err, service_file.ErrNotAllowed) {
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			} else if err != nil {
 				logger.Error("could not get file", zap.Error(err))
 				c.AbortWithStatus(http.StatusNotFound)
 				return
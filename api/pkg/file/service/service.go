@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha1"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -19,6 +20,7 @@ import (
 	"getsturdy.com/api/pkg/comments/live"
 	"getsturdy.com/api/pkg/file"
 	db_snapshots "getsturdy.com/api/pkg/snapshots/db"
+	"getsturdy.com/api/pkg/unidiff"
 	"getsturdy.com/api/pkg/workspaces"
 	"getsturdy.com/api/vcs/executor"
 	provider "getsturdy.com/api/vcs/provider/configuration"
@@ -42,7 +44,14 @@ func New(
 	}
 }
 
-func (s *Service) ReadWorkspaceFile(ctx context.Context, ws *workspaces.Workspace, filePath string, isNew bool) ([]byte, error) {
+// ErrNotAllowed is returned when reading a file that is not allowed by the allower.
+var ErrNotAllowed = errors.New("not allowed")
+
+func (s *Service) ReadWorkspaceFile(ctx context.Context, allower *unidiff.Allower, ws *workspaces.Workspace, filePath string, isNew bool) ([]byte, error) {
+	if !allower.IsAllowed(filePath, false) {
+		return nil, ErrNotAllowed
+	}
+
 	fsys, err := live.WorkspaceFS(s.executorProvider, s.snapshotsRepo, ws, isNew)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create fs: %w", err)
@@ -51,7 +60,11 @@ func (s *Service) ReadWorkspaceFile(ctx context.Context, ws *workspaces.Workspac
 	return s.readFile(fsys, filePath, ws.CodebaseID)
 }
 
-func (s *Service) ReadChangeFile(ctx context.Context, ch *changes.Change, filePath string, isNew bool) ([]byte, error) {
+func (s *Service) ReadChangeFile(ctx context.Context, allower *unidiff.Allower, ch *changes.Change, filePath string, isNew bool) ([]byte, error) {
+	if !allower.IsAllowed(filePath, false) {
+		return nil, ErrNotAllowed
+	}
+
 	fsys, err := live.ChangeFS(s.executorProvider, ch, isNew)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create fs: %w", err)
@@ -85,7 +85,7 @@ func (svc *Service) gcSnapshotsInView(ctx context.Context, view *views.View, sna
 		}
 		allBranches = branches
 		return nil
-	}).ExecView(view.CodebaseID, view.ID, "listBranches"); err != nil {
+	}).ExecViewContext(ctx, view.CodebaseID, view.ID, "listBranches"); err != nil {
 		return fmt.Errorf("failed to execute view: %w", err)
 	}
 
@@ -209,7 +209,7 @@ func (svc *Service) gcSnapshot(
 	// Throttle heavy operations
 	time.Sleep(time.Second / 2)
 
-	if err := svc.deleteSnapshotBranchInView(logger, view, snapshot); err != nil {
+	if err := svc.deleteSnapshotBranchInView(ctx, logger, view, snapshot); err != nil {
 		return fmt.Errorf("failed to delete snapshot id=%s: %w", snapshot.ID, err)
 	}
 
@@ -220,7 +220,7 @@ func (svc *Service) gcSnapshot(
 	return nil
 }
 
-func (svc *Service) deleteSnapshotBranchInView(logger *zap.Logger, view *views.View, snapshot *snapshots.Snapshot) error {
+func (svc *Service) deleteSnapshotBranchInView(ctx context.Context, logger *zap.Logger, view *views.View, snapshot *snapshots.Snapshot) error {
 	logger.Info("deleting snapshot")
 
 	if ws, err := svc.workspaceReader.GetBySnapshotID(snapshot.ID); err != nil && !errors.Is(err, sql.ErrNoRows) {
@@ -241,7 +241,7 @@ func (svc *Service) deleteSnapshotBranchInView(logger *zap.Logger, view *views.V
 		logger.Info("trunk branch deleted", zap.String("branch_name", snapshotBranchName))
 
 		return nil
-	}).ExecTrunk(snapshot.CodebaseID, "deleteTrunkSnapshot"); err != nil {
+	}).ExecTrunkContext(ctx, snapshot.CodebaseID, "deleteTrunkSnapshot"); err != nil {
 		logger.Error("failed to delete snapshot on trunk", zap.Error(err))
 		// do not fail
 		return nil
@@ -260,7 +260,7 @@ func (svc *Service) deleteSnapshotBranchInView(logger *zap.Logger, view *views.V
 			)
 
 			return nil
-		}).ExecView(snapshot.CodebaseID, view.ID, "deleteViewSnapshot"); err != nil {
+		}).ExecViewContext(ctx, snapshot.CodebaseID, view.ID, "deleteViewSnapshot"); err != nil {
 		logger.Error("failed to open view", zap.Error(err))
 		return nil
 	}
@@ -325,7 +325,7 @@ func (svc *Service) WorkWithOptions(
 		logger.Info("trunk cleaned up")
 
 		return nil
-	}).ExecTrunk(codebaseID, "gcTrunk"); err != nil {
+	}).ExecTrunkContext(ctx, codebaseID, "gcTrunk"); err != nil {
 		logger.Error("failed to git gc trunk", zap.Error(err))
 		// don't exit
 	}
@@ -357,7 +357,7 @@ func (svc *Service) WorkWithOptions(
 
 			logger.Info("view cleaned up")
 			return nil
-		}).ExecView(view.CodebaseID, view.ID, "gcView"); err != nil {
+		}).ExecViewContext(ctx, view.CodebaseID, view.ID, "gcView"); err != nil {
 			// If the view is rebasing, it will be GC'd on the next run, no big deal.
 			if errors.Is(err, executor.ErrIsRebasing) {
 				logger.Warn("failed to run git gc on view", zap.Error(err))
@@ -5,6 +5,7 @@ import (
 
 	service_auth "getsturdy.com/api/pkg/auth/service"
 	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/codebases/acl"
 	service_codebase "getsturdy.com/api/pkg/codebases/service"
 	"getsturdy.com/api/pkg/github"
 	github_client "getsturdy.com/api/pkg/github/enterprise/client"
@@ -13,6 +14,7 @@ import (
 	service_github "getsturdy.com/api/pkg/github/enterprise/service"
 	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
 	"getsturdy.com/api/pkg/graphql/resolvers"
+	"getsturdy.com/api/pkg/integrations/providers"
 	db_snapshots "getsturdy.com/api/pkg/snapshots/db"
 	service_snaphshots "getsturdy.com/api/pkg/snapshots/service"
 	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
@@ -108,6 +110,10 @@ func (r *codebaseGitHubIntegrationRootResolver) UpdateCodebaseGitHubIntegration(
 		return nil, gqlerrors.Error(err)
 	}
 
+	if err := r.authService.CanPerform(ctx, repo.CodebaseID, acl.ActionManageIntegrations, acl.Identity{Type: acl.Integrations, ID: string(providers.ProviderNameGithub)}); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
 	if args.Input.Enabled != nil {
 		repo.IntegrationEnabled = *args.Input.Enabled
 	}
@@ -13,6 +13,7 @@ import (
 	"getsturdy.com/api/pkg/graphql/resolvers"
 	"getsturdy.com/api/pkg/snapshots"
 	service_snapshotter "getsturdy.com/api/pkg/snapshots/service"
+	"getsturdy.com/api/pkg/trunks"
 	"getsturdy.com/api/pkg/workspaces"
 	"getsturdy.com/api/vcs"
 	"getsturdy.com/api/vcs/provider"
@@ -84,7 +85,7 @@ func (r *codebaseGitHubIntegrationRootResolver) CreateWorkspaceFromGitHubBranch(
 				return fmt.Errorf("failed to checkout branch: %w", err)
 			}
 
-			trunkCommit, err := clone.BranchCommitID("sturdytrunk")
+			trunkCommit, err := clone.BranchCommitID(trunks.BranchName(nil))
 			if err != nil {
 				return fmt.Errorf("failed to get trunk commit: %w", err)
 			}
@@ -124,7 +125,7 @@ func (r *codebaseGitHubIntegrationRootResolver) CreateWorkspaceFromGitHubBranch(
 			}
 
 			return nil
-		}).ExecView(codebaseID, viewID, "createWorkspaceFromGitHubBranch"); err != nil {
+		}).ExecViewContext(ctx, codebaseID, viewID, "createWorkspaceFromGitHubBranch"); err != nil {
 		return nil, gqlerrors.Error(err)
 	}
 
@@ -67,7 +67,7 @@ func (svc *Service) CreateBuild(ctx context.Context, codebaseID codebases.ID, sn
 		}
 
 		return nil
-	}).ExecTrunk(codebaseID, "pushToGitHub"); err != nil {
+	}).ExecTrunkContext(ctx, codebaseID, "pushToGitHub"); err != nil {
 		logger.Error("failed to push to github", zap.Error(err))
 		// save that the push failed
 		t := time.Now()
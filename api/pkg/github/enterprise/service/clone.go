@@ -94,7 +94,7 @@ func (svc *Service) Clone(
 		AllowRebasingState(). // allowed because the repo does not exist yet
 		Schedule(func(repoProvider provider.RepoProvider) error {
 			return vcs.CloneFromGithub(logger, repoProvider, codebaseID, gitHubRepoDetails, *accessToken.Token)
-		}).ExecTrunk(codebaseID, "clone github repository"); err != nil {
+		}).ExecTrunkContext(ctx, codebaseID, "clone github repository"); err != nil {
 		return fmt.Errorf("cloning failed: %w", err)
 	}
 
@@ -22,6 +22,7 @@ import (
 	github_client "getsturdy.com/api/pkg/github/enterprise/client"
 	vcs_github "getsturdy.com/api/pkg/github/enterprise/vcs"
 	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
+	"getsturdy.com/api/pkg/trunks"
 	"getsturdy.com/api/pkg/users"
 	"getsturdy.com/api/pkg/workspaces"
 )
@@ -199,9 +200,9 @@ func (svc *Service) CreateOrUpdatePullRequest(ctx context.Context, user *users.U
 	// If the repository is _empty_ there is a risk that the branch pushed for the PR is the first branch pushed to GH
 	// If this is the case, first push the sturdytrunk to be the new "master"/"main".
 	// This is done _without_ force, to not screw anything up if we're in the wrong.
-	if err := vcs_github.HaveTrackedBranch(svc.executorProvider, ws.CodebaseID, ghRepo.TrackedBranch); err != nil {
+	if err := vcs_github.HaveTrackedBranch(ctx, svc.executorProvider, ws.CodebaseID, ghRepo.TrackedBranch); err != nil {
 		logger.Info("pushing sturdytrunk to github")
-		userVisibleError, pushTrunkErr := vcs_github.PushBranchToGithubSafely(svc.executorProvider, ws.CodebaseID, "sturdytrunk", ghRepo.TrackedBranch, accessToken)
+		userVisibleError, pushTrunkErr := vcs_github.PushBranchToGithubSafely(ctx, svc.executorProvider, ws.CodebaseID, trunks.BranchName(nil), ghRepo.TrackedBranch, accessToken)
 		if pushTrunkErr != nil {
 			logger.Error("failed to push trunk to github (github is source of truth)", zap.Error(pushTrunkErr))
 
@@ -218,7 +219,7 @@ func (svc *Service) CreateOrUpdatePullRequest(ctx context.Context, user *users.U
 		logger.Info("github have a default branch, not pushing sturdytrunk")
 	}
 
-	userVisibleError, pushErr := vcs_github.PushBranchToGithubWithForce(svc.executorProvider, ws.CodebaseID, prBranch, remoteBranchName, *ghUser.AccessToken)
+	userVisibleError, pushErr := vcs_github.PushBranchToGithubWithForce(ctx, svc.executorProvider, ws.CodebaseID, prBranch, remoteBranchName, *ghUser.AccessToken)
 	if pushErr != nil {
 		logger.Error("failed to push to github (github is source of truth)", zap.Error(pushErr))
 
@@ -201,7 +201,7 @@ func (svc *Service) Push(ctx context.Context, gitHubRepository *github.Repositor
 			return err
 		}
 		return nil
-	}).ExecTrunk(codebaseID, "landChangePushTrackedToGitHub"); err != nil {
+	}).ExecTrunkContext(ctx, codebaseID, "landChangePushTrackedToGitHub"); err != nil {
 		logger.Error("failed to push to github (sturdy is source of truth)", zap.Error(err))
 		// save that the push failed
 		gitHubRepository.LastPushAt = &t
@@ -166,7 +166,7 @@ func (svc *Service) fetchAndSnapshotPullRequest(
 			}
 
 			return nil
-		}).ExecTrunk(workspace.CodebaseID, "gitHubImportBranchFetch"); err != nil {
+		}).ExecTrunkContext(ctx, workspace.CodebaseID, "gitHubImportBranchFetch"); err != nil {
 		return fmt.Errorf("failed to fetch pull to trunk: %w", err)
 	}
 
@@ -195,7 +195,7 @@ func (svc *Service) fetchAndSnapshotPullRequest(
 			}
 
 			return nil
-		}).ExecTemporaryView(workspace.CodebaseID, "gitHubImportBranch"); err != nil {
+		}).ExecTemporaryViewContext(ctx, workspace.CodebaseID, "gitHubImportBranch"); err != nil {
 		return fmt.Errorf("failed to create workspace from pr: %w", err)
 	}
 
@@ -204,7 +204,7 @@ func (svc *Service) fetchAndSnapshotPullRequest(
 			return fmt.Errorf("failed to delete importBranchName: %w", err)
 		}
 		return nil
-	}).ExecTrunk(workspace.CodebaseID, "gitHubImportBranchCleanup"); err != nil {
+	}).ExecTrunkContext(ctx, workspace.CodebaseID, "gitHubImportBranchCleanup"); err != nil {
 		return fmt.Errorf("failed to cleanup import branch: %w", err)
 	}
 
@@ -1,6 +1,7 @@
 package vcs
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/go-git/go-git/v5/config"
@@ -8,20 +9,21 @@ import (
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 
 	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/trunks"
 	"getsturdy.com/api/vcs"
 	"getsturdy.com/api/vcs/executor"
 )
 
 func FetchTrackedToSturdytrunk(accessToken, ref string) func(vcs.RepoGitWriter) error {
 	return func(repo vcs.RepoGitWriter) error {
-		refspec := fmt.Sprintf("+%s:refs/heads/sturdytrunk", ref)
+		refspec := fmt.Sprintf("+%s:refs/heads/%s", ref, trunks.BranchName(nil))
 		if err := repo.FetchNamedRemoteWithCreds("origin", newCredentialsCallback(accessToken), []config.RefSpec{config.RefSpec(refspec)}); err != nil {
 			return fmt.Errorf("failed to perform remote fetch: %w", err)
 		}
 
 		// Make sure that sturdytrunk is the HEAD branch
 		// This is the case for repositories that where empty the first time they where cloned to Sturdy
-		if err := repo.SetDefaultBranch("sturdytrunk"); err != nil {
+		if err := repo.SetDefaultBranch(trunks.BranchName(nil)); err != nil {
 			return fmt.Errorf("could not set default branch: %w", err)
 		}
 		return nil
@@ -50,7 +52,7 @@ func PushToGitHubWithRefspec(repo vcs.RepoGitWriter, accessToken, refspec string
 	return "", nil
 }
 
-func PushBranchToGithubWithForce(executorProvider executor.Provider, codebaseID codebases.ID, sturdyBranchName, remoteBranchName, accessToken string) (userError string, err error) {
+func PushBranchToGithubWithForce(ctx context.Context, executorProvider executor.Provider, codebaseID codebases.ID, sturdyBranchName, remoteBranchName, accessToken string) (userError string, err error) {
 	refspec := fmt.Sprintf("+refs/heads/%s:refs/heads/%s", sturdyBranchName, remoteBranchName)
 
 	err = executorProvider.New().GitWrite(func(r vcs.RepoGitWriter) error {
@@ -59,14 +61,14 @@ func PushBranchToGithubWithForce(executorProvider executor.Provider, codebaseID
 			return fmt.Errorf("failed to push %s: %w", refspec, err)
 		}
 		return nil
-	}).ExecTrunk(codebaseID, "PushBranchToGithubWithForce")
+	}).ExecTrunkContext(ctx, codebaseID, "PushBranchToGithubWithForce")
 	if err != nil {
 		return userError, err
 	}
 	return userError, nil
 }
 
-func PushBranchToGithubSafely(executorProvider executor.Provider, codebaseID codebases.ID, sturdyBranchName, remoteBranchName, accessToken string) (userError string, err error) {
+func PushBranchToGithubSafely(ctx context.Context, executorProvider executor.Provider, codebaseID codebases.ID, sturdyBranchName, remoteBranchName, accessToken string) (userError string, err error) {
 	refspec := fmt.Sprintf("refs/heads/%s:refs/heads/%s", sturdyBranchName, remoteBranchName)
 
 	err = executorProvider.New().GitWrite(func(r vcs.RepoGitWriter) error {
@@ -75,21 +77,21 @@ func PushBranchToGithubSafely(executorProvider executor.Provider, codebaseID cod
 			return fmt.Errorf("failed to push %s: %w", refspec, err)
 		}
 		return nil
-	}).ExecTrunk(codebaseID, "PushBranchToGithubSafely")
+	}).ExecTrunkContext(ctx, codebaseID, "PushBranchToGithubSafely")
 	if err != nil {
 		return userError, err
 	}
 	return userError, nil
 }
 
-func HaveTrackedBranch(executorProvider executor.Provider, codebaseID codebases.ID, remoteBranchName string) error {
+func HaveTrackedBranch(ctx context.Context, executorProvider executor.Provider, codebaseID codebases.ID, remoteBranchName string) error {
 	err := executorProvider.New().GitRead(func(r vcs.RepoGitReader) error {
 		_, err := r.RemoteBranchCommit("origin", remoteBranchName)
 		if err != nil {
 			return fmt.Errorf("could not get remote branch: %w", err)
 		}
 		return nil
-	}).ExecTrunk(codebaseID, "haveTrackedBranch")
+	}).ExecTrunkContext(ctx, codebaseID, "haveTrackedBranch")
 	if err != nil {
 		return err
 	}
@@ -4,4 +4,6 @@ import "getsturdy.com/api/pkg/configuration/flags"
 
 type Configuration struct {
 	Addr flags.Addr `long:"addr" description:"listen address" default:"127.0.0.1:3002"`
+	// SSHSecret authenticates the SSH server, that forwards git commands on behalf of its users.
+	SSHSecret string `long:"ssh-secret" description:"Secret that the SSH server uses to authenticate git requests. Git over SSH is disabled if not set"`
 }
@@ -1,12 +1,16 @@
 package gitserver
 
 import (
+	service_auth "getsturdy.com/api/pkg/auth/service"
 	service_codebase "getsturdy.com/api/pkg/codebases/service"
 	configuration "getsturdy.com/api/pkg/configuration/module"
 	"getsturdy.com/api/pkg/di"
 	service_jwt "getsturdy.com/api/pkg/jwt/service"
 	"getsturdy.com/api/pkg/logger"
+	service_pki "getsturdy.com/api/pkg/pki/service"
+	service_pushedbranches "getsturdy.com/api/pkg/pushedbranches/service"
 	service_servicetokens "getsturdy.com/api/pkg/servicetokens/service"
+	service_trunks "getsturdy.com/api/pkg/trunks/service"
 	"getsturdy.com/api/vcs/executor"
 )
 
@@ -14,8 +18,12 @@ func Module(c *di.Container) {
 	c.Import(logger.Module)
 	c.Import(configuration.Module)
 	c.Import(service_servicetokens.Module)
+	c.Import(service_auth.Module)
 	c.Import(service_jwt.Module)
 	c.Import(service_codebase.Module)
+	c.Import(service_pki.Module)
+	c.Import(service_pushedbranches.Module)
+	c.Import(service_trunks.Module)
 	c.Import(executor.Module)
 	c.Register(New)
 }
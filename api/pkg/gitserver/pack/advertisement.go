@@ -0,0 +1,47 @@
+package pack
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// AddHave adds a ".have" line for the commit to a ref advertisement, telling the client that the server already has
+// the commit (and its history) without advertising it as a ref.
+func AddHave(advertisement []byte, commitSHA string) ([]byte, error) {
+	r := bytes.NewReader(advertisement)
+	first, err := readPktLine(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read advertisement: %w", err)
+	}
+	rest, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read advertisement: %w", err)
+	}
+
+	var out bytes.Buffer
+	if first == nil {
+		// no refs, and no capabilities
+		writePktLine(&out, commitSHA+" .have\n")
+		out.WriteString("0000")
+		out.Write(rest)
+		return out.Bytes(), nil
+	}
+
+	// If there are no refs, the capabilities are sent on a placeholder line. It's replaced by the .have line, as the
+	// placeholder must be the only line in the advertisement.
+	if idx := bytes.IndexByte(first, 0); idx >= 0 && bytes.HasSuffix(first[:idx], []byte(" capabilities^{}")) {
+		writePktLine(&out, commitSHA+" .have"+string(first[idx:]))
+		out.Write(rest)
+		return out.Bytes(), nil
+	}
+
+	writePktLine(&out, string(first))
+	writePktLine(&out, commitSHA+" .have\n")
+	out.Write(rest)
+	return out.Bytes(), nil
+}
+
+func writePktLine(w io.Writer, payload string) {
+	fmt.Fprintf(w, "%04x%s", len(payload)+4, payload)
+}
@@ -0,0 +1,41 @@
+package pack
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestAddHave(t *testing.T) {
+	const sha = "2ab8b0433111e6d5602a71049e40902c1e5a556c"
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "refs",
+			input:    "005ce424d72b9db65aca594f00a39e61a53dbb767ea4 refs/heads/my-branch\x00report-status delete-refs\n0000",
+			expected: "005ce424d72b9db65aca594f00a39e61a53dbb767ea4 refs/heads/my-branch\x00report-status delete-refs\n0033" + sha + " .have\n0000",
+		},
+		{
+			name:     "no-refs",
+			input:    "00570000000000000000000000000000000000000000 capabilities^{}\x00report-status delete-refs\n0000",
+			expected: "004d" + sha + " .have\x00report-status delete-refs\n0000",
+		},
+		{
+			name:     "empty",
+			input:    "0000",
+			expected: "0033" + sha + " .have\n0000",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := AddHave([]byte(tc.input), sha)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, tc.expected, string(out))
+		})
+	}
+}
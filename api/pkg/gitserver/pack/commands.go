@@ -0,0 +1,138 @@
+package pack
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidCommand = errors.New("invalid receive-pack command")
+
+// ZeroSHA is the object name used by git to represent a ref that does not exist.
+const ZeroSHA = "0000000000000000000000000000000000000000"
+
+// Command is a single ref update sent by a client to git-receive-pack.
+type Command struct {
+	OldSHA string
+	NewSHA string
+	Ref    string
+}
+
+// Branch returns the name of the branch that the command updates, or an empty string if the command updates a ref
+// that is not a branch.
+func (c Command) Branch() string {
+	if !strings.HasPrefix(c.Ref, "refs/heads/") {
+		return ""
+	}
+	return strings.TrimPrefix(c.Ref, "refs/heads/")
+}
+
+// IsDelete returns true if the command deletes the ref.
+func (c Command) IsDelete() bool {
+	return c.NewSHA == ZeroSHA
+}
+
+// IsCreate returns true if the command creates the ref.
+func (c Command) IsCreate() bool {
+	return c.OldSHA == ZeroSHA
+}
+
+// ReceivePackRequest is the list of commands, and the capabilities, that a client sends to git-receive-pack.
+type ReceivePackRequest struct {
+	Commands     []Command
+	Capabilities []string
+}
+
+// HasCapability returns true if the client requested the capability.
+func (r *ReceivePackRequest) HasCapability(name string) bool {
+	for _, c := range r.Capabilities {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteError makes the git client abort the push with the message. If the client has requested a side-band, the
+// message is sent on the error band, otherwise it's sent as an ERR pkt-line.
+func (r *ReceivePackRequest) WriteError(w io.Writer, message string) error {
+	var payload string
+	if r.HasCapability("side-band-64k") || r.HasCapability("side-band") {
+		payload = "\x03" + message + "\n"
+	} else {
+		payload = "ERR " + message + "\n"
+	}
+	if _, err := fmt.Fprintf(w, "%04x%s", len(payload)+4, payload); err != nil {
+		return fmt.Errorf("failed to write error: %w", err)
+	}
+	return nil
+}
+
+// ReadReceivePackRequest reads the pkt-lines that a client sends to git-receive-pack after the ref advertisement, up
+// to and including the terminating flush-pkt. The raw bytes that were read are returned together with the parsed
+// request, so that they can be forwarded to git-receive-pack as-is.
+func ReadReceivePackRequest(r io.Reader) (*ReceivePackRequest, []byte, error) {
+	var raw bytes.Buffer
+	req := &ReceivePackRequest{}
+	for {
+		line, err := readPktLine(io.TeeReader(r, &raw))
+		if err != nil {
+			return nil, nil, err
+		}
+		if line == nil { // flush-pkt
+			return req, raw.Bytes(), nil
+		}
+
+		line = bytes.TrimSuffix(line, []byte("\n"))
+
+		// capabilities are sent after a NUL on the first line
+		if idx := bytes.IndexByte(line, 0); idx >= 0 {
+			req.Capabilities = append(req.Capabilities, strings.Fields(string(line[idx+1:]))...)
+			line = line[:idx]
+		}
+
+		if bytes.HasPrefix(line, []byte("shallow ")) {
+			continue
+		}
+
+		parts := strings.Split(string(line), " ")
+		if len(parts) != 3 || len(parts[0]) != len(ZeroSHA) || len(parts[1]) != len(ZeroSHA) {
+			return nil, nil, fmt.Errorf("%w: %q", ErrInvalidCommand, line)
+		}
+
+		req.Commands = append(req.Commands, Command{
+			OldSHA: parts[0],
+			NewSHA: parts[1],
+			Ref:    parts[2],
+		})
+	}
+}
+
+// readPktLine reads a single pkt-line, and returns its payload. A nil payload is returned for flush-pkts.
+func readPktLine(r io.Reader) ([]byte, error) {
+	var lengthHex [4]byte
+	if _, err := io.ReadFull(r, lengthHex[:]); err != nil {
+		return nil, fmt.Errorf("failed to read pkt-line length: %w", err)
+	}
+
+	length, err := strconv.ParseUint(string(lengthHex[:]), 16, 16)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid pkt-line length %q", ErrInvalidCommand, lengthHex)
+	}
+
+	if length == 0 {
+		return nil, nil
+	}
+	if length < 4 {
+		return nil, fmt.Errorf("%w: invalid pkt-line length %q", ErrInvalidCommand, lengthHex)
+	}
+
+	payload := make([]byte, length-4)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, fmt.Errorf("failed to read pkt-line: %w", err)
+	}
+	return payload, nil
+}
@@ -0,0 +1,96 @@
+package pack
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestReadReceivePackRequest(t *testing.T) {
+	tests := []struct {
+		name                 string
+		input                string
+		expected             []Command
+		expectedCapabilities []string
+		expectedErr          error
+	}{
+		{
+			name:  "single",
+			input: "00a80000000000000000000000000000000000000000 2ab8b0433111e6d5602a71049e40902c1e5a556c refs/heads/my-branch\x00 report-status side-band-64k agent=git/2.24.3.(Apple.Git-128)0000PACK",
+			expected: []Command{
+				{OldSHA: ZeroSHA, NewSHA: "2ab8b0433111e6d5602a71049e40902c1e5a556c", Ref: "refs/heads/my-branch"},
+			},
+			expectedCapabilities: []string{"report-status", "side-band-64k", "agent=git/2.24.3.(Apple.Git-128)"},
+		},
+		{
+			name: "multiple",
+			input: "00a80000000000000000000000000000000000000000 2ab8b0433111e6d5602a71049e40902c1e5a556c refs/heads/my-branch\x00 report-status side-band-64k agent=git/2.24.3.(Apple.Git-128)" +
+				"0067e424d72b9db65aca594f00a39e61a53dbb767ea4 0000000000000000000000000000000000000000 refs/heads/other\n" +
+				"0000PACK",
+			expected: []Command{
+				{OldSHA: ZeroSHA, NewSHA: "2ab8b0433111e6d5602a71049e40902c1e5a556c", Ref: "refs/heads/my-branch"},
+				{OldSHA: "e424d72b9db65aca594f00a39e61a53dbb767ea4", NewSHA: ZeroSHA, Ref: "refs/heads/other"},
+			},
+			expectedCapabilities: []string{"report-status", "side-band-64k", "agent=git/2.24.3.(Apple.Git-128)"},
+		},
+		{
+			name:  "empty",
+			input: "0000",
+		},
+		{
+			name:        "invalid",
+			input:       "000bhello0000",
+			expectedErr: ErrInvalidCommand,
+		},
+		{
+			name:        "truncated",
+			input:       "00a80000000000",
+			expectedErr: io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := bytes.NewReader([]byte(tc.input))
+			req, raw, err := ReadReceivePackRequest(r)
+			if tc.expectedErr != nil {
+				assert.Equal(t, true, errors.Is(err, tc.expectedErr))
+				return
+			}
+			assert.Equal(t, nil, err)
+			assert.Equal(t, tc.expected, req.Commands)
+			assert.Equal(t, tc.expectedCapabilities, req.Capabilities)
+
+			// the raw bytes and the remaining bytes make up the full input
+			rest, _ := io.ReadAll(r)
+			assert.Equal(t, tc.input, string(raw)+string(rest))
+		})
+	}
+}
+
+func TestCommand(t *testing.T) {
+	c := Command{OldSHA: ZeroSHA, NewSHA: "2ab8b0433111e6d5602a71049e40902c1e5a556c", Ref: "refs/heads/my-branch"}
+	assert.Equal(t, "my-branch", c.Branch())
+	assert.Equal(t, true, c.IsCreate())
+	assert.Equal(t, false, c.IsDelete())
+
+	c = Command{OldSHA: "2ab8b0433111e6d5602a71049e40902c1e5a556c", NewSHA: ZeroSHA, Ref: "refs/tags/v1"}
+	assert.Equal(t, "", c.Branch())
+	assert.Equal(t, false, c.IsCreate())
+	assert.Equal(t, true, c.IsDelete())
+}
+
+func TestReceivePackRequest_WriteError(t *testing.T) {
+	var buf bytes.Buffer
+	req := &ReceivePackRequest{}
+	assert.Equal(t, nil, req.WriteError(&buf, "nope"))
+	assert.Equal(t, "000dERR nope\n", buf.String())
+
+	buf.Reset()
+	req = &ReceivePackRequest{Capabilities: []string{"report-status", "side-band-64k"}}
+	assert.Equal(t, nil, req.WriteError(&buf, "nope"))
+	assert.Equal(t, "000a\x03nope\n", buf.String())
+}
@@ -11,14 +11,18 @@ import (
 	"strings"
 	"time"
 
+	service_auth "getsturdy.com/api/pkg/auth/service"
 	"getsturdy.com/api/pkg/codebases"
 	service_codebase "getsturdy.com/api/pkg/codebases/service"
 	"getsturdy.com/api/pkg/gitserver/configuration"
 	"getsturdy.com/api/pkg/gitserver/pack"
 	"getsturdy.com/api/pkg/jwt"
 	service_jwt "getsturdy.com/api/pkg/jwt/service"
+	service_pki "getsturdy.com/api/pkg/pki/service"
+	service_pushedbranches "getsturdy.com/api/pkg/pushedbranches/service"
 	"getsturdy.com/api/pkg/servicetokens"
 	service_servicetokens "getsturdy.com/api/pkg/servicetokens/service"
+	service_trunks "getsturdy.com/api/pkg/trunks/service"
 	"getsturdy.com/api/pkg/users"
 	"getsturdy.com/api/pkg/version"
 	"getsturdy.com/api/vcs"
@@ -33,10 +37,14 @@ type Server struct {
 	logger *zap.Logger
 	cfg    *configuration.Configuration
 
-	serviceTokensService *service_servicetokens.Service
-	jwtTokensService     *service_jwt.Service
-	codebaseService      *service_codebase.Service
-	executorProvider     executor.Provider
+	serviceTokensService  *service_servicetokens.Service
+	authService           *service_auth.Service
+	jwtTokensService      *service_jwt.Service
+	codebaseService       *service_codebase.Service
+	pkiService            *service_pki.Service
+	pushedBranchesService *service_pushedbranches.Service
+	trunksService         *service_trunks.Service
+	executorProvider      executor.Provider
 
 	router *gin.Engine
 }
@@ -52,8 +60,12 @@ func New(
 	logger *zap.Logger,
 	cfg *configuration.Configuration,
 	serviceTokensService *service_servicetokens.Service,
+	authService *service_auth.Service,
 	jwtTokensService *service_jwt.Service,
 	codebaeService *service_codebase.Service,
+	pkiService *service_pki.Service,
+	pushedBranchesService *service_pushedbranches.Service,
+	trunksService *service_trunks.Service,
 	executorProvider executor.Provider,
 ) *Server {
 	gin.SetMode(ginMode())
@@ -63,10 +75,14 @@ func New(
 		logger: logger,
 		cfg:    cfg,
 
-		serviceTokensService: serviceTokensService,
-		jwtTokensService:     jwtTokensService,
-		codebaseService:      codebaeService,
-		executorProvider:     executorProvider,
+		serviceTokensService:  serviceTokensService,
+		authService:           authService,
+		jwtTokensService:      jwtTokensService,
+		codebaseService:       codebaeService,
+		pkiService:            pkiService,
+		pushedBranchesService: pushedBranchesService,
+		trunksService:         trunksService,
+		executorProvider:      executorProvider,
 
 		router: ginRouter,
 	}
@@ -84,6 +100,15 @@ func (h *Server) Start() error {
 	importGroup.GET("/info/refs", h.handleInfoRefs)
 	importGroup.POST("/git-receive-pack", h.handleGitReceivePack)
 
+	// the identity headers of the ssh routes are only trusted from the SSH server, that has to know the secret
+	if h.cfg.SSHSecret != "" {
+		sshGroup := h.router.Group("/ssh/:codebaseId").Use(h.sshSecretAuth, h.sshAuth)
+		sshGroup.POST("/git-upload-pack", h.handleSSHGitUploadPack)
+		sshGroup.POST("/git-receive-pack", h.handleSSHGitReceivePack)
+	} else {
+		h.logger.Warn("git over ssh is disabled, the ssh secret is not configured")
+	}
+
 	h.logger.Info("starting gitserver", zap.Stringer("addr", h.cfg.Addr))
 
 	if err := h.router.Run(h.cfg.Addr.String()); !errors.Is(err, http.ErrServerClosed) {
@@ -213,9 +238,16 @@ func (h *Server) handleGitReceivePack(c *gin.Context) {
 			return
 		}
 
-		if header.Branch != "sturdytrunk" {
+		// only the trunks of the codebase can be pushed to
+		isTrunk, err := h.trunksService.IsBranchName(c.Request.Context(), codebaseID, header.Branch)
+		if err != nil {
+			h.logger.Error("receive-pack failed to validate branch", zap.Error(err))
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		if !isTrunk {
 			c.AbortWithStatus(http.StatusBadRequest)
-			h.logger.Error("receive-pack request to non sturdytrunk branch",
+			h.logger.Error("receive-pack request to non trunk branch",
 				zap.String("branch", header.Branch),
 				zap.Stringer("codebase_id", codebaseID),
 			)
@@ -241,7 +273,7 @@ func (h *Server) handleGitReceivePack(c *gin.Context) {
 		}
 
 		return nil
-	}).ExecTrunk(codebaseID, "gitserverGitReceivePack"); err != nil {
+	}).ExecTrunkContext(c.Request.Context(), codebaseID, "gitserverGitReceivePack"); err != nil {
 		h.logger.Error("failed to handle git receive pack", zap.Error(err))
 		c.AbortWithError(http.StatusInternalServerError, err)
 		return
@@ -271,7 +303,7 @@ func (h *Server) handleGitUploadPack(c *gin.Context) {
 		}
 
 		return nil
-	}).ExecView(token.CodebaseID, ciRepo, "gitserverGitUploadPack"); err != nil {
+	}).ExecViewContext(c.Request.Context(), token.CodebaseID, ciRepo, "gitserverGitUploadPack"); err != nil {
 		h.logger.Error("failed to handle git upload pack", zap.Error(err))
 		c.AbortWithError(http.StatusInternalServerError, err)
 		return
@@ -310,13 +342,13 @@ func (h *Server) handleInfoRefs(c *gin.Context) {
 	})
 
 	if token := getToken(c); token != nil { // this is ci flow
-		if err := executor.ExecView(token.CodebaseID, ciRepo, "gitserverInfoRefs"); err != nil {
+		if err := executor.ExecViewContext(c.Request.Context(), token.CodebaseID, ciRepo, "gitserverInfoRefs"); err != nil {
 			h.logger.Error("failed to handle info refs", zap.Error(err))
 			c.AbortWithError(http.StatusInternalServerError, err)
 			return
 		}
 	} else { // this is import flow
-		if err := executor.ExecTrunk(codebases.ID(c.Param("codebaseId")), "gitserverInfoRefs"); err != nil {
+		if err := executor.ExecTrunkContext(c.Request.Context(), codebases.ID(c.Param("codebaseId")), "gitserverInfoRefs"); err != nil {
 			h.logger.Error("failed to handle info refs", zap.Error(err))
 			c.AbortWithError(http.StatusInternalServerError, err)
 			return
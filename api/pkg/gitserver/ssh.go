@@ -0,0 +1,272 @@
+package gitserver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+
+	"getsturdy.com/api/pkg/auth"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/gitserver/pack"
+	service_pki "getsturdy.com/api/pkg/pki/service"
+	"getsturdy.com/api/pkg/pushedbranches"
+	service_pushedbranches "getsturdy.com/api/pkg/pushedbranches/service"
+	"getsturdy.com/api/pkg/trunks"
+	"getsturdy.com/api/pkg/users"
+	"getsturdy.com/api/vcs"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// The SSH server forwards git commands to the gitserver by upgrading a HTTP request to a raw, bidirectional stream. The
+// SSH server has already authenticated the session with the users public key, which is passed on to the gitserver so
+// that the key and the codebase membership can be validated here. The SSH server itself is authenticated with a shared
+// secret, the identity headers are not trusted from anyone else.
+const (
+	SSHUserIDHeader    = "X-Sturdy-User-ID"
+	SSHPublicKeyHeader = "X-Sturdy-Public-Key"
+	sshUpgradeProtocol = "git"
+)
+
+func (h *Server) sshSecretAuth(c *gin.Context) {
+	expected := []byte("Bearer " + h.cfg.SSHSecret)
+	if subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), expected) != 1 {
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+}
+
+func (h *Server) sshAuth(c *gin.Context) {
+	userID := users.ID(c.GetHeader(SSHUserIDHeader))
+	publicKey, err := base64.StdEncoding.DecodeString(c.GetHeader(SSHPublicKeyHeader))
+	if userID == "" || err != nil || len(publicKey) == 0 {
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.pkiService.Verify(c.Request.Context(), userID, publicKey); errors.Is(err, service_pki.ErrNotFound) {
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	} else if err != nil {
+		h.logger.Error("failed to verify public key", zap.Error(err))
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	codebaseID := codebases.ID(c.Param("codebaseId"))
+	accessAllowed, err := h.codebaseService.CanAccess(c.Request.Context(), userID, codebaseID)
+	if err != nil {
+		h.logger.Error("failed to check access", zap.Error(err))
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	if !accessAllowed {
+		c.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+
+	c.Set(userIDKey, string(userID))
+}
+
+// upgrade takes over the connection of the request, and tells the client that the rest of the connection is a raw
+// stream.
+func upgrade(c *gin.Context) (net.Conn, *bufio.ReadWriter, error) {
+	if c.GetHeader("Upgrade") != sshUpgradeProtocol {
+		c.AbortWithStatus(http.StatusUpgradeRequired)
+		return nil, nil, fmt.Errorf("unexpected upgrade protocol: %q", c.GetHeader("Upgrade"))
+	}
+
+	conn, rw, err := c.Writer.Hijack()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to hijack connection: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(rw, "HTTP/1.1 %d %s\r\nConnection: Upgrade\r\nUpgrade: %s\r\n\r\n",
+		http.StatusSwitchingProtocols, http.StatusText(http.StatusSwitchingProtocols), sshUpgradeProtocol,
+	); err != nil {
+		_ = conn.Close()
+		return nil, nil, fmt.Errorf("failed to write upgrade response: %w", err)
+	}
+	if err := rw.Flush(); err != nil {
+		_ = conn.Close()
+		return nil, nil, fmt.Errorf("failed to write upgrade response: %w", err)
+	}
+
+	return conn, rw, nil
+}
+
+// canReadAll returns true if the user can read all files in the codebase. A clone or fetch can not leave out files, so
+// users that have files hidden from them by the ACL of the codebase can not clone or fetch it.
+func (h *Server) canReadAll(ctx context.Context, codebaseID codebases.ID, userID users.ID) (bool, error) {
+	ctx = auth.NewContext(ctx, &auth.Subject{ID: userID.String(), Type: auth.SubjectUser})
+	allower, err := h.authService.GetAllower(ctx, &codebases.Codebase{ID: codebaseID})
+	if err != nil {
+		return false, fmt.Errorf("failed to get allower: %w", err)
+	}
+	return allower.AllowsAll(), nil
+}
+
+func (h *Server) handleSSHGitUploadPack(c *gin.Context) {
+	codebaseID := codebases.ID(c.Param("codebaseId"))
+	userID := users.ID(c.GetString(userIDKey))
+	logger := h.logger.With(zap.Stringer("codebase_id", codebaseID), zap.Stringer("user_id", userID))
+
+	if canReadAll, err := h.canReadAll(c.Request.Context(), codebaseID, userID); err != nil {
+		logger.Error("failed to check read access", zap.Error(err))
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	} else if !canReadAll {
+		c.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+
+	conn, rw, err := upgrade(c)
+	if err != nil {
+		logger.Error("failed to upgrade connection", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	if err := h.executorProvider.New().Read(func(repo vcs.RepoReader) error {
+		cmd := exec.Command("git", "upload-pack", repo.Path())
+		cmd.Stdin = rw.Reader
+		cmd.Stdout = conn
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to run upload-pack: %w", err)
+		}
+		return nil
+	}).ExecTrunkContext(c.Request.Context(), codebaseID, "gitserverSSHGitUploadPack"); err != nil {
+		logger.Error("failed to handle ssh git upload pack", zap.Error(err))
+		return
+	}
+}
+
+// handleSSHGitReceivePack receives branches that are pushed by the user. The branches are stored in a git namespace
+// owned by the user, and every pushed branch is turned into a workspace.
+func (h *Server) handleSSHGitReceivePack(c *gin.Context) {
+	codebaseID := codebases.ID(c.Param("codebaseId"))
+	userID := users.ID(c.GetString(userIDKey))
+	logger := h.logger.With(zap.Stringer("codebase_id", codebaseID), zap.Stringer("user_id", userID))
+
+	conn, rw, err := upgrade(c)
+	if err != nil {
+		logger.Error("failed to upgrade connection", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	// Read the rest of the push before responding with an error, the client does not read the response until it has
+	// sent everything.
+	reject := func(req *pack.ReceivePackRequest, message string) {
+		_, _ = io.Copy(io.Discard, rw.Reader)
+		_ = req.WriteError(conn, message)
+	}
+
+	var pushed []pack.Command
+	if err := h.executorProvider.New().Write(func(repo vcs.RepoWriter) error {
+		namespaceEnv := "GIT_NAMESPACE=" + pushedbranches.RefNamespace(userID)
+
+		// The ref advertisement is the same for stateful and stateless connections. Advertise the refs, and read the
+		// commands that the client sends back, so that they can be validated before anything is written.
+		var advertisement bytes.Buffer
+		advertise := exec.Command("git", "receive-pack", "--stateless-rpc", "--advertise-refs", repo.Path())
+		advertise.Env = append(os.Environ(), namespaceEnv)
+		advertise.Stdout = &advertisement
+		if err := advertise.Run(); err != nil {
+			return fmt.Errorf("failed to advertise refs: %w", err)
+		}
+
+		// The trunk is not a part of the namespace, let the client know that it does not need to send it.
+		advertised := advertisement.Bytes()
+		if trunkCommitSHA, err := repo.BranchCommitID(trunks.BranchName(nil)); err == nil {
+			if advertised, err = pack.AddHave(advertised, trunkCommitSHA); err != nil {
+				return fmt.Errorf("failed to add trunk to advertisement: %w", err)
+			}
+		}
+		if _, err := conn.Write(advertised); err != nil {
+			return fmt.Errorf("failed to write advertisement: %w", err)
+		}
+
+		req, raw, err := pack.ReadReceivePackRequest(rw.Reader)
+		if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+			// the client hung up without pushing anything
+			return nil
+		} else if err != nil {
+			_ = (&pack.ReceivePackRequest{}).WriteError(conn, "invalid push")
+			return fmt.Errorf("failed to read commands: %w", err)
+		}
+
+		if len(req.Commands) == 0 {
+			// nothing to update
+			return nil
+		}
+
+		for _, command := range req.Commands {
+			switch {
+			case command.Branch() == "":
+				reject(req, fmt.Sprintf("pushing to %s is not allowed, only branches can be pushed", command.Ref))
+				return nil
+			case trunks.IsBranchName(command.Branch()):
+				reject(req, fmt.Sprintf("pushing to %s is not allowed, push a branch to create a draft", command.Branch()))
+				return nil
+			case command.IsDelete():
+				reject(req, fmt.Sprintf("deleting %s is not allowed", command.Ref))
+				return nil
+			}
+
+			if err := h.pushedBranchesService.Validate(c.Request.Context(), codebaseID, userID, command.Branch()); errors.Is(err, service_pushedbranches.ErrWorkspaceHasView) {
+				reject(req, fmt.Sprintf("the draft of %s is open in a view, and can not be pushed to", command.Ref))
+				return nil
+			} else if err != nil {
+				reject(req, "internal error")
+				return fmt.Errorf("failed to validate push: %w", err)
+			}
+		}
+
+		cmd := exec.Command("git", "receive-pack", "--stateless-rpc", repo.Path())
+		cmd.Env = append(os.Environ(), namespaceEnv)
+		cmd.Stdin = io.MultiReader(bytes.NewReader(raw), rw.Reader)
+		cmd.Stdout = conn
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to run receive-pack: %w", err)
+		}
+
+		// Only the refs that have been updated are turned into workspaces.
+		for _, command := range req.Commands {
+			out, err := exec.Command("git", "-C", repo.Path(), "rev-parse", "--verify", "--quiet", pushedbranches.Ref(userID, command.Branch())).Output()
+			if err != nil {
+				logger.Warn("pushed branch was not updated", zap.String("ref", command.Ref), zap.Error(err))
+				continue
+			}
+			if strings.TrimSpace(string(out)) != command.NewSHA {
+				logger.Warn("pushed branch was not updated", zap.String("ref", command.Ref))
+				continue
+			}
+			pushed = append(pushed, command)
+		}
+
+		return nil
+	}).ExecTrunkContext(c.Request.Context(), codebaseID, "gitserverSSHGitReceivePack"); err != nil {
+		logger.Error("failed to handle ssh git receive pack", zap.Error(err))
+		return
+	}
+
+	for _, command := range pushed {
+		if _, err := h.pushedBranchesService.Push(c.Request.Context(), codebaseID, userID, command.Branch(), command.NewSHA); err != nil {
+			logger.Error("failed to push branch", zap.String("ref", command.Ref), zap.Error(err))
+			continue
+		}
+	}
+}
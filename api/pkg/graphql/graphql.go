@@ -34,6 +34,7 @@ type RootResolver struct {
 	resolvers.ActivityRootResolver
 	resolvers.AuthorRootResolver
 	resolvers.BuildkiteInstantIntegrationRootResolver
+	resolvers.WebhookInstantIntegrationRootResolver
 	resolvers.ChangeRootResolver
 	resolvers.CodebaseGitHubIntegrationRootResolver
 	resolvers.CodebaseRootResolver
@@ -62,6 +63,11 @@ type RootResolver struct {
 	resolvers.WorkspaceWatcherRootResolver
 	resolvers.LandRootResovler
 	resolvers.SnapshotsRootResolver
+	resolvers.MergeQueueRootResolver
+	resolvers.LandingRulesRootResolver
+	resolvers.TrunksRootResolver
+	resolvers.WebhooksRootResolver
+	resolvers.ReleasesRootResolver
 
 	schema     *graphql.Schema
 	jwtService *service_jwt.Service
@@ -76,6 +82,7 @@ func NewRootResolver(
 	activityRootResolver resolvers.ActivityRootResolver,
 	authorRootResolver resolvers.AuthorRootResolver,
 	buildkiteRootResolver resolvers.BuildkiteInstantIntegrationRootResolver,
+	webhookRootResolver resolvers.WebhookInstantIntegrationRootResolver,
 	changeRootResolver resolvers.ChangeRootResolver,
 	codebaseGitHubIntegrationRootResolver resolvers.CodebaseGitHubIntegrationRootResolver,
 	codebaseRootResolver resolvers.CodebaseRootResolver,
@@ -104,6 +111,11 @@ func NewRootResolver(
 	workspaceWatcherRootResolver resolvers.WorkspaceWatcherRootResolver,
 	landRootResolver resolvers.LandRootResovler,
 	snapshotsRootResolver resolvers.SnapshotsRootResolver,
+	mergeQueueRootResolver resolvers.MergeQueueRootResolver,
+	landingRulesRootResolver resolvers.LandingRulesRootResolver,
+	trunksRootResolver resolvers.TrunksRootResolver,
+	webhooksRootResolver resolvers.WebhooksRootResolver,
+	releasesRootResolver resolvers.ReleasesRootResolver,
 ) *RootResolver {
 	r := &RootResolver{
 		jwtService: jwtService,
@@ -113,6 +125,7 @@ func NewRootResolver(
 		ActivityRootResolver:                    activityRootResolver,
 		AuthorRootResolver:                      authorRootResolver,
 		BuildkiteInstantIntegrationRootResolver: buildkiteRootResolver,
+		WebhookInstantIntegrationRootResolver:   webhookRootResolver,
 		ChangeRootResolver:                      changeRootResolver,
 		CodebaseGitHubIntegrationRootResolver:   codebaseGitHubIntegrationRootResolver,
 		CodebaseRootResolver:                    codebaseRootResolver,
@@ -141,6 +154,11 @@ func NewRootResolver(
 		WorkspaceWatcherRootResolver:            workspaceWatcherRootResolver,
 		LandRootResovler:                        landRootResolver,
 		SnapshotsRootResolver:                   snapshotsRootResolver,
+		MergeQueueRootResolver:                  mergeQueueRootResolver,
+		LandingRulesRootResolver:                landingRulesRootResolver,
+		TrunksRootResolver:                      trunksRootResolver,
+		WebhooksRootResolver:                    webhooksRootResolver,
+		ReleasesRootResolver:                    releasesRootResolver,
 	}
 
 	logger = logger.Named("graphql")
@@ -14,14 +14,20 @@ import (
 	graphql_installations "getsturdy.com/api/pkg/installations/graphql/module"
 	service_jwt "getsturdy.com/api/pkg/jwt/service"
 	graphql_land "getsturdy.com/api/pkg/land/graphql"
+	graphql_landingrules "getsturdy.com/api/pkg/landingrules/graphql"
 	graphql_licenses "getsturdy.com/api/pkg/licenses/graphql"
 	"getsturdy.com/api/pkg/logger"
+	graphql_mergequeue "getsturdy.com/api/pkg/mergequeue/graphql"
 	graphql_notification "getsturdy.com/api/pkg/notification/graphql"
 	graphql_onboarding "getsturdy.com/api/pkg/onboarding/graphql"
 	graphql_organizations "getsturdy.com/api/pkg/organization/graphql"
 	graphql_pki "getsturdy.com/api/pkg/pki/graphql"
+	graphql_releases "getsturdy.com/api/pkg/releases/graphql"
 	graphql_servicetokens "getsturdy.com/api/pkg/servicetokens/graphql"
 	graphql_snapshots "getsturdy.com/api/pkg/snapshots/graphql"
+	graphql_trunks "getsturdy.com/api/pkg/trunks/graphql"
+	graphql_webhookci "getsturdy.com/api/pkg/webhookci/graphql/module"
+	graphql_webhooks "getsturdy.com/api/pkg/webhooks/graphql"
 )
 
 func Module(c *di.Container) {
@@ -30,6 +36,7 @@ func Module(c *di.Container) {
 	c.Import(graphql_acl.Module)
 	c.Import(graphql_activity.Module)
 	c.Import(graphql_buildkite.Module)
+	c.Import(graphql_webhookci.Module)
 	c.Import(graphql_changes.Module)
 	c.Import(graphql_github.Module)
 	c.Import(graphql_codebases.Module)
@@ -45,5 +52,10 @@ func Module(c *di.Container) {
 	c.Import(graphql_servicetokens.Module)
 	c.Import(graphql_land.Module)
 	c.Import(graphql_snapshots.Module)
+	c.Import(graphql_mergequeue.Module)
+	c.Import(graphql_landingrules.Module)
+	c.Import(graphql_trunks.Module)
+	c.Import(graphql_webhooks.Module)
+	c.Import(graphql_releases.Module)
 	c.Register(NewRootResolver)
 }
@@ -12,6 +12,8 @@ type ACLRootResolver interface {
 
 	// Queries
 	CanI(ctx context.Context, args CanIArgs) (bool, error)
+	ExplainACL(ctx context.Context, args ExplainACLArgs) (ACLExplanationResolver, error)
+	ValidateACL(ctx context.Context, args ValidateACLArgs) (ACLValidationResolver, error)
 
 	// Mutations
 	UpdateACL(ctx context.Context, args UpdateACLArgs) (ACLResolver, error)
@@ -23,6 +25,27 @@ type CanIArgs struct {
 	Resource   string
 }
 
+type ExplainACLArgs struct {
+	Input ExplainACLInput
+}
+
+type ExplainACLInput struct {
+	CodebaseID graphql.ID
+	Principal  string
+	Action     string
+	Resource   string
+	Policy     *string
+}
+
+type ValidateACLArgs struct {
+	Input ValidateACLInput
+}
+
+type ValidateACLInput struct {
+	CodebaseID graphql.ID
+	Policy     string
+}
+
 type UpdateACLArgs struct {
 	Input UpdateACLInput
 }
@@ -36,3 +59,31 @@ type ACLResolver interface {
 	ID() graphql.ID
 	Policy() (string, error)
 }
+
+type ACLExplanationResolver interface {
+	Allowed() bool
+	MatchedRules() []ACLRuleResolver
+	MatchedGroups() []string
+}
+
+type ACLRuleResolver interface {
+	ID() string
+	Effect() string
+	Action() string
+}
+
+type ACLValidationResolver interface {
+	Valid() bool
+	Errors() []ACLPolicyErrorResolver
+	Tests() []ACLTestResultResolver
+}
+
+type ACLPolicyErrorResolver interface {
+	Path() string
+	Message() string
+}
+
+type ACLTestResultResolver interface {
+	ID() string
+	Passed() bool
+}
@@ -12,7 +12,7 @@ import (
 )
 
 type ChangeRootResolver interface {
-	InternalListChanges(ctx context.Context, codebaseID codebases.ID, limit int, before *graphql.ID) ([]ChangeResolver, error)
+	InternalListChanges(ctx context.Context, codebaseID codebases.ID, limit int, before *graphql.ID, trunkID *graphql.ID) ([]ChangeResolver, error)
 
 	Change(ctx context.Context, args ChangeArgs) (ChangeResolver, error)
 }
@@ -45,13 +45,15 @@ type UpdateCodebaseArgs struct {
 }
 
 type UpdateCodebaseInput struct {
-	ID                   graphql.ID
-	Name                 *string
-	DisableInviteCode    *bool
-	GenerateInviteCode   *bool
-	Archive              *bool
-	IsPublic             *bool
-	RequireHealthyStatus *bool
+	ID                        graphql.ID
+	Name                      *string
+	DisableInviteCode         *bool
+	GenerateInviteCode        *bool
+	Archive                   *bool
+	IsPublic                  *bool
+	RequireHealthyStatus      *bool
+	MergeQueueEnabled         *bool
+	RequireCodeOwnersApproval *bool
 }
 
 type CodebaseResolver interface {
@@ -78,7 +80,13 @@ type CodebaseResolver interface {
 	IsPublic() bool
 	Organization(ctx context.Context) (OrganizationResolver, error)
 	Remote(context.Context) (RemoteResolver, error)
+	LandingRules(context.Context) (LandingRulesResolver, error)
+	Trunks(context.Context) ([]TrunkResolver, error)
+	Webhooks(context.Context) ([]WebhookResolver, error)
+	Releases(context.Context) ([]ReleaseResolver, error)
 	RequireHealthyStatus() bool
+	MergeQueueEnabled() bool
+	RequireCodeOwnersApproval() bool
 
 	Writeable(context.Context) bool
 }
@@ -88,8 +96,9 @@ type CodebaseChangesArgs struct {
 }
 
 type CodebaseChangesInput struct {
-	Before *graphql.ID
-	Limit  *int32
+	Before  *graphql.ID
+	Limit   *int32
+	TrunkID *graphql.ID
 }
 
 type CodebaseFileArgs struct {
@@ -136,4 +145,5 @@ type PushCodebaseArgs struct {
 
 type PushCodebaseInput struct {
 	CodebaseID graphql.ID
+	TrunkID    *graphql.ID
 }
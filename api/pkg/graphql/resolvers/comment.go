@@ -114,6 +114,6 @@ type CommentCodeContext interface {
 	LineStart() int32
 	LineEnd() int32
 	LineIsNew() bool
-	Context() string
+	Context(context.Context) (string, error)
 	ContextStartsAtLine() int32
 }
@@ -0,0 +1,33 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type WebhookInstantIntegrationRootResolver interface {
+	// mutations
+	CreateOrUpdateWebhookIntegration(context.Context, CreateOrUpdateWebhookIntegrationArgs) (IntegrationResolver, error)
+
+	// internal
+	InternalWebhookConfigurationByIntegrationID(context.Context, string) (WebhookConfigurationResolver, error)
+}
+
+type CreateOrUpdateWebhookIntegrationArgs struct {
+	Input CreateOrUpdateWebhookIntegrationInput
+}
+
+type CreateOrUpdateWebhookIntegrationInput struct {
+	CodebaseID    graphql.ID
+	IntegrationID *graphql.ID
+	URL           string
+	Secret        string
+}
+
+type WebhookConfigurationResolver interface {
+	ID() graphql.ID
+	URL() string
+	Secret() string
+	CallbackURL() string
+}
@@ -53,8 +53,15 @@ type BuildkiteIntegration interface {
 	Configuration(context.Context) (BuildkiteConfigurationResolver, error)
 }
 
+type WebhookIntegration interface {
+	commonIntegrationResolver
+
+	Configuration(context.Context) (WebhookConfigurationResolver, error)
+}
+
 type IntegrationResolver interface {
 	ToBuildkiteIntegration() (BuildkiteIntegration, bool)
+	ToWebhookIntegration() (WebhookIntegration, bool)
 
 	commonIntegrationResolver
 }
@@ -64,4 +71,5 @@ type InstantIntegrationProviderType string
 const (
 	InstantIntegrationProviderUndefined InstantIntegrationProviderType = ""
 	InstantIntegrationProviderBuildkite InstantIntegrationProviderType = "Buildkite"
+	InstantIntegrationProviderWebhook   InstantIntegrationProviderType = "Webhook"
 )
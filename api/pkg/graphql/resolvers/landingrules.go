@@ -0,0 +1,35 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"getsturdy.com/api/pkg/codebases"
+)
+
+type LandingRulesRootResolver interface {
+	InternalLandingRulesByCodebaseID(ctx context.Context, codebaseID codebases.ID) (LandingRulesResolver, error)
+
+	// Mutations
+	UpdateLandingRules(ctx context.Context, args UpdateLandingRulesArgs) (LandingRulesResolver, error)
+}
+
+type LandingRulesResolver interface {
+	MinApprovals() int32
+	RequireResolvedComments() bool
+	RequiredStatuses() []string
+	RequireUpToDateWithTrunk() bool
+}
+
+type UpdateLandingRulesArgs struct {
+	Input UpdateLandingRulesInput
+}
+
+type UpdateLandingRulesInput struct {
+	CodebaseID               graphql.ID
+	MinApprovals             *int32
+	RequireResolvedComments  *bool
+	RequiredStatuses         *[]string
+	RequireUpToDateWithTrunk *bool
+}
@@ -0,0 +1,50 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type MergeQueueRootResolver interface {
+	MergeQueue(context.Context, MergeQueueArgs) ([]MergeQueueEntryResolver, error)
+
+	// Mutations
+	DequeueWorkspace(context.Context, DequeueWorkspaceArgs) (WorkspaceResolver, error)
+
+	// Subscriptions
+	UpdatedMergeQueue(context.Context, UpdatedMergeQueueArgs) (<-chan MergeQueueEntryResolver, error)
+}
+
+type MergeQueueArgs struct {
+	CodebaseID graphql.ID
+}
+
+type DequeueWorkspaceArgs struct {
+	WorkspaceID graphql.ID
+}
+
+type UpdatedMergeQueueArgs struct {
+	CodebaseID graphql.ID
+}
+
+type MergeQueueEntryResolver interface {
+	ID() graphql.ID
+	Workspace(context.Context) (WorkspaceResolver, error)
+	Status() (MergeQueueEntryStatus, error)
+	Position(context.Context) (*int32, error)
+	FailureReason() *string
+	CreatedAt() int32
+	UpdatedAt() int32
+}
+
+type MergeQueueEntryStatus string
+
+const (
+	MergeQueueEntryStatusUndefined MergeQueueEntryStatus = ""
+	MergeQueueEntryStatusQueued    MergeQueueEntryStatus = "Queued"
+	MergeQueueEntryStatusTesting   MergeQueueEntryStatus = "Testing"
+	MergeQueueEntryStatusLanded    MergeQueueEntryStatus = "Landed"
+	MergeQueueEntryStatusFailed    MergeQueueEntryStatus = "Failed"
+	MergeQueueEntryStatusDequeued  MergeQueueEntryStatus = "Dequeued"
+)
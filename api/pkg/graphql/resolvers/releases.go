@@ -0,0 +1,42 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"getsturdy.com/api/pkg/codebases"
+)
+
+type ReleasesRootResolver interface {
+	InternalListByCodebaseID(ctx context.Context, codebaseID codebases.ID) ([]ReleaseResolver, error)
+
+	// Queries
+	ReleaseNotes(ctx context.Context, args ReleaseNotesArgs) (string, error)
+
+	// Mutations
+	CreateRelease(ctx context.Context, args CreateReleaseArgs) (ReleaseResolver, error)
+}
+
+type ReleaseResolver interface {
+	ID() graphql.ID
+	Tag() string
+	Notes() string
+	Change(context.Context) (ChangeResolver, error)
+	Author(context.Context) (AuthorResolver, error)
+	CreatedAt() int32
+}
+
+type ReleaseNotesArgs struct {
+	ChangeID graphql.ID
+}
+
+type CreateReleaseArgs struct {
+	Input CreateReleaseInput
+}
+
+type CreateReleaseInput struct {
+	ChangeID graphql.ID
+	Tag      string
+	Notes    *string
+}
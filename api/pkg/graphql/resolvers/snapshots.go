@@ -10,6 +10,7 @@ import (
 
 type SnapshotsRootResolver interface {
 	InternalByID(context.Context, snapshots.ID) (SnapshotResolver, error)
+	InternalListByWorkspaceID(ctx context.Context, workspaceID string, limit int) ([]SnapshotResolver, error)
 }
 
 type SnapshotResolver interface {
@@ -18,4 +19,19 @@ type SnapshotResolver interface {
 	Next(context.Context) (SnapshotResolver, error)
 	CreatedAt() int32
 	Description(context.Context) (*string, error)
+	Action() string
+	Author(context.Context) (AuthorResolver, error)
+	DiffsCount() *int32
+	DiffStats(context.Context) (SnapshotDiffStatsResolver, error)
+	Diffs(context.Context, SnapshotDiffsArgs) ([]FileDiffResolver, error)
+}
+
+type SnapshotDiffsArgs struct {
+	CompareTo *graphql.ID
+}
+
+type SnapshotDiffStatsResolver interface {
+	FilesChanged() int32
+	Insertions() int32
+	Deletions() int32
 }
@@ -0,0 +1,45 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/trunks"
+)
+
+type TrunksRootResolver interface {
+	InternalListByCodebaseID(ctx context.Context, codebaseID codebases.ID) ([]TrunkResolver, error)
+	InternalTrunk(ctx context.Context, id trunks.ID) (TrunkResolver, error)
+
+	// Mutations
+	CreateTrunk(ctx context.Context, args CreateTrunkArgs) (TrunkResolver, error)
+	UpdateTrunk(ctx context.Context, args UpdateTrunkArgs) (TrunkResolver, error)
+}
+
+type TrunkResolver interface {
+	ID() graphql.ID
+	Name() string
+	TrackedBranch() *string
+	CreatedAt() int32
+}
+
+type CreateTrunkArgs struct {
+	Input CreateTrunkInput
+}
+
+type CreateTrunkInput struct {
+	CodebaseID    graphql.ID
+	Name          string
+	OnTopOfChange *graphql.ID
+}
+
+type UpdateTrunkArgs struct {
+	Input UpdateTrunkInput
+}
+
+type UpdateTrunkInput struct {
+	ID            graphql.ID
+	TrackedBranch *string
+}
@@ -0,0 +1,69 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"getsturdy.com/api/pkg/codebases"
+)
+
+type WebhooksRootResolver interface {
+	InternalWebhooksByCodebaseID(ctx context.Context, codebaseID codebases.ID) ([]WebhookResolver, error)
+
+	// Mutations
+	CreateWebhook(ctx context.Context, args CreateWebhookArgs) (WebhookResolver, error)
+	UpdateWebhook(ctx context.Context, args UpdateWebhookArgs) (WebhookResolver, error)
+	DeleteWebhook(ctx context.Context, args DeleteWebhookArgs) (WebhookResolver, error)
+}
+
+type WebhookResolver interface {
+	ID() graphql.ID
+	URL() string
+	Secret() string
+	Events() []string
+	CreatedAt() int32
+	UpdatedAt() int32
+	Deliveries(ctx context.Context, args WebhookDeliveriesArgs) ([]WebhookDeliveryResolver, error)
+}
+
+type WebhookDeliveryResolver interface {
+	ID() graphql.ID
+	EventID() graphql.ID
+	Event() string
+	Payload() string
+	Attempt() int32
+	StatusCode() *int32
+	Error() *string
+	DurationMs() int32
+	CreatedAt() int32
+}
+
+type WebhookDeliveriesArgs struct {
+	Last *int32
+}
+
+type CreateWebhookArgs struct {
+	Input CreateWebhookInput
+}
+
+type CreateWebhookInput struct {
+	CodebaseID graphql.ID
+	URL        string
+	Events     *[]string
+}
+
+type UpdateWebhookArgs struct {
+	Input UpdateWebhookInput
+}
+
+type UpdateWebhookInput struct {
+	ID           graphql.ID
+	URL          *string
+	Events       *[]string
+	RotateSecret *bool
+}
+
+type DeleteWebhookArgs struct {
+	ID graphql.ID
+}
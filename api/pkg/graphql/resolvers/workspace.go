@@ -58,6 +58,18 @@ type CreateWorkspaceInput struct {
 	CodebaseID              graphql.ID
 	OnTopOfChange           *graphql.ID
 	OnTopOfChangeWithRevert *graphql.ID
+	OnTopOfWorkspace        *graphql.ID
+	TrunkID                 *graphql.ID
+}
+
+type BackportChangeArgs struct {
+	Input BackportChangeInput
+}
+
+type BackportChangeInput struct {
+	ChangeID    graphql.ID
+	TrunkID     *graphql.ID
+	WorkspaceID *graphql.ID
 }
 
 type RemovePatchesArgs struct {
@@ -82,6 +94,21 @@ type SetWorkspaceSnapshotInput struct {
 	SnapshotID  graphql.ID
 }
 
+type RestoreWorkspaceSnapshotArgs struct {
+	Input RestoreWorkspaceSnapshotInput
+}
+
+type RestoreWorkspaceSnapshotInput struct {
+	WorkspaceID graphql.ID
+	SnapshotID  graphql.ID
+	Paths       *[]string
+	HunkIDs     *[]string
+}
+
+type WorkspaceSnapshotsArgs struct {
+	Last *int32
+}
+
 type WorkspaceRootResolver interface {
 	// internal
 	InternalWorkspace(*workspaces.Workspace) WorkspaceResolver
@@ -95,8 +122,10 @@ type WorkspaceRootResolver interface {
 	UnarchiveWorkspace(ctx context.Context, args UnarchiveWorkspaceArgs) (WorkspaceResolver, error)
 	CreateWorkspace(ctx context.Context, args CreateWorkspaceArgs) (WorkspaceResolver, error)
 	ExtractWorkspace(ctx context.Context, args ExtractWorkspaceArgs) (WorkspaceResolver, error)
+	BackportChange(ctx context.Context, args BackportChangeArgs) (WorkspaceResolver, error)
 	RemovePatches(context.Context, RemovePatchesArgs) (WorkspaceResolver, error)
 	SetWorkspaceSnapshot(context.Context, SetWorkspaceSnapshotArgs) (WorkspaceResolver, error)
+	RestoreWorkspaceSnapshot(context.Context, RestoreWorkspaceSnapshotArgs) (WorkspaceResolver, error)
 
 	// Subscriptions
 	UpdatedWorkspace(ctx context.Context, args UpdatedWorkspaceArgs) (<-chan WorkspaceResolver, error)
@@ -133,10 +162,14 @@ type WorkspaceResolver interface {
 	DiffsCount(context.Context) *int32
 	Diffs(context.Context) ([]FileDiffResolver, error)
 	Change(context.Context) (ChangeResolver, error)
+	ParentWorkspace(context.Context) (WorkspaceResolver, error)
+	ChildWorkspaces(context.Context) ([]WorkspaceResolver, error)
+	Trunk(context.Context) (TrunkResolver, error)
 	RebaseStatus(context.Context) (RebaseStatusResolver, error)
 	DownloadTarGz(context.Context, DownloadArchiveArgs) (ContentsDownloadUrlResolver, error)
 	DownloadZip(context.Context, DownloadArchiveArgs) (ContentsDownloadUrlResolver, error)
 	Snapshot(context.Context) (SnapshotResolver, error)
+	Snapshots(context.Context, WorkspaceSnapshotsArgs) ([]SnapshotResolver, error)
 }
 
 type DownloadArchiveArgs struct {
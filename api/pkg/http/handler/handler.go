@@ -18,6 +18,7 @@ import (
 	service_codebase "getsturdy.com/api/pkg/codebases/service"
 	"getsturdy.com/api/pkg/events"
 	eventsv2 "getsturdy.com/api/pkg/events/v2"
+	routes_exports "getsturdy.com/api/pkg/exports/routes"
 	routes_file "getsturdy.com/api/pkg/file/routes"
 	worker_gc "getsturdy.com/api/pkg/gc/worker"
 	"getsturdy.com/api/pkg/ginzap"
@@ -32,6 +33,7 @@ import (
 	routes_v3_newsletter "getsturdy.com/api/pkg/newsletter/routes"
 	db_pki "getsturdy.com/api/pkg/pki/db"
 	routes_v3_pki "getsturdy.com/api/pkg/pki/routes"
+	service_pki "getsturdy.com/api/pkg/pki/service"
 	service_presence "getsturdy.com/api/pkg/presence/service"
 	worker_snapshots "getsturdy.com/api/pkg/snapshots/worker"
 	service_suggestion "getsturdy.com/api/pkg/suggestions/service"
@@ -83,6 +85,7 @@ func ProvideHandler(
 	viewRepo db_view.Repository,
 	workspaceReader db_workspaces.WorkspaceReader,
 	userPublicKeyRepo db_pki.Repository,
+	pkiService *service_pki.Service,
 	snapshotterQueue worker_snapshots.Queue,
 	changeRepo db_change.Repository,
 	gcQueue *worker_gc.Queue,
@@ -105,6 +108,8 @@ func ProvideHandler(
 	uploader uploader.Uploader,
 	viewService *service_view.Service,
 	getFileRoute routes_file.GetFileRoute,
+	exportRoute routes_exports.ExportRoute,
+	importRoute routes_exports.ImportRoute,
 ) *Engine {
 	logger = logger.With(zap.String("component", "http"))
 	allowOrigins := []string{
@@ -185,7 +190,7 @@ func ProvideHandler(
 	publ.POST("/v3/acl-request-enterprise", acl.Insert(logger, analyticsService, aclInterestRepo))                                                                               // Used by the web (2021-10-04)
 	publ.POST("/v3/instant-integration", instantintegration.Insert(logger, analyticsService, instantIntegrationInterestRepo))                                                    // Used by the web (2021-10-27)
 	auth.POST("/v3/pki/add-public-key", routes_v3_pki.AddPublicKey(userPublicKeyRepo))                                                                                           // Used by the command line client
-	publ.POST("/v3/pki/verify", routes_v3_pki.Verify(userPublicKeyRepo))                                                                                                         // Used by the command line client
+	publ.POST("/v3/pki/verify", routes_v3_pki.Verify(pkiService))                                                                                                                // Used by the command line client
 	publ.POST("/v3/mutagen/validate-view", routes_v3_mutagen.ValidateView(logger, viewRepo, analyticsService, eventSenderV2))                                                    // Called from server-side mutagen
 	publ.POST("/v3/mutagen/sync-transitions", routes_v3_mutagen.SyncTransitions(logger, snapshotterQueue, viewRepo, gcQueue, presenceService, suggestionService, eventSenderV2)) // Called from server-side mutagen
 	publ.GET("/v3/mutagen/views/:id/allows", routes_v3_mutagen.ListAllows(logger, viewRepo, authService))                                                                        // Called form server-side mutagen
@@ -195,6 +200,9 @@ func ProvideHandler(
 
 	auth.GET("/v3/file", gin.HandlerFunc(getFileRoute))
 
+	auth.GET("/v3/codebases/:id/export", gin.HandlerFunc(exportRoute))
+	auth.POST("/v3/imports", gin.HandlerFunc(importRoute))
+
 	routes_blobs.Register(publ.Group("/v3/blobs"), logger, blobsService)
 	return (*Engine)(r)
 }
@@ -12,6 +12,7 @@ import (
 	service_codebases "getsturdy.com/api/pkg/codebases/service"
 	service_comments "getsturdy.com/api/pkg/comments/service"
 	"getsturdy.com/api/pkg/di"
+	routes_exports "getsturdy.com/api/pkg/exports/routes"
 	routes_file "getsturdy.com/api/pkg/file/routes"
 	worker_gc "getsturdy.com/api/pkg/gc/worker"
 	"getsturdy.com/api/pkg/graphql"
@@ -19,6 +20,7 @@ import (
 	db_mutagen "getsturdy.com/api/pkg/mutagen/db"
 	service_notifications "getsturdy.com/api/pkg/notification/service"
 	db_pki "getsturdy.com/api/pkg/pki/db"
+	service_pki "getsturdy.com/api/pkg/pki/service"
 	service_presence "getsturdy.com/api/pkg/presence/service"
 	worker_snapshots "getsturdy.com/api/pkg/snapshots/worker"
 	service_sync "getsturdy.com/api/pkg/sync/service"
@@ -42,6 +44,7 @@ func Module(c *di.Container) {
 	c.Import(db_views.Module)
 	c.Import(db_workspaces.Module)
 	c.Import(db_pki.Module)
+	c.Import(service_pki.Module)
 	c.Import(worker_snapshots.Module)
 	c.Import(db_changes.Module)
 	c.Import(worker_gc.Module)
@@ -62,6 +65,7 @@ func Module(c *di.Container) {
 	c.Import(service_blobs.Module)
 	c.Import(uploader_avatars.Module)
 	c.Import(routes_file.Module)
+	c.Import(routes_exports.Module)
 	c.Import(graphql.Module)
 
 	c.Register(ProvideHandler)
@@ -49,6 +49,8 @@ func (ir *instantIntegrationProvider) Provider() (resolvers.InstantIntegrationPr
 	switch ir.integration.Provider {
 	case providers.ProviderNameBuildkite:
 		return resolvers.InstantIntegrationProviderBuildkite, nil
+	case providers.ProviderNameWebhook:
+		return resolvers.InstantIntegrationProviderWebhook, nil
 	default:
 		return resolvers.InstantIntegrationProviderUndefined, fmt.Errorf("invalid provider: %s", ir.integration.Provider)
 	}
@@ -61,6 +63,13 @@ func (ir *instantIntegrationProvider) ToBuildkiteIntegration() (resolvers.Buildk
 	return &buildkiteProviderResolver{ir}, true
 }
 
+func (ir *instantIntegrationProvider) ToWebhookIntegration() (resolvers.WebhookIntegration, bool) {
+	if ir.integration.Provider != providers.ProviderNameWebhook {
+		return nil, false
+	}
+	return &webhookProviderResolver{ir}, true
+}
+
 type buildkiteProviderResolver struct {
 	*instantIntegrationProvider
 }
@@ -68,3 +77,11 @@ type buildkiteProviderResolver struct {
 func (br *buildkiteProviderResolver) Configuration(ctx context.Context) (resolvers.BuildkiteConfigurationResolver, error) {
 	return br.root.buildkiteRootResolver.InternalBuildkiteConfigurationByIntegrationID(ctx, br.integration.ID)
 }
+
+type webhookProviderResolver struct {
+	*instantIntegrationProvider
+}
+
+func (wr *webhookProviderResolver) Configuration(ctx context.Context) (resolvers.WebhookConfigurationResolver, error) {
+	return wr.root.webhookRootResolver.InternalWebhookConfigurationByIntegrationID(ctx, wr.integration.ID)
+}
@@ -9,6 +9,7 @@ import (
 	service_change "getsturdy.com/api/pkg/changes/service"
 	"getsturdy.com/api/pkg/ci/service"
 	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/codebases/acl"
 	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
 	"getsturdy.com/api/pkg/graphql/resolvers"
 	"getsturdy.com/api/pkg/integrations"
@@ -23,6 +24,7 @@ type rootResolver struct {
 	workspaceService *service_workspaces.Service
 
 	buildkiteRootResolver resolvers.BuildkiteInstantIntegrationRootResolver
+	webhookRootResolver   resolvers.WebhookInstantIntegrationRootResolver
 	statusesRootResolver  resolvers.StatusesRootResolver
 }
 
@@ -33,6 +35,7 @@ func NewRootResolver(
 	workspaceService *service_workspaces.Service,
 
 	buildkiteRootResolver resolvers.BuildkiteInstantIntegrationRootResolver,
+	webhookRootResolver resolvers.WebhookInstantIntegrationRootResolver,
 	statusesRootResolver resolvers.StatusesRootResolver,
 ) resolvers.IntegrationRootResolver {
 	return &rootResolver{
@@ -42,6 +45,7 @@ func NewRootResolver(
 		workspaceService: workspaceService,
 
 		buildkiteRootResolver: buildkiteRootResolver,
+		webhookRootResolver:   webhookRootResolver,
 		statusesRootResolver:  statusesRootResolver,
 	}
 }
@@ -133,6 +137,10 @@ func (r *rootResolver) DeleteIntegration(ctx context.Context, args resolvers.Del
 		return nil, gqlerrors.Error(err)
 	}
 
+	if err := r.authService.CanPerform(ctx, cfg.CodebaseID, acl.ActionManageIntegrations, acl.Identity{Type: acl.Integrations, ID: string(cfg.Provider)}); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
 	if err := r.svc.Delete(ctx, string(args.Input.ID)); err != nil {
 		return nil, gqlerrors.Error(err)
 	}
@@ -144,6 +152,8 @@ func convertProvider(in resolvers.InstantIntegrationProviderType) (providers.Pro
 	switch in {
 	case resolvers.InstantIntegrationProviderBuildkite:
 		return providers.ProviderNameBuildkite, nil
+	case resolvers.InstantIntegrationProviderWebhook:
+		return providers.ProviderNameWebhook, nil
 	default:
 		return providers.ProviderNameUndefined, fmt.Errorf("invalid provider: %s", in)
 	}
@@ -4,23 +4,30 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"getsturdy.com/api/pkg/auth"
 	services_auth "getsturdy.com/api/pkg/auth/service"
+	"getsturdy.com/api/pkg/codebases/acl"
+	service_codebases "getsturdy.com/api/pkg/codebases/service"
 	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
 	"getsturdy.com/api/pkg/graphql/resolvers"
 	service_land_enterprise "getsturdy.com/api/pkg/land/enterprise/service"
 	service_land_oss "getsturdy.com/api/pkg/land/service"
+	service_landingrules "getsturdy.com/api/pkg/landingrules/service"
+	service_mergequeue "getsturdy.com/api/pkg/mergequeue/service"
 	service_users "getsturdy.com/api/pkg/users/service"
 	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
 	"getsturdy.com/api/vcs"
 )
 
 type LandRootResolver struct {
-	workspaceService *service_workspaces.Service
-	landService      *service_land_enterprise.Service
-	authService      *services_auth.Service
-	userService      service_users.Service
+	workspaceService  *service_workspaces.Service
+	landService       *service_land_enterprise.Service
+	authService       *services_auth.Service
+	userService       service_users.Service
+	codebaseService   *service_codebases.Service
+	mergeQueueService *service_mergequeue.Service
 
 	workspaceResolver resolvers.WorkspaceRootResolver
 }
@@ -30,6 +37,8 @@ func NewResolver(
 	authService *services_auth.Service,
 	landService *service_land_enterprise.Service,
 	userService service_users.Service,
+	codebaseService *service_codebases.Service,
+	mergeQueueService *service_mergequeue.Service,
 	workspaceResolver resolvers.WorkspaceRootResolver,
 ) resolvers.LandRootResovler {
 	return &LandRootResolver{
@@ -37,6 +46,8 @@ func NewResolver(
 		authService:       authService,
 		userService:       userService,
 		landService:       landService,
+		codebaseService:   codebaseService,
+		mergeQueueService: mergeQueueService,
 		workspaceResolver: workspaceResolver,
 	}
 }
@@ -51,15 +62,47 @@ func (r *LandRootResolver) LandWorkspaceChange(ctx context.Context, args resolve
 		return nil, gqlerrors.Error(err)
 	}
 
+	if err := r.authService.CanPerform(ctx, ws.CodebaseID, acl.ActionLand, acl.Identity{Type: acl.Workspaces, ID: ws.ID}); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if err := r.authService.CanWriteChanges(ctx, ws); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	cb, err := r.codebaseService.GetByID(ctx, ws.CodebaseID)
+	if err != nil {
+		return nil, gqlerrors.Error(fmt.Errorf("failed to get codebase: %w", err))
+	}
+
+	// the merge queue only lands on the default trunk
+	if cb.MergeQueueEnabled && ws.TrunkID == nil {
+		_, err := r.mergeQueueService.Enqueue(ctx, ws)
+		switch {
+		case errors.Is(err, service_mergequeue.ErrAlreadyQueued):
+			return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "This draft is already in the merge queue")
+		case err != nil:
+			return nil, gqlerrors.Error(fmt.Errorf("failed to add workspace to merge queue: %w", err))
+		}
+		return r.workspaceResolver.InternalWorkspace(ws), nil
+	}
+
 	var diffOpts []vcs.DiffOption
 	if args.Input.DiffMaxSize > 0 {
 		diffOpts = append(diffOpts, vcs.WithGitMaxSize(args.Input.DiffMaxSize))
 	}
 
+	var failedRulesErr *service_landingrules.FailedError
 	_, err = r.landService.LandChange(ctx, ws, diffOpts...)
 	switch {
+	case errors.As(err, &failedRulesErr):
+		return nil, gqlerrors.Error(fmt.Errorf("failed to land change: %w", err), "message", fmt.Sprintf("This draft does not satisfy the landing rules: %s", strings.Join(failedRulesErr.Failures, ", ")))
 	case errors.Is(err, service_land_oss.ErrNotAllowedUnhealthyWorkspace):
 		return nil, gqlerrors.Error(fmt.Errorf("failed to land change: %w", err), "message", "This draft has unhealthy statuses and cannot be merged")
+	case errors.Is(err, service_land_oss.ErrNotAllowedMissingApproval):
+		return nil, gqlerrors.Error(fmt.Errorf("failed to land change: %w", err), "message", "This draft must be approved by the code owners before it can be merged")
+	case errors.Is(err, service_land_oss.ErrNotAllowedStacked):
+		return nil, gqlerrors.Error(fmt.Errorf("failed to land change: %w", err), "message", "This draft is stacked on a draft that has not been merged yet")
 	case err != nil:
 		return nil, gqlerrors.Error(fmt.Errorf("failed to land change: %w", err))
 	}
@@ -83,6 +126,9 @@ func (r *LandRootResolver) PushWorkspace(ctx context.Context, args resolvers.Pus
 	}
 
 	if args.Input.LandOnSturdyAndPushTracked != nil && *args.Input.LandOnSturdyAndPushTracked {
+		if err := r.authService.CanPerform(ctx, ws.CodebaseID, acl.ActionLand, acl.Identity{Type: acl.Workspaces, ID: ws.ID}); err != nil {
+			return nil, gqlerrors.Error(err)
+		}
 		if err := r.landService.LandOnSturdyAndPushTracked(ctx, ws); err != nil {
 			return nil, gqlerrors.Error(err)
 		}
@@ -36,6 +36,19 @@ func New(
 }
 
 func (s *Service) LandChange(ctx context.Context, ws *workspaces.Workspace, diffOpts ...vcs.DiffOption) (*changes.Change, error) {
+	return s.land(ctx, ws, func() (*changes.Change, error) {
+		return s.oss.LandChange(ctx, ws, diffOpts...)
+	})
+}
+
+// LandCommit lands a commit that has already been created from the workspace, see service_land.Service.LandCommit.
+func (s *Service) LandCommit(ctx context.Context, ws *workspaces.Workspace, commitSHA string) (*changes.Change, error) {
+	return s.land(ctx, ws, func() (*changes.Change, error) {
+		return s.oss.LandCommit(ctx, ws, commitSHA)
+	})
+}
+
+func (s *Service) land(ctx context.Context, ws *workspaces.Workspace, landFunc func() (*changes.Change, error)) (*changes.Change, error) {
 	gitHubRepository, err := s.gitHubService.GetRepositoryByCodebaseID(ctx, ws.CodebaseID)
 	switch {
 	case err == nil, errors.Is(err, sql.ErrNoRows):
@@ -47,7 +60,7 @@ func (s *Service) LandChange(ctx context.Context, ws *workspaces.Workspace, diff
 		return nil, fmt.Errorf("landing disallowed when a github integration exists for codebase (github is source of truth)")
 	}
 
-	change, err := s.oss.LandChange(ctx, ws, diffOpts...)
+	change, err := landFunc()
 	if err != nil {
 		return nil, err
 	}
@@ -91,7 +104,7 @@ func (s *Service) LandOnSturdyAndPushTracked(ctx context.Context, ws *workspaces
 		return fmt.Errorf("failed to land change: %w", err)
 	}
 
-	if err := s.remoteService.PushTrunk(ctx, ws.CodebaseID); err != nil {
+	if err := s.remoteService.PushTrunk(ctx, ws.CodebaseID, ws.TrunkID); err != nil {
 		return fmt.Errorf("failed to push trunk: %w", err)
 	}
 
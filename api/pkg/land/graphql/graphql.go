@@ -4,19 +4,26 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	services_auth "getsturdy.com/api/pkg/auth/service"
+	"getsturdy.com/api/pkg/codebases/acl"
+	service_codebases "getsturdy.com/api/pkg/codebases/service"
 	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
 	"getsturdy.com/api/pkg/graphql/resolvers"
 	service_land "getsturdy.com/api/pkg/land/service"
+	service_landingrules "getsturdy.com/api/pkg/landingrules/service"
+	service_mergequeue "getsturdy.com/api/pkg/mergequeue/service"
 	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
 	"getsturdy.com/api/vcs"
 )
 
 type LandRootResolver struct {
-	landService      *service_land.Service
-	workspaceService *service_workspaces.Service
-	authService      *services_auth.Service
+	landService       *service_land.Service
+	workspaceService  *service_workspaces.Service
+	authService       *services_auth.Service
+	codebaseService   *service_codebases.Service
+	mergeQueueService *service_mergequeue.Service
 
 	workspaceResolver resolvers.WorkspaceRootResolver
 }
@@ -25,6 +32,8 @@ func NewResolver(
 	landService *service_land.Service,
 	workspaceService *service_workspaces.Service,
 	authService *services_auth.Service,
+	codebaseService *service_codebases.Service,
+	mergeQueueService *service_mergequeue.Service,
 
 	workspaceResolver resolvers.WorkspaceRootResolver,
 ) resolvers.LandRootResovler {
@@ -32,6 +41,8 @@ func NewResolver(
 		landService:       landService,
 		workspaceService:  workspaceService,
 		authService:       authService,
+		codebaseService:   codebaseService,
+		mergeQueueService: mergeQueueService,
 		workspaceResolver: workspaceResolver,
 	}
 }
@@ -46,15 +57,47 @@ func (r *LandRootResolver) LandWorkspaceChange(ctx context.Context, args resolve
 		return nil, gqlerrors.Error(err)
 	}
 
+	if err := r.authService.CanPerform(ctx, ws.CodebaseID, acl.ActionLand, acl.Identity{Type: acl.Workspaces, ID: ws.ID}); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if err := r.authService.CanWriteChanges(ctx, ws); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	cb, err := r.codebaseService.GetByID(ctx, ws.CodebaseID)
+	if err != nil {
+		return nil, gqlerrors.Error(fmt.Errorf("failed to get codebase: %w", err))
+	}
+
+	// the merge queue only lands on the default trunk
+	if cb.MergeQueueEnabled && ws.TrunkID == nil {
+		_, err := r.mergeQueueService.Enqueue(ctx, ws)
+		switch {
+		case errors.Is(err, service_mergequeue.ErrAlreadyQueued):
+			return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "This draft is already in the merge queue")
+		case err != nil:
+			return nil, gqlerrors.Error(fmt.Errorf("failed to add workspace to merge queue: %w", err))
+		}
+		return r.workspaceResolver.InternalWorkspace(ws), nil
+	}
+
 	var diffOpts []vcs.DiffOption
 	if args.Input.DiffMaxSize > 0 {
 		diffOpts = append(diffOpts, vcs.WithGitMaxSize(args.Input.DiffMaxSize))
 	}
 
+	var failedRulesErr *service_landingrules.FailedError
 	_, err = r.landService.LandChange(ctx, ws, diffOpts...)
 	switch {
+	case errors.As(err, &failedRulesErr):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", fmt.Sprintf("This draft does not satisfy the landing rules: %s", strings.Join(failedRulesErr.Failures, ", ")))
 	case errors.Is(err, service_land.ErrNotAllowedUnhealthyWorkspace):
 		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "This draft has unhealthy statuses and cannot be merged")
+	case errors.Is(err, service_land.ErrNotAllowedMissingApproval):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "This draft must be approved by the code owners before it can be merged")
+	case errors.Is(err, service_land.ErrNotAllowedStacked):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "This draft is stacked on a draft that has not been merged yet")
 	case err != nil:
 		return nil, gqlerrors.Error(fmt.Errorf("failed to land change: %w", err))
 	}
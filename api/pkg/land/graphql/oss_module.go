@@ -5,8 +5,10 @@ package grapqhl
 
 import (
 	services_auth "getsturdy.com/api/pkg/auth/service"
+	service_codebases "getsturdy.com/api/pkg/codebases/service"
 	"getsturdy.com/api/pkg/di"
 	service_land "getsturdy.com/api/pkg/land/service"
+	service_mergequeue "getsturdy.com/api/pkg/mergequeue/service/module"
 	graphql_workspaces "getsturdy.com/api/pkg/workspaces/graphql"
 	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
 )
@@ -14,6 +16,8 @@ import (
 func Module(c *di.Container) {
 	c.Import(service_land.Module)
 	c.Import(services_auth.Module)
+	c.Import(service_codebases.Module)
+	c.Import(service_mergequeue.Module)
 	c.Import(service_workspaces.Module)
 	c.Import(graphql_workspaces.Module)
 	c.Register(NewResolver)
@@ -7,10 +7,12 @@ import (
 	service_changes "getsturdy.com/api/pkg/changes/service"
 	workers_ci "getsturdy.com/api/pkg/ci/workers"
 	service_codebase "getsturdy.com/api/pkg/codebases/service"
+	service_codeowners "getsturdy.com/api/pkg/codeowners/service"
 	service_comments "getsturdy.com/api/pkg/comments/service"
 	"getsturdy.com/api/pkg/di"
 	"getsturdy.com/api/pkg/events"
 	eventsv2 "getsturdy.com/api/pkg/events/v2"
+	service_landingrules "getsturdy.com/api/pkg/landingrules/service"
 	"getsturdy.com/api/pkg/logger"
 	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
 	worker_snapshots "getsturdy.com/api/pkg/snapshots/worker"
@@ -41,5 +43,7 @@ func Module(c *di.Container) {
 	c.Import(workers_ci.Module)
 	c.Import(sender.Module)
 	c.Import(service_workspace_statuses.Module)
+	c.Import(service_codeowners.Module)
+	c.Import(service_landingrules.Module)
 	c.Register(New)
 }
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -16,13 +17,17 @@ import (
 	"getsturdy.com/api/pkg/changes/message"
 	service_changes "getsturdy.com/api/pkg/changes/service"
 	workers_ci "getsturdy.com/api/pkg/ci/workers"
+	"getsturdy.com/api/pkg/codebases"
 	service_codebase "getsturdy.com/api/pkg/codebases/service"
+	service_codeowners "getsturdy.com/api/pkg/codeowners/service"
 	service_comments "getsturdy.com/api/pkg/comments/service"
 	"getsturdy.com/api/pkg/events"
 	eventsv2 "getsturdy.com/api/pkg/events/v2"
+	service_landingrules "getsturdy.com/api/pkg/landingrules/service"
 	"getsturdy.com/api/pkg/snapshots"
 	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
 	worker_snapshots "getsturdy.com/api/pkg/snapshots/worker"
+	"getsturdy.com/api/pkg/trunks"
 	service_users "getsturdy.com/api/pkg/users/service"
 	service_view "getsturdy.com/api/pkg/views/service"
 	vcs_view "getsturdy.com/api/pkg/views/vcs"
@@ -38,6 +43,10 @@ import (
 
 var (
 	ErrNotAllowedUnhealthyWorkspace = fmt.Errorf("not allowed to land workspace, it has unhealthy statuses")
+	ErrNotAllowedMissingApproval    = fmt.Errorf("not allowed to land workspace, it is not approved by the code owners")
+	ErrCommitNotOnTrunk             = fmt.Errorf("the commit is not based on the head of the trunk")
+	ErrNotAllowedStacked            = fmt.Errorf("not allowed to land workspace, it is stacked on a workspace that has not landed yet")
+	ErrWorkspaceUpdated             = fmt.Errorf("the workspace has changes that are not in the commit")
 )
 
 type Service struct {
@@ -55,6 +64,8 @@ type Service struct {
 	activityService          *service_activity.Service
 	codebaseService          *service_codebase.Service
 	workspaceStatusesService *service_workspace_statuses.Service
+	codeOwnersService        *service_codeowners.Service
+	landingRulesService      *service_landingrules.Service
 
 	activitySender   sender.ActivitySender
 	snapshotterQueue worker_snapshots.Queue
@@ -79,6 +90,8 @@ func New(
 	activityService *service_activity.Service,
 	codebaseService *service_codebase.Service,
 	workspaceStatusesService *service_workspace_statuses.Service,
+	codeOwnersService *service_codeowners.Service,
+	landingRulesService *service_landingrules.Service,
 
 	activitySender sender.ActivitySender,
 	snapshotterQueue worker_snapshots.Queue,
@@ -102,6 +115,8 @@ func New(
 		activityService:          activityService,
 		codebaseService:          codebaseService,
 		workspaceStatusesService: workspaceStatusesService,
+		codeOwnersService:        codeOwnersService,
+		landingRulesService:      landingRulesService,
 
 		activitySender:   activitySender,
 		snapshotterQueue: snapshotterQueue,
@@ -113,6 +128,10 @@ func New(
 }
 
 func (s *Service) LandChange(ctx context.Context, ws *workspaces.Workspace, diffOpts ...vcs.DiffOption) (*changes.Change, error) {
+	if ws.ParentWorkspaceID != nil {
+		return nil, ErrNotAllowedStacked
+	}
+
 	user, err := s.usersService.GetByID(ctx, ws.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
@@ -135,6 +154,14 @@ func (s *Service) LandChange(ctx context.Context, ws *workspaces.Workspace, diff
 		}
 	}
 
+	if err := s.checkCodeOwnersApproval(ctx, cb, ws); err != nil {
+		return nil, err
+	}
+
+	if err := s.landingRulesService.Check(ctx, ws); err != nil {
+		return nil, err
+	}
+
 	gitCommitMessage := message.CommitMessage(ws.DraftDescription)
 
 	signature := git.Signature{
@@ -150,6 +177,7 @@ func (s *Service) LandChange(ctx context.Context, ws *workspaces.Workspace, diff
 			viewRepo,
 			ws.CodebaseID,
 			ws.ID,
+			trunks.BranchName(ws.TrunkID),
 			gitCommitMessage,
 			signature,
 			diffOpts...,
@@ -203,6 +231,133 @@ func (s *Service) LandChange(ctx context.Context, ws *workspaces.Workspace, diff
 		ws.SetSnapshot(nil)
 	}
 
+	return s.completeLand(ctx, ws, change)
+}
+
+// checkCodeOwnersApproval makes sure that all owned paths in the workspace are approved by their owners, if the
+// codebase requires it.
+func (s *Service) checkCodeOwnersApproval(ctx context.Context, cb *codebases.Codebase, ws *workspaces.Workspace) error {
+	if !cb.RequireCodeOwnersApproval {
+		return nil
+	}
+	err := s.codeOwnersService.Check(ctx, ws)
+	switch {
+	case errors.Is(err, service_codeowners.ErrMissingApproval):
+		return fmt.Errorf("%w: %s", ErrNotAllowedMissingApproval, err)
+	case err != nil:
+		return fmt.Errorf("failed to check code owners: %w", err)
+	}
+	return nil
+}
+
+// LandCommit lands a commit that has already been created from the workspace. The commit must have the current head
+// of the trunk as its only parent. It's used by the merge queue, to land the commits that have been verified by the
+// continuous integration without creating a new commit.
+func (s *Service) LandCommit(ctx context.Context, ws *workspaces.Workspace, commitSHA string) (*changes.Change, error) {
+	if ws.ParentWorkspaceID != nil {
+		return nil, ErrNotAllowedStacked
+	}
+
+	cb, err := s.codebaseService.GetByID(ctx, ws.CodebaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get codebase: %w", err)
+	}
+
+	if err := s.checkCodeOwnersApproval(ctx, cb, ws); err != nil {
+		return nil, err
+	}
+
+	// the commit is verified to be on top of the trunk below
+	if err := s.landingRulesService.Check(ctx, ws, service_landingrules.WithSkipUpToDateWithTrunk()); err != nil {
+		return nil, err
+	}
+
+	if ws.ViewID != nil {
+		// the view is force checked out to the landed commit below, make sure that edits made since the last snapshot
+		// are saved, and that they are not silently dropped from the workspace
+		snapshot, err := s.snapshotter.Snapshot(ctx, ws.CodebaseID, ws.ID, snapshots.ActionPreChangeLand,
+			service_snapshots.WithOnView(*ws.ViewID),
+			service_snapshots.WithNoThrottle(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to snapshot: %w", err)
+		}
+		if ws.LatestSnapshotID == nil || snapshot.ID != *ws.LatestSnapshotID {
+			return nil, ErrWorkspaceUpdated
+		}
+	}
+
+	trunkBranchName := trunks.BranchName(ws.TrunkID)
+
+	var change *changes.Change
+	if err := s.executorProvider.New().
+		GitWrite(func(repo vcs.RepoGitWriter) error {
+			trunkHeadCommitID, err := repo.BranchCommitID(trunkBranchName)
+			if err != nil {
+				return fmt.Errorf("failed to get trunk head: %w", err)
+			}
+
+			parents, err := repo.GetCommitParents(commitSHA)
+			if err != nil {
+				return fmt.Errorf("failed get parents of commit: %w", err)
+			}
+			if len(parents) != 1 || parents[0] != trunkHeadCommitID {
+				return ErrCommitNotOnTrunk
+			}
+
+			change, err = s.changeService.CreateWithCommitAsParent(ctx, ws, commitSHA, trunkHeadCommitID)
+			if err != nil {
+				return fmt.Errorf("failed to create change: %w", err)
+			}
+
+			if err := repo.CreateNewBranchAt(trunkBranchName, commitSHA); err != nil {
+				return fmt.Errorf("failed to move trunk: %w", err)
+			}
+
+			// move the workspace branch to be the same as the new trunk
+			if err := repo.CreateNewBranchAt(ws.ID, commitSHA); err != nil {
+				return fmt.Errorf("failed to move workspace to new trunk: %w", err)
+			}
+
+			return nil
+		}).ExecTrunkContext(ctx, ws.CodebaseID, "landCommit"); err != nil {
+		return nil, fmt.Errorf("failed to land commit: %w", err)
+	}
+
+	if ws.ViewID != nil {
+		if err := s.executorProvider.New().
+			Write(func(repo vcs.RepoWriter) error {
+				if err := repo.FetchBranch(trunkBranchName); err != nil {
+					return fmt.Errorf("failed to fetch trunk: %w", err)
+				}
+				if err := repo.MoveBranchToCommit(trunkBranchName, commitSHA); err != nil {
+					return fmt.Errorf("failed to move trunk: %w", err)
+				}
+				if err := repo.MoveBranchToCommit(ws.ID, commitSHA); err != nil {
+					return fmt.Errorf("failed to move workspace to new trunk: %w", err)
+				}
+				if err := repo.CheckoutBranchWithForce(ws.ID); err != nil {
+					return fmt.Errorf("failed to checkout workspace branch: %w", err)
+				}
+				// LFS Pull
+				if err := repo.LargeFilesPull(); err != nil {
+					// Log and continue (repo can have LFS files from outside of Sturdy)
+					s.logger.Warn("failed to pull large files", zap.Error(err))
+				}
+				return nil
+			}).ExecViewContext(ctx, ws.CodebaseID, *ws.ViewID, "landCommitUpdateView"); err != nil {
+			return nil, fmt.Errorf("failed to update view: %w", err)
+		}
+	} else {
+		ws.SetSnapshot(nil)
+	}
+
+	return s.completeLand(ctx, ws, change)
+}
+
+// completeLand updates the state of the workspace, the codebase and all related objects after a change has been
+// landed.
+func (s *Service) completeLand(ctx context.Context, ws *workspaces.Workspace, change *changes.Change) (*changes.Change, error) {
 	s.analyticsService.Capture(ctx, "create change",
 		analytics.CodebaseID(ws.CodebaseID),
 		analytics.Property("workspace_id", ws.ID),
@@ -267,9 +422,11 @@ func (s *Service) LandChange(ctx context.Context, ws *workspaces.Workspace, diff
 		return nil, fmt.Errorf("failed to set change activity: %w", err)
 	}
 
-	// Update codebase cache
-	if err := s.changeService.SetAsHeadChange(change); err != nil {
-		return nil, fmt.Errorf("failed to set as head change: %w", err)
+	// Update codebase cache, it only tracks the head of the default trunk
+	if ws.TrunkID == nil {
+		if err := s.changeService.SetAsHeadChange(change); err != nil {
+			return nil, fmt.Errorf("failed to set as head change: %w", err)
+		}
 	}
 
 	// Send events that the codebase has been updated
@@ -281,6 +438,10 @@ func (s *Service) LandChange(ctx context.Context, ws *workspaces.Workspace, diff
 		s.logger.Error("failed to send workspace event", zap.Error(err))
 	}
 
+	if err := s.eventsPublisher.ChangeLanded(ctx, eventsv2.Codebase(ws.CodebaseID), change); err != nil {
+		s.logger.Error("failed to send change landed event", zap.Error(err))
+	}
+
 	if err := s.buildQueue.EnqueueChange(ctx, change); err != nil {
 		s.logger.Error("failed to enqueue change", zap.Error(err))
 	}
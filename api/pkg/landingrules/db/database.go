@@ -0,0 +1,66 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/landingrules"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var _ Repository = &database{}
+
+type database struct {
+	db *sqlx.DB
+}
+
+func NewDatabase(db *sqlx.DB) Repository {
+	return &database{
+		db: db,
+	}
+}
+
+func (d *database) Create(ctx context.Context, rules *landingrules.Rules) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		INSERT INTO landing_rules (
+			codebase_id, min_approvals, require_resolved_comments, required_statuses, require_up_to_date_with_trunk, created_at, updated_at
+		) VALUES (
+			:codebase_id, :min_approvals, :require_resolved_comments, :required_statuses, :require_up_to_date_with_trunk, :created_at, :updated_at
+		)
+	`, rules); err != nil {
+		return fmt.Errorf("failed to insert: %w", err)
+	}
+	return nil
+}
+
+func (d *database) Update(ctx context.Context, rules *landingrules.Rules) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		UPDATE landing_rules
+		SET min_approvals = :min_approvals,
+			require_resolved_comments = :require_resolved_comments,
+			required_statuses = :required_statuses,
+			require_up_to_date_with_trunk = :require_up_to_date_with_trunk,
+			updated_at = :updated_at
+		WHERE codebase_id = :codebase_id
+	`, rules); err != nil {
+		return fmt.Errorf("failed to update: %w", err)
+	}
+	return nil
+}
+
+func (d *database) GetByCodebaseID(ctx context.Context, codebaseID codebases.ID) (*landingrules.Rules, error) {
+	rules := &landingrules.Rules{}
+	if err := d.db.GetContext(ctx, rules, `
+		SELECT
+			codebase_id, min_approvals, require_resolved_comments, required_statuses, require_up_to_date_with_trunk, created_at, updated_at
+		FROM
+			landing_rules
+		WHERE
+			codebase_id = $1
+	`, codebaseID); err != nil {
+		return nil, fmt.Errorf("failed to get: %w", err)
+	}
+	return rules, nil
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/landingrules"
+)
+
+var _ Repository = &memory{}
+
+type memory struct {
+	mu         sync.RWMutex
+	byCodebase map[codebases.ID]*landingrules.Rules
+}
+
+func NewMemory() Repository {
+	return &memory{
+		byCodebase: map[codebases.ID]*landingrules.Rules{},
+	}
+}
+
+func (m *memory) Create(_ context.Context, rules *landingrules.Rules) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *rules
+	m.byCodebase[rules.CodebaseID] = &cp
+	return nil
+}
+
+func (m *memory) Update(_ context.Context, rules *landingrules.Rules) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, found := m.byCodebase[rules.CodebaseID]; !found {
+		return sql.ErrNoRows
+	}
+	cp := *rules
+	m.byCodebase[rules.CodebaseID] = &cp
+	return nil
+}
+
+func (m *memory) GetByCodebaseID(_ context.Context, codebaseID codebases.ID) (*landingrules.Rules, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	rules, found := m.byCodebase[codebaseID]
+	if !found {
+		return nil, sql.ErrNoRows
+	}
+	cp := *rules
+	return &cp, nil
+}
@@ -0,0 +1,14 @@
+package db
+
+import (
+	"context"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/landingrules"
+)
+
+type Repository interface {
+	Create(context.Context, *landingrules.Rules) error
+	Update(context.Context, *landingrules.Rules) error
+	GetByCodebaseID(context.Context, codebases.ID) (*landingrules.Rules, error)
+}
@@ -0,0 +1,106 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/codebases/acl"
+	service_codebases "getsturdy.com/api/pkg/codebases/service"
+	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
+	"getsturdy.com/api/pkg/graphql/resolvers"
+	"getsturdy.com/api/pkg/landingrules"
+	service_landingrules "getsturdy.com/api/pkg/landingrules/service"
+)
+
+type rootResolver struct {
+	authService         *service_auth.Service
+	codebaseService     *service_codebases.Service
+	landingRulesService *service_landingrules.Service
+}
+
+func New(
+	authService *service_auth.Service,
+	codebaseService *service_codebases.Service,
+	landingRulesService *service_landingrules.Service,
+) resolvers.LandingRulesRootResolver {
+	return &rootResolver{
+		authService:         authService,
+		codebaseService:     codebaseService,
+		landingRulesService: landingRulesService,
+	}
+}
+
+func (r *rootResolver) InternalLandingRulesByCodebaseID(ctx context.Context, codebaseID codebases.ID) (resolvers.LandingRulesResolver, error) {
+	rules, err := r.landingRulesService.Get(ctx, codebaseID)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+	return &resolver{rules: rules}, nil
+}
+
+func (r *rootResolver) UpdateLandingRules(ctx context.Context, args resolvers.UpdateLandingRulesArgs) (resolvers.LandingRulesResolver, error) {
+	cb, err := r.codebaseService.GetByID(ctx, codebases.ID(args.Input.CodebaseID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if err := r.authService.CanWrite(ctx, cb); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if err := r.authService.CanPerform(ctx, cb.ID, acl.ActionAdmin, acl.Identity{Type: acl.Codebases, ID: cb.ID.String()}); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	rules, err := r.landingRulesService.Get(ctx, cb.ID)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if args.Input.MinApprovals != nil {
+		if *args.Input.MinApprovals < 0 {
+			return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "minApprovals can not be negative")
+		}
+		rules.MinApprovals = int(*args.Input.MinApprovals)
+	}
+	if args.Input.RequireResolvedComments != nil {
+		rules.RequireResolvedComments = *args.Input.RequireResolvedComments
+	}
+	if args.Input.RequiredStatuses != nil {
+		rules.RequiredStatuses = *args.Input.RequiredStatuses
+	}
+	if args.Input.RequireUpToDateWithTrunk != nil {
+		rules.RequireUpToDateWithTrunk = *args.Input.RequireUpToDateWithTrunk
+	}
+
+	if err := r.landingRulesService.Update(ctx, rules); err != nil {
+		return nil, gqlerrors.Error(fmt.Errorf("failed to update landing rules: %w", err))
+	}
+
+	return &resolver{rules: rules}, nil
+}
+
+type resolver struct {
+	rules *landingrules.Rules
+}
+
+func (r *resolver) MinApprovals() int32 {
+	return int32(r.rules.MinApprovals)
+}
+
+func (r *resolver) RequireResolvedComments() bool {
+	return r.rules.RequireResolvedComments
+}
+
+func (r *resolver) RequiredStatuses() []string {
+	if r.rules.RequiredStatuses == nil {
+		return []string{}
+	}
+	return r.rules.RequiredStatuses
+}
+
+func (r *resolver) RequireUpToDateWithTrunk() bool {
+	return r.rules.RequireUpToDateWithTrunk
+}
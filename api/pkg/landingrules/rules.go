@@ -0,0 +1,34 @@
+package landingrules
+
+import (
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+
+	"github.com/lib/pq"
+)
+
+// Rules are the rules that a workspace must satisfy before it can be landed on the trunk of a codebase.
+type Rules struct {
+	CodebaseID codebases.ID `db:"codebase_id"`
+
+	// MinApprovals is the minimum number of approving reviews the workspace must have.
+	MinApprovals int `db:"min_approvals"`
+	// RequireResolvedComments requires all comment threads on the workspace to be resolved.
+	RequireResolvedComments bool `db:"require_resolved_comments"`
+	// RequiredStatuses are the titles of the statuses that must be healthy for the latest snapshot of the workspace.
+	RequiredStatuses pq.StringArray `db:"required_statuses"`
+	// RequireUpToDateWithTrunk requires the workspace to be based on the current head of the trunk.
+	RequireUpToDateWithTrunk bool `db:"require_up_to_date_with_trunk"`
+
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// Default returns the rules of a codebase that does not have any rules configured, which allows everything to land.
+func Default(codebaseID codebases.ID) *Rules {
+	return &Rules{
+		CodebaseID:       codebaseID,
+		RequiredStatuses: pq.StringArray{},
+	}
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	db_comments "getsturdy.com/api/pkg/comments/db"
+	"getsturdy.com/api/pkg/di"
+	db_landingrules "getsturdy.com/api/pkg/landingrules/db"
+	service_review "getsturdy.com/api/pkg/review/service"
+	service_statuses "getsturdy.com/api/pkg/statuses/service"
+	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
+	service_workspace_statuses "getsturdy.com/api/pkg/workspaces/statuses/service"
+)
+
+func Module(c *di.Container) {
+	c.Import(db_landingrules.Module)
+	c.Import(db_comments.Module)
+	c.Import(service_review.Module)
+	c.Import(service_statuses.Module)
+	c.Import(service_workspaces.Module)
+	c.Import(service_workspace_statuses.Module)
+	c.Register(New)
+}
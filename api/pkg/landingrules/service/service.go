@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+	db_comments "getsturdy.com/api/pkg/comments/db"
+	"getsturdy.com/api/pkg/landingrules"
+	db_landingrules "getsturdy.com/api/pkg/landingrules/db"
+	"getsturdy.com/api/pkg/review"
+	service_review "getsturdy.com/api/pkg/review/service"
+	"getsturdy.com/api/pkg/statuses"
+	service_statuses "getsturdy.com/api/pkg/statuses/service"
+	"getsturdy.com/api/pkg/workspaces"
+	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
+	service_workspace_statuses "getsturdy.com/api/pkg/workspaces/statuses/service"
+)
+
+// FailedError is returned by Check if the workspace does not satisfy the landing rules of the codebase.
+type FailedError struct {
+	// Failures are human-readable descriptions of the rules that failed.
+	Failures []string
+}
+
+func (e *FailedError) Error() string {
+	return fmt.Sprintf("landing rules failed: %s", strings.Join(e.Failures, ", "))
+}
+
+type Service struct {
+	repo db_landingrules.Repository
+
+	commentsRepo             db_comments.Repository
+	reviewService            *service_review.Service
+	statusesService          *service_statuses.Service
+	workspaceService         *service_workspaces.Service
+	workspaceStatusesService *service_workspace_statuses.Service
+}
+
+func New(
+	repo db_landingrules.Repository,
+
+	commentsRepo db_comments.Repository,
+	reviewService *service_review.Service,
+	statusesService *service_statuses.Service,
+	workspaceService *service_workspaces.Service,
+	workspaceStatusesService *service_workspace_statuses.Service,
+) *Service {
+	return &Service{
+		repo: repo,
+
+		commentsRepo:             commentsRepo,
+		reviewService:            reviewService,
+		statusesService:          statusesService,
+		workspaceService:         workspaceService,
+		workspaceStatusesService: workspaceStatusesService,
+	}
+}
+
+// Get returns the landing rules of the codebase. If the codebase has no rules configured, the default rules are
+// returned.
+func (s *Service) Get(ctx context.Context, codebaseID codebases.ID) (*landingrules.Rules, error) {
+	rules, err := s.repo.GetByCodebaseID(ctx, codebaseID)
+	switch {
+	case err == nil:
+		return rules, nil
+	case errors.Is(err, sql.ErrNoRows):
+		return landingrules.Default(codebaseID), nil
+	default:
+		return nil, fmt.Errorf("failed to get landing rules: %w", err)
+	}
+}
+
+// Update creates or updates the landing rules of the codebase.
+func (s *Service) Update(ctx context.Context, rules *landingrules.Rules) error {
+	if rules.MinApprovals < 0 {
+		return fmt.Errorf("min approvals can not be negative")
+	}
+
+	rules.UpdatedAt = time.Now()
+
+	_, err := s.repo.GetByCodebaseID(ctx, rules.CodebaseID)
+	switch {
+	case err == nil:
+		if err := s.repo.Update(ctx, rules); err != nil {
+			return fmt.Errorf("failed to update landing rules: %w", err)
+		}
+	case errors.Is(err, sql.ErrNoRows):
+		rules.CreatedAt = rules.UpdatedAt
+		if err := s.repo.Create(ctx, rules); err != nil {
+			return fmt.Errorf("failed to create landing rules: %w", err)
+		}
+	default:
+		return fmt.Errorf("failed to get landing rules: %w", err)
+	}
+
+	return nil
+}
+
+type CheckOptions struct {
+	skipUpToDateWithTrunk bool
+}
+
+type CheckOption func(*CheckOptions)
+
+// WithSkipUpToDateWithTrunk skips the RequireUpToDateWithTrunk rule, for callers that land the workspace on top of the
+// current trunk themselves.
+func WithSkipUpToDateWithTrunk() CheckOption {
+	return func(options *CheckOptions) {
+		options.skipUpToDateWithTrunk = true
+	}
+}
+
+// Check evaluates the landing rules of the codebase against the workspace. If one or more rules are not satisfied,
+// a *FailedError listing all of them is returned.
+func (s *Service) Check(ctx context.Context, ws *workspaces.Workspace, oo ...CheckOption) error {
+	options := &CheckOptions{}
+	for _, o := range oo {
+		o(options)
+	}
+
+	rules, err := s.Get(ctx, ws.CodebaseID)
+	if err != nil {
+		return err
+	}
+
+	var failures []string
+
+	if rules.MinApprovals > 0 {
+		approvals, err := s.countApprovals(ctx, ws)
+		if err != nil {
+			return err
+		}
+		if approvals < rules.MinApprovals {
+			failures = append(failures, fmt.Sprintf("requires %d approving reviews, has %d", rules.MinApprovals, approvals))
+		}
+	}
+
+	if rules.RequireResolvedComments {
+		unresolved, err := s.countUnresolvedComments(ws)
+		if err != nil {
+			return err
+		}
+		if unresolved > 0 {
+			failures = append(failures, fmt.Sprintf("has %d unresolved comments", unresolved))
+		}
+	}
+
+	if len(rules.RequiredStatuses) > 0 {
+		statusFailures, err := s.checkStatuses(ctx, ws, rules.RequiredStatuses)
+		if err != nil {
+			return err
+		}
+		failures = append(failures, statusFailures...)
+	}
+
+	if rules.RequireUpToDateWithTrunk && !options.skipUpToDateWithTrunk {
+		upToDate, err := s.workspaceService.UpToDateWithTrunk(ctx, ws)
+		if err != nil {
+			return fmt.Errorf("failed to check if workspace is up to date: %w", err)
+		}
+		if !upToDate {
+			failures = append(failures, "is not up to date with the trunk")
+		}
+	}
+
+	if len(failures) > 0 {
+		return &FailedError{Failures: failures}
+	}
+	return nil
+}
+
+func (s *Service) countApprovals(ctx context.Context, ws *workspaces.Workspace) (int, error) {
+	reviews, err := s.reviewService.ListLatestByWorkspace(ctx, ws.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list reviews: %w", err)
+	}
+	var approvals int
+	for _, rev := range reviews {
+		if rev.Grade == review.ReviewGradeApprove && rev.DismissedAt == nil && !rev.IsReplaced {
+			approvals++
+		}
+	}
+	return approvals, nil
+}
+
+func (s *Service) countUnresolvedComments(ws *workspaces.Workspace) (int, error) {
+	// only top level comments are returned, and they are the ones that can be resolved
+	comments, err := s.commentsRepo.GetByWorkspace(ws.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list comments: %w", err)
+	}
+	var unresolved int
+	for _, comment := range comments {
+		if comment.ResolvedAt == nil {
+			unresolved++
+		}
+	}
+	return unresolved, nil
+}
+
+func (s *Service) checkStatuses(ctx context.Context, ws *workspaces.Workspace, titles []string) ([]string, error) {
+	statusList, err := s.statusesService.ListByWorkspaceID(ctx, ws.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statuses: %w", err)
+	}
+
+	// statuses are ordered with the latest first
+	byTitle := make(map[string]*statuses.Status, len(statusList))
+	for _, status := range statusList {
+		if _, found := byTitle[status.Title]; !found {
+			byTitle[status.Title] = status
+		}
+	}
+
+	var failures []string
+	for _, title := range titles {
+		status, found := byTitle[title]
+		if !found {
+			failures = append(failures, fmt.Sprintf("status %q is missing", title))
+			continue
+		}
+
+		if status.Type != statuses.TypeHealthy {
+			failures = append(failures, fmt.Sprintf("status %q is %s", title, status.Type))
+			continue
+		}
+
+		isStale, err := s.workspaceStatusesService.StatusIsStaleForWorkspace(ctx, ws, status)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check if status is stale: %w", err)
+		}
+		if isStale {
+			failures = append(failures, fmt.Sprintf("status %q is stale", title))
+		}
+	}
+	return failures, nil
+}
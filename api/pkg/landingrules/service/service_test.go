@@ -0,0 +1,123 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/comments"
+	db_comments "getsturdy.com/api/pkg/comments/db"
+	"getsturdy.com/api/pkg/landingrules"
+	db_landingrules "getsturdy.com/api/pkg/landingrules/db"
+	service_landingrules "getsturdy.com/api/pkg/landingrules/service"
+	"getsturdy.com/api/pkg/review"
+	db_review "getsturdy.com/api/pkg/review/db"
+	service_review "getsturdy.com/api/pkg/review/service"
+	db_statuses "getsturdy.com/api/pkg/statuses/db"
+	service_statuses "getsturdy.com/api/pkg/statuses/service"
+	"getsturdy.com/api/pkg/users"
+	"getsturdy.com/api/pkg/workspaces"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestGetAndUpdate(t *testing.T) {
+	ctx := context.Background()
+	service := service_landingrules.New(db_landingrules.NewMemory(), nil, nil, nil, nil, nil)
+	codebaseID := codebases.ID(uuid.NewString())
+
+	rules, err := service.Get(ctx, codebaseID)
+	assert.NoError(t, err)
+	assert.Equal(t, landingrules.Default(codebaseID), rules)
+
+	// default rules allow everything to land
+	assert.NoError(t, service.Check(ctx, &workspaces.Workspace{ID: uuid.NewString(), CodebaseID: codebaseID}))
+
+	rules.MinApprovals = 2
+	rules.RequiredStatuses = []string{"tests"}
+	assert.NoError(t, service.Update(ctx, rules))
+
+	rules.RequireUpToDateWithTrunk = true
+	assert.NoError(t, service.Update(ctx, rules))
+
+	got, err := service.Get(ctx, codebaseID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, got.MinApprovals)
+	assert.Equal(t, []string{"tests"}, []string(got.RequiredStatuses))
+	assert.True(t, got.RequireUpToDateWithTrunk)
+	assert.False(t, got.RequireResolvedComments)
+
+	rules.MinApprovals = -1
+	assert.Error(t, service.Update(ctx, rules))
+}
+
+func TestFailedError(t *testing.T) {
+	err := &service_landingrules.FailedError{Failures: []string{"requires 2 approving reviews, has 1", "is not up to date with the trunk"}}
+	assert.Equal(t, "landing rules failed: requires 2 approving reviews, has 1, is not up to date with the trunk", err.Error())
+}
+
+type commentsRepo struct {
+	db_comments.Repository
+
+	comments []comments.Comment
+}
+
+func (r *commentsRepo) GetByWorkspace(workspaceID string) ([]comments.Comment, error) {
+	return r.comments, nil
+}
+
+func TestCheckFailingRules(t *testing.T) {
+	ctx := context.Background()
+	reviewRepo := db_review.NewMemory()
+	commentsRepo := &commentsRepo{}
+	service := service_landingrules.New(
+		db_landingrules.NewMemory(),
+		commentsRepo,
+		service_review.New(zap.NewNop(), reviewRepo, nil, nil, nil, nil, nil, nil),
+		service_statuses.New(zap.NewNop(), db_statuses.NewMemory(), nil),
+		nil,
+		nil,
+	)
+
+	codebaseID := codebases.ID(uuid.NewString())
+	ws := &workspaces.Workspace{ID: uuid.NewString(), CodebaseID: codebaseID}
+
+	rules := landingrules.Default(codebaseID)
+	rules.MinApprovals = 1
+	rules.RequireResolvedComments = true
+	rules.RequiredStatuses = []string{"tests"}
+	assert.NoError(t, service.Update(ctx, rules))
+
+	// dismissed and replaced approvals, rejections and unresolved comments do not count
+	now := time.Now()
+	assert.NoError(t, reviewRepo.Create(ctx, review.Review{ID: uuid.NewString(), UserID: users.ID(uuid.NewString()), WorkspaceID: ws.ID, Grade: review.ReviewGradeApprove, DismissedAt: &now}))
+	assert.NoError(t, reviewRepo.Create(ctx, review.Review{ID: uuid.NewString(), UserID: users.ID(uuid.NewString()), WorkspaceID: ws.ID, Grade: review.ReviewGradeApprove, IsReplaced: true}))
+	assert.NoError(t, reviewRepo.Create(ctx, review.Review{ID: uuid.NewString(), UserID: users.ID(uuid.NewString()), WorkspaceID: ws.ID, Grade: review.ReviewGradeReject}))
+	commentsRepo.comments = []comments.Comment{{ID: comments.ID(uuid.NewString())}, {ID: comments.ID(uuid.NewString()), ResolvedAt: &now}}
+
+	err := service.Check(ctx, ws)
+	var failedErr *service_landingrules.FailedError
+	if assert.ErrorAs(t, err, &failedErr) {
+		assert.Equal(t, []string{
+			"requires 1 approving reviews, has 0",
+			"has 1 unresolved comments",
+			`status "tests" is missing`,
+		}, failedErr.Failures)
+	}
+
+	// approving and resolving fixes the rules, the missing status still blocks landing
+	assert.NoError(t, reviewRepo.Create(ctx, review.Review{ID: uuid.NewString(), UserID: users.ID(uuid.NewString()), WorkspaceID: ws.ID, Grade: review.ReviewGradeApprove}))
+	commentsRepo.comments = []comments.Comment{{ID: comments.ID(uuid.NewString()), ResolvedAt: &now}}
+
+	err = service.Check(ctx, ws)
+	if assert.ErrorAs(t, err, &failedErr) {
+		assert.Equal(t, []string{`status "tests" is missing`}, failedErr.Failures)
+	}
+
+	rules.RequiredStatuses = []string{}
+	assert.NoError(t, service.Update(ctx, rules))
+	assert.NoError(t, service.Check(ctx, ws))
+}
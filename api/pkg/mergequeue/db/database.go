@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/mergequeue"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var _ Repository = &database{}
+
+type database struct {
+	db *sqlx.DB
+}
+
+func NewDatabase(db *sqlx.DB) Repository {
+	return &database{
+		db: db,
+	}
+}
+
+func (d *database) Create(ctx context.Context, entry *mergequeue.Entry) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		INSERT INTO merge_queue_entries (
+			id, codebase_id, workspace_id, user_id, snapshot_id, status, base_commit_sha, commit_sha, tested_commit_sha, change_id, failure_reason, created_at, updated_at
+		) VALUES (
+			:id, :codebase_id, :workspace_id, :user_id, :snapshot_id, :status, :base_commit_sha, :commit_sha, :tested_commit_sha, :change_id, :failure_reason, :created_at, :updated_at
+		)
+	`, entry); err != nil {
+		return fmt.Errorf("failed to insert: %w", err)
+	}
+	return nil
+}
+
+func (d *database) Update(ctx context.Context, entry *mergequeue.Entry) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		UPDATE merge_queue_entries
+		SET snapshot_id = :snapshot_id,
+			status = :status,
+			base_commit_sha = :base_commit_sha,
+			commit_sha = :commit_sha,
+			tested_commit_sha = :tested_commit_sha,
+			change_id = :change_id,
+			failure_reason = :failure_reason,
+			updated_at = :updated_at
+		WHERE id = :id
+	`, entry); err != nil {
+		return fmt.Errorf("failed to update: %w", err)
+	}
+	return nil
+}
+
+func (d *database) Get(ctx context.Context, id mergequeue.ID) (*mergequeue.Entry, error) {
+	entry := &mergequeue.Entry{}
+	if err := d.db.GetContext(ctx, entry, `
+		SELECT
+			id, codebase_id, workspace_id, user_id, snapshot_id, status, base_commit_sha, commit_sha, tested_commit_sha, change_id, failure_reason, created_at, updated_at
+		FROM merge_queue_entries
+		WHERE id = $1
+	`, id); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+	return entry, nil
+}
+
+func (d *database) ListActiveByCodebaseID(ctx context.Context, codebaseID codebases.ID) ([]*mergequeue.Entry, error) {
+	var entries []*mergequeue.Entry
+	if err := d.db.SelectContext(ctx, &entries, `
+		SELECT
+			id, codebase_id, workspace_id, user_id, snapshot_id, status, base_commit_sha, commit_sha, tested_commit_sha, change_id, failure_reason, created_at, updated_at
+		FROM merge_queue_entries
+		WHERE codebase_id = $1
+		  AND status IN ($2, $3)
+		ORDER BY created_at ASC
+	`, codebaseID, mergequeue.StatusQueued, mergequeue.StatusTesting); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+	return entries, nil
+}
+
+func (d *database) ListActiveCodebaseIDs(ctx context.Context) ([]codebases.ID, error) {
+	var ids []codebases.ID
+	if err := d.db.SelectContext(ctx, &ids, `
+		SELECT DISTINCT codebase_id
+		FROM merge_queue_entries
+		WHERE status IN ($1, $2)
+	`, mergequeue.StatusQueued, mergequeue.StatusTesting); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+	return ids, nil
+}
+
+func (d *database) GetActiveByWorkspaceID(ctx context.Context, workspaceID string) (*mergequeue.Entry, error) {
+	entry := &mergequeue.Entry{}
+	if err := d.db.GetContext(ctx, entry, `
+		SELECT
+			id, codebase_id, workspace_id, user_id, snapshot_id, status, base_commit_sha, commit_sha, tested_commit_sha, change_id, failure_reason, created_at, updated_at
+		FROM merge_queue_entries
+		WHERE workspace_id = $1
+		  AND status IN ($2, $3)
+		ORDER BY created_at DESC
+		LIMIT 1
+	`, workspaceID, mergequeue.StatusQueued, mergequeue.StatusTesting); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+	return entry, nil
+}
+
+func (d *database) Lock(ctx context.Context, codebaseID codebases.ID) (func(), error) {
+	// advisory locks are held by the session, so the same connection is used to release the lock
+	conn, err := d.db.Connx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock(hashtext('merge_queue:' || $1))`, codebaseID); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to lock: %w", err)
+	}
+	return func() {
+		// closing the connection releases the lock even if the unlock fails
+		_, _ = conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext('merge_queue:' || $1))`, codebaseID)
+		_ = conn.Close()
+	}, nil
+}
@@ -0,0 +1,192 @@
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/internal/dbtest"
+	"getsturdy.com/api/pkg/mergequeue"
+	db_mergequeue "getsturdy.com/api/pkg/mergequeue/db"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var implementations = []func() db_mergequeue.Repository{
+	func() db_mergequeue.Repository {
+		return db_mergequeue.NewMemory()
+	},
+}
+
+var tests = []func(*testing.T, db_mergequeue.Repository){
+	ShouldListActiveInOrder,
+	ShouldGetActiveByWorkspaceID,
+	ShouldListActiveCodebaseIDs,
+	ShouldLockCodebase,
+}
+
+func newEntry(codebaseID codebases.ID, workspaceID string, status mergequeue.Status, createdAt time.Time) *mergequeue.Entry {
+	return &mergequeue.Entry{
+		ID:          mergequeue.ID(uuid.NewString()),
+		CodebaseID:  codebaseID,
+		WorkspaceID: workspaceID,
+		UserID:      "user-id",
+		SnapshotID:  "snapshot-id",
+		Status:      status,
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+	}
+}
+
+func ShouldListActiveInOrder(t *testing.T, repo db_mergequeue.Repository) {
+	ctx := context.Background()
+	codebaseID := codebases.ID(uuid.NewString())
+	now := time.Now().Truncate(time.Second)
+
+	second := newEntry(codebaseID, uuid.NewString(), mergequeue.StatusQueued, now.Add(time.Second))
+	first := newEntry(codebaseID, uuid.NewString(), mergequeue.StatusTesting, now)
+	landed := newEntry(codebaseID, uuid.NewString(), mergequeue.StatusLanded, now.Add(-time.Second))
+	other := newEntry(codebases.ID(uuid.NewString()), uuid.NewString(), mergequeue.StatusQueued, now)
+
+	for _, entry := range []*mergequeue.Entry{second, first, landed, other} {
+		assert.NoError(t, repo.Create(ctx, entry))
+	}
+
+	entries, err := repo.ListActiveByCodebaseID(ctx, codebaseID)
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, first.ID, entries[0].ID)
+		assert.Equal(t, second.ID, entries[1].ID)
+	}
+
+	first.Status = mergequeue.StatusLanded
+	assert.NoError(t, repo.Update(ctx, first))
+
+	entries, err = repo.ListActiveByCodebaseID(ctx, codebaseID)
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, second.ID, entries[0].ID)
+	}
+}
+
+func ShouldGetActiveByWorkspaceID(t *testing.T, repo db_mergequeue.Repository) {
+	ctx := context.Background()
+	codebaseID := codebases.ID(uuid.NewString())
+	workspaceID := uuid.NewString()
+	now := time.Now().Truncate(time.Second)
+
+	failed := newEntry(codebaseID, workspaceID, mergequeue.StatusFailed, now.Add(-time.Second))
+	assert.NoError(t, repo.Create(ctx, failed))
+
+	_, err := repo.GetActiveByWorkspaceID(ctx, workspaceID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	queued := newEntry(codebaseID, workspaceID, mergequeue.StatusQueued, now)
+	assert.NoError(t, repo.Create(ctx, queued))
+
+	entry, err := repo.GetActiveByWorkspaceID(ctx, workspaceID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, queued.ID, entry.ID)
+	}
+
+	entry, err = repo.Get(ctx, failed.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, mergequeue.StatusFailed, entry.Status)
+	}
+}
+
+func ShouldListActiveCodebaseIDs(t *testing.T, repo db_mergequeue.Repository) {
+	ctx := context.Background()
+	active := codebases.ID(uuid.NewString())
+	inactive := codebases.ID(uuid.NewString())
+	now := time.Now().Truncate(time.Second)
+
+	assert.NoError(t, repo.Create(ctx, newEntry(active, uuid.NewString(), mergequeue.StatusQueued, now)))
+	assert.NoError(t, repo.Create(ctx, newEntry(active, uuid.NewString(), mergequeue.StatusTesting, now)))
+	assert.NoError(t, repo.Create(ctx, newEntry(inactive, uuid.NewString(), mergequeue.StatusDequeued, now)))
+
+	ids, err := repo.ListActiveCodebaseIDs(ctx)
+	assert.NoError(t, err)
+	assert.Contains(t, ids, active)
+	assert.NotContains(t, ids, inactive)
+}
+
+func ShouldLockCodebase(t *testing.T, repo db_mergequeue.Repository) {
+	ctx := context.Background()
+	codebaseID := codebases.ID(uuid.NewString())
+
+	unlock, err := repo.Lock(ctx, codebaseID)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// other codebases are not locked
+	unlockOther, err := repo.Lock(ctx, codebases.ID(uuid.NewString()))
+	if assert.NoError(t, err) {
+		unlockOther()
+	}
+
+	// the codebase can not be locked again until it's unlocked
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	_, err = repo.Lock(timeoutCtx, codebaseID)
+	assert.Error(t, err)
+
+	unlock()
+
+	unlock, err = repo.Lock(ctx, codebaseID)
+	if assert.NoError(t, err) {
+		unlock()
+	}
+}
+
+func TestMain(m *testing.M) {
+	defer m.Run()
+
+	if os.Getenv("E2E_TEST") == "" {
+		return
+	}
+
+	// register real db implementation
+	sqldb := dbtest.MustGetDB()
+	databaseImplementation := func() db_mergequeue.Repository { return db_mergequeue.NewDatabase(sqldb) }
+
+	implementations = append(implementations, databaseImplementation)
+}
+
+// runs all tests for a all implementations
+func TestImplementations(t *testing.T) {
+	for _, test := range tests {
+		t.Run(funcName(test), func(t *testing.T) {
+			for _, repoProvider := range implementations {
+				repo := repoProvider()
+				t.Run(implName(repo), func(t *testing.T) {
+					test(t, repo)
+				})
+			}
+		})
+	}
+}
+
+func funcName(v any) string {
+	pc := reflect.ValueOf(v).Pointer()
+	nameFull := runtime.FuncForPC(pc).Name()
+	nameEnd := filepath.Ext(nameFull)
+	name := strings.TrimPrefix(nameEnd, ".")
+	return name
+}
+
+func implName(v any) string {
+	nameFull := reflect.TypeOf(v).String()
+	nameEnd := filepath.Ext(nameFull)
+	name := strings.TrimPrefix(nameEnd, ".")
+	return name
+}
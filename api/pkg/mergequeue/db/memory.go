@@ -0,0 +1,128 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/mergequeue"
+)
+
+var _ Repository = &memory{}
+
+type memory struct {
+	mu   sync.RWMutex
+	byID map[mergequeue.ID]*mergequeue.Entry
+
+	locksMu sync.Mutex
+	locks   map[codebases.ID]chan struct{}
+}
+
+func NewMemory() Repository {
+	return &memory{
+		byID:  map[mergequeue.ID]*mergequeue.Entry{},
+		locks: map[codebases.ID]chan struct{}{},
+	}
+}
+
+func (m *memory) Create(_ context.Context, entry *mergequeue.Entry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *entry
+	m.byID[entry.ID] = &cp
+	return nil
+}
+
+func (m *memory) Update(_ context.Context, entry *mergequeue.Entry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, found := m.byID[entry.ID]; !found {
+		return sql.ErrNoRows
+	}
+	cp := *entry
+	m.byID[entry.ID] = &cp
+	return nil
+}
+
+func (m *memory) Get(_ context.Context, id mergequeue.ID) (*mergequeue.Entry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	entry, found := m.byID[id]
+	if !found {
+		return nil, sql.ErrNoRows
+	}
+	cp := *entry
+	return &cp, nil
+}
+
+func (m *memory) active() []*mergequeue.Entry {
+	var entries []*mergequeue.Entry
+	for _, entry := range m.byID {
+		if !entry.Status.IsActive() {
+			continue
+		}
+		cp := *entry
+		entries = append(entries, &cp)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+	return entries
+}
+
+func (m *memory) ListActiveByCodebaseID(_ context.Context, codebaseID codebases.ID) ([]*mergequeue.Entry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var entries []*mergequeue.Entry
+	for _, entry := range m.active() {
+		if entry.CodebaseID == codebaseID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (m *memory) ListActiveCodebaseIDs(_ context.Context) ([]codebases.ID, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	seen := map[codebases.ID]bool{}
+	var ids []codebases.ID
+	for _, entry := range m.active() {
+		if seen[entry.CodebaseID] {
+			continue
+		}
+		seen[entry.CodebaseID] = true
+		ids = append(ids, entry.CodebaseID)
+	}
+	return ids, nil
+}
+
+func (m *memory) GetActiveByWorkspaceID(_ context.Context, workspaceID string) (*mergequeue.Entry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, entry := range m.active() {
+		if entry.WorkspaceID == workspaceID {
+			return entry, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (m *memory) Lock(ctx context.Context, codebaseID codebases.ID) (func(), error) {
+	m.locksMu.Lock()
+	lock, found := m.locks[codebaseID]
+	if !found {
+		lock = make(chan struct{}, 1)
+		m.locks[codebaseID] = lock
+	}
+	m.locksMu.Unlock()
+
+	select {
+	case lock <- struct{}{}:
+		return func() { <-lock }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
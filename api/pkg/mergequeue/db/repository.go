@@ -0,0 +1,24 @@
+package db
+
+import (
+	"context"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/mergequeue"
+)
+
+type Repository interface {
+	Create(context.Context, *mergequeue.Entry) error
+	Update(context.Context, *mergequeue.Entry) error
+	Get(context.Context, mergequeue.ID) (*mergequeue.Entry, error)
+	// ListActiveByCodebaseID returns all queued and testing entries in the codebase, ordered by when they were
+	// enqueued.
+	ListActiveByCodebaseID(context.Context, codebases.ID) ([]*mergequeue.Entry, error)
+	// ListActiveCodebaseIDs returns the ids of all codebases that have at least one queued or testing entry.
+	ListActiveCodebaseIDs(context.Context) ([]codebases.ID, error)
+	// GetActiveByWorkspaceID returns the queued or testing entry of the workspace, if any.
+	GetActiveByWorkspaceID(context.Context, string) (*mergequeue.Entry, error)
+	// Lock blocks until the merge queue of the codebase is locked, and returns a function that releases the lock.
+	// The lock is shared between all replicas of the api.
+	Lock(context.Context, codebases.ID) (func(), error)
+}
@@ -0,0 +1,69 @@
+package mergequeue
+
+import (
+	"fmt"
+	"time"
+
+	"getsturdy.com/api/pkg/changes"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/snapshots"
+	"getsturdy.com/api/pkg/users"
+)
+
+type ID string
+
+func (id ID) String() string {
+	return string(id)
+}
+
+type Status string
+
+const (
+	// StatusQueued is the status of entries that are waiting for a speculative commit to be created.
+	StatusQueued Status = "queued"
+	// StatusTesting is the status of entries that have a speculative commit, and are waiting for the statuses
+	// of that commit to be reported.
+	StatusTesting Status = "testing"
+	// StatusLanded is the status of entries that have been landed on the trunk.
+	StatusLanded Status = "landed"
+	// StatusFailed is the status of entries that could not be landed.
+	StatusFailed Status = "failed"
+	// StatusDequeued is the status of entries that have been removed from the queue by a user.
+	StatusDequeued Status = "dequeued"
+)
+
+func (s Status) String() string {
+	return string(s)
+}
+
+// IsActive returns true if the entry with the status is still waiting to be landed.
+func (s Status) IsActive() bool {
+	return s == StatusQueued || s == StatusTesting
+}
+
+// Entry is a workspace that is waiting in the merge queue of a codebase.
+type Entry struct {
+	ID          ID           `db:"id"`
+	CodebaseID  codebases.ID `db:"codebase_id"`
+	WorkspaceID string       `db:"workspace_id"`
+	UserID      users.ID     `db:"user_id"`
+	// SnapshotID is the snapshot of the workspace that was enqueued.
+	SnapshotID snapshots.ID `db:"snapshot_id"`
+	Status     Status       `db:"status"`
+	// BaseCommitSHA is the commit that the speculative commit is built on top of. This is either the head of the
+	// trunk, or the speculative commit of the entry ahead in the queue.
+	BaseCommitSHA *string `db:"base_commit_sha"`
+	// CommitSHA is the speculative result of landing the entry on top of BaseCommitSHA.
+	CommitSHA *string `db:"commit_sha"`
+	// TestedCommitSHA is the commit that builds have been triggered for. It has the same tree as CommitSHA.
+	TestedCommitSHA *string     `db:"tested_commit_sha"`
+	ChangeID        *changes.ID `db:"change_id"`
+	FailureReason   *string     `db:"failure_reason"`
+	CreatedAt       time.Time   `db:"created_at"`
+	UpdatedAt       time.Time   `db:"updated_at"`
+}
+
+// BranchName is the name of the branch that holds the speculative commit of the entry.
+func (e *Entry) BranchName() string {
+	return fmt.Sprintf("mergequeue-%s", e.ID)
+}
@@ -0,0 +1,196 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"getsturdy.com/api/pkg/auth"
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	"getsturdy.com/api/pkg/codebases"
+	service_codebases "getsturdy.com/api/pkg/codebases/service"
+	"getsturdy.com/api/pkg/events"
+	eventsv2 "getsturdy.com/api/pkg/events/v2"
+	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
+	"getsturdy.com/api/pkg/graphql/resolvers"
+	"getsturdy.com/api/pkg/mergequeue"
+	service_mergequeue "getsturdy.com/api/pkg/mergequeue/service"
+	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
+
+	"github.com/graph-gophers/graphql-go"
+	"go.uber.org/zap"
+)
+
+type rootResolver struct {
+	logger            *zap.Logger
+	authService       *service_auth.Service
+	codebaseService   *service_codebases.Service
+	workspaceService  *service_workspaces.Service
+	mergeQueueService *service_mergequeue.Service
+	eventsSubscriber  *eventsv2.Subscriber
+
+	workspaceResolver resolvers.WorkspaceRootResolver
+}
+
+func New(
+	logger *zap.Logger,
+	authService *service_auth.Service,
+	codebaseService *service_codebases.Service,
+	workspaceService *service_workspaces.Service,
+	mergeQueueService *service_mergequeue.Service,
+	eventsSubscriber *eventsv2.Subscriber,
+	workspaceResolver resolvers.WorkspaceRootResolver,
+) resolvers.MergeQueueRootResolver {
+	return &rootResolver{
+		logger:            logger.Named("mergeQueueRootResolver"),
+		authService:       authService,
+		codebaseService:   codebaseService,
+		workspaceService:  workspaceService,
+		mergeQueueService: mergeQueueService,
+		eventsSubscriber:  eventsSubscriber,
+		workspaceResolver: workspaceResolver,
+	}
+}
+
+func (r *rootResolver) MergeQueue(ctx context.Context, args resolvers.MergeQueueArgs) ([]resolvers.MergeQueueEntryResolver, error) {
+	cb, err := r.codebaseService.GetByID(ctx, codebases.ID(args.CodebaseID))
+	if err != nil {
+		return nil, gqlerrors.Error(fmt.Errorf("failed to get codebase: %w", err))
+	}
+
+	if err := r.authService.CanRead(ctx, cb); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	entries, err := r.mergeQueueService.List(ctx, cb.ID)
+	if err != nil {
+		return nil, gqlerrors.Error(fmt.Errorf("failed to list merge queue: %w", err))
+	}
+
+	res := make([]resolvers.MergeQueueEntryResolver, 0, len(entries))
+	for _, entry := range entries {
+		res = append(res, &entryResolver{root: r, entry: entry})
+	}
+	return res, nil
+}
+
+func (r *rootResolver) DequeueWorkspace(ctx context.Context, args resolvers.DequeueWorkspaceArgs) (resolvers.WorkspaceResolver, error) {
+	ws, err := r.workspaceService.GetByID(ctx, string(args.WorkspaceID))
+	if err != nil {
+		return nil, gqlerrors.Error(fmt.Errorf("failed to get workspace: %w", err))
+	}
+
+	if err := r.authService.CanWrite(ctx, ws); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	_, err = r.mergeQueueService.Dequeue(ctx, ws)
+	switch {
+	case errors.Is(err, service_mergequeue.ErrNotQueued):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "This draft is not in the merge queue")
+	case err != nil:
+		return nil, gqlerrors.Error(fmt.Errorf("failed to remove workspace from merge queue: %w", err))
+	}
+
+	return r.workspaceResolver.InternalWorkspace(ws), nil
+}
+
+func (r *rootResolver) UpdatedMergeQueue(ctx context.Context, args resolvers.UpdatedMergeQueueArgs) (<-chan resolvers.MergeQueueEntryResolver, error) {
+	userID, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	cb, err := r.codebaseService.GetByID(ctx, codebases.ID(args.CodebaseID))
+	if err != nil {
+		return nil, gqlerrors.Error(fmt.Errorf("failed to get codebase: %w", err))
+	}
+
+	if err := r.authService.CanRead(ctx, cb); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	c := make(chan resolvers.MergeQueueEntryResolver, 100)
+	r.eventsSubscriber.OnMergeQueueEntryUpdated(ctx, eventsv2.SubscribeUser(userID), func(ctx context.Context, entry *mergequeue.Entry) error {
+		if entry.CodebaseID != cb.ID {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return events.ErrClientDisconnected
+		case c <- &entryResolver{root: r, entry: entry}:
+			return nil
+		default:
+			r.logger.Error(
+				"dropped subscription event",
+				zap.Stringer("user_id", userID),
+				zap.Stringer("event_type", eventsv2.MergeQueueEntryUpdated),
+				zap.Int("channel_size", len(c)),
+			)
+			return nil
+		}
+	})
+
+	return c, nil
+}
+
+type entryResolver struct {
+	root  *rootResolver
+	entry *mergequeue.Entry
+}
+
+func (r *entryResolver) ID() graphql.ID {
+	return graphql.ID(r.entry.ID)
+}
+
+func (r *entryResolver) Workspace(ctx context.Context) (resolvers.WorkspaceResolver, error) {
+	allowArchived := true
+	return r.root.workspaceResolver.Workspace(ctx, resolvers.WorkspaceArgs{
+		ID:            graphql.ID(r.entry.WorkspaceID),
+		AllowArchived: &allowArchived,
+	})
+}
+
+func (r *entryResolver) Status() (resolvers.MergeQueueEntryStatus, error) {
+	switch r.entry.Status {
+	case mergequeue.StatusQueued:
+		return resolvers.MergeQueueEntryStatusQueued, nil
+	case mergequeue.StatusTesting:
+		return resolvers.MergeQueueEntryStatusTesting, nil
+	case mergequeue.StatusLanded:
+		return resolvers.MergeQueueEntryStatusLanded, nil
+	case mergequeue.StatusFailed:
+		return resolvers.MergeQueueEntryStatusFailed, nil
+	case mergequeue.StatusDequeued:
+		return resolvers.MergeQueueEntryStatusDequeued, nil
+	default:
+		return resolvers.MergeQueueEntryStatusUndefined, fmt.Errorf("undefined status: %s", r.entry.Status)
+	}
+}
+
+func (r *entryResolver) Position(ctx context.Context) (*int32, error) {
+	if !r.entry.Status.IsActive() {
+		return nil, nil
+	}
+	position, err := r.root.mergeQueueService.Position(ctx, r.entry)
+	switch {
+	case errors.Is(err, service_mergequeue.ErrNotQueued):
+		return nil, nil
+	case err != nil:
+		return nil, gqlerrors.Error(err)
+	}
+	p := int32(position)
+	return &p, nil
+}
+
+func (r *entryResolver) FailureReason() *string {
+	return r.entry.FailureReason
+}
+
+func (r *entryResolver) CreatedAt() int32 {
+	return int32(r.entry.CreatedAt.Unix())
+}
+
+func (r *entryResolver) UpdatedAt() int32 {
+	return int32(r.entry.UpdatedAt.Unix())
+}
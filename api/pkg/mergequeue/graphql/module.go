@@ -0,0 +1,23 @@
+package graphql
+
+import (
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	service_codebases "getsturdy.com/api/pkg/codebases/service"
+	"getsturdy.com/api/pkg/di"
+	eventsv2 "getsturdy.com/api/pkg/events/v2"
+	"getsturdy.com/api/pkg/logger"
+	service_mergequeue "getsturdy.com/api/pkg/mergequeue/service/module"
+	graphql_workspaces "getsturdy.com/api/pkg/workspaces/graphql"
+	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
+)
+
+func Module(c *di.Container) {
+	c.Import(logger.Module)
+	c.Import(service_auth.Module)
+	c.Import(service_codebases.Module)
+	c.Import(service_workspaces.Module)
+	c.Import(service_mergequeue.Module)
+	c.Import(eventsv2.Module)
+	c.Import(graphql_workspaces.Module)
+	c.Register(New)
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	service_ci "getsturdy.com/api/pkg/ci/service"
+	"getsturdy.com/api/pkg/di"
+	eventsv2 "getsturdy.com/api/pkg/events/v2"
+	"getsturdy.com/api/pkg/logger"
+	db_mergequeue "getsturdy.com/api/pkg/mergequeue/db"
+	queue "getsturdy.com/api/pkg/queue/module"
+	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
+	service_statuses "getsturdy.com/api/pkg/statuses/service"
+	service_users "getsturdy.com/api/pkg/users/service/module"
+	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
+	"getsturdy.com/api/vcs/executor"
+)
+
+func Module(c *di.Container) {
+	c.Import(logger.Module)
+	c.Import(db_mergequeue.Module)
+	c.Import(service_users.Module)
+	c.Import(service_workspaces.Module)
+	c.Import(service_snapshots.Module)
+	c.Import(service_ci.Module)
+	c.Import(service_statuses.Module)
+	c.Import(executor.Module)
+	c.Import(eventsv2.Module)
+	c.Import(queue.Module)
+	c.Register(New)
+}
@@ -0,0 +1,18 @@
+//go:build enterprise || cloud
+// +build enterprise cloud
+
+package module
+
+import (
+	"getsturdy.com/api/pkg/di"
+	service_land "getsturdy.com/api/pkg/land/enterprise/service"
+	service_mergequeue "getsturdy.com/api/pkg/mergequeue/service"
+)
+
+func Module(c *di.Container) {
+	c.Import(service_land.Module)
+	c.Import(service_mergequeue.Module)
+	c.Register(func(s *service_land.Service) service_mergequeue.Lander {
+		return s
+	})
+}
@@ -0,0 +1,555 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"getsturdy.com/api/pkg/changes"
+	"getsturdy.com/api/pkg/changes/message"
+	service_ci "getsturdy.com/api/pkg/ci/service"
+	"getsturdy.com/api/pkg/codebases"
+	eventsv2 "getsturdy.com/api/pkg/events/v2"
+	service_land "getsturdy.com/api/pkg/land/service"
+	"getsturdy.com/api/pkg/mergequeue"
+	db_mergequeue "getsturdy.com/api/pkg/mergequeue/db"
+	"getsturdy.com/api/pkg/queue"
+	"getsturdy.com/api/pkg/queue/names"
+	"getsturdy.com/api/pkg/snapshots"
+	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
+	"getsturdy.com/api/pkg/statuses"
+	service_statuses "getsturdy.com/api/pkg/statuses/service"
+	"getsturdy.com/api/pkg/trunks"
+	service_users "getsturdy.com/api/pkg/users/service"
+	"getsturdy.com/api/pkg/workspaces"
+	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
+	"getsturdy.com/api/vcs"
+	"getsturdy.com/api/vcs/executor"
+
+	"github.com/google/uuid"
+	git "github.com/libgit2/git2go/v33"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrAlreadyQueued     = errors.New("workspace is already in the merge queue")
+	ErrNotQueued         = errors.New("workspace is not in the merge queue")
+	ErrNoSnapshot        = errors.New("workspace has no snapshot")
+	ErrNotOnDefaultTrunk = errors.New("only workspaces on the default trunk can be merged through the merge queue")
+)
+
+// testingTimeout is how long an entry can wait for the statuses of its speculative commit before it's failed.
+var testingTimeout = 2 * time.Hour
+
+// Lander lands verified commits on the trunk.
+type Lander interface {
+	LandCommit(ctx context.Context, ws *workspaces.Workspace, commitSHA string) (*changes.Change, error)
+}
+
+// Message is the message that is published to the merge queue worker, when the queue of a codebase needs to be
+// processed.
+type Message struct {
+	CodebaseID codebases.ID `json:"codebase_id"`
+}
+
+type Service struct {
+	logger *zap.Logger
+
+	repo db_mergequeue.Repository
+
+	usersService     service_users.Service
+	workspaceService *service_workspaces.Service
+	snapshotter      *service_snapshots.Service
+	ciService        *service_ci.Service
+	statusesService  *service_statuses.Service
+	lander           Lander
+
+	executorProvider executor.Provider
+	eventsPublisher  *eventsv2.Publisher
+	queue            queue.Queue
+}
+
+func New(
+	logger *zap.Logger,
+
+	repo db_mergequeue.Repository,
+
+	usersService service_users.Service,
+	workspaceService *service_workspaces.Service,
+	snapshotter *service_snapshots.Service,
+	ciService *service_ci.Service,
+	statusesService *service_statuses.Service,
+	lander Lander,
+
+	executorProvider executor.Provider,
+	eventsPublisher *eventsv2.Publisher,
+	queue queue.Queue,
+) *Service {
+	return &Service{
+		logger: logger.Named("mergeQueueService"),
+
+		repo: repo,
+
+		usersService:     usersService,
+		workspaceService: workspaceService,
+		snapshotter:      snapshotter,
+		ciService:        ciService,
+		statusesService:  statusesService,
+		lander:           lander,
+
+		executorProvider: executorProvider,
+		eventsPublisher:  eventsPublisher,
+		queue:            queue,
+	}
+}
+
+// Enqueue adds the workspace to the end of the merge queue of its codebase.
+func (s *Service) Enqueue(ctx context.Context, ws *workspaces.Workspace) (*mergequeue.Entry, error) {
+	if ws.TrunkID != nil {
+		return nil, ErrNotOnDefaultTrunk
+	}
+
+	if _, err := s.repo.GetActiveByWorkspaceID(ctx, ws.ID); err == nil {
+		return nil, ErrAlreadyQueued
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get entry: %w", err)
+	}
+
+	snapshotID := ws.LatestSnapshotID
+	if ws.ViewID != nil {
+		// make sure that the latest changes in the view are enqueued
+		snapshot, err := s.snapshotter.Snapshot(ctx, ws.CodebaseID, ws.ID, snapshots.ActionMergeQueue,
+			service_snapshots.WithOnView(*ws.ViewID),
+			service_snapshots.WithNoThrottle(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to snapshot: %w", err)
+		}
+		snapshotID = &snapshot.ID
+	}
+	if snapshotID == nil {
+		return nil, ErrNoSnapshot
+	}
+
+	now := time.Now()
+	entry := &mergequeue.Entry{
+		ID:          mergequeue.ID(uuid.NewString()),
+		CodebaseID:  ws.CodebaseID,
+		WorkspaceID: ws.ID,
+		UserID:      ws.UserID,
+		SnapshotID:  *snapshotID,
+		Status:      mergequeue.StatusQueued,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.repo.Create(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to create entry: %w", err)
+	}
+
+	if err := s.eventsPublisher.MergeQueueEntryUpdated(ctx, eventsv2.Codebase(entry.CodebaseID), entry); err != nil {
+		s.logger.Error("failed to send merge queue event", zap.Error(err))
+		// do not fail
+	}
+
+	if err := s.Notify(ctx, entry.CodebaseID); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// Dequeue removes the workspace from the merge queue.
+func (s *Service) Dequeue(ctx context.Context, ws *workspaces.Workspace) (*mergequeue.Entry, error) {
+	entry, err := s.repo.GetActiveByWorkspaceID(ctx, ws.ID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, ErrNotQueued
+	case err != nil:
+		return nil, fmt.Errorf("failed to get entry: %w", err)
+	}
+
+	if err := s.setStatus(ctx, entry, mergequeue.StatusDequeued, nil); err != nil {
+		return nil, err
+	}
+
+	// entries behind this one have to be rebuilt
+	if err := s.Notify(ctx, entry.CodebaseID); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// Notify schedules the merge queue of the codebase to be processed.
+func (s *Service) Notify(ctx context.Context, codebaseID codebases.ID) error {
+	if err := s.queue.Publish(ctx, names.MergeQueue, &Message{CodebaseID: codebaseID}); err != nil {
+		return fmt.Errorf("failed to publish to queue: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) GetByID(ctx context.Context, id mergequeue.ID) (*mergequeue.Entry, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// GetActiveByWorkspaceID returns the entry of the workspace if it's currently in the merge queue.
+func (s *Service) GetActiveByWorkspaceID(ctx context.Context, workspaceID string) (*mergequeue.Entry, error) {
+	return s.repo.GetActiveByWorkspaceID(ctx, workspaceID)
+}
+
+// List returns all entries that are currently in the merge queue of the codebase, in the order that they will be
+// landed.
+func (s *Service) List(ctx context.Context, codebaseID codebases.ID) ([]*mergequeue.Entry, error) {
+	return s.repo.ListActiveByCodebaseID(ctx, codebaseID)
+}
+
+// ListActiveCodebaseIDs returns the ids of all codebases that have workspaces in the merge queue.
+func (s *Service) ListActiveCodebaseIDs(ctx context.Context) ([]codebases.ID, error) {
+	return s.repo.ListActiveCodebaseIDs(ctx)
+}
+
+// Position returns the position of the entry in the queue. The first entry in the queue has position 0.
+func (s *Service) Position(ctx context.Context, entry *mergequeue.Entry) (int, error) {
+	entries, err := s.repo.ListActiveByCodebaseID(ctx, entry.CodebaseID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list entries: %w", err)
+	}
+	for i, e := range entries {
+		if e.ID == entry.ID {
+			return i, nil
+		}
+	}
+	return 0, ErrNotQueued
+}
+
+// Process advances the merge queue of the codebase.
+//
+// Every entry in the queue gets a speculative commit, that is the result of rebasing the workspace on top of the
+// speculative commit of the entry ahead of it (or the trunk, for the first entry). Builds are triggered for all
+// speculative commits, and the first entry in the queue is landed as soon as all statuses of its commit are healthy.
+//
+// If an entry fails, all entries behind it are rebased again on top of the remaining entries.
+//
+// The queue of a codebase is only processed by one replica at a time, concurrent calls wait for each other.
+func (s *Service) Process(ctx context.Context, codebaseID codebases.ID) error {
+	unlock, err := s.repo.Lock(ctx, codebaseID)
+	if err != nil {
+		return fmt.Errorf("failed to lock merge queue: %w", err)
+	}
+	defer unlock()
+
+	entries, err := s.repo.ListActiveByCodebaseID(ctx, codebaseID)
+	if err != nil {
+		return fmt.Errorf("failed to list entries: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	trunkHeadCommitID, err := s.trunkHead(ctx, codebaseID)
+	if err != nil {
+		return err
+	}
+
+	// if continuous integration is set up, its statuses are required before an entry can land
+	ciIntegrations, err := s.ciService.ListByCodebaseID(ctx, codebaseID)
+	if err != nil {
+		return fmt.Errorf("failed to list integrations: %w", err)
+	}
+	statusesRequired := len(ciIntegrations) > 0
+
+	onto := trunkHeadCommitID
+	for _, entry := range entries {
+		logger := s.logger.With(
+			zap.Stringer("codebase_id", codebaseID),
+			zap.Stringer("entry_id", entry.ID),
+			zap.String("workspace_id", entry.WorkspaceID),
+		)
+
+		ws, err := s.workspaceService.GetByID(ctx, entry.WorkspaceID)
+		if err != nil {
+			return fmt.Errorf("failed to get workspace: %w", err)
+		}
+
+		switch {
+		case ws.ArchivedAt != nil:
+			if err := s.fail(ctx, entry, "The draft has been archived"); err != nil {
+				return err
+			}
+			continue
+		case ws.LatestSnapshotID == nil || *ws.LatestSnapshotID != entry.SnapshotID:
+			if err := s.fail(ctx, entry, "The draft has been updated since it was added to the merge queue"); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if entry.BaseCommitSHA == nil || *entry.BaseCommitSHA != onto || entry.CommitSHA == nil || entry.TestedCommitSHA == nil {
+			conflicts, err := s.speculate(ctx, entry, ws, onto)
+			if err != nil {
+				return fmt.Errorf("failed to create speculative commit: %w", err)
+			}
+			if conflicts {
+				if err := s.fail(ctx, entry, "The draft has conflicts with the changes ahead of it in the queue"); err != nil {
+					return err
+				}
+				continue
+			}
+			logger.Info("created speculative commit", zap.Stringp("commit_sha", entry.CommitSHA))
+		}
+
+		statusList, err := s.statusesService.List(ctx, codebaseID, *entry.TestedCommitSHA)
+		if err != nil {
+			return fmt.Errorf("failed to list statuses: %w", err)
+		}
+
+		if failing := failingStatus(statusList); failing != nil {
+			if err := s.fail(ctx, entry, fmt.Sprintf("%s is failing", failing.Title)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		isFirst := *entry.BaseCommitSHA == trunkHeadCommitID
+		if isFirst && allHealthy(statusList, statusesRequired) {
+			change, err := s.lander.LandCommit(ctx, ws, *entry.CommitSHA)
+			if errors.Is(err, service_land.ErrWorkspaceUpdated) {
+				if err := s.fail(ctx, entry, "The draft has been updated since it was added to the merge queue"); err != nil {
+					return err
+				}
+				continue
+			} else if err != nil {
+				logger.Error("failed to land", zap.Error(err))
+				if err := s.fail(ctx, entry, "Failed to land the draft"); err != nil {
+					return err
+				}
+				continue
+			}
+
+			entry.ChangeID = &change.ID
+			if err := s.setStatus(ctx, entry, mergequeue.StatusLanded, nil); err != nil {
+				return err
+			}
+
+			logger.Info("landed", zap.Stringer("change_id", change.ID))
+
+			trunkHeadCommitID = *entry.CommitSHA
+			onto = trunkHeadCommitID
+			continue
+		}
+
+		if time.Since(entry.UpdatedAt) > testingTimeout {
+			if err := s.fail(ctx, entry, "Timed out waiting for the statuses of the draft"); err != nil {
+				return err
+			}
+			continue
+		}
+
+		onto = *entry.CommitSHA
+	}
+
+	return nil
+}
+
+func failingStatus(statusList []*statuses.Status) *statuses.Status {
+	for _, status := range statusList {
+		if status.Type == statuses.TypeFailing {
+			return status
+		}
+	}
+	return nil
+}
+
+// allHealthy returns true if all statuses are healthy. If statuses are required, there has to be at least one.
+func allHealthy(statusList []*statuses.Status, required bool) bool {
+	if required && len(statusList) == 0 {
+		return false
+	}
+	for _, status := range statusList {
+		if status.Type != statuses.TypeHealthy {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Service) trunkHead(ctx context.Context, codebaseID codebases.ID) (string, error) {
+	var trunkHeadCommitID string
+	if err := s.executorProvider.New().GitRead(func(repo vcs.RepoGitReader) error {
+		var err error
+		// only workspaces on the default trunk can be enqueued
+		trunkHeadCommitID, err = repo.BranchCommitID(trunks.BranchName(nil))
+		return err
+	}).ExecTrunkContext(ctx, codebaseID, "mergeQueueTrunkHead"); err != nil {
+		return "", fmt.Errorf("failed to get trunk head: %w", err)
+	}
+	return trunkHeadCommitID, nil
+}
+
+// speculate creates a commit with the changes of the entry on top of onto, and triggers builds for it.
+func (s *Service) speculate(ctx context.Context, entry *mergequeue.Entry, ws *workspaces.Workspace, onto string) (bool, error) {
+	snapshot, err := s.snapshotter.GetByID(ctx, entry.SnapshotID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+
+	user, err := s.usersService.GetByID(ctx, entry.UserID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	signature := git.Signature{
+		Name:  user.Name,
+		Email: user.Email,
+		When:  time.Now(),
+	}
+
+	// the builds of the previous speculative commit are no longer needed
+	s.cancelBuilds(ctx, entry)
+	entry.TestedCommitSHA = nil
+
+	var (
+		commitSHA string
+		conflicts bool
+	)
+	if err := s.executorProvider.New().
+		Write(func(repo vcs.RepoWriter) error {
+			if err := repo.FetchBranch(snapshot.BranchName()); err != nil {
+				return fmt.Errorf("failed to fetch snapshot: %w", err)
+			}
+
+			rebasingBranchName := fmt.Sprintf("rebasing-%s", entry.ID)
+			if err := repo.CreateAndCheckoutBranchAtCommit(onto, rebasingBranchName); err != nil {
+				return fmt.Errorf("create and checkout branch failed: %w", err)
+			}
+
+			rb, _, err := repo.InitRebaseRaw(snapshot.CommitSHA, onto)
+			if err != nil {
+				return fmt.Errorf("failed to rebase: %w", err)
+			}
+
+			rebaseStatus, err := rb.Status()
+			if err != nil {
+				return fmt.Errorf("failed to get rebase status: %w", err)
+			}
+
+			if rebaseStatus == vcs.RebaseHaveConflicts {
+				conflicts = true
+				return nil
+			}
+
+			head, err := repo.HeadCommit()
+			if err != nil {
+				return fmt.Errorf("failed to get head: %w", err)
+			}
+			defer head.Free()
+
+			// the rebased snapshot is replaced with a commit that has the same contents, but with the message and
+			// author of the change, so that it can be landed as is
+			commitSHA, err = repo.CreateNewCommitBasedOnCommit(entry.BranchName(), head.Id().String(), signature, message.CommitMessage(ws.DraftDescription))
+			if err != nil {
+				return fmt.Errorf("failed to create commit: %w", err)
+			}
+
+			if err := repo.ForcePush(s.logger, entry.BranchName()); err != nil {
+				return fmt.Errorf("failed to push: %w", err)
+			}
+
+			return nil
+		}).ExecTemporaryViewContext(ctx, entry.CodebaseID, "mergeQueueSpeculate"); err != nil {
+		return false, err
+	}
+
+	if conflicts {
+		return true, nil
+	}
+
+	// the speculative commit is snapshotted, so that it can be tested by the continuous integration in the same way
+	// as the workspace itself
+	var speculativeSnapshot *snapshots.Snapshot
+	if err := s.executorProvider.New().
+		FileReadGitWrite(func(repo vcs.RepoReaderGitWriter) error {
+			var err error
+			speculativeSnapshot, err = s.snapshotter.Snapshot(ctx, entry.CodebaseID, entry.WorkspaceID, snapshots.ActionMergeQueue,
+				service_snapshots.WithOnTemporaryView(),
+				service_snapshots.WithOnExistingCommit(commitSHA),
+				service_snapshots.WithOnRepo(repo),
+			)
+			return err
+		}).ExecTrunkContext(ctx, entry.CodebaseID, "mergeQueueSnapshot"); err != nil {
+		return false, fmt.Errorf("failed to snapshot speculative commit: %w", err)
+	}
+
+	entry.BaseCommitSHA = &onto
+	entry.CommitSHA = &commitSHA
+	// if the speculative commit has the same contents as the latest snapshot of the workspace, the existing snapshot
+	// is returned, and the builds of that snapshot can be reused
+	entry.TestedCommitSHA = &speculativeSnapshot.CommitSHA
+	if err := s.setStatus(ctx, entry, mergequeue.StatusTesting, nil); err != nil {
+		return false, err
+	}
+
+	if _, err := s.ciService.TriggerSnapshot(ctx, ws, speculativeSnapshot); err != nil {
+		// the entry will time out if no statuses are reported
+		s.logger.Error("failed to trigger build", zap.Error(err), zap.Stringer("entry_id", entry.ID))
+	}
+
+	return false, nil
+}
+
+// cancelBuilds cancels the builds of the speculative commit of the entry that are still running. If the speculative
+// commit is the same as the snapshot of the workspace, the builds are shared with the workspace, and are not cancelled.
+func (s *Service) cancelBuilds(ctx context.Context, entry *mergequeue.Entry) {
+	if entry.TestedCommitSHA == nil {
+		return
+	}
+	snapshot, err := s.snapshotter.GetByID(ctx, entry.SnapshotID)
+	if err != nil {
+		s.logger.Error("failed to get snapshot", zap.Error(err), zap.Stringer("entry_id", entry.ID))
+		return
+	}
+	if snapshot.CommitSHA == *entry.TestedCommitSHA {
+		return
+	}
+	if err := s.ciService.CancelBuilds(ctx, entry.CodebaseID, *entry.TestedCommitSHA); err != nil {
+		s.logger.Error("failed to cancel builds", zap.Error(err), zap.Stringer("entry_id", entry.ID))
+		// do not fail
+	}
+}
+
+func (s *Service) fail(ctx context.Context, entry *mergequeue.Entry, reason string) error {
+	return s.setStatus(ctx, entry, mergequeue.StatusFailed, &reason)
+}
+
+func (s *Service) setStatus(ctx context.Context, entry *mergequeue.Entry, status mergequeue.Status, failureReason *string) error {
+	entry.Status = status
+	entry.FailureReason = failureReason
+	entry.UpdatedAt = time.Now()
+	if err := s.repo.Update(ctx, entry); err != nil {
+		return fmt.Errorf("failed to update entry: %w", err)
+	}
+	if !status.IsActive() {
+		s.deleteBranch(ctx, entry)
+	}
+	if status == mergequeue.StatusFailed || status == mergequeue.StatusDequeued {
+		s.cancelBuilds(ctx, entry)
+	}
+	if err := s.eventsPublisher.MergeQueueEntryUpdated(ctx, eventsv2.Codebase(entry.CodebaseID), entry); err != nil {
+		s.logger.Error("failed to send merge queue event", zap.Error(err))
+		// do not fail
+	}
+	return nil
+}
+
+// deleteBranch removes the speculative branch of an entry that has left the queue.
+func (s *Service) deleteBranch(ctx context.Context, entry *mergequeue.Entry) {
+	if err := s.executorProvider.New().GitWrite(func(repo vcs.RepoGitWriter) error {
+		return repo.DeleteBranch(entry.BranchName())
+	}).ExecTrunkContext(ctx, entry.CodebaseID, "mergeQueueDeleteBranch"); err != nil {
+		s.logger.Error("failed to delete speculative branch", zap.Error(err), zap.Stringer("entry_id", entry.ID))
+		// do not fail
+	}
+}
@@ -0,0 +1,100 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	service_mergequeue "getsturdy.com/api/pkg/mergequeue/service"
+	"getsturdy.com/api/pkg/queue"
+	"getsturdy.com/api/pkg/queue/names"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// checkEvery is how often all merge queues are processed, to pick up statuses that have been reported for the
+	// speculative commits.
+	checkEvery = 30 * time.Second
+)
+
+// Queue is a background queue that processes the merge queues of codebases.
+type Queue struct {
+	logger *zap.Logger
+
+	queue queue.Queue
+	name  names.IncompleteQueueName
+
+	mergeQueueService *service_mergequeue.Service
+}
+
+func New(logger *zap.Logger, queue queue.Queue, mergeQueueService *service_mergequeue.Service) *Queue {
+	return &Queue{
+		logger:            logger.Named("mergeQueue"),
+		queue:             queue,
+		name:              names.MergeQueue,
+		mergeQueueService: mergeQueueService,
+	}
+}
+
+// Start starts the worker.
+func (q *Queue) Start(ctx context.Context) error {
+	messages := make(chan queue.Message)
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				q.logger.Error("panic in merge queue", zap.String("panic", fmt.Sprintf("%v", rec)))
+			}
+		}()
+		for msg := range messages {
+			m := &service_mergequeue.Message{}
+			if err := msg.As(m); err != nil {
+				q.logger.Error("failed to decode message", zap.Error(err), zap.Any("message", msg))
+				continue
+			}
+
+			if err := q.mergeQueueService.Process(ctx, m.CodebaseID); err != nil {
+				q.logger.Error("failed to process merge queue", zap.Error(err), zap.Stringer("codebase_id", m.CodebaseID))
+				continue
+			}
+
+			if err := msg.Ack(); err != nil {
+				q.logger.Error("failed to ack message", zap.Error(err), zap.Any("message", msg))
+				continue
+			}
+		}
+	}()
+
+	go q.tick(ctx)
+
+	q.logger.Info("starting queue", zap.Stringer("queue_name", q.name))
+	if err := q.queue.Subscribe(ctx, q.name, messages); err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+	q.logger.Info("queue stoped", zap.Stringer("queue_name", q.name))
+
+	return nil
+}
+
+// tick periodically schedules all codebases that have workspaces in the merge queue to be processed.
+func (q *Queue) tick(ctx context.Context) {
+	ticker := time.NewTicker(checkEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			codebaseIDs, err := q.mergeQueueService.ListActiveCodebaseIDs(ctx)
+			if err != nil {
+				q.logger.Error("failed to list codebases", zap.Error(err))
+				continue
+			}
+			for _, codebaseID := range codebaseIDs {
+				if err := q.mergeQueueService.Notify(ctx, codebaseID); err != nil {
+					q.logger.Error("failed to notify", zap.Error(err), zap.Stringer("codebase_id", codebaseID))
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
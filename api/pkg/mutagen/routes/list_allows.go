@@ -61,6 +61,8 @@ func ListAllows(
 			Type: auth.SubjectMutagen,
 		})
 
+		// all files that the user can read are synced to the view, changes to files that the user can not write to
+		// are refused when they are landed
 		allower, err := authService.GetAllower(ctx, &codebases.Codebase{ID: viewObj.CodebaseID})
 		if err != nil {
 			ctxlog.ErrorOrWarn(logger, "failed to list allowed pattern", err)
@@ -68,6 +68,7 @@ func TestListAllows(t *testing.T) {
 
 	cases := []struct {
 		name      string
+		action    string
 		resources string
 		expected  []string
 	}{
@@ -91,6 +92,12 @@ func TestListAllows(t *testing.T) {
 			resources: `"files::*", "files::!README.md"`,
 			expected:  []string{"*", "!README.md", "!.git", "!.git/**/*"},
 		},
+		{
+			name:      "read only",
+			action:    "read",
+			resources: `"files::pkg", "files::pkg/**/*"`,
+			expected:  []string{"pkg", "pkg/**/*", "!.git", "!.git/**/*"},
+		},
 	}
 
 	for _, tc := range cases {
@@ -115,10 +122,16 @@ func TestListAllows(t *testing.T) {
 
 			aclID := uuid.NewString()
 
+			action := tc.action
+			if action == "" {
+				action = "write"
+			}
+
 			rawPolicy := strings.NewReplacer(
 				"__USER_ID__", userID.String(),
 				"__CODEBASE_ID__", codebaseID.String(),
 				"__RESOURCES__", tc.resources,
+				"__ACTION__", action,
 			).Replace(`{
   "groups": [
     {
@@ -144,7 +157,7 @@ func TestListAllows(t *testing.T) {
     {
       "id": "user can access some files",
       "principals": ["users::__USER_ID__"],
-      "action": "write",
+      "action": "__ACTION__",
       "resources": [ __RESOURCES__ ],
     }
   ],
@@ -20,6 +20,9 @@ type ValidateViewRequest struct {
 	CodebaseID      codebases.ID `json:"codebase_id" binding:"required"`
 	UserID          users.ID     `json:"user_id" binding:"required"`
 	IsNewConnection bool         `json:"is_new_connection"`
+
+	// DryRun only validates the view, without marking it as used.
+	DryRun bool `json:"dry_run"`
 }
 
 func ValidateView(logger *zap.Logger, viewRepo db_view.Repository, analyticsService *service_analytics.Service, eventsSender *eventsv2.Publisher) func(c *gin.Context) {
@@ -54,6 +57,11 @@ func ValidateView(logger *zap.Logger, viewRepo db_view.Repository, analyticsServ
 			return
 		}
 
+		if req.DryRun {
+			c.JSON(http.StatusOK, gin.H{"status": "ok"})
+			return
+		}
+
 		// Set LastUsedAt
 		t := time.Now()
 		viewObj.LastUsedAt = &t
@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/users"
+	"getsturdy.com/api/pkg/views"
+	db_views "getsturdy.com/api/pkg/views/db"
+)
+
+func TestValidateView_dryRun(t *testing.T) {
+	viewRepo := db_views.NewInMemoryViewRepo()
+
+	userID := users.ID(uuid.NewString())
+	codebaseID := codebases.ID(uuid.NewString())
+	vw := views.View{
+		ID:         uuid.NewString(),
+		UserID:     userID,
+		CodebaseID: codebaseID,
+	}
+	assert.NoError(t, viewRepo.Create(vw))
+
+	// without an events sender or analytics, the route fails if it does more than validating the view
+	route := ValidateView(zap.NewNop(), viewRepo, nil, nil)
+
+	var res struct{}
+	request(t, userID, route, ValidateViewRequest{
+		ViewID:     vw.ID,
+		CodebaseID: codebaseID,
+		UserID:     userID,
+		DryRun:     true,
+	}, &res)
+
+	updated, err := viewRepo.Get(vw.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, updated.LastUsedAt, "the view must not be marked as used")
+}
@@ -1,7 +1,7 @@
 package routes
 
 import (
-	"crypto/subtle"
+	"errors"
 	"log"
 	"net/http"
 	"time"
@@ -12,6 +12,7 @@ import (
 	"getsturdy.com/api/pkg/auth"
 	"getsturdy.com/api/pkg/pki"
 	"getsturdy.com/api/pkg/pki/db"
+	service_pki "getsturdy.com/api/pkg/pki/service"
 	"getsturdy.com/api/pkg/users"
 )
 
@@ -64,7 +65,7 @@ type VerifyPublicKeyRequest struct {
 	UserID    users.ID `json:"user_id" binding:"required"`
 }
 
-func Verify(repo db.Repository) func(c *gin.Context) {
+func Verify(pkiService *service_pki.Service) func(c *gin.Context) {
 	return func(c *gin.Context) {
 		var req VerifyPublicKeyRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
@@ -73,50 +74,22 @@ func Verify(repo db.Repository) func(c *gin.Context) {
 			return
 		}
 
-		incomingKey, err := ssh.ParsePublicKey(req.PublicKey)
-		if err != nil {
+		if _, err := ssh.ParsePublicKey(req.PublicKey); err != nil {
 			log.Println(err)
 			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
 			return
 		}
 
-		keys, err := repo.GetKeyByUserID(req.UserID)
-		if err != nil {
+		if err := pkiService.Verify(c.Request.Context(), req.UserID, req.PublicKey); errors.Is(err, service_pki.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"status": "not found"})
+			return
+		} else if err != nil {
 			log.Println(err)
 			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "not found"})
 			return
 		}
 
-		for _, key := range keys {
-			if key.RevokedAt != nil {
-				continue
-			}
-
-			parsedAuthorizedKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key.PublicKey))
-			if err != nil {
-				log.Println(err)
-				continue
-			}
-
-			// This user has this key!
-			if KeysEqual(incomingKey, parsedAuthorizedKey) {
-				c.JSON(http.StatusOK, gin.H{"status": "ok"})
-				return
-			}
-		}
-
-		c.JSON(http.StatusNotFound, gin.H{"status": "not found"})
-	}
-}
-
-// KeysEqual is constant time compare of the keys to avoid timing attacks.
-func KeysEqual(ak, bk ssh.PublicKey) bool {
-	// avoid panic if one of the keys is nil, return false instead
-	if ak == nil || bk == nil {
-		return false
+		// This user has this key!
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	}
-
-	a := ak.Marshal()
-	b := bk.Marshal()
-	return (len(a) == len(b) && subtle.ConstantTimeCompare(a, b) == 1)
 }
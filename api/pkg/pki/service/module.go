@@ -0,0 +1,13 @@
+package service
+
+import (
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/logger"
+	db_pki "getsturdy.com/api/pkg/pki/db"
+)
+
+func Module(c *di.Container) {
+	c.Import(logger.Module)
+	c.Import(db_pki.Module)
+	c.Register(New)
+}
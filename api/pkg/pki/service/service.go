@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+
+	db_pki "getsturdy.com/api/pkg/pki/db"
+	"getsturdy.com/api/pkg/users"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+)
+
+var ErrNotFound = errors.New("public key not found")
+
+type Service struct {
+	logger *zap.Logger
+	repo   db_pki.Repository
+}
+
+func New(logger *zap.Logger, repo db_pki.Repository) *Service {
+	return &Service{
+		logger: logger.Named("pki"),
+		repo:   repo,
+	}
+}
+
+// Verify returns nil if the public key (in wire format) is one of the user's non-revoked keys, and ErrNotFound if it
+// is not.
+func (s *Service) Verify(ctx context.Context, userID users.ID, publicKey []byte) error {
+	incomingKey, err := ssh.ParsePublicKey(publicKey)
+	if err != nil {
+		return fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	keys, err := s.repo.GetKeyByUserID(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get keys: %w", err)
+	}
+
+	for _, key := range keys {
+		if key.RevokedAt != nil {
+			continue
+		}
+
+		parsedAuthorizedKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key.PublicKey))
+		if err != nil {
+			s.logger.Error("failed to parse authorized key", zap.Stringer("user_id", userID), zap.Error(err))
+			continue
+		}
+
+		// This user has this key!
+		if KeysEqual(incomingKey, parsedAuthorizedKey) {
+			return nil
+		}
+	}
+
+	return ErrNotFound
+}
+
+// KeysEqual is constant time compare of the keys to avoid timing attacks.
+func KeysEqual(ak, bk ssh.PublicKey) bool {
+	// avoid panic if one of the keys is nil, return false instead
+	if ak == nil || bk == nil {
+		return false
+	}
+
+	a := ak.Marshal()
+	b := bk.Marshal()
+	return (len(a) == len(b) && subtle.ConstantTimeCompare(a, b) == 1)
+}
@@ -0,0 +1,40 @@
+package pushedbranches
+
+import (
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/users"
+)
+
+type ID string
+
+func (id ID) String() string {
+	return string(id)
+}
+
+// PushedBranch is a git branch that a user has pushed to a codebase. Every branch is backed by a workspace, that is
+// updated with the contents of the branch on every push.
+type PushedBranch struct {
+	ID         ID           `db:"id"`
+	CodebaseID codebases.ID `db:"codebase_id"`
+	UserID     users.ID     `db:"user_id"`
+	// Name is the name of the branch, without the refs/heads/ prefix.
+	Name        string `db:"name"`
+	WorkspaceID string `db:"workspace_id"`
+	// CommitSHA is the head of the branch as it was last pushed.
+	CommitSHA string    `db:"commit_sha"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// RefNamespace is the git namespace that the branches of the user are stored in. Each user has their own namespace, so
+// that branches with the same name that are pushed by different users do not collide.
+func RefNamespace(userID users.ID) string {
+	return "pushed-" + userID.String()
+}
+
+// Ref is the full name of the ref that the branch is stored as in the trunk repository.
+func Ref(userID users.ID, name string) string {
+	return "refs/namespaces/" + RefNamespace(userID) + "/refs/heads/" + name
+}
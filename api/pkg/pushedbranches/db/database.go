@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/pushedbranches"
+	"getsturdy.com/api/pkg/users"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var _ Repository = &database{}
+
+type database struct {
+	db *sqlx.DB
+}
+
+func NewDatabase(db *sqlx.DB) Repository {
+	return &database{
+		db: db,
+	}
+}
+
+func (d *database) Create(ctx context.Context, branch *pushedbranches.PushedBranch) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		INSERT INTO pushed_branches (
+			id, codebase_id, user_id, name, workspace_id, commit_sha, created_at, updated_at
+		) VALUES (
+			:id, :codebase_id, :user_id, :name, :workspace_id, :commit_sha, :created_at, :updated_at
+		)
+	`, branch); err != nil {
+		return fmt.Errorf("failed to insert: %w", err)
+	}
+	return nil
+}
+
+func (d *database) Update(ctx context.Context, branch *pushedbranches.PushedBranch) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		UPDATE pushed_branches
+		SET workspace_id = :workspace_id,
+			commit_sha = :commit_sha,
+			updated_at = :updated_at
+		WHERE id = :id
+	`, branch); err != nil {
+		return fmt.Errorf("failed to update: %w", err)
+	}
+	return nil
+}
+
+func (d *database) Get(ctx context.Context, codebaseID codebases.ID, userID users.ID, name string) (*pushedbranches.PushedBranch, error) {
+	branch := &pushedbranches.PushedBranch{}
+	if err := d.db.GetContext(ctx, branch, `
+		SELECT
+			id, codebase_id, user_id, name, workspace_id, commit_sha, created_at, updated_at
+		FROM
+			pushed_branches
+		WHERE
+			codebase_id = $1
+			AND user_id = $2
+			AND name = $3
+	`, codebaseID, userID, name); err != nil {
+		return nil, fmt.Errorf("failed to get: %w", err)
+	}
+	return branch, nil
+}
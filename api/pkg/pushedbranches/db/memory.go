@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/pushedbranches"
+	"getsturdy.com/api/pkg/users"
+)
+
+var _ Repository = &memory{}
+
+type memory struct {
+	mu   sync.RWMutex
+	byID map[pushedbranches.ID]*pushedbranches.PushedBranch
+}
+
+func NewMemory() Repository {
+	return &memory{
+		byID: map[pushedbranches.ID]*pushedbranches.PushedBranch{},
+	}
+}
+
+func (m *memory) Create(_ context.Context, branch *pushedbranches.PushedBranch) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *branch
+	m.byID[branch.ID] = &cp
+	return nil
+}
+
+func (m *memory) Update(_ context.Context, branch *pushedbranches.PushedBranch) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, found := m.byID[branch.ID]; !found {
+		return sql.ErrNoRows
+	}
+	cp := *branch
+	m.byID[branch.ID] = &cp
+	return nil
+}
+
+func (m *memory) Get(_ context.Context, codebaseID codebases.ID, userID users.ID, name string) (*pushedbranches.PushedBranch, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, branch := range m.byID {
+		if branch.CodebaseID == codebaseID && branch.UserID == userID && branch.Name == name {
+			cp := *branch
+			return &cp, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
@@ -0,0 +1,15 @@
+package db
+
+import (
+	"context"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/pushedbranches"
+	"getsturdy.com/api/pkg/users"
+)
+
+type Repository interface {
+	Create(context.Context, *pushedbranches.PushedBranch) error
+	Update(context.Context, *pushedbranches.PushedBranch) error
+	Get(ctx context.Context, codebaseID codebases.ID, userID users.ID, name string) (*pushedbranches.PushedBranch, error)
+}
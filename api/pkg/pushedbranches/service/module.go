@@ -0,0 +1,25 @@
+package service
+
+import (
+	service_codeowners "getsturdy.com/api/pkg/codeowners/service"
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/logger"
+	db_pushedbranches "getsturdy.com/api/pkg/pushedbranches/db"
+	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
+	service_users "getsturdy.com/api/pkg/users/service/module"
+	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
+	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
+	"getsturdy.com/api/vcs/executor"
+)
+
+func Module(c *di.Container) {
+	c.Import(logger.Module)
+	c.Import(db_pushedbranches.Module)
+	c.Import(service_workspaces.Module)
+	c.Import(db_workspaces.Module)
+	c.Import(service_snapshots.Module)
+	c.Import(service_users.Module)
+	c.Import(service_codeowners.Module)
+	c.Import(executor.Module)
+	c.Register(New)
+}
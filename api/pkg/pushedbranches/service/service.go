@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+	service_codeowners "getsturdy.com/api/pkg/codeowners/service"
+	"getsturdy.com/api/pkg/pushedbranches"
+	db_pushedbranches "getsturdy.com/api/pkg/pushedbranches/db"
+	"getsturdy.com/api/pkg/snapshots"
+	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
+	"getsturdy.com/api/pkg/trunks"
+	"getsturdy.com/api/pkg/users"
+	service_users "getsturdy.com/api/pkg/users/service"
+	"getsturdy.com/api/pkg/workspaces"
+	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
+	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
+	"getsturdy.com/api/vcs"
+	"getsturdy.com/api/vcs/executor"
+
+	"github.com/google/uuid"
+	git "github.com/libgit2/git2go/v33"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrWorkspaceHasView = errors.New("the draft is open in a view")
+	ErrUnrelatedHistory = errors.New("the branch does not share any history with the trunk")
+)
+
+type Service struct {
+	logger            *zap.Logger
+	repo              db_pushedbranches.Repository
+	workspaceService  *service_workspaces.Service
+	workspaceWriter   db_workspaces.WorkspaceWriter
+	snapshotService   *service_snapshots.Service
+	userService       service_users.Service
+	codeOwnersService *service_codeowners.Service
+	executorProvider  executor.Provider
+}
+
+func New(
+	logger *zap.Logger,
+	repo db_pushedbranches.Repository,
+	workspaceService *service_workspaces.Service,
+	workspaceWriter db_workspaces.WorkspaceWriter,
+	snapshotService *service_snapshots.Service,
+	userService service_users.Service,
+	codeOwnersService *service_codeowners.Service,
+	executorProvider executor.Provider,
+) *Service {
+	return &Service{
+		logger:            logger.Named("pushedBranches"),
+		repo:              repo,
+		workspaceService:  workspaceService,
+		workspaceWriter:   workspaceWriter,
+		snapshotService:   snapshotService,
+		userService:       userService,
+		codeOwnersService: codeOwnersService,
+		executorProvider:  executorProvider,
+	}
+}
+
+// workspace returns the workspace that the branch is pushed to, or nil if a new workspace will be created.
+func (s *Service) workspace(ctx context.Context, branch *pushedbranches.PushedBranch) (*workspaces.Workspace, error) {
+	ws, err := s.workspaceService.GetByID(ctx, branch.WorkspaceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get workspace: %w", err)
+	}
+	if ws.IsArchived() {
+		// the draft has been landed or archived, the next push creates a new one
+		return nil, nil
+	}
+	return ws, nil
+}
+
+// Validate returns an error if the branch can not be pushed by the user.
+func (s *Service) Validate(ctx context.Context, codebaseID codebases.ID, userID users.ID, name string) error {
+	branch, err := s.repo.Get(ctx, codebaseID, userID, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get branch: %w", err)
+	}
+
+	ws, err := s.workspace(ctx, branch)
+	if err != nil {
+		return err
+	}
+
+	if ws != nil && ws.ViewID != nil {
+		return ErrWorkspaceHasView
+	}
+
+	return nil
+}
+
+// Push updates the workspace of the branch with the pushed commit, and creates a new workspace if the branch has not
+// been pushed before. All commits on the branch since it diverged from the trunk are squashed into a single snapshot.
+func (s *Service) Push(ctx context.Context, codebaseID codebases.ID, userID users.ID, name, commitSHA string) (*workspaces.Workspace, error) {
+	if err := s.Validate(ctx, codebaseID, userID, name); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userService.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	branch, err := s.repo.Get(ctx, codebaseID, userID, name)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		branch = nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to get branch: %w", err)
+	}
+
+	var ws *workspaces.Workspace
+	if branch != nil {
+		if ws, err = s.workspace(ctx, branch); err != nil {
+			return nil, err
+		}
+	}
+
+	if ws == nil {
+		if ws, err = s.workspaceService.Create(ctx, service_workspaces.CreateWorkspaceRequest{
+			UserID:     userID,
+			CodebaseID: codebaseID,
+			Name:       name,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to create workspace: %w", err)
+		}
+	}
+
+	squash := func(repo vcs.RepoReaderGitWriter) error {
+		trunkCommitSHA, err := repo.BranchCommitID(trunks.BranchName(ws.TrunkID))
+		if err != nil {
+			return fmt.Errorf("failed to get trunk head: %w", err)
+		}
+
+		baseCommitSHA, err := repo.CommonAncestor(trunkCommitSHA, commitSHA)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrUnrelatedHistory, err)
+		}
+
+		// The workspace is based on the commit where the branch diverged from the trunk, so that the snapshot
+		// contains the changes made on the branch only.
+		if err := repo.CreateNewBranchAt(ws.ID, baseCommitSHA); err != nil {
+			return fmt.Errorf("failed to move workspace branch: %w", err)
+		}
+
+		squashBranchName := "pushed-" + uuid.NewString()
+		squashedCommitSHA, err := repo.SquashCommits(squashBranchName, commitSHA, baseCommitSHA, git.Signature{
+			Name:  user.Name,
+			Email: user.Email,
+			When:  time.Now(),
+		}, fmt.Sprintf("Pushed %s", name))
+		if err != nil {
+			return fmt.Errorf("failed to squash commits: %w", err)
+		}
+
+		if _, err := s.snapshotService.Snapshot(
+			ctx,
+			codebaseID,
+			ws.ID,
+			snapshots.ActionGitPush,
+			service_snapshots.WithOnTemporaryView(),
+			service_snapshots.WithMarkAsLatestInWorkspace(),
+			service_snapshots.WithOnExistingCommit(squashedCommitSHA),
+			service_snapshots.WithOnRepo(repo), // Re-use repo context
+			service_snapshots.WithUser(user),
+		); err != nil {
+			return fmt.Errorf("failed to create snapshot: %w", err)
+		}
+
+		if err := repo.DeleteBranch(squashBranchName); err != nil {
+			return fmt.Errorf("failed to delete squash branch: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := s.executorProvider.New().FileReadGitWrite(squash).ExecTrunkContext(ctx, codebaseID, "pushedBranchPush"); err != nil {
+		return nil, fmt.Errorf("failed to snapshot pushed branch: %w", err)
+	}
+
+	// the base of the workspace has moved
+	if err := s.workspaceWriter.UpdateFields(ctx, ws.ID,
+		db_workspaces.SetHeadChangeComputed(false),
+		db_workspaces.SetUpToDateWithTrunk(nil),
+	); err != nil {
+		return nil, fmt.Errorf("failed to update workspace: %w", err)
+	}
+
+	if err := s.codeOwnersService.Notify(ctx, ws.ID); err != nil {
+		s.logger.Error("failed to notify code owners", zap.Error(err))
+		// don't fail
+	}
+
+	now := time.Now()
+	if branch == nil {
+		branch = &pushedbranches.PushedBranch{
+			ID:          pushedbranches.ID(uuid.NewString()),
+			CodebaseID:  codebaseID,
+			UserID:      userID,
+			Name:        name,
+			WorkspaceID: ws.ID,
+			CommitSHA:   commitSHA,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if err := s.repo.Create(ctx, branch); err != nil {
+			return nil, fmt.Errorf("failed to create branch: %w", err)
+		}
+	} else {
+		branch.WorkspaceID = ws.ID
+		branch.CommitSHA = commitSHA
+		branch.UpdatedAt = now
+		if err := s.repo.Update(ctx, branch); err != nil {
+			return nil, fmt.Errorf("failed to update branch: %w", err)
+		}
+	}
+
+	s.logger.Info("pushed branch",
+		zap.Stringer("codebase_id", codebaseID),
+		zap.Stringer("user_id", userID),
+		zap.String("workspace_id", ws.ID),
+		zap.String("commit_sha", commitSHA),
+	)
+
+	return ws, nil
+}
@@ -0,0 +1,13 @@
+package configuration
+
+import "time"
+
+type Configuration struct {
+	Type     string `long:"type" description:"Type of queue to use" default:"inmemory" choice:"inmemory" choice:"postgres"`
+	Prefix   string `long:"prefix" description:"Prefix for queue names" default:"sturdy"`
+	Hostname string `long:"hostname" description:"Name of this instance, used to name its queues. Defaults to the hostname"`
+
+	VisibilityTimeout time.Duration `long:"visibility-timeout" description:"Time before a received message that has not been acknowledged is delivered again" default:"5m"`
+	MaxReceiveCount   int           `long:"max-receive-count" description:"Number of times a message is received before it is moved to the dead letter queue" default:"5"`
+	PollInterval      time.Duration `long:"poll-interval" description:"Time to wait before looking for new messages, when the queue is empty" default:"1s"`
+}
@@ -302,7 +302,7 @@ func getOrCreateQueue(logger *zap.Logger, q *sqs.SQS, stsClient *sts.STS, queueN
 
 		// Create the Dead Letter Queue
 		createdDeadLetterQueue, createQueueErr := q.CreateQueue(&sqs.CreateQueueInput{
-			QueueName: aws.String(queueName + "_dead"),
+			QueueName: aws.String(names.BuildDeadLetterQueueName(queueName)),
 			Attributes: map[string]*string{
 				"KmsMasterKeyId": aws.String("alias/sns_and_sqs"),
 				"Policy":         aws.String(string(policyJson)),
@@ -4,12 +4,28 @@
 package module
 
 import (
+	"getsturdy.com/api/pkg/db"
 	"getsturdy.com/api/pkg/di"
 	"getsturdy.com/api/pkg/logger"
 	"getsturdy.com/api/pkg/queue"
+	"getsturdy.com/api/pkg/queue/configuration"
+	"getsturdy.com/api/pkg/queue/postgres"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
 )
 
 func Module(c *di.Container) {
 	c.Import(logger.Module)
-	c.Register(queue.NewInMemory, new(queue.Queue))
+	c.Import(db.Module)
+	c.Register(New)
+}
+
+func New(logger *zap.Logger, db *sqlx.DB, cfg *configuration.Configuration) (queue.Queue, error) {
+	switch cfg.Type {
+	case "postgres":
+		return postgres.New(logger, db, cfg)
+	default:
+		return queue.NewInMemory(logger), nil
+	}
 }
@@ -29,6 +29,10 @@ const (
 	GithubWebhooks                    IncompleteQueueName = "github_webhooks"
 	ViewSnapshot                      IncompleteQueueName = "view_snapshot"
 	CITriggerQueue                    IncompleteQueueName = "ci_trigger"
+	MergeQueue                        IncompleteQueueName = "merge_queue"
+	CodeOwners                        IncompleteQueueName = "codeowners_review"
+	Webhooks                          IncompleteQueueName = "webhooks_delivery"
+	SyncStackedWorkspaces             IncompleteQueueName = "workspace_syncStacked"
 	longestAllowedName                IncompleteQueueName = "xxxxxXXXXXxxxxxXXXXXxxxx" // To highlight how long a name can be
 )
 
@@ -40,6 +44,11 @@ func BuildQueueName(prefix, hostname string, name IncompleteQueueName) QueueName
 	return QueueName(safeQueueName(prefix) + "_" + string(name) + "_" + safeQueueName(hostname))
 }
 
+// BuildDeadLetterQueueName returns the name of the queue that messages that can't be processed are moved to.
+func BuildDeadLetterQueueName(queueName string) string {
+	return queueName + "_dead"
+}
+
 // BuildBroadcastQueuePublisherName builds names to be used by SNS topics
 func BuildBroadcastQueuePublisherName(prefix string, name IncompleteQueueName) BroadcastQueuePublisherName {
 	return BroadcastQueuePublisherName(safeQueueName(prefix) + "_" + string(name))
@@ -0,0 +1,253 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"getsturdy.com/api/pkg/queue"
+	"getsturdy.com/api/pkg/queue/configuration"
+	"getsturdy.com/api/pkg/queue/names"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+var (
+	_ queue.Queue   = &Queue{}
+	_ queue.Message = &message{}
+)
+
+// ErrNotReceived is returned by Ack if the message has been received by another subscriber since it was received,
+// because the visibility timeout expired.
+var ErrNotReceived = errors.New("message is not received")
+
+// Queue is a durable queue that stores messages in Postgres.
+//
+// Like the SQS queue, the queues are named after the hostname of the instance, and a message is received by exactly
+// one of the subscribers to the queue, across all instances that have the same hostname and use the same database.
+//
+// A message that is not acknowledged within the visibility timeout after it has been handed to a subscriber is
+// delivered again. A message that has been received more than MaxReceiveCount times is moved to a dead letter queue
+// with the "_dead" suffix, where it is kept for inspection, but not delivered.
+type Queue struct {
+	logger *zap.Logger
+	db     *sqlx.DB
+
+	prefix   string
+	hostname string
+
+	visibilityTimeout time.Duration
+	maxReceiveCount   int
+	pollInterval      time.Duration
+}
+
+func New(logger *zap.Logger, db *sqlx.DB, cfg *configuration.Configuration) (*Queue, error) {
+	hostname := cfg.Hostname
+	if hostname == "" {
+		defaultHostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get hostname: %w", err)
+		}
+		hostname = defaultHostname
+	}
+	return &Queue{
+		logger: logger.Named("postgresQueue"),
+		db:     db,
+
+		prefix:   cfg.Prefix,
+		hostname: hostname,
+
+		visibilityTimeout: cfg.VisibilityTimeout,
+		maxReceiveCount:   cfg.MaxReceiveCount,
+		pollInterval:      cfg.PollInterval,
+	}, nil
+}
+
+func (q *Queue) Publish(ctx context.Context, name names.IncompleteQueueName, v any) error {
+	q.logger.Info("publishing message", zap.String("queue", string(name)))
+
+	body, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	if _, err := q.db.ExecContext(ctx, `
+		INSERT INTO queue_messages (queue, body, receive_count, visible_at, created_at)
+		VALUES ($1, $2, 0, NOW(), NOW())
+	`, names.BuildQueueName(q.prefix, q.hostname, name), body); err != nil {
+		return fmt.Errorf("failed to insert message: %w", err)
+	}
+	return nil
+}
+
+func (q *Queue) Subscribe(ctx context.Context, name names.IncompleteQueueName, messages chan<- queue.Message) error {
+	q.logger.Info("new subscription", zap.String("queue", string(name)))
+
+	queueName := string(names.BuildQueueName(q.prefix, q.hostname, name))
+	for {
+		msg, err := q.receive(ctx, queueName)
+		if err != nil && ctx.Err() == nil {
+			q.logger.Error("failed to receive message", zap.String("queue", queueName), zap.Error(err))
+		}
+
+		if msg != nil {
+			if !q.deliver(ctx, msg, messages) {
+				q.logger.Info("stopping subscription", zap.String("queue", queueName))
+				return nil
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			q.logger.Info("stopping subscription", zap.String("queue", queueName))
+			return nil
+		case <-time.After(q.pollInterval):
+		}
+	}
+}
+
+// deliver hands the message to a subscriber. While it waits for the subscriber, the visibility timeout of the message
+// is extended, so that it's not received again by another instance. Once delivered, the subscriber has the full
+// visibility timeout to acknowledge the message. Returns false if the context is cancelled before the message is
+// delivered.
+func (q *Queue) deliver(ctx context.Context, msg *message, messages chan<- queue.Message) bool {
+	ticker := time.NewTicker(q.visibilityTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case messages <- msg:
+			if err := msg.extend(ctx, q.visibilityTimeout); err != nil {
+				q.logger.Error("failed to extend visibility timeout", zap.String("queue", msg.queue), zap.Error(err))
+			}
+			return true
+		case <-ticker.C:
+			if err := msg.extend(ctx, q.visibilityTimeout); err != nil {
+				q.logger.Error("failed to extend visibility timeout", zap.String("queue", msg.queue), zap.Error(err))
+			}
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+type row struct {
+	ID           int64  `db:"id"`
+	Body         []byte `db:"body"`
+	ReceiveCount int    `db:"receive_count"`
+}
+
+// receive receives the next visible message from the queue, and hides it from other subscribers until the visibility
+// timeout expires. Messages that have been received too many times are moved to the dead letter queue. Returns nil if
+// there are no visible messages.
+func (q *Queue) receive(ctx context.Context, queueName string) (*message, error) {
+	tx, err := q.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var rows []*row
+	if err := tx.SelectContext(ctx, &rows, `
+		SELECT id, body, receive_count
+		FROM queue_messages
+		WHERE queue = $1
+			AND visible_at <= NOW()
+		ORDER BY id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`, queueName); err != nil {
+		return nil, fmt.Errorf("failed to select messages: %w", err)
+	}
+
+	var res *message
+	for _, r := range rows {
+		if r.ReceiveCount >= q.maxReceiveCount {
+			if _, err := tx.ExecContext(ctx, `
+				UPDATE queue_messages SET queue = $2, visible_at = NOW() WHERE id = $1
+			`, r.ID, names.BuildDeadLetterQueueName(queueName)); err != nil {
+				return nil, fmt.Errorf("failed to move message to dead letter queue: %w", err)
+			}
+			q.logger.Warn("moved message to dead letter queue",
+				zap.String("queue", queueName),
+				zap.Int64("id", r.ID),
+				zap.Int("receive_count", r.ReceiveCount),
+			)
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE queue_messages
+			SET receive_count = receive_count + 1,
+				visible_at = NOW() + MAKE_INTERVAL(secs => $2)
+			WHERE id = $1
+		`, r.ID, q.visibilityTimeout.Seconds()); err != nil {
+			return nil, fmt.Errorf("failed to update message: %w", err)
+		}
+
+		res = &message{
+			db:           q.db,
+			queue:        queueName,
+			id:           r.ID,
+			receiveCount: r.ReceiveCount + 1,
+			body:         r.Body,
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return res, nil
+}
+
+type message struct {
+	db    *sqlx.DB
+	queue string
+
+	id int64
+	// receiveCount identifies this receive of the message. If the message is received again, the count is increased,
+	// and this receive can no longer ack it.
+	receiveCount int
+	body         []byte
+}
+
+func (m *message) As(v any) error {
+	if err := json.Unmarshal(m.body, v); err != nil {
+		return fmt.Errorf("failed to unmarshal message: %w", err)
+	}
+	return nil
+}
+
+// extend hides the message from other subscribers until the visibility timeout expires, counting from now.
+func (m *message) extend(ctx context.Context, visibilityTimeout time.Duration) error {
+	if _, err := m.db.ExecContext(ctx, `
+		UPDATE queue_messages
+		SET visible_at = NOW() + MAKE_INTERVAL(secs => $4)
+		WHERE id = $1 AND queue = $2 AND receive_count = $3
+	`, m.id, m.queue, m.receiveCount, visibilityTimeout.Seconds()); err != nil {
+		return fmt.Errorf("failed to update message: %w", err)
+	}
+	return nil
+}
+
+func (m *message) Ack() error {
+	res, err := m.db.Exec(`
+		DELETE FROM queue_messages WHERE id = $1 AND queue = $2 AND receive_count = $3
+	`, m.id, m.queue, m.receiveCount)
+	if err != nil {
+		return fmt.Errorf("failed to delete message: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotReceived
+	}
+	return nil
+}
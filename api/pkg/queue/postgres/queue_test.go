@@ -0,0 +1,188 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"getsturdy.com/api/pkg/internal/dbtest"
+	"getsturdy.com/api/pkg/logger"
+	"getsturdy.com/api/pkg/queue"
+	"getsturdy.com/api/pkg/queue/configuration"
+	"getsturdy.com/api/pkg/queue/names"
+	"getsturdy.com/api/pkg/queue/postgres"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+type testMessage struct {
+	Value string `json:"value"`
+}
+
+func newQueue(t *testing.T, db *sqlx.DB, prefix, hostname string) *postgres.Queue {
+	q, err := postgres.New(logger.NewTest(t), db, &configuration.Configuration{
+		Prefix:            prefix,
+		Hostname:          hostname,
+		VisibilityTimeout: 200 * time.Millisecond,
+		MaxReceiveCount:   2,
+		PollInterval:      10 * time.Millisecond,
+	})
+	assert.NoError(t, err)
+	return q
+}
+
+func receive(t *testing.T, messages <-chan queue.Message) queue.Message {
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for message")
+		return nil
+	}
+}
+
+func assertEmpty(t *testing.T, messages <-chan queue.Message, wait time.Duration) {
+	select {
+	case <-messages:
+		t.Fatal("unexpected message")
+	case <-time.After(wait):
+	}
+}
+
+func TestPublishSubscribe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	db := dbtest.DB(t)
+	prefix := uuid.NewString()
+	q := newQueue(t, db, prefix, "a")
+	name := names.IncompleteQueueName("testing")
+
+	assert.NoError(t, q.Publish(ctx, name, &testMessage{Value: "first"}))
+	assert.NoError(t, q.Publish(ctx, name, &testMessage{Value: "second"}))
+
+	messages := make(chan queue.Message)
+	go func() {
+		assert.NoError(t, q.Subscribe(ctx, name, messages))
+	}()
+
+	for _, expected := range []string{"first", "second"} {
+		msg := receive(t, messages)
+		m := &testMessage{}
+		assert.NoError(t, msg.As(m))
+		assert.Equal(t, expected, m.Value)
+		assert.NoError(t, msg.Ack())
+	}
+
+	// acked messages are not redelivered
+	assertEmpty(t, messages, 500*time.Millisecond)
+}
+
+func TestCompetingConsumers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	db := dbtest.DB(t)
+	prefix := uuid.NewString()
+	name := names.IncompleteQueueName("testing")
+
+	// instances with the same hostname share the queue
+	messages := make(chan queue.Message)
+	for i := 0; i < 2; i++ {
+		q := newQueue(t, db, prefix, "a")
+		go func() {
+			assert.NoError(t, q.Subscribe(ctx, name, messages))
+		}()
+	}
+
+	// instances with other hostnames have their own queues
+	otherMessages := make(chan queue.Message)
+	other := newQueue(t, db, prefix, "b")
+	go func() {
+		assert.NoError(t, other.Subscribe(ctx, name, otherMessages))
+	}()
+
+	q := newQueue(t, db, prefix, "a")
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, q.Publish(ctx, name, &testMessage{Value: uuid.NewString()}))
+	}
+
+	received := map[string]bool{}
+	for i := 0; i < 5; i++ {
+		msg := receive(t, messages)
+		m := &testMessage{}
+		assert.NoError(t, msg.As(m))
+		assert.False(t, received[m.Value], "message received twice")
+		received[m.Value] = true
+		assert.NoError(t, msg.Ack())
+	}
+	assertEmpty(t, messages, 500*time.Millisecond)
+	assertEmpty(t, otherMessages, 0)
+}
+
+func TestSlowConsumer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	db := dbtest.DB(t)
+	prefix := uuid.NewString()
+	q := newQueue(t, db, prefix, "a")
+	name := names.IncompleteQueueName("testing")
+
+	assert.NoError(t, q.Publish(ctx, name, &testMessage{Value: "first"}))
+	assert.NoError(t, q.Publish(ctx, name, &testMessage{Value: "second"}))
+
+	messages := make(chan queue.Message)
+	go func() {
+		assert.NoError(t, q.Subscribe(ctx, name, messages))
+	}()
+
+	// while the first message is processed for longer than the visibility timeout, the second message is waiting
+	// to be delivered, and is not received again
+	first := receive(t, messages)
+	time.Sleep(time.Second)
+	assert.NoError(t, first.Ack())
+
+	second := receive(t, messages)
+	m := &testMessage{}
+	assert.NoError(t, second.As(m))
+	assert.Equal(t, "second", m.Value)
+	assert.NoError(t, second.Ack())
+
+	assertEmpty(t, messages, 500*time.Millisecond)
+}
+
+func TestRedeliveryAndDeadLetter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	db := dbtest.DB(t)
+	prefix := uuid.NewString()
+	q := newQueue(t, db, prefix, "a")
+	name := names.IncompleteQueueName("testing")
+
+	assert.NoError(t, q.Publish(ctx, name, &testMessage{Value: "unacked"}))
+
+	messages := make(chan queue.Message)
+	go func() {
+		assert.NoError(t, q.Subscribe(ctx, name, messages))
+	}()
+
+	// the message is delivered again when the visibility timeout expires
+	first := receive(t, messages)
+	second := receive(t, messages)
+
+	// the first receive can no longer ack the message
+	assert.ErrorIs(t, first.Ack(), postgres.ErrNotReceived)
+
+	// after MaxReceiveCount receives, the message is moved to the dead letter queue
+	assertEmpty(t, messages, time.Second)
+	assert.ErrorIs(t, second.Ack(), postgres.ErrNotReceived)
+
+	var count int
+	assert.NoError(t, db.Get(&count, `SELECT COUNT(*) FROM queue_messages WHERE queue = $1`,
+		names.BuildDeadLetterQueueName(string(names.BuildQueueName(prefix, "a", name)))))
+	assert.Equal(t, 1, count)
+}
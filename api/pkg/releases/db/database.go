@@ -0,0 +1,102 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"getsturdy.com/api/pkg/changes"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/releases"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var _ Repository = &database{}
+
+type database struct {
+	db *sqlx.DB
+}
+
+func NewDatabase(db *sqlx.DB) Repository {
+	return &database{
+		db: db,
+	}
+}
+
+func (d *database) Create(ctx context.Context, release *releases.Release) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		INSERT INTO releases (
+			id, codebase_id, change_id, tag, notes, created_by, created_at
+		) VALUES (
+			:id, :codebase_id, :change_id, :tag, :notes, :created_by, :created_at
+		)
+	`, release); err != nil {
+		return fmt.Errorf("failed to insert: %w", err)
+	}
+	return nil
+}
+
+func (d *database) Get(ctx context.Context, id releases.ID) (*releases.Release, error) {
+	release := &releases.Release{}
+	if err := d.db.GetContext(ctx, release, `
+		SELECT
+			id, codebase_id, change_id, tag, notes, created_by, created_at
+		FROM
+			releases
+		WHERE
+			id = $1
+	`, id); err != nil {
+		return nil, fmt.Errorf("failed to get: %w", err)
+	}
+	return release, nil
+}
+
+func (d *database) GetByTag(ctx context.Context, codebaseID codebases.ID, tag string) (*releases.Release, error) {
+	release := &releases.Release{}
+	if err := d.db.GetContext(ctx, release, `
+		SELECT
+			id, codebase_id, change_id, tag, notes, created_by, created_at
+		FROM
+			releases
+		WHERE
+			codebase_id = $1
+			AND tag = $2
+	`, codebaseID, tag); err != nil {
+		return nil, fmt.Errorf("failed to get: %w", err)
+	}
+	return release, nil
+}
+
+func (d *database) ListByCodebaseID(ctx context.Context, codebaseID codebases.ID) ([]*releases.Release, error) {
+	var rr []*releases.Release
+	if err := d.db.SelectContext(ctx, &rr, `
+		SELECT
+			id, codebase_id, change_id, tag, notes, created_by, created_at
+		FROM
+			releases
+		WHERE
+			codebase_id = $1
+		ORDER BY
+			created_at DESC
+	`, codebaseID); err != nil {
+		return nil, fmt.Errorf("failed to list: %w", err)
+	}
+	return rr, nil
+}
+
+func (d *database) ListByChangeID(ctx context.Context, changeID changes.ID) ([]*releases.Release, error) {
+	var rr []*releases.Release
+	if err := d.db.SelectContext(ctx, &rr, `
+		SELECT
+			id, codebase_id, change_id, tag, notes, created_by, created_at
+		FROM
+			releases
+		WHERE
+			change_id = $1
+		ORDER BY
+			created_at DESC
+	`, changeID); err != nil {
+		return nil, fmt.Errorf("failed to list: %w", err)
+	}
+	return rr, nil
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+
+	"getsturdy.com/api/pkg/changes"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/releases"
+)
+
+var _ Repository = &memory{}
+
+type memory struct {
+	mu   sync.RWMutex
+	byID map[releases.ID]*releases.Release
+}
+
+func NewMemory() Repository {
+	return &memory{
+		byID: map[releases.ID]*releases.Release{},
+	}
+}
+
+func (m *memory) Create(_ context.Context, release *releases.Release) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *release
+	m.byID[release.ID] = &cp
+	return nil
+}
+
+func (m *memory) Get(_ context.Context, id releases.ID) (*releases.Release, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	release, found := m.byID[id]
+	if !found {
+		return nil, sql.ErrNoRows
+	}
+	cp := *release
+	return &cp, nil
+}
+
+func (m *memory) GetByTag(_ context.Context, codebaseID codebases.ID, tag string) (*releases.Release, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, release := range m.byID {
+		if release.CodebaseID == codebaseID && release.Tag == tag {
+			cp := *release
+			return &cp, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (m *memory) ListByCodebaseID(_ context.Context, codebaseID codebases.ID) ([]*releases.Release, error) {
+	return m.list(func(release *releases.Release) bool {
+		return release.CodebaseID == codebaseID
+	}), nil
+}
+
+func (m *memory) ListByChangeID(_ context.Context, changeID changes.ID) ([]*releases.Release, error) {
+	return m.list(func(release *releases.Release) bool {
+		return release.ChangeID == changeID
+	}), nil
+}
+
+func (m *memory) list(filter func(*releases.Release) bool) []*releases.Release {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var rr []*releases.Release
+	for _, release := range m.byID {
+		if filter(release) {
+			cp := *release
+			rr = append(rr, &cp)
+		}
+	}
+	sort.Slice(rr, func(i, j int) bool {
+		return rr[i].CreatedAt.After(rr[j].CreatedAt)
+	})
+	return rr
+}
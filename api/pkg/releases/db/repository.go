@@ -0,0 +1,17 @@
+package db
+
+import (
+	"context"
+
+	"getsturdy.com/api/pkg/changes"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/releases"
+)
+
+type Repository interface {
+	Create(context.Context, *releases.Release) error
+	Get(context.Context, releases.ID) (*releases.Release, error)
+	GetByTag(ctx context.Context, codebaseID codebases.ID, tag string) (*releases.Release, error)
+	ListByCodebaseID(context.Context, codebases.ID) ([]*releases.Release, error)
+	ListByChangeID(context.Context, changes.ID) ([]*releases.Release, error)
+}
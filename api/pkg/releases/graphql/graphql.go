@@ -0,0 +1,152 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"getsturdy.com/api/pkg/auth"
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	"getsturdy.com/api/pkg/changes"
+	service_changes "getsturdy.com/api/pkg/changes/service"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/codebases/acl"
+	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
+	"getsturdy.com/api/pkg/graphql/resolvers"
+	"getsturdy.com/api/pkg/releases"
+	service_releases "getsturdy.com/api/pkg/releases/service"
+)
+
+type rootResolver struct {
+	authService     *service_auth.Service
+	changeService   *service_changes.Service
+	releasesService *service_releases.Service
+
+	changeRootResolver *resolvers.ChangeRootResolver
+	authorRootResolver resolvers.AuthorRootResolver
+}
+
+func New(
+	authService *service_auth.Service,
+	changeService *service_changes.Service,
+	releasesService *service_releases.Service,
+
+	changeRootResolver *resolvers.ChangeRootResolver,
+	authorRootResolver resolvers.AuthorRootResolver,
+) resolvers.ReleasesRootResolver {
+	return &rootResolver{
+		authService:     authService,
+		changeService:   changeService,
+		releasesService: releasesService,
+
+		changeRootResolver: changeRootResolver,
+		authorRootResolver: authorRootResolver,
+	}
+}
+
+func (r *rootResolver) InternalListByCodebaseID(ctx context.Context, codebaseID codebases.ID) ([]resolvers.ReleaseResolver, error) {
+	rr, err := r.releasesService.List(ctx, codebaseID)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+	res := make([]resolvers.ReleaseResolver, 0, len(rr))
+	for _, release := range rr {
+		res = append(res, &resolver{root: r, release: release})
+	}
+	return res, nil
+}
+
+func (r *rootResolver) ReleaseNotes(ctx context.Context, args resolvers.ReleaseNotesArgs) (string, error) {
+	ch, err := r.changeService.GetChangeByID(ctx, changes.ID(args.ChangeID))
+	if err != nil {
+		return "", gqlerrors.Error(err)
+	}
+
+	if err := r.authService.CanRead(ctx, ch); err != nil {
+		return "", gqlerrors.Error(err)
+	}
+
+	notes, err := r.releasesService.GenerateNotes(ctx, ch)
+	if err != nil {
+		return "", gqlerrors.Error(fmt.Errorf("failed to generate release notes: %w", err))
+	}
+	return notes, nil
+}
+
+// canRelease returns nil if the user is allowed to create releases in the codebase. Releasing is allowed to the
+// same users that can land changes.
+func (r *rootResolver) canRelease(ctx context.Context, codebaseID codebases.ID) error {
+	if err := r.authService.CanWrite(ctx, &codebases.Codebase{ID: codebaseID}); err != nil {
+		return err
+	}
+
+	return r.authService.CanPerform(ctx, codebaseID, acl.ActionLand, acl.Identity{Type: acl.Codebases, ID: codebaseID.String()})
+}
+
+func (r *rootResolver) CreateRelease(ctx context.Context, args resolvers.CreateReleaseArgs) (resolvers.ReleaseResolver, error) {
+	ch, err := r.changeService.GetChangeByID(ctx, changes.ID(args.Input.ChangeID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if err := r.canRelease(ctx, ch.CodebaseID); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	userID, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	release, err := r.releasesService.Create(ctx, service_releases.CreateRequest{
+		CodebaseID: ch.CodebaseID,
+		ChangeID:   ch.ID,
+		UserID:     userID,
+		Tag:        args.Input.Tag,
+		Notes:      args.Input.Notes,
+	})
+	switch {
+	case errors.Is(err, service_releases.ErrInvalidTag):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "tag", "Tag must be a semantic version, such as v1.2.3")
+	case errors.Is(err, service_releases.ErrTagTaken):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "tag", "A release with this tag already exists")
+	case errors.Is(err, service_releases.ErrChangeNotLanded):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "changeID", "The change has not been landed")
+	case err != nil:
+		return nil, gqlerrors.Error(fmt.Errorf("failed to create release: %w", err))
+	}
+
+	return &resolver{root: r, release: release}, nil
+}
+
+type resolver struct {
+	root    *rootResolver
+	release *releases.Release
+}
+
+func (r *resolver) ID() graphql.ID {
+	return graphql.ID(r.release.ID)
+}
+
+func (r *resolver) Tag() string {
+	return r.release.Tag
+}
+
+func (r *resolver) Notes() string {
+	return r.release.Notes
+}
+
+func (r *resolver) Change(ctx context.Context) (resolvers.ChangeResolver, error) {
+	id := graphql.ID(r.release.ChangeID)
+	return (*r.root.changeRootResolver).Change(ctx, resolvers.ChangeArgs{ID: &id})
+}
+
+func (r *resolver) Author(ctx context.Context) (resolvers.AuthorResolver, error) {
+	return r.root.authorRootResolver.Author(ctx, graphql.ID(r.release.CreatedBy))
+}
+
+func (r *resolver) CreatedAt() int32 {
+	return int32(r.release.CreatedAt.Unix())
+}
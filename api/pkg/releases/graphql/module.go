@@ -0,0 +1,19 @@
+package graphql
+
+import (
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	graphql_author "getsturdy.com/api/pkg/author/graphql"
+	service_changes "getsturdy.com/api/pkg/changes/service"
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/graphql/resolvers"
+	service_releases "getsturdy.com/api/pkg/releases/service"
+)
+
+func Module(c *di.Container) {
+	c.Import(service_auth.Module)
+	c.Import(service_changes.Module)
+	c.Import(service_releases.Module)
+	c.Import(resolvers.Module)
+	c.Import(graphql_author.Module)
+	c.Register(New)
+}
@@ -0,0 +1,50 @@
+package releases
+
+import (
+	"time"
+
+	"golang.org/x/mod/semver"
+
+	"getsturdy.com/api/pkg/changes"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/users"
+)
+
+type ID string
+
+func (id ID) String() string {
+	return string(id)
+}
+
+// Release is a semver tag on a change, with release notes. Every release has a matching annotated git tag in the
+// trunk repository of the codebase.
+type Release struct {
+	ID         ID           `db:"id"`
+	CodebaseID codebases.ID `db:"codebase_id"`
+	ChangeID   changes.ID   `db:"change_id"`
+	Tag        string       `db:"tag"`
+	Notes      string       `db:"notes"`
+
+	CreatedBy users.ID  `db:"created_by"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// ParseTag returns the canonical form of a semver tag, always prefixed with a "v" (1.2.3 becomes v1.2.3). Short
+// versions, such as v1.2, are not valid tags.
+func ParseTag(tag string) (string, bool) {
+	if len(tag) > 0 && tag[0] != 'v' {
+		tag = "v" + tag
+	}
+	if !semver.IsValid(tag) {
+		return "", false
+	}
+	if semver.Canonical(tag)+semver.Build(tag) != tag {
+		return "", false
+	}
+	return tag, true
+}
+
+// Compare returns an integer comparing the versions of two tags, according to semver precedence.
+func Compare(a, b string) int {
+	return semver.Compare(a, b)
+}
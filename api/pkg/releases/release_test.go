@@ -0,0 +1,37 @@
+package releases
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTag(t *testing.T) {
+	for _, tc := range []struct {
+		in       string
+		expected string
+		ok       bool
+	}{
+		{in: "v1.2.3", expected: "v1.2.3", ok: true},
+		{in: "1.2.3", expected: "v1.2.3", ok: true},
+		{in: "v1.2.3-rc.1", expected: "v1.2.3-rc.1", ok: true},
+		{in: "v1.2.3+build.5", expected: "v1.2.3+build.5", ok: true},
+		{in: "v1.2", ok: false},
+		{in: "v1", ok: false},
+		{in: "", ok: false},
+		{in: "release-1", ok: false},
+		{in: "v01.2.3", ok: false},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			tag, ok := ParseTag(tc.in)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, tag)
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	assert.Equal(t, -1, Compare("v1.2.3-rc.1", "v1.2.3"))
+	assert.Equal(t, 1, Compare("v1.10.0", "v1.9.0"))
+	assert.Equal(t, 0, Compare("v1.2.3", "v1.2.3+build.5"))
+}
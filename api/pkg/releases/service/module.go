@@ -0,0 +1,23 @@
+package service
+
+import (
+	service_analytics "getsturdy.com/api/pkg/analytics/service"
+	service_changes "getsturdy.com/api/pkg/changes/service"
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/logger"
+	db_releases "getsturdy.com/api/pkg/releases/db"
+	service_remote "getsturdy.com/api/pkg/remote/service/module"
+	service_users "getsturdy.com/api/pkg/users/service"
+	"getsturdy.com/api/vcs/executor"
+)
+
+func Module(c *di.Container) {
+	c.Import(db_releases.Module)
+	c.Import(logger.Module)
+	c.Import(service_changes.Module)
+	c.Import(service_users.Module)
+	c.Import(service_remote.Module)
+	c.Import(service_analytics.Module)
+	c.Import(executor.Module)
+	c.Register(New)
+}
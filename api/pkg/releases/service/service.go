@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"getsturdy.com/api/pkg/analytics"
+	service_analytics "getsturdy.com/api/pkg/analytics/service"
+	"getsturdy.com/api/pkg/changes"
+	"getsturdy.com/api/pkg/changes/message"
+	service_changes "getsturdy.com/api/pkg/changes/service"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/releases"
+	db_releases "getsturdy.com/api/pkg/releases/db"
+	service_remote "getsturdy.com/api/pkg/remote/service"
+	"getsturdy.com/api/pkg/users"
+	service_users "getsturdy.com/api/pkg/users/service"
+	"getsturdy.com/api/vcs"
+	"getsturdy.com/api/vcs/executor"
+
+	"github.com/google/uuid"
+	git "github.com/libgit2/git2go/v33"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidTag          = errors.New("invalid semver tag")
+	ErrTagTaken            = errors.New("a release with this tag already exists")
+	ErrChangeNotInCodebase = errors.New("change does not belong to this codebase")
+	ErrChangeNotLanded     = errors.New("change does not have a commit")
+)
+
+// maxNotesChanges is the maximum number of changes that are included in generated release notes.
+const maxNotesChanges = 500
+
+type Service struct {
+	repo   db_releases.Repository
+	logger *zap.Logger
+
+	changeService    *service_changes.Service
+	userService      service_users.Service
+	remoteService    service_remote.Service
+	analyticsService *service_analytics.Service
+	executorProvider executor.Provider
+}
+
+func New(
+	repo db_releases.Repository,
+	logger *zap.Logger,
+
+	changeService *service_changes.Service,
+	userService service_users.Service,
+	remoteService service_remote.Service,
+	analyticsService *service_analytics.Service,
+	executorProvider executor.Provider,
+) *Service {
+	return &Service{
+		repo:   repo,
+		logger: logger.Named("releasesService"),
+
+		changeService:    changeService,
+		userService:      userService,
+		remoteService:    remoteService,
+		analyticsService: analyticsService,
+		executorProvider: executorProvider,
+	}
+}
+
+type CreateRequest struct {
+	CodebaseID codebases.ID
+	ChangeID   changes.ID
+	UserID     users.ID
+	Tag        string
+
+	// Notes are the release notes. If nil, the notes are generated from the changes since the previous release (see
+	// GenerateNotes).
+	Notes *string
+}
+
+// Create creates a new release of the change, and tags the commit of the change in the trunk repository. If the
+// codebase has a remote, the tag is pushed to it.
+func (s *Service) Create(ctx context.Context, req CreateRequest) (*releases.Release, error) {
+	tag, ok := releases.ParseTag(strings.TrimSpace(req.Tag))
+	if !ok {
+		return nil, ErrInvalidTag
+	}
+
+	if _, err := s.repo.GetByTag(ctx, req.CodebaseID, tag); err == nil {
+		return nil, ErrTagTaken
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get release by tag: %w", err)
+	}
+
+	ch, err := s.changeService.GetChangeByID(ctx, req.ChangeID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get change by id: %w", err)
+	}
+	if ch.CodebaseID != req.CodebaseID {
+		return nil, ErrChangeNotInCodebase
+	}
+	if ch.CommitID == nil {
+		return nil, ErrChangeNotLanded
+	}
+
+	var notes string
+	if req.Notes != nil {
+		notes = strings.TrimSpace(*req.Notes)
+	} else {
+		if notes, err = s.GenerateNotes(ctx, ch); err != nil {
+			return nil, fmt.Errorf("failed to generate release notes: %w", err)
+		}
+	}
+
+	user, err := s.userService.GetByID(ctx, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	release := &releases.Release{
+		ID:         releases.ID(uuid.NewString()),
+		CodebaseID: req.CodebaseID,
+		ChangeID:   ch.ID,
+		Tag:        tag,
+		Notes:      notes,
+		CreatedBy:  req.UserID,
+		CreatedAt:  time.Now(),
+	}
+
+	tagger := git.Signature{
+		Name:  user.Name,
+		Email: user.Email,
+		When:  release.CreatedAt,
+	}
+
+	if err := s.executorProvider.New().GitWrite(func(repo vcs.RepoGitWriter) error {
+		return repo.CreateTag(tag, *ch.CommitID, tagger, tagMessage(tag, notes))
+	}).ExecTrunkContext(ctx, req.CodebaseID, "createReleaseTag"); err != nil {
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	if err := s.remoteService.PushTag(ctx, req.CodebaseID, tag); err != nil {
+		s.deleteTag(ctx, req.CodebaseID, tag)
+		return nil, fmt.Errorf("failed to push tag: %w", err)
+	}
+
+	if err := s.repo.Create(ctx, release); err != nil {
+		return nil, fmt.Errorf("failed to create release: %w", err)
+	}
+
+	s.analyticsService.CaptureUser(ctx, req.UserID, "created release",
+		analytics.CodebaseID(req.CodebaseID),
+		analytics.Property("release_id", release.ID),
+		analytics.Property("generated_notes", req.Notes == nil),
+	)
+
+	return release, nil
+}
+
+// deleteTag removes a tag that was created for a release that could not be completed.
+func (s *Service) deleteTag(ctx context.Context, codebaseID codebases.ID, tag string) {
+	if err := s.executorProvider.New().GitWrite(func(repo vcs.RepoGitWriter) error {
+		return repo.DeleteTag(tag)
+	}).ExecTrunkContext(ctx, codebaseID, "deleteReleaseTag"); err != nil {
+		s.logger.Error("failed to delete release tag", zap.Error(err), zap.String("tag", tag))
+		// do not fail
+	}
+}
+
+func (s *Service) Get(ctx context.Context, id releases.ID) (*releases.Release, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// List returns all releases of the codebase, with the highest versions first.
+func (s *Service) List(ctx context.Context, codebaseID codebases.ID) ([]*releases.Release, error) {
+	rr, err := s.repo.ListByCodebaseID(ctx, codebaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+	sort.SliceStable(rr, func(i, j int) bool {
+		return releases.Compare(rr[i].Tag, rr[j].Tag) > 0
+	})
+	return rr, nil
+}
+
+// ListByChange returns all releases of the change.
+func (s *Service) ListByChange(ctx context.Context, changeID changes.ID) ([]*releases.Release, error) {
+	return s.repo.ListByChangeID(ctx, changeID)
+}
+
+// GenerateNotes generates release notes for _ch_ from the titles and descriptions of the change and its ancestors, up
+// until the previous release. The notes are formatted as markdown, with the latest change first.
+func (s *Service) GenerateNotes(ctx context.Context, ch *changes.Change) (string, error) {
+	var sections []string
+	for current := ch; current != nil && len(sections) < maxNotesChanges; {
+		released, err := s.repo.ListByChangeID(ctx, current.ID)
+		if err != nil {
+			return "", fmt.Errorf("failed to list releases: %w", err)
+		}
+		if len(released) > 0 {
+			break
+		}
+
+		sections = append(sections, notesSection(current))
+
+		parent, err := s.changeService.ParentChange(ctx, current)
+		switch {
+		case errors.Is(err, service_changes.ErrNotFound):
+			current = nil
+		case err != nil:
+			return "", fmt.Errorf("failed to get parent change: %w", err)
+		default:
+			current = parent
+		}
+	}
+	return strings.Join(sections, "\n\n"), nil
+}
+
+func notesSection(ch *changes.Change) string {
+	title := "Untitled"
+	if ch.Title != nil {
+		title = *ch.Title
+	}
+
+	// the first line of the description is the title
+	body := message.Text(ch.UpdatedDescription)
+	if idx := strings.Index(body, "\n"); idx >= 0 {
+		body = strings.TrimSpace(body[idx+1:])
+	} else {
+		body = ""
+	}
+
+	if body == "" {
+		return "## " + title
+	}
+	return "## " + title + "\n\n" + body
+}
+
+func tagMessage(tag, notes string) string {
+	if notes == "" {
+		return tag
+	}
+	return tag + "\n\n" + notes
+}
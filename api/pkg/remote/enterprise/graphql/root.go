@@ -6,6 +6,7 @@ import (
 
 	service_auth "getsturdy.com/api/pkg/auth/service"
 	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/codebases/acl"
 	service_codebase "getsturdy.com/api/pkg/codebases/service"
 	"getsturdy.com/api/pkg/crypto"
 	gqlerror "getsturdy.com/api/pkg/graphql/errors"
@@ -71,6 +72,10 @@ func (r *remoteRootResolver) CreateOrUpdateCodebaseRemote(ctx context.Context, a
 		return nil, gqlerror.Error(err)
 	}
 
+	if err := r.authService.CanPerform(ctx, cb.ID, acl.ActionManageIntegrations, acl.Identity{Type: acl.Integrations, ID: "remote"}); err != nil {
+		return nil, gqlerror.Error(err)
+	}
+
 	var keyPairID *crypto.KeyPairID
 	if args.Input.KeyPairID != nil {
 		kpi := crypto.KeyPairID(*args.Input.KeyPairID)
@@ -9,6 +9,7 @@ import (
 	db_remote "getsturdy.com/api/pkg/remote/enterprise/db"
 	remote_service "getsturdy.com/api/pkg/remote/service"
 	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
+	service_trunks "getsturdy.com/api/pkg/trunks/service"
 	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
 	meta_workspaces "getsturdy.com/api/pkg/workspaces/meta"
 	"getsturdy.com/api/vcs/executor"
@@ -24,6 +25,7 @@ func Module(c *di.Container) {
 	c.Import(service_change.Module)
 	c.Import(analytics_service.Module)
 	c.Import(db_crypto.Module)
+	c.Import(service_trunks.Module)
 	c.Register(New)
 	c.Register(func(e *EnterpriseService) remote_service.Service {
 		return e
@@ -29,6 +29,8 @@ import (
 	db_remote "getsturdy.com/api/pkg/remote/enterprise/db"
 	"getsturdy.com/api/pkg/remote/service"
 	service_snapshotter "getsturdy.com/api/pkg/snapshots/service"
+	"getsturdy.com/api/pkg/trunks"
+	service_trunks "getsturdy.com/api/pkg/trunks/service"
 	"getsturdy.com/api/pkg/users"
 	"getsturdy.com/api/pkg/workspaces"
 	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
@@ -46,6 +48,7 @@ type EnterpriseService struct {
 	changeService     *service_change.Service
 	analyticsService  *analytics_service.Service
 	keyPairRepository db_crypto.KeyPairRepository
+	trunksService     *service_trunks.Service
 }
 
 var _ service.Service = (*EnterpriseService)(nil)
@@ -60,6 +63,7 @@ func New(
 	changeService *service_change.Service,
 	analyticsService *analytics_service.Service,
 	keyPairRepository db_crypto.KeyPairRepository,
+	trunksService *service_trunks.Service,
 ) *EnterpriseService {
 	return &EnterpriseService{
 		repo:              repo,
@@ -71,6 +75,7 @@ func New(
 		changeService:     changeService,
 		analyticsService:  analyticsService,
 		keyPairRepository: keyPairRepository,
+		trunksService:     trunksService,
 	}
 }
 
@@ -177,7 +182,10 @@ func (svc *EnterpriseService) SetRemote(ctx context.Context, codebaseID codebase
 	}
 }
 
-var ErrRemoteDisabled = errors.New("this remote is disabled")
+var (
+	ErrRemoteDisabled   = errors.New("this remote is disabled")
+	ErrTrunkNotTracking = errors.New("the trunk does not track a branch on the remote")
+)
 
 func (svc *EnterpriseService) Push(ctx context.Context, user *users.User, ws *workspaces.Workspace) error {
 	rem, err := svc.GetWithFixedURL(ctx, ws.CodebaseID)
@@ -215,7 +223,7 @@ func (svc *EnterpriseService) Push(ctx context.Context, user *users.User, ws *wo
 		}
 	}
 
-	if err := svc.executorProvider.New().GitWrite(push).ExecTrunk(ws.CodebaseID, "pushRemote"); err != nil {
+	if err := svc.executorProvider.New().GitWrite(push).ExecTrunkContext(ctx, ws.CodebaseID, "pushRemote"); err != nil {
 		return fmt.Errorf("failed to push workspace to remote: %w", err)
 	}
 
@@ -224,7 +232,7 @@ func (svc *EnterpriseService) Push(ctx context.Context, user *users.User, ws *wo
 	return nil
 }
 
-func (svc *EnterpriseService) PushTrunk(ctx context.Context, codebaseID codebases.ID) error {
+func (svc *EnterpriseService) PushTrunk(ctx context.Context, codebaseID codebases.ID, trunkID *trunks.ID) error {
 	rem, err := svc.GetWithFixedURL(ctx, codebaseID)
 	if err != nil {
 		return fmt.Errorf("could not get remote: %w", err)
@@ -233,7 +241,22 @@ func (svc *EnterpriseService) PushTrunk(ctx context.Context, codebaseID codebase
 		return ErrRemoteDisabled
 	}
 
-	refspec := fmt.Sprintf("refs/heads/sturdytrunk:refs/heads/%s", rem.TrackedBranch)
+	trackedBranch := rem.TrackedBranch
+	if trunkID != nil {
+		trunk, err := svc.trunksService.Get(ctx, *trunkID)
+		if err != nil {
+			return fmt.Errorf("could not get trunk: %w", err)
+		}
+		if trunk.CodebaseID != codebaseID {
+			return fmt.Errorf("trunk does not belong to this codebase")
+		}
+		if trunk.TrackedBranch == nil {
+			return ErrTrunkNotTracking
+		}
+		trackedBranch = *trunk.TrackedBranch
+	}
+
+	refspec := fmt.Sprintf("refs/heads/%s:refs/heads/%s", trunks.BranchName(trunkID), trackedBranch)
 
 	creds, err := svc.newCredentialsCallback(ctx, rem)
 	if err != nil {
@@ -252,11 +275,50 @@ func (svc *EnterpriseService) PushTrunk(ctx context.Context, codebaseID codebase
 		}
 	}
 
-	if err := svc.executorProvider.New().GitWrite(push).ExecTrunk(codebaseID, "pushTrunkRemote"); err != nil {
+	if err := svc.executorProvider.New().GitWrite(push).ExecTrunkContext(ctx, codebaseID, "pushTrunkRemote"); err != nil {
 		return fmt.Errorf("failed to push trunk to remote: %w", err)
 	}
 
-	svc.analyticsService.Capture(ctx, "pushed trunk to remote", analytics.CodebaseID(codebaseID))
+	svc.analyticsService.Capture(ctx, "pushed trunk to remote", analytics.CodebaseID(codebaseID), analytics.Property("default_trunk", trunkID == nil))
+
+	return nil
+}
+
+func (svc *EnterpriseService) PushTag(ctx context.Context, codebaseID codebases.ID, tag string) error {
+	rem, err := svc.GetWithFixedURL(ctx, codebaseID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil
+	case err != nil:
+		return fmt.Errorf("could not get remote: %w", err)
+	case !rem.Enabled:
+		return nil
+	}
+
+	refspec := fmt.Sprintf("refs/tags/%s:refs/tags/%s", tag, tag)
+
+	creds, err := svc.newCredentialsCallback(ctx, rem)
+	if err != nil {
+		return fmt.Errorf("could not get creds: %w", err)
+	}
+
+	push := func(repo vcs.RepoGitWriter) error {
+		_, err := repo.PushRemoteUrlWithRefspec(rem.URL, creds, []config.RefSpec{config.RefSpec(refspec)})
+		switch {
+		case errors.Is(err, gogit.NoErrAlreadyUpToDate):
+			return nil
+		case err != nil:
+			return fmt.Errorf("failed to push: %w", err)
+		default:
+			return nil
+		}
+	}
+
+	if err := svc.executorProvider.New().GitWrite(push).ExecTrunkContext(ctx, codebaseID, "pushTagRemote"); err != nil {
+		return fmt.Errorf("failed to push tag to remote: %w", err)
+	}
+
+	svc.analyticsService.Capture(ctx, "pushed tag to remote", analytics.CodebaseID(codebaseID))
 
 	return nil
 }
@@ -270,7 +332,20 @@ func (svc *EnterpriseService) Pull(ctx context.Context, codebaseID codebases.ID)
 		return ErrRemoteDisabled
 	}
 
-	refspec := fmt.Sprintf("+refs/heads/%s:refs/heads/sturdytrunk", rem.TrackedBranch)
+	refspecs := []config.RefSpec{
+		config.RefSpec(fmt.Sprintf("+refs/heads/%s:refs/heads/%s", rem.TrackedBranch, trunks.DefaultBranchName)),
+	}
+
+	tt, err := svc.trunksService.List(ctx, codebaseID)
+	if err != nil {
+		return fmt.Errorf("could not list trunks: %w", err)
+	}
+	for _, trunk := range tt {
+		if trunk.TrackedBranch == nil {
+			continue
+		}
+		refspecs = append(refspecs, config.RefSpec(fmt.Sprintf("+refs/heads/%s:refs/heads/%s", *trunk.TrackedBranch, trunk.BranchName())))
+	}
 
 	creds, err := svc.newCredentialsCallback(ctx, rem)
 	if err != nil {
@@ -278,7 +353,7 @@ func (svc *EnterpriseService) Pull(ctx context.Context, codebaseID codebases.ID)
 	}
 
 	pull := func(repo vcs.RepoGitWriter) error {
-		err := repo.FetchUrlRemoteWithCreds(rem.URL, creds, []config.RefSpec{config.RefSpec(refspec)})
+		err := repo.FetchUrlRemoteWithCreds(rem.URL, creds, refspecs)
 		switch {
 		case errors.Is(err, gogit.NoErrAlreadyUpToDate):
 			return nil
@@ -289,7 +364,7 @@ func (svc *EnterpriseService) Pull(ctx context.Context, codebaseID codebases.ID)
 		}
 	}
 
-	if err := svc.executorProvider.New().GitWrite(pull).ExecTrunk(codebaseID, "pullRemote"); err != nil {
+	if err := svc.executorProvider.New().GitWrite(pull).ExecTrunkContext(ctx, codebaseID, "pullRemote"); err != nil {
 		return fmt.Errorf("failed to pull: %w", err)
 	}
 
@@ -376,7 +451,7 @@ func (svc *EnterpriseService) prepareBranchForPullRequestFromSnapshot(ctx contex
 		return nil
 	})
 
-	if err := exec.ExecTrunk(ws.CodebaseID, "prepareBranchForPullRequestFromSnapshot"); err != nil {
+	if err := exec.ExecTrunkContext(ctx, ws.CodebaseID, "prepareBranchForPullRequestFromSnapshot"); err != nil {
 		return "", fmt.Errorf("failed to create pr branch from snapshot")
 	}
 
@@ -420,7 +495,7 @@ func (svc *EnterpriseService) prepareBranchForPullRequestWithView(ctx context.Co
 		return nil
 	})
 
-	if err := exec.ExecView(ws.CodebaseID, *ws.ViewID, "prepareBranchForPullRequestWithView"); err != nil {
+	if err := exec.ExecViewContext(ctx, ws.CodebaseID, *ws.ViewID, "prepareBranchForPullRequestWithView"); err != nil {
 		return "", fmt.Errorf("failed to create pr branch from view: %w", err)
 	}
 
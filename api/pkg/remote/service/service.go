@@ -5,11 +5,16 @@ import (
 	"errors"
 
 	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/trunks"
 )
 
 type Service interface {
+	// Pull fetches the tracked branches of the remote to the default trunk, and to every trunk that tracks a branch.
 	Pull(ctx context.Context, codebaseID codebases.ID) error
-	PushTrunk(ctx context.Context, codebaseID codebases.ID) error
+	// PushTrunk pushes the trunk to its tracked branch on the remote. A nil trunkID pushes the default trunk.
+	PushTrunk(ctx context.Context, codebaseID codebases.ID, trunkID *trunks.ID) error
+	// PushTag pushes the git tag to the remote. It's a no-op if the codebase does not have an enabled remote.
+	PushTag(ctx context.Context, codebaseID codebases.ID, tag string) error
 }
 
 type service struct{}
@@ -22,6 +27,11 @@ func (*service) Pull(context.Context, codebases.ID) error {
 	return errors.New("not available")
 }
 
-func (*service) PushTrunk(context.Context, codebases.ID) error {
+func (*service) PushTrunk(context.Context, codebases.ID, *trunks.ID) error {
 	return errors.New("not available")
 }
+
+func (*service) PushTag(context.Context, codebases.ID, string) error {
+	// remotes are not available, so there is nowhere to push to
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"getsturdy.com/api/pkg/codebases"
 	"getsturdy.com/api/pkg/review"
 	"getsturdy.com/api/pkg/users"
 
@@ -76,3 +77,10 @@ func (r *database) ListLatestByWorkspace(ctx context.Context, workspaceID string
 	}
 	return res, nil
 }
+
+func (r *database) DeleteByCodebaseID(ctx context.Context, codebaseID codebases.ID) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM workspace_reviews WHERE codebase_id = $1`, codebaseID); err != nil {
+		return fmt.Errorf("failed to delete workspace_reviews: %w", err)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 
+	"getsturdy.com/api/pkg/codebases"
 	"getsturdy.com/api/pkg/review"
 	"getsturdy.com/api/pkg/users"
 )
@@ -84,3 +85,14 @@ func (m *memory) ListLatestByWorkspace(ctx context.Context, workspaceID string)
 	}
 	return rr, nil
 }
+
+func (m *memory) DeleteByCodebaseID(_ context.Context, codebaseID codebases.ID) error {
+	for id, r := range m.byID {
+		if r.CodebaseID != codebaseID {
+			continue
+		}
+		delete(m.byID, id)
+		delete(m.byWorkspaceByUser, r.WorkspaceID)
+	}
+	return nil
+}
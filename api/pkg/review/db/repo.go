@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 
+	"getsturdy.com/api/pkg/codebases"
 	"getsturdy.com/api/pkg/review"
 	"getsturdy.com/api/pkg/users"
 )
@@ -13,4 +14,5 @@ type ReviewRepository interface {
 	Get(ctx context.Context, id string) (*review.Review, error)
 	GetLatestByUserAndWorkspace(ctx context.Context, userID users.ID, workspaceID string) (*review.Review, error)
 	ListLatestByWorkspace(ctx context.Context, workspaceID string) ([]*review.Review, error)
+	DeleteByCodebaseID(context.Context, codebases.ID) error
 }
@@ -11,6 +11,7 @@ import (
 	"getsturdy.com/api/pkg/logger"
 	"getsturdy.com/api/pkg/notification/sender"
 	db_review "getsturdy.com/api/pkg/review/db"
+	service_review "getsturdy.com/api/pkg/review/service"
 	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
 	service_workspace_watchers "getsturdy.com/api/pkg/workspaces/watchers/service"
 )
@@ -18,6 +19,7 @@ import (
 func Module(c *di.Container) {
 	c.Import(logger.Module)
 	c.Import(db_review.Module)
+	c.Import(service_review.Module)
 	c.Import(db_workspaces.Module)
 	c.Import(service_auth.Module)
 	c.Import(grapqhl_author.Module)
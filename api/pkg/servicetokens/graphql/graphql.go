@@ -6,6 +6,7 @@ import (
 
 	service_auth "getsturdy.com/api/pkg/auth/service"
 	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/codebases/acl"
 	service_codebase "getsturdy.com/api/pkg/codebases/service"
 	gqlerror "getsturdy.com/api/pkg/graphql/errors"
 	"getsturdy.com/api/pkg/graphql/resolvers"
@@ -43,6 +44,10 @@ func (r *rootResolver) CreateServiceToken(ctx context.Context, args resolvers.Cr
 		return nil, gqlerror.Error(err)
 	}
 
+	if err := r.authService.CanPerform(ctx, codebase.ID, acl.ActionAdmin, acl.Identity{Type: acl.Codebases, ID: codebase.ID.String()}); err != nil {
+		return nil, gqlerror.Error(err)
+	}
+
 	plainTextToken, token, err := r.serviceTokensService.Create(ctx, codebase.ID, args.Input.Name)
 	if err != nil {
 		return nil, gqlerror.Error(fmt.Errorf("failed to create token: %w", err))
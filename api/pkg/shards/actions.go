@@ -0,0 +1,33 @@
+package shards
+
+import (
+	"context"
+	"encoding/json"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/di"
+)
+
+// Action is git work that can be forwarded to the node that stores the repositories of a codebase. Unlike the
+// functions that are scheduled on an executor, actions are identified by their name, and take and return JSON, so that
+// they can be sent between nodes.
+type Action struct {
+	Name string
+	Func func(ctx context.Context, codebaseID codebases.ID, args json.RawMessage) (any, error)
+}
+
+// ActionOut is used to register an Action in the di container:
+//
+//	c.Register(func(p executor.Provider) shards.ActionOut { return shards.ActionOut{Action: myAction(p)} })
+type ActionOut struct {
+	di.Out
+
+	Action Action `group:"shards_actions"`
+}
+
+// ActionsIn contains all Actions that have been registered in the di container.
+type ActionsIn struct {
+	di.In
+
+	Actions []Action `group:"shards_actions"`
+}
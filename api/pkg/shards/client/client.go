@@ -0,0 +1,100 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/shards"
+	"getsturdy.com/api/pkg/shards/configuration"
+)
+
+// Client makes requests to the internal API of other nodes.
+type Client struct {
+	httpClient *http.Client
+	secret     string
+}
+
+func New(cfg *configuration.Configuration) *Client {
+	return &Client{
+		httpClient: http.DefaultClient,
+		secret:     cfg.Secret,
+	}
+}
+
+// Exec executes the action on the node, and returns its JSON encoded result.
+func (c *Client) Exec(ctx context.Context, node *shards.Node, codebaseID codebases.ID, action string, args json.RawMessage) (json.RawMessage, error) {
+	resp, err := c.do(ctx, node, http.MethodPost, "/v1/exec", url.Values{
+		"codebase_id": {codebaseID.String()},
+		"action":      {action},
+	}, bytes.NewReader(args))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	result, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return result, nil
+}
+
+// Archive returns an archive of the repositories of the codebase that are stored on the node. The caller must close
+// the archive.
+func (c *Client) Archive(ctx context.Context, node *shards.Node, codebaseID codebases.ID) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, node, http.MethodGet, "/v1/archive", url.Values{
+		"codebase_id": {codebaseID.String()},
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Pull makes the node copy the repositories of the codebase from the node _from_.
+func (c *Client) Pull(ctx context.Context, node *shards.Node, codebaseID codebases.ID, from shards.NodeID) error {
+	resp, err := c.do(ctx, node, http.MethodPost, "/v1/pull", url.Values{
+		"codebase_id": {codebaseID.String()},
+		"from":        {from.String()},
+	}, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Remove makes the node delete its copy of the repositories of the codebase.
+func (c *Client) Remove(ctx context.Context, node *shards.Node, codebaseID codebases.ID) error {
+	resp, err := c.do(ctx, node, http.MethodPost, "/v1/remove", url.Values{
+		"codebase_id": {codebaseID.String()},
+	}, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (c *Client) do(ctx context.Context, node *shards.Node, method, path string, query url.Values, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, node.Addr+path+"?"+query.Encode(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.secret)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request to node %s: %w", node.ID, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("node %s: unexpected status code %d: %s", node.ID, resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	return resp, nil
+}
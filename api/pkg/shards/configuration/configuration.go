@@ -0,0 +1,23 @@
+package configuration
+
+import "getsturdy.com/api/pkg/configuration/flags"
+
+type Configuration struct {
+	NodeID string     `long:"node-id" description:"ID of this node. If set, codebases are sharded between all nodes that share the same database, and git operations on a codebase are only executed on the node that stores it"`
+	Addr   flags.Addr `long:"addr" description:"Address to listen on for requests from other nodes" default:"localhost:3003"`
+	URL    *flags.URL `long:"url" description:"URL that other nodes use to reach this node (defaults to http://<addr>)"`
+	Secret string     `long:"secret" description:"Secret that nodes use to authenticate requests to each other, required if node-id is set"`
+}
+
+// Enabled returns true if codebases are sharded between nodes.
+func (c *Configuration) Enabled() bool {
+	return c != nil && c.NodeID != ""
+}
+
+// NodeURL returns the URL that other nodes use to reach this node.
+func (c *Configuration) NodeURL() string {
+	if c.URL != nil {
+		return c.URL.String()
+	}
+	return "http://" + c.Addr.String()
+}
@@ -0,0 +1,163 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/shards"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var _ Repository = &database{}
+
+type database struct {
+	db *sqlx.DB
+}
+
+func NewDatabase(db *sqlx.DB) Repository {
+	return &database{
+		db: db,
+	}
+}
+
+func (d *database) UpsertNode(ctx context.Context, node *shards.Node) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		INSERT INTO storage_nodes (
+			id, addr, seen_at
+		) VALUES (
+			:id, :addr, :seen_at
+		)
+		ON CONFLICT (id) DO UPDATE SET
+			addr = :addr,
+			seen_at = :seen_at
+	`, node); err != nil {
+		return fmt.Errorf("failed to upsert: %w", err)
+	}
+	return nil
+}
+
+func (d *database) GetNode(ctx context.Context, id shards.NodeID) (*shards.Node, error) {
+	node := &shards.Node{}
+	if err := d.db.GetContext(ctx, node, `
+		SELECT
+			id, addr, seen_at
+		FROM
+			storage_nodes
+		WHERE
+			id = $1
+	`, id); err != nil {
+		return nil, fmt.Errorf("failed to get: %w", err)
+	}
+	return node, nil
+}
+
+func (d *database) ListNodes(ctx context.Context) ([]*shards.Node, error) {
+	var nodes []*shards.Node
+	if err := d.db.SelectContext(ctx, &nodes, `
+		SELECT
+			id, addr, seen_at
+		FROM
+			storage_nodes
+		ORDER BY
+			id
+	`); err != nil {
+		return nil, fmt.Errorf("failed to list: %w", err)
+	}
+	return nodes, nil
+}
+
+func (d *database) CreateShard(ctx context.Context, shard *shards.Shard) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		INSERT INTO codebase_shards (
+			codebase_id, node_id, moving_to, created_at, updated_at
+		) VALUES (
+			:codebase_id, :node_id, :moving_to, :created_at, :updated_at
+		)
+		ON CONFLICT (codebase_id) DO NOTHING
+	`, shard); err != nil {
+		return fmt.Errorf("failed to insert: %w", err)
+	}
+	return nil
+}
+
+func (d *database) GetShard(ctx context.Context, codebaseID codebases.ID) (*shards.Shard, error) {
+	shard := &shards.Shard{}
+	if err := d.db.GetContext(ctx, shard, `
+		SELECT
+			codebase_id, node_id, moving_to, created_at, updated_at
+		FROM
+			codebase_shards
+		WHERE
+			codebase_id = $1
+	`, codebaseID); err != nil {
+		return nil, fmt.Errorf("failed to get: %w", err)
+	}
+	return shard, nil
+}
+
+func (d *database) ClaimMove(ctx context.Context, codebaseID codebases.ID, from, to shards.NodeID, staleBefore time.Time) (bool, error) {
+	res, err := d.db.ExecContext(ctx, `
+		UPDATE
+			codebase_shards
+		SET
+			moving_to = $3,
+			updated_at = $4
+		WHERE
+			codebase_id = $1
+			AND node_id = $2
+			AND (moving_to IS NULL OR updated_at < $5)
+	`, codebaseID, from, to, time.Now(), staleBefore)
+	if err != nil {
+		return false, fmt.Errorf("failed to update: %w", err)
+	}
+	return updatedOne(res)
+}
+
+func (d *database) CompleteMove(ctx context.Context, codebaseID codebases.ID, from, to shards.NodeID) (bool, error) {
+	res, err := d.db.ExecContext(ctx, `
+		UPDATE
+			codebase_shards
+		SET
+			node_id = $3,
+			moving_to = NULL,
+			updated_at = $4
+		WHERE
+			codebase_id = $1
+			AND node_id = $2
+			AND moving_to = $3
+	`, codebaseID, from, to, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("failed to update: %w", err)
+	}
+	return updatedOne(res)
+}
+
+func (d *database) CancelMove(ctx context.Context, codebaseID codebases.ID, from, to shards.NodeID) (bool, error) {
+	res, err := d.db.ExecContext(ctx, `
+		UPDATE
+			codebase_shards
+		SET
+			moving_to = NULL,
+			updated_at = $4
+		WHERE
+			codebase_id = $1
+			AND node_id = $2
+			AND moving_to = $3
+	`, codebaseID, from, to, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("failed to update: %w", err)
+	}
+	return updatedOne(res)
+}
+
+func updatedOne(res sql.Result) (bool, error) {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	return affected == 1, nil
+}
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/shards"
+)
+
+var _ Repository = &memory{}
+
+type memory struct {
+	mu     sync.RWMutex
+	nodes  map[shards.NodeID]*shards.Node
+	shards map[codebases.ID]*shards.Shard
+}
+
+func NewMemory() Repository {
+	return &memory{
+		nodes:  map[shards.NodeID]*shards.Node{},
+		shards: map[codebases.ID]*shards.Shard{},
+	}
+}
+
+func (m *memory) UpsertNode(_ context.Context, node *shards.Node) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *node
+	m.nodes[node.ID] = &cp
+	return nil
+}
+
+func (m *memory) GetNode(_ context.Context, id shards.NodeID) (*shards.Node, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	node, found := m.nodes[id]
+	if !found {
+		return nil, sql.ErrNoRows
+	}
+	cp := *node
+	return &cp, nil
+}
+
+func (m *memory) ListNodes(_ context.Context) ([]*shards.Node, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	nodes := make([]*shards.Node, 0, len(m.nodes))
+	for _, node := range m.nodes {
+		cp := *node
+		nodes = append(nodes, &cp)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID < nodes[j].ID
+	})
+	return nodes, nil
+}
+
+func (m *memory) CreateShard(_ context.Context, shard *shards.Shard) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, found := m.shards[shard.CodebaseID]; found {
+		return nil
+	}
+	cp := *shard
+	m.shards[shard.CodebaseID] = &cp
+	return nil
+}
+
+func (m *memory) GetShard(_ context.Context, codebaseID codebases.ID) (*shards.Shard, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	shard, found := m.shards[codebaseID]
+	if !found {
+		return nil, sql.ErrNoRows
+	}
+	cp := *shard
+	return &cp, nil
+}
+
+func (m *memory) ClaimMove(_ context.Context, codebaseID codebases.ID, from, to shards.NodeID, staleBefore time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	shard, found := m.shards[codebaseID]
+	if !found || shard.NodeID != from || (shard.MovingTo != nil && !shard.UpdatedAt.Before(staleBefore)) {
+		return false, nil
+	}
+	shard.MovingTo = &to
+	shard.UpdatedAt = time.Now()
+	return true, nil
+}
+
+func (m *memory) CompleteMove(_ context.Context, codebaseID codebases.ID, from, to shards.NodeID) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	shard, found := m.shards[codebaseID]
+	if !found || shard.NodeID != from || shard.MovingTo == nil || *shard.MovingTo != to {
+		return false, nil
+	}
+	shard.NodeID = to
+	shard.MovingTo = nil
+	shard.UpdatedAt = time.Now()
+	return true, nil
+}
+
+func (m *memory) CancelMove(_ context.Context, codebaseID codebases.ID, from, to shards.NodeID) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	shard, found := m.shards[codebaseID]
+	if !found || shard.NodeID != from || shard.MovingTo == nil || *shard.MovingTo != to {
+		return false, nil
+	}
+	shard.MovingTo = nil
+	shard.UpdatedAt = time.Now()
+	return true, nil
+}
@@ -0,0 +1,30 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/shards"
+)
+
+type Repository interface {
+	// UpsertNode creates the node, or updates its address and when it was last seen.
+	UpsertNode(context.Context, *shards.Node) error
+	GetNode(context.Context, shards.NodeID) (*shards.Node, error)
+	ListNodes(context.Context) ([]*shards.Node, error)
+
+	// CreateShard creates the shard, unless the codebase is already assigned to a node.
+	CreateShard(context.Context, *shards.Shard) error
+	GetShard(context.Context, codebases.ID) (*shards.Shard, error)
+
+	// ClaimMove marks the codebase as moving from the node _from_ to the node _to_. It returns false if the codebase is
+	// not assigned to _from_, or if it's already moving, unless that move was last updated before _staleBefore_.
+	ClaimMove(ctx context.Context, codebaseID codebases.ID, from, to shards.NodeID, staleBefore time.Time) (bool, error)
+	// CompleteMove assigns the codebase to _to_. It returns false if the codebase is no longer moving from _from_ to
+	// _to_.
+	CompleteMove(ctx context.Context, codebaseID codebases.ID, from, to shards.NodeID) (bool, error)
+	// CancelMove marks the codebase as no longer moving. It returns false if the codebase is no longer moving from
+	// _from_ to _to_.
+	CancelMove(ctx context.Context, codebaseID codebases.ID, from, to shards.NodeID) (bool, error)
+}
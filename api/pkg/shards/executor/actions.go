@@ -0,0 +1,40 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/shards"
+	"getsturdy.com/api/vcs"
+	"getsturdy.com/api/vcs/executor"
+)
+
+const ActionBranchCommitID = "trunk.branchCommitID"
+
+type BranchCommitIDArgs struct {
+	Branch string `json:"branch"`
+}
+
+// BranchCommitID returns an action that resolves a branch of the trunk to a commit id.
+func BranchCommitID(p executor.Provider) shards.ActionOut {
+	return shards.ActionOut{Action: shards.Action{
+		Name: ActionBranchCommitID,
+		Func: func(ctx context.Context, codebaseID codebases.ID, args json.RawMessage) (any, error) {
+			var a BranchCommitIDArgs
+			if err := json.Unmarshal(args, &a); err != nil {
+				return nil, fmt.Errorf("failed to decode args: %w", err)
+			}
+			var commitID string
+			if err := p.New().GitRead(func(repo vcs.RepoGitReader) error {
+				var err error
+				commitID, err = repo.BranchCommitID(a.Branch)
+				return err
+			}).ExecTrunkContext(ctx, codebaseID, "shardsBranchCommitID"); err != nil {
+				return nil, err
+			}
+			return commitID, nil
+		},
+	}}
+}
@@ -0,0 +1,122 @@
+package executor
+
+import (
+	"context"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/vcs/executor"
+)
+
+var _ executor.Provider = &provider{}
+
+type provider struct {
+	provider executor.Provider
+	router   *Router
+}
+
+// Decorate makes _p_ shard aware. Each codebase is pinned to the node that stores its repositories, and git operations
+// on it are only executed on that node. On other nodes, a *shards.NotOwnerError is returned. The scheduled functions
+// can not be sent to another node, instead small, read only, work can be forwarded to the node that stores the codebase
+// with the Forwarder, and codebases are moved between nodes explicitly with the Mover.
+//
+// A lease on the codebase is held while the functions are executed, so that the codebase is not moved away from this
+// node in the middle of an operation.
+func Decorate(p executor.Provider, router *Router) executor.Provider {
+	return &provider{
+		provider: p,
+		router:   router,
+	}
+}
+
+func (p *provider) New() executor.Executor {
+	return &shardedExecutor{
+		executor: p.provider.New(),
+		router:   p.router,
+	}
+}
+
+var _ executor.Executor = &shardedExecutor{}
+
+type shardedExecutor struct {
+	executor executor.Executor
+	router   *Router
+}
+
+func (e *shardedExecutor) Read(fn executor.FileReadFunc) executor.Executor {
+	e.executor = e.executor.Read(fn)
+	return e
+}
+
+func (e *shardedExecutor) Write(fn executor.FileWriteFunc) executor.Executor {
+	e.executor = e.executor.Write(fn)
+	return e
+}
+
+func (e *shardedExecutor) GitRead(fn executor.GitReadFunc) executor.Executor {
+	e.executor = e.executor.GitRead(fn)
+	return e
+}
+
+func (e *shardedExecutor) GitWrite(fn executor.GitWriteFunc) executor.Executor {
+	e.executor = e.executor.GitWrite(fn)
+	return e
+}
+
+func (e *shardedExecutor) FileReadGitWrite(fn executor.FileReadGitWriteFunc) executor.Executor {
+	e.executor = e.executor.FileReadGitWrite(fn)
+	return e
+}
+
+func (e *shardedExecutor) Schedule(fn executor.ExecuteFunc) executor.Executor {
+	e.executor = e.executor.Schedule(fn)
+	return e
+}
+
+func (e *shardedExecutor) AllowRebasingState() executor.Executor {
+	e.executor = e.executor.AllowRebasingState()
+	return e
+}
+
+func (e *shardedExecutor) AssertBranchName(name string) executor.Executor {
+	e.executor = e.executor.AssertBranchName(name)
+	return e
+}
+
+func (e *shardedExecutor) ExecView(codebaseID codebases.ID, viewID, actionName string) error {
+	return e.ExecViewContext(context.Background(), codebaseID, viewID, actionName)
+}
+
+func (e *shardedExecutor) ExecTrunk(codebaseID codebases.ID, actionName string) error {
+	return e.ExecTrunkContext(context.Background(), codebaseID, actionName)
+}
+
+func (e *shardedExecutor) ExecTemporaryView(codebaseID codebases.ID, actionName string) error {
+	return e.ExecTemporaryViewContext(context.Background(), codebaseID, actionName)
+}
+
+func (e *shardedExecutor) ExecViewContext(ctx context.Context, codebaseID codebases.ID, viewID, actionName string) error {
+	release, err := e.router.Lease(ctx, codebaseID)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return e.executor.ExecViewContext(ctx, codebaseID, viewID, actionName)
+}
+
+func (e *shardedExecutor) ExecTrunkContext(ctx context.Context, codebaseID codebases.ID, actionName string) error {
+	release, err := e.router.Lease(ctx, codebaseID)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return e.executor.ExecTrunkContext(ctx, codebaseID, actionName)
+}
+
+func (e *shardedExecutor) ExecTemporaryViewContext(ctx context.Context, codebaseID codebases.ID, actionName string) error {
+	release, err := e.router.Lease(ctx, codebaseID)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return e.executor.ExecTemporaryViewContext(ctx, codebaseID, actionName)
+}
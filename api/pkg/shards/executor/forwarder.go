@@ -0,0 +1,87 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/shards"
+	"getsturdy.com/api/pkg/shards/client"
+)
+
+var ErrUnknownAction = errors.New("unknown action")
+
+// Forwarder executes actions on the node that stores the repositories of a codebase.
+type Forwarder struct {
+	router  *Router
+	client  *client.Client
+	actions map[string]shards.Action
+}
+
+func NewForwarder(router *Router, client *client.Client, in shards.ActionsIn) *Forwarder {
+	actions := make(map[string]shards.Action, len(in.Actions))
+	for _, action := range in.Actions {
+		actions[action.Name] = action
+	}
+	return &Forwarder{
+		router:  router,
+		client:  client,
+		actions: actions,
+	}
+}
+
+// Exec executes the action with _args_ on the codebase, and decodes its result into _result_. If the codebase is
+// stored on another node, the action is forwarded to that node.
+func (f *Forwarder) Exec(ctx context.Context, codebaseID codebases.ID, action string, args, result any) error {
+	encodedArgs, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("failed to encode args: %w", err)
+	}
+
+	var encodedResult json.RawMessage
+	err = f.router.Check(ctx, codebaseID)
+	var notOwnerErr *shards.NotOwnerError
+	switch {
+	case err == nil:
+		if encodedResult, err = f.ExecLocal(ctx, codebaseID, action, encodedArgs); err != nil {
+			return err
+		}
+	case errors.As(err, &notOwnerErr):
+		node, err := f.router.Node(ctx, notOwnerErr.Owner)
+		if err != nil {
+			return err
+		}
+		if encodedResult, err = f.client.Exec(ctx, node, codebaseID, action, encodedArgs); err != nil {
+			return fmt.Errorf("failed to forward %s: %w", action, err)
+		}
+	default:
+		return err
+	}
+
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(encodedResult, result); err != nil {
+		return fmt.Errorf("failed to decode result: %w", err)
+	}
+	return nil
+}
+
+// ExecLocal executes the action on this node, and returns its JSON encoded result.
+func (f *Forwarder) ExecLocal(ctx context.Context, codebaseID codebases.ID, action string, args json.RawMessage) (json.RawMessage, error) {
+	a, ok := f.actions[action]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAction, action)
+	}
+	result, err := a.Func(ctx, codebaseID, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute %s: %w", action, err)
+	}
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode result: %w", err)
+	}
+	return encoded, nil
+}
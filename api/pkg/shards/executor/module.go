@@ -0,0 +1,23 @@
+package executor
+
+import (
+	configuration "getsturdy.com/api/pkg/configuration/module"
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/logger"
+	"getsturdy.com/api/pkg/shards/client"
+	db_shards "getsturdy.com/api/pkg/shards/db"
+	"getsturdy.com/api/vcs/executor"
+)
+
+func Module(c *di.Container) {
+	c.Import(configuration.Module)
+	c.Import(logger.Module)
+	c.Import(db_shards.Module)
+	c.Import(client.Module)
+	c.Import(executor.Module)
+	c.Register(NewRouter)
+	c.Register(NewForwarder)
+	c.Register(NewMover)
+	c.Register(BranchCommitID)
+	c.Decorate(Decorate)
+}
@@ -0,0 +1,102 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/shards"
+	"getsturdy.com/api/pkg/shards/client"
+	db_shards "getsturdy.com/api/pkg/shards/db"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrDisabled    = errors.New("sharding is not enabled")
+	ErrMoving      = errors.New("codebase is already being moved")
+	ErrUnknownNode = errors.New("unknown node")
+)
+
+// Mover moves the repositories of codebases between nodes.
+type Mover struct {
+	repo   db_shards.Repository
+	router *Router
+	client *client.Client
+	logger *zap.Logger
+}
+
+func NewMover(repo db_shards.Repository, router *Router, client *client.Client, logger *zap.Logger) *Mover {
+	return &Mover{
+		repo:   repo,
+		router: router,
+		client: client,
+		logger: logger.Named("shardsMover"),
+	}
+}
+
+// Move moves the repositories of the codebase to the node _to_.
+//
+// While the codebase is moving, no new git operations are started on it. The target node copies the repositories from
+// the node that currently stores them, once all git operations that were running on them have completed. Once the copy
+// is complete the codebase is assigned to the target node, and the old copy is removed.
+func (m *Mover) Move(ctx context.Context, codebaseID codebases.ID, to shards.NodeID) error {
+	if !m.router.Enabled() {
+		return ErrDisabled
+	}
+
+	shard, err := m.router.Shard(ctx, codebaseID)
+	if err != nil {
+		return err
+	}
+	if shard.NodeID == to && shard.MovingTo == nil {
+		return nil
+	}
+	from := shard.NodeID
+
+	target, err := m.repo.GetNode(ctx, to)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUnknownNode, to)
+	}
+	source, err := m.repo.GetNode(ctx, from)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUnknownNode, from)
+	}
+
+	// only one move can be in progress at a time, unless the previous one has failed without being cancelled
+	claimed, err := m.repo.ClaimMove(ctx, codebaseID, from, to, time.Now().Add(-staleMoveAfter))
+	if err != nil {
+		return fmt.Errorf("failed to mark shard as moving: %w", err)
+	}
+	if !claimed {
+		return ErrMoving
+	}
+
+	if err := m.client.Pull(ctx, target, codebaseID, source.ID); err != nil {
+		if _, err := m.repo.CancelMove(context.Background(), codebaseID, from, to); err != nil {
+			m.logger.Error("failed to cancel move", zap.Error(err))
+		}
+		return fmt.Errorf("failed to copy repositories: %w", err)
+	}
+
+	completed, err := m.repo.CompleteMove(ctx, codebaseID, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to update shard: %w", err)
+	}
+	if !completed {
+		// the move was cancelled, or taken over, while the repositories were copied
+		if err := m.client.Remove(ctx, target, codebaseID); err != nil {
+			m.logger.Error("failed to remove copied repositories", zap.Error(err), zap.Stringer("node_id", target.ID))
+		}
+		return ErrMoving
+	}
+
+	if err := m.client.Remove(ctx, source, codebaseID); err != nil {
+		m.logger.Error("failed to remove moved repositories", zap.Error(err), zap.Stringer("node_id", source.ID))
+		// do not fail
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+package executor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/shards"
+	"getsturdy.com/api/pkg/shards/configuration"
+	db_shards "getsturdy.com/api/pkg/shards/db"
+)
+
+type moveContextKey struct{}
+
+// ForMove returns a context that allows git operations on a codebase while it is being moved away from this node. It
+// is used by the move itself, to read the repositories that are being moved.
+func ForMove(ctx context.Context) context.Context {
+	return context.WithValue(ctx, moveContextKey{}, true)
+}
+
+func isForMove(ctx context.Context) bool {
+	forMove, _ := ctx.Value(moveContextKey{}).(bool)
+	return forMove
+}
+
+const (
+	// movingTimeout is how long git operations wait for a moving codebase, before they fail with a *shards.MovingError.
+	movingTimeout = time.Minute
+	// movingPollInterval is how often a moving codebase is checked to see if the move has completed.
+	movingPollInterval = 100 * time.Millisecond
+	// staleMoveAfter is how long a move can go without being updated, before it's considered to have failed. Stale
+	// moves are cancelled by the node that stores the codebase, and can be taken over by a new move.
+	staleMoveAfter = 15 * time.Minute
+)
+
+// Router keeps track of which node stores the repositories of each codebase.
+type Router struct {
+	repo db_shards.Repository
+	cfg  *configuration.Configuration
+
+	leasesMu sync.Mutex
+	leases   map[codebases.ID]*sync.RWMutex
+}
+
+func NewRouter(repo db_shards.Repository, cfg *configuration.Configuration) *Router {
+	return &Router{
+		repo:   repo,
+		cfg:    cfg,
+		leases: make(map[codebases.ID]*sync.RWMutex),
+	}
+}
+
+// Enabled returns true if codebases are sharded between nodes. If not, all codebases are stored on this node.
+func (r *Router) Enabled() bool {
+	return r.cfg.Enabled()
+}
+
+// NodeID returns the id of this node.
+func (r *Router) NodeID() shards.NodeID {
+	return shards.NodeID(r.cfg.NodeID)
+}
+
+// Shard returns the shard of the codebase. Codebases that are not yet assigned to a node are assigned to this node,
+// as they are created by, or already stored on, this node.
+func (r *Router) Shard(ctx context.Context, codebaseID codebases.ID) (*shards.Shard, error) {
+	shard, err := r.repo.GetShard(ctx, codebaseID)
+	switch {
+	case err == nil:
+		return shard, nil
+	case errors.Is(err, sql.ErrNoRows):
+	default:
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+
+	now := time.Now()
+	if err := r.repo.CreateShard(ctx, &shards.Shard{
+		CodebaseID: codebaseID,
+		NodeID:     r.NodeID(),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create shard: %w", err)
+	}
+
+	// another node might have claimed the codebase first
+	shard, err = r.repo.GetShard(ctx, codebaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shard: %w", err)
+	}
+	return shard, nil
+}
+
+// Check returns nil if git operations on the codebase can be executed on this node. Otherwise, a
+// *shards.NotOwnerError or a *shards.MovingError is returned.
+func (r *Router) Check(ctx context.Context, codebaseID codebases.ID) error {
+	if !r.Enabled() {
+		return nil
+	}
+
+	shard, err := r.Shard(ctx, codebaseID)
+	if err != nil {
+		return err
+	}
+	if shard.NodeID != r.NodeID() {
+		return &shards.NotOwnerError{CodebaseID: codebaseID, Owner: shard.NodeID}
+	}
+	if shard.MovingTo == nil || isForMove(ctx) {
+		return nil
+	}
+	if time.Since(shard.UpdatedAt) < staleMoveAfter {
+		return &shards.MovingError{CodebaseID: codebaseID}
+	}
+
+	// the move has failed, and the codebase is still stored on this node
+	cancelled, err := r.repo.CancelMove(ctx, codebaseID, shard.NodeID, *shard.MovingTo)
+	if err != nil {
+		return fmt.Errorf("failed to cancel stale move: %w", err)
+	}
+	if !cancelled {
+		// the move was updated, or taken over, in the meantime
+		return &shards.MovingError{CodebaseID: codebaseID}
+	}
+	return nil
+}
+
+// Lease waits until git operations on the codebase can be executed on this node, and returns a function that releases
+// the lease. The lease must be held for the whole operation, the codebase is not moved away from this node while any
+// lease on it is held.
+//
+// If the codebase is stored on another node, a *shards.NotOwnerError is returned. If the codebase is being moved, Lease
+// waits for the move to complete, for at most movingTimeout, before a *shards.MovingError is returned.
+func (r *Router) Lease(ctx context.Context, codebaseID codebases.ID) (func(), error) {
+	if !r.Enabled() {
+		return func() {}, nil
+	}
+	if isForMove(ctx) {
+		// the move holds the exclusive lease
+		return func() {}, r.Check(ctx, codebaseID)
+	}
+
+	lease := r.lease(codebaseID)
+	deadline := time.After(movingTimeout)
+	for {
+		lease.RLock()
+		err := r.Check(ctx, codebaseID)
+		if err == nil {
+			return lease.RUnlock, nil
+		}
+		lease.RUnlock()
+
+		var movingErr *shards.MovingError
+		if !errors.As(err, &movingErr) {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline:
+			return nil, err
+		case <-time.After(movingPollInterval):
+		}
+	}
+}
+
+// LockForMove waits until all leases on the codebase are released, and prevents new ones from being acquired until the
+// returned function is called.
+func (r *Router) LockForMove(codebaseID codebases.ID) func() {
+	lease := r.lease(codebaseID)
+	lease.Lock()
+	return lease.Unlock
+}
+
+func (r *Router) lease(codebaseID codebases.ID) *sync.RWMutex {
+	r.leasesMu.Lock()
+	defer r.leasesMu.Unlock()
+	lease, ok := r.leases[codebaseID]
+	if !ok {
+		lease = &sync.RWMutex{}
+		r.leases[codebaseID] = lease
+	}
+	return lease
+}
+
+// Node returns the node with the given id.
+func (r *Router) Node(ctx context.Context, id shards.NodeID) (*shards.Node, error) {
+	node, err := r.repo.GetNode(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node: %w", err)
+	}
+	return node, nil
+}
@@ -0,0 +1,17 @@
+package server
+
+import (
+	configuration "getsturdy.com/api/pkg/configuration/module"
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/logger"
+	shards_executor "getsturdy.com/api/pkg/shards/executor"
+	service_shards "getsturdy.com/api/pkg/shards/service"
+)
+
+func Module(c *di.Container) {
+	c.Import(configuration.Module)
+	c.Import(logger.Module)
+	c.Import(shards_executor.Module)
+	c.Import(service_shards.Module)
+	c.Register(New)
+}
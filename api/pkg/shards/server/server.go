@@ -0,0 +1,184 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/shards"
+	"getsturdy.com/api/pkg/shards/configuration"
+	shards_executor "getsturdy.com/api/pkg/shards/executor"
+	service_shards "getsturdy.com/api/pkg/shards/service"
+
+	"go.uber.org/zap"
+)
+
+// heartbeatInterval is how often the node updates when it was last seen.
+const heartbeatInterval = 30 * time.Second
+
+// ErrMissingSecret is returned if sharding is enabled, but no secret is configured.
+var ErrMissingSecret = errors.New("a secret is required when node-id is set")
+
+// Server serves the internal API that nodes use to forward git operations to each other, and to move codebases between
+// them. It's only started if sharding is enabled.
+type Server struct {
+	cfg       *configuration.Configuration
+	service   *service_shards.Service
+	forwarder *shards_executor.Forwarder
+	logger    *zap.Logger
+}
+
+func New(
+	cfg *configuration.Configuration,
+	service *service_shards.Service,
+	forwarder *shards_executor.Forwarder,
+	logger *zap.Logger,
+) *Server {
+	return &Server{
+		cfg:       cfg,
+		service:   service,
+		forwarder: forwarder,
+		logger:    logger.Named("shardsServer"),
+	}
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/exec", s.authenticated(http.MethodPost, s.exec))
+	mux.HandleFunc("/v1/archive", s.authenticated(http.MethodGet, s.archive))
+	mux.HandleFunc("/v1/pull", s.authenticated(http.MethodPost, s.pull))
+	mux.HandleFunc("/v1/remove", s.authenticated(http.MethodPost, s.remove))
+	return mux
+}
+
+func (s *Server) Start(ctx context.Context) error {
+	if !s.cfg.Enabled() {
+		return nil
+	}
+	if s.cfg.Secret == "" {
+		return ErrMissingSecret
+	}
+
+	if err := s.service.Register(ctx); err != nil {
+		return err
+	}
+	if err := s.service.ClaimLocal(ctx); err != nil {
+		return fmt.Errorf("failed to claim local codebases: %w", err)
+	}
+
+	go s.heartbeat(ctx)
+
+	srv := &http.Server{
+		Addr:    s.cfg.Addr.String(),
+		Handler: s.Handler(),
+	}
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to start shards server: %w", err)
+	}
+	return nil
+}
+
+func (s *Server) heartbeat(ctx context.Context) {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.service.Register(ctx); err != nil {
+				s.logger.Error("failed to register node", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (s *Server) authenticated(method string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		// an empty secret would make every request authorized, Start refuses to serve without one
+		expected := []byte("Bearer " + s.cfg.Secret)
+		if s.cfg.Secret == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) exec(w http.ResponseWriter, r *http.Request) {
+	codebaseID := codebases.ID(r.URL.Query().Get("codebase_id"))
+	args, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := s.forwarder.ExecLocal(r.Context(), codebaseID, r.URL.Query().Get("action"), args)
+	if err != nil {
+		s.error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(result)
+}
+
+func (s *Server) archive(w http.ResponseWriter, r *http.Request) {
+	codebaseID := codebases.ID(r.URL.Query().Get("codebase_id"))
+	w.Header().Set("Content-Type", "application/gzip")
+	if err := s.service.Archive(r.Context(), codebaseID, w); err != nil {
+		// the archive might be partially written, the client will fail to read it
+		s.error(w, err)
+		return
+	}
+}
+
+func (s *Server) pull(w http.ResponseWriter, r *http.Request) {
+	codebaseID := codebases.ID(r.URL.Query().Get("codebase_id"))
+	from := shards.NodeID(r.URL.Query().Get("from"))
+	if err := s.service.Pull(r.Context(), codebaseID, from); err != nil {
+		s.error(w, err)
+		return
+	}
+}
+
+func (s *Server) remove(w http.ResponseWriter, r *http.Request) {
+	codebaseID := codebases.ID(r.URL.Query().Get("codebase_id"))
+	if err := s.service.Remove(r.Context(), codebaseID); err != nil {
+		s.error(w, err)
+		return
+	}
+}
+
+func (s *Server) error(w http.ResponseWriter, err error) {
+	var notOwnerErr *shards.NotOwnerError
+	var movingErr *shards.MovingError
+	switch {
+	case errors.Is(err, shards_executor.ErrUnknownAction),
+		errors.Is(err, service_shards.ErrInvalidID),
+		errors.Is(err, service_shards.ErrUnknownNode):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.As(err, &notOwnerErr),
+		errors.As(err, &movingErr),
+		errors.Is(err, service_shards.ErrNotOwner),
+		errors.Is(err, service_shards.ErrStillOwner),
+		errors.Is(err, service_shards.ErrNotMoving):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		s.logger.Error("failed to handle request", zap.Error(err))
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
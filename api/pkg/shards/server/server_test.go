@@ -0,0 +1,320 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"getsturdy.com/api/pkg/codebases"
+	vcs_codebases "getsturdy.com/api/pkg/codebases/vcs"
+	"getsturdy.com/api/pkg/configuration/flags"
+	"getsturdy.com/api/pkg/shards"
+	"getsturdy.com/api/pkg/shards/client"
+	"getsturdy.com/api/pkg/shards/configuration"
+	db_shards "getsturdy.com/api/pkg/shards/db"
+	shards_executor "getsturdy.com/api/pkg/shards/executor"
+	service_shards "getsturdy.com/api/pkg/shards/service"
+	"getsturdy.com/api/vcs"
+	"getsturdy.com/api/vcs/executor"
+	"getsturdy.com/api/vcs/provider"
+	provider_configuration "getsturdy.com/api/vcs/provider/configuration"
+)
+
+type testNode struct {
+	reposPath string
+	executor  executor.Provider
+	forwarder *shards_executor.Forwarder
+	service   *service_shards.Service
+}
+
+// newTestNode starts a node with its own repositories directory, that shares _repo_ with all other nodes.
+func newTestNode(t *testing.T, repo db_shards.Repository, id string) *testNode {
+	reposPath := t.TempDir()
+	repoProvider := provider.New(reposPath, "localhost:8888")
+
+	var handler http.Handler
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	cfg := &configuration.Configuration{
+		NodeID: id,
+		URL:    &flags.URL{URL: *u},
+		Secret: "secret",
+	}
+
+	router := shards_executor.NewRouter(repo, cfg)
+	shardsClient := client.New(cfg)
+	mover := shards_executor.NewMover(repo, router, shardsClient, zap.NewNop())
+	executorProvider := shards_executor.Decorate(executor.NewProvider(zap.NewNop(), repoProvider), router)
+	forwarder := shards_executor.NewForwarder(router, shardsClient, shards.ActionsIn{
+		Actions: []shards.Action{shards_executor.BranchCommitID(executorProvider).Action},
+	})
+	service := service_shards.New(
+		repo,
+		router,
+		mover,
+		shardsClient,
+		executorProvider,
+		repoProvider,
+		&provider_configuration.Configuration{ReposPath: reposPath},
+		cfg,
+		zap.NewNop(),
+	)
+	handler = New(cfg, service, forwarder, zap.NewNop()).Handler()
+
+	require.NoError(t, service.Register(context.Background()))
+
+	return &testNode{
+		reposPath: reposPath,
+		executor:  executorProvider,
+		forwarder: forwarder,
+		service:   service,
+	}
+}
+
+func headCommitID(p executor.Provider, codebaseID codebases.ID) (string, error) {
+	return branchCommitID(p, codebaseID, "sturdytrunk")
+}
+
+func branchCommitID(p executor.Provider, codebaseID codebases.ID, branch string) (string, error) {
+	var commitID string
+	err := p.New().GitRead(func(repo vcs.RepoGitReader) error {
+		var err error
+		commitID, err = repo.BranchCommitID(branch)
+		return err
+	}).ExecTrunk(codebaseID, "testBranchCommitID")
+	return commitID, err
+}
+
+func TestMove(t *testing.T) {
+	ctx := context.Background()
+	repo := db_shards.NewMemory()
+
+	nodeA := newTestNode(t, repo, "a")
+	nodeB := newTestNode(t, repo, "b")
+
+	codebaseID := codebases.ID("cb")
+
+	// the codebase is created on, and assigned to, node a
+	require.NoError(t, nodeA.executor.New().
+		Schedule(vcs_codebases.Create(codebaseID)).
+		ExecTrunk(codebaseID, "createTrunk"))
+
+	commitID, err := headCommitID(nodeA.executor, codebaseID)
+	require.NoError(t, err)
+
+	// node b can forward work to node a, without moving the codebase
+	var forwarded string
+	require.NoError(t, nodeB.forwarder.Exec(ctx, codebaseID, shards_executor.ActionBranchCommitID, shards_executor.BranchCommitIDArgs{Branch: "sturdytrunk"}, &forwarded))
+	assert.Equal(t, commitID, forwarded)
+
+	shard, err := repo.GetShard(ctx, codebaseID)
+	require.NoError(t, err)
+	assert.Equal(t, shards.NodeID("a"), shard.NodeID)
+
+	require.NoError(t, nodeB.service.Move(ctx, codebaseID, "b"))
+
+	shard, err = repo.GetShard(ctx, codebaseID)
+	require.NoError(t, err)
+	assert.Equal(t, shards.NodeID("b"), shard.NodeID)
+	assert.Nil(t, shard.MovingTo)
+
+	// the repositories are now on node b
+	_, err = os.Stat(filepath.Join(nodeB.reposPath, codebaseID.String(), "trunk"))
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(nodeA.reposPath, codebaseID.String()))
+	assert.True(t, os.IsNotExist(err), "repositories are removed from node a")
+
+	moved, err := headCommitID(nodeB.executor, codebaseID)
+	require.NoError(t, err)
+	assert.Equal(t, commitID, moved)
+
+	// node a now forwards to node b
+	require.NoError(t, nodeA.forwarder.Exec(ctx, codebaseID, shards_executor.ActionBranchCommitID, shards_executor.BranchCommitIDArgs{Branch: "sturdytrunk"}, &forwarded))
+	assert.Equal(t, commitID, forwarded)
+}
+
+func TestExecTrunk_notOwner(t *testing.T) {
+	ctx := context.Background()
+	repo := db_shards.NewMemory()
+
+	nodeA := newTestNode(t, repo, "a")
+	nodeB := newTestNode(t, repo, "b")
+
+	codebaseID := codebases.ID("cb")
+	require.NoError(t, nodeA.executor.New().
+		Schedule(vcs_codebases.Create(codebaseID)).
+		ExecTrunk(codebaseID, "createTrunk"))
+
+	// the codebase is pinned to node a, work is not executed on node b
+	var started bool
+	err := nodeB.executor.New().GitRead(func(vcs.RepoGitReader) error {
+		started = true
+		return nil
+	}).ExecTrunk(codebaseID, "testNotOwner")
+	var notOwnerErr *shards.NotOwnerError
+	if assert.ErrorAs(t, err, &notOwnerErr) {
+		assert.Equal(t, shards.NodeID("a"), notOwnerErr.Owner)
+	}
+	assert.False(t, started)
+
+	shard, err := repo.GetShard(ctx, codebaseID)
+	require.NoError(t, err)
+	assert.Equal(t, shards.NodeID("a"), shard.NodeID)
+	assert.Nil(t, shard.MovingTo)
+}
+
+func TestMove_moving(t *testing.T) {
+	ctx := context.Background()
+	repo := db_shards.NewMemory()
+
+	nodeA := newTestNode(t, repo, "a")
+	newTestNode(t, repo, "b")
+
+	codebaseID := codebases.ID("cb")
+	require.NoError(t, nodeA.executor.New().
+		Schedule(vcs_codebases.Create(codebaseID)).
+		ExecTrunk(codebaseID, "createTrunk"))
+
+	claimed, err := repo.ClaimMove(ctx, codebaseID, "a", "b", time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	// only one move can be in progress at a time
+	claimed, err = repo.ClaimMove(ctx, codebaseID, "a", "c", time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.False(t, claimed)
+
+	// no new work is started while moving, it waits for the move to complete
+	timeoutCtx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer cancel()
+	var started bool
+	err = nodeA.executor.New().GitRead(func(vcs.RepoGitReader) error {
+		started = true
+		return nil
+	}).ExecTrunkContext(timeoutCtx, codebaseID, "testMoving")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.False(t, started)
+
+	assert.ErrorIs(t, nodeA.service.Move(ctx, codebaseID, "b"), service_shards.ErrMoving)
+}
+
+func TestMove_stale(t *testing.T) {
+	ctx := context.Background()
+	repo := db_shards.NewMemory()
+
+	nodeA := newTestNode(t, repo, "a")
+	newTestNode(t, repo, "b")
+
+	// a move that was started long ago, and that never completed
+	codebaseID := codebases.ID("cb")
+	movingTo := shards.NodeID("b")
+	startedAt := time.Now().Add(-time.Hour)
+	require.NoError(t, repo.CreateShard(ctx, &shards.Shard{
+		CodebaseID: codebaseID,
+		NodeID:     "a",
+		MovingTo:   &movingTo,
+		CreatedAt:  startedAt,
+		UpdatedAt:  startedAt,
+	}))
+
+	// the move is cancelled, and work is executed on node a
+	require.NoError(t, nodeA.executor.New().
+		Schedule(vcs_codebases.Create(codebaseID)).
+		ExecTrunk(codebaseID, "createTrunk"))
+
+	shard, err := repo.GetShard(ctx, codebaseID)
+	require.NoError(t, err)
+	assert.Equal(t, shards.NodeID("a"), shard.NodeID)
+	assert.Nil(t, shard.MovingTo)
+}
+
+func TestMove_waitsForLease(t *testing.T) {
+	ctx := context.Background()
+	repo := db_shards.NewMemory()
+
+	nodeA := newTestNode(t, repo, "a")
+	nodeB := newTestNode(t, repo, "b")
+
+	codebaseID := codebases.ID("cb")
+	require.NoError(t, nodeA.executor.New().
+		Schedule(vcs_codebases.Create(codebaseID)).
+		ExecTrunk(codebaseID, "createTrunk"))
+
+	// a write is running on node a when the move starts
+	started := make(chan struct{})
+	release := make(chan struct{})
+	writeErr := make(chan error, 1)
+	var newCommitID string
+	go func() {
+		writeErr <- nodeA.executor.New().GitWrite(func(repo vcs.RepoGitWriter) error {
+			close(started)
+			<-release
+			var err error
+			newCommitID, err = repo.CreateCommitWithFiles([]vcs.FileContents{{Path: "README.md", Contents: []byte("hello")}}, "test-branch")
+			return err
+		}).ExecTrunk(codebaseID, "testWrite")
+	}()
+	<-started
+
+	moveErr := make(chan error, 1)
+	go func() {
+		moveErr <- nodeB.service.Move(ctx, codebaseID, "b")
+	}()
+
+	time.Sleep(200 * time.Millisecond)
+	close(release)
+	require.NoError(t, <-writeErr)
+	require.NoError(t, <-moveErr)
+
+	// the write was completed before the repositories were copied
+	branchHead, err := branchCommitID(nodeB.executor, codebaseID, "test-branch")
+	require.NoError(t, err)
+	assert.Equal(t, newCommitID, branchHead)
+}
+
+func TestUnauthorized(t *testing.T) {
+	node := newTestNode(t, db_shards.NewMemory(), "a")
+
+	nodes, err := node.service.ListNodes(context.Background())
+	require.NoError(t, err)
+	require.Len(t, nodes, 1)
+
+	resp, err := http.Post(nodes[0].Addr+"/v1/remove?codebase_id=cb", "", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestStart_missingSecret(t *testing.T) {
+	cfg := &configuration.Configuration{NodeID: "a"}
+	srv := New(cfg, nil, nil, zap.NewNop())
+	assert.ErrorIs(t, srv.Start(context.Background()), ErrMissingSecret)
+}
+
+func TestUnauthorized_emptySecret(t *testing.T) {
+	srv := httptest.NewServer(New(&configuration.Configuration{NodeID: "a"}, nil, nil, zap.NewNop()).Handler())
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/v1/remove?codebase_id=cb", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer ")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	configuration "getsturdy.com/api/pkg/configuration/module"
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/logger"
+	"getsturdy.com/api/pkg/shards/client"
+	db_shards "getsturdy.com/api/pkg/shards/db"
+	shards_executor "getsturdy.com/api/pkg/shards/executor"
+	"getsturdy.com/api/vcs/executor"
+	"getsturdy.com/api/vcs/provider"
+)
+
+func Module(c *di.Container) {
+	c.Import(configuration.Module)
+	c.Import(logger.Module)
+	c.Import(db_shards.Module)
+	c.Import(client.Module)
+	c.Import(shards_executor.Module)
+	c.Import(executor.Module)
+	c.Import(provider.Module)
+	c.Register(New)
+}
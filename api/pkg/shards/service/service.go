@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/shards"
+	"getsturdy.com/api/pkg/shards/client"
+	"getsturdy.com/api/pkg/shards/configuration"
+	db_shards "getsturdy.com/api/pkg/shards/db"
+	shards_executor "getsturdy.com/api/pkg/shards/executor"
+	"getsturdy.com/api/vcs"
+	"getsturdy.com/api/vcs/executor"
+	"getsturdy.com/api/vcs/provider"
+	provider_configuration "getsturdy.com/api/vcs/provider/configuration"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrDisabled    = shards_executor.ErrDisabled
+	ErrMoving      = shards_executor.ErrMoving
+	ErrNotMoving   = errors.New("codebase is not being moved")
+	ErrNotOwner    = errors.New("codebase is not stored on this node")
+	ErrStillOwner  = errors.New("codebase is still stored on this node")
+	ErrUnknownNode = shards_executor.ErrUnknownNode
+	ErrInvalidID   = errors.New("invalid codebase id")
+)
+
+// drainInterval is how often a moving codebase is checked for temporary views that are still in use.
+const drainInterval = 100 * time.Millisecond
+
+type Service struct {
+	repo             db_shards.Repository
+	router           *shards_executor.Router
+	mover            *shards_executor.Mover
+	client           *client.Client
+	executorProvider executor.Provider
+	repoProvider     provider.RepoProvider
+	reposPath        string
+	cfg              *configuration.Configuration
+	logger           *zap.Logger
+}
+
+func New(
+	repo db_shards.Repository,
+	router *shards_executor.Router,
+	mover *shards_executor.Mover,
+	client *client.Client,
+	executorProvider executor.Provider,
+	repoProvider provider.RepoProvider,
+	providerConfiguration *provider_configuration.Configuration,
+	cfg *configuration.Configuration,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		repo:             repo,
+		router:           router,
+		mover:            mover,
+		client:           client,
+		executorProvider: executorProvider,
+		repoProvider:     repoProvider,
+		reposPath:        providerConfiguration.ReposPath,
+		cfg:              cfg,
+		logger:           logger.Named("shardsService"),
+	}
+}
+
+// Register adds this node to the list of nodes, or updates when it was last seen.
+func (s *Service) Register(ctx context.Context) error {
+	if !s.router.Enabled() {
+		return ErrDisabled
+	}
+	if err := s.repo.UpsertNode(ctx, &shards.Node{
+		ID:     s.router.NodeID(),
+		Addr:   s.cfg.NodeURL(),
+		SeenAt: time.Now(),
+	}); err != nil {
+		return fmt.Errorf("failed to register node: %w", err)
+	}
+	return nil
+}
+
+// ClaimLocal assigns all codebases that are stored on this node, and that are not yet assigned to a node, to this
+// node. It's used to adopt the codebases of an installation that did not use sharding before.
+func (s *Service) ClaimLocal(ctx context.Context) error {
+	entries, err := os.ReadDir(s.reposPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to list repositories: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		codebaseID := codebases.ID(entry.Name())
+		shard, err := s.router.Shard(ctx, codebaseID)
+		if err != nil {
+			return fmt.Errorf("failed to claim %s: %w", codebaseID, err)
+		}
+		if shard.NodeID != s.router.NodeID() && shard.MovingTo == nil {
+			s.logger.Warn("codebase is stored on this node, but is assigned to another node",
+				zap.Stringer("codebase_id", codebaseID),
+				zap.Stringer("node_id", shard.NodeID),
+			)
+		}
+	}
+	return nil
+}
+
+func (s *Service) ListNodes(ctx context.Context) ([]*shards.Node, error) {
+	return s.repo.ListNodes(ctx)
+}
+
+// Shard returns the shard of the codebase.
+func (s *Service) Shard(ctx context.Context, codebaseID codebases.ID) (*shards.Shard, error) {
+	return s.router.Shard(ctx, codebaseID)
+}
+
+// Move moves the repositories of the codebase to the node _to_, see shards_executor.Mover.
+func (s *Service) Move(ctx context.Context, codebaseID codebases.ID, to shards.NodeID) error {
+	return s.mover.Move(ctx, codebaseID, to)
+}
+
+// Archive writes an archive of the repositories of a moving codebase to _w_. It waits for all git operations on the
+// repositories to complete, and holds the lease of the codebase while the archive is written.
+//
+// Temporary views are not included, they are recreated on demand.
+func (s *Service) Archive(ctx context.Context, codebaseID codebases.ID, w io.Writer) error {
+	if !isValidID(codebaseID) {
+		return ErrInvalidID
+	}
+
+	shard, err := s.router.Shard(ctx, codebaseID)
+	if err != nil {
+		return err
+	}
+	if shard.NodeID != s.router.NodeID() {
+		return ErrNotOwner
+	}
+	if shard.MovingTo == nil {
+		return ErrNotMoving
+	}
+
+	// wait for the leases of running git operations to be released, and do not let new operations start until the
+	// archive has been written
+	unlock := s.router.LockForMove(codebaseID)
+	defer unlock()
+
+	ctx = shards_executor.ForMove(ctx)
+	codebasePath := s.codebasePath(codebaseID)
+
+	// no new operations are started on the codebase, wait for the running ones to complete
+	for {
+		entries, err := os.ReadDir(codebasePath)
+		if err != nil {
+			return fmt.Errorf("failed to list views: %w", err)
+		}
+		var inUse bool
+		for _, entry := range entries {
+			switch name := entry.Name(); {
+			case name == "trunk":
+			case isTemporaryView(name):
+				inUse = inUse || isInUse(name)
+			default:
+				if err := s.executorProvider.New().
+					AllowRebasingState().
+					Read(func(vcs.RepoReader) error { return nil }).
+					ExecViewContext(ctx, codebaseID, name, "shardsDrainView"); err != nil {
+					return fmt.Errorf("failed to wait for view %s: %w", name, err)
+				}
+			}
+		}
+		if !inUse {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(drainInterval):
+		}
+	}
+
+	if err := s.executorProvider.New().
+		Schedule(func(provider.RepoProvider) error {
+			return writeArchive(w, codebasePath, isTemporaryView)
+		}).
+		ExecTrunkContext(ctx, codebaseID, "shardsArchive"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Pull copies the repositories of a codebase that is moving to this node from the node _from_.
+func (s *Service) Pull(ctx context.Context, codebaseID codebases.ID, from shards.NodeID) error {
+	if !isValidID(codebaseID) {
+		return ErrInvalidID
+	}
+
+	shard, err := s.router.Shard(ctx, codebaseID)
+	if err != nil {
+		return err
+	}
+	if shard.MovingTo == nil || *shard.MovingTo != s.router.NodeID() {
+		return ErrNotMoving
+	}
+
+	source, err := s.repo.GetNode(ctx, from)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUnknownNode, from)
+	}
+
+	archive, err := s.client.Archive(ctx, source, codebaseID)
+	if err != nil {
+		return fmt.Errorf("failed to get archive: %w", err)
+	}
+	defer archive.Close()
+
+	codebasePath := s.codebasePath(codebaseID)
+	tmpPath := fmt.Sprintf("%s.pull-%s", codebasePath, uuid.NewString())
+	if err := extractArchive(archive, tmpPath); err != nil {
+		_ = os.RemoveAll(tmpPath)
+		return fmt.Errorf("failed to extract archive: %w", err)
+	}
+
+	// remove any leftovers from a previous copy of the codebase
+	if err := os.RemoveAll(codebasePath); err != nil {
+		_ = os.RemoveAll(tmpPath)
+		return fmt.Errorf("failed to remove old repositories: %w", err)
+	}
+	if err := os.Rename(tmpPath, codebasePath); err != nil {
+		_ = os.RemoveAll(tmpPath)
+		return fmt.Errorf("failed to move repositories in place: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes the local copy of the repositories of a codebase that has been moved to another node.
+func (s *Service) Remove(ctx context.Context, codebaseID codebases.ID) error {
+	if !isValidID(codebaseID) {
+		return ErrInvalidID
+	}
+
+	shard, err := s.router.Shard(ctx, codebaseID)
+	if err != nil {
+		return err
+	}
+	if shard.NodeID == s.router.NodeID() {
+		return ErrStillOwner
+	}
+	if err := os.RemoveAll(s.codebasePath(codebaseID)); err != nil {
+		return fmt.Errorf("failed to remove repositories: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) codebasePath(codebaseID codebases.ID) string {
+	return filepath.Dir(s.repoProvider.TrunkPath(codebaseID))
+}
+
+// isValidID returns true if _codebaseID_ can safely be used as a path in the repositories directory.
+func isValidID(codebaseID codebases.ID) bool {
+	id := codebaseID.String()
+	return id != "" && id != "." && id != ".." && filepath.Base(id) == id
+}
+
+// temporary views are named "tmp-<uuid>", and are prefixed with "using-" while in use by the executor
+func isTemporaryView(name string) bool {
+	return strings.HasPrefix(strings.TrimPrefix(name, "using-"), "tmp-")
+}
+
+func isInUse(name string) bool {
+	return strings.HasPrefix(name, "using-")
+}
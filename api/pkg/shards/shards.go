@@ -0,0 +1,53 @@
+package shards
+
+import (
+	"fmt"
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+)
+
+// NodeID is the id of an API node that stores repositories.
+type NodeID string
+
+func (id NodeID) String() string {
+	return string(id)
+}
+
+// Node is an API node that stores repositories on its local disk, and that accepts requests from other nodes on Addr.
+type Node struct {
+	ID     NodeID    `db:"id"`
+	Addr   string    `db:"addr"`
+	SeenAt time.Time `db:"seen_at"`
+}
+
+// Shard is the assignment of a codebase to the node that stores its repositories.
+type Shard struct {
+	CodebaseID codebases.ID `db:"codebase_id"`
+	NodeID     NodeID       `db:"node_id"`
+	// MovingTo is set while the codebase is being moved to another node. No new git operations are started on the
+	// codebase while it is moving.
+	MovingTo  *NodeID   `db:"moving_to"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// NotOwnerError is returned when git operations on a codebase are executed on a node that does not store the
+// repositories of the codebase.
+type NotOwnerError struct {
+	CodebaseID codebases.ID
+	Owner      NodeID
+}
+
+func (e *NotOwnerError) Error() string {
+	return fmt.Sprintf("codebase %s is stored on node %s", e.CodebaseID, e.Owner)
+}
+
+// MovingError is returned when git operations are executed on a codebase that is being moved between nodes.
+type MovingError struct {
+	CodebaseID codebases.ID
+}
+
+func (e *MovingError) Error() string {
+	return fmt.Sprintf("codebase %s is being moved to another node", e.CodebaseID)
+}
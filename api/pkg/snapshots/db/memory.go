@@ -3,7 +3,9 @@ package db
 import (
 	"context"
 	"database/sql"
+	"sort"
 
+	"getsturdy.com/api/pkg/codebases"
 	"getsturdy.com/api/pkg/snapshots"
 )
 
@@ -59,6 +61,22 @@ func (f *snapshotRepo) ListByIDs(ctx context.Context, ids []snapshots.ID) ([]*sn
 	return res, nil
 }
 
+func (f *snapshotRepo) ListByWorkspaceID(_ context.Context, workspaceID string, limit int) ([]*snapshots.Snapshot, error) {
+	res := []*snapshots.Snapshot{}
+	for _, snap := range f.byID {
+		if snap.WorkspaceID == workspaceID && !snap.IsDeleted() {
+			res = append(res, snap)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].CreatedAt.After(res[j].CreatedAt)
+	})
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
+
 func (r *snapshotRepo) GetByPreviousSnapshotID(ctx context.Context, previousSnapshotID snapshots.ID) (*snapshots.Snapshot, error) {
 	for _, snap := range r.byID {
 		if snap.PreviousSnapshotID != nil && *snap.PreviousSnapshotID == previousSnapshotID {
@@ -67,3 +85,17 @@ func (r *snapshotRepo) GetByPreviousSnapshotID(ctx context.Context, previousSnap
 	}
 	return nil, sql.ErrNoRows
 }
+
+func (f *snapshotRepo) DeleteByCodebaseID(_ context.Context, codebaseID codebases.ID) error {
+	for id, snap := range f.byID {
+		if snap.CodebaseID == codebaseID {
+			delete(f.byID, id)
+		}
+	}
+	for workspaceID, snap := range f.latestInWorkspace {
+		if snap.CodebaseID == codebaseID {
+			delete(f.latestInWorkspace, workspaceID)
+		}
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"getsturdy.com/api/pkg/codebases"
 	"getsturdy.com/api/pkg/snapshots"
 
 	"github.com/jmoiron/sqlx"
@@ -16,8 +17,10 @@ type Repository interface {
 	GetByCommitSHA(context.Context, string) (*snapshots.Snapshot, error)
 	GetByPreviousSnapshotID(context.Context, snapshots.ID) (*snapshots.Snapshot, error)
 	ListByIDs(context.Context, []snapshots.ID) ([]*snapshots.Snapshot, error)
+	ListByWorkspaceID(ctx context.Context, workspaceID string, limit int) ([]*snapshots.Snapshot, error)
 	Get(snapshots.ID) (*snapshots.Snapshot, error)
 	Update(*snapshots.Snapshot) error
+	DeleteByCodebaseID(context.Context, codebases.ID) error
 }
 
 type dbrepo struct {
@@ -100,6 +103,21 @@ func (r *dbrepo) ListByIDs(ctx context.Context, ids []snapshots.ID) ([]*snapshot
 	return res, nil
 }
 
+// ListByWorkspaceID returns the latest snapshots in the workspace, newest first. Deleted snapshots are not returned.
+func (r *dbrepo) ListByWorkspaceID(ctx context.Context, workspaceID string, limit int) ([]*snapshots.Snapshot, error) {
+	var res []*snapshots.Snapshot
+	if err := r.db.SelectContext(ctx, &res, `SELECT id, created_at, previous_snapshot_id, codebase_id, commit_id, workspace_id,  action, diffs_count
+		FROM snapshots
+		WHERE workspace_id = $1
+		AND deleted_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT $2
+	`, workspaceID, limit); err != nil {
+		return nil, fmt.Errorf("failed to list by workspace id: %w", err)
+	}
+	return res, nil
+}
+
 func (r *dbrepo) GetByPreviousSnapshotID(ctx context.Context, previousSnapshotID snapshots.ID) (*snapshots.Snapshot, error) {
 	var res snapshots.Snapshot
 	if err := r.db.GetContext(ctx, &res, `SELECT id, created_at, previous_snapshot_id, codebase_id, commit_id, workspace_id,  action, diffs_count
@@ -113,3 +131,10 @@ func (r *dbrepo) GetByPreviousSnapshotID(ctx context.Context, previousSnapshotID
 	}
 	return &res, nil
 }
+
+func (r *dbrepo) DeleteByCodebaseID(ctx context.Context, codebaseID codebases.ID) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM snapshots WHERE codebase_id = $1`, codebaseID); err != nil {
+		return fmt.Errorf("failed to delete snapshots: %w", err)
+	}
+	return nil
+}
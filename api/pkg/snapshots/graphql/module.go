@@ -1,11 +1,19 @@
 package graphql
 
 import (
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	graphql_author "getsturdy.com/api/pkg/author/graphql"
+	graphql_changes "getsturdy.com/api/pkg/changes/graphql"
 	"getsturdy.com/api/pkg/di"
 	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
+	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
 )
 
 func Module(c *di.Container) {
 	c.Import(service_snapshots.Module)
+	c.Import(service_workspaces.Module)
+	c.Import(service_auth.Module)
+	c.Import(graphql_author.Module)
+	c.Import(graphql_changes.Module)
 	c.Register(NewRoot)
 }
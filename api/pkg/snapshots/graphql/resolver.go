@@ -4,10 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"sync"
 
 	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
 	"getsturdy.com/api/pkg/graphql/resolvers"
 	"getsturdy.com/api/pkg/snapshots"
+	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
+	"getsturdy.com/api/pkg/unidiff"
 
 	"github.com/graph-gophers/graphql-go"
 )
@@ -17,6 +21,10 @@ var _ resolvers.SnapshotResolver = (*resolver)(nil)
 type resolver struct {
 	root     *rootResolver
 	snapshot *snapshots.Snapshot
+
+	summary     *service_snapshots.Summary
+	summaryErr  error
+	summaryOnce sync.Once
 }
 
 func (r *resolver) ID() graphql.ID {
@@ -61,11 +69,113 @@ func (r *resolver) Description(context.Context) (*string, error) {
 		return p("undo patch"), nil
 	case snapshots.ActionSuggestionApply:
 		return p("suggestion apply"), nil
+	case snapshots.ActionSnapshotRestore:
+		return p("restore snapshot"), nil
 	default:
 		return nil, nil
 	}
 }
 
+func (r *resolver) Action() string {
+	return r.snapshot.Action.String()
+}
+
+// getSummary returns the author and the diff stats of the snapshot, they are read from git together.
+func (r *resolver) getSummary(ctx context.Context) (*service_snapshots.Summary, error) {
+	r.summaryOnce.Do(func() {
+		ws, err := r.root.workspaceService.GetByID(ctx, r.snapshot.WorkspaceID)
+		if err != nil {
+			r.summaryErr = err
+			return
+		}
+
+		allower, err := r.root.authService.GetAllower(ctx, ws)
+		if err != nil {
+			r.summaryErr = fmt.Errorf("failed to get allowed patterns: %w", err)
+			return
+		}
+
+		r.summary, r.summaryErr = r.root.snapshotService.Summary(ctx, r.snapshot, service_snapshots.WithAllower(allower))
+	})
+	return r.summary, r.summaryErr
+}
+
+func (r *resolver) Author(ctx context.Context) (resolvers.AuthorResolver, error) {
+	summary, err := r.getSummary(ctx)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+	return r.root.authorRootResolver.InternalAuthorFromNameAndEmail(ctx, summary.Author.Name, summary.Author.Email), nil
+}
+
+func (r *resolver) DiffsCount() *int32 {
+	return r.snapshot.DiffsCount
+}
+
+func (r *resolver) DiffStats(ctx context.Context) (resolvers.SnapshotDiffStatsResolver, error) {
+	summary, err := r.getSummary(ctx)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+	return &diffStatsResolver{stats: &summary.Stats}, nil
+}
+
+func (r *resolver) Diffs(ctx context.Context, args resolvers.SnapshotDiffsArgs) ([]resolvers.FileDiffResolver, error) {
+	ws, err := r.root.workspaceService.GetByID(ctx, r.snapshot.WorkspaceID)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	allower, err := r.root.authService.GetAllower(ctx, ws)
+	if err != nil {
+		return nil, gqlerrors.Error(fmt.Errorf("failed to get allowed patterns: %w", err))
+	}
+
+	keyPrefix := string(r.snapshot.ID)
+	var diffs []unidiff.FileDiff
+	if args.CompareTo != nil {
+		compareTo, err := r.root.snapshotService.GetByID(ctx, snapshots.ID(*args.CompareTo))
+		if err != nil {
+			return nil, gqlerrors.Error(err)
+		}
+		if compareTo.WorkspaceID != r.snapshot.WorkspaceID {
+			return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "compareTo", "snapshot is not in the same workspace")
+		}
+		keyPrefix = fmt.Sprintf("%s-%s", compareTo.ID, r.snapshot.ID)
+		diffs, err = r.root.snapshotService.DiffBetween(ctx, compareTo, r.snapshot, service_snapshots.WithAllower(allower))
+		if err != nil {
+			return nil, gqlerrors.Error(err)
+		}
+	} else {
+		diffs, err = r.root.snapshotService.Diffs(ctx, r.snapshot.ID, service_snapshots.WithAllower(allower))
+		if err != nil {
+			return nil, gqlerrors.Error(err)
+		}
+	}
+
+	res := make([]resolvers.FileDiffResolver, len(diffs))
+	for k, diff := range diffs {
+		res[k] = r.root.fileDiffRootResolver.InternalFileDiffWithWorkspace(keyPrefix, &diff, ws)
+	}
+	return res, nil
+}
+
+type diffStatsResolver struct {
+	stats *service_snapshots.DiffStats
+}
+
+func (r *diffStatsResolver) FilesChanged() int32 {
+	return int32(r.stats.FilesChanged)
+}
+
+func (r *diffStatsResolver) Insertions() int32 {
+	return int32(r.stats.Insertions)
+}
+
+func (r *diffStatsResolver) Deletions() int32 {
+	return int32(r.stats.Deletions)
+}
+
 func p[T any](v T) *T {
 	return &v
 }
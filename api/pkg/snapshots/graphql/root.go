@@ -3,21 +3,38 @@ package graphql
 import (
 	"context"
 
+	service_auth "getsturdy.com/api/pkg/auth/service"
 	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
 	"getsturdy.com/api/pkg/graphql/resolvers"
 	"getsturdy.com/api/pkg/snapshots"
 	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
+	service_workspaces "getsturdy.com/api/pkg/workspaces/service"
 )
 
 type rootResolver struct {
-	snapshotService *service_snapshots.Service
+	snapshotService  *service_snapshots.Service
+	workspaceService *service_workspaces.Service
+	authService      *service_auth.Service
+
+	authorRootResolver   resolvers.AuthorRootResolver
+	fileDiffRootResolver resolvers.FileDiffRootResolver
 }
 
 func NewRoot(
 	snapshotService *service_snapshots.Service,
+	workspaceService *service_workspaces.Service,
+	authService *service_auth.Service,
+
+	authorRootResolver resolvers.AuthorRootResolver,
+	fileDiffRootResolver resolvers.FileDiffRootResolver,
 ) resolvers.SnapshotsRootResolver {
 	return &rootResolver{
-		snapshotService: snapshotService,
+		snapshotService:  snapshotService,
+		workspaceService: workspaceService,
+		authService:      authService,
+
+		authorRootResolver:   authorRootResolver,
+		fileDiffRootResolver: fileDiffRootResolver,
 	}
 }
 
@@ -31,3 +48,18 @@ func (r *rootResolver) InternalByID(ctx context.Context, id snapshots.ID) (resol
 		root:     r,
 	}, nil
 }
+
+func (r *rootResolver) InternalListByWorkspaceID(ctx context.Context, workspaceID string, limit int) ([]resolvers.SnapshotResolver, error) {
+	snaps, err := r.snapshotService.ListByWorkspaceID(ctx, workspaceID, limit)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+	res := make([]resolvers.SnapshotResolver, 0, len(snaps))
+	for _, snap := range snaps {
+		res = append(res, &resolver{
+			snapshot: snap,
+			root:     r,
+		})
+	}
+	return res, nil
+}
@@ -176,6 +176,11 @@ func (s *Service) Next(ctx context.Context, snapshot *snapshots.Snapshot) (*snap
 	return s.snapshotsRepo.GetByPreviousSnapshotID(ctx, snapshot.ID)
 }
 
+// ListByWorkspaceID returns up to limit snapshots of the workspace, newest first.
+func (s *Service) ListByWorkspaceID(ctx context.Context, workspaceID string, limit int) ([]*snapshots.Snapshot, error) {
+	return s.snapshotsRepo.ListByWorkspaceID(ctx, workspaceID, limit)
+}
+
 var (
 	ErrCantSnapshotRebasing    = errors.New("can't snapshot, rebasing in progress")
 	ErrCantSnapshotWrongBranch = errors.New("can't snapshot, unexpected branch")
@@ -411,9 +416,9 @@ func (s *Service) Snapshot(ctx context.Context, codebaseID codebases.ID, workspa
 
 		var err error
 		if options.onTemporaryView {
-			err = exec.Write(vcs_view.CheckoutBranch(workspaceID)).ExecTemporaryView(codebaseID, "snapshotOnTemporaryView")
+			err = exec.Write(vcs_view.CheckoutBranch(workspaceID)).ExecTemporaryViewContext(ctx, codebaseID, "snapshotOnTemporaryView")
 		} else {
-			err = exec.ExecView(codebaseID, *options.onView, "snapshotOnView")
+			err = exec.ExecViewContext(ctx, codebaseID, *options.onView, "snapshotOnView")
 		}
 
 		if errors.Is(err, executor.ErrUnexpectedBranch) {
@@ -469,6 +474,11 @@ func (s *Service) Snapshot(ctx context.Context, codebaseID codebases.ID, workspa
 				// do not fail
 			}
 
+			if err := s.eventsSenderV2.WorkspaceUpdatedSnapshot(ctx, eventsv2.Workspace(ws.ID), ws); err != nil {
+				s.logger.Error("failed to send workspace updated snapshot event", zap.Error(err))
+				// do not fail
+			}
+
 			// mark all snapshots as stale
 			if err := s.statusesService.NotifyAllInWorkspace(ctx, ws.ID); err != nil {
 				s.logger.Error("failed to notify statuses", zap.Error(err))
@@ -579,41 +589,151 @@ func (s *Service) diffs(ctx context.Context, snapshot *snapshots.Snapshot, oo ..
 
 	var diffs []unidiff.FileDiff
 	if err := s.executorProvider.New().GitRead(func(repo vcs.RepoGitReader) error {
-		snapParent, err := repo.GetCommitParents(snapshot.CommitSHA)
+		snapParent, err := snapshotParent(repo, snapshot)
 		if err != nil {
-			return fmt.Errorf("failed to get commit parents: %w", err)
-		}
-		if len(snapParent) != 1 {
-			return fmt.Errorf("unexpected number of snapshot parents: %d, expected %d", len(snapParent), 1)
+			return err
 		}
 
-		gitDiffs, err := repo.DiffCommits(snapParent[0], snapshot.CommitSHA)
+		diffs, err = s.decorateDiffs(repo, snapParent, snapshot.CommitSHA, options)
+		return err
+	}).ExecTrunkContext(ctx, snapshot.CodebaseID, "snapshotDiffs"); err != nil {
+		return nil, fmt.Errorf("failed to get diffs from snapshot: %w", err)
+	}
+	return diffs, nil
+}
+
+// DiffBetween returns the diffs that turn the content of the from snapshot into the content of the to snapshot.
+func (s *Service) DiffBetween(ctx context.Context, from, to *snapshots.Snapshot, oo ...DiffsOption) ([]unidiff.FileDiff, error) {
+	if from.CodebaseID != to.CodebaseID {
+		return nil, errors.New("snapshots are in different codebases")
+	}
+
+	options := getDiffOptions(oo...)
+
+	var diffs []unidiff.FileDiff
+	if err := s.executorProvider.New().GitRead(func(repo vcs.RepoGitReader) error {
+		var err error
+		diffs, err = s.decorateDiffs(repo, from.CommitSHA, to.CommitSHA, options)
+		return err
+	}).ExecTrunkContext(ctx, to.CodebaseID, "snapshotDiffBetween"); err != nil {
+		return nil, fmt.Errorf("failed to get diffs between snapshots: %w", err)
+	}
+	return diffs, nil
+}
+
+func snapshotParent(repo vcs.RepoGitReader, snapshot *snapshots.Snapshot) (string, error) {
+	snapParent, err := repo.GetCommitParents(snapshot.CommitSHA)
+	if err != nil {
+		return "", fmt.Errorf("failed to get commit parents: %w", err)
+	}
+	if len(snapParent) != 1 {
+		return "", fmt.Errorf("unexpected number of snapshot parents: %d, expected %d", len(snapParent), 1)
+	}
+	return snapParent[0], nil
+}
+
+func (s *Service) decorateDiffs(repo vcs.RepoGitReader, fromCommitSHA, toCommitSHA string, options *DiffsOptions) ([]unidiff.FileDiff, error) {
+	gitDiffs, err := repo.DiffCommits(fromCommitSHA, toCommitSHA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get git diffs: %w", err)
+	}
+	defer gitDiffs.Free()
+
+	differ := unidiff.NewUnidiff(unidiff.NewGitPatchReader(gitDiffs), s.logger).
+		WithExpandedHunks()
+
+	if options.Allower != nil {
+		differ = differ.WithAllower(options.Allower)
+	}
+
+	if options.PatchIDs != nil {
+		differ = differ.WithHunksFilter(*options.PatchIDs...)
+	}
+
+	hunkifiedDiff, err := differ.Decorate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decorate diffs: %w", err)
+	}
+	return hunkifiedDiff, nil
+}
+
+// DiffStats is a summary of the diffs in a snapshot.
+type DiffStats struct {
+	FilesChanged int
+	Insertions   int
+	Deletions    int
+}
+
+// Summary is the author and the diff stats of a snapshot.
+type Summary struct {
+	Author *git.Signature
+	Stats  DiffStats
+}
+
+// Summary returns the git signature of the snapshot commit, and a summary of the diffs in the snapshot. Snapshots that
+// were not made on behalf of a user are authored by Sturdy. Files that are not allowed by the allower (see
+// WithAllower) are not counted.
+func (s *Service) Summary(ctx context.Context, snapshot *snapshots.Snapshot, oo ...DiffsOption) (*Summary, error) {
+	options := getDiffOptions(oo...)
+
+	var summary Summary
+	if err := s.executorProvider.New().GitRead(func(repo vcs.RepoGitReader) error {
+		author, _, err := repo.CommitMessage(snapshot.CommitSHA)
 		if err != nil {
-			return fmt.Errorf("failed to get git diffs: %w", err)
+			return fmt.Errorf("failed to get commit: %w", err)
 		}
-		defer gitDiffs.Free()
-
-		differ := unidiff.NewUnidiff(unidiff.NewGitPatchReader(gitDiffs), s.logger).
-			WithExpandedHunks()
+		summary.Author = author
 
-		if options.Allower != nil {
-			differ = differ.WithAllower(options.Allower)
+		snapParent, err := snapshotParent(repo, snapshot)
+		if err != nil {
+			return err
 		}
 
-		if options.PatchIDs != nil {
-			differ = differ.WithHunksFilter(*options.PatchIDs...)
+		gitDiffs, err := repo.DiffCommits(snapParent, snapshot.CommitSHA)
+		if err != nil {
+			return fmt.Errorf("failed to get git diffs: %w", err)
 		}
+		defer gitDiffs.Free()
 
-		hunkifiedDiff, err := differ.Decorate()
+		summary.Stats, err = diffStats(gitDiffs, options.Allower)
 		if err != nil {
-			return fmt.Errorf("failed to decorate diffs: %w", err)
+			return fmt.Errorf("failed to get diff stats: %w", err)
+		}
+		return nil
+	}).ExecTrunkContext(ctx, snapshot.CodebaseID, "snapshotSummary"); err != nil {
+		return nil, fmt.Errorf("failed to get summary of snapshot: %w", err)
+	}
+	return &summary, nil
+}
+
+// diffStats counts the changed files and lines in the diff. Files that are not allowed by the allower are skipped.
+func diffStats(gitDiffs *git.Diff, allower *unidiff.Allower) (DiffStats, error) {
+	var stats DiffStats
+	skipLine := func(git.DiffLine) error { return nil }
+	countLine := func(line git.DiffLine) error {
+		switch line.Origin {
+		case git.DiffLineAddition:
+			stats.Insertions++
+		case git.DiffLineDeletion:
+			stats.Deletions++
 		}
-		diffs = hunkifiedDiff
 		return nil
-	}).ExecTrunk(snapshot.CodebaseID, "snapshotDiffs"); err != nil {
-		return nil, fmt.Errorf("failed to get diffs from snapshot: %w", err)
 	}
-	return diffs, nil
+
+	if err := gitDiffs.ForEach(func(delta git.DiffDelta, _ float64) (git.DiffForEachHunkCallback, error) {
+		path := delta.NewFile.Path
+		if delta.Status == git.DeltaDeleted {
+			path = delta.OldFile.Path
+		}
+		if allower != nil && !allower.IsAllowed(path, false) {
+			return func(git.DiffHunk) (git.DiffForEachLineCallback, error) { return skipLine, nil }, nil
+		}
+		stats.FilesChanged++
+		return func(git.DiffHunk) (git.DiffForEachLineCallback, error) { return countLine, nil }, nil
+	}, git.DiffDetailLines); err != nil {
+		return DiffStats{}, err
+	}
+	return stats, nil
 }
 
 type CopyOptions struct {
@@ -704,7 +824,7 @@ func (s *Service) Copy(ctx context.Context, snapshotID snapshots.ID, oo ...CopyO
 			}
 			newSnapshot.CommitSHA = commitID
 			return nil
-		}).ExecTemporaryView(snapshot.CodebaseID, "copySnapshot"); err != nil {
+		}).ExecTemporaryViewContext(ctx, snapshot.CodebaseID, "copySnapshot"); err != nil {
 		return nil, fmt.Errorf("failed to copy snapshot: %w", err)
 	}
 
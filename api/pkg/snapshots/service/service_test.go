@@ -19,6 +19,7 @@ import (
 	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
 	db_statuses "getsturdy.com/api/pkg/statuses/db"
 	db_suggestions "getsturdy.com/api/pkg/suggestions/db"
+	"getsturdy.com/api/pkg/unidiff"
 	"getsturdy.com/api/pkg/users"
 	db_view "getsturdy.com/api/pkg/views/db"
 	service_view "getsturdy.com/api/pkg/views/service"
@@ -213,3 +214,33 @@ func writeFile(filename string, content []byte) func(vcs.RepoWriter) error {
 		return nil
 	}
 }
+
+func TestSnapshot_summary(t *testing.T) {
+	tc := setup(t)
+
+	assert.NoError(t, tc.executorProvider.New().
+		Write(func(repo vcs.RepoWriter) error {
+			if err := writeFile("visible.txt", []byte("one\ntwo\n"))(repo); err != nil {
+				return err
+			}
+			return writeFile("hidden.txt", []byte("secret\n"))(repo)
+		}).
+		ExecView(tc.codebaseID, tc.viewID, "make some changes"))
+
+	snap, err := tc.snapshotService.Snapshot(context.Background(), tc.codebaseID, tc.workspaceID, snapshots.ActionViewSync, service_snapshots.WithOnView(tc.viewID))
+	assert.NoError(t, err)
+
+	summary, err := tc.snapshotService.Summary(context.Background(), snap)
+	if assert.NoError(t, err) {
+		assert.NotNil(t, summary.Author)
+		assert.Equal(t, service_snapshots.DiffStats{FilesChanged: 2, Insertions: 3}, summary.Stats)
+	}
+
+	allower, err := unidiff.NewAllower("visible.txt")
+	assert.NoError(t, err)
+
+	summary, err = tc.snapshotService.Summary(context.Background(), snap, service_snapshots.WithAllower(allower))
+	if assert.NoError(t, err) {
+		assert.Equal(t, service_snapshots.DiffStats{FilesChanged: 1, Insertions: 2}, summary.Stats)
+	}
+}
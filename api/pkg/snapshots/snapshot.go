@@ -62,4 +62,8 @@ const (
 	ActionChangeReverted            Action = "change_reverted"
 	ActionSuggestionApply           Action = "suggestion_apply"
 	ActionCITrigger                 Action = "ci_trigger"
+	ActionMergeQueue                Action = "merge_queue"
+	ActionGitPush                   Action = "git_push"
+	ActionSnapshotRestore           Action = "snapshot_restore"
+	ActionPreSnapshotRestore        Action = "pre_snapshot_restore"
 )
@@ -1,6 +1,7 @@
 package worker
 
 import (
+	service_codeowners "getsturdy.com/api/pkg/codeowners/service"
 	"getsturdy.com/api/pkg/di"
 	"getsturdy.com/api/pkg/logger"
 	queue "getsturdy.com/api/pkg/queue/module"
@@ -13,5 +14,6 @@ func Module(c *di.Container) {
 	c.Import(queue.Module)
 	c.Import(service_snapshots.Module)
 	c.Import(service_users.Module)
+	c.Import(service_codeowners.Module)
 	c.Register(New)
 }
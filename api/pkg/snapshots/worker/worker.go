@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"getsturdy.com/api/pkg/codebases"
+	service_codeowners "getsturdy.com/api/pkg/codeowners/service"
 	"getsturdy.com/api/pkg/queue"
 	"getsturdy.com/api/pkg/queue/names"
 	"getsturdy.com/api/pkg/snapshots"
@@ -27,8 +28,9 @@ type q struct {
 	queue  queue.Queue
 	name   names.IncompleteQueueName
 
-	snapshotter *service_snapshots.Service
-	userService service_users.Service
+	snapshotter       *service_snapshots.Service
+	userService       service_users.Service
+	codeOwnersService *service_codeowners.Service
 }
 
 func New(
@@ -36,13 +38,15 @@ func New(
 	queue queue.Queue,
 	snapshotter *service_snapshots.Service,
 	userService service_users.Service,
+	codeOwnersService *service_codeowners.Service,
 ) Queue {
 	return &q{
-		logger:      logger.Named("snapshotterQueue"),
-		queue:       queue,
-		name:        names.ViewSnapshot,
-		snapshotter: snapshotter,
-		userService: userService,
+		logger:            logger.Named("snapshotterQueue"),
+		queue:             queue,
+		name:              names.ViewSnapshot,
+		snapshotter:       snapshotter,
+		userService:       userService,
+		codeOwnersService: codeOwnersService,
 	}
 }
 
@@ -127,6 +131,11 @@ func (q *q) Start(ctx context.Context) error {
 				continue
 			}
 
+			if err := q.codeOwnersService.Notify(ctx, m.WorkspaceID); err != nil {
+				logger.Error("failed to notify code owners", zap.Error(err))
+				// don't fail
+			}
+
 			if err := msg.Ack(); err != nil {
 				logger.Error("failed to ack message", zap.Error(err))
 				continue
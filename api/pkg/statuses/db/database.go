@@ -144,3 +144,10 @@ func (r *repository) ListByCodebaseIDAndCommitID(ctx context.Context, codebaseID
 	}
 	return ss, nil
 }
+
+func (r *repository) DeleteByCodebaseID(ctx context.Context, codebaseID codebases.ID) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM statuses WHERE codebase_id = $1`, codebaseID); err != nil {
+		return fmt.Errorf("failed to delete statuses: %w", err)
+	}
+	return nil
+}
@@ -38,6 +38,20 @@ func (m *memory) ListByWorkspaceID(ctx context.Context, workspaceID string) ([]*
 }
 
 func (m *memory) ListByCodebaseIDAndCommitID(ctx context.Context, codebaseID codebases.ID, commitID string) ([]*statuses.Status, error) {
-	// todo: implement
-	return nil, nil
+	var ss []*statuses.Status
+	for _, status := range m.byID {
+		if status.CodebaseID == codebaseID && status.CommitSHA == commitID {
+			ss = append(ss, status)
+		}
+	}
+	return ss, nil
+}
+
+func (m *memory) DeleteByCodebaseID(_ context.Context, codebaseID codebases.ID) error {
+	for id, status := range m.byID {
+		if status.CodebaseID == codebaseID {
+			delete(m.byID, id)
+		}
+	}
+	return nil
 }
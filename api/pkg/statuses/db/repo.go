@@ -12,4 +12,5 @@ type Repository interface {
 	Get(ctx context.Context, id string) (*statuses.Status, error)
 	ListByWorkspaceID(ctx context.Context, workspaceID string) ([]*statuses.Status, error)
 	ListByCodebaseIDAndCommitID(ctx context.Context, codebaseID codebases.ID, commitID string) ([]*statuses.Status, error)
+	DeleteByCodebaseID(context.Context, codebases.ID) error
 }
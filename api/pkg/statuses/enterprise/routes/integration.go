@@ -0,0 +1,50 @@
+package routes
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+
+	"getsturdy.com/api/pkg/ci"
+	service_ci "getsturdy.com/api/pkg/ci/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// IntegrationWebhookHandler handles webhooks that are sent to a specific integration, the integration's provider
+// is used to parse the status from the request.
+func IntegrationWebhookHandler(
+	logger *zap.Logger,
+	ciService *service_ci.Service,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		integrationID := c.Param("id")
+		logger := logger.With(zap.String("integration_id", integrationID))
+
+		requestBody, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			logger.Error("failed to read body", zap.Error(err))
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		status, err := ciService.HandleWebhook(c.Request.Context(), integrationID, c.Request.Header, requestBody)
+		switch {
+		case errors.Is(err, ci.ErrIgnoredWebhook):
+			c.Status(http.StatusOK)
+			return
+		case errors.Is(err, sql.ErrNoRows):
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		case err != nil:
+			logger.Error("failed to handle webhook", zap.Error(err))
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		logger.Info("got webhook", zap.Stringer("codebase_id", status.CodebaseID), zap.String("commit_sha", status.CommitSHA))
+		c.Status(http.StatusOK)
+	}
+}
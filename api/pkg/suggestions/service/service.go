@@ -281,13 +281,13 @@ func (s *Service) ApplyHunks(ctx context.Context, suggestion *suggestions.Sugges
 				}
 
 				return nil
-			}).ExecTemporaryView(originalWorkspace.CodebaseID, "applySuggestionDiffs"); err != nil {
+			}).ExecTemporaryViewContext(ctx, originalWorkspace.CodebaseID, "applySuggestionDiffs"); err != nil {
 			return fmt.Errorf("failed to apply patches: %w", err)
 		}
 	} else { // apply to the view
 		if err := s.executorProvider.New().Write(func(repo vcs.RepoWriter) error {
 			return repo.ApplyPatchesToWorkdir(patches)
-		}).ExecView(originalWorkspace.CodebaseID, *originalWorkspace.ViewID, "applySuggestionDiffs"); err != nil {
+		}).ExecViewContext(ctx, originalWorkspace.CodebaseID, *originalWorkspace.ViewID, "applySuggestionDiffs"); err != nil {
 			return fmt.Errorf("failed to apply patches: %w", err)
 		}
 	}
@@ -391,7 +391,7 @@ func (s *Service) RemovePatches(ctx context.Context, suggestion *suggestions.Sug
 				return fmt.Errorf("failed to snapshot: %w", err)
 			}
 			return nil
-		}).ExecView(workspace.CodebaseID, *workspace.ViewID, "removeSuggestionPatches"); err != nil {
+		}).ExecViewContext(ctx, workspace.CodebaseID, *workspace.ViewID, "removeSuggestionPatches"); err != nil {
 			return fmt.Errorf("failed to apply patches: %w", err)
 		}
 		return nil
@@ -421,7 +421,7 @@ func (s *Service) RemovePatches(ctx context.Context, suggestion *suggestions.Sug
 				}
 
 				return nil
-			}).ExecTemporaryView(workspace.CodebaseID, "removeSuggestionPatches"); err != nil {
+			}).ExecTemporaryViewContext(ctx, workspace.CodebaseID, "removeSuggestionPatches"); err != nil {
 			return fmt.Errorf("failed to apply patches: %w", err)
 		}
 		return nil
@@ -481,7 +481,7 @@ func (s *Service) diffs(
 		}
 		diffs = hunkifiedDiffs
 		return nil
-	}).ExecTrunk(suggestingWorkspace.CodebaseID, "snapshotDiffs"); err != nil {
+	}).ExecTrunkContext(ctx, suggestingWorkspace.CodebaseID, "snapshotDiffs"); err != nil {
 		return nil, fmt.Errorf("failed to schedule repo on trunk: %w", err)
 	}
 
@@ -541,7 +541,7 @@ func (s *Service) diffs(
 			}
 		}
 		return nil
-	}).ExecView(originalWorkspace.CodebaseID, *originalWorkspace.ViewID, "calculateOutdatedDiffs"); err != nil {
+	}).ExecViewContext(ctx, originalWorkspace.CodebaseID, *originalWorkspace.ViewID, "calculateOutdatedDiffs"); err != nil {
 		return nil, fmt.Errorf("failed to calculate outdated diffs: %w", err)
 	}
 
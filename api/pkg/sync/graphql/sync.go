@@ -60,7 +60,7 @@ func (r *rootResolver) InternalWorkspaceRebaseStatus(ctx context.Context, worksp
 			}
 			status = rebaseStatus
 			return nil
-		}).ExecView(workspace.CodebaseID, *workspace.ViewID, "rebaseStatus"); err != nil {
+		}).ExecViewContext(ctx, workspace.CodebaseID, *workspace.ViewID, "rebaseStatus"); err != nil {
 		return nil, gqlerror.Error(fmt.Errorf("failed to get status: %w", err))
 	}
 	return &resolver{
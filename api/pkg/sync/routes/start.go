@@ -40,8 +40,8 @@ func StartV2(
 			return
 		}
 
-		if status, err := syncService.OnTrunk(c.Request.Context(), workspace); err != nil {
-			logger.Error("failed to sync on trunk", zap.Error(err))
+		if status, err := syncService.Sync(c.Request.Context(), workspace); err != nil {
+			logger.Error("failed to sync", zap.Error(err))
 			c.AbortWithStatus(http.StatusInternalServerError)
 			return
 		} else {
@@ -45,7 +45,7 @@ func Status(repo db_view.Repository, executorProvider executor.Provider, logger
 				}
 				status = rebaseStatus
 				return nil
-			}).ExecView(view.CodebaseID, view.ID, "rebaseStatus"); err != nil {
+			}).ExecViewContext(c.Request.Context(), view.CodebaseID, view.ID, "rebaseStatus"); err != nil {
 			logger.Error("failed to get rebase status", zap.Error(err))
 			c.AbortWithStatus(http.StatusInternalServerError)
 			return
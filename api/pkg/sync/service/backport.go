@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"getsturdy.com/api/pkg/changes"
+	"getsturdy.com/api/pkg/events/v2"
+	"getsturdy.com/api/pkg/sync"
+	"getsturdy.com/api/pkg/users"
+	vcs_view "getsturdy.com/api/pkg/views/vcs"
+	"getsturdy.com/api/pkg/workspaces"
+	vcsvcs "getsturdy.com/api/vcs"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrChangeNotLanded     = fmt.Errorf("change has no commit")
+	ErrChangeNotInCodebase = fmt.Errorf("change is not in the same codebase as the workspace")
+	ErrUnsavedChanges      = fmt.Errorf("workspace has unsaved changes")
+)
+
+// Backport cherry-picks the commit of _ch_ on top of the workspace, and leaves the changes of the commit as the work in
+// progress of the workspace, ready to be landed.
+//
+// The workspace must not have any unsaved changes. For workspaces without a view, the changes in the latest snapshot of
+// the workspace are its unsaved changes. If the commit conflicts with the workspace, and the workspace is
+// open on a view, the cherry-pick is left in progress on the view, and a conflicting sync.RebaseStatusResponse is
+// returned which has to be resolved by the user (see Resolve). If the workspace is not open on a view, a view is created
+// for _userID_ to hold the conflicts.
+func (svc *Service) Backport(ctx context.Context, userID users.ID, ch *changes.Change, ws *workspaces.Workspace) (*sync.RebaseStatusResponse, error) {
+	if ch.CommitID == nil {
+		return nil, ErrChangeNotLanded
+	}
+	if ch.CodebaseID != ws.CodebaseID {
+		return nil, ErrChangeNotInCodebase
+	}
+
+	if ws.ViewID == nil && ws.LatestSnapshotID != nil {
+		diffs, err := svc.snap.Diffs(ctx, *ws.LatestSnapshotID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get diffs of the latest snapshot: %w", err)
+		}
+		if len(diffs) > 0 {
+			return nil, ErrUnsavedChanges
+		}
+	}
+
+	changeCommitID := *ch.CommitID
+	branchName := fmt.Sprintf("backport-%s", uuid.NewString())
+
+	// make the commit of the change fetchable from the view, and check if it can be cleanly picked
+	var conflicted bool
+	if err := svc.executorProvider.New().
+		Write(func(repo vcsvcs.RepoWriter) error {
+			if err := repo.CreateNewBranchAt(branchName, changeCommitID); err != nil {
+				return fmt.Errorf("failed to create backport branch: %w", err)
+			}
+			workspaceHeadCommitID, err := repo.BranchCommitID(ws.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get workspace head: %w", err)
+			}
+			if _, conflicted, _, err = repo.CherryPickOnto(changeCommitID, workspaceHeadCommitID); err != nil {
+				return fmt.Errorf("failed to cherry-pick: %w", err)
+			}
+			return nil
+		}).ExecTrunkContext(ctx, ws.CodebaseID, "backportPrepare"); err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := svc.executorProvider.New().
+			Write(func(repo vcsvcs.RepoWriter) error {
+				return repo.DeleteBranch(branchName)
+			}).ExecTrunkContext(ctx, ws.CodebaseID, "backportCleanup"); err != nil {
+			svc.logger.Error("failed to delete backport branch", zap.Error(err))
+			// do not fail
+		}
+	}()
+
+	// conflicts are resolved on a view, so open the workspace on one
+	if conflicted && ws.ViewID == nil {
+		if _, err := svc.viewService.Create(ctx, userID, ws, nil, nil); err != nil {
+			return nil, fmt.Errorf("failed to create view: %w", err)
+		}
+		var err error
+		if ws, err = svc.workspaceReader.Get(ws.ID); err != nil {
+			return nil, fmt.Errorf("failed to get workspace: %w", err)
+		}
+	}
+
+	var rebaseStatusResponse *sync.RebaseStatusResponse
+
+	backportFunc := func(repo vcsvcs.RepoWriter) error {
+		diff, err := repo.CurrentDiffNoIndex()
+		if err != nil {
+			return fmt.Errorf("failed to get diff: %w", err)
+		}
+		defer diff.Free()
+		deltas, err := diff.NumDeltas()
+		if err != nil {
+			return fmt.Errorf("failed to get number of deltas: %w", err)
+		}
+		if deltas > 0 {
+			return ErrUnsavedChanges
+		}
+
+		if err := repo.FetchBranch(branchName); err != nil {
+			return fmt.Errorf("failed to fetch backport branch: %w", err)
+		}
+
+		head, err := repo.HeadCommit()
+		if err != nil {
+			return fmt.Errorf("failed to get head commit: %w", err)
+		}
+		headCommitID := head.Id().String()
+		head.Free()
+
+		if err := repo.CreateAndCheckoutBranchAtCommit(headCommitID, branchName); err != nil {
+			return fmt.Errorf("create and checkout branch failed: %w", err)
+		}
+
+		rb, rebasedCommits, err := repo.InitRebaseRaw(changeCommitID, headCommitID)
+		if err != nil {
+			return fmt.Errorf("failed to cherry-pick: %w", err)
+		}
+
+		rebaseStatus, err := rb.Status()
+		if err != nil {
+			return err
+		}
+
+		// We have conflicts, require resolution from user
+		if rebaseStatus == vcsvcs.RebaseHaveConflicts {
+			// Restore large files
+			if err := repo.LargeFilesPull(); err != nil {
+				// don't fail
+				svc.logger.Error("failed to restore large files", zap.Error(err))
+			}
+
+			rebaseStatusResponse, err = Status(svc.logger, rb)
+			if err != nil {
+				return fmt.Errorf("failed to get conflict status: %w", err)
+			}
+
+			return nil
+		}
+
+		// No conflicts
+
+		if err := repo.MoveBranchToHEAD(branchName); err != nil {
+			return fmt.Errorf("branch to head failed: %w", err)
+		}
+
+		// the picked commit is reset, and its changes are left as the work in progress of the workspace
+		if err := svc.complete(ctx, repo, ws.CodebaseID, ws.ID, *repo.ViewID(), &changeCommitID, rebasedCommits); err != nil {
+			return err
+		}
+
+		rebaseStatusResponse = &sync.RebaseStatusResponse{HaveConflicts: false}
+		return nil
+	}
+
+	if ws.ViewID != nil {
+		if err := svc.executorProvider.New().
+			AssertBranchName(ws.ID).
+			Write(backportFunc).
+			ExecViewContext(ctx, ws.CodebaseID, *ws.ViewID, "backport"); err != nil {
+			return nil, err
+		}
+		vw, err := svc.viewRepo.Get(*ws.ViewID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get view: %w", err)
+		}
+
+		if err := svc.eventsPublisher.ViewUpdated(ctx, events.Codebase(vw.CodebaseID), vw); err != nil {
+			svc.logger.Error("failed to send workspace updated event", zap.Error(err))
+			// do not fail
+		}
+	} else {
+		// the latest snapshot has no changes, so the workspace is backported from its branch
+		if err := svc.executorProvider.New().
+			Write(vcs_view.CheckoutBranch(ws.ID)).
+			Write(backportFunc).
+			ExecTemporaryViewContext(ctx, ws.CodebaseID, "backport"); err != nil {
+			return nil, err
+		}
+	}
+
+	if rebaseStatusResponse == nil {
+		return nil, fmt.Errorf("no rebase status found")
+	}
+
+	return rebaseStatusResponse, nil
+}
@@ -0,0 +1,256 @@
+package service_test
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+
+	"getsturdy.com/api/pkg/changes"
+	"getsturdy.com/api/pkg/codebases"
+	db_codebases "getsturdy.com/api/pkg/codebases/db"
+	service_codebase "getsturdy.com/api/pkg/codebases/service"
+	"getsturdy.com/api/pkg/configuration"
+	"getsturdy.com/api/pkg/di"
+	db_installations "getsturdy.com/api/pkg/installations/db"
+	"getsturdy.com/api/pkg/logger"
+	module_queue "getsturdy.com/api/pkg/queue/module"
+	"getsturdy.com/api/pkg/snapshots"
+	db_snapshots "getsturdy.com/api/pkg/snapshots/db"
+	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
+	db_statuses "getsturdy.com/api/pkg/statuses/db"
+	db_suggestions "getsturdy.com/api/pkg/suggestions/db"
+	service_sync "getsturdy.com/api/pkg/sync/service"
+	db_trunks "getsturdy.com/api/pkg/trunks/db"
+	"getsturdy.com/api/pkg/users"
+	db_view "getsturdy.com/api/pkg/views/db"
+	service_view "getsturdy.com/api/pkg/views/service"
+	"getsturdy.com/api/pkg/workspaces"
+	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
+	service_workspace "getsturdy.com/api/pkg/workspaces/service"
+	"getsturdy.com/api/vcs"
+	"getsturdy.com/api/vcs/executor"
+	"getsturdy.com/api/vcs/testutil"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func testModule(t *testing.T) di.Module {
+	return func(c *di.Container) {
+		c.Import(service_sync.Module)
+		c.Import(service_snapshots.Module)
+		c.Import(service_codebase.Module)
+		c.Import(service_workspace.Module)
+		c.Import(service_view.Module)
+
+		c.ImportWithForce(db_snapshots.TestModule)
+		c.ImportWithForce(db_view.TestModule)
+		c.ImportWithForce(db_workspaces.TestModule)
+		c.ImportWithForce(db_suggestions.TestModule)
+		c.ImportWithForce(db_codebases.TestModule)
+		c.ImportWithForce(db_installations.TestModule)
+		c.ImportWithForce(db_statuses.TestModule)
+		c.ImportWithForce(db_trunks.TestModule)
+		c.ImportWithForce(module_queue.TestModule(t))
+		c.ImportWithForce(configuration.TestModule)
+		c.RegisterWithForce(logger.NewTest)
+
+		c.RegisterWithForce(func() *sqlx.DB { return nil }) // make sure db is not used
+		c.Register(func() *testing.T { return t })
+		c.RegisterWithForce(testutil.TestingRepoProvider)
+	}
+}
+
+type testCase struct {
+	syncService      *service_sync.Service
+	snapshotService  *service_snapshots.Service
+	workspaceService *service_workspace.Service
+	codebaseService  *service_codebase.Service
+	viewService      *service_view.Service
+	executorProvider executor.Provider
+
+	userID     users.ID
+	codebaseID codebases.ID
+}
+
+func setup(t *testing.T) *testCase {
+	tc := &testCase{}
+	if !assert.NoError(t, di.Init(testModule(t)).To(
+		&tc.syncService, &tc.snapshotService, &tc.workspaceService, &tc.codebaseService, &tc.viewService, &tc.executorProvider,
+	)) {
+		t.FailNow()
+	}
+
+	tc.userID = users.ID(uuid.NewString())
+
+	cb, err := tc.codebaseService.Create(context.Background(), tc.userID, "test", nil)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	tc.codebaseID = cb.ID
+
+	return tc
+}
+
+// landedChange creates a commit on top of the trunk with the given files, and returns it as a landed change.
+func (tc *testCase) landedChange(t *testing.T, files ...vcs.FileContents) *changes.Change {
+	var commitID string
+	assert.NoError(t, tc.executorProvider.New().GitWrite(func(repo vcs.RepoGitWriter) error {
+		var err error
+		commitID, err = repo.CreateCommitWithFiles(files, "change-"+uuid.NewString())
+		return err
+	}).ExecTrunk(tc.codebaseID, "createChange"))
+
+	return &changes.Change{
+		ID:         changes.ID(uuid.NewString()),
+		CodebaseID: tc.codebaseID,
+		CommitID:   &commitID,
+	}
+}
+
+func (tc *testCase) createWorkspace(t *testing.T) *workspaces.Workspace {
+	ws, err := tc.workspaceService.Create(context.Background(), service_workspace.CreateWorkspaceRequest{UserID: tc.userID, CodebaseID: tc.codebaseID})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return ws
+}
+
+func TestBackport_clean(t *testing.T) {
+	tc := setup(t)
+	ctx := context.Background()
+
+	ws := tc.createWorkspace(t)
+	vw, err := tc.viewService.Create(ctx, tc.userID, ws, nil, nil)
+	assert.NoError(t, err)
+	ws, err = tc.workspaceService.GetByID(ctx, ws.ID)
+	assert.NoError(t, err)
+
+	ch := tc.landedChange(t, vcs.FileContents{Path: "a.txt", Contents: []byte("change")})
+
+	status, err := tc.syncService.Backport(ctx, tc.userID, ch, ws)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.False(t, status.HaveConflicts)
+
+	// the changes of the commit are left as the work in progress of the workspace
+	assert.NoError(t, tc.executorProvider.New().Read(func(repo vcs.RepoReader) error {
+		a, err := os.ReadFile(path.Join(repo.Path(), "a.txt"))
+		assert.NoError(t, err)
+		assert.Equal(t, "change", string(a))
+		return nil
+	}).ExecView(tc.codebaseID, vw.ID, "verifyBackport"))
+
+	ws, err = tc.workspaceService.GetByID(ctx, ws.ID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, ws.LatestSnapshotID) {
+		diffs, err := tc.snapshotService.Diffs(ctx, *ws.LatestSnapshotID)
+		assert.NoError(t, err)
+		if assert.Len(t, diffs, 1) {
+			assert.Equal(t, "a.txt", diffs[0].NewName)
+		}
+	}
+
+	// the workspace now has unsaved changes
+	_, err = tc.syncService.Backport(ctx, tc.userID, tc.landedChange(t, vcs.FileContents{Path: "b.txt", Contents: []byte("b")}), ws)
+	assert.ErrorIs(t, err, service_sync.ErrUnsavedChanges)
+}
+
+func TestBackport_conflicting(t *testing.T) {
+	tc := setup(t)
+	ctx := context.Background()
+
+	ws := tc.createWorkspace(t)
+
+	// the workspace has a different version of the file in its history
+	assert.NoError(t, tc.executorProvider.New().GitWrite(func(repo vcs.RepoGitWriter) error {
+		_, err := repo.CreateCommitWithFiles([]vcs.FileContents{{Path: "a.txt", Contents: []byte("workspace")}}, ws.ID)
+		return err
+	}).ExecTrunk(tc.codebaseID, "commitToWorkspace"))
+
+	ch := tc.landedChange(t, vcs.FileContents{Path: "a.txt", Contents: []byte("change")})
+
+	// the workspace is not open on a view, a view is created to resolve the conflicts on
+	status, err := tc.syncService.Backport(ctx, tc.userID, ch, ws)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, status.HaveConflicts)
+	if assert.Len(t, status.ConflictingFiles, 1) {
+		assert.Equal(t, "a.txt", status.ConflictingFiles[0].Path)
+	}
+
+	ws, err = tc.workspaceService.GetByID(ctx, ws.ID)
+	assert.NoError(t, err)
+	if !assert.NotNil(t, ws.ViewID) {
+		return
+	}
+	assert.NoError(t, tc.executorProvider.New().
+		AllowRebasingState().
+		Read(func(repo vcs.RepoReader) error {
+			assert.True(t, repo.IsRebasing(), "the cherry-pick is left in progress on the view")
+			return nil
+		}).ExecView(tc.codebaseID, *ws.ViewID, "verifyBackport"))
+}
+
+func TestBackport_noView(t *testing.T) {
+	tc := setup(t)
+	ctx := context.Background()
+
+	ws := tc.createWorkspace(t)
+	vw, err := tc.viewService.Create(ctx, tc.userID, ws, nil, nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, tc.executorProvider.New().
+		Write(writeFile("b.txt", []byte("b"))).
+		ExecView(tc.codebaseID, vw.ID, "makeChanges"))
+	_, err = tc.snapshotService.Snapshot(ctx, tc.codebaseID, ws.ID, snapshots.Action("testing"), service_snapshots.WithOnView(vw.ID))
+	assert.NoError(t, err)
+
+	ws, err = tc.workspaceService.GetByID(ctx, ws.ID)
+	assert.NoError(t, err)
+	ws.ViewID = nil // the view is closed, the changes are only in the latest snapshot
+
+	ch := tc.landedChange(t, vcs.FileContents{Path: "a.txt", Contents: []byte("change")})
+
+	_, err = tc.syncService.Backport(ctx, tc.userID, ch, ws)
+	assert.ErrorIs(t, err, service_sync.ErrUnsavedChanges)
+
+	// once the changes are gone from the latest snapshot, the change can be backported
+	assert.NoError(t, tc.executorProvider.New().
+		Write(func(repo vcs.RepoWriter) error {
+			return os.Remove(path.Join(repo.Path(), "b.txt"))
+		}).
+		ExecView(tc.codebaseID, vw.ID, "revertChanges"))
+	_, err = tc.snapshotService.Snapshot(ctx, tc.codebaseID, ws.ID, snapshots.Action("testing"), service_snapshots.WithOnView(vw.ID))
+	assert.NoError(t, err)
+
+	ws, err = tc.workspaceService.GetByID(ctx, ws.ID)
+	assert.NoError(t, err)
+	ws.ViewID = nil
+
+	status, err := tc.syncService.Backport(ctx, tc.userID, ch, ws)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.False(t, status.HaveConflicts)
+
+	ws, err = tc.workspaceService.GetByID(ctx, ws.ID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, ws.LatestSnapshotID) {
+		diffs, err := tc.snapshotService.Diffs(ctx, *ws.LatestSnapshotID)
+		assert.NoError(t, err)
+		if assert.Len(t, diffs, 1) {
+			assert.Equal(t, "a.txt", diffs[0].NewName)
+		}
+	}
+}
+
+func writeFile(filename string, content []byte) func(vcs.RepoWriter) error {
+	return func(repo vcs.RepoWriter) error {
+		return os.WriteFile(path.Join(repo.Path(), filename), content, 0o644)
+	}
+}
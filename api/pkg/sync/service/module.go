@@ -4,8 +4,10 @@ import (
 	"getsturdy.com/api/pkg/di"
 	"getsturdy.com/api/pkg/events"
 	"getsturdy.com/api/pkg/logger"
+	queue "getsturdy.com/api/pkg/queue/module"
 	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
 	db_view "getsturdy.com/api/pkg/views/db"
+	service_view "getsturdy.com/api/pkg/views/service"
 	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
 	"getsturdy.com/api/vcs/executor"
 )
@@ -16,6 +18,8 @@ func Module(c *di.Container) {
 	c.Import(db_view.Module)
 	c.Import(db_workspaces.Module)
 	c.Import(service_snapshots.Module)
+	c.Import(service_view.Module)
 	c.Import(events.Module)
+	c.Import(queue.Module)
 	c.Register(New)
 }
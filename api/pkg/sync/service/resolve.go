@@ -56,7 +56,7 @@ func (svc *Service) Resolve(ctx context.Context, viewID string, resolves []vcsvc
 	err = svc.executorProvider.New().
 		AllowRebasingState(). // allowed to get the state of existing conflicts
 		Write(resolveSyncFunc).
-		ExecView(view.CodebaseID, view.ID, "syncResolve2")
+		ExecViewContext(ctx, view.CodebaseID, view.ID, "syncResolve2")
 	if err != nil {
 		return nil, err
 	}
@@ -8,11 +8,15 @@ import (
 	change_vcs "getsturdy.com/api/pkg/changes/vcs"
 	"getsturdy.com/api/pkg/codebases"
 	"getsturdy.com/api/pkg/events/v2"
+	"getsturdy.com/api/pkg/queue"
+	"getsturdy.com/api/pkg/queue/names"
 	"getsturdy.com/api/pkg/snapshots"
 	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
 	"getsturdy.com/api/pkg/sync"
+	"getsturdy.com/api/pkg/trunks"
 	"getsturdy.com/api/pkg/unidiff"
 	db_view "getsturdy.com/api/pkg/views/db"
+	service_view "getsturdy.com/api/pkg/views/service"
 	vcs_view "getsturdy.com/api/pkg/views/vcs"
 	"getsturdy.com/api/pkg/workspaces"
 	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
@@ -22,9 +26,16 @@ import (
 
 	"github.com/google/uuid"
 	git "github.com/libgit2/git2go/v33"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 )
 
+// Message is the message that is published to the sync worker, when the workspaces that are stacked on top of a
+// workspace need to be synced.
+type Message struct {
+	ParentWorkspaceID string `json:"parent_workspace_id"`
+}
+
 type Service struct {
 	logger           *zap.Logger
 	executorProvider executor.Provider
@@ -32,8 +43,10 @@ type Service struct {
 	workspaceReader  db_workspaces.WorkspaceReader
 	workspaceWriter  db_workspaces.WorkspaceWriter
 	snap             *service_snapshots.Service
+	viewService      *service_view.Service
 
 	eventsPublisher *events.Publisher
+	queue           queue.Queue
 }
 
 func New(
@@ -43,7 +56,9 @@ func New(
 	workspaceReader db_workspaces.WorkspaceReader,
 	workspaceWriter db_workspaces.WorkspaceWriter,
 	snap *service_snapshots.Service,
+	viewService *service_view.Service,
 	eventsPublisher *events.Publisher,
+	queue queue.Queue,
 ) *Service {
 	return &Service{
 		logger:           logger.Named("syncService"),
@@ -52,19 +67,137 @@ func New(
 		workspaceReader:  workspaceReader,
 		workspaceWriter:  workspaceWriter,
 		snap:             snap,
+		viewService:      viewService,
 		eventsPublisher:  eventsPublisher,
+		queue:            queue,
 	}
 }
 
 const unsavedCommitMessage = "Unsaved workspace changes"
 
-// OnTrunk starts a sync of the workspace on top of the current sturdytrunk
+var ErrNotStacked = fmt.Errorf("workspace is not stacked on top of another workspace")
+
+// OnTrunk starts a sync of the workspace on top of the current head of its trunk
 // If the work in progress changes on the workspace conflicts with trunk, a conflicting sync.RebaseStatusResponse is returned
 // which has to be resolved by the user (see Resolve).
 //
 // The current work in progress will be added to a commit, that is rebased on top of the trunk.
 // After the syncing is done, the commit is "git reset --mixed HEAD^1"-ed, to restore it to the WIP.
 func (svc *Service) OnTrunk(ctx context.Context, ws *workspaces.Workspace) (*sync.RebaseStatusResponse, error) {
+	return svc.onBranch(ctx, ws, trunks.BranchName(ws.TrunkID))
+}
+
+// OnParent starts a sync of a stacked workspace on top of the latest snapshot of its parent workspace. It works the
+// same way as OnTrunk.
+func (svc *Service) OnParent(ctx context.Context, ws *workspaces.Workspace) (*sync.RebaseStatusResponse, error) {
+	if ws.ParentWorkspaceID == nil {
+		return nil, ErrNotStacked
+	}
+
+	parent, err := svc.workspaceReader.Get(*ws.ParentWorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parent workspace: %w", err)
+	}
+
+	// workspaces without snapshots have no changes, so the branch of the parent is the same as its latest state
+	baseBranch := parent.ID
+	if parent.LatestSnapshotID != nil {
+		snapshot, err := svc.snap.GetByID(ctx, *parent.LatestSnapshotID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent snapshot: %w", err)
+		}
+		baseBranch = snapshot.BranchName()
+	}
+
+	return svc.onBranch(ctx, ws, baseBranch)
+}
+
+// Sync syncs the workspace on top of its parent if it's stacked, and on top of the trunk otherwise.
+func (svc *Service) Sync(ctx context.Context, ws *workspaces.Workspace) (*sync.RebaseStatusResponse, error) {
+	if ws.ParentWorkspaceID != nil {
+		return svc.OnParent(ctx, ws)
+	}
+	return svc.OnTrunk(ctx, ws)
+}
+
+// SyncChildren syncs all workspaces that are stacked on top of _parentWorkspaceID_. If the parent has been archived
+// (for example because it has landed), the children are unstacked and synced on top of the trunk instead, which
+// contains the changes of the parent if it has landed.
+//
+// A child that fails to sync does not stop the others from being synced, the errors of all failed children are
+// returned together.
+func (svc *Service) SyncChildren(ctx context.Context, parentWorkspaceID string) error {
+	parent, err := svc.workspaceReader.Get(parentWorkspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to get parent workspace: %w", err)
+	}
+
+	children, err := svc.workspaceReader.ListByParentWorkspaceID(ctx, parentWorkspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to list stacked workspaces: %w", err)
+	}
+
+	archived := parent.ArchivedAt != nil
+	var errs error
+	for _, child := range children {
+		if err := svc.syncChild(ctx, parent, child, archived); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to sync workspace %s: %w", child.ID, err))
+		}
+	}
+	return errs
+}
+
+func (svc *Service) syncChild(ctx context.Context, parent, child *workspaces.Workspace, parentArchived bool) error {
+	if !parentArchived {
+		status, err := svc.OnParent(ctx, child)
+		if err != nil {
+			return err
+		}
+		if status.HaveConflicts {
+			svc.logger.Info("stacked workspace has conflicts with its parent",
+				zap.String("workspace_id", child.ID),
+				zap.String("parent_workspace_id", parent.ID),
+			)
+		}
+		return nil
+	}
+
+	// the child stays stacked until it has been synced on top of the trunk, so that a failed sync is retried
+	status, err := svc.OnTrunk(ctx, child)
+	if err != nil {
+		return err
+	}
+	if err := svc.workspaceWriter.UpdateFields(ctx, child.ID, db_workspaces.SetParentWorkspaceID(nil)); err != nil {
+		return fmt.Errorf("failed to unstack workspace: %w", err)
+	}
+	child.ParentWorkspaceID = nil
+	if status.HaveConflicts {
+		svc.logger.Info("unstacked workspace has conflicts with the trunk",
+			zap.String("workspace_id", child.ID),
+			zap.String("parent_workspace_id", parent.ID),
+		)
+	}
+	return nil
+}
+
+// NotifyChildren schedules the workspaces that are stacked on top of _parentWorkspaceID_ to be synced, see
+// SyncChildren.
+func (svc *Service) NotifyChildren(ctx context.Context, parentWorkspaceID string) error {
+	children, err := svc.workspaceReader.ListByParentWorkspaceID(ctx, parentWorkspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to list stacked workspaces: %w", err)
+	}
+	if len(children) == 0 {
+		return nil
+	}
+	if err := svc.queue.Publish(ctx, names.SyncStackedWorkspaces, &Message{ParentWorkspaceID: parentWorkspaceID}); err != nil {
+		return fmt.Errorf("failed to publish to queue: %w", err)
+	}
+	return nil
+}
+
+// onBranch syncs the workspace on top of _baseBranch_ from the trunk repository.
+func (svc *Service) onBranch(ctx context.Context, ws *workspaces.Workspace, baseBranch string) (*sync.RebaseStatusResponse, error) {
 	syncID := uuid.NewString()
 
 	branchName := fmt.Sprintf("sync-%s", syncID)
@@ -85,11 +218,11 @@ func (svc *Service) OnTrunk(ctx context.Context, ws *workspaces.Workspace) (*syn
 			return nil
 		}
 
-		if err := repo.FetchBranch("sturdytrunk"); err != nil {
+		if err := repo.FetchBranch(baseBranch); err != nil {
 			return err
 		}
 
-		trunkHeadCommit, err := repo.RemoteBranchCommit("origin", "sturdytrunk")
+		baseHeadCommit, err := repo.RemoteBranchCommit("origin", baseBranch)
 		if err != nil {
 			return err
 		}
@@ -113,7 +246,7 @@ func (svc *Service) OnTrunk(ctx context.Context, ws *workspaces.Workspace) (*syn
 
 		// no changes, early return
 		if treeID == nil {
-			if err := repo.MoveBranchToCommit(branchName, trunkHeadCommit.Id().String()); err != nil {
+			if err := repo.MoveBranchToCommit(branchName, baseHeadCommit.Id().String()); err != nil {
 				return fmt.Errorf("failed to move branch to commit in early return: %w", err)
 			}
 			if err := repo.CheckoutBranchWithForce(branchName); err != nil {
@@ -137,14 +270,14 @@ func (svc *Service) OnTrunk(ctx context.Context, ws *workspaces.Workspace) (*syn
 			return fmt.Errorf("failed to create commit with unsave changes: %w", err)
 		}
 
-		if err := repo.CreateAndCheckoutBranchAtCommit(trunkHeadCommit.Id().String(), branchName); err != nil {
+		if err := repo.CreateAndCheckoutBranchAtCommit(baseHeadCommit.Id().String(), branchName); err != nil {
 			return fmt.Errorf("create and checkout branch failed: %w", err)
 		}
 
 		// Apply our unsaved changes
 		rb, rebasedCommits, err := repo.InitRebaseRaw(
 			unsavedCommitID,
-			trunkHeadCommit.Id().String(),
+			baseHeadCommit.Id().String(),
 		)
 		if err != nil {
 			return err
@@ -190,7 +323,7 @@ func (svc *Service) OnTrunk(ctx context.Context, ws *workspaces.Workspace) (*syn
 			AssertBranchName(ws.ID).
 			AllowRebasingState(). // allowed to get the state of existing conflicts
 			Write(rebaseFunc).
-			ExecView(ws.CodebaseID, *ws.ViewID, "syncOnTrunk"); err != nil {
+			ExecViewContext(ctx, ws.CodebaseID, *ws.ViewID, "syncOnTrunk"); err != nil {
 			return nil, err
 		}
 		vw, err := svc.viewRepo.Get(*ws.ViewID)
@@ -203,10 +336,20 @@ func (svc *Service) OnTrunk(ctx context.Context, ws *workspaces.Workspace) (*syn
 			// do not fail
 		}
 	} else {
+		// restore the work in progress from the latest snapshot, if there is one
+		checkout := vcs_view.CheckoutBranch(ws.ID)
+		if ws.LatestSnapshotID != nil {
+			snapshot, err := svc.snap.GetByID(ctx, *ws.LatestSnapshotID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get snapshot: %w", err)
+			}
+			checkout = vcs_view.CheckoutSnapshot(snapshot)
+		}
+
 		if err := svc.executorProvider.New().
-			Write(vcs_view.CheckoutBranch(ws.ID)).
+			Write(checkout).
 			Write(rebaseFunc).
-			ExecTemporaryView(ws.CodebaseID, "syncOnTrunk"); err != nil {
+			ExecTemporaryViewContext(ctx, ws.CodebaseID, "syncOnTrunk"); err != nil {
 			return nil, err
 		}
 	}
@@ -218,7 +361,7 @@ func (svc *Service) OnTrunk(ctx context.Context, ws *workspaces.Workspace) (*syn
 	return rebaseStatusResponse, nil
 }
 
-// complete is called by OnTrunk (if there where no conflicts) and Resolve (when all conflicts have been resolved)
+// complete is called by onBranch (if there where no conflicts) and Resolve (when all conflicts have been resolved)
 func (svc *Service) complete(ctx context.Context, repo vcsvcs.RepoWriter, codebaseID codebases.ID, workspaceID, viewID string, unsavedCommitID *string, rebasedCommits []vcsvcs.RebasedCommit) error {
 	if err := repo.MoveBranchToHEAD(workspaceID); err != nil {
 		return fmt.Errorf("failed to move workspace to head: %w", err)
@@ -0,0 +1,40 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	service_workspace "getsturdy.com/api/pkg/workspaces/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncChildren_archivedParent(t *testing.T) {
+	tc := setup(t)
+	ctx := context.Background()
+
+	parent := tc.createWorkspace(t)
+	child, err := tc.workspaceService.Create(ctx, service_workspace.CreateWorkspaceRequest{
+		UserID:            tc.userID,
+		CodebaseID:        tc.codebaseID,
+		ParentWorkspaceID: &parent.ID,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// the parent is still active, the child stays stacked
+	assert.NoError(t, tc.syncService.SyncChildren(ctx, parent.ID))
+	child, err = tc.workspaceService.GetByID(ctx, child.ID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, child.ParentWorkspaceID) {
+		assert.Equal(t, parent.ID, *child.ParentWorkspaceID)
+	}
+
+	// once the parent is archived, the child is unstacked
+	assert.NoError(t, tc.workspaceService.Archive(ctx, parent))
+	assert.NoError(t, tc.syncService.SyncChildren(ctx, parent.ID))
+	child, err = tc.workspaceService.GetByID(ctx, child.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, child.ParentWorkspaceID)
+}
@@ -0,0 +1,100 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	"getsturdy.com/api/pkg/changes"
+	"getsturdy.com/api/pkg/codebases"
+	eventsv2 "getsturdy.com/api/pkg/events/v2"
+	"getsturdy.com/api/pkg/queue"
+	"getsturdy.com/api/pkg/queue/names"
+	service_sync "getsturdy.com/api/pkg/sync/service"
+	"getsturdy.com/api/pkg/workspaces"
+
+	"go.uber.org/zap"
+)
+
+// Queue is a background queue that syncs stacked workspaces when their parent workspace changes or lands.
+type Queue struct {
+	logger *zap.Logger
+
+	queue queue.Queue
+	name  names.IncompleteQueueName
+
+	eventsSubscriber *eventsv2.Subscriber
+	syncService      *service_sync.Service
+}
+
+func New(
+	logger *zap.Logger,
+	queue queue.Queue,
+	eventsSubscriber *eventsv2.Subscriber,
+	syncService *service_sync.Service,
+) *Queue {
+	return &Queue{
+		logger:           logger.Named("syncStackedQueue"),
+		queue:            queue,
+		name:             names.SyncStackedWorkspaces,
+		eventsSubscriber: eventsSubscriber,
+		syncService:      syncService,
+	}
+}
+
+// Start starts the worker.
+func (q *Queue) Start(ctx context.Context) error {
+	q.eventsSubscriber.OnAnyInCodebase(ctx, func(ctx context.Context, _ codebases.ID, t eventsv2.Type, payload any) error {
+		switch t {
+		case eventsv2.WorkspaceUpdatedSnapshot:
+			if ws, ok := payload.(*workspaces.Workspace); ok {
+				return q.syncService.NotifyChildren(ctx, ws.ID)
+			}
+		case eventsv2.WorkspaceUpdated:
+			// children of archived workspaces are unstacked
+			if ws, ok := payload.(*workspaces.Workspace); ok && ws.ArchivedAt != nil {
+				return q.syncService.NotifyChildren(ctx, ws.ID)
+			}
+		case eventsv2.ChangeLanded:
+			if change, ok := payload.(*changes.Change); ok && change.WorkspaceID != nil {
+				return q.syncService.NotifyChildren(ctx, *change.WorkspaceID)
+			}
+		}
+		return nil
+	})
+
+	messages := make(chan queue.Message)
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				q.logger.Error("panic in sync stacked queue", zap.String("panic", fmt.Sprintf("%v", rec)))
+			}
+		}()
+		for msg := range messages {
+			m := &service_sync.Message{}
+			if err := msg.As(m); err != nil {
+				q.logger.Error("failed to decode message", zap.Error(err), zap.Any("message", msg))
+				continue
+			}
+
+			logger := q.logger.With(zap.String("parent_workspace_id", m.ParentWorkspaceID))
+
+			if err := q.syncService.SyncChildren(ctx, m.ParentWorkspaceID); err != nil {
+				logger.Error("failed to sync stacked workspaces", zap.Error(err))
+				continue
+			}
+
+			if err := msg.Ack(); err != nil {
+				logger.Error("failed to ack message", zap.Error(err))
+				continue
+			}
+		}
+	}()
+
+	q.logger.Info("starting queue", zap.Stringer("queue_name", q.name))
+	if err := q.queue.Subscribe(ctx, q.name, messages); err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+	q.logger.Info("queue stoped", zap.Stringer("queue_name", q.name))
+
+	return nil
+}
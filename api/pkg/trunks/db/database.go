@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/trunks"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var _ Repository = &database{}
+
+type database struct {
+	db *sqlx.DB
+}
+
+func NewDatabase(db *sqlx.DB) Repository {
+	return &database{
+		db: db,
+	}
+}
+
+func (d *database) Create(ctx context.Context, trunk *trunks.Trunk) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		INSERT INTO trunks (
+			id, codebase_id, name, tracked_branch, created_by, created_at
+		) VALUES (
+			:id, :codebase_id, :name, :tracked_branch, :created_by, :created_at
+		)
+	`, trunk); err != nil {
+		return fmt.Errorf("failed to insert: %w", err)
+	}
+	return nil
+}
+
+func (d *database) Update(ctx context.Context, trunk *trunks.Trunk) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		UPDATE trunks
+		SET name = :name,
+			tracked_branch = :tracked_branch
+		WHERE id = :id
+	`, trunk); err != nil {
+		return fmt.Errorf("failed to update: %w", err)
+	}
+	return nil
+}
+
+func (d *database) Get(ctx context.Context, id trunks.ID) (*trunks.Trunk, error) {
+	trunk := &trunks.Trunk{}
+	if err := d.db.GetContext(ctx, trunk, `
+		SELECT
+			id, codebase_id, name, tracked_branch, created_by, created_at
+		FROM
+			trunks
+		WHERE
+			id = $1
+	`, id); err != nil {
+		return nil, fmt.Errorf("failed to get: %w", err)
+	}
+	return trunk, nil
+}
+
+func (d *database) GetByName(ctx context.Context, codebaseID codebases.ID, name string) (*trunks.Trunk, error) {
+	trunk := &trunks.Trunk{}
+	if err := d.db.GetContext(ctx, trunk, `
+		SELECT
+			id, codebase_id, name, tracked_branch, created_by, created_at
+		FROM
+			trunks
+		WHERE
+			codebase_id = $1
+			AND name = $2
+	`, codebaseID, name); err != nil {
+		return nil, fmt.Errorf("failed to get: %w", err)
+	}
+	return trunk, nil
+}
+
+func (d *database) ListByCodebaseID(ctx context.Context, codebaseID codebases.ID) ([]*trunks.Trunk, error) {
+	var tt []*trunks.Trunk
+	if err := d.db.SelectContext(ctx, &tt, `
+		SELECT
+			id, codebase_id, name, tracked_branch, created_by, created_at
+		FROM
+			trunks
+		WHERE
+			codebase_id = $1
+		ORDER BY
+			created_at ASC
+	`, codebaseID); err != nil {
+		return nil, fmt.Errorf("failed to list: %w", err)
+	}
+	return tt, nil
+}
+
+func (d *database) DeleteByCodebaseID(ctx context.Context, codebaseID codebases.ID) error {
+	if _, err := d.db.ExecContext(ctx, `DELETE FROM trunks WHERE codebase_id = $1`, codebaseID); err != nil {
+		return fmt.Errorf("failed to delete trunks: %w", err)
+	}
+	return nil
+}
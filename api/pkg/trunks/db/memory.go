@@ -0,0 +1,93 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/trunks"
+)
+
+var _ Repository = &memory{}
+
+type memory struct {
+	mu   sync.RWMutex
+	byID map[trunks.ID]*trunks.Trunk
+}
+
+func NewMemory() Repository {
+	return &memory{
+		byID: map[trunks.ID]*trunks.Trunk{},
+	}
+}
+
+func (m *memory) Create(_ context.Context, trunk *trunks.Trunk) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *trunk
+	m.byID[trunk.ID] = &cp
+	return nil
+}
+
+func (m *memory) Update(_ context.Context, trunk *trunks.Trunk) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, found := m.byID[trunk.ID]; !found {
+		return sql.ErrNoRows
+	}
+	cp := *trunk
+	m.byID[trunk.ID] = &cp
+	return nil
+}
+
+func (m *memory) Get(_ context.Context, id trunks.ID) (*trunks.Trunk, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	trunk, found := m.byID[id]
+	if !found {
+		return nil, sql.ErrNoRows
+	}
+	cp := *trunk
+	return &cp, nil
+}
+
+func (m *memory) GetByName(_ context.Context, codebaseID codebases.ID, name string) (*trunks.Trunk, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, trunk := range m.byID {
+		if trunk.CodebaseID == codebaseID && trunk.Name == name {
+			cp := *trunk
+			return &cp, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (m *memory) ListByCodebaseID(_ context.Context, codebaseID codebases.ID) ([]*trunks.Trunk, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var tt []*trunks.Trunk
+	for _, trunk := range m.byID {
+		if trunk.CodebaseID == codebaseID {
+			cp := *trunk
+			tt = append(tt, &cp)
+		}
+	}
+	sort.Slice(tt, func(i, j int) bool {
+		return tt[i].CreatedAt.Before(tt[j].CreatedAt)
+	})
+	return tt, nil
+}
+
+func (m *memory) DeleteByCodebaseID(_ context.Context, codebaseID codebases.ID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, trunk := range m.byID {
+		if trunk.CodebaseID == codebaseID {
+			delete(m.byID, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package db
+
+import (
+	"context"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/trunks"
+)
+
+type Repository interface {
+	Create(context.Context, *trunks.Trunk) error
+	Update(context.Context, *trunks.Trunk) error
+	Get(context.Context, trunks.ID) (*trunks.Trunk, error)
+	GetByName(ctx context.Context, codebaseID codebases.ID, name string) (*trunks.Trunk, error)
+	ListByCodebaseID(context.Context, codebases.ID) ([]*trunks.Trunk, error)
+	DeleteByCodebaseID(context.Context, codebases.ID) error
+}
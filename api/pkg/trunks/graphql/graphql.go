@@ -0,0 +1,155 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"getsturdy.com/api/pkg/auth"
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	"getsturdy.com/api/pkg/changes"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/codebases/acl"
+	service_codebases "getsturdy.com/api/pkg/codebases/service"
+	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
+	"getsturdy.com/api/pkg/graphql/resolvers"
+	"getsturdy.com/api/pkg/trunks"
+	service_trunks "getsturdy.com/api/pkg/trunks/service"
+)
+
+type rootResolver struct {
+	authService     *service_auth.Service
+	codebaseService *service_codebases.Service
+	trunksService   *service_trunks.Service
+}
+
+func New(
+	authService *service_auth.Service,
+	codebaseService *service_codebases.Service,
+	trunksService *service_trunks.Service,
+) resolvers.TrunksRootResolver {
+	return &rootResolver{
+		authService:     authService,
+		codebaseService: codebaseService,
+		trunksService:   trunksService,
+	}
+}
+
+func (r *rootResolver) InternalListByCodebaseID(ctx context.Context, codebaseID codebases.ID) ([]resolvers.TrunkResolver, error) {
+	tt, err := r.trunksService.List(ctx, codebaseID)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+	res := make([]resolvers.TrunkResolver, 0, len(tt))
+	for _, trunk := range tt {
+		res = append(res, &resolver{trunk: trunk})
+	}
+	return res, nil
+}
+
+func (r *rootResolver) InternalTrunk(ctx context.Context, id trunks.ID) (resolvers.TrunkResolver, error) {
+	trunk, err := r.trunksService.Get(ctx, id)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+	return &resolver{trunk: trunk}, nil
+}
+
+// canManage returns nil if the user is allowed to create and configure trunks in the codebase.
+func (r *rootResolver) canManage(ctx context.Context, codebaseID codebases.ID) error {
+	cb, err := r.codebaseService.GetByID(ctx, codebaseID)
+	if err != nil {
+		return err
+	}
+
+	if err := r.authService.CanWrite(ctx, cb); err != nil {
+		return err
+	}
+
+	return r.authService.CanPerform(ctx, cb.ID, acl.ActionAdmin, acl.Identity{Type: acl.Codebases, ID: cb.ID.String()})
+}
+
+func (r *rootResolver) CreateTrunk(ctx context.Context, args resolvers.CreateTrunkArgs) (resolvers.TrunkResolver, error) {
+	codebaseID := codebases.ID(args.Input.CodebaseID)
+	if err := r.canManage(ctx, codebaseID); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	userID, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	req := service_trunks.CreateRequest{
+		CodebaseID: codebaseID,
+		UserID:     userID,
+		Name:       args.Input.Name,
+	}
+	if args.Input.OnTopOfChange != nil {
+		changeID := changes.ID(*args.Input.OnTopOfChange)
+		req.BaseChangeID = &changeID
+	}
+
+	trunk, err := r.trunksService.Create(ctx, req)
+	switch {
+	case errors.Is(err, service_trunks.ErrInvalidName):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "Invalid trunk name")
+	case errors.Is(err, service_trunks.ErrNameTaken):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "A trunk with this name already exists")
+	case errors.Is(err, service_trunks.ErrNoDefaultTrunk):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "The codebase does not have any changes yet")
+	case errors.Is(err, service_trunks.ErrChangeNotInCodebase):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "The change does not belong to this codebase")
+	case err != nil:
+		return nil, gqlerrors.Error(fmt.Errorf("failed to create trunk: %w", err))
+	}
+
+	return &resolver{trunk: trunk}, nil
+}
+
+func (r *rootResolver) UpdateTrunk(ctx context.Context, args resolvers.UpdateTrunkArgs) (resolvers.TrunkResolver, error) {
+	trunk, err := r.trunksService.Get(ctx, trunks.ID(args.Input.ID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if err := r.canManage(ctx, trunk.CodebaseID); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	trackedBranch := args.Input.TrackedBranch
+	if trackedBranch != nil && *trackedBranch == "" {
+		trackedBranch = nil
+	}
+
+	switch err := r.trunksService.SetTrackedBranch(ctx, trunk, trackedBranch); {
+	case errors.Is(err, service_trunks.ErrTrackedBranchTaken):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "The branch is already tracked by another trunk")
+	case err != nil:
+		return nil, gqlerrors.Error(fmt.Errorf("failed to update trunk: %w", err))
+	}
+
+	return &resolver{trunk: trunk}, nil
+}
+
+type resolver struct {
+	trunk *trunks.Trunk
+}
+
+func (r *resolver) ID() graphql.ID {
+	return graphql.ID(r.trunk.ID)
+}
+
+func (r *resolver) Name() string {
+	return r.trunk.Name
+}
+
+func (r *resolver) TrackedBranch() *string {
+	return r.trunk.TrackedBranch
+}
+
+func (r *resolver) CreatedAt() int32 {
+	return int32(r.trunk.CreatedAt.Unix())
+}
@@ -0,0 +1,15 @@
+package graphql
+
+import (
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	service_codebases "getsturdy.com/api/pkg/codebases/service"
+	"getsturdy.com/api/pkg/di"
+	service_trunks "getsturdy.com/api/pkg/trunks/service"
+)
+
+func Module(c *di.Container) {
+	c.Import(service_auth.Module)
+	c.Import(service_codebases.Module)
+	c.Import(service_trunks.Module)
+	c.Register(New)
+}
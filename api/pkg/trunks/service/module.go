@@ -0,0 +1,17 @@
+package service
+
+import (
+	service_analytics "getsturdy.com/api/pkg/analytics/service"
+	service_changes "getsturdy.com/api/pkg/changes/service"
+	"getsturdy.com/api/pkg/di"
+	db_trunks "getsturdy.com/api/pkg/trunks/db"
+	"getsturdy.com/api/vcs/executor"
+)
+
+func Module(c *di.Container) {
+	c.Import(db_trunks.Module)
+	c.Import(service_changes.Module)
+	c.Import(service_analytics.Module)
+	c.Import(executor.Module)
+	c.Register(New)
+}
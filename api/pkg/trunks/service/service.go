@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"getsturdy.com/api/pkg/analytics"
+	service_analytics "getsturdy.com/api/pkg/analytics/service"
+	"getsturdy.com/api/pkg/changes"
+	service_changes "getsturdy.com/api/pkg/changes/service"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/trunks"
+	db_trunks "getsturdy.com/api/pkg/trunks/db"
+	"getsturdy.com/api/pkg/users"
+	"getsturdy.com/api/vcs"
+	"getsturdy.com/api/vcs/executor"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidName         = errors.New("invalid trunk name")
+	ErrNameTaken           = errors.New("a trunk with this name already exists")
+	ErrTrackedBranchTaken  = errors.New("the branch is already tracked by another trunk")
+	ErrNoDefaultTrunk      = errors.New("the codebase does not have any changes yet")
+	ErrChangeNotInCodebase = errors.New("change does not belong to this codebase")
+)
+
+const maxNameLength = 100
+
+type Service struct {
+	repo db_trunks.Repository
+
+	changeService    *service_changes.Service
+	analyticsService *service_analytics.Service
+	executorProvider executor.Provider
+}
+
+func New(
+	repo db_trunks.Repository,
+
+	changeService *service_changes.Service,
+	analyticsService *service_analytics.Service,
+	executorProvider executor.Provider,
+) *Service {
+	return &Service{
+		repo: repo,
+
+		changeService:    changeService,
+		analyticsService: analyticsService,
+		executorProvider: executorProvider,
+	}
+}
+
+type CreateRequest struct {
+	CodebaseID codebases.ID
+	UserID     users.ID
+	Name       string
+
+	// BaseChangeID is the change that the new trunk starts at. If nil, the trunk starts at the head of the default
+	// trunk.
+	BaseChangeID *changes.ID
+}
+
+// Create creates a new trunk in the codebase, and its git branch.
+func (s *Service) Create(ctx context.Context, req CreateRequest) (*trunks.Trunk, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > maxNameLength {
+		return nil, ErrInvalidName
+	}
+
+	if _, err := s.repo.GetByName(ctx, req.CodebaseID, name); err == nil {
+		return nil, ErrNameTaken
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get trunk by name: %w", err)
+	}
+
+	var baseCommitID string
+	if req.BaseChangeID != nil {
+		ch, err := s.changeService.GetChangeByID(ctx, *req.BaseChangeID)
+		if err != nil {
+			return nil, fmt.Errorf("could not get change by id: %w", err)
+		}
+		if ch.CodebaseID != req.CodebaseID {
+			return nil, ErrChangeNotInCodebase
+		}
+		if ch.CommitID == nil {
+			return nil, fmt.Errorf("the change does not have a commit")
+		}
+		baseCommitID = *ch.CommitID
+	}
+
+	trunk := &trunks.Trunk{
+		ID:         trunks.ID(uuid.NewString()),
+		CodebaseID: req.CodebaseID,
+		Name:       name,
+		CreatedBy:  req.UserID,
+		CreatedAt:  time.Now(),
+	}
+
+	if err := s.executorProvider.New().GitWrite(func(repo vcs.RepoGitWriter) error {
+		if baseCommitID == "" {
+			headCommitID, err := repo.BranchCommitID(trunks.DefaultBranchName)
+			if err != nil {
+				return ErrNoDefaultTrunk
+			}
+			baseCommitID = headCommitID
+		}
+		if err := repo.CreateNewBranchAt(trunk.BranchName(), baseCommitID); err != nil {
+			return fmt.Errorf("failed to create branch: %w", err)
+		}
+		return nil
+	}).ExecTrunkContext(ctx, req.CodebaseID, "createTrunk"); err != nil {
+		return nil, fmt.Errorf("failed to create trunk branch: %w", err)
+	}
+
+	if err := s.repo.Create(ctx, trunk); err != nil {
+		return nil, fmt.Errorf("failed to create trunk: %w", err)
+	}
+
+	s.analyticsService.CaptureUser(ctx, req.UserID, "created trunk",
+		analytics.CodebaseID(req.CodebaseID),
+		analytics.Property("trunk_id", trunk.ID),
+		analytics.Property("at_existing_change", req.BaseChangeID != nil),
+	)
+
+	return trunk, nil
+}
+
+func (s *Service) Get(ctx context.Context, id trunks.ID) (*trunks.Trunk, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// IsBranchName returns true if name is the git branch of the default trunk, or of one of the trunks of the codebase.
+func (s *Service) IsBranchName(ctx context.Context, codebaseID codebases.ID, name string) (bool, error) {
+	id, ok := trunks.IDFromBranchName(name)
+	if !ok {
+		return false, nil
+	}
+	if id == nil {
+		return true, nil
+	}
+	trunk, err := s.repo.Get(ctx, *id)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("failed to get trunk: %w", err)
+	}
+	return trunk.CodebaseID == codebaseID, nil
+}
+
+// List returns all trunks of the codebase, except for the default trunk.
+func (s *Service) List(ctx context.Context, codebaseID codebases.ID) ([]*trunks.Trunk, error) {
+	return s.repo.ListByCodebaseID(ctx, codebaseID)
+}
+
+// SetTrackedBranch sets the branch on the remote that the trunk is pushed to and pulled from. A nil branch stops
+// syncing the trunk with the remote.
+func (s *Service) SetTrackedBranch(ctx context.Context, trunk *trunks.Trunk, branch *string) error {
+	if branch != nil {
+		trimmed := strings.TrimSpace(*branch)
+		if trimmed == "" {
+			return fmt.Errorf("tracked branch can not be empty")
+		}
+		branch = &trimmed
+
+		tt, err := s.repo.ListByCodebaseID(ctx, trunk.CodebaseID)
+		if err != nil {
+			return fmt.Errorf("failed to list trunks: %w", err)
+		}
+		for _, t := range tt {
+			if t.ID != trunk.ID && t.TrackedBranch != nil && *t.TrackedBranch == *branch {
+				return ErrTrackedBranchTaken
+			}
+		}
+	}
+
+	trunk.TrackedBranch = branch
+	if err := s.repo.Update(ctx, trunk); err != nil {
+		return fmt.Errorf("failed to update trunk: %w", err)
+	}
+	return nil
+}
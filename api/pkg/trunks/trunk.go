@@ -0,0 +1,66 @@
+package trunks
+
+import (
+	"strings"
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/users"
+)
+
+// DefaultBranchName is the name of the git branch of the default trunk of a codebase.
+const DefaultBranchName = "sturdytrunk"
+
+type ID string
+
+func (id ID) String() string {
+	return string(id)
+}
+
+// Trunk is a named, long-lived line of changes in a codebase, such as a release branch. Every codebase has an implicit
+// default trunk, that is not stored as a Trunk. Workspaces that are not created against a Trunk are based on, and land
+// to, the default trunk.
+type Trunk struct {
+	ID         ID           `db:"id"`
+	CodebaseID codebases.ID `db:"codebase_id"`
+	Name       string       `db:"name"`
+
+	// TrackedBranch is the name of the branch on the remote of the codebase that the trunk is pushed to and pulled
+	// from. If nil, the trunk is not synced with the remote.
+	TrackedBranch *string `db:"tracked_branch"`
+
+	CreatedBy users.ID  `db:"created_by"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// BranchName returns the name of the git branch of the trunk.
+func (t *Trunk) BranchName() string {
+	return BranchName(&t.ID)
+}
+
+// BranchName returns the name of the git branch of the trunk with the given id. A nil id is the default trunk.
+func BranchName(id *ID) string {
+	if id == nil {
+		return DefaultBranchName
+	}
+	return DefaultBranchName + "-" + id.String()
+}
+
+// IsBranchName returns true if name is the name of the git branch of any trunk.
+func IsBranchName(name string) bool {
+	_, ok := IDFromBranchName(name)
+	return ok
+}
+
+// IDFromBranchName returns the id of the trunk that name is the git branch of. A nil id is the default trunk. If name
+// is not the name of a trunk branch, ok is false.
+func IDFromBranchName(name string) (id *ID, ok bool) {
+	if name == DefaultBranchName {
+		return nil, true
+	}
+	if suffix := strings.TrimPrefix(name, DefaultBranchName+"-"); suffix != name && suffix != "" {
+		trunkID := ID(suffix)
+		return &trunkID, true
+	}
+	return nil, false
+}
@@ -0,0 +1,47 @@
+package trunks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBranchName(t *testing.T) {
+	id := ID("release")
+	assert.Equal(t, "sturdytrunk", BranchName(nil))
+	assert.Equal(t, "sturdytrunk-release", BranchName(&id))
+	assert.Equal(t, "sturdytrunk-release", (&Trunk{ID: id}).BranchName())
+}
+
+func TestIsBranchName(t *testing.T) {
+	cases := []struct {
+		name     string
+		expected bool
+	}{
+		{"sturdytrunk", true},
+		{"sturdytrunk-release", true},
+		{"main", false},
+		{"sturdytrunks", false},
+		{"feature/sturdytrunk", false},
+		{"sturdytrunk-", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, IsBranchName(tc.name))
+		})
+	}
+}
+
+func TestIDFromBranchName(t *testing.T) {
+	id, ok := IDFromBranchName("sturdytrunk")
+	assert.True(t, ok)
+	assert.Nil(t, id)
+
+	id, ok = IDFromBranchName("sturdytrunk-release")
+	if assert.True(t, ok) && assert.NotNil(t, id) {
+		assert.Equal(t, ID("release"), *id)
+	}
+
+	_, ok = IDFromBranchName("main")
+	assert.False(t, ok)
+}
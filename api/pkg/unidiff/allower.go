@@ -175,3 +175,19 @@ func (i *Allower) IsAllowed(path string, directory bool) bool {
 	// Done.
 	return allowed
 }
+
+// AllowsAll returns true if every path is allowed, except for the .git directory, which is never allowed.
+func (i *Allower) AllowsAll() bool {
+	allowsAll := false
+	for _, p := range i.patterns {
+		switch {
+		case p.negated && (p.pattern == ".git" || strings.HasPrefix(p.pattern, ".git/")):
+			continue
+		case p.negated:
+			allowsAll = false
+		case !p.directoryOnly && (p.pattern == "*" || p.pattern == "**"):
+			allowsAll = true
+		}
+	}
+	return allowsAll
+}
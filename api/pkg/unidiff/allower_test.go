@@ -259,3 +259,26 @@ func TestAllower_GitIsAlwaysHidden_evenIfAllowed(t *testing.T) {
 	}
 	test.run(t)
 }
+
+func TestAllower_AllowsAll(t *testing.T) {
+	cases := []struct {
+		allows   []string
+		expected bool
+	}{
+		{nil, false},
+		{[]string{"*"}, true},
+		{[]string{"**"}, true},
+		{[]string{"*", "!.git"}, true},
+		{[]string{"src/**", "README.md"}, false},
+		{[]string{"*/"}, false},
+		{[]string{"*", "!infra/**"}, false},
+		{[]string{"!infra/**", "*"}, true},
+		{[]string{"*", "!infra/**", "infra/main.tf"}, false},
+	}
+
+	for _, tc := range cases {
+		allower, err := unidiff.NewAllower(tc.allows...)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, allower.AllowsAll(), "%v", tc.allows)
+	}
+}
@@ -38,6 +38,17 @@ type FileDiff struct {
 	Hunks []Hunk `json:"hunks"`
 }
 
+// Paths returns the paths of the files that are changed by the diff, both the original and the new name of the file.
+func (fd FileDiff) Paths() []string {
+	var paths []string
+	for _, path := range []string{fd.OrigName, fd.NewName} {
+		if path != "" && path != "/dev/null" && (len(paths) == 0 || paths[0] != path) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
 type LargeFileInfo struct {
 	Size uint64 `json:"size"`
 }
@@ -270,7 +270,7 @@ func (r *ViewRootResolver) RepairView(ctx context.Context, args struct{ ID graph
 			return err
 		}
 		return nil
-	}).ExecView(ws.CodebaseID, vw.ID, "repairView")
+	}).ExecViewContext(ctx, ws.CodebaseID, vw.ID, "repairView")
 	if err != nil {
 		return nil, gqlerrors.Error(err)
 	}
@@ -455,7 +455,7 @@ func (r *Resolver) IgnoredPaths(ctx context.Context) ([]string, error) {
 				return err
 			}
 			return nil
-		}).ExecView(r.v.CodebaseID, r.v.ID, "findIgnores")
+		}).ExecViewContext(ctx, r.v.CodebaseID, r.v.ID, "findIgnores")
 	if err != nil {
 		return nil, err
 	}
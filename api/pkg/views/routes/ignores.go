@@ -32,7 +32,7 @@ func Ignores(logger *zap.Logger, executorProvider executor.Provider, viewRepo db
 					return err
 				}
 				return nil
-			}).ExecView(view.CodebaseID, view.ID, "findIgnores")
+			}).ExecViewContext(c.Request.Context(), view.CodebaseID, view.ID, "findIgnores")
 		if err != nil {
 			logger.Error("failed to find ignores", zap.Error(err))
 			c.AbortWithStatus(http.StatusInternalServerError)
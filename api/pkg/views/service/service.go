@@ -129,7 +129,7 @@ func (s *Service) OpenWorkspace(ctx context.Context, view *views.View, ws *works
 				}
 			}
 			return nil
-		}).ExecView(ws.CodebaseID, view.ID, "OpenWorkspaceOnView"); err != nil {
+		}).ExecViewContext(ctx, ws.CodebaseID, view.ID, "OpenWorkspaceOnView"); err != nil {
 		return fmt.Errorf("failed to open workspace on view: %w", err)
 	}
 
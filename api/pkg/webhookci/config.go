@@ -0,0 +1,20 @@
+package webhookci
+
+import (
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+)
+
+// Config is the configuration of a webhook integration. Builds are started by sending a signed request to URL, and
+// statuses are reported back to Sturdy by sending signed requests to the callback url of the integration.
+type Config struct {
+	ID            string       `db:"id"`
+	CodebaseID    codebases.ID `db:"codebase_id"`
+	IntegrationID string       `db:"integration_id"`
+
+	URL       string     `db:"url"`
+	Secret    string     `db:"secret"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at"`
+}
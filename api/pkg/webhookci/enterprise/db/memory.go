@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"getsturdy.com/api/pkg/webhookci"
+)
+
+var _ Repository = &memory{}
+
+type memory struct {
+	byIntegrationID map[string]*webhookci.Config
+}
+
+func NewInMemory() *memory {
+	return &memory{
+		byIntegrationID: make(map[string]*webhookci.Config),
+	}
+}
+
+func (m *memory) Create(ctx context.Context, cfg *webhookci.Config) error {
+	m.byIntegrationID[cfg.IntegrationID] = cfg
+	return nil
+}
+
+func (m *memory) Update(ctx context.Context, cfg *webhookci.Config) error {
+	m.byIntegrationID[cfg.IntegrationID] = cfg
+	return nil
+}
+
+func (m *memory) GetConfigByIntegrationID(ctx context.Context, integrationID string) (*webhookci.Config, error) {
+	cfg, found := m.byIntegrationID[integrationID]
+	if !found {
+		return nil, sql.ErrNoRows
+	}
+	return cfg, nil
+}
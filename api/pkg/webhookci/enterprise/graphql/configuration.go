@@ -0,0 +1,28 @@
+package graphql
+
+import (
+	"github.com/graph-gophers/graphql-go"
+
+	"getsturdy.com/api/pkg/webhookci"
+)
+
+type webhookConfigurationResolver struct {
+	config      *webhookci.Config
+	callbackURL string
+}
+
+func (r *webhookConfigurationResolver) ID() graphql.ID {
+	return graphql.ID(r.config.ID)
+}
+
+func (r *webhookConfigurationResolver) URL() string {
+	return r.config.URL
+}
+
+func (r *webhookConfigurationResolver) Secret() string {
+	return r.config.Secret
+}
+
+func (r *webhookConfigurationResolver) CallbackURL() string {
+	return r.callbackURL
+}
@@ -0,0 +1,155 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	service_ci "getsturdy.com/api/pkg/ci/service"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/codebases/acl"
+	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
+	"getsturdy.com/api/pkg/graphql/resolvers"
+	"getsturdy.com/api/pkg/integrations"
+	"getsturdy.com/api/pkg/integrations/providers"
+	"getsturdy.com/api/pkg/webhookci"
+	service_webhookci "getsturdy.com/api/pkg/webhookci/enterprise/service"
+
+	"github.com/google/uuid"
+)
+
+type rootResolver struct {
+	authService                    *service_auth.Service
+	webhookService                 *service_webhookci.Service
+	instantIntegrationService      *service_ci.Service
+	instantIntegrationRootResolver *resolvers.IntegrationRootResolver
+}
+
+func New(
+	authService *service_auth.Service,
+	webhookService *service_webhookci.Service,
+	instantIntegrationService *service_ci.Service,
+	instantIntegrationRootResolver *resolvers.IntegrationRootResolver,
+) resolvers.WebhookInstantIntegrationRootResolver {
+	return &rootResolver{
+		authService:                    authService,
+		webhookService:                 webhookService,
+		instantIntegrationService:      instantIntegrationService,
+		instantIntegrationRootResolver: instantIntegrationRootResolver,
+	}
+}
+
+func (root *rootResolver) createNewConfiguration(ctx context.Context, args resolvers.CreateOrUpdateWebhookIntegrationArgs) (*integrations.Integration, error) {
+	integration := &integrations.Integration{
+		ID:           uuid.NewString(),
+		CodebaseID:   codebases.ID(args.Input.CodebaseID),
+		Provider:     providers.ProviderNameWebhook,
+		ProviderType: providers.ProviderTypeBuild,
+		CreatedAt:    time.Now(),
+	}
+
+	if err := root.instantIntegrationService.CreateIntegration(ctx, integration); err != nil {
+		return nil, fmt.Errorf("failed to create integration: %w", err)
+	}
+
+	cfg := &webhookci.Config{
+		ID:            uuid.NewString(),
+		IntegrationID: integration.ID,
+		CodebaseID:    codebases.ID(args.Input.CodebaseID),
+		URL:           args.Input.URL,
+		Secret:        args.Input.Secret,
+		CreatedAt:     time.Now(),
+	}
+
+	if err := root.webhookService.CreateIntegration(ctx, cfg); err != nil {
+		return nil, fmt.Errorf("failed to create configuration: %w", err)
+	}
+
+	return integration, nil
+}
+
+func (root *rootResolver) updateConfiguration(ctx context.Context, integration *integrations.Integration, args resolvers.CreateOrUpdateWebhookIntegrationArgs) (*integrations.Integration, error) {
+	existingCfg, err := root.webhookService.GetConfigurationByIntegrationID(ctx, integration.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configuration: %w", err)
+	}
+
+	configChanged := existingCfg.URL != args.Input.URL ||
+		existingCfg.Secret != args.Input.Secret
+
+	if !configChanged {
+		return integration, nil
+	}
+
+	now := time.Now()
+	existingCfg.URL = args.Input.URL
+	existingCfg.Secret = args.Input.Secret
+	existingCfg.UpdatedAt = &now
+	if err := root.webhookService.UpdateIntegration(ctx, existingCfg); err != nil {
+		return nil, fmt.Errorf("failed to update configuration: %w", err)
+	}
+
+	integration.UpdatedAt = now
+	if err := root.instantIntegrationService.UpdateIntegration(ctx, integration); err != nil {
+		return nil, fmt.Errorf("failed to update integration: %w", err)
+	}
+
+	return integration, nil
+}
+
+func (root *rootResolver) CreateOrUpdateWebhookIntegration(ctx context.Context, args resolvers.CreateOrUpdateWebhookIntegrationArgs) (resolvers.IntegrationResolver, error) {
+	if err := root.authService.CanWrite(ctx, &codebases.Codebase{ID: codebases.ID(args.Input.CodebaseID)}); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if err := root.authService.CanPerform(ctx, codebases.ID(args.Input.CodebaseID), acl.ActionManageIntegrations, acl.Identity{Type: acl.Integrations, ID: string(providers.ProviderNameWebhook)}); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if u, err := url.Parse(args.Input.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "url", "must be a valid http or https url")
+	}
+
+	if args.Input.Secret == "" {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "secret", "must not be empty")
+	}
+
+	// Create new
+	if args.Input.IntegrationID == nil {
+		integration, err := root.createNewConfiguration(ctx, args)
+		if err != nil {
+			return nil, gqlerrors.Error(fmt.Errorf("failed to create new configuration: %w", err))
+		}
+		return (*root.instantIntegrationRootResolver).InternalIntegrationProvider(integration), nil
+	}
+
+	// Update existing
+	integration, err := root.instantIntegrationService.GetByID(ctx, string(*args.Input.IntegrationID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if integration.CodebaseID != codebases.ID(args.Input.CodebaseID) || integration.Provider != providers.ProviderNameWebhook {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "integrationID", "not a webhook integration of the codebase")
+	}
+
+	integration, err = root.updateConfiguration(ctx, integration, args)
+	if err != nil {
+		return nil, gqlerrors.Error(fmt.Errorf("failed to update existing configuration: %w", err))
+	}
+
+	return (*root.instantIntegrationRootResolver).InternalIntegrationProvider(integration), nil
+}
+
+func (r *rootResolver) InternalWebhookConfigurationByIntegrationID(ctx context.Context, integrationID string) (resolvers.WebhookConfigurationResolver, error) {
+	cfg, err := r.webhookService.GetConfigurationByIntegrationID(ctx, integrationID)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+	return &webhookConfigurationResolver{
+		config:      cfg,
+		callbackURL: r.webhookService.CallbackURL(integrationID),
+	}, nil
+}
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"getsturdy.com/api/pkg/ci"
+	configuration "getsturdy.com/api/pkg/configuration/module"
+	"getsturdy.com/api/pkg/di"
+	db_webhookci "getsturdy.com/api/pkg/webhookci/enterprise/db"
+)
+
+func Module(c *di.Container) {
+	c.Import(db_webhookci.Module)
+	c.Import(configuration.Module)
+	c.Register(New)
+	c.Register(func(svc *Service) ci.BuildProviderOut { return ci.BuildProviderOut{BuildProvider: svc} })
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"getsturdy.com/api/pkg/ci"
+	"getsturdy.com/api/pkg/ci/service/configuration"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/integrations/providers"
+	"getsturdy.com/api/pkg/statuses"
+	"getsturdy.com/api/pkg/webhookci"
+	db_webhookci "getsturdy.com/api/pkg/webhookci/enterprise/db"
+
+	"github.com/google/uuid"
+)
+
+var _ ci.BuildProvider = &Service{}
+
+var (
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrForbiddenAddress = errors.New("address is not allowed")
+)
+
+const (
+	// SignatureHeader is the header that contains the signature of requests sent to, and received from the
+	// webhook integration. The format is the same as Buildkite's X-Buildkite-Signature:
+	//
+	//   timestamp=1637075221,signature=dbdabe3596995f7bd1f39f50f135df4c48e4291f5368c0eb5c5a02664ae536e9
+	//
+	// where the signature is the hex encoded HMAC-SHA256 of "<timestamp>.<body>", keyed with the secret of the
+	// integration.
+	SignatureHeader = "X-Sturdy-Signature"
+
+	EventBuildCreate = "build.create"
+	EventBuildCancel = "build.cancel"
+)
+
+var (
+	// allowedWindow is how old the timestamp of a signature can be.
+	allowedWindow = 5 * time.Minute
+	// allowedSkew is how far in the future the timestamp of a signature can be, to allow for clock skew.
+	allowedSkew = time.Minute
+)
+
+type Service struct {
+	configRepo        db_webhookci.Repository
+	publicApiHostname string
+	client            *http.Client
+}
+
+func New(
+	configRepo db_webhookci.Repository,
+	cfg *configuration.Configuration,
+) *Service {
+	return &Service{
+		configRepo:        configRepo,
+		publicApiHostname: cfg.PublicAPIHostname,
+		client:            newHTTPClient(cfg.AllowPrivateWebhookAddresses),
+	}
+}
+
+// newHTTPClient returns the client that build events are sent with. Unless private addresses are allowed, the address
+// of every connection is checked after the host has been resolved, so that the integration can not be used to reach
+// internal services.
+func newHTTPClient(allowPrivateAddresses bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	if !allowPrivateAddresses {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || forbiddenIP(ip) {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// a proxy would make the connection on our behalf, without the address check
+	transport.Proxy = nil
+
+	return &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: transport,
+	}
+}
+
+// forbiddenIP returns true if ip is a loopback, link-local, private, unspecified or multicast address.
+func forbiddenIP(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsPrivate() ||
+		ip.IsUnspecified()
+}
+
+func (s *Service) CreateIntegration(ctx context.Context, cfg *webhookci.Config) error {
+	return s.configRepo.Create(ctx, cfg)
+}
+
+func (s *Service) UpdateIntegration(ctx context.Context, cfg *webhookci.Config) error {
+	return s.configRepo.Update(ctx, cfg)
+}
+
+func (s *Service) GetConfigurationByIntegrationID(ctx context.Context, integrationID string) (*webhookci.Config, error) {
+	return s.configRepo.GetConfigByIntegrationID(ctx, integrationID)
+}
+
+// CallbackURL is the url that statuses of the builds should be sent to.
+func (s *Service) CallbackURL(integrationID string) string {
+	return fmt.Sprintf("https://%s/v3/statuses/webhook/%s", s.publicApiHostname, integrationID)
+}
+
+func (s *Service) ProviderName() providers.ProviderName {
+	return providers.ProviderNameWebhook
+}
+
+// buildEvent is sent to the url of the integration.
+type buildEvent struct {
+	Event       string       `json:"event"`
+	BuildID     string       `json:"build_id"`
+	CodebaseID  codebases.ID `json:"codebase_id"`
+	CommitSHA   string       `json:"commit_sha,omitempty"`
+	Title       string       `json:"title,omitempty"`
+	CallbackURL string       `json:"callback_url"`
+}
+
+// buildEventResponse can optionally be returned by the webhook receiver, to link the status to the build.
+type buildEventResponse struct {
+	URL         string  `json:"url"`
+	Description *string `json:"description"`
+}
+
+func (s *Service) CreateBuild(ctx context.Context, integrationID, ciCommitSHA, title string) (*ci.Build, error) {
+	cfg, err := s.configRepo.GetConfigByIntegrationID(ctx, integrationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config by integration id: %w", err)
+	}
+
+	event := &buildEvent{
+		Event:       EventBuildCreate,
+		BuildID:     uuid.NewString(),
+		CodebaseID:  cfg.CodebaseID,
+		CommitSHA:   ciCommitSHA,
+		Title:       title,
+		CallbackURL: s.CallbackURL(integrationID),
+	}
+
+	response, err := s.send(ctx, cfg, event)
+	if err != nil {
+		return nil, err
+	}
+
+	build := &ci.Build{
+		ID:   event.BuildID,
+		Name: title,
+	}
+
+	if len(bytes.TrimSpace(response)) > 0 {
+		var res buildEventResponse
+		if err := json.Unmarshal(response, &res); err != nil {
+			return nil, fmt.Errorf("failed to read response (%s): %w", string(response), err)
+		}
+		build.URL = res.URL
+		build.Description = res.Description
+	}
+
+	return build, nil
+}
+
+func (s *Service) CancelBuild(ctx context.Context, integrationID, buildID string) error {
+	cfg, err := s.configRepo.GetConfigByIntegrationID(ctx, integrationID)
+	if err != nil {
+		return fmt.Errorf("failed to get config by integration id: %w", err)
+	}
+
+	if _, err := s.send(ctx, cfg, &buildEvent{
+		Event:       EventBuildCancel,
+		BuildID:     buildID,
+		CodebaseID:  cfg.CodebaseID,
+		CallbackURL: s.CallbackURL(integrationID),
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Service) send(ctx context.Context, cfg *webhookci.Config, event *buildEvent) ([]byte, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build json: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, signatureHeader(cfg.Secret, time.Now(), body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send %s: %w", event.Event, err)
+	}
+	defer resp.Body.Close()
+
+	response, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(response))
+	}
+
+	return response, nil
+}
+
+// statusCallback is sent by the ci system to the callback url of the integration.
+type statusCallback struct {
+	// CommitSHA is the commit_sha from the build event.
+	CommitSHA   string  `json:"commit_sha"`
+	State       string  `json:"state"`
+	Title       string  `json:"title"`
+	Description *string `json:"description"`
+	DetailsURL  *string `json:"details_url"`
+}
+
+func (s *Service) StatusFromWebhook(ctx context.Context, integrationID string, header http.Header, body []byte) (*statuses.Status, error) {
+	cfg, err := s.configRepo.GetConfigByIntegrationID(ctx, integrationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config by integration id: %w", err)
+	}
+
+	if err := validateSignature(cfg.Secret, header.Get(SignatureHeader), time.Now(), body); err != nil {
+		return nil, err
+	}
+
+	var callback statusCallback
+	if err := json.Unmarshal(body, &callback); err != nil {
+		return nil, fmt.Errorf("failed to parse payload: %w", err)
+	}
+
+	statusType := statuses.Type(callback.State)
+	if !statuses.ValidType[statusType] {
+		return nil, fmt.Errorf("invalid state: %s", callback.State)
+	}
+
+	if callback.CommitSHA == "" {
+		return nil, fmt.Errorf("commit_sha is not set")
+	}
+
+	if callback.Title == "" {
+		return nil, fmt.Errorf("title is not set")
+	}
+
+	return &statuses.Status{
+		CommitSHA:   callback.CommitSHA,
+		Type:        statusType,
+		Title:       callback.Title,
+		Description: callback.Description,
+		DetailsURL:  callback.DetailsURL,
+	}, nil
+}
+
+func sign(secret string, timestamp time.Time, body []byte) string {
+	hmacSum := hmac.New(sha256.New, []byte(secret))
+	_, _ = hmacSum.Write([]byte(fmt.Sprint(timestamp.Unix())))
+	_, _ = hmacSum.Write([]byte("."))
+	_, _ = hmacSum.Write(body)
+	return fmt.Sprintf("%x", hmacSum.Sum(nil))
+}
+
+func signatureHeader(secret string, timestamp time.Time, body []byte) string {
+	return fmt.Sprintf("timestamp=%d,signature=%s", timestamp.Unix(), sign(secret, timestamp, body))
+}
+
+func validateSignature(secret, header string, now time.Time, body []byte) error {
+	var timestamp time.Time
+	var signature string
+	for _, param := range strings.Split(header, ",") {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) != 2 {
+			return ErrInvalidSignature
+		}
+		switch kv[0] {
+		case "timestamp":
+			ts, err := strconv.ParseInt(kv[1], 10, 64)
+			if err != nil {
+				return ErrInvalidSignature
+			}
+			timestamp = time.Unix(ts, 0)
+		case "signature":
+			signature = kv[1]
+		}
+	}
+
+	if timestamp.After(now.Add(allowedSkew)) || timestamp.Before(now.Add(-1*allowedWindow)) {
+		return ErrInvalidSignature
+	}
+
+	if !hmac.Equal([]byte(sign(secret, timestamp, body)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
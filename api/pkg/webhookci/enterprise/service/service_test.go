@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"getsturdy.com/api/pkg/ci/service/configuration"
+	"getsturdy.com/api/pkg/statuses"
+	"getsturdy.com/api/pkg/webhookci"
+	db_webhookci "getsturdy.com/api/pkg/webhookci/enterprise/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSignature(t *testing.T) {
+	now := time.Now()
+	body := []byte(`{"hello":"world"}`)
+
+	assert.NoError(t, validateSignature("secret", signatureHeader("secret", now, body), now, body))
+	assert.NoError(t, validateSignature("secret", signatureHeader("secret", now.Add(-time.Minute), body), now, body))
+	assert.NoError(t, validateSignature("secret", signatureHeader("secret", now.Add(30*time.Second), body), now, body), "small clock skew is allowed")
+
+	assert.ErrorIs(t, validateSignature("other", signatureHeader("secret", now, body), now, body), ErrInvalidSignature)
+	assert.ErrorIs(t, validateSignature("secret", signatureHeader("secret", now, body), now, []byte("{}")), ErrInvalidSignature)
+	assert.ErrorIs(t, validateSignature("secret", signatureHeader("secret", now.Add(-time.Hour), body), now, body), ErrInvalidSignature)
+	assert.ErrorIs(t, validateSignature("secret", signatureHeader("secret", now.Add(2*time.Minute), body), now, body), ErrInvalidSignature)
+	assert.ErrorIs(t, validateSignature("secret", "", now, body), ErrInvalidSignature)
+}
+
+func TestCreateBuild(t *testing.T) {
+	var received buildEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, validateSignature("secret", r.Header.Get(SignatureHeader), time.Now(), body))
+		assert.NoError(t, json.Unmarshal(body, &received))
+		_, _ = w.Write([]byte(`{"url":"https://ci.example.com/builds/1"}`))
+	}))
+	defer srv.Close()
+
+	repo := db_webhookci.NewInMemory()
+	assert.NoError(t, repo.Create(context.Background(), &webhookci.Config{
+		ID:            "config-id",
+		CodebaseID:    "codebase-id",
+		IntegrationID: "integration-id",
+		URL:           srv.URL,
+		Secret:        "secret",
+	}))
+
+	// the test server listens on a loopback address
+	svc := New(repo, &configuration.Configuration{PublicAPIHostname: "api.example.com", AllowPrivateWebhookAddresses: true})
+
+	build, err := svc.CreateBuild(context.Background(), "integration-id", "ci-commit-sha", "title")
+	if assert.NoError(t, err) {
+		assert.Equal(t, received.BuildID, build.ID)
+		assert.Equal(t, "https://ci.example.com/builds/1", build.URL)
+	}
+
+	assert.Equal(t, EventBuildCreate, received.Event)
+	assert.Equal(t, "ci-commit-sha", received.CommitSHA)
+	assert.Equal(t, "https://api.example.com/v3/statuses/webhook/integration-id", received.CallbackURL)
+}
+
+func TestCreateBuild_privateAddress(t *testing.T) {
+	var called bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	repo := db_webhookci.NewInMemory()
+	assert.NoError(t, repo.Create(context.Background(), &webhookci.Config{
+		ID:            "config-id",
+		CodebaseID:    "codebase-id",
+		IntegrationID: "integration-id",
+		URL:           srv.URL,
+		Secret:        "secret",
+	}))
+
+	svc := New(repo, &configuration.Configuration{PublicAPIHostname: "api.example.com"})
+
+	_, err := svc.CreateBuild(context.Background(), "integration-id", "ci-commit-sha", "title")
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+	assert.False(t, called)
+}
+
+func TestStatusFromWebhook(t *testing.T) {
+	repo := db_webhookci.NewInMemory()
+	assert.NoError(t, repo.Create(context.Background(), &webhookci.Config{
+		ID:            "config-id",
+		CodebaseID:    "codebase-id",
+		IntegrationID: "integration-id",
+		URL:           "https://ci.example.com",
+		Secret:        "secret",
+	}))
+
+	svc := New(repo, &configuration.Configuration{})
+
+	body := []byte(`{"commit_sha":"ci-commit-sha","state":"healthy","title":"tests"}`)
+	header := http.Header{}
+	header.Set(SignatureHeader, signatureHeader("secret", time.Now(), body))
+
+	status, err := svc.StatusFromWebhook(context.Background(), "integration-id", header, body)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "ci-commit-sha", status.CommitSHA)
+		assert.Equal(t, statuses.TypeHealthy, status.Type)
+		assert.Equal(t, "tests", status.Title)
+	}
+
+	header.Set(SignatureHeader, signatureHeader("wrong", time.Now(), body))
+	_, err = svc.StatusFromWebhook(context.Background(), "integration-id", header, body)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	body = []byte(`{"commit_sha":"ci-commit-sha","state":"unknown","title":"tests"}`)
+	header.Set(SignatureHeader, signatureHeader("secret", time.Now(), body))
+	_, err = svc.StatusFromWebhook(context.Background(), "integration-id", header, body)
+	assert.Error(t, err)
+}
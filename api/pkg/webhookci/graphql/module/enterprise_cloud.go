@@ -0,0 +1,13 @@
+//go:build cloud || enterprise
+// +build cloud enterprise
+
+package graphql
+
+import (
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/webhookci/enterprise/graphql"
+)
+
+func Module(c *di.Container) {
+	c.Import(graphql.Module)
+}
@@ -0,0 +1,22 @@
+package graphql
+
+import (
+	"context"
+
+	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
+	"getsturdy.com/api/pkg/graphql/resolvers"
+)
+
+type rootResolver struct{}
+
+func New() resolvers.WebhookInstantIntegrationRootResolver {
+	return &rootResolver{}
+}
+
+func (root *rootResolver) CreateOrUpdateWebhookIntegration(ctx context.Context, args resolvers.CreateOrUpdateWebhookIntegrationArgs) (resolvers.IntegrationResolver, error) {
+	return nil, gqlerrors.ErrNotImplemented
+}
+
+func (r *rootResolver) InternalWebhookConfigurationByIntegrationID(ctx context.Context, integrationID string) (resolvers.WebhookConfigurationResolver, error) {
+	return nil, gqlerrors.ErrNotImplemented
+}
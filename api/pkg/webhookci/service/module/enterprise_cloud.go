@@ -0,0 +1,13 @@
+//go:build enterprise || cloud
+// +build enterprise cloud
+
+package module
+
+import (
+	"getsturdy.com/api/pkg/di"
+	service_webhookci "getsturdy.com/api/pkg/webhookci/enterprise/service"
+)
+
+func Module(c *di.Container) {
+	c.Import(service_webhookci.Module)
+}
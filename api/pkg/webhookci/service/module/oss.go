@@ -0,0 +1,11 @@
+//go:build !enterprise && !cloud
+// +build !enterprise,!cloud
+
+package module
+
+import (
+	"getsturdy.com/api/pkg/di"
+)
+
+// Module is empty, webhook integrations are not available in the OSS version of Sturdy.
+func Module(c *di.Container) {}
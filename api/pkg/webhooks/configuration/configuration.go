@@ -0,0 +1,5 @@
+package configuration
+
+type Configuration struct {
+	AllowPrivateAddresses bool `long:"allow-private-addresses" description:"Allow webhooks to be delivered to loopback, link-local and private network addresses"`
+}
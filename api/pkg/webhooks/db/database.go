@@ -0,0 +1,152 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/webhooks"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var _ Repository = &database{}
+
+type database struct {
+	db *sqlx.DB
+}
+
+func NewDatabase(db *sqlx.DB) Repository {
+	return &database{
+		db: db,
+	}
+}
+
+func (d *database) Create(ctx context.Context, webhook *webhooks.Webhook) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		INSERT INTO webhooks (
+			id, codebase_id, url, secret, events, created_by, created_at, updated_at, deleted_at
+		) VALUES (
+			:id, :codebase_id, :url, :secret, :events, :created_by, :created_at, :updated_at, :deleted_at
+		)
+	`, webhook); err != nil {
+		return fmt.Errorf("failed to insert: %w", err)
+	}
+	return nil
+}
+
+func (d *database) Update(ctx context.Context, webhook *webhooks.Webhook) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		UPDATE webhooks
+		SET url = :url,
+			secret = :secret,
+			events = :events,
+			updated_at = :updated_at,
+			deleted_at = :deleted_at
+		WHERE id = :id
+	`, webhook); err != nil {
+		return fmt.Errorf("failed to update: %w", err)
+	}
+	return nil
+}
+
+func (d *database) Get(ctx context.Context, id webhooks.ID) (*webhooks.Webhook, error) {
+	webhook := &webhooks.Webhook{}
+	if err := d.db.GetContext(ctx, webhook, `
+		SELECT
+			id, codebase_id, url, secret, events, created_by, created_at, updated_at, deleted_at
+		FROM
+			webhooks
+		WHERE
+			id = $1
+	`, id); err != nil {
+		return nil, fmt.Errorf("failed to get: %w", err)
+	}
+	return webhook, nil
+}
+
+func (d *database) ListByCodebaseID(ctx context.Context, codebaseID codebases.ID) ([]*webhooks.Webhook, error) {
+	var res []*webhooks.Webhook
+	if err := d.db.SelectContext(ctx, &res, `
+		SELECT
+			id, codebase_id, url, secret, events, created_by, created_at, updated_at, deleted_at
+		FROM
+			webhooks
+		WHERE
+			codebase_id = $1
+			AND deleted_at IS NULL
+		ORDER BY
+			created_at ASC
+	`, codebaseID); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+	return res, nil
+}
+
+var _ DeliveryRepository = &deliveryDatabase{}
+
+type deliveryDatabase struct {
+	db *sqlx.DB
+}
+
+func NewDeliveryDatabase(db *sqlx.DB) DeliveryRepository {
+	return &deliveryDatabase{
+		db: db,
+	}
+}
+
+func (d *deliveryDatabase) Create(ctx context.Context, delivery *webhooks.Delivery) error {
+	if _, err := d.db.NamedExecContext(ctx, `
+		INSERT INTO webhook_deliveries (
+			id, webhook_id, event_id, event, payload, attempt, status_code, error, duration_ms, created_at, retry_at
+		) VALUES (
+			:id, :webhook_id, :event_id, :event, :payload, :attempt, :status_code, :error, :duration_ms, :created_at, :retry_at
+		)
+	`, delivery); err != nil {
+		return fmt.Errorf("failed to insert: %w", err)
+	}
+	return nil
+}
+
+func (d *deliveryDatabase) ListByWebhookID(ctx context.Context, webhookID webhooks.ID, limit int) ([]*webhooks.Delivery, error) {
+	var res []*webhooks.Delivery
+	if err := d.db.SelectContext(ctx, &res, `
+		SELECT
+			id, webhook_id, event_id, event, payload, attempt, status_code, error, duration_ms, created_at, retry_at
+		FROM
+			webhook_deliveries
+		WHERE
+			webhook_id = $1
+		ORDER BY
+			created_at DESC
+		LIMIT $2
+	`, webhookID, limit); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+	return res, nil
+}
+
+func (d *deliveryDatabase) ClaimRetries(ctx context.Context, now time.Time, limit int) ([]*webhooks.Delivery, error) {
+	var res []*webhooks.Delivery
+	if err := d.db.SelectContext(ctx, &res, `
+		UPDATE
+			webhook_deliveries
+		SET
+			retry_at = NULL
+		WHERE
+			id IN (
+				SELECT id
+				FROM webhook_deliveries
+				WHERE retry_at <= $1
+				ORDER BY retry_at
+				LIMIT $2
+				FOR UPDATE SKIP LOCKED
+			)
+		RETURNING
+			id, webhook_id, event_id, event, payload, attempt, status_code, error, duration_ms, created_at, retry_at
+	`, now, limit); err != nil {
+		return nil, fmt.Errorf("failed to update: %w", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/webhooks"
+)
+
+var _ Repository = &memory{}
+
+type memory struct {
+	mu   sync.RWMutex
+	byID map[webhooks.ID]*webhooks.Webhook
+}
+
+func NewMemory() Repository {
+	return &memory{
+		byID: map[webhooks.ID]*webhooks.Webhook{},
+	}
+}
+
+func (m *memory) Create(_ context.Context, webhook *webhooks.Webhook) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *webhook
+	m.byID[webhook.ID] = &cp
+	return nil
+}
+
+func (m *memory) Update(_ context.Context, webhook *webhooks.Webhook) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, found := m.byID[webhook.ID]; !found {
+		return sql.ErrNoRows
+	}
+	cp := *webhook
+	m.byID[webhook.ID] = &cp
+	return nil
+}
+
+func (m *memory) Get(_ context.Context, id webhooks.ID) (*webhooks.Webhook, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	webhook, found := m.byID[id]
+	if !found {
+		return nil, sql.ErrNoRows
+	}
+	cp := *webhook
+	return &cp, nil
+}
+
+func (m *memory) ListByCodebaseID(_ context.Context, codebaseID codebases.ID) ([]*webhooks.Webhook, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var res []*webhooks.Webhook
+	for _, webhook := range m.byID {
+		if webhook.CodebaseID != codebaseID || webhook.DeletedAt != nil {
+			continue
+		}
+		cp := *webhook
+		res = append(res, &cp)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].CreatedAt.Before(res[j].CreatedAt)
+	})
+	return res, nil
+}
+
+var _ DeliveryRepository = &deliveryMemory{}
+
+type deliveryMemory struct {
+	mu         sync.RWMutex
+	deliveries []*webhooks.Delivery
+}
+
+func NewDeliveryMemory() DeliveryRepository {
+	return &deliveryMemory{}
+}
+
+func (m *deliveryMemory) Create(_ context.Context, delivery *webhooks.Delivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *delivery
+	m.deliveries = append(m.deliveries, &cp)
+	return nil
+}
+
+func (m *deliveryMemory) ListByWebhookID(_ context.Context, webhookID webhooks.ID, limit int) ([]*webhooks.Delivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var res []*webhooks.Delivery
+	for i := len(m.deliveries) - 1; i >= 0 && len(res) < limit; i-- {
+		if m.deliveries[i].WebhookID != webhookID {
+			continue
+		}
+		cp := *m.deliveries[i]
+		res = append(res, &cp)
+	}
+	return res, nil
+}
+
+func (m *deliveryMemory) ClaimRetries(_ context.Context, now time.Time, limit int) ([]*webhooks.Delivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var res []*webhooks.Delivery
+	for _, delivery := range m.deliveries {
+		if len(res) >= limit {
+			break
+		}
+		if delivery.RetryAt == nil || delivery.RetryAt.After(now) {
+			continue
+		}
+		delivery.RetryAt = nil
+		cp := *delivery
+		res = append(res, &cp)
+	}
+	return res, nil
+}
@@ -0,0 +1,26 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/webhooks"
+)
+
+type Repository interface {
+	Create(context.Context, *webhooks.Webhook) error
+	Update(context.Context, *webhooks.Webhook) error
+	Get(context.Context, webhooks.ID) (*webhooks.Webhook, error)
+	// ListByCodebaseID returns all webhooks in the codebase that are not deleted.
+	ListByCodebaseID(context.Context, codebases.ID) ([]*webhooks.Webhook, error)
+}
+
+type DeliveryRepository interface {
+	Create(context.Context, *webhooks.Delivery) error
+	// ListByWebhookID returns the latest deliveries to the webhook, newest first.
+	ListByWebhookID(ctx context.Context, webhookID webhooks.ID, limit int) ([]*webhooks.Delivery, error)
+	// ClaimRetries returns up to limit deliveries with a retry that is due at now, and clears their RetryAt, so that
+	// each retry is claimed only once.
+	ClaimRetries(ctx context.Context, now time.Time, limit int) ([]*webhooks.Delivery, error)
+}
@@ -0,0 +1,267 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"getsturdy.com/api/pkg/auth"
+	service_auth "getsturdy.com/api/pkg/auth/service"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/codebases/acl"
+	service_codebases "getsturdy.com/api/pkg/codebases/service"
+	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
+	"getsturdy.com/api/pkg/graphql/resolvers"
+	"getsturdy.com/api/pkg/webhooks"
+	service_webhooks "getsturdy.com/api/pkg/webhooks/service"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+const defaultDeliveriesLimit = 20
+
+type rootResolver struct {
+	authService     *service_auth.Service
+	codebaseService *service_codebases.Service
+	webhooksService *service_webhooks.Service
+}
+
+func New(
+	authService *service_auth.Service,
+	codebaseService *service_codebases.Service,
+	webhooksService *service_webhooks.Service,
+) resolvers.WebhooksRootResolver {
+	return &rootResolver{
+		authService:     authService,
+		codebaseService: codebaseService,
+		webhooksService: webhooksService,
+	}
+}
+
+func (r *rootResolver) InternalWebhooksByCodebaseID(ctx context.Context, codebaseID codebases.ID) ([]resolvers.WebhookResolver, error) {
+	if err := r.canManage(ctx, codebaseID); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	hooks, err := r.webhooksService.ListByCodebaseID(ctx, codebaseID)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	res := make([]resolvers.WebhookResolver, 0, len(hooks))
+	for _, webhook := range hooks {
+		res = append(res, &resolver{root: r, webhook: webhook})
+	}
+	return res, nil
+}
+
+func (r *rootResolver) CreateWebhook(ctx context.Context, args resolvers.CreateWebhookArgs) (resolvers.WebhookResolver, error) {
+	userID, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	codebaseID := codebases.ID(args.Input.CodebaseID)
+	if err := r.canManage(ctx, codebaseID); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	var events []webhooks.Event
+	if args.Input.Events != nil {
+		events = toEvents(*args.Input.Events)
+	}
+
+	webhook, err := r.webhooksService.Create(ctx, codebaseID, args.Input.URL, events, userID)
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+
+	return &resolver{root: r, webhook: webhook}, nil
+}
+
+func (r *rootResolver) UpdateWebhook(ctx context.Context, args resolvers.UpdateWebhookArgs) (resolvers.WebhookResolver, error) {
+	webhook, err := r.get(ctx, webhooks.ID(args.Input.ID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if args.Input.URL != nil {
+		webhook.URL = *args.Input.URL
+	}
+	if args.Input.Events != nil {
+		webhook.Events = eventStrings(toEvents(*args.Input.Events))
+	}
+
+	if args.Input.RotateSecret != nil && *args.Input.RotateSecret {
+		err = r.webhooksService.RotateSecret(ctx, webhook)
+	} else {
+		err = r.webhooksService.Update(ctx, webhook)
+	}
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+
+	return &resolver{root: r, webhook: webhook}, nil
+}
+
+func (r *rootResolver) DeleteWebhook(ctx context.Context, args resolvers.DeleteWebhookArgs) (resolvers.WebhookResolver, error) {
+	webhook, err := r.get(ctx, webhooks.ID(args.ID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if err := r.webhooksService.Delete(ctx, webhook); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	return &resolver{root: r, webhook: webhook}, nil
+}
+
+// get returns the webhook if it exists, and the user is allowed to manage it.
+func (r *rootResolver) get(ctx context.Context, id webhooks.ID) (*webhooks.Webhook, error) {
+	webhook, err := r.webhooksService.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	if webhook.DeletedAt != nil {
+		return nil, gqlerrors.ErrNotFound
+	}
+	if err := r.canManage(ctx, webhook.CodebaseID); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (r *rootResolver) canManage(ctx context.Context, codebaseID codebases.ID) error {
+	cb, err := r.codebaseService.GetByID(ctx, codebaseID)
+	if err != nil {
+		return fmt.Errorf("failed to get codebase: %w", err)
+	}
+	if err := r.authService.CanWrite(ctx, cb); err != nil {
+		return err
+	}
+	return r.authService.CanPerform(ctx, cb.ID, acl.ActionManageIntegrations, acl.Identity{Type: acl.Integrations, ID: "webhooks"})
+}
+
+func toGraphQLError(err error) error {
+	switch {
+	case errors.Is(err, service_webhooks.ErrInvalidURL):
+		return gqlerrors.Error(gqlerrors.ErrBadRequest, "url", "must be a valid http or https url")
+	case errors.Is(err, service_webhooks.ErrForbiddenAddress):
+		return gqlerrors.Error(gqlerrors.ErrBadRequest, "url", "must not point to a loopback, link-local or private address")
+	case errors.Is(err, service_webhooks.ErrInvalidEvent):
+		return gqlerrors.Error(gqlerrors.ErrBadRequest, "events", "invalid event")
+	default:
+		return gqlerrors.Error(err)
+	}
+}
+
+func toEvents(ss []string) []webhooks.Event {
+	res := make([]webhooks.Event, 0, len(ss))
+	for _, s := range ss {
+		res = append(res, webhooks.Event(s))
+	}
+	return res
+}
+
+func eventStrings(events []webhooks.Event) []string {
+	res := make([]string, 0, len(events))
+	for _, event := range events {
+		res = append(res, string(event))
+	}
+	return res
+}
+
+type resolver struct {
+	root    *rootResolver
+	webhook *webhooks.Webhook
+}
+
+func (r *resolver) ID() graphql.ID {
+	return graphql.ID(r.webhook.ID)
+}
+
+func (r *resolver) URL() string {
+	return r.webhook.URL
+}
+
+func (r *resolver) Secret() string {
+	return r.webhook.Secret
+}
+
+func (r *resolver) Events() []string {
+	if r.webhook.Events == nil {
+		return []string{}
+	}
+	return r.webhook.Events
+}
+
+func (r *resolver) CreatedAt() int32 {
+	return int32(r.webhook.CreatedAt.Unix())
+}
+
+func (r *resolver) UpdatedAt() int32 {
+	return int32(r.webhook.UpdatedAt.Unix())
+}
+
+func (r *resolver) Deliveries(ctx context.Context, args resolvers.WebhookDeliveriesArgs) ([]resolvers.WebhookDeliveryResolver, error) {
+	limit := defaultDeliveriesLimit
+	if args.Last != nil && *args.Last > 0 {
+		limit = int(*args.Last)
+	}
+
+	deliveries, err := r.root.webhooksService.ListDeliveries(ctx, r.webhook.ID, limit)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	res := make([]resolvers.WebhookDeliveryResolver, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		res = append(res, &deliveryResolver{delivery: delivery})
+	}
+	return res, nil
+}
+
+type deliveryResolver struct {
+	delivery *webhooks.Delivery
+}
+
+func (r *deliveryResolver) ID() graphql.ID {
+	return graphql.ID(r.delivery.ID)
+}
+
+func (r *deliveryResolver) EventID() graphql.ID {
+	return graphql.ID(r.delivery.EventID)
+}
+
+func (r *deliveryResolver) Event() string {
+	return string(r.delivery.Event)
+}
+
+func (r *deliveryResolver) Payload() string {
+	return r.delivery.Payload
+}
+
+func (r *deliveryResolver) Attempt() int32 {
+	return int32(r.delivery.Attempt)
+}
+
+func (r *deliveryResolver) StatusCode() *int32 {
+	if r.delivery.StatusCode == nil {
+		return nil
+	}
+	code := int32(*r.delivery.StatusCode)
+	return &code
+}
+
+func (r *deliveryResolver) Error() *string {
+	return r.delivery.Error
+}
+
+func (r *deliveryResolver) DurationMs() int32 {
+	return int32(r.delivery.Duration)
+}
+
+func (r *deliveryResolver) CreatedAt() int32 {
+	return int32(r.delivery.CreatedAt.Unix())
+}
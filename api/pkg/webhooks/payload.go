@@ -0,0 +1,146 @@
+package webhooks
+
+import (
+	"time"
+
+	"getsturdy.com/api/pkg/changes"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/comments"
+	"getsturdy.com/api/pkg/mergequeue"
+	"getsturdy.com/api/pkg/review"
+	"getsturdy.com/api/pkg/statuses"
+	"getsturdy.com/api/pkg/users"
+)
+
+// Payload is the body of the requests sent to webhooks.
+type Payload struct {
+	// ID identifies the event, and is the same for all attempts to deliver it.
+	ID         string       `json:"id"`
+	Event      Event        `json:"event"`
+	CodebaseID codebases.ID `json:"codebase_id"`
+	CreatedAt  time.Time    `json:"created_at"`
+	// Data is one of the *Data types below, depending on Event.
+	Data any `json:"data"`
+}
+
+type ChangeData struct {
+	ID          changes.ID `json:"id"`
+	Title       *string    `json:"title"`
+	Description string     `json:"description"`
+	UserID      *users.ID  `json:"user_id"`
+	WorkspaceID *string    `json:"workspace_id"`
+	CommitSHA   *string    `json:"commit_sha"`
+	CreatedAt   *time.Time `json:"created_at"`
+}
+
+func NewChangeData(change *changes.Change) *ChangeData {
+	return &ChangeData{
+		ID:          change.ID,
+		Title:       change.Title,
+		Description: change.UpdatedDescription,
+		UserID:      change.UserID,
+		WorkspaceID: change.WorkspaceID,
+		CommitSHA:   change.CommitID,
+		CreatedAt:   change.CreatedAt,
+	}
+}
+
+type CommentData struct {
+	ID              comments.ID  `json:"id"`
+	UserID          users.ID     `json:"user_id"`
+	WorkspaceID     *string      `json:"workspace_id"`
+	ChangeID        *changes.ID  `json:"change_id"`
+	ParentCommentID *comments.ID `json:"parent_comment_id"`
+	Message         string       `json:"message"`
+	Path            string       `json:"path"`
+	LineStart       int          `json:"line_start"`
+	LineEnd         int          `json:"line_end"`
+	CreatedAt       time.Time    `json:"created_at"`
+	ResolvedAt      *time.Time   `json:"resolved_at"`
+	DeletedAt       *time.Time   `json:"deleted_at"`
+}
+
+func NewCommentData(comment *comments.Comment) *CommentData {
+	return &CommentData{
+		ID:              comment.ID,
+		UserID:          comment.UserID,
+		WorkspaceID:     comment.WorkspaceID,
+		ChangeID:        comment.ChangeID,
+		ParentCommentID: comment.ParentComment,
+		Message:         comment.Message,
+		Path:            comment.Path,
+		LineStart:       comment.LineStart,
+		LineEnd:         comment.LineEnd,
+		CreatedAt:       comment.CreatedAt,
+		ResolvedAt:      comment.ResolvedAt,
+		DeletedAt:       comment.DeletedAt,
+	}
+}
+
+type ReviewData struct {
+	ID          string             `json:"id"`
+	UserID      users.ID           `json:"user_id"`
+	WorkspaceID string             `json:"workspace_id"`
+	Grade       review.ReviewGrade `json:"grade"`
+	RequestedBy *users.ID          `json:"requested_by"`
+	CreatedAt   time.Time          `json:"created_at"`
+	DismissedAt *time.Time         `json:"dismissed_at"`
+}
+
+func NewReviewData(r *review.Review) *ReviewData {
+	return &ReviewData{
+		ID:          r.ID,
+		UserID:      r.UserID,
+		WorkspaceID: r.WorkspaceID,
+		Grade:       r.Grade,
+		RequestedBy: r.RequestedBy,
+		CreatedAt:   r.CreatedAt,
+		DismissedAt: r.DismissedAt,
+	}
+}
+
+type StatusData struct {
+	ID          string        `json:"id"`
+	CommitSHA   string        `json:"commit_sha"`
+	Type        statuses.Type `json:"type"`
+	Title       string        `json:"title"`
+	Description *string       `json:"description"`
+	DetailsURL  *string       `json:"details_url"`
+	Timestamp   time.Time     `json:"timestamp"`
+}
+
+func NewStatusData(status *statuses.Status) *StatusData {
+	return &StatusData{
+		ID:          status.ID,
+		CommitSHA:   status.CommitSHA,
+		Type:        status.Type,
+		Title:       status.Title,
+		Description: status.Description,
+		DetailsURL:  status.DetailsURL,
+		Timestamp:   status.Timestamp,
+	}
+}
+
+type MergeQueueEntryData struct {
+	ID            mergequeue.ID     `json:"id"`
+	WorkspaceID   string            `json:"workspace_id"`
+	UserID        users.ID          `json:"user_id"`
+	Status        mergequeue.Status `json:"status"`
+	ChangeID      *changes.ID       `json:"change_id"`
+	FailureReason *string           `json:"failure_reason"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+func NewMergeQueueEntryData(entry *mergequeue.Entry) *MergeQueueEntryData {
+	return &MergeQueueEntryData{
+		ID:            entry.ID,
+		WorkspaceID:   entry.WorkspaceID,
+		UserID:        entry.UserID,
+		Status:        entry.Status,
+		ChangeID:      entry.ChangeID,
+		FailureReason: entry.FailureReason,
+		CreatedAt:     entry.CreatedAt,
+		UpdatedAt:     entry.UpdatedAt,
+	}
+}
@@ -0,0 +1,17 @@
+package service
+
+import (
+	configuration "getsturdy.com/api/pkg/configuration/module"
+	"getsturdy.com/api/pkg/di"
+	"getsturdy.com/api/pkg/logger"
+	queue "getsturdy.com/api/pkg/queue/module"
+	db_webhooks "getsturdy.com/api/pkg/webhooks/db"
+)
+
+func Module(c *di.Container) {
+	c.Import(logger.Module)
+	c.Import(configuration.Module)
+	c.Import(db_webhooks.Module)
+	c.Import(queue.Module)
+	c.Register(New)
+}
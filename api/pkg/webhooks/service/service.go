@@ -0,0 +1,427 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"getsturdy.com/api/pkg/changes"
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/comments"
+	eventsv2 "getsturdy.com/api/pkg/events/v2"
+	"getsturdy.com/api/pkg/mergequeue"
+	"getsturdy.com/api/pkg/queue"
+	"getsturdy.com/api/pkg/queue/names"
+	"getsturdy.com/api/pkg/review"
+	"getsturdy.com/api/pkg/statuses"
+	"getsturdy.com/api/pkg/users"
+	"getsturdy.com/api/pkg/webhooks"
+	"getsturdy.com/api/pkg/webhooks/configuration"
+	db_webhooks "getsturdy.com/api/pkg/webhooks/db"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidURL   = errors.New("invalid url")
+	ErrInvalidEvent = errors.New("invalid event")
+	// ErrForbiddenAddress is returned if the url of a webhook points to an address that is not on the public internet.
+	ErrForbiddenAddress = errors.New("forbidden address")
+)
+
+const (
+	// maxAttempts is the number of times the delivery of an event is attempted, before giving up.
+	maxAttempts = 6
+	// requestTimeout is how long a webhook has to respond to a delivery.
+	requestTimeout = 10 * time.Second
+	// retryBatchSize is the max number of retries that are scheduled by each call to Retry.
+	retryBatchSize = 100
+)
+
+// backoff returns how long to wait before the next attempt, after the given attempt has failed.
+func backoff(attempt int) time.Duration {
+	return 30 * time.Second << (attempt - 1)
+}
+
+// Message is the message that is published to the webhooks worker for each attempt to deliver an event.
+type Message struct {
+	WebhookID webhooks.ID    `json:"webhook_id"`
+	EventID   string         `json:"event_id"`
+	Event     webhooks.Event `json:"event"`
+	Payload   string         `json:"payload"`
+	Attempt   int            `json:"attempt"`
+}
+
+type Service struct {
+	logger *zap.Logger
+
+	repo         db_webhooks.Repository
+	deliveryRepo db_webhooks.DeliveryRepository
+
+	queue      queue.Queue
+	httpClient *http.Client
+
+	allowPrivateAddresses bool
+}
+
+func New(
+	logger *zap.Logger,
+	cfg *configuration.Configuration,
+	repo db_webhooks.Repository,
+	deliveryRepo db_webhooks.DeliveryRepository,
+	queue queue.Queue,
+) *Service {
+	return &Service{
+		logger: logger.Named("webhooksService"),
+
+		repo:         repo,
+		deliveryRepo: deliveryRepo,
+
+		queue:      queue,
+		httpClient: newHTTPClient(cfg.AllowPrivateAddresses),
+
+		allowPrivateAddresses: cfg.AllowPrivateAddresses,
+	}
+}
+
+// newHTTPClient returns the client that webhooks are delivered with. Unless private addresses are allowed, the
+// address of every connection is checked after the host has been resolved, so that a webhook can not reach internal
+// services by changing what its host resolves to after it was created.
+func newHTTPClient(allowPrivateAddresses bool) *http.Client {
+	dialer := &net.Dialer{Timeout: requestTimeout}
+	if !allowPrivateAddresses {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || forbiddenIP(ip) {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// a proxy would make the connection on our behalf, without the address check
+	transport.Proxy = nil
+
+	return &http.Client{
+		Timeout:   requestTimeout,
+		Transport: transport,
+	}
+}
+
+func (s *Service) Get(ctx context.Context, id webhooks.ID) (*webhooks.Webhook, error) {
+	return s.repo.Get(ctx, id)
+}
+
+func (s *Service) ListByCodebaseID(ctx context.Context, codebaseID codebases.ID) ([]*webhooks.Webhook, error) {
+	return s.repo.ListByCodebaseID(ctx, codebaseID)
+}
+
+// ListDeliveries returns the latest delivery attempts to the webhook, newest first.
+func (s *Service) ListDeliveries(ctx context.Context, webhookID webhooks.ID, limit int) ([]*webhooks.Delivery, error) {
+	return s.deliveryRepo.ListByWebhookID(ctx, webhookID, limit)
+}
+
+// Create creates a new webhook in the codebase, with a randomly generated secret.
+func (s *Service) Create(ctx context.Context, codebaseID codebases.ID, rawURL string, events []webhooks.Event, createdBy users.ID) (*webhooks.Webhook, error) {
+	secret, err := newSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate secret: %w", err)
+	}
+
+	now := time.Now()
+	webhook := &webhooks.Webhook{
+		ID:         webhooks.ID(uuid.NewString()),
+		CodebaseID: codebaseID,
+		URL:        rawURL,
+		Secret:     secret,
+		Events:     eventStrings(events),
+		CreatedBy:  createdBy,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.validate(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// Update updates the url, events and secret of the webhook.
+func (s *Service) Update(ctx context.Context, webhook *webhooks.Webhook) error {
+	if err := s.validate(ctx, webhook); err != nil {
+		return err
+	}
+	webhook.UpdatedAt = time.Now()
+	if err := s.repo.Update(ctx, webhook); err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return nil
+}
+
+// RotateSecret replaces the secret of the webhook with a new randomly generated one.
+func (s *Service) RotateSecret(ctx context.Context, webhook *webhooks.Webhook) error {
+	secret, err := newSecret()
+	if err != nil {
+		return fmt.Errorf("failed to generate secret: %w", err)
+	}
+	webhook.Secret = secret
+	return s.Update(ctx, webhook)
+}
+
+func (s *Service) Delete(ctx context.Context, webhook *webhooks.Webhook) error {
+	now := time.Now()
+	webhook.DeletedAt = &now
+	webhook.UpdatedAt = now
+	if err := s.repo.Update(ctx, webhook); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return nil
+}
+
+// Dispatch schedules the event to be delivered to all webhooks in the codebase that are subscribed to it. Events
+// that can not be sent to webhooks are ignored.
+func (s *Service) Dispatch(ctx context.Context, codebaseID codebases.ID, t eventsv2.Type, object any) error {
+	event, data, ok := eventData(t, object)
+	if !ok {
+		return nil
+	}
+
+	hooks, err := s.repo.ListByCodebaseID(ctx, codebaseID)
+	if err != nil {
+		return fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	var payload []byte
+	eventID := uuid.NewString()
+	for _, webhook := range hooks {
+		if !webhook.Subscribes(event) {
+			continue
+		}
+
+		if payload == nil {
+			payload, err = json.Marshal(&webhooks.Payload{
+				ID:         eventID,
+				Event:      event,
+				CodebaseID: codebaseID,
+				CreatedAt:  time.Now(),
+				Data:       data,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to marshal payload: %w", err)
+			}
+		}
+
+		if err := s.queue.Publish(ctx, names.Webhooks, &Message{
+			WebhookID: webhook.ID,
+			EventID:   eventID,
+			Event:     event,
+			Payload:   string(payload),
+			Attempt:   1,
+		}); err != nil {
+			return fmt.Errorf("failed to publish to queue: %w", err)
+		}
+	}
+	return nil
+}
+
+// Deliver makes one attempt to deliver the event in msg to its webhook, and logs the result. If the attempt fails, a
+// new attempt is scheduled with exponential backoff, until maxAttempts is reached. Scheduled attempts are published
+// by Retry once they are due.
+func (s *Service) Deliver(ctx context.Context, msg *Message) error {
+	webhook, err := s.repo.Get(ctx, msg.WebhookID)
+	if err != nil {
+		return fmt.Errorf("failed to get webhook: %w", err)
+	}
+	if webhook.DeletedAt != nil {
+		return nil
+	}
+
+	delivery := s.send(ctx, webhook, msg)
+	if !delivery.Succeeded() && msg.Attempt < maxAttempts {
+		retryAt := time.Now().Add(backoff(msg.Attempt))
+		delivery.RetryAt = &retryAt
+	}
+	if err := s.deliveryRepo.Create(ctx, delivery); err != nil {
+		return fmt.Errorf("failed to create delivery: %w", err)
+	}
+	return nil
+}
+
+// Retry publishes the next attempt of all failed deliveries that are due for a retry.
+func (s *Service) Retry(ctx context.Context) error {
+	for {
+		deliveries, err := s.deliveryRepo.ClaimRetries(ctx, time.Now(), retryBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to claim retries: %w", err)
+		}
+
+		for _, delivery := range deliveries {
+			if err := s.queue.Publish(ctx, names.Webhooks, &Message{
+				WebhookID: delivery.WebhookID,
+				EventID:   delivery.EventID,
+				Event:     delivery.Event,
+				Payload:   delivery.Payload,
+				Attempt:   delivery.Attempt + 1,
+			}); err != nil {
+				return fmt.Errorf("failed to publish retry to queue: %w", err)
+			}
+		}
+
+		if len(deliveries) < retryBatchSize {
+			return nil
+		}
+	}
+}
+
+func (s *Service) send(ctx context.Context, webhook *webhooks.Webhook, msg *Message) *webhooks.Delivery {
+	delivery := &webhooks.Delivery{
+		ID:        webhooks.DeliveryID(uuid.NewString()),
+		WebhookID: webhook.ID,
+		EventID:   msg.EventID,
+		Event:     msg.Event,
+		Payload:   msg.Payload,
+		Attempt:   msg.Attempt,
+		CreatedAt: time.Now(),
+	}
+
+	setError := func(err error) *webhooks.Delivery {
+		errString := err.Error()
+		delivery.Error = &errString
+		delivery.Duration = time.Since(delivery.CreatedAt).Milliseconds()
+		return delivery
+	}
+
+	body := []byte(msg.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return setError(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Sturdy-Webhooks")
+	req.Header.Set("X-Sturdy-Event", string(msg.Event))
+	req.Header.Set("X-Sturdy-Delivery", msg.EventID)
+	req.Header.Set("X-Sturdy-Signature-256", webhook.Sign(body))
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return setError(err)
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64*1024))
+
+	delivery.StatusCode = &res.StatusCode
+	delivery.Duration = time.Since(delivery.CreatedAt).Milliseconds()
+	return delivery
+}
+
+func eventData(t eventsv2.Type, object any) (webhooks.Event, any, bool) {
+	switch t {
+	case eventsv2.ChangeLanded:
+		if change, ok := object.(*changes.Change); ok && change != nil {
+			return webhooks.EventChangeLanded, webhooks.NewChangeData(change), true
+		}
+	case eventsv2.CommentUpdated:
+		if comment, ok := object.(*comments.Comment); ok && comment != nil {
+			return webhooks.EventCommentUpdated, webhooks.NewCommentData(comment), true
+		}
+	case eventsv2.ReviewUpdated:
+		if r, ok := object.(*review.Review); ok && r != nil {
+			return webhooks.EventReviewUpdated, webhooks.NewReviewData(r), true
+		}
+	case eventsv2.StatusUpdated:
+		if status, ok := object.(*statuses.Status); ok && status != nil {
+			return webhooks.EventStatusUpdated, webhooks.NewStatusData(status), true
+		}
+	case eventsv2.MergeQueueEntryUpdated:
+		if entry, ok := object.(*mergequeue.Entry); ok && entry != nil {
+			return webhooks.EventMergeQueueEntryUpdated, webhooks.NewMergeQueueEntryData(entry), true
+		}
+	}
+	return "", nil, false
+}
+
+func (s *Service) validate(ctx context.Context, webhook *webhooks.Webhook) error {
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("%w: %q", ErrInvalidURL, webhook.URL)
+	}
+	if !s.allowPrivateAddresses {
+		if err := checkHost(ctx, u.Hostname()); err != nil {
+			return err
+		}
+	}
+	for _, event := range webhook.Events {
+		if !webhooks.ValidEvent[webhooks.Event(event)] {
+			return fmt.Errorf("%w: %q", ErrInvalidEvent, event)
+		}
+	}
+	return nil
+}
+
+// checkHost returns ErrForbiddenAddress if host is, or resolves to, an address that is not on the public internet.
+// Hosts that can not be resolved are allowed, their addresses are checked again each time an event is delivered.
+func checkHost(ctx context.Context, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if forbiddenIP(ip) {
+			return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil
+	}
+	for _, addr := range addrs {
+		if forbiddenIP(addr.IP) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrForbiddenAddress, host, addr.IP)
+		}
+	}
+	return nil
+}
+
+// forbiddenIP returns true if ip is a loopback, link-local, private, unspecified or multicast address.
+func forbiddenIP(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsPrivate() ||
+		ip.IsUnspecified()
+}
+
+func eventStrings(events []webhooks.Event) []string {
+	res := make([]string, 0, len(events))
+	for _, event := range events {
+		res = append(res, string(event))
+	}
+	return res
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,241 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+	eventsv2 "getsturdy.com/api/pkg/events/v2"
+	"getsturdy.com/api/pkg/queue"
+	"getsturdy.com/api/pkg/queue/names"
+	"getsturdy.com/api/pkg/review"
+	"getsturdy.com/api/pkg/users"
+	"getsturdy.com/api/pkg/webhooks"
+	"getsturdy.com/api/pkg/webhooks/configuration"
+	db_webhooks "getsturdy.com/api/pkg/webhooks/db"
+	service_webhooks "getsturdy.com/api/pkg/webhooks/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// deliveryRepo moves the clock forward by offset when claiming retries.
+type deliveryRepo struct {
+	db_webhooks.DeliveryRepository
+
+	offset time.Duration
+}
+
+func (r *deliveryRepo) ClaimRetries(ctx context.Context, now time.Time, limit int) ([]*webhooks.Delivery, error) {
+	return r.DeliveryRepository.ClaimRetries(ctx, now.Add(r.offset), limit)
+}
+
+type request struct {
+	headers http.Header
+	body    []byte
+}
+
+func TestDispatchAndDeliver(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, request{headers: r.Header, body: body})
+		first := len(requests) == 1
+		mu.Unlock()
+		if first {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	logger := zap.NewNop()
+	q := queue.NewInMemory(logger)
+	messages := make(chan queue.Message, 10)
+	go func() {
+		_ = q.Subscribe(ctx, names.Webhooks, messages)
+	}()
+
+	deliveryRepo := &deliveryRepo{DeliveryRepository: db_webhooks.NewDeliveryMemory()}
+	service := service_webhooks.New(logger, &configuration.Configuration{AllowPrivateAddresses: true}, db_webhooks.NewMemory(), deliveryRepo, q)
+
+	codebaseID := codebases.ID(uuid.NewString())
+	reviews, err := service.Create(ctx, codebaseID, server.URL, []webhooks.Event{webhooks.EventReviewUpdated}, users.ID(uuid.NewString()))
+	assert.NoError(t, err)
+	_, err = service.Create(ctx, codebaseID, server.URL, []webhooks.Event{webhooks.EventChangeLanded}, users.ID(uuid.NewString()))
+	assert.NoError(t, err)
+
+	rev := &review.Review{ID: uuid.NewString(), CodebaseID: codebaseID, WorkspaceID: uuid.NewString(), Grade: review.ReviewGradeApprove}
+	assert.NoError(t, service.Dispatch(ctx, codebaseID, eventsv2.ReviewUpdated, rev))
+	// not sent to webhooks
+	assert.NoError(t, service.Dispatch(ctx, codebaseID, eventsv2.ViewUpdated, nil))
+
+	next := func() *service_webhooks.Message {
+		select {
+		case msg := <-messages:
+			m := &service_webhooks.Message{}
+			assert.NoError(t, msg.As(m))
+			assert.NoError(t, msg.Ack())
+			return m
+		case <-time.After(time.Second):
+			t.Fatal("no message published")
+			return nil
+		}
+	}
+
+	// only the webhook that is subscribed to reviews receives the event
+	msg := next()
+	assert.Equal(t, reviews.ID, msg.WebhookID)
+	assert.Equal(t, 1, msg.Attempt)
+
+	noMessage := func() {
+		select {
+		case <-messages:
+			t.Fatal("unexpected message")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	// first attempt fails, and a retry is scheduled
+	assert.NoError(t, service.Deliver(ctx, msg))
+	noMessage()
+
+	// the retry is not published before it is due
+	assert.NoError(t, service.Retry(ctx))
+	noMessage()
+
+	deliveryRepo.offset = time.Minute
+	assert.NoError(t, service.Retry(ctx))
+	retry := next()
+	assert.Equal(t, 2, retry.Attempt)
+	assert.Equal(t, msg.EventID, retry.EventID)
+	assert.Equal(t, msg.Payload, retry.Payload)
+
+	// the retry is published only once
+	assert.NoError(t, service.Retry(ctx))
+	noMessage()
+
+	// second attempt succeeds
+	assert.NoError(t, service.Deliver(ctx, retry))
+	assert.NoError(t, service.Retry(ctx))
+	noMessage()
+
+	if assert.Len(t, requests, 2) {
+		req := requests[1]
+		assert.Equal(t, "ReviewUpdated", req.headers.Get("X-Sturdy-Event"))
+		assert.Equal(t, msg.EventID, req.headers.Get("X-Sturdy-Delivery"))
+		assert.Equal(t, reviews.Sign(req.body), req.headers.Get("X-Sturdy-Signature-256"))
+
+		var payload struct {
+			Event      string `json:"event"`
+			CodebaseID string `json:"codebase_id"`
+			Data       struct {
+				ID    string `json:"id"`
+				Grade string `json:"grade"`
+			} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(req.body, &payload))
+		assert.Equal(t, "ReviewUpdated", payload.Event)
+		assert.Equal(t, codebaseID.String(), payload.CodebaseID)
+		assert.Equal(t, rev.ID, payload.Data.ID)
+		assert.Equal(t, "Approve", payload.Data.Grade)
+	}
+
+	deliveries, err := service.ListDeliveries(ctx, reviews.ID, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, deliveries, 2) {
+		assert.True(t, deliveries[0].Succeeded())
+		assert.Equal(t, 2, deliveries[0].Attempt)
+		assert.Nil(t, deliveries[0].RetryAt)
+		assert.False(t, deliveries[1].Succeeded())
+		assert.Nil(t, deliveries[1].RetryAt)
+		assert.Equal(t, http.StatusInternalServerError, *deliveries[1].StatusCode)
+	}
+}
+
+func TestCreateValidation(t *testing.T) {
+	ctx := context.Background()
+	service := service_webhooks.New(zap.NewNop(), &configuration.Configuration{}, db_webhooks.NewMemory(), db_webhooks.NewDeliveryMemory(), nil)
+	codebaseID := codebases.ID(uuid.NewString())
+
+	_, err := service.Create(ctx, codebaseID, "ftp://example.com", nil, users.ID(uuid.NewString()))
+	assert.True(t, errors.Is(err, service_webhooks.ErrInvalidURL))
+
+	_, err = service.Create(ctx, codebaseID, "https://example.com", []webhooks.Event{"Unknown"}, users.ID(uuid.NewString()))
+	assert.True(t, errors.Is(err, service_webhooks.ErrInvalidEvent))
+
+	webhook, err := service.Create(ctx, codebaseID, "https://example.com/hook", nil, users.ID(uuid.NewString()))
+	assert.NoError(t, err)
+	assert.Len(t, webhook.Secret, 64)
+
+	list, err := service.ListByCodebaseID(ctx, codebaseID)
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	assert.NoError(t, service.Delete(ctx, webhook))
+	list, err = service.ListByCodebaseID(ctx, codebaseID)
+	assert.NoError(t, err)
+	assert.Len(t, list, 0)
+}
+
+func TestForbiddenAddresses(t *testing.T) {
+	ctx := context.Background()
+	repo := db_webhooks.NewMemory()
+	deliveryRepo := db_webhooks.NewDeliveryMemory()
+	service := service_webhooks.New(zap.NewNop(), &configuration.Configuration{}, repo, deliveryRepo, nil)
+	codebaseID := codebases.ID(uuid.NewString())
+
+	for _, rawURL := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://[::1]/hook",
+		"http://0.0.0.0/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.0.0.1/hook",
+		"https://172.16.0.1/hook",
+		"https://192.168.1.1/hook",
+		"http://[fd00::1]/hook",
+	} {
+		_, err := service.Create(ctx, codebaseID, rawURL, nil, users.ID(uuid.NewString()))
+		assert.ErrorIs(t, err, service_webhooks.ErrForbiddenAddress, rawURL)
+	}
+
+	// the address is checked again when the event is delivered
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	allowed := service_webhooks.New(zap.NewNop(), &configuration.Configuration{AllowPrivateAddresses: true}, repo, deliveryRepo, nil)
+	webhook, err := allowed.Create(ctx, codebaseID, server.URL, nil, users.ID(uuid.NewString()))
+	assert.NoError(t, err)
+
+	assert.NoError(t, service.Deliver(ctx, &service_webhooks.Message{
+		WebhookID: webhook.ID,
+		EventID:   uuid.NewString(),
+		Event:     webhooks.EventReviewUpdated,
+		Payload:   "{}",
+		Attempt:   1,
+	}))
+	assert.Equal(t, 0, requests)
+
+	deliveries, err := service.ListDeliveries(ctx, webhook.ID, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, deliveries, 1) && assert.NotNil(t, deliveries[0].Error) {
+		assert.Contains(t, *deliveries[0].Error, service_webhooks.ErrForbiddenAddress.Error())
+		assert.NotNil(t, deliveries[0].RetryAt)
+	}
+}
@@ -0,0 +1,98 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/users"
+
+	"github.com/lib/pq"
+)
+
+type ID string
+
+func (id ID) String() string {
+	return string(id)
+}
+
+// Event is the type of event that a webhook can subscribe to.
+type Event string
+
+const (
+	EventChangeLanded           Event = "ChangeLanded"
+	EventCommentUpdated         Event = "CommentUpdated"
+	EventReviewUpdated          Event = "ReviewUpdated"
+	EventStatusUpdated          Event = "StatusUpdated"
+	EventMergeQueueEntryUpdated Event = "MergeQueueEntryUpdated"
+)
+
+var ValidEvent = map[Event]bool{
+	EventChangeLanded:           true,
+	EventCommentUpdated:         true,
+	EventReviewUpdated:          true,
+	EventStatusUpdated:          true,
+	EventMergeQueueEntryUpdated: true,
+}
+
+// Webhook is an endpoint outside of Sturdy that receives events that happen in a codebase.
+type Webhook struct {
+	ID         ID           `db:"id"`
+	CodebaseID codebases.ID `db:"codebase_id"`
+	URL        string       `db:"url"`
+	// Secret is used to sign the payloads that are sent to the webhook.
+	Secret string `db:"secret"`
+	// Events are the events that the webhook is subscribed to. If empty, the webhook receives all events.
+	Events    pq.StringArray `db:"events"`
+	CreatedBy users.ID       `db:"created_by"`
+	CreatedAt time.Time      `db:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at"`
+	DeletedAt *time.Time     `db:"deleted_at"`
+}
+
+// Subscribes returns true if the webhook should receive events of type e.
+func (w *Webhook) Subscribes(e Event) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, event := range w.Events {
+		if Event(event) == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Sign returns the signature of payload, as sent in the X-Sturdy-Signature-256 header.
+func (w *Webhook) Sign(payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(w.Secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+type DeliveryID string
+
+// Delivery is the log of one attempt to deliver an event to a webhook.
+type Delivery struct {
+	ID        DeliveryID `db:"id"`
+	WebhookID ID         `db:"webhook_id"`
+	// EventID is the same for all attempts to deliver the same event.
+	EventID string `db:"event_id"`
+	Event   Event  `db:"event"`
+	Payload string `db:"payload"`
+	Attempt int    `db:"attempt"`
+	// StatusCode is the status code of the response, nil if no response was received.
+	StatusCode *int      `db:"status_code"`
+	Error      *string   `db:"error"`
+	Duration   int64     `db:"duration_ms"`
+	CreatedAt  time.Time `db:"created_at"`
+	// RetryAt is when the next attempt to deliver the event is due, nil if no more attempts are pending.
+	RetryAt *time.Time `db:"retry_at"`
+}
+
+// Succeeded returns true if the webhook accepted the event.
+func (d *Delivery) Succeeded() bool {
+	return d.Error == nil && d.StatusCode != nil && *d.StatusCode >= 200 && *d.StatusCode < 300
+}
@@ -0,0 +1,24 @@
+package webhooks_test
+
+import (
+	"testing"
+
+	"getsturdy.com/api/pkg/webhooks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	wh := &webhooks.Webhook{Secret: "It's a Secret to Everybody"}
+	assert.Equal(t, "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17", wh.Sign([]byte("Hello, World!")))
+}
+
+func TestSubscribes(t *testing.T) {
+	all := &webhooks.Webhook{}
+	assert.True(t, all.Subscribes(webhooks.EventChangeLanded))
+	assert.True(t, all.Subscribes(webhooks.EventReviewUpdated))
+
+	some := &webhooks.Webhook{Events: []string{string(webhooks.EventChangeLanded)}}
+	assert.True(t, some.Subscribes(webhooks.EventChangeLanded))
+	assert.False(t, some.Subscribes(webhooks.EventReviewUpdated))
+}
@@ -0,0 +1,116 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"getsturdy.com/api/pkg/codebases"
+	eventsv2 "getsturdy.com/api/pkg/events/v2"
+	"getsturdy.com/api/pkg/queue"
+	"getsturdy.com/api/pkg/queue/names"
+	service_webhooks "getsturdy.com/api/pkg/webhooks/service"
+
+	"go.uber.org/zap"
+)
+
+// retryInterval is how often failed deliveries are checked for retries that are due.
+const retryInterval = 15 * time.Second
+
+// Queue is a background queue that delivers the events that happen in codebases to their webhooks.
+type Queue struct {
+	logger *zap.Logger
+
+	queue queue.Queue
+	name  names.IncompleteQueueName
+
+	eventsSubscriber *eventsv2.Subscriber
+	webhooksService  *service_webhooks.Service
+}
+
+func New(
+	logger *zap.Logger,
+	queue queue.Queue,
+	eventsSubscriber *eventsv2.Subscriber,
+	webhooksService *service_webhooks.Service,
+) *Queue {
+	return &Queue{
+		logger:           logger.Named("webhooksQueue"),
+		queue:            queue,
+		name:             names.Webhooks,
+		eventsSubscriber: eventsSubscriber,
+		webhooksService:  webhooksService,
+	}
+}
+
+// Start starts the worker.
+func (q *Queue) Start(ctx context.Context) error {
+	q.eventsSubscriber.OnAnyInCodebase(ctx, func(ctx context.Context, codebaseID codebases.ID, t eventsv2.Type, payload any) error {
+		if err := q.webhooksService.Dispatch(ctx, codebaseID, t, payload); err != nil {
+			return fmt.Errorf("failed to dispatch %s: %w", t, err)
+		}
+		return nil
+	})
+
+	go q.retry(ctx)
+
+	messages := make(chan queue.Message)
+	go func() {
+		for msg := range messages {
+			q.handle(ctx, msg)
+		}
+	}()
+
+	q.logger.Info("starting queue", zap.Stringer("queue_name", q.name))
+	if err := q.queue.Subscribe(ctx, q.name, messages); err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+	q.logger.Info("queue stoped", zap.Stringer("queue_name", q.name))
+
+	return nil
+}
+
+func (q *Queue) handle(ctx context.Context, msg queue.Message) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			q.logger.Error("panic in webhooks queue", zap.String("panic", fmt.Sprintf("%v", rec)))
+		}
+	}()
+
+	m := &service_webhooks.Message{}
+	if err := msg.As(m); err != nil {
+		q.logger.Error("failed to decode message", zap.Error(err), zap.Any("message", msg))
+		return
+	}
+
+	logger := q.logger.With(
+		zap.Stringer("webhook_id", m.WebhookID),
+		zap.String("event_id", m.EventID),
+		zap.Int("attempt", m.Attempt),
+	)
+
+	if err := q.webhooksService.Deliver(ctx, m); err != nil {
+		logger.Error("failed to deliver webhook", zap.Error(err))
+		return
+	}
+
+	if err := msg.Ack(); err != nil {
+		logger.Error("failed to ack message", zap.Error(err))
+	}
+}
+
+// retry publishes the failed deliveries that are due for a retry, until ctx is cancelled.
+func (q *Queue) retry(ctx context.Context) {
+	ticker := time.NewTicker(retryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := q.webhooksService.Retry(ctx); err != nil {
+				q.logger.Error("failed to retry deliveries", zap.Error(err))
+			}
+		}
+	}
+}
@@ -32,7 +32,19 @@ func (f *memory) Get(id string) (*workspaces.Workspace, error) {
 }
 
 func (f *memory) ListByCodebaseIDs(codebaseIDs []codebases.ID, includeArchived bool) ([]*workspaces.Workspace, error) {
-	panic("not implemented")
+	ww := []*workspaces.Workspace{}
+	for _, ws := range f.workspaces {
+		if !includeArchived && ws.ArchivedAt != nil {
+			continue
+		}
+		for _, codebaseID := range codebaseIDs {
+			if ws.CodebaseID == codebaseID {
+				ww = append(ww, ws)
+				break
+			}
+		}
+	}
+	return ww, nil
 }
 
 func (f *memory) ListByCodebaseIDsAndUserID(codebaseIDs []codebases.ID, userID string) ([]*workspaces.Workspace, error) {
@@ -96,6 +108,9 @@ func (f *memory) UpdateFields(_ context.Context, workspaceID string, fields ...U
 		if opts.userIDSet {
 			ws.UserID = opts.userID
 		}
+		if opts.parentWorkspaceIDSet {
+			ws.ParentWorkspaceID = opts.parentWorkspaceID
+		}
 		return nil
 	}
 	return sql.ErrNoRows
@@ -136,3 +151,24 @@ func (f *memory) ListByIDs(_ context.Context, ids ...string) ([]*workspaces.Work
 	}
 	return ww, nil
 }
+
+func (f *memory) ListByParentWorkspaceID(_ context.Context, parentWorkspaceID string) ([]*workspaces.Workspace, error) {
+	ww := []*workspaces.Workspace{}
+	for _, workspace := range f.workspaces {
+		if workspace.ParentWorkspaceID != nil && *workspace.ParentWorkspaceID == parentWorkspaceID && workspace.ArchivedAt == nil {
+			ww = append(ww, workspace)
+		}
+	}
+	return ww, nil
+}
+
+func (f *memory) DeleteByCodebaseID(_ context.Context, codebaseID codebases.ID) error {
+	kept := f.workspaces[:0]
+	for _, ws := range f.workspaces {
+		if ws.CodebaseID != codebaseID {
+			kept = append(kept, ws)
+		}
+	}
+	f.workspaces = kept
+	return nil
+}
@@ -21,6 +21,7 @@ type WorkspaceWriter interface {
 	UnsetUpToDateWithTrunkForAllInCodebase(codebases.ID) error
 
 	UpdateFields(ctx context.Context, workspaceID string, fields ...UpdateOption) error
+	DeleteByCodebaseID(context.Context, codebases.ID) error
 }
 
 type WorkspaceReader interface {
@@ -31,6 +32,8 @@ type WorkspaceReader interface {
 	ListByUserID(context.Context, users.ID) ([]*workspaces.Workspace, error)
 	GetByViewID(viewID string, includeArchived bool) (*workspaces.Workspace, error)
 	GetBySnapshotID(snapshots.ID) (*workspaces.Workspace, error)
+	// ListByParentWorkspaceID returns the unarchived workspaces that are stacked on top of the parent workspace.
+	ListByParentWorkspaceID(ctx context.Context, parentWorkspaceID string) ([]*workspaces.Workspace, error)
 }
 
 type UpdateOptions struct {
@@ -75,6 +78,9 @@ type UpdateOptions struct {
 
 	userID    users.ID
 	userIDSet bool
+
+	parentWorkspaceID    *string
+	parentWorkspaceIDSet bool
 }
 
 type UpdateOption func(*UpdateOptions)
@@ -186,3 +192,10 @@ func SetUserID(userID users.ID) UpdateOption {
 		opts.userIDSet = true
 	}
 }
+
+func SetParentWorkspaceID(parentWorkspaceID *string) UpdateOption {
+	return func(opts *UpdateOptions) {
+		opts.parentWorkspaceID = parentWorkspaceID
+		opts.parentWorkspaceIDSet = true
+	}
+}
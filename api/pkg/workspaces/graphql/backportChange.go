@@ -0,0 +1,114 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	"getsturdy.com/api/pkg/auth"
+	"getsturdy.com/api/pkg/changes"
+	"getsturdy.com/api/pkg/codebases"
+	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
+	"getsturdy.com/api/pkg/graphql/resolvers"
+	service_sync "getsturdy.com/api/pkg/sync/service"
+	"getsturdy.com/api/pkg/trunks"
+	"getsturdy.com/api/pkg/workspaces"
+	"getsturdy.com/api/pkg/workspaces/service"
+	"getsturdy.com/api/vcs/executor"
+
+	"go.uber.org/zap"
+)
+
+func (r *WorkspaceRootResolver) BackportChange(ctx context.Context, args resolvers.BackportChangeArgs) (resolvers.WorkspaceResolver, error) {
+	if args.Input.TrunkID != nil && args.Input.WorkspaceID != nil {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest,
+			"trunkID", "can't be set together with workspaceID",
+			"workspaceID", "can't be set together with trunkID",
+		)
+	}
+
+	ch, err := r.changeService.GetChangeByID(ctx, changes.ID(args.Input.ChangeID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	if err := r.authService.CanRead(ctx, ch); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	userID, err := auth.UserID(ctx)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	var ws *workspaces.Workspace
+	var created bool
+	if args.Input.WorkspaceID != nil {
+		ws, err = r.workspaceService.GetByID(ctx, string(*args.Input.WorkspaceID))
+		if err != nil {
+			return nil, gqlerrors.Error(err)
+		}
+		if err := r.authService.CanWrite(ctx, ws); err != nil {
+			return nil, gqlerrors.Error(err)
+		}
+		if ws.CodebaseID != ch.CodebaseID {
+			return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "workspaceID", "workspace is not in the same codebase as the change")
+		}
+	} else {
+		if err := r.authService.CanWrite(ctx, &codebases.Codebase{ID: ch.CodebaseID}); err != nil {
+			return nil, gqlerrors.Error(err)
+		}
+
+		req := service.CreateWorkspaceRequest{
+			CodebaseID:       ch.CodebaseID,
+			UserID:           userID,
+			Name:             "Backport " + changeTitle(ch),
+			DraftDescription: ch.UpdatedDescription,
+		}
+		if args.Input.TrunkID != nil {
+			trunkID := trunks.ID(*args.Input.TrunkID)
+			req.TrunkID = &trunkID
+		}
+
+		ws, err = r.workspaceService.Create(ctx, req)
+		if err != nil {
+			return nil, gqlerrors.Error(err)
+		}
+		created = true
+	}
+
+	if _, err := r.syncService.Backport(ctx, userID, ch, ws); err != nil {
+		// don't leave an empty workspace behind if the backport could not be made
+		if created {
+			if err := r.workspaceService.Archive(ctx, ws); err != nil {
+				r.logger.Error("failed to archive backport workspace", zap.Error(err))
+				// do not fail
+			}
+		}
+
+		switch {
+		case errors.Is(err, service_sync.ErrChangeNotLanded):
+			return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "changeID", "change has not been landed")
+		case errors.Is(err, service_sync.ErrUnsavedChanges):
+			return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "workspaceID", "workspace has unsaved changes")
+		case errors.Is(err, executor.ErrIsRebasing):
+			return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "workspaceID", "workspace is syncing")
+		default:
+			return nil, gqlerrors.Error(err)
+		}
+	}
+
+	// the workspace has been updated by the backport
+	ws, err = r.workspaceService.GetByID(ctx, ws.ID)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	return &WorkspaceResolver{w: ws, root: r}, nil
+}
+
+func changeTitle(ch *changes.Change) string {
+	if ch.Title == nil {
+		return "Untitled"
+	}
+	return *ch.Title
+}
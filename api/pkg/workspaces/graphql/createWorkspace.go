@@ -2,12 +2,14 @@ package graphql
 
 import (
 	"context"
+	"errors"
 
 	"getsturdy.com/api/pkg/auth"
 	"getsturdy.com/api/pkg/changes"
 	"getsturdy.com/api/pkg/codebases"
 	gqlerrors "getsturdy.com/api/pkg/graphql/errors"
 	"getsturdy.com/api/pkg/graphql/resolvers"
+	"getsturdy.com/api/pkg/trunks"
 	"getsturdy.com/api/pkg/workspaces/service"
 
 	"github.com/graph-gophers/graphql-go"
@@ -38,6 +40,12 @@ func (r *WorkspaceRootResolver) CreateWorkspace(ctx context.Context, args resolv
 		)
 	}
 
+	if args.Input.OnTopOfWorkspace != nil && (args.Input.OnTopOfChange != nil || args.Input.OnTopOfChangeWithRevert != nil) {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest,
+			"onTopOfWorkspace", "can't be set together with onTopOfChange or onTopOfChangeWithRevert",
+		)
+	}
+
 	// Create request to pass to the old REST API route handler
 	req := service.CreateWorkspaceRequest{
 		CodebaseID: codebaseID,
@@ -68,8 +76,28 @@ func (r *WorkspaceRootResolver) CreateWorkspace(ctx context.Context, args resolv
 		}
 	}
 
+	if args.Input.OnTopOfWorkspace != nil {
+		parent, err := r.workspaceService.GetByID(ctx, string(*args.Input.OnTopOfWorkspace))
+		if err != nil {
+			return nil, gqlerrors.Error(err)
+		}
+		if err := r.authService.CanRead(ctx, parent); err != nil {
+			return nil, gqlerrors.Error(err)
+		}
+		req.ParentWorkspaceID = &parent.ID
+		req.Name = "On " + parent.NameOrFallback()
+	}
+
+	if args.Input.TrunkID != nil {
+		trunkID := trunks.ID(*args.Input.TrunkID)
+		req.TrunkID = &trunkID
+	}
+
 	ws, err := r.workspaceService.Create(ctx, req)
-	if err != nil {
+	switch {
+	case errors.Is(err, service.ErrParentArchived):
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "onTopOfWorkspace", "can't stack on top of an archived workspace")
+	case err != nil:
 		return nil, gqlerrors.Error(err)
 	}
 
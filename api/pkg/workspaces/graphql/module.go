@@ -17,6 +17,8 @@ import (
 	graphql_snapshots "getsturdy.com/api/pkg/snapshots/graphql"
 	graphql_suggestions "getsturdy.com/api/pkg/suggestions/graphql"
 	graphql_rebase "getsturdy.com/api/pkg/sync/graphql"
+	service_sync "getsturdy.com/api/pkg/sync/service"
+	graphql_trunks "getsturdy.com/api/pkg/trunks/graphql"
 	db_view "getsturdy.com/api/pkg/views/db"
 	graphql_view "getsturdy.com/api/pkg/views/graphql"
 	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
@@ -42,6 +44,8 @@ func Module(c *di.Container) {
 	c.Import(graphql_workspace_watchers.Module)
 	c.Import(graphql_rebase.Module)
 	c.Import(graphql_snapshots.Module)
+	c.Import(graphql_trunks.Module)
+	c.Import(service_sync.Module)
 
 	c.Register(NewResolver)
 
@@ -15,10 +15,7 @@ import (
 	"getsturdy.com/api/pkg/snapshots"
 	"getsturdy.com/api/pkg/unidiff"
 	"getsturdy.com/api/pkg/workspaces"
-	"getsturdy.com/api/pkg/workspaces/db"
 	service_workspace "getsturdy.com/api/pkg/workspaces/service"
-	"getsturdy.com/api/pkg/workspaces/vcs"
-	vcsvcs "getsturdy.com/api/vcs"
 )
 
 type WorkspaceResolver struct {
@@ -178,44 +175,11 @@ func (r *WorkspaceResolver) GitHubPullRequest(ctx context.Context) (resolvers.Gi
 }
 
 func (r *WorkspaceResolver) UpToDateWithTrunk(ctx context.Context) (bool, error) {
-	if err := r.updateIsUpToDateWithTrunk(ctx); err != nil {
-		return false, gqlerrors.Error(err)
-	}
-	if r.w.UpToDateWithTrunk == nil {
-		return false, gqlerrors.Error(fmt.Errorf("failed to calculate UpToDateWithTrunk"))
-	}
-	return *r.w.UpToDateWithTrunk, nil
-}
-
-func (r *WorkspaceResolver) updateIsUpToDateWithTrunk(ctx context.Context) error {
-	// We have a cached result, don't do anything
-	if r.w.UpToDateWithTrunk != nil {
-		return nil
-	}
-
-	var upToDate bool
-	err := r.root.executorProvider.New().GitRead(func(repo vcsvcs.RepoGitReader) error {
-		// Recalculate
-		var err error
-		upToDate, err = vcs.UpToDateWithTrunk(repo, r.w.ID)
-		if err != nil {
-			return fmt.Errorf("failed to check if workspace is up to date with trunk: %w", err)
-		}
-		return nil
-	}).ExecTrunk(r.w.CodebaseID, "updateIsUpToDateWithTrunk")
+	upToDate, err := r.root.workspaceService.UpToDateWithTrunk(ctx, r.w)
 	if err != nil {
-		return err
-	}
-
-	// Save updated cache
-	if err := r.root.workspaceWriter.UpdateFields(ctx, r.w.ID, db.SetUpToDateWithTrunk(&upToDate)); err != nil {
-		return err
+		return false, gqlerrors.Error(err)
 	}
-
-	// Also update the cached version of the workspace that we have in memory
-	r.w.UpToDateWithTrunk = &upToDate
-
-	return nil
+	return upToDate, nil
 }
 
 func (r *WorkspaceResolver) Conflicts(ctx context.Context) (bool, error) {
@@ -376,6 +340,33 @@ func (r *WorkspaceResolver) Change(ctx context.Context) (resolvers.ChangeResolve
 	})
 }
 
+func (r *WorkspaceResolver) ParentWorkspace(ctx context.Context) (resolvers.WorkspaceResolver, error) {
+	if r.w.ParentWorkspaceID == nil {
+		return nil, nil
+	}
+	allowArchived := true
+	return r.root.Workspace(ctx, resolvers.WorkspaceArgs{ID: graphql.ID(*r.w.ParentWorkspaceID), AllowArchived: &allowArchived})
+}
+
+func (r *WorkspaceResolver) ChildWorkspaces(ctx context.Context) ([]resolvers.WorkspaceResolver, error) {
+	children, err := r.root.workspaceService.Children(ctx, r.w)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+	res := make([]resolvers.WorkspaceResolver, 0, len(children))
+	for _, child := range children {
+		res = append(res, &WorkspaceResolver{w: child, root: r.root})
+	}
+	return res, nil
+}
+
+func (r *WorkspaceResolver) Trunk(ctx context.Context) (resolvers.TrunkResolver, error) {
+	if r.w.TrunkID == nil {
+		return nil, nil
+	}
+	return r.root.trunksRootResolver.InternalTrunk(ctx, *r.w.TrunkID)
+}
+
 func (r *WorkspaceResolver) RebaseStatus(ctx context.Context) (resolvers.RebaseStatusResolver, error) {
 	return r.root.rebaseStatusRootResolver.InternalWorkspaceRebaseStatus(ctx, r.w.ID)
 }
@@ -398,3 +389,13 @@ func (r *WorkspaceResolver) Snapshot(ctx context.Context) (resolvers.SnapshotRes
 	}
 	return sr, nil
 }
+
+const defaultSnapshotsLimit = 50
+
+func (r *WorkspaceResolver) Snapshots(ctx context.Context, args resolvers.WorkspaceSnapshotsArgs) ([]resolvers.SnapshotResolver, error) {
+	limit := defaultSnapshotsLimit
+	if args.Last != nil && *args.Last > 0 {
+		limit = int(*args.Last)
+	}
+	return r.root.snapshotsResolver.InternalListByWorkspaceID(ctx, r.w.ID, limit)
+}
@@ -2,11 +2,14 @@ package graphql
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"getsturdy.com/api/pkg/auth"
 	service_auth "getsturdy.com/api/pkg/auth/service"
 	service_change "getsturdy.com/api/pkg/changes/service"
 	"getsturdy.com/api/pkg/codebases"
+	"getsturdy.com/api/pkg/codebases/acl"
 	db_codebases "getsturdy.com/api/pkg/codebases/db"
 	db_comments "getsturdy.com/api/pkg/comments/db"
 	"getsturdy.com/api/pkg/events"
@@ -17,6 +20,7 @@ import (
 	db_snapshots "getsturdy.com/api/pkg/snapshots/db"
 	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
 	service_suggestions "getsturdy.com/api/pkg/suggestions/service"
+	service_sync "getsturdy.com/api/pkg/sync/service"
 	service_user "getsturdy.com/api/pkg/users/service"
 	db_view "getsturdy.com/api/pkg/views/db"
 	"getsturdy.com/api/pkg/workspaces"
@@ -50,12 +54,14 @@ type WorkspaceRootResolver struct {
 	rebaseStatusRootResolver      resolvers.RebaseStatusRootResolver
 	downloadsResolver             resolvers.ContentsDownloadUrlRootResolver
 	snapshotsResolver             resolvers.SnapshotsRootResolver
+	trunksRootResolver            resolvers.TrunksRootResolver
 
 	suggestionsService *service_suggestions.Service
 	workspaceService   *service_workspace.Service
 	authService        *service_auth.Service
 	changeService      *service_change.Service
 	userService        service_user.Service
+	syncService        *service_sync.Service
 
 	logger           *zap.Logger
 	viewEvents       events.EventReadWriter
@@ -89,12 +95,14 @@ func NewResolver(
 	rebaseStatusRootResolver resolvers.RebaseStatusRootResolver,
 	downloadsResolver resolvers.ContentsDownloadUrlRootResolver,
 	snapshotsResolver resolvers.SnapshotsRootResolver,
+	trunksRootResolver resolvers.TrunksRootResolver,
 
 	suggestionsService *service_suggestions.Service,
 	workspaceService *service_workspace.Service,
 	authService *service_auth.Service,
 	changeService *service_change.Service,
 	userService service_user.Service,
+	syncService *service_sync.Service,
 
 	logger *zap.Logger,
 	viewEventsWriter events.EventReadWriter,
@@ -127,12 +135,14 @@ func NewResolver(
 		rebaseStatusRootResolver:      rebaseStatusRootResolver,
 		downloadsResolver:             downloadsResolver,
 		snapshotsResolver:             snapshotsResolver,
+		trunksRootResolver:            trunksRootResolver,
 
 		suggestionsService: suggestionsService,
 		workspaceService:   workspaceService,
 		authService:        authService,
 		changeService:      changeService,
 		userService:        userService,
+		syncService:        syncService,
 
 		logger:           logger.Named("workspaceRootResolver"),
 		viewEvents:       viewEventsWriter,
@@ -205,6 +215,10 @@ func (r *WorkspaceRootResolver) ArchiveWorkspace(ctx context.Context, args resol
 		return nil, gqlerrors.Error(err)
 	}
 
+	if err := r.canAdministrate(ctx, ws); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
 	if err := r.workspaceService.Archive(ctx, ws); err != nil {
 		return nil, gqlerrors.Error(err)
 	}
@@ -212,6 +226,18 @@ func (r *WorkspaceRootResolver) ArchiveWorkspace(ctx context.Context, args resol
 	return &WorkspaceResolver{w: ws, root: r}, nil
 }
 
+// canAdministrate checks that the user is allowed to make changes to other users' workspaces, if ws is not their own.
+func (r *WorkspaceRootResolver) canAdministrate(ctx context.Context, ws *workspaces.Workspace) error {
+	userID, err := auth.UserID(ctx)
+	if err != nil {
+		return err
+	}
+	if ws.UserID == userID {
+		return nil
+	}
+	return r.authService.CanPerform(ctx, ws.CodebaseID, acl.ActionAdmin, acl.Identity{Type: acl.Workspaces, ID: ws.ID})
+}
+
 func (r *WorkspaceRootResolver) UnarchiveWorkspace(ctx context.Context, args resolvers.UnarchiveWorkspaceArgs) (resolvers.WorkspaceResolver, error) {
 	ws, err := r.workspaceReader.Get(string(args.ID))
 	if err != nil {
@@ -222,6 +248,10 @@ func (r *WorkspaceRootResolver) UnarchiveWorkspace(ctx context.Context, args res
 		return nil, gqlerrors.Error(err)
 	}
 
+	if err := r.canAdministrate(ctx, ws); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
 	if err := r.workspaceService.Unarchive(ctx, ws); err != nil {
 		return nil, gqlerrors.Error(err)
 	}
@@ -243,9 +273,73 @@ func (r *WorkspaceRootResolver) SetWorkspaceSnapshot(ctx context.Context, args r
 		return nil, gqlerrors.Error(err)
 	}
 
+	diffs, err := r.gitSnapshotter.Diffs(ctx, snap.ID)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+	var paths []string
+	for _, diff := range diffs {
+		paths = append(paths, diff.Paths()...)
+	}
+	if err := r.authService.CanWriteFiles(ctx, ws, paths...); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
 	if err := r.workspaceService.SetSnapshot(ctx, ws, snap); err != nil {
 		return nil, gqlerrors.Error(err)
 	}
 
 	return &WorkspaceResolver{w: ws, root: r}, nil
 }
+
+func (r *WorkspaceRootResolver) RestoreWorkspaceSnapshot(ctx context.Context, args resolvers.RestoreWorkspaceSnapshotArgs) (resolvers.WorkspaceResolver, error) {
+	var restoreOptions []service_workspace.RestoreOption
+	if args.Input.Paths != nil && len(*args.Input.Paths) > 0 {
+		restoreOptions = append(restoreOptions, service_workspace.RestoreWithPaths(*args.Input.Paths...))
+	}
+	if args.Input.HunkIDs != nil && len(*args.Input.HunkIDs) > 0 {
+		restoreOptions = append(restoreOptions, service_workspace.RestoreWithHunkIDs(*args.Input.HunkIDs...))
+	}
+	if len(restoreOptions) == 0 {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "paths or hunkIDs must be set")
+	}
+
+	ws, err := r.workspaceService.GetByID(ctx, string(args.Input.WorkspaceID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+	if err := r.authService.CanWrite(ctx, ws); err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	snap, err := r.snapshotsRepo.Get(snapshots.ID(args.Input.SnapshotID))
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+	if snap.WorkspaceID != ws.ID {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "snapshotID", "snapshot is not in the workspace")
+	}
+
+	if args.Input.Paths != nil {
+		if err := r.authService.CanWriteFiles(ctx, ws, *args.Input.Paths...); err != nil {
+			return nil, gqlerrors.Error(err)
+		}
+	}
+
+	// hunks in files that the user can not write to are not restored
+	allower, err := r.authService.GetWriteAllower(ctx, ws)
+	if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+	restoreOptions = append(restoreOptions, service_workspace.RestoreWithAllower(allower))
+
+	if _, err := r.workspaceService.RestoreSnapshot(ctx, ws, snap, restoreOptions...); errors.Is(err, service_workspace.ErrNothingToRestore) {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "message", "nothing to restore")
+	} else if errors.Is(err, service_workspace.ErrUnknownHunks) {
+		return nil, gqlerrors.Error(gqlerrors.ErrBadRequest, "hunkIDs", err.Error())
+	} else if err != nil {
+		return nil, gqlerrors.Error(err)
+	}
+
+	return &WorkspaceResolver{w: ws, root: r}, nil
+}
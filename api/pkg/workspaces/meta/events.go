@@ -70,6 +70,10 @@ func (w *writerWithEvents) UnsetUpToDateWithTrunkForAllInCodebase(codebaseID cod
 	return nil
 }
 
+func (w *writerWithEvents) DeleteByCodebaseID(ctx context.Context, codebaseID codebases.ID) error {
+	return w.workspaceRepo.DeleteByCodebaseID(ctx, codebaseID)
+}
+
 // Updated sets UpdatedAt, and resets Behind and Ahead counters
 func Updated(ctx context.Context, workspaceReader db.WorkspaceReader, workspaceWriter db.WorkspaceWriter, workspaceID string) error {
 	now := time.Now()
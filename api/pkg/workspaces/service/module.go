@@ -10,6 +10,7 @@ import (
 	eventsv2 "getsturdy.com/api/pkg/events/v2"
 	"getsturdy.com/api/pkg/logger"
 	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
+	service_trunks "getsturdy.com/api/pkg/trunks/service"
 	service_users "getsturdy.com/api/pkg/users/service/module"
 	service_view "getsturdy.com/api/pkg/views/service"
 	db_workspaces "getsturdy.com/api/pkg/workspaces/db"
@@ -29,5 +30,6 @@ func Module(c *di.Container) {
 	c.Import(events.Module)
 	c.Import(eventsv2.Module)
 	c.Import(service_snapshots.Module)
+	c.Import(service_trunks.Module)
 	c.Register(New)
 }
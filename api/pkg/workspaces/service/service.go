@@ -19,6 +19,8 @@ import (
 	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
 	vcs_snapshots "getsturdy.com/api/pkg/snapshots/vcs"
 	service_statuses "getsturdy.com/api/pkg/statuses/service"
+	"getsturdy.com/api/pkg/trunks"
+	service_trunks "getsturdy.com/api/pkg/trunks/service"
 	"getsturdy.com/api/pkg/unidiff"
 	"getsturdy.com/api/pkg/unidiff/lfs"
 	"getsturdy.com/api/pkg/users"
@@ -43,6 +45,14 @@ type CreateWorkspaceRequest struct {
 
 	BaseChangeID *changes.ID
 	Revert       bool
+
+	// ParentWorkspaceID stacks the new workspace on top of the latest snapshot of another workspace. It's mutually
+	// exclusive with BaseChangeID.
+	ParentWorkspaceID *string
+
+	// TrunkID is the trunk that the new workspace is based on, and lands to. If nil, the default trunk is used.
+	// Stacked workspaces are always on the same trunk as their parent.
+	TrunkID *trunks.ID
 }
 
 type Service struct {
@@ -56,6 +66,7 @@ type Service struct {
 	viewService     *service_view.Service
 	usersService    service_users.Service
 	statusesService *service_statuses.Service
+	trunksService   *service_trunks.Service
 
 	eventsSender     events.EventSender
 	eventsSenderV2   *eventsv2.Publisher
@@ -74,6 +85,7 @@ func New(
 	viewService *service_view.Service,
 	usersService service_users.Service,
 	statusesService *service_statuses.Service,
+	trunksService *service_trunks.Service,
 
 	executorProvider executor.Provider,
 	eventsSender events.EventSender,
@@ -91,6 +103,7 @@ func New(
 		viewService:     viewService,
 		usersService:    usersService,
 		statusesService: statusesService,
+		trunksService:   trunksService,
 
 		executorProvider: executorProvider,
 		eventsSender:     eventsSender,
@@ -193,7 +206,7 @@ func (s *Service) diffsFromView(ctx context.Context, ws *workspaces.Workspace, o
 
 			diffs = hunkifiedDiff
 			return nil
-		}).ExecView(ws.CodebaseID, *ws.ViewID, "workspaceViewDiffs"); err != nil {
+		}).ExecViewContext(ctx, ws.CodebaseID, *ws.ViewID, "workspaceViewDiffs"); err != nil {
 		return nil, false, fmt.Errorf("failed to get diffs from view: %w", err)
 	}
 	return diffs, isRebasing, nil
@@ -232,6 +245,249 @@ func (s *Service) SetSnapshot(ctx context.Context, ws *workspaces.Workspace, sna
 	return nil
 }
 
+type RestoreOptions struct {
+	Paths   []string
+	HunkIDs []string
+	Allower *unidiff.Allower
+}
+
+type RestoreOption func(*RestoreOptions)
+
+// RestoreWithPaths restores the given files, as they were in the snapshot.
+func RestoreWithPaths(paths ...string) RestoreOption {
+	return func(options *RestoreOptions) {
+		options.Paths = append(options.Paths, paths...)
+	}
+}
+
+// RestoreWithHunkIDs restores the given hunks. The hunk ids are from the diffs between the latest snapshot of the
+// workspace, and the snapshot that is restored.
+func RestoreWithHunkIDs(hunkIDs ...string) RestoreOption {
+	return func(options *RestoreOptions) {
+		options.HunkIDs = append(options.HunkIDs, hunkIDs...)
+	}
+}
+
+// RestoreWithAllower only restores the files that are allowed by allower.
+func RestoreWithAllower(allower *unidiff.Allower) RestoreOption {
+	return func(options *RestoreOptions) {
+		options.Allower = allower
+	}
+}
+
+func getRestoreOptions(oo ...RestoreOption) *RestoreOptions {
+	options := &RestoreOptions{}
+	for _, o := range oo {
+		o(options)
+	}
+	return options
+}
+
+var (
+	ErrNothingToRestore = errors.New("nothing to restore")
+	ErrUnknownHunks     = errors.New("hunks are not in the diff between the latest snapshot and the restored snapshot")
+)
+
+// RestoreSnapshot brings back selected files or hunks from an older snapshot of the workspace, on top of the current
+// state of the workspace. Everything else in the workspace is left as is. Unlike SetSnapshot, the history of the
+// workspace is not rewound, and the result is recorded as a new snapshot.
+func (s *Service) RestoreSnapshot(ctx context.Context, ws *workspaces.Workspace, snap *snapshots.Snapshot, oo ...RestoreOption) (*snapshots.Snapshot, error) {
+	if snap.WorkspaceID != ws.ID {
+		return nil, fmt.Errorf("snapshot is not from this workspace")
+	}
+
+	options := getRestoreOptions(oo...)
+	if len(options.Paths) == 0 && len(options.HunkIDs) == 0 {
+		return nil, ErrNothingToRestore
+	}
+
+	var diffsOptions []service_snapshots.DiffsOption
+	if options.Allower != nil {
+		diffsOptions = append(diffsOptions, service_snapshots.WithAllower(options.Allower))
+	}
+
+	// the hunk ids are the ones that are shown to the user, from the diffs between the latest snapshot and the restored
+	// snapshot, so they are matched before the view is snapshotted again
+	var patches [][]byte
+	if len(options.HunkIDs) > 0 {
+		if ws.LatestSnapshotID == nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownHunks, strings.Join(options.HunkIDs, ", "))
+		}
+		latest, err := s.snap.GetByID(ctx, *ws.LatestSnapshotID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get latest snapshot: %w", err)
+		}
+		diffs, err := s.snap.DiffBetween(ctx, latest, snap, diffsOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get diffs: %w", err)
+		}
+		hunkPatches, unknown := restoreHunkPatches(diffs, options)
+		if len(unknown) > 0 {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownHunks, strings.Join(unknown, ", "))
+		}
+		patches = append(patches, hunkPatches...)
+	}
+
+	var current *snapshots.Snapshot
+	if ws.ViewID != nil {
+		// make sure that the latest changes in the view are not lost
+		var err error
+		current, err = s.snap.Snapshot(ctx, ws.CodebaseID, ws.ID, snapshots.ActionPreSnapshotRestore,
+			service_snapshots.WithOnView(*ws.ViewID),
+			service_snapshots.WithMarkAsLatestInWorkspace(),
+			service_snapshots.WithNoThrottle(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to snapshot view: %w", err)
+		}
+	} else if ws.LatestSnapshotID != nil {
+		var err error
+		current, err = s.snap.GetByID(ctx, *ws.LatestSnapshotID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get snapshot: %w", err)
+		}
+	} else {
+		return nil, fmt.Errorf("failed to restore snapshot: no view or snapshot")
+	}
+
+	if len(options.Paths) > 0 {
+		// whole files are restored on top of the current state, including changes that are not in the latest snapshot
+		diffs, err := s.snap.DiffBetween(ctx, current, snap, diffsOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get diffs: %w", err)
+		}
+		patches = append(patches, restorePathPatches(diffs, options)...)
+	}
+
+	if len(patches) == 0 {
+		return nil, ErrNothingToRestore
+	}
+
+	applyPatches := func(repo vcs.RepoWriter) error {
+		if err := repo.ApplyPatchesToWorkdir(patches); err != nil {
+			return fmt.Errorf("failed to apply patches: %w", err)
+		}
+		return nil
+	}
+
+	var restored *snapshots.Snapshot
+	if ws.ViewID != nil {
+		if err := s.executorProvider.New().Write(applyPatches).ExecViewContext(ctx, ws.CodebaseID, *ws.ViewID, "restoreSnapshot"); err != nil {
+			return nil, fmt.Errorf("failed to restore snapshot: %w", err)
+		}
+
+		var err error
+		restored, err = s.snap.Snapshot(ctx, ws.CodebaseID, ws.ID, snapshots.ActionSnapshotRestore,
+			service_snapshots.WithOnView(*ws.ViewID),
+			service_snapshots.WithMarkAsLatestInWorkspace(),
+			service_snapshots.WithNoThrottle(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to snapshot: %w", err)
+		}
+
+		view, err := s.viewService.GetByID(ctx, *ws.ViewID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get view: %w", err)
+		}
+
+		if err := s.eventsSenderV2.ViewUpdated(ctx, eventsv2.Codebase(ws.CodebaseID), view); err != nil {
+			return nil, fmt.Errorf("failed to send event about updated view view: %w", err)
+		}
+	} else {
+		if err := s.executorProvider.New().
+			Write(vcs_view.CheckoutSnapshot(current)).
+			Write(func(repo vcs.RepoWriter) error {
+				if err := applyPatches(repo); err != nil {
+					return err
+				}
+
+				var err error
+				restored, err = s.snap.Snapshot(
+					ctx,
+					ws.CodebaseID,
+					ws.ID,
+					snapshots.ActionSnapshotRestore,
+					service_snapshots.WithOnView(*repo.ViewID()),
+					service_snapshots.WithMarkAsLatestInWorkspace(),
+					service_snapshots.WithOnRepo(repo),
+				)
+				if err != nil {
+					return fmt.Errorf("failed to snapshot: %w", err)
+				}
+				return nil
+			}).ExecTemporaryViewContext(ctx, ws.CodebaseID, "restoreSnapshot"); err != nil {
+			return nil, fmt.Errorf("failed to restore snapshot: %w", err)
+		}
+	}
+
+	ws.SetSnapshot(restored)
+
+	s.analyticsService.Capture(ctx, "restore-snapshot",
+		analytics.Property("workspace_id", ws.ID),
+		analytics.Property("snapshot_id", snap.ID),
+		analytics.Property("paths_count", len(options.Paths)),
+		analytics.Property("hunks_count", len(options.HunkIDs)),
+		analytics.Property("codebase_id", ws.CodebaseID),
+	)
+
+	return restored, nil
+}
+
+// restorePathPatches returns the patches of the files in diffs that are selected by the options.
+func restorePathPatches(diffs []unidiff.FileDiff, options *RestoreOptions) [][]byte {
+	paths := make(map[string]bool, len(options.Paths))
+	for _, p := range options.Paths {
+		paths[p] = true
+	}
+
+	var patches [][]byte
+	for _, fd := range diffs {
+		if !paths[fd.OrigName] && !paths[fd.NewName] {
+			continue
+		}
+		for _, hunk := range fd.Hunks {
+			patches = append(patches, []byte(hunk.Patch))
+		}
+	}
+	return patches
+}
+
+// restoreHunkPatches returns the patches of the hunks in diffs that are selected by the options, and the ids of the
+// selected hunks that are not in diffs. Hunks in files that are restored as a whole are skipped.
+func restoreHunkPatches(diffs []unidiff.FileDiff, options *RestoreOptions) ([][]byte, []string) {
+	paths := make(map[string]bool, len(options.Paths))
+	for _, p := range options.Paths {
+		paths[p] = true
+	}
+	found := make(map[string]bool, len(options.HunkIDs))
+	for _, id := range options.HunkIDs {
+		found[id] = false
+	}
+
+	var patches [][]byte
+	for _, fd := range diffs {
+		wholeFile := paths[fd.OrigName] || paths[fd.NewName]
+		for _, hunk := range fd.Hunks {
+			if _, selected := found[hunk.ID]; !selected {
+				continue
+			}
+			found[hunk.ID] = true
+			if !wholeFile {
+				patches = append(patches, []byte(hunk.Patch))
+			}
+		}
+	}
+
+	var unknown []string
+	for _, id := range options.HunkIDs {
+		if !found[id] {
+			unknown = append(unknown, id)
+		}
+	}
+	return patches, unknown
+}
+
 type CopyPatchesOptions struct {
 	PatchIDs *[]string
 }
@@ -323,6 +579,7 @@ func (s *Service) CreateFromWorkspace(ctx context.Context, from *workspaces.Work
 		CodebaseID:   from.CodebaseID,
 		Name:         name,
 		BaseChangeID: baseChangeID,
+		TrunkID:      from.TrunkID,
 	}
 
 	newWorkspace, err := s.Create(ctx, createRequest)
@@ -352,6 +609,17 @@ func (s *Service) Create(ctx context.Context, req CreateWorkspaceRequest) (*work
 		ws.Name = &n
 	}
 
+	if req.TrunkID != nil {
+		trunk, err := s.trunksService.Get(ctx, *req.TrunkID)
+		if err != nil {
+			return nil, fmt.Errorf("could not get trunk: %w", err)
+		}
+		if trunk.CodebaseID != ws.CodebaseID {
+			return nil, fmt.Errorf("trunk does not belong to this codebase")
+		}
+		ws.TrunkID = &trunk.ID
+	}
+
 	var baseCommitSha string
 	var baseCommitParentSha *string
 	if req.BaseChangeID != nil {
@@ -382,17 +650,54 @@ func (s *Service) Create(ctx context.Context, req CreateWorkspaceRequest) (*work
 		}
 	}
 
+	if req.ParentWorkspaceID != nil {
+		if req.BaseChangeID != nil {
+			return nil, fmt.Errorf("can't create a workspace on top of both a change and a workspace")
+		}
+
+		parent, err := s.GetByID(ctx, *req.ParentWorkspaceID)
+		if err != nil {
+			return nil, fmt.Errorf("could not get parent workspace: %w", err)
+		}
+		if parent.CodebaseID != ws.CodebaseID {
+			return nil, fmt.Errorf("parent workspace does not belong to this codebase")
+		}
+		if parent.IsArchived() {
+			return nil, ErrParentArchived
+		}
+
+		baseCommitSha, err = s.stackBaseCommitID(ctx, parent)
+		if err != nil {
+			return nil, fmt.Errorf("could not get parent workspace base: %w", err)
+		}
+		if req.TrunkID != nil && (parent.TrunkID == nil || *parent.TrunkID != *req.TrunkID) {
+			return nil, fmt.Errorf("stacked workspaces must be on the same trunk as their parent")
+		}
+
+		ws.ParentWorkspaceID = &parent.ID
+		ws.TrunkID = parent.TrunkID
+	}
+
 	if err := s.executorProvider.New().GitWrite(func(repo vcs.RepoGitWriter) error {
 		// Ensure codebase status
 		if err := EnsureCodebaseStatus(repo); err != nil {
 			return err
 		}
 
-		if req.BaseChangeID != nil && baseCommitSha != "" {
-			// Create workspace at the change that we want to revert
+		if baseCommitSha != "" {
+			// Create workspace at the change that we want to revert, or at the parent workspace
 			if err := vcs_workspace.CreateOnCommitID(repo, ws.ID, baseCommitSha); err != nil {
 				return fmt.Errorf("failed to create workspace at change: %w", err)
 			}
+		} else if ws.TrunkID != nil {
+			// Create workspace at the current head of the trunk
+			trunkHeadCommitID, err := repo.BranchCommitID(trunks.BranchName(ws.TrunkID))
+			if err != nil {
+				return fmt.Errorf("failed to get trunk head: %w", err)
+			}
+			if err := vcs_workspace.CreateOnCommitID(repo, ws.ID, trunkHeadCommitID); err != nil {
+				return fmt.Errorf("failed to create workspace at trunk: %w", err)
+			}
 		} else {
 			// Create workspace at current trunk
 			if err := vcs_workspace.Create(repo, ws.ID); err != nil {
@@ -400,7 +705,7 @@ func (s *Service) Create(ctx context.Context, req CreateWorkspaceRequest) (*work
 			}
 		}
 		return nil
-	}).ExecTrunk(req.CodebaseID, "createWorkspace"); err != nil {
+	}).ExecTrunkContext(ctx, req.CodebaseID, "createWorkspace"); err != nil {
 		return nil, err
 	}
 
@@ -427,13 +732,64 @@ func (s *Service) Create(ctx context.Context, req CreateWorkspaceRequest) (*work
 		analytics.CodebaseID(req.CodebaseID),
 		analytics.Property("id", ws.ID),
 		analytics.Property("at_existing_change", req.BaseChangeID != nil),
+		analytics.Property("stacked", req.ParentWorkspaceID != nil),
+		analytics.Property("on_trunk", ws.TrunkID != nil),
 		analytics.Property("name", ws.Name),
 	)
 
 	return &ws, nil
 }
 
-var ErrNotFound = errors.New("not found")
+var (
+	ErrNotFound       = errors.New("not found")
+	ErrParentArchived = errors.New("parent workspace is archived")
+)
+
+// stackBaseCommitID returns the commit that workspaces stacked on top of _parent_ are based on. That is the commit of
+// the latest snapshot of the parent, or the head of the parent if it doesn't have any snapshots.
+func (s *Service) stackBaseCommitID(ctx context.Context, parent *workspaces.Workspace) (string, error) {
+	if parent.ViewID != nil {
+		// make sure that the latest changes from the view are included
+		if _, err := s.snap.Snapshot(ctx, parent.CodebaseID, parent.ID, snapshots.ActionViewSync,
+			service_snapshots.WithOnView(*parent.ViewID),
+			service_snapshots.WithNoThrottle(),
+		); err != nil {
+			return "", fmt.Errorf("failed to snapshot parent: %w", err)
+		}
+		// reload to get the latest snapshot id
+		reloaded, err := s.GetByID(ctx, parent.ID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get parent: %w", err)
+		}
+		parent = reloaded
+	}
+
+	if parent.LatestSnapshotID != nil {
+		snapshot, err := s.snap.GetByID(ctx, *parent.LatestSnapshotID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get snapshot: %w", err)
+		}
+		return snapshot.CommitSHA, nil
+	}
+
+	var commitID string
+	if err := s.executorProvider.New().GitRead(func(repo vcs.RepoGitReader) error {
+		var err error
+		commitID, err = repo.BranchCommitID(parent.ID)
+		if err != nil {
+			return fmt.Errorf("could not get head commit from git: %w", err)
+		}
+		return nil
+	}).ExecTrunkContext(ctx, parent.CodebaseID, "workspaceStackBase"); err != nil {
+		return "", err
+	}
+	return commitID, nil
+}
+
+// Children returns the unarchived workspaces that are stacked on top of _ws_.
+func (s *Service) Children(ctx context.Context, ws *workspaces.Workspace) ([]*workspaces.Workspace, error) {
+	return s.workspaceReader.ListByParentWorkspaceID(ctx, ws.ID)
+}
 
 func (s *Service) HeadChange(ctx context.Context, ws *workspaces.Workspace) (*changes.Change, error) {
 	if ws.HeadChangeComputed {
@@ -457,7 +813,7 @@ func (s *Service) HeadChange(ctx context.Context, ws *workspaces.Workspace) (*ch
 			return fmt.Errorf("could not get head commit from git: %w", err)
 		}
 		return nil
-	}).ExecTrunk(ws.CodebaseID, "workspaceHeadChange")
+	}).ExecTrunkContext(ctx, ws.CodebaseID, "workspaceHeadChange")
 	if err != nil {
 		return nil, err
 	}
@@ -512,8 +868,8 @@ func EnsureCodebaseStatus(repo vcs.RepoGitWriter) error {
 	if err != nil {
 		return err
 	}
-	if defaultBranch != "refs/heads/sturdytrunk" {
-		if err := repo.CreateAndSetDefaultBranch("sturdytrunk"); err != nil {
+	if defaultBranch != "refs/heads/"+trunks.BranchName(nil) {
+		if err := repo.CreateAndSetDefaultBranch(trunks.BranchName(nil)); err != nil {
 			return err
 		}
 	}
@@ -577,7 +933,7 @@ func (svc *Service) CreateWelcomeWorkspace(ctx context.Context, codebaseID codeb
 		return nil
 	}
 
-	if err := svc.executorProvider.New().FileReadGitWrite(cb).ExecTrunk(codebaseID, "createWelcomeMessage"); err != nil {
+	if err := svc.executorProvider.New().FileReadGitWrite(cb).ExecTrunkContext(ctx, codebaseID, "createWelcomeMessage"); err != nil {
 		return fmt.Errorf("failed to create welcome snapshot: %w", err)
 	}
 
@@ -588,7 +944,7 @@ func (s *Service) RemovePatches(ctx context.Context, ws *workspaces.Workspace, h
 	removePatches := vcs_workspace.Remove(s.logger, hunkIDs...)
 
 	if ws.ViewID != nil {
-		if err := s.executorProvider.New().Write(removePatches).ExecView(ws.CodebaseID, *ws.ViewID, "removePatches"); err != nil {
+		if err := s.executorProvider.New().Write(removePatches).ExecViewContext(ctx, ws.CodebaseID, *ws.ViewID, "removePatches"); err != nil {
 			return fmt.Errorf("failed to remove patches: %w", err)
 		}
 
@@ -629,7 +985,7 @@ func (s *Service) RemovePatches(ctx context.Context, ws *workspaces.Workspace, h
 				}
 
 				return nil
-			}).ExecTemporaryView(ws.CodebaseID, "removePatches"); err != nil {
+			}).ExecTemporaryViewContext(ctx, ws.CodebaseID, "removePatches"); err != nil {
 			return fmt.Errorf("failed to remove patches: %w", err)
 		}
 
@@ -639,6 +995,38 @@ func (s *Service) RemovePatches(ctx context.Context, ws *workspaces.Workspace, h
 	return fmt.Errorf("failed to remove patches: no view or snapshot")
 }
 
+// UpToDateWithTrunk returns true if the workspace is based on the current head of the trunk. The result is cached on
+// the workspace.
+func (s *Service) UpToDateWithTrunk(ctx context.Context, ws *workspaces.Workspace) (bool, error) {
+	// We have a cached result, don't do anything
+	if ws.UpToDateWithTrunk != nil {
+		return *ws.UpToDateWithTrunk, nil
+	}
+
+	var upToDate bool
+	if err := s.executorProvider.New().GitRead(func(repo vcs.RepoGitReader) error {
+		// Recalculate
+		var err error
+		upToDate, err = vcs_workspace.UpToDateWithTrunk(repo, ws.ID, trunks.BranchName(ws.TrunkID))
+		if err != nil {
+			return fmt.Errorf("failed to check if workspace is up to date with trunk: %w", err)
+		}
+		return nil
+	}).ExecTrunkContext(ctx, ws.CodebaseID, "updateIsUpToDateWithTrunk"); err != nil {
+		return false, err
+	}
+
+	// Save updated cache
+	if err := s.workspaceWriter.UpdateFields(ctx, ws.ID, db.SetUpToDateWithTrunk(&upToDate)); err != nil {
+		return false, err
+	}
+
+	// Also update the cached version of the workspace that we have in memory
+	ws.UpToDateWithTrunk = &upToDate
+
+	return upToDate, nil
+}
+
 func (s *Service) HasConflicts(ctx context.Context, ws *workspaces.Workspace) (bool, error) {
 	if ws.LatestSnapshotID == nil {
 		// can not check for conflicts, have no snapshot
@@ -646,10 +1034,11 @@ func (s *Service) HasConflicts(ctx context.Context, ws *workspaces.Workspace) (b
 	}
 
 	snapshotBranchName := fmt.Sprintf("snapshot-%s", *ws.LatestSnapshotID)
+	trunkBranchName := trunks.BranchName(ws.TrunkID)
 
 	var hasConflicts bool
 	checkConflicts := func(repo vcs.RepoGitWriter) error {
-		idx, err := repo.MergeBranches(snapshotBranchName, "sturdytrunk")
+		idx, err := repo.MergeBranches(snapshotBranchName, trunkBranchName)
 		if err != nil {
 			return fmt.Errorf("failed to merge branches: %w", err)
 		}
@@ -660,12 +1049,12 @@ func (s *Service) HasConflicts(ctx context.Context, ws *workspaces.Workspace) (b
 	}
 
 	checkConflictsOnView := func(repo vcs.RepoGitWriter) error {
-		// If sturdytrunk doesn't exist (such as when an empty repository has been imported), it's not conflicting
-		if _, err := repo.BranchCommitID("sturdytrunk"); err != nil {
+		// If the trunk doesn't exist (such as when an empty repository has been imported), it's not conflicting
+		if _, err := repo.BranchCommitID(trunkBranchName); err != nil {
 			return nil
 		}
 
-		if err := repo.FetchBranch(snapshotBranchName, "sturdytrunk"); err != nil {
+		if err := repo.FetchBranch(snapshotBranchName, trunkBranchName); err != nil {
 			return fmt.Errorf("failed to fetch branch: %w", err)
 		}
 
@@ -673,8 +1062,8 @@ func (s *Service) HasConflicts(ctx context.Context, ws *workspaces.Workspace) (b
 	}
 
 	checkConflictsOnTrunk := func(repo vcs.RepoGitWriter) error {
-		// If sturdytrunk doesn't exist (such as when an empty repository has been imported), it's not conflicting
-		if _, err := repo.BranchCommitID("sturdytrunk"); err != nil {
+		// If the trunk doesn't exist (such as when an empty repository has been imported), it's not conflicting
+		if _, err := repo.BranchCommitID(trunkBranchName); err != nil {
 			return nil
 		}
 
@@ -734,6 +1123,11 @@ func (s *Service) archive(ctx context.Context, ws *workspaces.Workspace, changeI
 		analytics.Property("workspace_id", ws.ID),
 	)
 
+	if err := s.eventsSenderV2.WorkspaceUpdated(ctx, eventsv2.Codebase(ws.CodebaseID), ws); err != nil {
+		s.logger.Error("failed to send workspace updated event", zap.Error(err))
+		// do not fail
+	}
+
 	if ws.ViewID == nil {
 		return nil
 	}
@@ -748,6 +1142,7 @@ func (s *Service) archive(ctx context.Context, ws *workspaces.Workspace, changeI
 	newWorkspace, err := s.Create(ctx, CreateWorkspaceRequest{
 		CodebaseID: ws.CodebaseID,
 		UserID:     ws.UserID,
+		TrunkID:    ws.TrunkID,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create new workspace: %w", err)
@@ -20,6 +20,8 @@ import (
 	service_snapshots "getsturdy.com/api/pkg/snapshots/service"
 	db_statuses "getsturdy.com/api/pkg/statuses/db"
 	db_suggestions "getsturdy.com/api/pkg/suggestions/db"
+	db_trunks "getsturdy.com/api/pkg/trunks/db"
+	service_trunks "getsturdy.com/api/pkg/trunks/service"
 	"getsturdy.com/api/pkg/users"
 	db_view "getsturdy.com/api/pkg/views/db"
 	service_view "getsturdy.com/api/pkg/views/service"
@@ -48,6 +50,7 @@ func testModule(t *testing.T) di.Module {
 		c.ImportWithForce(db_codebases.TestModule)
 		c.ImportWithForce(db_installations.TestModule)
 		c.ImportWithForce(db_statuses.TestModule)
+		c.ImportWithForce(db_trunks.TestModule)
 		c.ImportWithForce(module_queue.TestModule(t))
 		c.ImportWithForce(configuration.TestModule)
 		c.RegisterWithForce(logger.NewTest)
@@ -63,6 +66,7 @@ type testCase struct {
 	workspaceService *service_workspace.Service
 	codebaseService  *service_codebase.Service
 	viewService      *service_view.Service
+	trunksService    *service_trunks.Service
 	executorProvider executor.Provider
 
 	userID     users.ID
@@ -72,7 +76,7 @@ type testCase struct {
 func setup(t *testing.T) *testCase {
 	tc := &testCase{}
 	if !assert.NoError(t, di.Init(testModule(t)).To(
-		&tc.snapshotService, &tc.workspaceService, &tc.codebaseService, &tc.viewService, &tc.executorProvider,
+		&tc.snapshotService, &tc.workspaceService, &tc.codebaseService, &tc.viewService, &tc.trunksService, &tc.executorProvider,
 	)) {
 		t.FailNow()
 	}
@@ -116,6 +120,92 @@ func TestCreate_with_name(t *testing.T) {
 	assert.Equal(t, "test", *ws.Name)
 }
 
+func TestCreate_on_trunk(t *testing.T) {
+	tc := setup(t)
+
+	ctx := context.Background()
+
+	// the first workspace makes sure that the default trunk exists
+	_, err := tc.workspaceService.Create(ctx, service_workspace.CreateWorkspaceRequest{UserID: tc.userID, CodebaseID: tc.codebaseID})
+	assert.NoError(t, err)
+
+	trunk, err := tc.trunksService.Create(ctx, service_trunks.CreateRequest{
+		CodebaseID: tc.codebaseID,
+		UserID:     tc.userID,
+		Name:       "release-1.x",
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = tc.trunksService.Create(ctx, service_trunks.CreateRequest{
+		CodebaseID: tc.codebaseID,
+		UserID:     tc.userID,
+		Name:       "release-1.x",
+	})
+	assert.ErrorIs(t, err, service_trunks.ErrNameTaken)
+
+	ws, err := tc.workspaceService.Create(ctx, service_workspace.CreateWorkspaceRequest{
+		UserID:     tc.userID,
+		CodebaseID: tc.codebaseID,
+		TrunkID:    &trunk.ID,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	if assert.NotNil(t, ws.TrunkID) {
+		assert.Equal(t, trunk.ID, *ws.TrunkID)
+	}
+
+	upToDate, err := tc.workspaceService.UpToDateWithTrunk(ctx, ws)
+	assert.NoError(t, err)
+	assert.True(t, upToDate)
+}
+
+func TestCreate_stacked(t *testing.T) {
+	tc := setup(t)
+
+	ctx := context.Background()
+
+	parent, err := tc.workspaceService.Create(ctx, service_workspace.CreateWorkspaceRequest{UserID: tc.userID, CodebaseID: tc.codebaseID})
+	assert.NoError(t, err)
+
+	parentView, err := tc.viewService.Create(ctx, tc.userID, parent, nil, nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, tc.executorProvider.New().Write(writeFile("parent.txt", []byte("parent"))).ExecView(tc.codebaseID, parentView.ID, "make parent changes"))
+
+	child, err := tc.workspaceService.Create(ctx, service_workspace.CreateWorkspaceRequest{
+		UserID:            tc.userID,
+		CodebaseID:        tc.codebaseID,
+		ParentWorkspaceID: &parent.ID,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	if assert.NotNil(t, child.ParentWorkspaceID) {
+		assert.Equal(t, parent.ID, *child.ParentWorkspaceID)
+	}
+
+	children, err := tc.workspaceService.Children(ctx, parent)
+	assert.NoError(t, err)
+	if assert.Len(t, children, 1) {
+		assert.Equal(t, child.ID, children[0].ID)
+	}
+
+	childView, err := tc.viewService.Create(ctx, tc.userID, child, nil, nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, tc.executorProvider.New().Write(writeFile("child.txt", []byte("child"))).ExecView(tc.codebaseID, childView.ID, "make child changes"))
+
+	// the diff of the child is relative to the parent
+	diffs, _, err := tc.workspaceService.Diffs(ctx, child.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, diffs, 1) {
+		assert.Equal(t, "child.txt", diffs[0].NewName)
+	}
+}
+
 func TestWorkspace_SetSnapshot(t *testing.T) {
 	tc := setup(t)
 
@@ -158,6 +248,93 @@ func TestWorkspace_SetSnapshot(t *testing.T) {
 	assert.NoError(t, tc.executorProvider.New().Read(verifySnapshot(secondSnapshot)).ExecView(tc.codebaseID, vw.ID, "verify snapshot"))
 }
 
+func TestWorkspace_RestoreSnapshot(t *testing.T) {
+	tc := setup(t)
+
+	ctx := context.Background()
+
+	ws, err := tc.workspaceService.Create(ctx, service_workspace.CreateWorkspaceRequest{UserID: tc.userID, CodebaseID: tc.codebaseID})
+	assert.NoError(t, err)
+
+	vw, err := tc.viewService.Create(ctx, tc.userID, ws, nil, nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, tc.executorProvider.New().
+		Write(writeFile("a.txt", []byte("a"))).
+		Write(writeFile("b.txt", []byte("b"))).
+		ExecView(tc.codebaseID, vw.ID, "make some changes"))
+
+	firstSnapshot, err := tc.snapshotService.Snapshot(ctx, tc.codebaseID, ws.ID, snapshots.Action("testing"), service_snapshots.WithOnView(vw.ID))
+	assert.NoError(t, err)
+
+	assert.NoError(t, tc.executorProvider.New().
+		Write(writeFile("a.txt", []byte("a2"))).
+		Write(writeFile("b.txt", []byte("b2"))).
+		ExecView(tc.codebaseID, vw.ID, "make more changes"))
+
+	ws, err = tc.workspaceService.GetByID(ctx, ws.ID)
+	assert.NoError(t, err)
+
+	_, err = tc.workspaceService.RestoreSnapshot(ctx, ws, firstSnapshot)
+	assert.ErrorIs(t, err, service_workspace.ErrNothingToRestore)
+
+	restored, err := tc.workspaceService.RestoreSnapshot(ctx, ws, firstSnapshot, service_workspace.RestoreWithPaths("a.txt"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, snapshots.ActionSnapshotRestore, restored.Action)
+
+	wsAfterRestore, err := tc.workspaceService.GetByID(ctx, ws.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, restored.ID, *wsAfterRestore.LatestSnapshotID)
+
+	// only a.txt is restored, b.txt keeps the latest changes
+	assert.NoError(t, tc.executorProvider.New().Read(func(repo vcs.RepoReader) error {
+		a, err := os.ReadFile(path.Join(repo.Path(), "a.txt"))
+		assert.NoError(t, err)
+		assert.Equal(t, "a", string(a))
+		b, err := os.ReadFile(path.Join(repo.Path(), "b.txt"))
+		assert.NoError(t, err)
+		assert.Equal(t, "b2", string(b))
+		return nil
+	}).ExecView(tc.codebaseID, vw.ID, "verify restore"))
+
+	snaps, err := tc.snapshotService.ListByWorkspaceID(ctx, ws.ID, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, snaps, 3) {
+		assert.Equal(t, restored.ID, snaps[0].ID)
+		assert.Equal(t, snapshots.ActionPreSnapshotRestore, snaps[1].Action)
+		assert.Equal(t, firstSnapshot.ID, snaps[2].ID)
+	}
+
+	_, err = tc.workspaceService.RestoreSnapshot(ctx, wsAfterRestore, firstSnapshot, service_workspace.RestoreWithHunkIDs("unknown"))
+	assert.ErrorIs(t, err, service_workspace.ErrUnknownHunks)
+
+	// the hunk ids are from the diffs between the latest snapshot and the restored snapshot
+	diffs, err := tc.snapshotService.DiffBetween(ctx, restored, firstSnapshot)
+	assert.NoError(t, err)
+	if !assert.Len(t, diffs, 1) || !assert.Len(t, diffs[0].Hunks, 1) {
+		return
+	}
+	assert.Equal(t, "b.txt", diffs[0].NewName)
+
+	restoredHunk, err := tc.workspaceService.RestoreSnapshot(ctx, wsAfterRestore, firstSnapshot, service_workspace.RestoreWithHunkIDs(diffs[0].Hunks[0].ID))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	wsAfterHunkRestore, err := tc.workspaceService.GetByID(ctx, ws.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, restoredHunk.ID, *wsAfterHunkRestore.LatestSnapshotID)
+
+	assert.NoError(t, tc.executorProvider.New().Read(func(repo vcs.RepoReader) error {
+		b, err := os.ReadFile(path.Join(repo.Path(), "b.txt"))
+		assert.NoError(t, err)
+		assert.Equal(t, "b", string(b))
+		return nil
+	}).ExecView(tc.codebaseID, vw.ID, "verify hunk restore"))
+}
+
 func verifySnapshot(snapshot *snapshots.Snapshot) func(vcs.RepoReader) error {
 	return func(repo vcs.RepoReader) error {
 		head, err := repo.HeadCommit()
@@ -4,10 +4,10 @@ import (
 	"getsturdy.com/api/vcs"
 )
 
-func UpToDateWithTrunk(repo vcs.RepoGitReader, workspaceID string) (bool, error) {
-	trunkHEAD, err := repo.BranchCommitID("sturdytrunk")
+func UpToDateWithTrunk(repo vcs.RepoGitReader, workspaceID, trunkBranchName string) (bool, error) {
+	trunkHEAD, err := repo.BranchCommitID(trunkBranchName)
 	if err != nil {
-		// If sturdytrunk doesn't exist (such as when an empty repository has been imported), treat it as up to date
+		// If the trunk doesn't exist (such as when an empty repository has been imported), treat it as up to date
 		return true, nil
 	}
 	return repo.BranchHasCommit(workspaceID, trunkHEAD)
@@ -8,6 +8,7 @@ import (
 	"getsturdy.com/api/pkg/changes"
 	"getsturdy.com/api/pkg/codebases"
 	"getsturdy.com/api/pkg/snapshots"
+	"getsturdy.com/api/pkg/trunks"
 	"getsturdy.com/api/pkg/users"
 
 	"github.com/microcosm-cc/bluemonday"
@@ -44,6 +45,14 @@ type Workspace struct {
 
 	// ChangeID is the last change id that was landed from this workspace.
 	ChangeID *changes.ID `db:"change_id" json:"-"`
+
+	// ParentWorkspaceID is set if the workspace is stacked on top of another workspace. A stacked workspace is based
+	// on the latest snapshot of its parent, and is rebased when the parent changes or lands.
+	ParentWorkspaceID *string `db:"parent_workspace_id" json:"-"`
+
+	// TrunkID is the trunk that the workspace is based on, and lands to. If nil, the workspace is on the default trunk
+	// of the codebase.
+	TrunkID *trunks.ID `db:"trunk_id" json:"-"`
 }
 
 func (w *Workspace) SetSnapshot(snapshot *snapshots.Snapshot) {
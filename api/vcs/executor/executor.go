@@ -1,6 +1,7 @@
 package executor
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/rand"
@@ -54,6 +55,14 @@ type Executor interface {
 	// ExecTemporaryView creates a view for the given codebase, cloning it from the trunk,
 	// executes all the scheduled functions, and then deletes the view.
 	ExecTemporaryView(codebaseID codebases.ID, actionName string) error
+
+	// ExecViewContext is like ExecView, but stops waiting for the repository locks, and stops before running the next
+	// scheduled function, when the context is done. A function that has already started is not interrupted.
+	ExecViewContext(ctx context.Context, codebaseID codebases.ID, viewID, actionName string) error
+	// ExecTrunkContext is like ExecTrunk, but honours the context in the same way as ExecViewContext.
+	ExecTrunkContext(ctx context.Context, codebaseID codebases.ID, actionName string) error
+	// ExecTemporaryViewContext is like ExecTemporaryView, but honours the context in the same way as ExecViewContext.
+	ExecTemporaryViewContext(ctx context.Context, codebaseID codebases.ID, actionName string) error
 }
 
 type executeFunc struct {
@@ -122,6 +131,9 @@ type executor struct {
 
 	allowRebasing bool
 
+	// locks that are held for longer than this are logged
+	longHeldLockThreshold time.Duration
+
 	logger           *zap.Logger
 	repoProvider     provider.RepoProvider
 	locks            *locker
@@ -135,10 +147,11 @@ func newExecutor(
 	minTmpBufferSize int,
 ) *executor {
 	return &executor{
-		logger:           logger,
-		repoProvider:     repoProvider,
-		locks:            locks,
-		minTmpBufferSize: minTmpBufferSize,
+		longHeldLockThreshold: defaultLongHeldLockThreshold,
+		logger:                logger,
+		repoProvider:          repoProvider,
+		locks:                 locks,
+		minTmpBufferSize:      minTmpBufferSize,
 	}
 }
 
@@ -328,6 +341,10 @@ func (e *executor) temporaryViewMarkNotUsing(codebaseID codebases.ID, viewID str
 }
 
 func (e *executor) ExecTemporaryView(codebaseID codebases.ID, actionName string) error {
+	return e.ExecTemporaryViewContext(context.Background(), codebaseID, actionName)
+}
+
+func (e *executor) ExecTemporaryViewContext(ctx context.Context, codebaseID codebases.ID, actionName string) error {
 	e.allowRebasing = true
 
 	viewID, err := e.getTemporaryViewID(codebaseID)
@@ -387,18 +404,26 @@ func (e *executor) ExecTemporaryView(codebaseID codebases.ID, actionName string)
 				return nil
 			}
 		},
-	}).ExecView(codebaseID, viewID, actionName)
+	}).ExecViewContext(ctx, codebaseID, viewID, actionName)
 }
 
 func (e *executor) ExecView(codebaseID codebases.ID, viewID, actionName string) error {
-	return e.exec(codebaseID, &viewID, actionName)
+	return e.exec(context.Background(), codebaseID, &viewID, actionName)
 }
 
 func (e *executor) ExecTrunk(codebaseID codebases.ID, actionName string) error {
-	return e.exec(codebaseID, nil, actionName)
+	return e.exec(context.Background(), codebaseID, nil, actionName)
+}
+
+func (e *executor) ExecViewContext(ctx context.Context, codebaseID codebases.ID, viewID, actionName string) error {
+	return e.exec(ctx, codebaseID, &viewID, actionName)
+}
+
+func (e *executor) ExecTrunkContext(ctx context.Context, codebaseID codebases.ID, actionName string) error {
+	return e.exec(ctx, codebaseID, nil, actionName)
 }
 
-func (e *executor) exec(codebaseID codebases.ID, viewID *string, actionName string) (err error) {
+func (e *executor) exec(ctx context.Context, codebaseID codebases.ID, viewID *string, actionName string) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("recovered call in vcs executor: %v\nStacktrace: %s", r, string(debug.Stack()))
@@ -433,44 +458,44 @@ func (e *executor) exec(codebaseID codebases.ID, viewID *string, actionName stri
 	}
 
 	if e.writeLock {
-		lock := e.locks.Get(codebaseID, viewID)
-		if err := lock.Lock(); err != nil {
-			return fmt.Errorf("failed to acquire write lock: %w", err)
+		unlock, lockErr := e.acquireLock(ctx, logger, codebaseID, viewID, lockKindWrite)
+		if lockErr != nil {
+			return fmt.Errorf("failed to acquire write lock: %w", lockErr)
 		}
 		defer func() {
-			if unlockErr := lock.Unlock(); unlockErr != nil {
+			if unlockErr := unlock(); unlockErr != nil {
 				err = fmt.Errorf("failed to release write lock: %w", unlockErr)
 			}
 		}()
 	} else if e.readLock {
-		lock := e.locks.Get(codebaseID, viewID)
-		if err := lock.RLock(); err != nil {
-			return fmt.Errorf("failed to acquire read lock: %w", err)
+		unlock, lockErr := e.acquireLock(ctx, logger, codebaseID, viewID, lockKindRead)
+		if lockErr != nil {
+			return fmt.Errorf("failed to acquire read lock: %w", lockErr)
 		}
 		defer func() {
-			if unlockErr := lock.RUnlock(); unlockErr != nil {
+			if unlockErr := unlock(); unlockErr != nil {
 				err = fmt.Errorf("failed to release read lock: %w", unlockErr)
 			}
 		}()
 	}
 
 	if e.inMemoryWriteLock {
-		lock := e.locks.GetInMemory(codebaseID, viewID)
-		if err := lock.Lock(); err != nil {
-			return fmt.Errorf("failed to acquire in-memory write lock: %w", err)
+		unlock, lockErr := e.acquireLock(ctx, logger, codebaseID, viewID, lockKindInMemoryWrite)
+		if lockErr != nil {
+			return fmt.Errorf("failed to acquire in-memory write lock: %w", lockErr)
 		}
 		defer func() {
-			if unlockErr := lock.Unlock(); unlockErr != nil {
+			if unlockErr := unlock(); unlockErr != nil {
 				err = fmt.Errorf("failed to release in-memory write lock: %w", unlockErr)
 			}
 		}()
 	} else if e.inMemoryReadLock {
-		lock := e.locks.GetInMemory(codebaseID, viewID)
-		if err := lock.RLock(); err != nil {
-			return fmt.Errorf("failed to acquire in-memory read lock: %w", err)
+		unlock, lockErr := e.acquireLock(ctx, logger, codebaseID, viewID, lockKindInMemoryRead)
+		if lockErr != nil {
+			return fmt.Errorf("failed to acquire in-memory read lock: %w", lockErr)
 		}
 		defer func() {
-			if unlockErr := lock.RUnlock(); unlockErr != nil {
+			if unlockErr := unlock(); unlockErr != nil {
 				err = fmt.Errorf("failed to release in-memory read lock: %w", unlockErr)
 			}
 		}()
@@ -482,6 +507,9 @@ func (e *executor) exec(codebaseID codebases.ID, viewID *string, actionName stri
 
 	onceRepo := openOnce(e.repoProvider, codebaseID, viewID)
 	for _, fn := range e.funs {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("git executor stopped: %w", err)
+		}
 		if err := fn.Exec(onceRepo); err != nil {
 			return err
 		}
@@ -490,6 +518,68 @@ func (e *executor) exec(codebaseID codebases.ID, viewID *string, actionName stri
 	return nil
 }
 
+type lockKind string
+
+const (
+	lockKindWrite         lockKind = "write"
+	lockKindRead          lockKind = "read"
+	lockKindInMemoryWrite lockKind = "inmemory_write"
+	lockKindInMemoryRead  lockKind = "inmemory_read"
+)
+
+// defaultLongHeldLockThreshold is the duration after which a held lock is logged as long-held.
+const defaultLongHeldLockThreshold = 10 * time.Second
+
+// acquireLock acquires the lock of kind _lk_ for the repository, and returns a function that releases it. The time spent
+// waiting for, and holding the lock is recorded. A warning is logged as soon as the lock has been held for longer than
+// longHeldLockThreshold.
+func (e *executor) acquireLock(ctx context.Context, logger *zap.Logger, codebaseID codebases.ID, viewID *string, lk lockKind) (func() error, error) {
+	var l lock
+	if lk == lockKindInMemoryWrite || lk == lockKindInMemoryRead {
+		l = e.locks.GetInMemory(codebaseID, viewID)
+	} else {
+		l = e.locks.Get(codebaseID, viewID)
+	}
+
+	labels := prometheus.Labels{"codebase_id": codebaseID.String(), "lock": string(lk)}
+
+	waitT0 := time.Now()
+	lockFn, unlock := l.RLockContext, l.RUnlock
+	if lk == lockKindWrite || lk == lockKindInMemoryWrite {
+		lockFn, unlock = l.LockContext, l.Unlock
+	}
+	err := lockFn(ctx)
+	lockWaitMillis.With(labels).Observe(float64(time.Since(waitT0).Milliseconds()))
+	if err != nil {
+		return nil, err
+	}
+
+	holdT0 := time.Now()
+	waited := holdT0.Sub(waitT0)
+
+	// warn while the lock is still held, so that a lock that is never released is noticed too
+	longHeld := time.AfterFunc(e.longHeldLockThreshold, func() {
+		logger.Warn("git executor is holding lock for a long time",
+			zap.String("lock", string(lk)),
+			zap.Duration("threshold", e.longHeldLockThreshold),
+			zap.Duration("waited", waited),
+		)
+	})
+
+	return func() error {
+		held := time.Since(holdT0)
+		lockHoldMillis.With(labels).Observe(float64(held.Milliseconds()))
+		if !longHeld.Stop() {
+			logger.Warn("git executor released long-held lock",
+				zap.String("lock", string(lk)),
+				zap.Duration("held", held),
+				zap.Duration("waited", waited),
+			)
+		}
+		return unlock()
+	}, nil
+}
+
 type onceRepo struct {
 	codebaseID   codebases.ID
 	viewID       *string
@@ -522,19 +612,31 @@ func (or *onceRepo) Get() (vcs.RepoWriter, error) {
 }
 
 var (
+	millisBuckets = []float64{
+		.01, .025, .05,
+		.1, .25, .5,
+		1, 2.5, 5,
+		10, 25, 50,
+		100, 250, 500,
+		1000, 2500, 5000,
+		10000, 25000, 50000,
+		100000, 250000, 500000,
+	}
+
 	meteredMethod = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{Name: "sturdy_executor_call_millis",
-			Buckets: []float64{
-				.01, .025, .05,
-				.1, .25, .5,
-				1, 2.5, 5,
-				10, 25, 50,
-				100, 250, 500,
-				1000, 2500, 5000,
-				10000, 25000, 50000,
-				100000, 250000, 500000,
-			},
+			Buckets: millisBuckets,
 		}, []string{"action"})
+
+	lockWaitMillis = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{Name: "sturdy_executor_lock_wait_millis",
+			Buckets: millisBuckets,
+		}, []string{"codebase_id", "lock"})
+
+	lockHoldMillis = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{Name: "sturdy_executor_lock_hold_millis",
+			Buckets: millisBuckets,
+		}, []string{"codebase_id", "lock"})
 )
 
 func getMeterFunc(action string) func() {
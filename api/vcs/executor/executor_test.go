@@ -1,14 +1,17 @@
 package executor
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 
 	"getsturdy.com/api/pkg/codebases"
 	vcs_codebases "getsturdy.com/api/pkg/codebases/vcs"
@@ -132,3 +135,96 @@ func TestExecutor_AllowRebasingState(t *testing.T) {
 	}).ExecView("cb", "vw", "tryToOpenWithAllowed")
 	assert.NoError(t, err)
 }
+
+func TestExecutor_ExecTrunkContext_cancelled_while_waiting_for_lock(t *testing.T) {
+	exec := NewProvider(zap.NewNop(), testutil.TestingRepoProvider(t))
+
+	codebaseID := codebases.ID("cb")
+
+	assert.NoError(t, exec.New().
+		Schedule(vcs_codebases.Create(codebaseID)).
+		ExecTrunk(codebaseID, "createTrunk"), "failed to create trunk")
+
+	locked := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		assert.NoError(t, exec.New().GitWrite(func(vcs.RepoGitWriter) error {
+			close(locked)
+			<-release
+			return nil
+		}).ExecTrunk(codebaseID, "holdLock"))
+		close(done)
+	}()
+	<-locked
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	var called bool
+	err := exec.New().GitWrite(func(vcs.RepoGitWriter) error {
+		called = true
+		return nil
+	}).ExecTrunkContext(ctx, codebaseID, "waitForLock")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.False(t, called)
+
+	close(release)
+	<-done
+
+	// the lock is available again
+	assert.NoError(t, exec.New().GitWrite(func(vcs.RepoGitWriter) error {
+		return nil
+	}).ExecTrunk(codebaseID, "afterCancel"))
+}
+
+func TestExecutor_ExecTrunkContext_stops_between_functions(t *testing.T) {
+	exec := NewProvider(zap.NewNop(), testutil.TestingRepoProvider(t))
+
+	codebaseID := codebases.ID("cb")
+
+	assert.NoError(t, exec.New().
+		Schedule(vcs_codebases.Create(codebaseID)).
+		ExecTrunk(codebaseID, "createTrunk"), "failed to create trunk")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var secondCalled bool
+	err := exec.New().
+		GitRead(func(vcs.RepoGitReader) error {
+			cancel()
+			return nil
+		}).
+		GitRead(func(vcs.RepoGitReader) error {
+			secondCalled = true
+			return nil
+		}).
+		ExecTrunkContext(ctx, codebaseID, "cancelled")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, secondCalled)
+}
+
+func TestExecutor_warns_while_lock_is_held(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	exec := NewProvider(zap.New(core), testutil.TestingRepoProvider(t))
+
+	codebaseID := codebases.ID("cb")
+
+	assert.NoError(t, exec.New().
+		Schedule(vcs_codebases.Create(codebaseID)).
+		ExecTrunk(codebaseID, "createTrunk"), "failed to create trunk")
+
+	e := exec.New().(*executor)
+	e.longHeldLockThreshold = 10 * time.Millisecond
+
+	assert.NoError(t, e.GitWrite(func(vcs.RepoGitWriter) error {
+		// the warning is logged before the lock is released
+		assert.Eventually(t, func() bool {
+			return logs.FilterMessage("git executor is holding lock for a long time").Len() == 1
+		}, time.Second, time.Millisecond)
+		return nil
+	}).ExecTrunk(codebaseID, "holdLock"))
+
+	assert.Equal(t, 1, logs.FilterMessage("git executor released long-held lock").Len())
+}
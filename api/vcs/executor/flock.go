@@ -3,13 +3,19 @@
 package executor
 
 import (
+	"context"
 	"errors"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/gofrs/flock"
 )
 
+// fileLockRetryDelay is how often a file lock that is held by another process is retried, when waiting for it with a
+// context.
+const fileLockRetryDelay = 10 * time.Millisecond
+
 type FileLock struct {
 	mu      sync.RWMutex // lock within this process
 	lock    *flock.Flock // lock between processes
@@ -35,6 +41,21 @@ func (fl *FileLock) Lock() error {
 	return nil
 }
 
+// LockContext is like Lock, but gives up waiting for the lock when the context is done.
+func (fl *FileLock) LockContext(ctx context.Context) error {
+	if err := lockContext(ctx, fl.mu.Lock, fl.mu.Unlock); err != nil {
+		return err
+	}
+	if _, err := fl.lock.TryLockContext(ctx, fileLockRetryDelay); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		fl.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
 func (fl *FileLock) Unlock() error {
 	defer fl.mu.Unlock()
 	if err := fl.lock.Unlock(); err != nil {
@@ -61,6 +82,31 @@ func (fl *FileLock) RLock() error {
 	return nil
 }
 
+// RLockContext is like RLock, but gives up waiting for the lock when the context is done.
+func (fl *FileLock) RLockContext(ctx context.Context) error {
+	fl.countMx.Lock()
+	fl.count++
+	fl.countMx.Unlock()
+
+	if err := lockContext(ctx, fl.mu.RLock, fl.mu.RUnlock); err != nil {
+		fl.countMx.Lock()
+		fl.count--
+		fl.countMx.Unlock()
+		return err
+	}
+	if _, err := fl.lock.TryRLockContext(ctx, fileLockRetryDelay); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		fl.countMx.Lock()
+		fl.count--
+		fl.countMx.Unlock()
+		fl.mu.RUnlock()
+		return err
+	}
+	return nil
+}
+
 func (fl *FileLock) RUnlock() error {
 	fl.countMx.Lock()
 	fl.count--
@@ -1,9 +1,12 @@
 package executor
 
 import (
+	"context"
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestInProcess_RLockNonBlocksRLock(t *testing.T) {
@@ -149,3 +152,30 @@ func TestInProcess_LockBlocksRLock(t *testing.T) {
 		t.Fatal("lock is not blocking rlock")
 	}
 }
+
+func TestInProcess_LockContextGivesUp(t *testing.T) {
+	dir := t.TempDir()
+	lock := New(filepath.Join(dir, ".lock"))
+
+	assert.NoError(t, lock.Lock())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, lock.LockContext(ctx), context.DeadlineExceeded)
+	assert.ErrorIs(t, lock.RLockContext(ctx), context.DeadlineExceeded)
+
+	assert.NoError(t, lock.Unlock())
+
+	// the lock is not left locked by the cancelled attempts
+	secondLocked := make(chan struct{})
+	go func() {
+		assert.NoError(t, lock.LockContext(context.Background()))
+		close(secondLocked)
+	}()
+	select {
+	case <-secondLocked:
+		// success, cancelled attempts released the lock
+	case <-time.Tick(100 * time.Millisecond):
+		t.Fatal("cancelled lock attempts are blocking lock")
+	}
+}
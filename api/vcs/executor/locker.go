@@ -1,6 +1,7 @@
 package executor
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"sync"
@@ -23,6 +24,10 @@ type lock interface {
 	Unlock() error
 	RLock() error
 	RUnlock() error
+
+	// LockContext and RLockContext are like Lock and RLock, but give up waiting for the lock when the context is done.
+	LockContext(context.Context) error
+	RLockContext(context.Context) error
 }
 
 type mutexLock struct {
@@ -49,6 +54,39 @@ func (ml *mutexLock) RUnlock() error {
 	return nil
 }
 
+func (ml *mutexLock) LockContext(ctx context.Context) error {
+	return lockContext(ctx, ml.mu.Lock, ml.mu.Unlock)
+}
+
+func (ml *mutexLock) RLockContext(ctx context.Context) error {
+	return lockContext(ctx, ml.mu.RLock, ml.mu.RUnlock)
+}
+
+// lockContext waits for _lock_ until it's acquired, or until the context is done. If the context is done first, the
+// lock is released by _unlock_ as soon as it's acquired.
+func lockContext(ctx context.Context, lock, unlock func()) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		lock()
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		return nil
+	case <-ctx.Done():
+		go func() {
+			<-acquired
+			unlock()
+		}()
+		return ctx.Err()
+	}
+}
+
 func newLocker(provider provider.RepoProvider) *locker {
 	return &locker{
 		provider: provider,
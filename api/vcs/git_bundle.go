@@ -0,0 +1,55 @@
+package vcs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// CreateBundle writes a git bundle with the given branches, and all tags of the repository, to w.
+func (r *repository) CreateBundle(w io.Writer, branchNames ...string) error {
+	defer getMeterFunc("CreateBundle")()
+
+	refs := make([]string, 0, len(branchNames))
+	for _, name := range branchNames {
+		refs = append(refs, "refs/heads/"+name)
+	}
+
+	// the refs are passed on stdin, as a codebase can have more branches than fit in the arguments
+	cmd := exec.Command("git", "bundle", "create", "-", "--tags", "--stdin")
+	errLog := &bytes.Buffer{}
+	cmd.Dir = r.path
+	cmd.Stdin = strings.NewReader(strings.Join(refs, "\n") + "\n")
+	cmd.Stdout = w
+	cmd.Stderr = errLog
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run git bundle create: %w, %s", err, errLog.String())
+	}
+	return nil
+}
+
+// CreateBareRepoFromBundle creates a new bare repository at path, with all branches and tags of the bundle at
+// bundlePath. HEAD of the new repository points to headBranchName.
+func CreateBareRepoFromBundle(bundlePath, path, headBranchName string) (*repository, error) {
+	defer getMeterFunc("CreateBareRepoFromBundle")()
+
+	r, err := CreateEmptyBareRepo(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create repository: %w", err)
+	}
+
+	cmd := exec.Command("git", "fetch", bundlePath, "refs/heads/*:refs/heads/*", "refs/tags/*:refs/tags/*")
+	errLog := &bytes.Buffer{}
+	cmd.Dir = path
+	cmd.Stderr = errLog
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to fetch from bundle: %w, %s", err, errLog.String())
+	}
+
+	if err := r.SetDefaultBranch(headBranchName); err != nil {
+		return nil, fmt.Errorf("failed to set default branch: %w", err)
+	}
+	return r, nil
+}
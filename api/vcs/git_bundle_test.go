@@ -0,0 +1,44 @@
+package vcs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	git "github.com/libgit2/git2go/v33"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBundle(t *testing.T) {
+	repo, err := CreateBareRepoWithRootCommit(t.TempDir())
+	assert.NoError(t, err)
+
+	commitID, err := repo.CreateCommitWithFiles([]FileContents{
+		{"README.md", []byte("# Hello World!")},
+	}, "sturdytrunk")
+	assert.NoError(t, err)
+	assert.NoError(t, repo.CreateNewBranchAt("snapshot-1", commitID))
+	assert.NoError(t, repo.CreateNewBranchAt("snapshot-2", commitID))
+	assert.NoError(t, repo.CreateTag("v1.0.0", commitID, git.Signature{Name: "Test", Email: "test@getsturdy.com"}, "First release"))
+
+	bundlePath := filepath.Join(t.TempDir(), "trunk.bundle")
+	f, err := os.Create(bundlePath)
+	assert.NoError(t, err)
+	assert.NoError(t, repo.CreateBundle(f, "sturdytrunk", "snapshot-1"))
+	assert.NoError(t, f.Close())
+
+	imported, err := CreateBareRepoFromBundle(bundlePath, filepath.Join(t.TempDir(), "imported"), "sturdytrunk")
+	assert.NoError(t, err)
+
+	head, err := imported.HeadBranch()
+	assert.NoError(t, err)
+	assert.Equal(t, "sturdytrunk", head)
+
+	branches, err := imported.Branches()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"sturdytrunk", "snapshot-1"}, branches)
+
+	taggedCommitID, err := imported.TagCommitID("v1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, commitID, taggedCommitID)
+}
@@ -216,3 +216,47 @@ func (r *repository) CreateCommitWithFiles(files []FileContents, newBranchName s
 
 	return newCommit.String(), nil
 }
+
+// SquashCommits creates a new commit on newBranchName, that has the tree of headCommitID and parentCommitID as its only
+// parent. All changes made between parentCommitID and headCommitID are squashed into the new commit.
+func (r *repository) SquashCommits(newBranchName, headCommitID, parentCommitID string, signature git.Signature, message string) (string, error) {
+	defer getMeterFunc("SquashCommits")()
+
+	headOid, err := git.NewOid(headCommitID)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse head commit: %w", err)
+	}
+
+	headCommit, err := r.r.LookupCommit(headOid)
+	if err != nil {
+		return "", fmt.Errorf("failed to lookup head commit: %w", err)
+	}
+	defer headCommit.Free()
+
+	tree, err := headCommit.Tree()
+	if err != nil {
+		return "", fmt.Errorf("failed to get tree: %w", err)
+	}
+	defer tree.Free()
+
+	parentOid, err := git.NewOid(parentCommitID)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse parent commit: %w", err)
+	}
+
+	parentCommit, err := r.r.LookupCommit(parentOid)
+	if err != nil {
+		return "", fmt.Errorf("failed to lookup parent commit: %w", err)
+	}
+	defer parentCommit.Free()
+
+	// delete branch if already exists
+	_ = r.DeleteBranch(newBranchName)
+
+	newCommit, err := r.r.CreateCommit("refs/heads/"+newBranchName, &signature, &signature, message, tree, parentCommit)
+	if err != nil {
+		return "", fmt.Errorf("failed to create commit: %w", err)
+	}
+
+	return newCommit.String(), nil
+}
@@ -3,6 +3,7 @@ package vcs
 import (
 	"testing"
 
+	git "github.com/libgit2/git2go/v33"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -21,3 +22,43 @@ func TestCreateCommitWithFiles(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "# Hello World!", string(contents))
 }
+
+func TestSquashCommits(t *testing.T) {
+	repoPath := t.TempDir()
+
+	repo, err := CreateBareRepoWithRootCommit(repoPath)
+	assert.NoError(t, err)
+
+	rootCommit, err := repo.HeadCommit()
+	assert.NoError(t, err)
+	rootCommitID := rootCommit.Id().String()
+
+	firstCommitID, err := repo.CreateCommitWithFiles([]FileContents{
+		{"README.md", []byte("# Hello World!")},
+	}, "sturdytrunk")
+	assert.NoError(t, err)
+
+	secondCommitID, err := repo.CreateCommitWithFiles([]FileContents{
+		{"main.go", []byte("package main")},
+	}, "sturdytrunk")
+	assert.NoError(t, err)
+
+	squashedCommitID, err := repo.SquashCommits("squashed", secondCommitID, rootCommitID, git.Signature{Name: "Test", Email: "test@getsturdy.com"}, "Squashed")
+	assert.NoError(t, err)
+
+	parents, err := repo.GetCommitParents(squashedCommitID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{rootCommitID}, parents)
+
+	branchCommitID, err := repo.BranchCommitID("squashed")
+	assert.NoError(t, err)
+	assert.Equal(t, squashedCommitID, branchCommitID)
+
+	for path, expected := range map[string]string{"README.md": "# Hello World!", "main.go": "package main"} {
+		contents, err := repo.FileContentsAtCommit(squashedCommitID, path)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(contents))
+	}
+
+	assert.NotEqual(t, firstCommitID, squashedCommitID)
+}
@@ -0,0 +1,51 @@
+package vcs
+
+import (
+	"fmt"
+
+	git "github.com/libgit2/git2go/v33"
+)
+
+// CreateTag creates an annotated tag called _name_ pointing at _commitSha_.
+func (r *repository) CreateTag(name, commitSha string, tagger git.Signature, message string) error {
+	defer getMeterFunc("CreateTag")()
+	id, err := git.NewOid(commitSha)
+	if err != nil {
+		return fmt.Errorf("failed to parse commit ID: %w", err)
+	}
+	commit, err := r.r.LookupCommit(id)
+	if err != nil {
+		return fmt.Errorf("failed to find commit: %w", err)
+	}
+	defer commit.Free()
+	if _, err := r.r.Tags.Create(name, commit, &tagger, message); err != nil {
+		return fmt.Errorf("failed to create tag %s: %w", name, err)
+	}
+	return nil
+}
+
+func (r *repository) TagCommitID(name string) (string, error) {
+	defer getMeterFunc("TagCommitID")()
+	ref, err := r.r.References.Lookup("refs/tags/" + name)
+	if err != nil {
+		return "", fmt.Errorf("failed to find tag %s: %w", name, err)
+	}
+	defer ref.Free()
+	obj, err := ref.Peel(git.ObjectCommit)
+	if err != nil {
+		return "", fmt.Errorf("failed to peel tag %s: %w", name, err)
+	}
+	defer obj.Free()
+	return obj.Id().String(), nil
+}
+
+func (r *repository) DeleteTag(name string) error {
+	defer getMeterFunc("DeleteTag")()
+	if err := r.r.Tags.Remove(name); err != nil {
+		if isGitNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to delete tag %s: %w", name, err)
+	}
+	return nil
+}
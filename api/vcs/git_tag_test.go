@@ -0,0 +1,38 @@
+package vcs
+
+import (
+	"testing"
+
+	git "github.com/libgit2/git2go/v33"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTag(t *testing.T) {
+	repoPath := t.TempDir()
+
+	repo, err := CreateBareRepoWithRootCommit(repoPath)
+	assert.NoError(t, err)
+
+	commitID, err := repo.CreateCommitWithFiles([]FileContents{
+		{"README.md", []byte("# Hello World!")},
+	}, "sturdytrunk")
+	assert.NoError(t, err)
+
+	err = repo.CreateTag("v1.0.0", commitID, git.Signature{Name: "Test", Email: "test@getsturdy.com"}, "First release")
+	assert.NoError(t, err)
+
+	taggedCommitID, err := repo.TagCommitID("v1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, commitID, taggedCommitID)
+
+	// tags can not be moved
+	err = repo.CreateTag("v1.0.0", commitID, git.Signature{Name: "Test", Email: "test@getsturdy.com"}, "First release")
+	assert.Error(t, err)
+
+	assert.NoError(t, repo.DeleteTag("v1.0.0"))
+	_, err = repo.TagCommitID("v1.0.0")
+	assert.Error(t, err)
+
+	// deleting a missing tag is a noop
+	assert.NoError(t, repo.DeleteTag("v1.0.0"))
+}
@@ -2,6 +2,7 @@ package vcs
 
 import (
 	"context"
+	"io"
 
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing/transport"
@@ -33,6 +34,7 @@ type RepoGitReader interface {
 	Branches() ([]string, error)
 
 	BranchCommitID(branchName string) (string, error)
+	TagCommitID(name string) (string, error)
 
 	GetCommitParents(commitID string) ([]string, error)
 	CommitMessage(id string) (author *git.Signature, message string, err error)
@@ -48,6 +50,8 @@ type RepoGitReader interface {
 	LogHead(limit int) ([]*LogEntry, error)
 	LogBranch(branchName string, limit int) ([]*LogEntry, error)
 
+	CreateBundle(w io.Writer, branchNames ...string) error
+
 	OpenRebase() (*SturdyRebase, error)
 }
 
@@ -64,6 +68,10 @@ type RepoGitWriter interface {
 	CreateNewBranchOnHEAD(name string) error
 	CreateNewBranchAt(name string, targetSha string) error
 	CreateNewCommitBasedOnCommit(newBranchName string, existingCommitID string, signature git.Signature, message string) (string, error)
+	SquashCommits(newBranchName, headCommitID, parentCommitID string, signature git.Signature, message string) (string, error)
+
+	CreateTag(name, commitSha string, tagger git.Signature, message string) error
+	DeleteTag(name string) error
 
 	CleanStaged() error
 	Push(logger *zap.Logger, branchName string) error
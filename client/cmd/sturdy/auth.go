@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -15,12 +16,31 @@ import (
 	"github.com/dgrijalva/jwt-go"
 )
 
-func auth(conf *config.Config, configPath string) {
-	url := "https://getsturdy.com/install/token"
-	fmt.Printf("👉 Open this page in your browser: %s\n", url)
+// authAll authenticates with the servers of all profiles, or only with the server of _profileName_ if it's set.
+func authAll(conf *config.Config, configPath, profileName string) {
+	if profileName != "" {
+		profile := conf.Profile(profileName)
+		if profile == nil {
+			fmt.Printf("⚠️  There is no profile named '%s'. Add it with 'sturdy profile add'\n", profileName)
+			os.Exit(1)
+		}
+		auth(profile, configPath)
+		return
+	}
+
+	for _, profile := range conf.Profiles {
+		if len(conf.Profiles) > 1 {
+			fmt.Printf("\n🌐 Authenticating with %s (%s)\n", profile.Name, profile.APIRemote)
+		}
+		auth(profile, configPath)
+	}
+}
+
+func auth(profile *config.Profile, configPath string) {
+	fmt.Printf("👉 Open this page in your browser: %s\n", tokenURL(profile))
 	fmt.Print("🔑 Paste the code that the browser gave you, and press enter")
 
-	code, err := readUntilValidTokenInput(conf, os.Stdin, checkToken)
+	code, err := readUntilValidTokenInput(profile, os.Stdin, checkToken)
 	if errors.Is(err, io.EOF) {
 		os.Exit(1)
 	}
@@ -30,16 +50,16 @@ func auth(conf *config.Config, configPath string) {
 		os.Exit(1)
 	}
 
-	err = config.SetAuth(configPath, code)
+	err = config.SetAuth(configPath, profile.Name, code)
 	if err != nil {
 		fmt.Println("Failed to update config")
 		fmt.Println(err)
 		return
 	}
-	conf.Auth = code
+	profile.Auth = code
 
 	// Create a new API client
-	apiClient := api.NewHttpApiClient(conf)
+	apiClient := api.NewHttpApiClient(profile)
 	user, err := apiClient.GetUser()
 	if err != nil {
 		fmt.Println("Something went wrong")
@@ -51,7 +71,19 @@ func auth(conf *config.Config, configPath string) {
 	fmt.Printf("The configuration has been saved to %s\n", configPath)
 }
 
-func readUntilValidTokenInput(conf *config.Config, termReadWriter io.ReadWriter, validateFunc validateTokenFunc) (string, error) {
+// tokenURL returns the page of the web app that gives the user a token. The web app is served from the host of the API,
+// without the "api." prefix or the "/api" path.
+func tokenURL(profile *config.Profile) string {
+	u, err := url.Parse(profile.APIRemote)
+	if err != nil || u.Host == "" {
+		return "https://getsturdy.com/install/token"
+	}
+	u.Host = strings.TrimPrefix(u.Host, "api.")
+	u.Path = strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), "/api") + "/install/token"
+	return u.String()
+}
+
+func readUntilValidTokenInput(profile *config.Profile, termReadWriter io.ReadWriter, validateFunc validateTokenFunc) (string, error) {
 	fmt.Println()
 
 	for attempt := 0; attempt < 30; attempt++ {
@@ -69,7 +101,7 @@ func readUntilValidTokenInput(conf *config.Config, termReadWriter io.ReadWriter,
 		}
 
 		input := strings.TrimSpace(codeBytes)
-		err = validateFunc(conf, input)
+		err = validateFunc(profile, input)
 		if err != nil {
 			fmt.Println("❌ Invalid token. Please try pasting it again.")
 			continue
@@ -85,12 +117,12 @@ func readUntilValidTokenInput(conf *config.Config, termReadWriter io.ReadWriter,
 
 var errOutOfTries = fmt.Errorf("❌ Maximum attempts reached, aborting!")
 
-type validateTokenFunc func(conf *config.Config, checkToken string) error
+type validateTokenFunc func(profile *config.Profile, checkToken string) error
 
-func checkToken(conf *config.Config, checkToken string) error {
-	copyConf := *conf
-	copyConf.Auth = checkToken
-	apiClient := api.NewHttpApiClient(&copyConf)
+func checkToken(profile *config.Profile, checkToken string) error {
+	copyProfile := *profile
+	copyProfile.Auth = checkToken
+	apiClient := api.NewHttpApiClient(&copyProfile)
 	_, err := apiClient.GetUser()
 	if err != nil {
 		return err
@@ -98,14 +130,14 @@ func checkToken(conf *config.Config, checkToken string) error {
 	return nil
 }
 
-func renewAuth(conf *config.Config, configPath string, api api.SturdyAPI) error {
+func renewAuth(conf *config.Config, profile *config.Profile, configPath string, api api.SturdyAPI) error {
 	// Not authed, don't do anything
-	if len(conf.Auth) == 0 {
+	if len(profile.Auth) == 0 {
 		return nil
 	}
 
 	// This does _not_ validate the token. It simply extracts the expiration date to check if we're eligible for a token renewal
-	token, _, err := new(jwt.Parser).ParseUnverified(conf.Auth, jwt.MapClaims{})
+	token, _, err := new(jwt.Parser).ParseUnverified(profile.Auth, jwt.MapClaims{})
 	if err != nil {
 		return nil
 	}
@@ -142,7 +174,7 @@ func renewAuth(conf *config.Config, configPath string, api api.SturdyAPI) error
 	}
 
 	// Updated token!
-	conf.Auth = res.Token
+	profile.Auth = res.Token
 
 	err = config.WriteConfig(configPath, conf)
 	if err != nil {
@@ -152,19 +184,22 @@ func renewAuth(conf *config.Config, configPath string, api api.SturdyAPI) error
 	return nil
 }
 
-func requireAuth(conf *config.Config, configPath string) (*api.HttpApiClient, error) {
+func requireAuth(conf *config.Config, profile *config.Profile, configPath string) (*api.HttpApiClient, error) {
 	// New authentication
-	if conf.Auth == "" {
-		auth(conf, configPath)
-		return api.NewHttpApiClient(conf), nil
+	if profile.Auth == "" {
+		if len(conf.Profiles) > 1 {
+			fmt.Printf("🌐 Authenticating with %s (%s)\n", profile.Name, profile.APIRemote)
+		}
+		auth(profile, configPath)
+		return api.NewHttpApiClient(profile), nil
 	}
 
 	// Check if we need to renew the authentication
-	apiClient := api.NewHttpApiClient(conf)
-	err := renewAuth(conf, configPath, apiClient)
+	apiClient := api.NewHttpApiClient(profile)
+	err := renewAuth(conf, profile, configPath, apiClient)
 	if err != nil {
 		return nil, err
 	}
 
-	return api.NewHttpApiClient(conf), nil
+	return api.NewHttpApiClient(profile), nil
 }
@@ -25,7 +25,7 @@ func TestReadUntilValidTokenInput(t *testing.T) {
 	}{
 		{
 			name: "valid on first try",
-			args: args{termReadWriter: bytes.NewBufferString("xoxo\n\n"), validationFunc: func(conf *config.Config, checkToken string) error {
+			args: args{termReadWriter: bytes.NewBufferString("xoxo\n\n"), validationFunc: func(profile *config.Profile, checkToken string) error {
 				if checkToken == "xoxo" {
 					return nil
 				}
@@ -37,7 +37,7 @@ func TestReadUntilValidTokenInput(t *testing.T) {
 			name: "valid on second try",
 			args: args{
 				termReadWriter: &scheduledReader{strs: []string{"xoxo\r\n", "bobo\r\n", "hobo\r\n"}},
-				validationFunc: func(conf *config.Config, checkToken string) error {
+				validationFunc: func(profile *config.Profile, checkToken string) error {
 					log.Println("Validation func", checkToken)
 					if checkToken == "bobo" {
 						return nil
@@ -60,7 +60,7 @@ func TestReadUntilValidTokenInput(t *testing.T) {
 					"\r\n", "\r\n", "\r\n", "\r\n", "\r\n",
 					"\r\n", "\r\n", "\r\n", "\r\n", "\r\n",
 				}},
-				validationFunc: func(conf *config.Config, checkToken string) error {
+				validationFunc: func(profile *config.Profile, checkToken string) error {
 					return fmt.Errorf("invalid")
 				},
 			},
@@ -69,8 +69,8 @@ func TestReadUntilValidTokenInput(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			var conf config.Config
-			got, err := readUntilValidTokenInput(&conf, tt.args.termReadWriter, tt.args.validationFunc)
+			var profile config.Profile
+			got, err := readUntilValidTokenInput(&profile, tt.args.termReadWriter, tt.args.validationFunc)
 			assert.Equal(t, tt.wantErr, err)
 			assert.Equal(t, tt.want, got)
 		})
@@ -96,3 +96,17 @@ func (r *scheduledReader) Read(p []byte) (n int, err error) {
 func (r *scheduledReader) Write(p []byte) (n int, err error) {
 	return len(p), nil
 }
+
+func TestTokenURL(t *testing.T) {
+	cases := map[string]string{
+		"https://api.getsturdy.com":       "https://getsturdy.com/install/token",
+		"http://localhost:30080/api":      "http://localhost:30080/install/token",
+		"https://sturdy.example.com/api/": "https://sturdy.example.com/install/token",
+		"":                                "https://getsturdy.com/install/token",
+	}
+	for apiRemote, expected := range cases {
+		t.Run(apiRemote, func(t *testing.T) {
+			assert.Equal(t, expected, tokenURL(&config.Profile{APIRemote: apiRemote}))
+		})
+	}
+}
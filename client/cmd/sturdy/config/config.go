@@ -5,30 +5,119 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"sort"
 )
 
+// DefaultProfileName is the name of the profile that is used when no profile is given, and that the configuration of
+// older versions is migrated to.
+const DefaultProfileName = "default"
+
 type Config struct {
-	Remote         string       `json:"remote"` // gRPC API (unused)
-	InsecureRemote bool         `json:"insecure-remote,omitempty"`
-	APIRemote      string       `json:"api-remote"`  // HTTP API
-	SyncRemote     string       `json:"sync-remote"` // Mutagen SSH API
-	Auth           string       `json:"auth"`
-	GitRemote      string       `json:"git-remote,omitempty"` // Git Server
-	Views          []ViewConfig `json:"views"`
+	Profiles []*Profile   `json:"profiles"`
+	Views    []ViewConfig `json:"views"`
+}
+
+// Profile is a Sturdy server, and the credentials to use with it.
+type Profile struct {
+	Name           string `json:"name"`
+	InsecureRemote bool   `json:"insecure-remote,omitempty"`
+	APIRemote      string `json:"api-remote"`  // HTTP API
+	SyncRemote     string `json:"sync-remote"` // Mutagen SSH API
+	Auth           string `json:"auth"`
+	GitRemote      string `json:"git-remote,omitempty"` // Git Server
 }
 
-func (c Config) GetGitRemote() (proto, host string) {
-	if c.GitRemote == "" {
+func (p Profile) GetGitRemote() (proto, host string) {
+	if p.GitRemote == "" {
 		// Default remote
 		return "https", "git.getsturdy.com"
 	}
 
-	return "http", c.GitRemote
+	return "http", p.GitRemote
 }
 
 type ViewConfig struct {
-	ID   string `json:"id"`
-	Path string `json:"path"`
+	ID      string `json:"id"`
+	Path    string `json:"path"`
+	Profile string `json:"profile,omitempty"`
+}
+
+// legacyConfig is the format of the configuration before profiles were added, when it could only hold a single
+// server.
+type legacyConfig struct {
+	Config
+
+	InsecureRemote bool   `json:"insecure-remote,omitempty"`
+	APIRemote      string `json:"api-remote"`
+	SyncRemote     string `json:"sync-remote"`
+	Auth           string `json:"auth"`
+	GitRemote      string `json:"git-remote,omitempty"`
+}
+
+func defaultProfile() *Profile {
+	return &Profile{
+		Name:       DefaultProfileName,
+		APIRemote:  "https://api.getsturdy.com",
+		SyncRemote: "sync.getsturdy.com",
+	}
+}
+
+// Profile returns the profile with the given name, or nil if it does not exist. An empty name is the default profile.
+func (c *Config) Profile(name string) *Profile {
+	if name == "" {
+		name = DefaultProfileName
+	}
+	for _, p := range c.Profiles {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// ViewsForProfile returns the views that are bound to the profile.
+func (c *Config) ViewsForProfile(name string) []ViewConfig {
+	var views []ViewConfig
+	for _, v := range c.Views {
+		if v.Profile == name {
+			views = append(views, v)
+		}
+	}
+	return views
+}
+
+// AddProfile adds a new profile, or replaces the remotes of an existing one. The credentials of an existing profile
+// are kept if the API remote is not changed.
+func (c *Config) AddProfile(profile *Profile) {
+	if existing := c.Profile(profile.Name); existing != nil {
+		if existing.APIRemote == profile.APIRemote && profile.Auth == "" {
+			profile.Auth = existing.Auth
+		}
+		*existing = *profile
+		return
+	}
+	c.Profiles = append(c.Profiles, profile)
+	sort.Slice(c.Profiles, func(i, j int) bool {
+		return c.Profiles[i].Name < c.Profiles[j].Name
+	})
+}
+
+// RemoveProfile removes a profile. Profiles that still have views bound to them can not be removed.
+func (c *Config) RemoveProfile(name string) error {
+	if c.Profile(name) == nil {
+		return fmt.Errorf("profile %q does not exist", name)
+	}
+	if views := c.ViewsForProfile(name); len(views) > 0 {
+		return fmt.Errorf("profile %q is used by %d directories", name, len(views))
+	}
+	var profiles []*Profile
+	for _, p := range c.Profiles {
+		if p.Name != name {
+			profiles = append(profiles, p)
+		}
+	}
+	c.Profiles = profiles
+	return nil
 }
 
 func ReadConfig(path string) (*Config, error) {
@@ -38,26 +127,51 @@ func ReadConfig(path string) (*Config, error) {
 			// Create a default config
 			// TODO: Easier defaults for local dev
 			return &Config{
-				Remote:         "fs.getsturdy.com:443",
-				InsecureRemote: false,
-				APIRemote:      "https://api.getsturdy.com",
-				SyncRemote:     "sync.getsturdy.com",
+				Profiles: []*Profile{defaultProfile()},
 			}, nil
 		}
 		return nil, fmt.Errorf("failed to read config: %w", err)
 	}
-	var conf Config
+	var conf legacyConfig
 	err = json.Unmarshal(configContents, &conf)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse config: %w", err)
 	}
 
-	// For backwards compatibility
-	if conf.SyncRemote == "" {
-		conf.SyncRemote = "sync.getsturdy.com"
+	migrate(&conf)
+
+	return &conf.Config, nil
+}
+
+// migrate moves the server of a configuration without profiles to the default profile, and binds all views without a
+// profile to it.
+func migrate(conf *legacyConfig) {
+	if conf.Profile(DefaultProfileName) == nil && (len(conf.Profiles) == 0 || conf.APIRemote != "" || conf.Auth != "") {
+		profile := defaultProfile()
+		profile.InsecureRemote = conf.InsecureRemote
+		profile.Auth = conf.Auth
+		profile.GitRemote = conf.GitRemote
+		if conf.APIRemote != "" {
+			profile.APIRemote = conf.APIRemote
+		}
+		if conf.SyncRemote != "" {
+			profile.SyncRemote = conf.SyncRemote
+		}
+		conf.AddProfile(profile)
 	}
 
-	return &conf, nil
+	for i := range conf.Views {
+		if conf.Views[i].Profile == "" {
+			conf.Views[i].Profile = DefaultProfileName
+		}
+	}
+
+	// For backwards compatibility
+	for _, p := range conf.Profiles {
+		if p.SyncRemote == "" {
+			p.SyncRemote = "sync.getsturdy.com"
+		}
+	}
 }
 
 func WriteConfig(path string, conf *Config) error {
@@ -72,12 +186,16 @@ func WriteConfig(path string, conf *Config) error {
 	return nil
 }
 
-func SetAuth(configPath, auth string) error {
+func SetAuth(configPath, profileName, auth string) error {
 	c, err := ReadConfig(configPath)
 	if err != nil {
 		return fmt.Errorf("failed to read config: %w", err)
 	}
-	c.Auth = auth
+	profile := c.Profile(profileName)
+	if profile == nil {
+		return fmt.Errorf("profile %q does not exist", profileName)
+	}
+	profile.Auth = auth
 	err = WriteConfig(configPath, c)
 	if err != nil {
 		return fmt.Errorf("failed to update config: %w", err)
@@ -85,15 +203,16 @@ func SetAuth(configPath, auth string) error {
 	return nil
 }
 
-func AddMount(configPath, viewID, mountPath string) (*Config, error) {
+func AddMount(configPath, profileName, viewID, mountPath string) (*Config, error) {
 	c, err := ReadConfig(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config: %w", err)
 	}
 
 	c.Views = append(c.Views, ViewConfig{
-		ID:   viewID,
-		Path: mountPath,
+		ID:      viewID,
+		Path:    mountPath,
+		Profile: profileName,
 	})
 
 	err = WriteConfig(configPath, c)
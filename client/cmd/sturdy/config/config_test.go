@@ -0,0 +1,92 @@
+package config
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadConfig_migratesSingleServer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".sturdy")
+	err := ioutil.WriteFile(path, []byte(`{
+		"remote": "fs.getsturdy.com:443",
+		"api-remote": "http://localhost:3000",
+		"sync-remote": "localhost:2222",
+		"auth": "token",
+		"git-remote": "localhost:3002",
+		"views": [{"id": "view", "path": "/code/a"}]
+	}`), 0o644)
+	assert.NoError(t, err)
+
+	conf, err := ReadConfig(path)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []*Profile{{
+		Name:       DefaultProfileName,
+		APIRemote:  "http://localhost:3000",
+		SyncRemote: "localhost:2222",
+		Auth:       "token",
+		GitRemote:  "localhost:3002",
+	}}, conf.Profiles)
+	assert.Equal(t, []ViewConfig{{ID: "view", Path: "/code/a", Profile: DefaultProfileName}}, conf.Views)
+
+	// the migrated configuration is read back as is
+	assert.NoError(t, WriteConfig(path, conf))
+	reread, err := ReadConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, conf, reread)
+}
+
+func TestReadConfig_profiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".sturdy")
+	err := ioutil.WriteFile(path, []byte(`{
+		"profiles": [
+			{"name": "default", "api-remote": "https://api.getsturdy.com", "auth": "cloud"},
+			{"name": "work", "api-remote": "https://sturdy.example.com/api", "sync-remote": "sturdy.example.com:2222", "auth": "work"}
+		],
+		"views": [
+			{"id": "a", "path": "/code/a", "profile": "default"},
+			{"id": "b", "path": "/code/b", "profile": "work"}
+		]
+	}`), 0o644)
+	assert.NoError(t, err)
+
+	conf, err := ReadConfig(path)
+	assert.NoError(t, err)
+
+	assert.Len(t, conf.Profiles, 2)
+	assert.Equal(t, "sync.getsturdy.com", conf.Profile("").SyncRemote)
+	assert.Equal(t, "work", conf.Profile("work").Auth)
+	assert.Nil(t, conf.Profile("missing"))
+	assert.Equal(t, []ViewConfig{{ID: "b", Path: "/code/b", Profile: "work"}}, conf.ViewsForProfile("work"))
+
+	assert.Error(t, conf.RemoveProfile("work"), "profile is in use")
+	conf.Views = conf.Views[:1]
+	assert.NoError(t, conf.RemoveProfile("work"))
+	assert.Nil(t, conf.Profile("work"))
+}
+
+func TestReadConfig_default(t *testing.T) {
+	conf, err := ReadConfig(filepath.Join(t.TempDir(), "missing"))
+	assert.NoError(t, err)
+	assert.Equal(t, []*Profile{defaultProfile()}, conf.Profiles)
+}
+
+func TestAddProfile(t *testing.T) {
+	conf := &Config{Profiles: []*Profile{defaultProfile()}}
+	conf.AddProfile(&Profile{Name: "work", APIRemote: "https://sturdy.example.com/api"})
+	conf.Profile("work").Auth = "token"
+
+	// updating the remotes of a profile keeps its credentials
+	conf.AddProfile(&Profile{Name: "work", APIRemote: "https://sturdy.example.com/api", SyncRemote: "sturdy.example.com:2222"})
+	assert.Equal(t, "token", conf.Profile("work").Auth)
+	assert.Equal(t, "sturdy.example.com:2222", conf.Profile("work").SyncRemote)
+
+	// but not if the server changes
+	conf.AddProfile(&Profile{Name: "work", APIRemote: "https://sturdy.example.org/api"})
+	assert.Equal(t, "", conf.Profile("work").Auth)
+
+	assert.Equal(t, []string{"default", "work"}, []string{conf.Profiles[0].Name, conf.Profiles[1].Name})
+}
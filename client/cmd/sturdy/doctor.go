@@ -0,0 +1,425 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"getsturdy.com/client/cmd/sturdy/config"
+	"getsturdy.com/client/pkg/api"
+	"getsturdy.com/client/pkg/ignore"
+	"getsturdy.com/client/pkg/mutagen"
+
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+type checkStatus string
+
+const (
+	checkOK      checkStatus = "ok"
+	checkWarning checkStatus = "warning"
+	checkFailed  checkStatus = "error"
+)
+
+// doctorCheck is the result of a single check. Profile and Path are set if the check is for a profile or for a view.
+type doctorCheck struct {
+	Name    string      `json:"name"`
+	Profile string      `json:"profile,omitempty"`
+	Path    string      `json:"path,omitempty"`
+	Status  checkStatus `json:"status"`
+	Message string      `json:"message"`
+	Fix     string      `json:"fix,omitempty"`
+}
+
+type doctorReport struct {
+	OK     bool          `json:"ok"`
+	Checks []doctorCheck `json:"checks"`
+}
+
+func (r *doctorReport) add(check doctorCheck) {
+	r.Checks = append(r.Checks, check)
+	if check.Status == checkFailed {
+		r.OK = false
+	}
+}
+
+// doctorEnv is everything that the checks use to talk to the outside world.
+type doctorEnv struct {
+	newAPI          func(*config.Profile) api.SturdyAPI
+	sshPing         func(hostWithOptionalPort string, hostKeyCallback ssh.HostKeyCallback) error
+	syncVersion     func() (string, error)
+	sessions        func() ([]mutagen.SessionStatus, error)
+	agentDirPath    string
+	knownHostsPath  string
+	readLocalIgnore func(viewPath string) ([]string, error)
+}
+
+func doctor(conf *config.Config, profileName string, asJSON bool) {
+	agentDirPath, err := mutagenSturdyAgentDirPath()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	knownHostsFilePath, err := knownHostsPath()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	report := runDoctor(conf, profileName, doctorEnv{
+		newAPI: func(profile *config.Profile) api.SturdyAPI {
+			return api.NewHttpApiClient(profile)
+		},
+		sshPing:        sshPing,
+		syncVersion:    syncVersion,
+		sessions:       mutagen.Status,
+		agentDirPath:   agentDirPath,
+		knownHostsPath: knownHostsFilePath,
+		readLocalIgnore: func(viewPath string) ([]string, error) {
+			return ignore.FindIgnore(os.DirFS(viewPath))
+		},
+	})
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "    ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	} else {
+		printDoctorReport(report)
+	}
+
+	if !report.OK {
+		os.Exit(1)
+	}
+}
+
+func printDoctorReport(report doctorReport) {
+	var fixes []string
+	for _, check := range report.Checks {
+		var icon string
+		switch check.Status {
+		case checkOK:
+			icon = "✅"
+		case checkWarning:
+			icon = "⚠️ "
+		default:
+			icon = "❌"
+		}
+
+		subject := check.Name
+		if check.Path != "" {
+			subject += " " + check.Path
+		} else if check.Profile != "" {
+			subject += " (" + check.Profile + ")"
+		}
+		fmt.Printf("%s %s: %s\n", icon, subject, check.Message)
+
+		if check.Fix != "" {
+			fixes = append(fixes, check.Fix)
+		}
+	}
+
+	if len(fixes) == 0 {
+		fmt.Println("\n🎉 Everything looks good!")
+		return
+	}
+
+	fmt.Println("\n👉 To fix the problems:")
+	seen := make(map[string]struct{})
+	for _, fix := range fixes {
+		if _, ok := seen[fix]; ok {
+			continue
+		}
+		seen[fix] = struct{}{}
+		fmt.Printf("  - %s\n", fix)
+	}
+}
+
+// runDoctor checks the connections to all profiles, or only to _profileName_ if it's set, and the views that are bound
+// to them.
+func runDoctor(conf *config.Config, profileName string, env doctorEnv) doctorReport {
+	report := doctorReport{OK: true}
+
+	version, err := env.syncVersion()
+	if err != nil {
+		report.add(doctorCheck{
+			Name:    "sturdy-sync",
+			Status:  checkFailed,
+			Message: err.Error(),
+			Fix:     "Reinstall Sturdy, sturdy-sync must be installed next to sturdy and be in your PATH",
+		})
+		return report
+	}
+	report.add(doctorCheck{Name: "sturdy-sync", Status: checkOK, Message: "version " + version})
+
+	sessionsByName := make(map[string]mutagen.SessionStatus)
+	if sessions, err := env.sessions(); err != nil {
+		report.add(doctorCheck{
+			Name:    "daemon",
+			Status:  checkFailed,
+			Message: fmt.Sprintf("the sync daemon is not responding: %s", err),
+			Fix:     "Run 'sturdy restart'",
+		})
+	} else {
+		for _, s := range sessions {
+			sessionsByName[s.Session.Name] = s
+		}
+		report.add(doctorCheck{Name: "daemon", Status: checkOK, Message: fmt.Sprintf("%d connections", len(sessions))})
+	}
+
+	for _, profile := range conf.Profiles {
+		if profileName != "" && profile.Name != profileName {
+			continue
+		}
+		doctorProfile(&report, conf, profile, sessionsByName, env)
+	}
+
+	return report
+}
+
+func doctorProfile(report *doctorReport, conf *config.Config, profile *config.Profile, sessionsByName map[string]mutagen.SessionStatus, env doctorEnv) {
+	check := func(name string, status checkStatus, message, fix string) {
+		report.add(doctorCheck{Name: name, Profile: profile.Name, Status: status, Message: message, Fix: fix})
+	}
+	authFix := fmt.Sprintf("Run 'sturdy auth -profile %s'", profile.Name)
+
+	// API
+	if profile.Auth == "" {
+		check("api", checkFailed, "not authenticated", authFix)
+		return
+	}
+	apiClient := env.newAPI(profile)
+	user, err := apiClient.GetUser()
+	switch {
+	case errors.Is(err, api.ErrUnauthorized):
+		check("api", checkFailed, "the credentials have expired, or are not valid", authFix)
+		return
+	case err != nil:
+		check("api", checkFailed, fmt.Sprintf("could not reach %s: %s", profile.APIRemote, err),
+			fmt.Sprintf("Check your network connection, and that the api-remote of the '%s' profile is correct", profile.Name))
+		return
+	default:
+		check("api", checkOK, fmt.Sprintf("connected to %s as %s", profile.APIRemote, user.Name), "")
+	}
+
+	// SSH
+	host, port := splitHostPort(profile.SyncRemote)
+	var knownHostsErr error
+	hostKeyCallback := func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		knownHostsErr = checkKnownHosts(env.knownHostsPath, hostname, remote, key)
+		return nil
+	}
+	if err := env.sshPing(profile.SyncRemote, hostKeyCallback); err != nil {
+		check("ssh", checkFailed, fmt.Sprintf("could not reach %s: %s", profile.SyncRemote, err),
+			fmt.Sprintf("Check your network connection, and that port %s of %s is not blocked by a firewall", port, host))
+	} else {
+		check("ssh", checkOK, fmt.Sprintf("%s is reachable", profile.SyncRemote), "")
+
+		var keyErr *knownhosts.KeyError
+		switch {
+		case knownHostsErr == nil:
+			check("known_hosts", checkOK, fmt.Sprintf("the key of %s is trusted", profile.SyncRemote), "")
+		case errors.As(knownHostsErr, &keyErr) && len(keyErr.Want) > 0:
+			check("known_hosts", checkFailed,
+				fmt.Sprintf("the key of %s does not match the key in %s", profile.SyncRemote, env.knownHostsPath),
+				fmt.Sprintf("If the server has been reinstalled, remove the old key with 'ssh-keygen -R %s' and run 'sturdy restart'", knownhosts.Normalize(net.JoinHostPort(host, port))))
+		case errors.As(knownHostsErr, &keyErr):
+			check("known_hosts", checkFailed, fmt.Sprintf("%s is not trusted", profile.SyncRemote), "Run 'sturdy restart'")
+		default:
+			check("known_hosts", checkFailed, knownHostsErr.Error(), "")
+		}
+	}
+
+	// Key registration
+	privateKeyPath := privateKeyPathForUser(env.agentDirPath, user.ID)
+	if privateKey, err := ioutil.ReadFile(privateKeyPath); errors.Is(err, os.ErrNotExist) {
+		check("key", checkFailed, "there is no key for the user", "Run 'sturdy restart'")
+	} else if err != nil {
+		check("key", checkFailed, err.Error(), "")
+	} else if signer, err := ssh.ParsePrivateKey(privateKey); err != nil {
+		check("key", checkFailed, fmt.Sprintf("the key in %s is not valid: %s", privateKeyPath, err),
+			fmt.Sprintf("Remove %s and run 'sturdy restart'", privateKeyPath))
+	} else if ok, err := apiClient.VerifyPublicKey(user.ID, signer.PublicKey().Marshal()); err != nil {
+		check("key", checkFailed, err.Error(), "")
+	} else if !ok {
+		check("key", checkFailed, "the key is not registered with the server", "Run 'sturdy restart'")
+	} else {
+		check("key", checkOK, "the key is registered with the server", "")
+	}
+
+	for _, view := range conf.ViewsForProfile(profile.Name) {
+		doctorView(report, profile, view, user, apiClient, sessionsByName, env)
+	}
+}
+
+func doctorView(report *doctorReport, profile *config.Profile, view config.ViewConfig, user api.GetUserResponse, apiClient api.SturdyAPI, sessionsByName map[string]mutagen.SessionStatus, env doctorEnv) {
+	check := func(name string, status checkStatus, message, fix string) {
+		report.add(doctorCheck{Name: name, Profile: profile.Name, Path: view.Path, Status: status, Message: message, Fix: fix})
+	}
+
+	if _, err := os.Stat(view.Path); err != nil {
+		check("view", checkFailed, "the directory does not exist",
+			fmt.Sprintf("Remove %s from the views in your configuration, or create the directory and run 'sturdy restart'", view.Path))
+		return
+	}
+
+	apiView, err := apiClient.GetView(view.ID)
+	switch {
+	case errors.Is(err, api.ErrUnauthorized):
+		check("view", checkFailed, "you don't have access to the codebase", "Run 'sturdy restart' to remove the directory from your configuration")
+		return
+	case err != nil:
+		check("view", checkFailed, err.Error(), "")
+		return
+	case apiView.CodebaseIsArchived:
+		check("view", checkWarning, fmt.Sprintf("the codebase %s has been archived", apiView.CodebaseName), "Run 'sturdy restart' to remove the directory from your configuration")
+		return
+	}
+
+	if err := apiClient.ValidateView(view.ID, apiView.CodebaseID, user.ID); err != nil {
+		check("view", checkFailed, fmt.Sprintf("the sync server will not accept connections to this directory: %s", err),
+			fmt.Sprintf("Set up %s again with 'sturdy init %s PATH'", apiView.CodebaseName, apiView.CodebaseID))
+	} else {
+		check("view", checkOK, fmt.Sprintf("connected to %s", apiView.CodebaseName), "")
+	}
+
+	session, hasSession := sessionsByName[viewMutagenName(view)]
+	switch {
+	case !hasSession:
+		check("sync", checkFailed, "there is no connection", "Run 'sturdy start'")
+	case session.Session.Paused:
+		check("sync", checkWarning, "the connection is paused", "Run 'sturdy start'")
+	case session.LastError != "":
+		check("sync", checkFailed, session.LastError, "Run 'sturdy restart'")
+	case !session.AlphaConnected || !session.BetaConnected:
+		check("sync", checkWarning, "not connected", "")
+	default:
+		check("sync", checkOK, "connected", "")
+	}
+
+	ignores, err := apiClient.GetIgnores(view.ID)
+	if err != nil {
+		check("ignores", checkFailed, err.Error(), "")
+		return
+	}
+	localIgnores, err := env.readLocalIgnore(view.Path)
+	if err != nil {
+		check("ignores", checkFailed, fmt.Sprintf("failed to read .gitignore: %s", err), "")
+		return
+	}
+
+	if onlyServer, onlyLocal := diffIgnores(ignores.Paths, localIgnores); len(onlyServer) > 0 || len(onlyLocal) > 0 {
+		var msgs []string
+		if len(onlyLocal) > 0 {
+			msgs = append(msgs, fmt.Sprintf("not synced to the server: %s", strings.Join(onlyLocal, ", ")))
+		}
+		if len(onlyServer) > 0 {
+			msgs = append(msgs, fmt.Sprintf("removed locally but not on the server: %s", strings.Join(onlyServer, ", ")))
+		}
+		check("ignores", checkWarning, "the .gitignore files differ from the server, "+strings.Join(msgs, "; "),
+			"Wait for the changes to .gitignore to sync, and run 'sturdy restart' to apply them")
+	} else if missing, _ := diffIgnores(ignores.Paths, session.Session.Configuration.Ignores); hasSession && len(missing) > 0 {
+		check("ignores", checkWarning, fmt.Sprintf("the connection does not ignore %s", strings.Join(missing, ", ")), "Run 'sturdy restart' to apply the ignores")
+	} else {
+		check("ignores", checkOK, fmt.Sprintf("%d ignored paths", len(ignores.Paths)), "")
+	}
+}
+
+// checkKnownHosts returns a *knownhosts.KeyError if the host is not in the known hosts file, or if it's there with
+// another key.
+func checkKnownHosts(knownHostsPath, hostname string, remote net.Addr, key ssh.PublicKey) error {
+	callback, err := knownhosts.New(knownHostsPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return &knownhosts.KeyError{}
+	} else if err != nil {
+		return fmt.Errorf("failed to read %s: %w", knownHostsPath, err)
+	}
+	return callback(hostname, remote, key)
+}
+
+// sshPing connects to the sync server as the "ping" user, which accepts any key, and expects it to respond with pong.
+func sshPing(hostWithOptionalPort string, hostKeyCallback ssh.HostKeyCallback) error {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return fmt.Errorf("failed to generate key: %w", err)
+	}
+	signer, err := ssh.NewSignerFromKey(privateKey)
+	if err != nil {
+		return fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	client, err := ssh.Dial("tcp", net.JoinHostPort(splitHostPort(hostWithOptionalPort)), &ssh.ClientConfig{
+		User:            "ping",
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         10 * time.Second,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.Close()
+
+	output, err := session.Output("")
+	if err != nil {
+		return fmt.Errorf("failed to ping: %w", err)
+	}
+	if strings.TrimSpace(string(output)) != "pong!" {
+		return fmt.Errorf("unexpected response: %q", output)
+	}
+	return nil
+}
+
+func syncVersion() (string, error) {
+	if _, err := exec.LookPath("sturdy-sync"); err != nil {
+		return "", fmt.Errorf("sturdy-sync is not installed: %w", err)
+	}
+	output, err := exec.Command("sturdy-sync", "version").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("sturdy-sync is not working: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// diffIgnores returns the patterns that are only in a, and the patterns that are only in b.
+func diffIgnores(a, b []string) (onlyA, onlyB []string) {
+	inA := make(map[string]struct{}, len(a))
+	for _, p := range a {
+		inA[p] = struct{}{}
+	}
+	inB := make(map[string]struct{}, len(b))
+	for _, p := range b {
+		inB[p] = struct{}{}
+	}
+	for p := range inA {
+		if _, ok := inB[p]; !ok {
+			onlyA = append(onlyA, p)
+		}
+	}
+	for p := range inB {
+		if _, ok := inA[p]; !ok {
+			onlyB = append(onlyB, p)
+		}
+	}
+	sort.Strings(onlyA)
+	sort.Strings(onlyB)
+	return onlyA, onlyB
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"getsturdy.com/client/cmd/sturdy/config"
+	"getsturdy.com/client/pkg/api"
+	"getsturdy.com/client/pkg/mutagen"
+)
+
+type fakeAPI struct {
+	api.SturdyAPI
+
+	userErr      error
+	registered   bool
+	invalidViews map[string]bool
+	ignores      []string
+}
+
+func (f *fakeAPI) GetUser() (api.GetUserResponse, error) {
+	if f.userErr != nil {
+		return api.GetUserResponse{}, f.userErr
+	}
+	return api.GetUserResponse{ID: "user", Name: "User"}, nil
+}
+
+func (f *fakeAPI) VerifyPublicKey(userID string, publicKey []byte) (bool, error) {
+	return f.registered, nil
+}
+
+func (f *fakeAPI) GetView(id string) (api.View, error) {
+	return api.View{ID: id, CodebaseID: "codebase", CodebaseName: "Codebase"}, nil
+}
+
+func (f *fakeAPI) ValidateView(viewID, codebaseID, userID string) error {
+	if f.invalidViews[viewID] {
+		return fmt.Errorf("unexpected response code 400")
+	}
+	return nil
+}
+
+func (f *fakeAPI) GetIgnores(viewID string) (api.GetIgnoresResponse, error) {
+	return api.GetIgnoresResponse{Paths: f.ignores}, nil
+}
+
+func newHostKey(t *testing.T) ssh.PublicKey {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	key, err := ssh.NewPublicKey(pub)
+	assert.NoError(t, err)
+	return key
+}
+
+func TestRunDoctor(t *testing.T) {
+	agentDir := t.TempDir()
+	viewPath := t.TempDir()
+
+	// a registered key for the user
+	_, privateKeyPath, err := generateKey(agentDir, "user")
+	assert.NoError(t, err)
+	assert.FileExists(t, privateKeyPath)
+
+	hostKey := newHostKey(t)
+	knownHostsPath := filepath.Join(t.TempDir(), "known_hosts")
+	assert.NoError(t, ioutil.WriteFile(knownHostsPath, []byte(knownhosts.Line([]string{"[sync.example.com]:2222"}, hostKey)+"\n"), 0o644))
+
+	conf := &config.Config{
+		Profiles: []*config.Profile{
+			{Name: "default", APIRemote: "https://api.getsturdy.com", SyncRemote: "sync.getsturdy.com"},
+			{Name: "work", APIRemote: "https://sturdy.example.com/api", SyncRemote: "sync.example.com:2222", Auth: "token"},
+		},
+		Views: []config.ViewConfig{
+			{ID: "a", Path: viewPath, Profile: "work"},
+			{ID: "b", Path: viewPath, Profile: "work"},
+			{ID: "c", Path: filepath.Join(viewPath, "missing"), Profile: "work"},
+		},
+	}
+
+	fake := &fakeAPI{registered: true, invalidViews: map[string]bool{"b": true}, ignores: []string{"node_modules"}}
+	sessions := []mutagen.SessionStatus{{AlphaConnected: true, BetaConnected: true}, {}}
+	sessions[0].Session.Name = "view-a"
+	sessions[0].Session.Configuration.Ignores = []string{"node_modules", ".DS_Store"}
+	sessions[1].Session.Name = "view-b"
+	sessions[1].Session.Paused = true
+
+	var pinged []string
+	report := runDoctor(conf, "", doctorEnv{
+		newAPI: func(profile *config.Profile) api.SturdyAPI {
+			assert.Equal(t, "work", profile.Name, "unauthenticated profiles do not make requests")
+			return fake
+		},
+		sshPing: func(host string, callback ssh.HostKeyCallback) error {
+			pinged = append(pinged, host)
+			return callback("sync.example.com:2222", &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 2222}, hostKey)
+		},
+		syncVersion: func() (string, error) {
+			return "0.13.0", nil
+		},
+		sessions: func() ([]mutagen.SessionStatus, error) {
+			return sessions, nil
+		},
+		agentDirPath:   agentDir,
+		knownHostsPath: knownHostsPath,
+		readLocalIgnore: func(path string) ([]string, error) {
+			return []string{"node_modules"}, nil
+		},
+	})
+
+	assert.False(t, report.OK)
+	assert.Equal(t, []string{"sync.example.com:2222"}, pinged)
+
+	type result struct {
+		name, profile, path string
+		status              checkStatus
+	}
+	var results []result
+	for _, check := range report.Checks {
+		results = append(results, result{check.Name, check.Profile, check.Path, check.Status})
+	}
+	assert.Equal(t, []result{
+		{"sturdy-sync", "", "", checkOK},
+		{"daemon", "", "", checkOK},
+		{"api", "default", "", checkFailed},
+		{"api", "work", "", checkOK},
+		{"ssh", "work", "", checkOK},
+		{"known_hosts", "work", "", checkOK},
+		{"key", "work", "", checkOK},
+		{"view", "work", viewPath, checkOK},
+		{"sync", "work", viewPath, checkOK},
+		{"ignores", "work", viewPath, checkOK},
+		{"view", "work", viewPath, checkFailed},
+		{"sync", "work", viewPath, checkWarning},
+		{"ignores", "work", viewPath, checkWarning},
+		{"view", "work", filepath.Join(viewPath, "missing"), checkFailed},
+	}, results)
+
+	assert.Equal(t, "Run 'sturdy auth -profile default'", report.Checks[2].Fix)
+}
+
+func TestRunDoctor_profile(t *testing.T) {
+	conf := &config.Config{
+		Profiles: []*config.Profile{
+			{Name: "default", APIRemote: "https://api.getsturdy.com", SyncRemote: "sync.getsturdy.com"},
+			{Name: "work", APIRemote: "https://sturdy.example.com/api", SyncRemote: "sync.example.com:2222", Auth: "token"},
+		},
+	}
+
+	report := runDoctor(conf, "work", doctorEnv{
+		newAPI: func(profile *config.Profile) api.SturdyAPI {
+			return &fakeAPI{userErr: api.ErrUnauthorized}
+		},
+		syncVersion: func() (string, error) {
+			return "0.13.0", nil
+		},
+		sessions: func() ([]mutagen.SessionStatus, error) {
+			return nil, nil
+		},
+	})
+
+	assert.False(t, report.OK)
+	if assert.Len(t, report.Checks, 3) {
+		assert.Equal(t, "work", report.Checks[2].Profile)
+		assert.Equal(t, checkFailed, report.Checks[2].Status)
+		assert.Equal(t, "Run 'sturdy auth -profile work'", report.Checks[2].Fix)
+	}
+}
+
+func TestCheckKnownHosts(t *testing.T) {
+	hostKey := newHostKey(t)
+	remote := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 22}
+
+	path := filepath.Join(t.TempDir(), "known_hosts")
+
+	// no known_hosts file
+	err := checkKnownHosts(path, "sync.getsturdy.com:22", remote, hostKey)
+	var keyErr *knownhosts.KeyError
+	if assert.ErrorAs(t, err, &keyErr) {
+		assert.Empty(t, keyErr.Want)
+	}
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte(knownhosts.Line([]string{"sync.getsturdy.com"}, hostKey)+"\n"), 0o644))
+	assert.NoError(t, checkKnownHosts(path, "sync.getsturdy.com:22", remote, hostKey))
+
+	// the key has changed
+	err = checkKnownHosts(path, "sync.getsturdy.com:22", remote, newHostKey(t))
+	if assert.ErrorAs(t, err, &keyErr) {
+		assert.NotEmpty(t, keyErr.Want)
+	}
+}
+
+func TestDiffIgnores(t *testing.T) {
+	onlyServer, onlyLocal := diffIgnores([]string{"a", "b", "c"}, []string{"d", "c", "a"})
+	assert.Equal(t, []string{"b"}, onlyServer)
+	assert.Equal(t, []string{"d"}, onlyLocal)
+
+	onlyServer, onlyLocal = diffIgnores(nil, nil)
+	assert.Empty(t, onlyServer)
+	assert.Empty(t, onlyLocal)
+}
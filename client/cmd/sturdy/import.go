@@ -10,7 +10,7 @@ import (
 	"getsturdy.com/client/pkg/api"
 )
 
-func importCodebase(conf *config.Config, args []string, apiClient *api.HttpApiClient) {
+func importCodebase(profile *config.Profile, args []string, apiClient *api.HttpApiClient) {
 	workingDir, err := os.Getwd()
 	if err != nil {
 		log.Println("Failed to get working directory", err)
@@ -36,9 +36,9 @@ func importCodebase(conf *config.Config, args []string, apiClient *api.HttpApiCl
 
 	fmt.Printf("✅ Importing git repo at %s to '%s'\n", workingDir, codebase.Name)
 
-	gitProto, gitHost := conf.GetGitRemote()
+	gitProto, gitHost := profile.GetGitRemote()
 
-	cmd := exec.Command("git", "push", fmt.Sprintf("%s://import:%s@%s/%s", gitProto, conf.Auth, gitHost, codebaseID), "HEAD:sturdytrunk", "--force")
+	cmd := exec.Command("git", "push", fmt.Sprintf("%s://import:%s@%s/%s", gitProto, profile.Auth, gitHost, codebaseID), "HEAD:sturdytrunk", "--force")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		log.Println("Import failed", err)
@@ -19,17 +19,17 @@ import (
 // If auth is missing it will run the auth flow.
 // If the mount point path is already configured with a view it will not create a new one
 // It will restart Sturdy daemon only if needed
-func initSmart(conf *config.Config, configPath string, args []string, apiClient api.SturdyAPI) {
+func initSmart(conf *config.Config, profile *config.Profile, configPath string, args []string, apiClient api.SturdyAPI) {
 	if len(args) < 2 {
 		log.Fatalln("❌ Unexpected number of arguments")
 	}
 
-	newConfig, _ := createView(conf, configPath, args)
+	newConfig, _ := createView(conf, profile, configPath, args)
 
 	conf = newConfig
 
 	fmt.Println("🔁 Starting Sturdy")
-	startMutagen(configPath, conf, apiClient)
+	startMutagen(configPath, conf, conf.Profile(profile.Name), apiClient)
 
 	printReady(args)
 }
@@ -38,7 +38,7 @@ func printReady(args []string) {
 	fmt.Printf("✅ Your new codebase view is now ready at: %s\n", args[1])
 }
 
-func createView(conf *config.Config, configPath string, args []string) (newConfig *config.Config, newlyCreated bool) {
+func createView(conf *config.Config, profile *config.Profile, configPath string, args []string) (newConfig *config.Config, newlyCreated bool) {
 	mountPath, err := absPath(args[1])
 	if err != nil {
 		log.Fatalf("Failed to convert to absolute path: %s\n", err)
@@ -81,12 +81,12 @@ func createView(conf *config.Config, configPath string, args []string) (newConfi
 
 	codebaseID := args[0]
 
-	viewID, err := initView.CreateWorkspaceAndView(conf.APIRemote, conf.Auth, codebaseID, mountPath)
+	viewID, err := initView.CreateWorkspaceAndView(profile.APIRemote, profile.Auth, codebaseID, mountPath)
 	if err != nil {
 		log.Fatalln(err)
 	}
 
-	newConfig, err = config.AddMount(configPath, viewID, mountPath)
+	newConfig, err = config.AddMount(configPath, profile.Name, viewID, mountPath)
 	if err != nil {
 		log.Fatalln(err)
 	}
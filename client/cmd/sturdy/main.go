@@ -10,6 +10,7 @@ import (
 	"getsturdy.com/client/cmd/sturdy/config"
 	"getsturdy.com/client/cmd/sturdy/legal"
 	"getsturdy.com/client/cmd/sturdy/version"
+	"getsturdy.com/client/pkg/api"
 )
 
 func printHelpAndExit() {
@@ -22,9 +23,20 @@ func printHelpAndExit() {
 	fmt.Println("  stop     Stop all connections and stop the daemon")
 	fmt.Println("  restart  Restart and re-configure all connections")
 	fmt.Println("  status   Get the current status of each codebase")
+	fmt.Println("  doctor   Diagnose problems with the connections, use -json for a machine-readable report")
 	fmt.Println("  auth     Authenticate yourself with Sturdy")
 	fmt.Println("  init     Configure a new codebase to be used from this computer")
 	fmt.Println("  import   Import a Git repository to Sturdy")
+	fmt.Println("  profile list|add|remove  Manage the Sturdy servers that you use")
+	fmt.Println("")
+	fmt.Println("  workspace list|create|switch|archive  Manage the workspaces of the current directory")
+	fmt.Println("  diff                                  Show the changes in the current workspace")
+	fmt.Println("  land -m MESSAGE                       Land the changes in the current workspace")
+	fmt.Println("  review request USER|approve           Request or give a review of the current workspace")
+	fmt.Println("  comment [-path PATH -line N] MESSAGE  Comment on the current workspace")
+	fmt.Println("")
+	fmt.Println("  start, stop, status, doctor, and auth use all servers, unless a server is given with -profile NAME")
+	fmt.Println("")
 	fmt.Println("  version  Display Sturdy version information")
 	fmt.Println("  legal    Display legal credits")
 	os.Exit(1)
@@ -50,6 +62,11 @@ func main() {
 
 	fs := flag.FlagSet{}
 	configPath := fs.String("config", path.Join(home, ".sturdy"), "Path to your Sturdy configuration file")
+	message := fs.String("m", "", "Message to land the changes with (land)")
+	commentPath := fs.String("path", "", "Path of the file to comment on (comment)")
+	commentLine := fs.Int("line", 0, "Line to comment on (comment)")
+	profileName := fs.String("profile", "", "Name of the server profile to use")
+	asJSON := fs.Bool("json", false, "Print a machine-readable report (doctor)")
 	err = fs.Parse(args)
 	if err != nil {
 		log.Println("Failed to parse flags", err)
@@ -74,35 +91,66 @@ func main() {
 		}
 	}
 
+	// profile returns the profile given with -profile, or the default profile
+	profile := func() *config.Profile {
+		p := conf.Profile(*profileName)
+		if p == nil {
+			fmt.Printf("⚠️  There is no profile named '%s'. Add it with 'sturdy profile add'\n", *profileName)
+			os.Exit(1)
+		}
+		return p
+	}
+
+	// graphQLClient returns a client for the server of the directory that the command is run in
+	graphQLClient := func() *api.GraphQLClient {
+		p, err := currentProfile(conf)
+		exitIfErr(err)
+		_, err = requireAuth(conf, p, *configPath)
+		exitIfErr(err)
+		return api.NewGraphQLClient(p)
+	}
+
 	switch os.Args[1] {
 	case "auth":
 		// It's important to not attempt to require auth, or renew auth _before_ calling auth()
-		auth(conf, *configPath)
+		authAll(conf, *configPath, *profileName)
 	case "status":
 		status(conf)
+	case "doctor":
+		doctor(conf, *profileName, *asJSON)
 	case "init":
-		apiClient, err := requireAuth(conf, *configPath)
+		p := profile()
+		apiClient, err := requireAuth(conf, p, *configPath)
 		exitIfErr(err)
-		initSmart(conf, *configPath, args, apiClient)
+		initSmart(conf, p, *configPath, args, apiClient)
 	case "start":
-		apiClient, err := requireAuth(conf, *configPath)
-		exitIfErr(err)
-		startMutagen(*configPath, conf, apiClient)
+		startAll(*configPath, conf, *profileName)
 	case "stop":
 		stopMutagen(conf)
 	case "restart":
 		stopMutagen(conf)
-		apiClient, err := requireAuth(conf, *configPath)
-		exitIfErr(err)
-		startMutagen(*configPath, conf, apiClient)
+		startAll(*configPath, conf, *profileName)
+	case "profile":
+		profileCmd(conf, *configPath, args)
 	case "legal":
 		fmt.Println(legal.LegalNotice)
 	case "version":
 		version.VersionCMD()
 	case "import":
-		apiClient, err := requireAuth(conf, *configPath)
+		p := profile()
+		apiClient, err := requireAuth(conf, p, *configPath)
 		exitIfErr(err)
-		importCodebase(conf, args, apiClient)
+		importCodebase(p, args, apiClient)
+	case "workspace":
+		workspaceCmd(conf, args, graphQLClient())
+	case "diff":
+		diff(conf, graphQLClient())
+	case "land":
+		land(conf, *message, graphQLClient())
+	case "review":
+		review(conf, args, graphQLClient())
+	case "comment":
+		comment(conf, args, *commentPath, *commentLine, graphQLClient())
 	default:
 		printHelpAndExit()
 	}
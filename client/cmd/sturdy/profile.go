@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"getsturdy.com/client/cmd/sturdy/config"
+)
+
+func profileCmd(conf *config.Config, configPath string, args []string) {
+	if len(args) < 1 {
+		fmt.Println("⚠️  Unexpected number of arguments. Expected: 'sturdy profile list|add|remove'")
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "list":
+		for _, profile := range conf.Profiles {
+			authed := "not authenticated"
+			if profile.Auth != "" {
+				authed = "authenticated"
+			}
+			fmt.Printf("%s\t%s\t%d directories\t%s\n", profile.Name, profile.APIRemote, len(conf.ViewsForProfile(profile.Name)), authed)
+		}
+
+	case "add":
+		if len(args) < 3 {
+			fmt.Println("⚠️  Unexpected number of arguments. Expected: 'sturdy profile add $NAME $API_REMOTE [$SYNC_REMOTE [$GIT_REMOTE]]'")
+			os.Exit(1)
+		}
+		profile := &config.Profile{
+			Name:       args[1],
+			APIRemote:  args[2],
+			SyncRemote: "sync.getsturdy.com",
+		}
+		if len(args) > 3 {
+			profile.SyncRemote = args[3]
+		}
+		if len(args) > 4 {
+			profile.GitRemote = args[4]
+		}
+		conf.AddProfile(profile)
+		if err := config.WriteConfig(configPath, conf); err != nil {
+			log.Fatalf("failed to save configuration: %s", err)
+		}
+		fmt.Printf("✅ Added '%s', authenticate with 'sturdy auth -profile %s'\n", profile.Name, profile.Name)
+
+	case "remove":
+		if len(args) < 2 {
+			fmt.Println("⚠️  Unexpected number of arguments. Expected: 'sturdy profile remove $NAME'")
+			os.Exit(1)
+		}
+		if err := conf.RemoveProfile(args[1]); err != nil {
+			log.Fatalln(err)
+		}
+		if err := config.WriteConfig(configPath, conf); err != nil {
+			log.Fatalf("failed to save configuration: %s", err)
+		}
+		fmt.Printf("✅ Removed '%s'\n", args[1])
+
+	default:
+		fmt.Printf("⚠️  Unknown command 'sturdy profile %s'. Expected: 'sturdy profile list|add|remove'\n", args[0])
+		os.Exit(1)
+	}
+}
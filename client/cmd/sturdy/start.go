@@ -37,7 +37,30 @@ func viewMutagenName(view config.ViewConfig) string {
 	return "view-" + view.ID
 }
 
-func startMutagen(dotSturdyConfigPath string, conf *config.Config, apiClient api.SturdyAPI) {
+// startAll starts the views of all profiles, or only the views of _profileName_ if it's set.
+func startAll(dotSturdyConfigPath string, conf *config.Config, profileName string) {
+	if len(conf.Views) == 0 {
+		fmt.Println("You don't have any codebases configured. Go to https://getsturdy.com to get started!")
+		os.Exit(0)
+	}
+
+	for _, profile := range conf.Profiles {
+		if profileName != "" && profile.Name != profileName {
+			continue
+		}
+		if len(conf.ViewsForProfile(profile.Name)) == 0 {
+			continue
+		}
+		apiClient, err := requireAuth(conf, profile, dotSturdyConfigPath)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		startMutagen(dotSturdyConfigPath, conf, profile, apiClient)
+	}
+}
+
+// startMutagen starts the views that are bound to _profile_.
+func startMutagen(dotSturdyConfigPath string, conf *config.Config, profile *config.Profile, apiClient api.SturdyAPI) {
 	mutagenAgentDirPath, err := mutagenSturdyAgentDirPath()
 	if err != nil {
 		log.Fatalf("failed to get config: %s", err)
@@ -58,7 +81,7 @@ func startMutagen(dotSturdyConfigPath string, conf *config.Config, apiClient api
 		log.Fatalf("failed to establish a secure connection: %s", err)
 	}
 
-	err = ensureKnownHosts(conf.SyncRemote)
+	err = ensureKnownHosts(profile.SyncRemote)
 	if err != nil {
 		log.Fatalf("failed to add trust: %s", err)
 	}
@@ -73,13 +96,13 @@ func startMutagen(dotSturdyConfigPath string, conf *config.Config, apiClient api
 		sessionsByName[s.Session.Name] = s
 	}
 
-	// removeViewConfIdx contains keys for view indexes that should be removed from the configuration
+	// removeViewConfIDs contains the ids of views that should be removed from the configuration
 	// after this command has been successfully executed
-	removeViewConfIdx := make(map[int]struct{})
+	removeViewConfIDs := make(map[string]struct{})
 
-	removeView := func(name string, viewIDx int, view config.ViewConfig) {
+	removeView := func(name string, view config.ViewConfig) {
 		// Mark for removal
-		removeViewConfIdx[viewIDx] = struct{}{}
+		removeViewConfIDs[view.ID] = struct{}{}
 
 		// Terminate the connection if it exists
 		if _, ok := sessionsByName[name]; ok {
@@ -90,20 +113,15 @@ func startMutagen(dotSturdyConfigPath string, conf *config.Config, apiClient api
 		}
 	}
 
-	if len(conf.Views) == 0 {
-		fmt.Println("You don't have any codebases configured. Go to https://getsturdy.com to get started!")
-		os.Exit(0)
-	}
-
 	// Create mutagen sync sessions
-	for viewIDx, view := range conf.Views {
+	for _, view := range conf.ViewsForProfile(profile.Name) {
 		name := viewMutagenName(view)
 
 		apiView, err := apiClient.GetView(view.ID)
 		if errors.Is(err, api.ErrUnauthorized) {
 			// Broom emoji
 			fmt.Printf("\U0001F9F9 Removing configuration for %s (you don't have access to the codebase)\n", view.Path)
-			removeView(name, viewIDx, view)
+			removeView(name, view)
 			continue
 		}
 		if err != nil {
@@ -113,7 +131,7 @@ func startMutagen(dotSturdyConfigPath string, conf *config.Config, apiClient api
 		if apiView.CodebaseIsArchived {
 			// Broom emoji
 			fmt.Printf("\U0001F9F9 Removing configuration for %s (the codebase has been archived)\n", view.Path)
-			removeView(name, viewIDx, view)
+			removeView(name, view)
 			continue
 		}
 
@@ -151,7 +169,7 @@ func startMutagen(dotSturdyConfigPath string, conf *config.Config, apiClient api
 			didMigrate = true
 		}
 
-		remote := conf.APIRemote
+		remote := profile.APIRemote
 		labelProto := ""
 		if strings.HasPrefix(remote, "https://") {
 			remote = remote[len("https://"):]
@@ -187,7 +205,7 @@ func startMutagen(dotSturdyConfigPath string, conf *config.Config, apiClient api
 			// Beta
 			fmt.Sprintf("%s@%s:/repos/%s/%s/",
 				apiView.UserID,
-				conf.SyncRemote,
+				profile.SyncRemote,
 				apiView.CodebaseID,
 				view.ID,
 			),
@@ -208,8 +226,8 @@ func startMutagen(dotSturdyConfigPath string, conf *config.Config, apiClient api
 
 	// Filter the config, and remove views that should no longer exist
 	var newViews []config.ViewConfig
-	for idx, view := range conf.Views {
-		if _, ok := removeViewConfIdx[idx]; ok {
+	for _, view := range conf.Views {
+		if _, ok := removeViewConfIDs[view.ID]; ok {
 			continue
 		}
 		newViews = append(newViews, view)
@@ -238,16 +256,28 @@ func terminateMutagenForView(view config.ViewConfig) error {
 	return nil
 }
 
-// hostWithOptionalPort is on format "host" or "host:1234"
-func ensureKnownHosts(hostWithOptionalPort string) error {
-	var port = "22"
-	var host = hostWithOptionalPort
-
+// splitHostPort splits a host on the format "host" or "host:1234", the port defaults to 22
+func splitHostPort(hostWithOptionalPort string) (host, port string) {
+	host, port = hostWithOptionalPort, "22"
 	parts := strings.Split(hostWithOptionalPort, ":")
 	if len(parts) > 1 {
 		host = parts[0]
 		port = parts[1]
 	}
+	return host, port
+}
+
+func knownHostsPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not get home dir: %w", err)
+	}
+	return filepath.Join(homeDir, ".ssh", "known_hosts"), nil
+}
+
+// hostWithOptionalPort is on format "host" or "host:1234"
+func ensureKnownHosts(hostWithOptionalPort string) error {
+	host, port := splitHostPort(hostWithOptionalPort)
 
 	cmd := exec.Command("ssh-keyscan", "-p", port, host)
 	output, err := cmd.Output()
@@ -268,13 +298,11 @@ func ensureKnownHosts(hostWithOptionalPort string) error {
 		trustRows = append(trustRows, sshKeyscanRow)
 	}
 
-	homeDir, err := os.UserHomeDir()
+	knownHostsFilePath, err := knownHostsPath()
 	if err != nil {
-		return fmt.Errorf("could not get home dir: %w", err)
+		return err
 	}
-
-	knownHostsDir := filepath.Join(homeDir, ".ssh")
-	knownHostsFilePath := filepath.Join(homeDir, ".ssh", "known_hosts")
+	knownHostsDir := filepath.Dir(knownHostsFilePath)
 
 	// Create the .ssh dir if it does not exist
 	// drwxr-xr-x
@@ -391,8 +419,12 @@ func mutagenSturdyAgentDirPath() (string, error) {
 	return configPath, nil
 }
 
+func privateKeyPathForUser(configPath string, userID string) string {
+	return filepath.Join(configPath, "private-key-ed25519-"+userID+".pem")
+}
+
 func generateKey(configPath string, userID string) (authorizedKey, privateKeyPath string, err error) {
-	privateKeyPath = filepath.Join(configPath, "private-key-ed25519-"+userID+".pem")
+	privateKeyPath = privateKeyPathForUser(configPath, userID)
 
 	// Check if we have a private key already
 	existingPrivateKey, err := os.Open(privateKeyPath)
@@ -19,7 +19,20 @@ func status(conf *config.Config) {
 		sessionsByName[s.Session.Name] = s
 	}
 
-	for _, view := range conf.Views {
+	for _, profile := range conf.Profiles {
+		views := conf.ViewsForProfile(profile.Name)
+		if len(views) == 0 {
+			continue
+		}
+		if len(conf.Profiles) > 1 {
+			fmt.Printf("🌐 %s (%s)\n", profile.Name, profile.APIRemote)
+		}
+		statusViews(views, sessionsByName)
+	}
+}
+
+func statusViews(views []config.ViewConfig, sessionsByName map[string]mutagen.SessionStatus) {
+	for _, view := range views {
 		name := viewMutagenName(view)
 
 		session, ok := sessionsByName[name]
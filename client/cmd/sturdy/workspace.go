@@ -0,0 +1,341 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"getsturdy.com/client/cmd/sturdy/config"
+	"getsturdy.com/client/pkg/api"
+)
+
+var errNotInView = errors.New("the current directory is not inside a Sturdy directory, run this command from a directory that has been set up with 'sturdy init'")
+
+// viewForPath returns the view that contains absPath. If views are nested, the innermost view is returned.
+func viewForPath(absPath string, views []config.ViewConfig) (config.ViewConfig, bool) {
+	var found config.ViewConfig
+	var ok bool
+	for _, view := range views {
+		viewPath, err := filepath.Abs(view.Path)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(viewPath, absPath)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+			continue
+		}
+		if !ok || len(viewPath) > len(found.Path) {
+			found = config.ViewConfig{ID: view.ID, Path: viewPath, Profile: view.Profile}
+			ok = true
+		}
+	}
+	return found, ok
+}
+
+// currentProfile returns the profile of the view that the working directory is in.
+func currentProfile(conf *config.Config) (*config.Profile, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	viewConfig, ok := viewForPath(wd, conf.Views)
+	if !ok {
+		return nil, errNotInView
+	}
+	profile := conf.Profile(viewConfig.Profile)
+	if profile == nil {
+		return nil, fmt.Errorf("the profile '%s' of this directory does not exist", viewConfig.Profile)
+	}
+	return profile, nil
+}
+
+// currentView returns the view that the working directory is in.
+func currentView(conf *config.Config, gql api.SturdyGraphQLAPI) (api.GraphQLView, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return api.GraphQLView{}, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	viewConfig, ok := viewForPath(wd, conf.Views)
+	if !ok {
+		return api.GraphQLView{}, errNotInView
+	}
+	return gql.View(viewConfig.ID)
+}
+
+// currentWorkspace returns the workspace that is open on the view that the working directory is in.
+func currentWorkspace(conf *config.Config, gql api.SturdyGraphQLAPI) (api.GraphQLView, *api.Workspace, error) {
+	view, err := currentView(conf, gql)
+	if err != nil {
+		return api.GraphQLView{}, nil, err
+	}
+	if view.Workspace == nil {
+		return api.GraphQLView{}, nil, errors.New("no workspace is open in this directory, open one with 'sturdy workspace switch'")
+	}
+	return view, view.Workspace, nil
+}
+
+// findWorkspace finds a workspace by its id, or by its name if the name is unique.
+func findWorkspace(workspaces []api.Workspace, idOrName string) (*api.Workspace, error) {
+	var byName []api.Workspace
+	for _, ws := range workspaces {
+		if ws.ID == idOrName {
+			return &ws, nil
+		}
+		if strings.EqualFold(ws.Name, idOrName) {
+			byName = append(byName, ws)
+		}
+	}
+	switch len(byName) {
+	case 0:
+		return nil, fmt.Errorf("no workspace found with id or name %q", idOrName)
+	case 1:
+		return &byName[0], nil
+	default:
+		return nil, fmt.Errorf("there are %d workspaces named %q, use the workspace id instead", len(byName), idOrName)
+	}
+}
+
+func workspaceCmd(conf *config.Config, args []string, gql api.SturdyGraphQLAPI) {
+	if len(args) < 1 {
+		fmt.Println("⚠️  Unexpected number of arguments. Expected: 'sturdy workspace list|create|switch|archive'")
+		os.Exit(1)
+	}
+
+	view, err := currentView(conf, gql)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	switch args[0] {
+	case "list":
+		workspaces, err := gql.Workspaces(view.Codebase.ID)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		for _, ws := range workspaces {
+			marker := " "
+			if view.Workspace != nil && view.Workspace.ID == ws.ID {
+				marker = "*"
+			}
+			fmt.Printf("%s %s\t%s\t%s\n", marker, ws.ID, ws.Name, ws.Author.Name)
+		}
+
+	case "create":
+		ws, err := gql.CreateWorkspace(view.Codebase.ID)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if len(args) > 1 {
+			name := strings.Join(args[1:], " ")
+			if _, err := gql.UpdateWorkspace(api.UpdateWorkspaceInput{ID: ws.ID, Name: &name}); err != nil {
+				log.Fatalln(err)
+			}
+			ws.Name = name
+		}
+		if err := gql.OpenWorkspaceOnView(view.ID, ws.ID); err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Printf("✅ Created '%s' and opened it in %s\n", ws.Name, view.MountPath)
+
+	case "switch":
+		if len(args) < 2 {
+			fmt.Println("⚠️  Unexpected number of arguments. Expected: 'sturdy workspace switch $WORKSPACE'")
+			os.Exit(1)
+		}
+		workspaces, err := gql.Workspaces(view.Codebase.ID)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		ws, err := findWorkspace(workspaces, strings.Join(args[1:], " "))
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if err := gql.OpenWorkspaceOnView(view.ID, ws.ID); err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Printf("✅ Opened '%s' in %s\n", ws.Name, view.MountPath)
+
+	case "archive":
+		ws := view.Workspace
+		if len(args) > 1 {
+			workspaces, err := gql.Workspaces(view.Codebase.ID)
+			if err != nil {
+				log.Fatalln(err)
+			}
+			if ws, err = findWorkspace(workspaces, strings.Join(args[1:], " ")); err != nil {
+				log.Fatalln(err)
+			}
+		}
+		if ws == nil {
+			fmt.Println("⚠️  No workspace is open in this directory. Expected: 'sturdy workspace archive $WORKSPACE'")
+			os.Exit(1)
+		}
+		if _, err := gql.ArchiveWorkspace(ws.ID); err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Printf("✅ Archived '%s'\n", ws.Name)
+
+	default:
+		fmt.Printf("⚠️  Unknown command 'sturdy workspace %s'. Expected: 'sturdy workspace list|create|switch|archive'\n", args[0])
+		os.Exit(1)
+	}
+}
+
+func diff(conf *config.Config, gql api.SturdyGraphQLAPI) {
+	_, ws, err := currentWorkspace(conf, gql)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	diffs, err := gql.WorkspaceDiffs(ws.ID)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	for _, d := range diffs {
+		switch {
+		case d.IsLarge:
+			fmt.Printf("large file %s\n", d.PreferredName)
+			continue
+		case d.IsMoved:
+			fmt.Printf("moved %s -> %s\n", d.OrigName, d.NewName)
+		}
+		for _, hunk := range d.Hunks {
+			fmt.Print(hunk.Patch)
+		}
+	}
+}
+
+func land(conf *config.Config, message string, gql api.SturdyGraphQLAPI) {
+	_, ws, err := currentWorkspace(conf, gql)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	if message != "" {
+		description := descriptionHTML(message)
+		if _, err := gql.UpdateWorkspace(api.UpdateWorkspaceInput{ID: ws.ID, DraftDescription: &description}); err != nil {
+			log.Fatalln(err)
+		}
+	} else if strings.TrimSpace(ws.DraftDescription) == "" {
+		fmt.Println("⚠️  The workspace has no description. Expected: 'sturdy land -m $MESSAGE'")
+		os.Exit(1)
+	}
+
+	if _, err := gql.LandWorkspaceChange(ws.ID); err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Printf("✅ Landed '%s'\n", ws.Name)
+}
+
+// descriptionHTML formats a plain text message as a description, in the same html format that the web app uses. The
+// first line of the message is the title of the change.
+func descriptionHTML(message string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(message), "\n") {
+		b.WriteString("<p>")
+		b.WriteString(html.EscapeString(strings.TrimRight(line, "\r")))
+		b.WriteString("</p>")
+	}
+	return b.String()
+}
+
+func review(conf *config.Config, args []string, gql api.SturdyGraphQLAPI) {
+	if len(args) < 1 {
+		fmt.Println("⚠️  Unexpected number of arguments. Expected: 'sturdy review request|approve'")
+		os.Exit(1)
+	}
+
+	view, ws, err := currentWorkspace(conf, gql)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	switch args[0] {
+	case "request":
+		if len(args) < 2 {
+			fmt.Println("⚠️  Unexpected number of arguments. Expected: 'sturdy review request $USER'")
+			os.Exit(1)
+		}
+		members, err := gql.CodebaseMembers(view.Codebase.ID)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		reviewer, err := findMember(members, strings.Join(args[1:], " "))
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if _, err := gql.RequestReview(ws.ID, reviewer.ID); err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Printf("✅ Requested a review of '%s' from %s\n", ws.Name, reviewer.Name)
+
+	case "approve":
+		if _, err := gql.CreateOrUpdateReview(ws.ID, api.ReviewGradeApprove); err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Printf("✅ Approved '%s'\n", ws.Name)
+
+	default:
+		fmt.Printf("⚠️  Unknown command 'sturdy review %s'. Expected: 'sturdy review request|approve'\n", args[0])
+		os.Exit(1)
+	}
+}
+
+// findMember finds a codebase member by id, email, or name.
+func findMember(members []api.Author, query string) (*api.Author, error) {
+	var byName []api.Author
+	for _, member := range members {
+		if member.ID == query || strings.EqualFold(member.Email, query) {
+			return &member, nil
+		}
+		if strings.EqualFold(member.Name, query) {
+			byName = append(byName, member)
+		}
+	}
+	switch len(byName) {
+	case 0:
+		return nil, fmt.Errorf("no member of the codebase found with id, email, or name %q", query)
+	case 1:
+		return &byName[0], nil
+	default:
+		return nil, fmt.Errorf("there are %d members named %q, use their email instead", len(byName), query)
+	}
+}
+
+func comment(conf *config.Config, args []string, path string, line int, gql api.SturdyGraphQLAPI) {
+	if len(args) < 1 {
+		fmt.Println("⚠️  Unexpected number of arguments. Expected: 'sturdy comment [-path $PATH -line $LINE] $MESSAGE'")
+		os.Exit(1)
+	}
+
+	_, ws, err := currentWorkspace(conf, gql)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	input := api.CreateCommentInput{
+		Message:     strings.Join(args, " "),
+		WorkspaceID: ws.ID,
+	}
+	if path != "" {
+		if line < 1 {
+			fmt.Println("⚠️  -line is required when commenting on a file")
+			os.Exit(1)
+		}
+		lineIsNew := true
+		input.Path = &path
+		input.LineStart = &line
+		input.LineEnd = &line
+		input.LineIsNew = &lineIsNew
+	}
+
+	if _, err := gql.CreateComment(input); err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Printf("✅ Commented on '%s'\n", ws.Name)
+}
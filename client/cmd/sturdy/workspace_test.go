@@ -0,0 +1,83 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"getsturdy.com/client/cmd/sturdy/config"
+	"getsturdy.com/client/pkg/api"
+)
+
+func TestViewForPath(t *testing.T) {
+	views := []config.ViewConfig{
+		{ID: "a", Path: "/code/a"},
+		{ID: "b", Path: "/code/b"},
+		{ID: "nested", Path: "/code/b/nested"},
+	}
+
+	cases := []struct {
+		path       string
+		expectedID string
+		expectedOk bool
+	}{
+		{"/code/a", "a", true},
+		{"/code/a/src/main.go", "a", true},
+		{"/code/b/nested/x", "nested", true},
+		{"/code/b/nestedx", "b", true},
+		{"/code/ab", "", false},
+		{"/code", "", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.path, func(t *testing.T) {
+			view, ok := viewForPath(tc.path, views)
+			assert.Equal(t, tc.expectedOk, ok)
+			assert.Equal(t, tc.expectedID, view.ID)
+		})
+	}
+}
+
+func TestFindWorkspace(t *testing.T) {
+	workspaces := []api.Workspace{
+		{ID: "1", Name: "Fix bug"},
+		{ID: "2", Name: "Feature"},
+		{ID: "3", Name: "feature"},
+	}
+
+	ws, err := findWorkspace(workspaces, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", ws.ID)
+
+	ws, err = findWorkspace(workspaces, "fix bug")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", ws.ID)
+
+	_, err = findWorkspace(workspaces, "feature")
+	assert.Error(t, err, "name is ambiguous")
+
+	_, err = findWorkspace(workspaces, "missing")
+	assert.Error(t, err)
+}
+
+func TestFindMember(t *testing.T) {
+	members := []api.Author{
+		{ID: "1", Name: "Alice", Email: "alice@example.com"},
+		{ID: "2", Name: "Bob", Email: "bob@example.com"},
+	}
+
+	for _, query := range []string{"2", "BOB@example.com", "bob"} {
+		member, err := findMember(members, query)
+		if assert.NoError(t, err, query) {
+			assert.Equal(t, "2", member.ID)
+		}
+	}
+
+	_, err := findMember(members, "carol")
+	assert.Error(t, err)
+}
+
+func TestDescriptionHTML(t *testing.T) {
+	assert.Equal(t, "<p>Fix bug</p>", descriptionHTML("Fix bug\n"))
+	assert.Equal(t, "<p>Fix &lt;div&gt;</p><p></p><p>Details</p>", descriptionHTML("Fix <div>\n\nDetails"))
+}
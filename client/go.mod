@@ -7,6 +7,7 @@ require (
 	github.com/google/uuid v1.3.0
 	github.com/kolide/launcher v0.11.23
 	github.com/stretchr/testify v1.7.0
+	github.com/tidwall/match v1.0.3
 	go.opencensus.io v0.23.0
 	golang.org/x/crypto v0.0.0-20220112180741-5e0467b6c7ce
 )
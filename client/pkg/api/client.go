@@ -11,7 +11,10 @@ import (
 	"getsturdy.com/client/cmd/sturdy/version"
 )
 
-var ErrUnauthorized = errors.New("unexpected response code 401")
+var (
+	ErrUnauthorized = errors.New("unexpected response code 401")
+	ErrNotFound     = errors.New("unexpected response code 404")
+)
 
 func Request(host, method, path, authToken string, request, response interface{}) error {
 	data, err := json.Marshal(request)
@@ -39,6 +42,9 @@ func Request(host, method, path, authToken string, request, response interface{}
 	if resp.StatusCode == 401 {
 		return ErrUnauthorized
 	}
+	if resp.StatusCode == 404 {
+		return ErrNotFound
+	}
 	if resp.StatusCode != 200 {
 		return fmt.Errorf("unexpected response code %d", resp.StatusCode)
 	}
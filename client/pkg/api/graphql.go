@@ -0,0 +1,94 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+
+	"getsturdy.com/client/cmd/sturdy/version"
+)
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []GraphQLError  `json:"errors"`
+}
+
+// GraphQLError is an error returned by the GraphQL API. Extensions contain the reason for the error, by input field.
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Extensions map[string]interface{} `json:"extensions"`
+}
+
+func (e GraphQLError) Error() string {
+	fields := make([]string, 0, len(e.Extensions))
+	for field, reason := range e.Extensions {
+		fields = append(fields, fmt.Sprintf("%s: %v", field, reason))
+	}
+	if len(fields) == 0 {
+		return e.Message
+	}
+	sort.Strings(fields)
+	return fmt.Sprintf("%s (%s)", e.Message, strings.Join(fields, ", "))
+}
+
+// GraphQL makes a request to the GraphQL API, and decodes the data of the response into response.
+func GraphQL(host, authToken, query string, variables map[string]interface{}, response interface{}) error {
+	data, err := json.Marshal(graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", host+"/graphql", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+
+	req.AddCookie(&http.Cookie{Name: "auth", Value: authToken})
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Client-Name", "sturdy-cli")
+	req.Header.Set("X-Client-Version", version.Version)
+
+	client := http.DefaultClient
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 401 {
+		return ErrUnauthorized
+	}
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("unexpected response code %d", resp.StatusCode)
+	}
+	respContent, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var gqlResp graphQLResponse
+	if err := json.Unmarshal(respContent, &gqlResp); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(gqlResp.Errors) > 0 {
+		errs := make([]string, 0, len(gqlResp.Errors))
+		for _, e := range gqlResp.Errors {
+			errs = append(errs, e.Error())
+		}
+		return errors.New(strings.Join(errs, "; "))
+	}
+	if err := json.Unmarshal(gqlResp.Data, response); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,352 @@
+package api
+
+import (
+	"fmt"
+
+	"getsturdy.com/client/cmd/sturdy/config"
+)
+
+// SturdyGraphQLAPI is a typed client for the parts of the GraphQL API that are used by the CLI. It uses the same
+// queries and mutations as the web app.
+type SturdyGraphQLAPI interface {
+	View(id string) (GraphQLView, error)
+	Workspaces(codebaseID string) ([]Workspace, error)
+	WorkspaceDiffs(workspaceID string) ([]FileDiff, error)
+	CodebaseMembers(codebaseID string) ([]Author, error)
+
+	CreateWorkspace(codebaseID string) (Workspace, error)
+	UpdateWorkspace(input UpdateWorkspaceInput) (Workspace, error)
+	ArchiveWorkspace(id string) (Workspace, error)
+	OpenWorkspaceOnView(viewID, workspaceID string) error
+	LandWorkspaceChange(workspaceID string) (Workspace, error)
+
+	CreateOrUpdateReview(workspaceID string, grade ReviewGrade) (Review, error)
+	RequestReview(workspaceID, userID string) (Review, error)
+	CreateComment(input CreateCommentInput) (Comment, error)
+}
+
+type Author struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type Workspace struct {
+	ID               string  `json:"id"`
+	Name             string  `json:"name"`
+	DraftDescription string  `json:"draftDescription"`
+	ArchivedAt       *int    `json:"archivedAt"`
+	Author           Author  `json:"author"`
+	DiffsCount       *int    `json:"diffsCount"`
+	View             *IDOnly `json:"view"`
+}
+
+type IDOnly struct {
+	ID string `json:"id"`
+}
+
+// GraphQLView is a view as returned by the GraphQL API, as opposed to View which is returned by the REST API.
+type GraphQLView struct {
+	ID        string     `json:"id"`
+	MountPath string     `json:"mountPath"`
+	Workspace *Workspace `json:"workspace"`
+	Codebase  Codebase   `json:"codebase"`
+}
+
+type FileDiff struct {
+	OrigName      string `json:"origName"`
+	NewName       string `json:"newName"`
+	PreferredName string `json:"preferredName"`
+	IsDeleted     bool   `json:"isDeleted"`
+	IsNew         bool   `json:"isNew"`
+	IsMoved       bool   `json:"isMoved"`
+	IsLarge       bool   `json:"isLarge"`
+	Hunks         []Hunk `json:"hunks"`
+}
+
+type Hunk struct {
+	HunkID string `json:"hunkID"`
+	Patch  string `json:"patch"`
+}
+
+type ReviewGrade string
+
+const (
+	ReviewGradeApprove   ReviewGrade = "Approve"
+	ReviewGradeReject    ReviewGrade = "Reject"
+	ReviewGradeRequested ReviewGrade = "Requested"
+)
+
+type Review struct {
+	ID     string      `json:"id"`
+	Grade  ReviewGrade `json:"grade"`
+	Author Author      `json:"author"`
+}
+
+type Comment struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+}
+
+type UpdateWorkspaceInput struct {
+	ID               string  `json:"id"`
+	Name             *string `json:"name,omitempty"`
+	DraftDescription *string `json:"draftDescription,omitempty"`
+}
+
+type CreateCommentInput struct {
+	Message     string  `json:"message"`
+	WorkspaceID string  `json:"workspaceID"`
+	Path        *string `json:"path,omitempty"`
+	LineStart   *int    `json:"lineStart,omitempty"`
+	LineEnd     *int    `json:"lineEnd,omitempty"`
+	LineIsNew   *bool   `json:"lineIsNew,omitempty"`
+}
+
+const workspaceFields = `
+	id
+	name
+	draftDescription
+	archivedAt
+	diffsCount
+	author { id name email }
+	view { id }
+`
+
+type GraphQLClient struct {
+	host      string
+	authToken string
+}
+
+func NewGraphQLClient(p *config.Profile) *GraphQLClient {
+	return &GraphQLClient{
+		host:      p.APIRemote,
+		authToken: p.Auth,
+	}
+}
+
+var _ SturdyGraphQLAPI = (*GraphQLClient)(nil)
+
+func (g *GraphQLClient) View(id string) (GraphQLView, error) {
+	var res struct {
+		View GraphQLView `json:"view"`
+	}
+	err := GraphQL(g.host, g.authToken, `
+		query View($id: ID!) {
+			view(id: $id) {
+				id
+				mountPath
+				workspace {`+workspaceFields+`}
+				codebase { id name }
+			}
+		}
+	`, map[string]interface{}{"id": id}, &res)
+	if err != nil {
+		return GraphQLView{}, fmt.Errorf("failed to load view: %w", err)
+	}
+	return res.View, nil
+}
+
+func (g *GraphQLClient) Workspaces(codebaseID string) ([]Workspace, error) {
+	var res struct {
+		Workspaces []Workspace `json:"workspaces"`
+	}
+	err := GraphQL(g.host, g.authToken, `
+		query Workspaces($codebaseID: ID!) {
+			workspaces(codebaseID: $codebaseID) {`+workspaceFields+`}
+		}
+	`, map[string]interface{}{"codebaseID": codebaseID}, &res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	return res.Workspaces, nil
+}
+
+func (g *GraphQLClient) WorkspaceDiffs(workspaceID string) ([]FileDiff, error) {
+	var res struct {
+		Workspace struct {
+			Diffs []FileDiff `json:"diffs"`
+		} `json:"workspace"`
+	}
+	err := GraphQL(g.host, g.authToken, `
+		query WorkspaceDiffs($id: ID!) {
+			workspace(id: $id) {
+				id
+				diffs {
+					origName
+					newName
+					preferredName
+					isDeleted
+					isNew
+					isMoved
+					isLarge
+					hunks { hunkID patch }
+				}
+			}
+		}
+	`, map[string]interface{}{"id": workspaceID}, &res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load diffs: %w", err)
+	}
+	return res.Workspace.Diffs, nil
+}
+
+func (g *GraphQLClient) CodebaseMembers(codebaseID string) ([]Author, error) {
+	var res struct {
+		Codebase struct {
+			Members []Author `json:"members"`
+		} `json:"codebase"`
+	}
+	err := GraphQL(g.host, g.authToken, `
+		query CodebaseMembers($id: ID!) {
+			codebase(id: $id) {
+				id
+				members { id name email }
+			}
+		}
+	`, map[string]interface{}{"id": codebaseID}, &res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load codebase members: %w", err)
+	}
+	return res.Codebase.Members, nil
+}
+
+func (g *GraphQLClient) CreateWorkspace(codebaseID string) (Workspace, error) {
+	var res struct {
+		CreateWorkspace Workspace `json:"createWorkspace"`
+	}
+	err := GraphQL(g.host, g.authToken, `
+		mutation CreateWorkspace($input: CreateWorkspaceInput!) {
+			createWorkspace(input: $input) {`+workspaceFields+`}
+		}
+	`, map[string]interface{}{"input": map[string]interface{}{"codebaseID": codebaseID}}, &res)
+	if err != nil {
+		return Workspace{}, fmt.Errorf("failed to create workspace: %w", err)
+	}
+	return res.CreateWorkspace, nil
+}
+
+func (g *GraphQLClient) UpdateWorkspace(input UpdateWorkspaceInput) (Workspace, error) {
+	var res struct {
+		UpdateWorkspace Workspace `json:"updateWorkspace"`
+	}
+	err := GraphQL(g.host, g.authToken, `
+		mutation UpdateWorkspace($input: UpdateWorkspaceInput!) {
+			updateWorkspace(input: $input) {`+workspaceFields+`}
+		}
+	`, map[string]interface{}{"input": input}, &res)
+	if err != nil {
+		return Workspace{}, fmt.Errorf("failed to update workspace: %w", err)
+	}
+	return res.UpdateWorkspace, nil
+}
+
+func (g *GraphQLClient) ArchiveWorkspace(id string) (Workspace, error) {
+	var res struct {
+		ArchiveWorkspace Workspace `json:"archiveWorkspace"`
+	}
+	err := GraphQL(g.host, g.authToken, `
+		mutation ArchiveWorkspace($id: ID!) {
+			archiveWorkspace(id: $id) {`+workspaceFields+`}
+		}
+	`, map[string]interface{}{"id": id}, &res)
+	if err != nil {
+		return Workspace{}, fmt.Errorf("failed to archive workspace: %w", err)
+	}
+	return res.ArchiveWorkspace, nil
+}
+
+func (g *GraphQLClient) OpenWorkspaceOnView(viewID, workspaceID string) error {
+	var res struct {
+		OpenWorkspaceOnView IDOnly `json:"openWorkspaceOnView"`
+	}
+	err := GraphQL(g.host, g.authToken, `
+		mutation OpenWorkspaceOnView($input: OpenWorkspaceOnViewInput!) {
+			openWorkspaceOnView(input: $input) { id }
+		}
+	`, map[string]interface{}{"input": map[string]interface{}{
+		"viewID":      viewID,
+		"workspaceID": workspaceID,
+	}}, &res)
+	if err != nil {
+		return fmt.Errorf("failed to open workspace: %w", err)
+	}
+	return nil
+}
+
+func (g *GraphQLClient) LandWorkspaceChange(workspaceID string) (Workspace, error) {
+	var res struct {
+		LandWorkspaceChange Workspace `json:"landWorkspaceChange"`
+	}
+	err := GraphQL(g.host, g.authToken, `
+		mutation LandWorkspaceChange($input: LandWorkspaceChangeInput!) {
+			landWorkspaceChange(input: $input) {`+workspaceFields+`}
+		}
+	`, map[string]interface{}{"input": map[string]interface{}{"workspaceID": workspaceID}}, &res)
+	if err != nil {
+		return Workspace{}, fmt.Errorf("failed to land: %w", err)
+	}
+	return res.LandWorkspaceChange, nil
+}
+
+func (g *GraphQLClient) CreateOrUpdateReview(workspaceID string, grade ReviewGrade) (Review, error) {
+	var res struct {
+		CreateOrUpdateReview Review `json:"createOrUpdateReview"`
+	}
+	err := GraphQL(g.host, g.authToken, `
+		mutation CreateOrUpdateReview($input: CreateReviewInput!) {
+			createOrUpdateReview(input: $input) {
+				id
+				grade
+				author { id name email }
+			}
+		}
+	`, map[string]interface{}{"input": map[string]interface{}{
+		"workspaceID": workspaceID,
+		"grade":       grade,
+	}}, &res)
+	if err != nil {
+		return Review{}, fmt.Errorf("failed to review: %w", err)
+	}
+	return res.CreateOrUpdateReview, nil
+}
+
+func (g *GraphQLClient) RequestReview(workspaceID, userID string) (Review, error) {
+	var res struct {
+		RequestReview Review `json:"requestReview"`
+	}
+	err := GraphQL(g.host, g.authToken, `
+		mutation RequestReview($input: RequestReviewInput!) {
+			requestReview(input: $input) {
+				id
+				grade
+				author { id name email }
+			}
+		}
+	`, map[string]interface{}{"input": map[string]interface{}{
+		"workspaceID": workspaceID,
+		"userID":      userID,
+	}}, &res)
+	if err != nil {
+		return Review{}, fmt.Errorf("failed to request review: %w", err)
+	}
+	return res.RequestReview, nil
+}
+
+func (g *GraphQLClient) CreateComment(input CreateCommentInput) (Comment, error) {
+	var res struct {
+		CreateComment Comment `json:"createComment"`
+	}
+	err := GraphQL(g.host, g.authToken, `
+		mutation CreateComment($input: CreateCommentInput!) {
+			createComment(input: $input) {
+				id
+				message
+			}
+		}
+	`, map[string]interface{}{"input": input}, &res)
+	if err != nil {
+		return Comment{}, fmt.Errorf("failed to comment: %w", err)
+	}
+	return res.CreateComment, nil
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"getsturdy.com/client/cmd/sturdy/config"
+)
+
+func TestGraphQLClient_LandWorkspaceChange(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/graphql", r.URL.Path)
+
+		cookie, err := r.Cookie("auth")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "token", cookie.Value)
+		}
+
+		var req graphQLRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Contains(t, req.Query, "landWorkspaceChange(input: $input)")
+		assert.Equal(t, map[string]interface{}{"workspaceID": "ws"}, req.Variables["input"])
+
+		_, _ = w.Write([]byte(`{"data":{"landWorkspaceChange":{"id":"ws","name":"My workspace","author":{"id":"u","name":"User"}}}}`))
+	}))
+	defer srv.Close()
+
+	client := NewGraphQLClient(&config.Profile{APIRemote: srv.URL, Auth: "token"})
+	ws, err := client.LandWorkspaceChange("ws")
+	assert.NoError(t, err)
+	assert.Equal(t, "My workspace", ws.Name)
+	assert.Equal(t, "User", ws.Author.Name)
+}
+
+func TestGraphQLClient_errors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errors":[{"message":"BadRequestError","extensions":{"workspaceID":"workspace is syncing"}}],"data":null}`))
+	}))
+	defer srv.Close()
+
+	client := NewGraphQLClient(&config.Profile{APIRemote: srv.URL, Auth: "token"})
+	err := client.OpenWorkspaceOnView("view", "ws")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "BadRequestError (workspaceID: workspace is syncing)")
+	}
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 
 	"getsturdy.com/client/cmd/sturdy/config"
@@ -11,10 +12,10 @@ type HttpApiClient struct {
 	authToken string
 }
 
-func NewHttpApiClient(c *config.Config) *HttpApiClient {
+func NewHttpApiClient(p *config.Profile) *HttpApiClient {
 	return &HttpApiClient{
-		host:      c.APIRemote,
-		authToken: c.Auth,
+		host:      p.APIRemote,
+		authToken: p.Auth,
 	}
 }
 
@@ -133,3 +134,49 @@ func (h *HttpApiClient) GetIgnores(viewID string) (GetIgnoresResponse, error) {
 	}
 	return res, nil
 }
+
+// VerifyPublicKey returns true if the public key is registered to the user. The key is in the ssh wire format.
+func (h *HttpApiClient) VerifyPublicKey(userID string, publicKey []byte) (bool, error) {
+	type verifyPublicKeyRequest struct {
+		PublicKey []byte `json:"public_key"`
+		UserID    string `json:"user_id"`
+	}
+
+	var res struct{}
+
+	err := Request(h.host, "POST", "/v3/pki/verify", h.authToken, verifyPublicKeyRequest{
+		PublicKey: publicKey,
+		UserID:    userID,
+	}, &res)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to verify public key: %w", err)
+	}
+	return true, nil
+}
+
+// ValidateView makes the same check as the sync server does when a connection is made to the view. It's a dry run, the
+// view is not marked as used.
+func (h *HttpApiClient) ValidateView(viewID, codebaseID, userID string) error {
+	type validateViewRequest struct {
+		ViewID     string `json:"view_id"`
+		CodebaseID string `json:"codebase_id"`
+		UserID     string `json:"user_id"`
+		DryRun     bool   `json:"dry_run"`
+	}
+
+	var res struct{}
+
+	err := Request(h.host, "POST", "/v3/mutagen/validate-view", h.authToken, validateViewRequest{
+		ViewID:     viewID,
+		CodebaseID: codebaseID,
+		UserID:     userID,
+		DryRun:     true,
+	}, &res)
+	if err != nil {
+		return fmt.Errorf("failed to validate view: %w", err)
+	}
+	return nil
+}
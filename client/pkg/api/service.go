@@ -7,6 +7,8 @@ type SturdyAPI interface {
 	RenewAuth() (RenewAuthResponse, error)
 	GetUser() (GetUserResponse, error)
 	GetIgnores(viewID string) (GetIgnoresResponse, error)
+	VerifyPublicKey(userID string, publicKey []byte) (bool, error)
+	ValidateView(viewID, codebaseID, userID string) error
 }
 
 type View struct {
@@ -0,0 +1,39 @@
+package ignore
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIgnore(t *testing.T) {
+	res, err := FindIgnore(os.DirFS("testdata/ignores"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"hello-*",
+		"/this/that/foobar.txt",
+		"/this/that/*.swp",
+	}, res)
+}
+
+func TestNoRecursionInIgnored(t *testing.T) {
+	fs := fstest.MapFS{
+		".gitignore":              {Data: []byte("in-root.txt\n/foo\n*.swp\n/.DS_Store\n*.tmp\n")},
+		"foo/bar/.gitignore":      {Data: []byte("in-nested-ignored.txt\n")},
+		"nested/other/.gitignore": {Data: []byte("in-nested-not-ignored.txt\n")},
+		"nested/f.tmp/.gitignore": {Data: []byte("in-nested-dot-tmp-should-be-ignored.txt\n")},
+	}
+
+	res, err := FindIgnore(fs)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"in-root.txt",
+		"/foo",
+		"*.swp",
+		"/.DS_Store",
+		"*.tmp",
+		"/nested/other/in-nested-not-ignored.txt",
+	}, res)
+}
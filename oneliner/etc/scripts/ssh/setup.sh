@@ -19,4 +19,17 @@ generate_keys() {
 	fi
 }
 
+# The secret authenticates git requests from the SSH server to the git server, it's read by both the api and the ssh
+# services.
+GIT_SERVER_SECRET_PATH="/var/data/ssh/git-server-secret"
+
+generate_git_server_secret() {
+	if [[ ! -f ${GIT_SERVER_SECRET_PATH} ]]; then
+		log "Generating git server secret"
+		mkdir -p "$(dirname ${GIT_SERVER_SECRET_PATH})"
+		(umask 077 && head -c 32 /dev/urandom | base64 > ${GIT_SERVER_SECRET_PATH})
+	fi
+}
+
 generate_keys
+generate_git_server_secret
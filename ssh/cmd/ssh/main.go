@@ -18,6 +18,8 @@ import (
 func main() {
 	sshListenAddr := flag.String("ssh-listen-addr", "127.0.0.1:2222", "")
 	sturdyApiAddr := flag.String("sturdy-api-addr", "http://host.docker.internal:3000", "")
+	gitServerAddr := flag.String("git-server-addr", "http://host.docker.internal:3002", "")
+	gitServerSecret := flag.String("git-server-secret", "", "")
 	mutagenAgentBinaryDir := flag.String("mutagen-agent-binary-dir", "/usr/bin/", "")
 	keyHostPath := flag.String("ssh-key-path", "id_ed25519", "")
 	httpPprofListenAddr := flag.String("http-pprof-listen-addr", "127.0.0.1:6060", "")
@@ -30,6 +32,8 @@ func main() {
 		KeyHostPath:           *keyHostPath,
 		MutagenAgentBinaryDir: *mutagenAgentBinaryDir,
 		SturdyApiAddr:         *sturdyApiAddr,
+		GitServerAddr:         *gitServerAddr,
+		GitServerSecret:       *gitServerSecret,
 	})
 
 	// Pprof server
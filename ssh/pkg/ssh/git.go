@@ -0,0 +1,134 @@
+package ssh
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gliderlabs/ssh"
+	"go.uber.org/zap"
+)
+
+const (
+	gitUploadPack  = "git-upload-pack"
+	gitReceivePack = "git-receive-pack"
+)
+
+func isGitCommand(command []string) bool {
+	if len(command) != 2 {
+		return false
+	}
+	return command[0] == gitUploadPack || command[0] == gitReceivePack
+}
+
+// codebaseIDFromGitPath returns the codebase ID from the path that the git client sends, supporting both
+// "ssh://host/<codebase-id>.git" and "host:<codebase-id>" style remotes.
+func codebaseIDFromGitPath(p string) string {
+	p = strings.TrimPrefix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	p = strings.TrimSuffix(p, ".git")
+	return p
+}
+
+// sshGitHandler forwards git-upload-pack and git-receive-pack to the gitserver. The gitserver validates that the
+// public key belongs to the user, and that the user is a member of the codebase. Requests are authenticated with the
+// secret that is shared with the gitserver.
+func (srv *Server) sshGitHandler(logger *zap.Logger, s ssh.Session) {
+	service := s.Command()[0]
+	codebaseID := codebaseIDFromGitPath(s.Command()[1])
+
+	logger = logger.With(zap.String("codebase_id", codebaseID))
+	logger.Info("SSH git connection")
+
+	t0 := time.Now()
+
+	if codebaseID == "" || strings.Contains(codebaseID, "/") {
+		fmt.Fprintf(s.Stderr(), "fatal: '%s' does not appear to be a Sturdy codebase\n", s.Command()[1])
+		_ = s.Exit(128)
+		return
+	}
+
+	if err := srv.proxyGit(logger, s, service, codebaseID); err != nil {
+		logger.Error("git proxy failed", zap.Error(err))
+		fmt.Fprintf(s.Stderr(), "fatal: %s\n", err)
+		_ = s.Exit(128)
+		return
+	}
+
+	if err := s.Exit(0); err != nil {
+		logger.Error("failed to exit session", zap.Error(err))
+	}
+
+	logger.Info("Disconnected SSH git connection",
+		zap.Duration("connection_duration", time.Since(t0)),
+	)
+}
+
+var errGitAccessDenied = errors.New("access denied")
+
+func (srv *Server) proxyGit(logger *zap.Logger, s ssh.Session, service, codebaseID string) error {
+	gitServerURL, err := url.Parse(srv.cfg.GitServerAddr)
+	if err != nil {
+		return fmt.Errorf("failed to parse git server address: %w", err)
+	}
+
+	conn, err := net.Dial("tcp", gitServerURL.Host)
+	if err != nil {
+		return fmt.Errorf("failed to connect to git server: %w", err)
+	}
+	defer conn.Close()
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/ssh/%s/%s", srv.cfg.GitServerAddr, url.PathEscape(codebaseID), service), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "git")
+	req.Header.Set("Authorization", "Bearer "+srv.cfg.GitServerSecret)
+	req.Header.Set("X-Sturdy-User-ID", s.User())
+	req.Header.Set("X-Sturdy-Public-Key", base64.StdEncoding.EncodeToString(s.PublicKey().Marshal()))
+
+	if err := req.Write(conn); err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusSwitchingProtocols:
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		return errGitAccessDenied
+	default:
+		return fmt.Errorf("unexpected response code %d", resp.StatusCode)
+	}
+
+	// stdin
+	go func() {
+		if _, err := io.Copy(conn, s); err != nil && !errIsConnectionClosed(err) && !errors.Is(err, net.ErrClosed) {
+			logger.Error("stdin copy failed", zap.Error(err))
+		}
+		// Let git know that the client is done.
+		if tcpConn, ok := conn.(*net.TCPConn); ok {
+			_ = tcpConn.CloseWrite()
+		}
+	}()
+
+	// stdout, the git server closes the connection when git exits
+	if _, err := io.Copy(s, br); err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, os.ErrClosed) {
+		return fmt.Errorf("stdout copy failed: %w", err)
+	}
+
+	return nil
+}
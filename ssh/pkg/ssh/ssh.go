@@ -22,6 +22,8 @@ import (
 type Config struct {
 	ListenAddr            string
 	SturdyApiAddr         string
+	GitServerAddr         string
+	GitServerSecret       string
 	KeyHostPath           string
 	MutagenAgentBinaryDir string
 }
@@ -107,6 +109,10 @@ func (srv *Server) sshHandler(s ssh.Session) {
 	case "ping":
 		srv.sshPingHandler(logger, s)
 	default:
+		if isGitCommand(s.Command()) {
+			srv.sshGitHandler(logger, s)
+			return
+		}
 		srv.sshMutagenHandler(logger, s)
 	}
 }